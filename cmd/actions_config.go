@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/cli/go-gh/v2/pkg/term"
+
+	"github.com/jefeish/gh-repo-transfer/internal/ghclient"
+)
+
+// repositoryActionsConfig captures the repository-level Actions variables (with their values)
+// and the names of its Actions secrets, whose values the API never returns
+type repositoryActionsConfig struct {
+	Variables []actionsVariable
+	Secrets   []string
+}
+
+type actionsVariable struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// captureRepositoryActionsConfig reads the repository's Actions variables and secret names
+// (requires admin access)
+func captureRepositoryActionsConfig(client api.RESTClient, owner, repo string) (*repositoryActionsConfig, error) {
+	config := &repositoryActionsConfig{}
+	if err := ghclient.GetAllField(&client, fmt.Sprintf("repos/%s/%s/actions/variables", owner, repo), "variables", &config.Variables); err != nil {
+		return nil, fmt.Errorf("failed to list Actions variables: %v", err)
+	}
+	var secrets []struct {
+		Name string `json:"name"`
+	}
+	if err := ghclient.GetAllField(&client, fmt.Sprintf("repos/%s/%s/actions/secrets", owner, repo), "secrets", &secrets); err != nil {
+		return nil, fmt.Errorf("failed to list Actions secrets: %v", err)
+	}
+	for _, secret := range secrets {
+		config.Secrets = append(config.Secrets, secret.Name)
+	}
+	return config, nil
+}
+
+// restoreRepositoryActionsConfig recreates the captured variables the moved repository lacks
+// and reports the secrets it lacks. With --prompt-secrets, gh asks for the value of each
+// missing secret on the terminal. Variables and secrets that exist are left untouched.
+func restoreRepositoryActionsConfig(client api.RESTClient, owner, repo string, config *repositoryActionsConfig, verboseOutput bool) error {
+	if config == nil || (len(config.Variables) == 0 && len(config.Secrets) == 0) {
+		return nil
+	}
+	present, err := captureRepositoryActionsConfig(client, owner, repo)
+	if err != nil {
+		return err
+	}
+
+	presentVariables := make(map[string]bool)
+	for _, variable := range present.Variables {
+		presentVariables[strings.ToUpper(variable.Name)] = true
+	}
+	var failed []string
+	created := 0
+	for _, variable := range config.Variables {
+		if presentVariables[strings.ToUpper(variable.Name)] {
+			continue
+		}
+		payload, err := json.Marshal(variable)
+		if err != nil {
+			return err
+		}
+		var response map[string]interface{}
+		if err := client.Post(fmt.Sprintf("repos/%s/%s/actions/variables", owner, repo), bytes.NewBuffer(payload), &response); err != nil {
+			failed = append(failed, fmt.Sprintf("variable %s (%v)", variable.Name, err))
+			continue
+		}
+		created++
+	}
+	if created > 0 {
+		fmt.Printf("🔧 Recreated %d Actions variable(s) on %s/%s\n", created, owner, repo)
+	} else if verboseOutput && len(config.Variables) > 0 {
+		fmt.Fprintf(os.Stderr, "All %d Actions variable(s) are present on %s/%s\n", len(config.Variables), owner, repo)
+	}
+
+	presentSecrets := make(map[string]bool)
+	for _, secret := range present.Secrets {
+		presentSecrets[strings.ToUpper(secret)] = true
+	}
+	var missing []string
+	for _, secret := range config.Secrets {
+		if !presentSecrets[strings.ToUpper(secret)] {
+			missing = append(missing, secret)
+		}
+	}
+	missing = promptMissingSecrets(owner, repo, "", missing, &failed)
+	if len(missing) > 0 {
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: %d Actions secret(s) are missing on %s/%s and must be set again: %s\n", len(missing), owner, repo, strings.Join(missing, ", "))
+		fmt.Fprintf(os.Stderr, "   Set them with 'gh secret set <name> --repo %s', or re-run with --prompt-secrets on a terminal.\n", ghclient.RepoArg(owner, repo))
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("could not restore %d Actions item(s): %v", len(failed), failed)
+	}
+	return nil
+}
+
+// promptMissingSecrets lets gh ask for the value of each missing repository secret, or
+// environment secret when environment is set (gh encrypts it with the right public key), and
+// returns the secrets that are still missing. Without --prompt-secrets or a terminal, nothing
+// is asked.
+func promptMissingSecrets(owner, repo, environment string, missing []string, failed *[]string) []string {
+	if !promptSecrets || len(missing) == 0 || !term.IsTerminal(os.Stdin) {
+		return missing
+	}
+	var skipped []string
+	for _, secret := range missing {
+		args := []string{"secret", "set", secret, "--repo", ghclient.RepoArg(owner, repo)}
+		if environment != "" {
+			args = append(args, "--env", environment)
+			fmt.Fprintf(os.Stderr, "🔑 Value for secret %s of environment %s in %s/%s:\n", secret, environment, owner, repo)
+		} else {
+			fmt.Fprintf(os.Stderr, "🔑 Value for secret %s of %s/%s:\n", secret, owner, repo)
+		}
+		command, err := ghclient.Command(args...)
+		if err == nil {
+			command.Stdin, command.Stdout, command.Stderr = os.Stdin, os.Stdout, os.Stderr
+			err = command.Run()
+		}
+		if err != nil {
+			*failed = append(*failed, fmt.Sprintf("secret %s (%v)", secret, err))
+			skipped = append(skipped, secret)
+		}
+	}
+	return skipped
+}
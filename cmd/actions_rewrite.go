@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+
+	"github.com/jefeish/gh-repo-transfer/internal/dependencies"
+)
+
+// actionsBranch is the branch the workflow rewrite is proposed from
+const actionsBranch = "repo-transfer/rewrite-actions"
+
+// rewriteActions rewrites the uses: references of a transferred repository's workflows from
+// actions and reusable workflows of sourceOrg to the same repositories in targetOrg, for those
+// that exist there (including repositories moved before). The rewritten files are written
+// below outDir/<repo>, or proposed as a pull request on the default branch without outDir.
+// References to repositories the target does not have are left as they are and reported.
+func rewriteActions(client api.RESTClient, sourceOrg, targetOrg, repo, outDir string, verbose bool) error {
+	repository := fmt.Sprintf("%s/%s", targetOrg, repo)
+
+	var entries []struct {
+		Name string `json:"name"`
+		Path string `json:"path"`
+		Type string `json:"type"`
+	}
+	if err := client.Get(fmt.Sprintf("repos/%s/contents/.github/workflows", repository), &entries); err != nil {
+		if strings.Contains(err.Error(), "404") {
+			if verbose {
+				fmt.Fprintf(os.Stderr, "No workflows in %s, nothing to rewrite\n", repository)
+			}
+			return nil
+		}
+		return fmt.Errorf("failed to list the workflows of %s: %v", repository, err)
+	}
+
+	// Whether a repository exists in the target, looked up once per repository
+	existing := make(map[string]bool)
+	exists := func(name string) bool {
+		key := strings.ToLower(name)
+		if found, ok := existing[key]; ok {
+			return found
+		}
+		var target struct {
+			Name string `json:"name"`
+		}
+		err := client.Get(fmt.Sprintf("repos/%s/%s", targetOrg, name), &target)
+		if err != nil && !strings.Contains(err.Error(), "404") {
+			fmt.Fprintf(os.Stderr, "⚠️  Warning: failed to look up %s/%s: %v\n", targetOrg, name, err)
+		}
+		existing[key] = err == nil
+		return err == nil
+	}
+
+	var changes []fileChange
+	var rewritten, kept []string
+	for _, entry := range entries {
+		if entry.Type != "file" || (path.Ext(entry.Name) != ".yml" && path.Ext(entry.Name) != ".yaml") {
+			continue
+		}
+		var file struct {
+			SHA     string `json:"sha"`
+			Content string `json:"content"`
+		}
+		if err := client.Get(fmt.Sprintf("repos/%s/contents/%s", repository, entry.Path), &file); err != nil {
+			return fmt.Errorf("failed to read %s: %v", entry.Path, err)
+		}
+		content, err := base64.StdEncoding.DecodeString(file.Content)
+		if err != nil {
+			return fmt.Errorf("failed to decode %s: %v", entry.Path, err)
+		}
+
+		updated, changed, unresolved := dependencies.RewriteActionReferences(string(content), sourceOrg, targetOrg, exists)
+		rewritten = appendMissing(rewritten, changed)
+		kept = appendMissing(kept, unresolved)
+		if len(changed) > 0 {
+			changes = append(changes, fileChange{Path: entry.Path, SHA: file.SHA, Content: []byte(updated)})
+		}
+	}
+
+	for _, reference := range kept {
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: %s in the workflows of %s was not rewritten: the repository does not exist in %s\n", reference, repository, targetOrg)
+	}
+	if len(changes) == 0 {
+		if verbose {
+			fmt.Fprintf(os.Stderr, "No %s/ action references to rewrite in %s\n", sourceOrg, repository)
+		}
+		return nil
+	}
+
+	if outDir != "" {
+		for _, change := range changes {
+			filePath := filepath.Join(outDir, repo, filepath.FromSlash(change.Path))
+			if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+				return err
+			}
+			if err := os.WriteFile(filePath, change.Content, 0644); err != nil {
+				return fmt.Errorf("failed to write %s: %v", filePath, err)
+			}
+		}
+		fmt.Printf("   Rewritten workflows (%d references in %d files) written to %s\n", len(rewritten), len(changes), filepath.Join(outDir, repo))
+		return nil
+	}
+
+	url, err := proposeFileChanges(client, repository, actionsBranch, changes,
+		fmt.Sprintf("Point workflows at the actions of %s", targetOrg),
+		actionsPullRequestBody(sourceOrg, targetOrg, rewritten, kept))
+	if err != nil {
+		return fmt.Errorf("failed to propose the workflow rewrite: %v", err)
+	}
+	fmt.Printf("   Workflow rewrite proposed (%d references in %d files): %s\n", len(rewritten), len(changes), url)
+	return nil
+}
+
+// appendMissing appends the values that are not in list yet, compared case-insensitively
+func appendMissing(list, values []string) []string {
+	for _, value := range values {
+		found := false
+		for _, existing := range list {
+			if strings.EqualFold(existing, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, value)
+		}
+	}
+	return list
+}
+
+// actionsPullRequestBody describes the rewritten and the remaining action references
+func actionsPullRequestBody(sourceOrg, targetOrg string, changed, kept []string) string {
+	var body strings.Builder
+	fmt.Fprintf(&body, "This repository moved from %s to %s. Its workflows still use actions and reusable workflows through their %s paths, which resolve through redirects only until the old names are reused.\n\n", sourceOrg, targetOrg, sourceOrg)
+	body.WriteString("Rewritten to the repositories of the same name in the new organization, keeping their refs:\n\n")
+	for _, reference := range changed {
+		fmt.Fprintf(&body, "- `%s` → `%s/%s`\n", reference, targetOrg, reference[strings.Index(reference, "/")+1:])
+	}
+	if len(kept) > 0 {
+		fmt.Fprintf(&body, "\nLeft unchanged because %s has no repository of that name:\n\n", targetOrg)
+		for _, reference := range kept {
+			fmt.Fprintf(&body, "- `%s`\n", reference)
+		}
+	}
+	return body.String()
+}
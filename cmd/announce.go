@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+
+	"github.com/jefeish/gh-repo-transfer/internal/ghclient"
+)
+
+const migratedLabel = "repo-migrated"
+
+// openItemCounts holds the number of open issues and pull requests on a repository
+type openItemCounts struct {
+	Issues       int `json:"open_issues"`
+	PullRequests int `json:"open_pull_requests"`
+}
+
+// getOpenItemCounts counts open issues and pull requests. The repository's open_issues_count
+// includes pull requests, which are counted with one more request; the search API, limited to
+// 30 requests a minute, is only the fallback.
+func getOpenItemCounts(client api.RESTClient, owner, repo string) (*openItemCounts, error) {
+	var repository struct {
+		OpenIssuesCount int `json:"open_issues_count"`
+	}
+	if err := client.Get(fmt.Sprintf("repos/%s/%s", owner, repo), &repository); err == nil {
+		pulls, err := ghclient.Count(&client, fmt.Sprintf("repos/%s/%s/pulls?state=open", owner, repo))
+		if err == nil && pulls <= repository.OpenIssuesCount {
+			return &openItemCounts{Issues: repository.OpenIssuesCount - pulls, PullRequests: pulls}, nil
+		}
+	}
+	return searchOpenItemCounts(client, owner, repo)
+}
+
+// searchOpenItemCounts counts open issues and pull requests using the search API
+func searchOpenItemCounts(client api.RESTClient, owner, repo string) (*openItemCounts, error) {
+	counts := &openItemCounts{}
+
+	for _, itemType := range []string{"issue", "pr"} {
+		var response struct {
+			TotalCount int `json:"total_count"`
+		}
+		query := fmt.Sprintf("search/issues?q=repo:%s/%s+is:open+is:%s&per_page=1", owner, repo, itemType)
+		if err := client.Get(query, &response); err != nil {
+			return nil, fmt.Errorf("failed to count open %ss: %v", itemType, err)
+		}
+		if itemType == "issue" {
+			counts.Issues = response.TotalCount
+		} else {
+			counts.PullRequests = response.TotalCount
+		}
+	}
+
+	return counts, nil
+}
+
+// formatOpenItemsAdvisory returns a one-line advisory for open items, or "" when there are none
+func formatOpenItemsAdvisory(counts *openItemCounts) string {
+	if counts == nil || (counts.Issues == 0 && counts.PullRequests == 0) {
+		return ""
+	}
+	return fmt.Sprintf("%d open issues, %d open pull requests", counts.Issues, counts.PullRequests)
+}
+
+// announceMigration labels all open issues/PRs with 'repo-migrated' and opens a pinned issue
+// announcing the new location of the repository
+func announceMigration(client api.RESTClient, originalPath, targetOwner, repoName string, verboseOutput bool) error {
+	if err := ensureLabel(client, targetOwner, repoName, migratedLabel, "5319e7", "Repository was migrated to a new organization"); err != nil {
+		return err
+	}
+
+	labeled, err := labelOpenItems(client, targetOwner, repoName, migratedLabel)
+	if err != nil {
+		return err
+	}
+	if verboseOutput {
+		fmt.Fprintf(os.Stderr, "✅ Applied '%s' label to %d open issues/pull requests\n", migratedLabel, labeled)
+	}
+
+	newPath := fmt.Sprintf("%s/%s", targetOwner, repoName)
+	issuePayload, err := json.Marshal(map[string]interface{}{
+		"title":  fmt.Sprintf("📦 This repository has moved to %s", newPath),
+		"body":   fmt.Sprintf("This repository was migrated from `%s` to `%s`.\n\nPlease update your git remotes, bookmarks and any references:\n\n```sh\ngit remote set-url origin %s.git\n```", originalPath, newPath, ghclient.WebURL(newPath)),
+		"labels": []string{migratedLabel},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal announcement issue payload: %v", err)
+	}
+
+	var issue struct {
+		Number  int    `json:"number"`
+		NodeID  string `json:"node_id"`
+		HTMLURL string `json:"html_url"`
+	}
+	if err := client.Post(fmt.Sprintf("repos/%s/%s/issues", targetOwner, repoName), bytes.NewBuffer(issuePayload), &issue); err != nil {
+		return fmt.Errorf("failed to create announcement issue: %v", err)
+	}
+
+	if err := pinIssue(issue.NodeID); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: Announcement issue #%d created but could not be pinned: %v\n", issue.Number, err)
+	} else if verboseOutput {
+		fmt.Fprintf(os.Stderr, "✅ Pinned announcement issue #%d: %s\n", issue.Number, issue.HTMLURL)
+	}
+
+	return nil
+}
+
+// ensureLabel creates a label on the repository unless it already exists
+func ensureLabel(client api.RESTClient, owner, repo, name, color, description string) error {
+	var existing map[string]interface{}
+	if err := client.Get(fmt.Sprintf("repos/%s/%s/labels/%s", owner, repo, name), &existing); err == nil {
+		return nil
+	}
+
+	payloadBytes, err := json.Marshal(map[string]string{
+		"name":        name,
+		"color":       color,
+		"description": description,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal label payload: %v", err)
+	}
+
+	var response map[string]interface{}
+	if err := client.Post(fmt.Sprintf("repos/%s/%s/labels", owner, repo), bytes.NewBuffer(payloadBytes), &response); err != nil {
+		return fmt.Errorf("failed to create label '%s': %v", name, err)
+	}
+	return nil
+}
+
+// labelOpenItems adds a label to every open issue and pull request
+func labelOpenItems(client api.RESTClient, owner, repo, label string) (int, error) {
+	payloadBytes, err := json.Marshal(map[string][]string{"labels": {label}})
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal label payload: %v", err)
+	}
+
+	var items []struct {
+		Number int `json:"number"`
+	}
+	if err := ghclient.GetAll(&client, fmt.Sprintf("repos/%s/%s/issues?state=open", owner, repo), &items); err != nil {
+		return 0, fmt.Errorf("failed to list open issues: %v", err)
+	}
+
+	labeled := 0
+	for _, item := range items {
+		var response []map[string]interface{}
+		endpoint := fmt.Sprintf("repos/%s/%s/issues/%d/labels", owner, repo, item.Number)
+		if err := client.Post(endpoint, bytes.NewReader(payloadBytes), &response); err != nil {
+			return labeled, fmt.Errorf("failed to label #%d: %v", item.Number, err)
+		}
+		labeled++
+	}
+	return labeled, nil
+}
+
+// pinIssue pins an issue using the GraphQL API (no REST equivalent exists)
+func pinIssue(issueNodeID string) error {
+	client, err := ghclient.NewGraphQLClient()
+	if err != nil {
+		return fmt.Errorf("failed to create GraphQL client: %v", err)
+	}
+
+	var response struct {
+		PinIssue struct {
+			Issue struct {
+				ID string `json:"id"`
+			} `json:"issue"`
+		} `json:"pinIssue"`
+	}
+	mutation := `mutation($id: ID!) { pinIssue(input: {issueId: $id}) { issue { id } } }`
+	return client.Do(mutation, map[string]interface{}{"id": issueNodeID}, &response)
+}
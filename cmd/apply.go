@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jefeish/gh-repo-transfer/internal/plan"
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+var (
+	// appliedPlan is the plan being executed by 'apply' (nil otherwise)
+	appliedPlan     *plan.Plan
+	appliedPlanPath string
+)
+
+// applyCmd executes a plan written by 'plan'
+var applyCmd = &cobra.Command{
+	Use:   "apply plan.json",
+	Short: "Execute a migration plan written by 'plan'",
+	Long: `Execute a reviewed migration plan written by 'plan'.
+
+The operation, target organization and options (--assign, --add-topics, the settings
+profile, ...) are taken from the plan; flags given to apply for them are ignored. Every
+repository is analyzed and validated again and compared with the validation in the plan:
+items that regressed since the plan was reviewed abort that repository unless --allow-drift
+is set. Archives keep the archived names shown in the plan.
+
+A two-hop plan (plan --via staging-org) is applied into the staging org only, without
+team assignment, announcements or the settings profile; 'promote' runs the second hop.
+
+--dry-run, --enforce, --allow-drift, --state-file, --db and --control-repo still apply.
+
+  gh repo-transfer apply plan.json --dry-run
+  gh repo-transfer apply plan.json --db migrations.db`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE:         runApply,
+}
+
+func init() {
+	rootCmd.AddCommand(applyCmd)
+}
+
+func runApply(cmd *cobra.Command, args []string) error {
+	migrationPlan, err := plan.Read(args[0])
+	if err != nil {
+		return err
+	}
+	if err := usePlan(cmd, migrationPlan, args[0]); err != nil {
+		return err
+	}
+
+	// A two-hop plan is applied into the staging org; 'promote' runs the second hop
+	if migrationPlan.StagingOrg != "" {
+		enterStagingHop(migrationPlan.StagingOrg)
+		fmt.Fprintf(os.Stderr, "📝 Applying %s: first hop of %d repository(ies) into the staging org %s (target %s, planned %s)\n",
+			appliedPlanPath, len(migrationPlan.Repositories), migrationPlan.StagingOrg, migrationPlan.TargetOrg, migrationPlan.CreatedAt.Format("2006-01-02 15:04 MST"))
+		return runTransfer(cmd, migrationPlan.Names())
+	}
+
+	fmt.Fprintf(os.Stderr, "📝 Applying %s (%s of %d repository(ies) to %s, planned %s)\n",
+		appliedPlanPath, migrationPlan.Operation, len(migrationPlan.Repositories), migrationPlan.TargetOrg, migrationPlan.CreatedAt.Format("2006-01-02 15:04 MST"))
+
+	if migrationPlan.Operation == plan.OperationArchive {
+		return runArchive(cmd, migrationPlan.Names())
+	}
+	return runTransfer(cmd, migrationPlan.Names())
+}
+
+// usePlan makes a plan the one being executed and sets the target organization and options it
+// was made with. Only a --target-org given on the command line has to match the plan; the
+// config's default target organization gives way to it.
+func usePlan(cmd *cobra.Command, migrationPlan *plan.Plan, path string) error {
+	if cmd.Flags().Changed("target-org") && !strings.EqualFold(targetOrg, migrationPlan.TargetOrg) {
+		return fmt.Errorf("--target-org %s does not match the plan's target organization %s", targetOrg, migrationPlan.TargetOrg)
+	}
+	// A plan reviewed against an old target scan no longer says what the target can take
+	if age, ok := migrationPlan.ScanAge(time.Now()); ok && maxCapabilityAge > 0 && age > maxCapabilityAge {
+		return fmt.Errorf("the plan's target capability scan is %s old (--max-capability-age %s); run 'plan' again to review the target's current state",
+			age.Round(time.Second), maxCapabilityAge)
+	}
+	appliedPlan = migrationPlan
+	appliedPlanPath = path
+	targetOrg = migrationPlan.TargetOrg
+
+	options := migrationPlan.Options
+	assign = options.Assign
+	createTeams = options.CreateTeams
+	addTopics = options.AddTopics
+	removeTopics = options.RemoveTopics
+	defaultBranch = options.DefaultBranch
+	settingsProfilePath = options.SettingsProfile
+	verifySettings = options.Verify
+	announce = options.Announce
+	cleanupSource = options.CleanupSource
+	createTombstone = options.CreateTombstone
+	rewriteCodeownersRefs = options.RewriteCodeowners
+	rewriteActionsRefs = options.RewriteActions
+	rewriteActionsDir = options.RewriteActionsDir
+	migrateWebhooks = options.MigrateWebhooks
+	migratePages = options.MigratePages
+	migrateBranchProtection = options.MigrateBranchProtection
+	migrateRulesets = options.MigrateRulesets
+	migrateEnvironments = options.MigrateEnvironments
+	patchRulesetIncludes = options.PatchRulesetIncludes
+	allowPermissionChange = options.AllowPermissionChange
+	archiveAfter = options.ArchiveAfter
+	policyFilePath = options.PolicyFile
+	provision = options.Provision
+	provisionValuesPath = options.ProvisionValues
+	if options.OriginTracking != "" {
+		originTracking = options.OriginTracking
+	}
+	if options.TeamMatcher != "" {
+		teamMatcher = options.TeamMatcher
+	}
+	return nil
+}
+
+// plannedArchivedName returns the archived name the applied plan fixed for a repository
+func plannedArchivedName(repository string) (string, bool) {
+	planned, ok := appliedPlan.Find(repository)
+	if !ok || planned.ArchivedName == "" {
+		return "", false
+	}
+	return planned.ArchivedName, true
+}
+
+// plannedValidation returns the validation the applied plan recorded for the hop a repository
+// is about to make
+func plannedValidation(repository string) *types.MigrationValidation {
+	if promoting {
+		planned, ok := appliedPlan.FindStaged(repository)
+		if !ok {
+			return nil
+		}
+		return planned.Validation
+	}
+	planned, ok := appliedPlan.Find(repository)
+	if !ok {
+		return nil
+	}
+	if appliedPlan.StagingOrg != "" {
+		if planned.Staging == nil {
+			return nil
+		}
+		return planned.Staging.Validation
+	}
+	return planned.Validation
+}
@@ -13,8 +13,21 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/jefeish/gh-repo-transfer/internal/analyzer"
+	"github.com/jefeish/gh-repo-transfer/internal/batch"
+	"github.com/jefeish/gh-repo-transfer/internal/dependencies"
+	"github.com/jefeish/gh-repo-transfer/internal/ghclient"
+	"github.com/jefeish/gh-repo-transfer/internal/history"
+	"github.com/jefeish/gh-repo-transfer/internal/journal"
+	"github.com/jefeish/gh-repo-transfer/internal/origin"
+	"github.com/jefeish/gh-repo-transfer/internal/policy"
+	"github.com/jefeish/gh-repo-transfer/internal/reposize"
+	"github.com/jefeish/gh-repo-transfer/internal/secretscan"
+	"github.com/jefeish/gh-repo-transfer/internal/state"
+	"github.com/jefeish/gh-repo-transfer/internal/steps"
+	"github.com/jefeish/gh-repo-transfer/internal/teams"
 	"github.com/jefeish/gh-repo-transfer/internal/types"
 	"github.com/jefeish/gh-repo-transfer/internal/validation"
+	"github.com/jefeish/gh-repo-transfer/pkg/utils"
 )
 
 // archiveCmd represents the archive command
@@ -61,6 +74,11 @@ type archiveResult struct {
 	UID            string `json:"uid"`
 	OriginalPath   string `json:"original_path"`
 	Validation     *types.MigrationValidation `json:"validation,omitempty"`
+	OpenItems      *openItemCounts `json:"open_items,omitempty"`
+	SecretScan     *secretscan.Summary `json:"secret_scan,omitempty"`
+	Enforceable    bool `json:"-"` // Blocked by validation blockers or plan drift, which --interactive can enforce
+	OriginChain    []string `json:"origin_chain,omitempty"` // Origins recorded by earlier archives, oldest first, then this one
+	Size           *reposize.Stats `json:"size,omitempty"` // Repository size and largest object, nil when they could not be read
 }
 
 func init() {
@@ -72,8 +90,12 @@ func init() {
 
 func runArchive(cmd *cobra.Command, args []string) error {
 	var repos []string
+	args, err := repositoryArgs(args)
+	if err != nil {
+		return err
+	}
 	
-	if len(args) == 0 {
+	if len(args) == 0 && !resumeRun {
 		// Try to get repo from current directory
 		currentRepo, err := getCurrentRepo()
 		if err != nil {
@@ -92,6 +114,15 @@ func runArchive(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// With --resume, the journal of the previous run decides which repositories are left
+	repos, err = startRunJournal("archive", repos)
+	if err != nil {
+		return err
+	}
+	if len(repos) == 0 {
+		return nil
+	}
+
 	if verbose {
 		if len(repos) == 1 {
 			fmt.Fprintf(os.Stderr, "Preparing to archive repository: %s to %s\n", repos[0], targetOrg)
@@ -100,11 +131,55 @@ func runArchive(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	client, err := api.DefaultRESTClient()
+	client, err := ghclient.NewRESTClient()
 	if err != nil {
 		return fmt.Errorf("failed to create API client: %v", err)
 	}
 
+	// Load the settings profile up front so a bad file fails before any repository is moved
+	loadedSettingsProfile, err = loadSettingsProfile(settingsProfilePath)
+	if err != nil {
+		return err
+	}
+	loadedPolicy, err = policy.Load(policyFilePath)
+	if err != nil {
+		return err
+	}
+	if err := parseArchiveAfter(); err != nil {
+		return err
+	}
+	if err := validation.LoadEffortWeights(effortWeightsPath); err != nil {
+		return err
+	}
+	if err := validation.LoadSuppressions(ignoreFilePath); err != nil {
+		return err
+	}
+	if err := validateConcurrency(); err != nil {
+		return err
+	}
+	validation.SetCollisionAwareness(checkCollisions)
+	dependencies.SetEnterprise(enterpriseSlug)
+	validation.SetTargetEnterprise(resolvedTargetEnterprise())
+	matchMode, err := teams.ParseMatchMode(teamMatcher)
+	if err != nil {
+		return err
+	}
+	teams.SetMatchMode(matchMode)
+	if _, err := origin.Strategies(originTracking); err != nil {
+		return err
+	}
+	if err := loadRunState(); err != nil {
+		return err
+	}
+	defer saveRunState()
+	if err := openHistoryStore(); err != nil {
+		return err
+	}
+	defer closeHistoryStore()
+	if err := resolveControlRepo(*client); err != nil {
+		return err
+	}
+
 	// Validate target owner exists (once for all repos)
 	if err := validateTargetOwner(*client, targetOrg); err != nil {
 		return fmt.Errorf("failed to validate target owner: %v", err)
@@ -123,7 +198,7 @@ func runArchive(cmd *cobra.Command, args []string) error {
 		if verbose {
 			fmt.Fprintf(os.Stderr, "Scanning target organization capabilities: %s\n", targetOrg)
 		}
-		caps, err := validation.ScanTargetOrganization(*client, targetOrg, verbose)
+		caps, err := scanTargetCapabilities(*client, targetOrg)
 		if err != nil {
 			return fmt.Errorf("failed to scan target organization: %v", err)
 		}
@@ -157,26 +232,38 @@ func runArchive(cmd *cobra.Command, args []string) error {
 		fmt.Fprintf(os.Stderr, "Processing %d repositories across %d organizations\n", len(repos), len(orgRepos))
 	}
 
-	// Process each repository with optimizations
-	var results []archiveResult
-	repoIndex := 0
+	// Validate repositories in parallel, up to --concurrency at a time; the moves themselves
+	// run one after another once the batch is confirmed
+	var ordered []string
 	for orgName, orgRepoList := range orgRepos {
 		if verbose && len(orgRepoList) > 1 {
 			fmt.Fprintf(os.Stderr, "\nProcessing %d repositories from organization: %s\n", len(orgRepoList), orgName)
 		}
-		
-		for _, repo := range orgRepoList {
-			parts := strings.Split(repo, "/")
-			owner, repoName := parts[0], parts[1]
+		ordered = append(ordered, orgRepoList...)
+	}
 
-			if len(repos) > 1 {
-				repoIndex++
-				fmt.Fprintf(os.Stderr, "\n[%d/%d] Processing %s\n", repoIndex, len(repos), repo)
-			}
+	results := make([]archiveResult, len(ordered))
+	batch.ForEach(len(ordered), concurrency, func(index int) {
+		repo := ordered[index]
+		parts := strings.Split(repo, "/")
+		owner, repoName := parts[0], parts[1]
+
+		if len(repos) > 1 {
+			fmt.Fprintf(os.Stderr, "\n[%d/%d] Processing %s\n", index+1, len(repos), repo)
+		}
 
-			result := processRepoArchiveOptimized(*client, owner, repoName, targetCapabilities)
-			results = append(results, result)
+		// A repository an earlier run transferred has no source left to validate
+		if entry, ok := resumedEntry(repo); ok {
+			results[index] = resumedArchiveResult(entry, owner, repoName)
+			return
 		}
+		results[index] = processRepoArchiveOptimized(*client, owner, repoName, targetCapabilities)
+		recordJournal(runJournal.Validated(repo, fmt.Sprintf("%s/%s", targetOrg, results[index].ArchivedName), results[index].Error))
+	})
+
+	// With --interactive, the operator decides about each repository with findings
+	if err := reviewArchiveResults(results); err != nil {
+		return err
 	}
 
 	// Handle dry-run summary for multiple repos
@@ -188,12 +275,56 @@ func runArchive(cmd *cobra.Command, args []string) error {
 	return handleBatchArchiveResults(*client, results)
 }
 
+// reviewArchiveResults lets the operator enforce, skip or abort each validated repository with
+// findings under --interactive, before anything is archived
+func reviewArchiveResults(results []archiveResult) error {
+	review, err := newReviewer()
+	if err != nil || review == nil {
+		return err
+	}
+	for i, result := range results {
+		if result.Mode == "RESUMED" || (!result.Success && !result.Enforceable) {
+			continue
+		}
+		var blockedBy error
+		if !result.Success {
+			blockedBy = result.Error
+		}
+		choice, err := review.reviewFindings("archive", result.Repository, result.Validation, blockedBy)
+		if err != nil {
+			return err
+		}
+		switch choice {
+		case reviewAbort:
+			return fmt.Errorf("archive aborted during review, no repository was archived")
+		case reviewSkip:
+			results[i].Success = false
+			results[i].Mode = "SKIPPED"
+			results[i].Error = fmt.Errorf("skipped during review")
+		case reviewProceed:
+			if blockedBy == nil {
+				continue
+			}
+			results[i].Success = true
+			results[i].Mode = "ENFORCED"
+			results[i].Error = nil
+		}
+		recordJournal(runJournal.Validated(result.Repository, fmt.Sprintf("%s/%s", targetOrg, result.ArchivedName), results[i].Error))
+	}
+	return nil
+}
+
 // processRepoArchiveOptimized handles the archive logic with pre-scanned target capabilities
 func processRepoArchiveOptimized(client api.RESTClient, owner, repoName string, targetCapabilities *types.TargetOrgCapabilities) archiveResult {
 	// Generate unique identifier
 	uid := generateUID()
 	originalPath := fmt.Sprintf("%s/%s", owner, repoName)
 	archivedName := fmt.Sprintf("%s-%s", repoName, uid)
+	// An applied plan fixes the archived name reviewers saw
+	if planned, ok := plannedArchivedName(originalPath); ok {
+		archivedName = planned
+		uid = strings.TrimPrefix(planned, repoName+"-")
+	}
 	
 	result := archiveResult{
 		Repository:   fmt.Sprintf("%s/%s", owner, repoName),
@@ -233,6 +364,38 @@ func processRepoArchiveOptimized(client api.RESTClient, owner, repoName string,
 		return result
 	}
 
+	// A legal hold is honored regardless of --enforce
+	if err := checkLegalHold(client, owner, repoName); err != nil {
+		result.Error = err
+		result.Success = false
+		return result
+	}
+
+	// Origin markers left by an earlier archive are kept as a chain rather than overwritten
+	if earlier := priorOrigins(client, owner, repoName); len(earlier) > 0 {
+		result.OriginChain = origin.Chain(earlier, originalPath)
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: %s already records the origin %s: it was archived before and not restored cleanly.\n", originalPath, earlier[len(earlier)-1])
+		fmt.Fprintf(os.Stderr, "   Recording the origin chain %s instead of overwriting it.\n", strings.Join(result.OriginChain, " → "))
+	}
+
+	// Open issues/PRs advisory (best effort) - archived repositories become read-only
+	if counts, err := getOpenItemCounts(client, owner, repoName); err == nil {
+		result.OpenItems = counts
+	} else if verbose {
+		fmt.Fprintf(os.Stderr, "Warning: Could not count open issues/pull requests: %v\n", err)
+	}
+
+	// Size advisory (best effort) - large repositories take long to move
+	result.Size = measureRepository(client, owner, repoName)
+
+	// Secret scanning summary (best effort) - recorded so the security team can sign off on
+	// what is in the code before access to it is reduced
+	if summary, err := secretscan.Summarize(client, owner, repoName); err == nil {
+		result.SecretScan = summary
+	} else {
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: Could not summarize secret scanning alerts for %s: %v\n", result.Repository, err)
+	}
+
 	// Perform dependency validation unless enforced
 	if !enforce {
 		if verbose {
@@ -246,6 +409,7 @@ func processRepoArchiveOptimized(client api.RESTClient, owner, repoName string,
 			result.Success = false
 			return result
 		}
+		recordHistory(result.Repository, history.KindAnalysis, "completed", "", nil)
 		
 		// If target org is specified, validate against it (use pre-scanned capabilities if available)
 		if targetOrg != "" {
@@ -260,7 +424,7 @@ func processRepoArchiveOptimized(client api.RESTClient, owner, repoName string,
 				if verbose {
 					fmt.Fprintf(os.Stderr, "Scanning target organization capabilities: %s\n", targetOrg)
 				}
-				capabilities, err = validation.ScanTargetOrganization(client, targetOrg, verbose)
+				capabilities, err = scanTargetCapabilities(client, targetOrg)
 				if err != nil {
 					result.Error = fmt.Errorf("failed to scan target organization: %v", err)
 					result.Success = false
@@ -269,12 +433,26 @@ func processRepoArchiveOptimized(client api.RESTClient, owner, repoName string,
 			}
 			
 			// Validate migration readiness
-			validation := validation.ValidateAgainstTarget(deps, capabilities, assign)
+			validation, drifts := validateAgainstPlan(deps, capabilities, assign)
+			validation, drifts, err = provisionAndRevalidate(client, owner, deps, capabilities, validation, drifts)
+			if err != nil {
+				result.Error = err
+				result.Success = false
+				return result
+			}
 			result.Validation = validation
 			
 			if validation.Summary.Blockers > 0 {
 				result.Error = fmt.Errorf("archive blocked: %d validation blockers found", validation.Summary.Blockers)
 				result.Success = false
+				result.Enforceable = true
+				return result
+			}
+
+			if err := checkPlanDrift(result.Repository, drifts); err != nil {
+				result.Error = fmt.Errorf("archive blocked: %v", err)
+				result.Success = false
+				result.Enforceable = true
 				return result
 			}
 		}
@@ -283,7 +461,7 @@ func processRepoArchiveOptimized(client api.RESTClient, owner, repoName string,
 	if verbose {
 		fmt.Fprintf(os.Stderr, "Archive validation passed, repository will be renamed from '%s' to '%s'\n", repoName, archivedName)
 		if dryRun {
-			fmt.Fprintf(os.Stderr, "Note: Original path would be stored as custom property 'repo-origin' = '%s'\n", originalPath)
+			fmt.Fprintf(os.Stderr, "Note: Original path '%s' would be stored with --origin-tracking %s\n", originalPath, originTracking)
 		}
 	}
 
@@ -337,12 +515,14 @@ func displayBatchArchiveSummary(results []archiveResult) error {
 	}
 
 	// Calculate summary statistics
-	var total, wouldSucceed, wouldFail, blockedByValidation int
+	var total, wouldSucceed, wouldFail, blockedByValidation, skipped int
 	total = len(results)
 
 	for _, result := range results {
 		if result.Success {
 			wouldSucceed++
+		} else if result.Mode == "SKIPPED" {
+			skipped++
 		} else {
 			wouldFail++
 			// Check if failure was due to validation blockers
@@ -360,7 +540,23 @@ func displayBatchArchiveSummary(results []archiveResult) error {
 	for _, result := range results {
 		if result.Success {
 			fmt.Printf("%-50s ✅ READY\n", result.Repository)
-			fmt.Printf("  └─ ✅ Would be archived as: %s (read-only)\n", result.ArchivedName)
+			fmt.Printf("  └─ ✅ Would be archived as: %s (%s)\n", result.ArchivedName, readOnlyNote())
+			operation := &archiveOperation{owner: result.Owner, repoName: result.RepoName, targetOwner: targetOrg, archivedName: result.ArchivedName, teams: result.Teams}
+			fmt.Printf("  └─ 🪜 Steps: %s\n", formatStepPlan(withoutCompletedSteps(result.Repository, operation.steps())))
+			if advisory := formatOpenItemsAdvisory(result.OpenItems); advisory != "" {
+				fmt.Printf("  └─ 📬 %s will become read-only\n", advisory)
+			}
+			if result.SecretScan != nil {
+				fmt.Printf("  └─ 🔐 Secret scanning: %s\n", result.SecretScan)
+			}
+			if len(result.OriginChain) > 0 {
+				fmt.Printf("  └─ 🔁 Archived before, origin chain: %s\n", strings.Join(result.OriginChain, " → "))
+			}
+			for _, warning := range result.Size.Warnings() {
+				fmt.Printf("  └─ 📦 %s\n", warning)
+			}
+		} else if result.Mode == "SKIPPED" {
+			fmt.Printf("%-50s ⏭️  SKIPPED\n", result.Repository)
 		} else {
 			fmt.Printf("%-50s ❌ FAIL (BLOCKED)\n", result.Repository)
 			if result.Validation != nil && result.Validation.Summary.Blockers > 0 {
@@ -408,6 +604,18 @@ func displayBatchArchiveSummary(results []archiveResult) error {
 	if blockedByValidation > 0 {
 		fmt.Printf("  Blocked by validation: %d\n", blockedByValidation)
 	}
+	if skipped > 0 {
+		fmt.Printf("  Skipped during review: %d\n", skipped)
+	}
+	effortMinutes := 0
+	for _, result := range results {
+		if result.Validation != nil && result.Validation.Effort != nil {
+			effortMinutes += result.Validation.Effort.TotalMinutes
+		}
+	}
+	if effortMinutes > 0 {
+		fmt.Printf("  Estimated remediation effort: %s\n", utils.FormatMinutes(effortMinutes))
+	}
 	fmt.Printf("  Target: %s\n", targetOrg)
 
 	return nil
@@ -417,30 +625,58 @@ func displayBatchArchiveSummary(results []archiveResult) error {
 func handleBatchArchiveResults(client api.RESTClient, results []archiveResult) error {
 	var hasFailures bool
 
+	ready := 0
+	for _, result := range results {
+		if result.Success {
+			ready++
+		}
+	}
+	if err := confirmBatch("archive", ready); err != nil {
+		return err
+	}
+
 	fmt.Printf("🗃️ EXECUTING: Batch repository archive\n")
 	fmt.Printf("═════════════════════════════════════════\n")
 
 	for _, result := range results {
+		if result.Mode == "SKIPPED" {
+			fmt.Printf("%-50s ⏭️  SKIPPED\n", result.Repository)
+			continue
+		}
 		if !result.Success {
 			hasFailures = true
 			fmt.Printf("%-50s ❌ FAILED\n", result.Repository)
 			if result.Error != nil {
 				fmt.Printf("  └─ ❌ %s\n", result.Error.Error())
 			}
+			reportMigrationOutcome(client, result.Repository, "archive", targetOrg, result.Error)
 			continue
 		}
 
 		// Execute the actual archive (transfer with rename)
 		fmt.Printf("%-50s 🗃️ ARCHIVING...\n", result.Repository)
 		
-		err := executeArchive(client, result.Owner, result.RepoName, targetOrg, result.ArchivedName, result.OriginalPath, result.Teams, verbose)
+		deployment := startMigrationDeployment(client, result.Repository, "archive", targetOrg)
+		err := executeArchive(client, result.Owner, result.RepoName, targetOrg, result.ArchivedName, result.OriginalPath, result.OriginChain, result.Teams, result.Size, verbose)
+		deployment.finish(client, err, fmt.Sprintf("%s/%s", targetOrg, result.ArchivedName))
+		if err != nil {
+			recordHistory(result.Repository, history.KindArchive, "failed", targetOrg, map[string]string{"error": err.Error()})
+		} else {
+			recordHistory(result.Repository, history.KindArchive, "succeeded", targetOrg, archiveRecord{ArchivedName: result.ArchivedName, ArchiveAfter: archiveAfter, SecretScan: result.SecretScan, OriginChain: result.OriginChain})
+		}
 		if err != nil {
 			hasFailures = true
 			fmt.Printf("%-50s ❌ FAILED\n", result.Repository)
 			fmt.Printf("  └─ ❌ %s\n", err.Error())
 		} else {
 			fmt.Printf("%-50s ✅ ARCHIVED\n", result.Repository)
-			fmt.Printf("  └─ ✅ Archived as: %s/%s (read-only)\n", targetOrg, result.ArchivedName)
+			fmt.Printf("  └─ ✅ Archived as: %s/%s (%s)\n", targetOrg, result.ArchivedName, readOnlyNote())
+			if result.SecretScan != nil {
+				fmt.Printf("  └─ 🔐 Secret scanning: %s\n", result.SecretScan)
+			}
+			if len(result.OriginChain) > 0 {
+				fmt.Printf("  └─ 🔁 Origin chain recorded: %s\n", strings.Join(result.OriginChain, " → "))
+			}
 			if verbose {
 				fmt.Printf("  └─ 📝 Original path stored: %s\n", result.OriginalPath)
 			}
@@ -454,180 +690,353 @@ func handleBatchArchiveResults(client api.RESTClient, results []archiveResult) e
 	return nil
 }
 
-// executeArchive performs the actual repository archive with renaming and metadata storage
-func executeArchive(client api.RESTClient, owner, repoName, targetOwner, archivedName, originalPath string, teams []string, verboseOutput bool) error {
-	if verboseOutput {
-		fmt.Fprintf(os.Stderr, "Archiving repository %s/%s as %s/%s...\n", owner, repoName, targetOwner, archivedName)
-		fmt.Fprintf(os.Stderr, "Original path will be stored: %s\n", originalPath)
-	}
+// archiveRecord is the history record of a completed archive
+type archiveRecord struct {
+	ArchivedName string              `json:"archived_name"`
+	ArchiveAfter string              `json:"archive_after,omitempty"` // Soak period before 'finalize' sets the archived flag
+	SecretScan   *secretscan.Summary `json:"secret_scan,omitempty"`
+	OriginChain  []string            `json:"origin_chain,omitempty"` // Set when the repository was archived before
+}
 
-	// Prepare the transfer request with new name
-	transferRequest := map[string]interface{}{
-		"new_owner": targetOwner,
-		"new_name":  archivedName,
-	}
+// archiveOperation holds the state threaded through the steps of an archive
+type archiveOperation struct {
+	client        api.RESTClient
+	owner         string
+	repoName      string
+	targetOwner   string
+	archivedName  string
+	originalPath  string
+	originChain   []string // Origins of earlier archives and this one, when archived before
+	teams         []string
+	size          *reposize.Stats // Extends the wait for the moved repository
+	verboseOutput bool
+
+	captured      sourceCapture
+	teamIDs       []int
+	transferredID int
+	visibility    string
+}
 
-	// If teams are specified, look up their IDs in the target organization
-	if len(teams) > 0 {
-		if verboseOutput {
-			fmt.Fprintf(os.Stderr, "Looking up team IDs for: %v\n", teams)
-		}
-		var teamIDs []int
-		for _, teamSlug := range teams {
-			var teamResponse struct {
-				ID int `json:"id"`
-			}
-			err := client.Get(fmt.Sprintf("orgs/%s/teams/%s", targetOwner, teamSlug), &teamResponse)
-			if err != nil {
-				return fmt.Errorf("failed to get team ID for '%s': %v", teamSlug, err)
+// steps lists the archive as a sequence: capture what the move loses, transfer under the
+// archived name, align the repository while it is still writable, then make it read-only
+// and record where it came from
+func (o *archiveOperation) steps() []steps.Step {
+	operation := o.captured.captureSteps(o.client, o.owner, o.repoName, o.originalPath, false, o.verboseOutput)
+	operation = append(operation, []steps.Step{
+		{Name: "resolve-team-ids", Description: "Look up team IDs in the target organization", Skip: len(o.teams) == 0, Critical: true, Execute: o.resolveTeamIDs},
+		{Name: "transfer", Description: "Transfer the repository under its archived name", Critical: true, Execute: o.transfer, Rollback: o.transferBack},
+		{Name: "default-branch", Description: "Rename the default branch", Skip: defaultBranch == "", Execute: func() error {
+			if err := alignDefaultBranch(o.client, o.targetOwner, o.archivedName, defaultBranch, o.verboseOutput); err != nil {
+				return fmt.Errorf("Default branch rename failed: %v", err)
 			}
-			teamIDs = append(teamIDs, teamResponse.ID)
-			if verboseOutput {
-				fmt.Fprintf(os.Stderr, "Found team '%s' with ID %d\n", teamSlug, teamResponse.ID)
+			return nil
+		}},
+	}...)
+	// Topics must be updated before the repository becomes read-only
+	operation = append(operation, o.captured.restoreSteps(o.client, o.targetOwner, o.archivedName, false, o.verboseOutput)...)
+	return append(operation, []steps.Step{
+		{Name: "announce", Description: "Announce the new location on open issues and pull requests", Skip: !announce, Execute: func() error {
+			if err := announceMigration(o.client, o.originalPath, o.targetOwner, o.archivedName, o.verboseOutput); err != nil {
+				return fmt.Errorf("Migration announcement failed: %v", err)
 			}
+			return nil
+		}},
+		{Name: "settings-profile", Description: "Apply the settings profile", Skip: loadedSettingsProfile == nil, Execute: func() error {
+			return applySettingsProfile(o.client, o.targetOwner, o.archivedName, loadedSettingsProfile, o.verboseOutput)
+		}},
+		// Topics and the description cannot be changed once the repository is archived
+		{Name: "store-origin", Description: originTrackingDescription(), Execute: o.storeOrigin},
+		// With --archive-after the repository stays writable until 'finalize' runs after the soak period
+		{Name: "schedule-archive", Description: "Tag the repository and record the pending archive for 'finalize'", Skip: archiveSoak == 0, Execute: o.scheduleArchive, Rollback: func() error {
+			runState.RemovePendingArchive(fmt.Sprintf("%s/%s", o.targetOwner, o.archivedName))
+			return nil
+		}},
+		{Name: "set-archived", Description: "Mark the repository as archived (read-only)", Skip: archiveSoak > 0, Execute: o.setArchived, Rollback: func() error {
+			return setRepositoryArchiveStatus(o.client, o.targetOwner, o.archivedName, false, o.verboseOutput)
+		}},
+		{Name: "create-tombstone", Description: "Create an archived tombstone at the original path", Skip: !createTombstone, Execute: func() error {
+			originalPath := strings.SplitN(o.originalPath, "/", 2)
+			return createTombstoneRepository(o.client, originalPath[0], originalPath[1], fmt.Sprintf("%s/%s", o.targetOwner, o.archivedName), o.visibility)
+		}, Rollback: func() error {
+			return deleteTombstone(o.client, o.originalPath)
+		}},
+		// Org rulesets that enumerate repository names do not cover the archived name
+		{Name: "ruleset-includes", Description: "Check target org rulesets for the archived name", Execute: func() error {
+			return alignRulesetIncludes(o.client, o.targetOwner, o.repoName, o.archivedName, patchRulesetIncludes)
+		}},
+		{Name: "cleanup-source", Description: "Remove references left in the source organization", Skip: !cleanupSource, Execute: func() error {
+			cleanupSourceReferences(o.client, o.owner, o.repoName, int64(o.transferredID), fmt.Sprintf("%s/%s", o.targetOwner, o.archivedName))
+			return nil
+		}},
+		{Name: "verify-settings", Description: "Report settings that changed during the move", Skip: !verifySettings, Restores: true, Execute: o.verifySettings},
+	}...)
+}
+
+// resolveTeamIDs looks up the IDs of the teams included in the transfer payload
+func (o *archiveOperation) resolveTeamIDs() error {
+	if o.verboseOutput {
+		fmt.Fprintf(os.Stderr, "Looking up team IDs for: %v\n", o.teams)
+	}
+	for _, teamName := range o.teams {
+		teamSlug, _ := resolveTeamSlug(o.client, o.targetOwner, teamName)
+		var teamResponse struct {
+			ID int `json:"id"`
+		}
+		err := o.client.Get(fmt.Sprintf("orgs/%s/teams/%s", o.targetOwner, teamSlug), &teamResponse)
+		if err != nil {
+			return fmt.Errorf("failed to get team ID for '%s': %v", teamSlug, err)
+		}
+		o.teamIDs = append(o.teamIDs, teamResponse.ID)
+		if o.verboseOutput {
+			fmt.Fprintf(os.Stderr, "Found team '%s' with ID %d\n", teamSlug, teamResponse.ID)
 		}
-		transferRequest["team_ids"] = teamIDs
+	}
+	return nil
+}
+
+// transfer moves the repository under its archived name. When the transfer call fails, a
+// repository archived by an earlier, interrupted run is adopted instead.
+func (o *archiveOperation) transfer() error {
+	transferRequest := map[string]interface{}{
+		"new_owner": o.targetOwner,
+		"new_name":  o.archivedName,
+	}
+	if len(o.teamIDs) > 0 {
+		transferRequest["team_ids"] = o.teamIDs
 	}
 
-	// Marshal the payload
 	payloadBytes, err := json.Marshal(transferRequest)
 	if err != nil {
 		return fmt.Errorf("failed to marshal archive payload: %v", err)
 	}
-
-	if verboseOutput {
+	if o.verboseOutput {
 		fmt.Fprintf(os.Stderr, "Archive payload: %s\n", string(payloadBytes))
 	}
 
-	// Execute the transfer
 	var transferResponse struct {
-		ID          int    `json:"id"`
-		NodeID      string `json:"node_id"`
-		Name        string `json:"name"`
-		FullName    string `json:"full_name"`
-		Owner       struct {
-			Login string `json:"login"`
-		} `json:"owner"`
-	}
-	err = client.Post(fmt.Sprintf("repos/%s/%s/transfer", owner, repoName), bytes.NewBuffer(payloadBytes), &transferResponse)
+		ID         int    `json:"id"`
+		FullName   string `json:"full_name"`
+		Visibility string `json:"visibility"`
+	}
+	err = o.client.Post(fmt.Sprintf("repos/%s/%s/transfer", o.owner, o.repoName), bytes.NewBuffer(payloadBytes), &transferResponse)
 	if err != nil {
-		// Check if the repository might already be transferred
-		if verboseOutput {
-			fmt.Fprintf(os.Stderr, "Transfer API call failed: %v\n", err)
-			fmt.Fprintf(os.Stderr, "Checking if repository was already transferred with different archive name...\n")
-		}
-		
-		// Search for repositories in the target org that start with the base repository name
-		baseName := repoName
-		var reposList []struct {
-			Name     string `json:"name"`
-			FullName string `json:"full_name"`
-			Owner    struct {
-				Login string `json:"login"`
-			} `json:"owner"`
-		}
-		
-		// Get list of repositories in target organization
-		err2 := client.Get(fmt.Sprintf("orgs/%s/repos", targetOwner), &reposList)
-		if err2 == nil {
-			// Look for repositories that start with the base name followed by a hyphen and UID pattern
-			baseNamePrefix := baseName + "-"
-			var foundRepo *struct {
-				Name     string `json:"name"`
-				FullName string `json:"full_name"`
-				Owner    struct {
-					Login string `json:"login"`
-				} `json:"owner"`
-			}
-			
-			for _, repo := range reposList {
-				if strings.HasPrefix(repo.Name, baseNamePrefix) && len(repo.Name) > len(baseNamePrefix)+6 {
-					// Found a potential match - check if it has the UID pattern (letters and numbers)
-					suffix := repo.Name[len(baseNamePrefix):]
-					if len(suffix) >= 6 && len(suffix) <= 10 {
-						// This looks like an archived version of our repository
-						foundRepo = &repo
-						if verboseOutput {
-							fmt.Fprintf(os.Stderr, "Found existing archived repository: %s\n", repo.FullName)
-						}
-						break
-					}
-				}
-			}
-			
-			if foundRepo != nil {
-				// Update our archive name to match the existing repository
-				archivedName = foundRepo.Name
-				transferResponse.FullName = foundRepo.FullName
-				transferResponse.Owner.Login = foundRepo.Owner.Login
-				if verboseOutput {
-					fmt.Fprintf(os.Stderr, "✅ Repository already exists in target organization: %s\n", foundRepo.FullName)
-					fmt.Fprintf(os.Stderr, "Proceeding with custom property and archive flag setting...\n")
-				}
-			} else {
-				// Transfer failed and no archived version found - this is a real error
-				return fmt.Errorf("failed to transfer repository and no archived version found in target organization: %v", err)
-			}
-		} else {
-			// Can't list repos, try the specific name check as fallback
-			var existingRepo struct {
-				ID       int    `json:"id"`
-				FullName string `json:"full_name"`
-				Owner    struct {
-					Login string `json:"login"`
-				} `json:"owner"`
-			}
-			checkErr := client.Get(fmt.Sprintf("repos/%s/%s", targetOwner, archivedName), &existingRepo)
-			if checkErr == nil {
-				transferResponse.FullName = existingRepo.FullName
-				transferResponse.Owner.Login = existingRepo.Owner.Login
-				if verboseOutput {
-					fmt.Fprintf(os.Stderr, "✅ Repository already exists in target organization: %s\n", existingRepo.FullName)
-					fmt.Fprintf(os.Stderr, "Proceeding with custom property and archive flag setting...\n")
-				}
-			} else {
-				return fmt.Errorf("failed to transfer repository and repository does not exist in target organization: %v", err)
-			}
+		if adoptErr := o.adoptExistingArchive(err); adoptErr != nil {
+			return adoptErr
 		}
 	} else {
-		if verboseOutput {
+		o.transferredID = transferResponse.ID
+		o.visibility = transferResponse.Visibility
+		if o.verboseOutput {
 			fmt.Fprintf(os.Stderr, "✅ Repository transfer completed: %s\n", transferResponse.FullName)
 		}
 	}
+	// An adopted archive may carry another UID than the one validated
+	recordJournal(runJournal.SetTarget(o.originalPath, fmt.Sprintf("%s/%s", o.targetOwner, o.archivedName)))
 
-	// Add a small delay to allow the transfer to fully complete
-	if verboseOutput {
+	// Add a small delay to allow the transfer to fully complete; large repositories get longer
+	if o.verboseOutput {
 		fmt.Fprintf(os.Stderr, "Waiting for transfer to complete fully...\n")
 	}
-	time.Sleep(3 * time.Second)
+	waitForMovedRepository(o.client, fmt.Sprintf("%s/%s", o.targetOwner, o.archivedName), o.size, 3*time.Second)
+	return nil
+}
 
-	// Archive the repository (set as read-only) in the target organization
-	err = setRepositoryArchiveStatus(client, targetOwner, archivedName, true, verboseOutput)
-	if err != nil {
-		if verboseOutput {
+// adoptExistingArchive looks for a repository already archived under a different UID after
+// the transfer call failed, and continues with it
+func (o *archiveOperation) adoptExistingArchive(transferErr error) error {
+	if o.verboseOutput {
+		fmt.Fprintf(os.Stderr, "Transfer API call failed: %v\n", transferErr)
+		fmt.Fprintf(os.Stderr, "Checking if repository was already transferred with different archive name...\n")
+	}
+
+	type targetRepository struct {
+		ID       int    `json:"id"`
+		Name     string `json:"name"`
+		FullName string `json:"full_name"`
+	}
+
+	// Search for repositories in the target org that start with the base repository name
+	var reposList []targetRepository
+	if err := ghclient.GetAll(&o.client, fmt.Sprintf("orgs/%s/repos", o.targetOwner), &reposList); err != nil {
+		// Can't list repos, try the specific name check as fallback
+		var existingRepo targetRepository
+		if checkErr := o.client.Get(fmt.Sprintf("repos/%s/%s", o.targetOwner, o.archivedName), &existingRepo); checkErr != nil {
+			return fmt.Errorf("failed to transfer repository and repository does not exist in target organization: %v", transferErr)
+		}
+		o.transferredID = existingRepo.ID
+		if o.verboseOutput {
+			fmt.Fprintf(os.Stderr, "✅ Repository already exists in target organization: %s\n", existingRepo.FullName)
+			fmt.Fprintf(os.Stderr, "Proceeding with custom property and archive flag setting...\n")
+		}
+		return nil
+	}
+
+	// Look for repositories that start with the base name followed by a hyphen and UID pattern
+	baseNamePrefix := o.repoName + "-"
+	for _, repo := range reposList {
+		if !strings.HasPrefix(repo.Name, baseNamePrefix) || len(repo.Name) <= len(baseNamePrefix)+6 {
+			continue
+		}
+		// Found a potential match - check if it has the UID pattern (letters and numbers)
+		suffix := repo.Name[len(baseNamePrefix):]
+		if len(suffix) >= 6 && len(suffix) <= 10 {
+			// Update our archive name to match the existing repository
+			o.archivedName = repo.Name
+			o.transferredID = repo.ID
+			if o.verboseOutput {
+				fmt.Fprintf(os.Stderr, "✅ Repository already exists in target organization: %s\n", repo.FullName)
+				fmt.Fprintf(os.Stderr, "Proceeding with custom property and archive flag setting...\n")
+			}
+			return nil
+		}
+	}
+
+	// Transfer failed and no archived version found - this is a real error
+	return fmt.Errorf("failed to transfer repository and no archived version found in target organization: %v", transferErr)
+}
+
+// transferBack returns the repository to its source owner and original name
+func (o *archiveOperation) transferBack() error {
+	return transferRepositoryBack(o.client, o.targetOwner, o.archivedName, o.owner, o.repoName)
+}
+
+// setArchived archives the repository (sets it read-only) in the target organization
+func (o *archiveOperation) setArchived() error {
+	if err := setRepositoryArchiveStatus(o.client, o.targetOwner, o.archivedName, true, o.verboseOutput); err != nil {
+		if o.verboseOutput {
 			fmt.Fprintf(os.Stderr, "❌ Warning: Failed to set repository archive status: %v\n", err)
 			fmt.Fprintf(os.Stderr, "Repository transferred but not marked as archived (read-only)\n")
 		}
 		// Don't fail the entire operation for archive status issues, but log the issue
-	} else {
-		if verboseOutput {
-			fmt.Fprintf(os.Stderr, "✅ Repository marked as archived (read-only)\n")
-		}
+		return nil
+	}
+	if o.verboseOutput {
+		fmt.Fprintf(os.Stderr, "✅ Repository marked as archived (read-only)\n")
+	}
+	return nil
+}
+
+// scheduleArchive tags the repository as pending archive and records it in the state file so
+// 'finalize' sets the archived flag once the soak period has passed
+func (o *archiveOperation) scheduleArchive() error {
+	topics, err := getRepositoryTopics(o.client, o.targetOwner, o.archivedName)
+	if err == nil {
+		err = setRepositoryTopics(o.client, o.targetOwner, o.archivedName, append(topics, pendingArchiveTopic))
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: Could not add the %s topic to %s/%s: %v\n", pendingArchiveTopic, o.targetOwner, o.archivedName, err)
+	}
+
+	now := time.Now().UTC()
+	runState.StorePendingArchive(state.PendingArchive{
+		Repository:    fmt.Sprintf("%s/%s", o.targetOwner, o.archivedName),
+		OriginalPath:  o.originalPath,
+		TransferredAt: now,
+		ArchiveAfter:  now.Add(archiveSoak),
+	})
+	if o.verboseOutput {
+		fmt.Fprintf(os.Stderr, "⏳ Archived flag will be set by 'finalize' after %s\n", now.Add(archiveSoak).Format(time.RFC3339))
 	}
+	return nil
+}
 
-	// Store the original path as a repository custom property
-	err = storeOriginalPathProperty(client, targetOwner, archivedName, originalPath, verboseOutput)
+// storeOrigin stores the original path with the --origin-tracking strategies, and the origin
+// chain of a repository archived before
+func (o *archiveOperation) storeOrigin() error {
+	err := storeOriginTracking(o.client, o.targetOwner, o.archivedName, o.originalPath, origin.Archived, o.verboseOutput)
+	if err == nil && len(o.originChain) > 0 {
+		err = storeOriginHistory(o.client, o.targetOwner, o.archivedName, o.originChain, o.verboseOutput)
+	}
 	if err != nil {
-		if verboseOutput {
-			fmt.Fprintf(os.Stderr, "❌ Warning: Failed to store original path as custom property: %v\n", err)
+		if o.verboseOutput {
 			fmt.Fprintf(os.Stderr, "Archive completed, but restoration metadata may need to be added manually\n")
 		}
-		// Don't fail the entire operation for metadata storage issues
+		// Don't fail the entire operation for metadata storage issues; the warning is recorded in the journal (see rollback)
+		return fmt.Errorf("Failed to store original path: %v", err)
+	}
+	return nil
+}
+
+// verifySettings reports settings GitHub changed implicitly during the move
+func (o *archiveOperation) verifySettings() error {
+	if o.captured.settingsBefore == nil {
+		return nil
+	}
+	settingsAfter, err := snapshotRepositorySettings(o.client, o.targetOwner, o.archivedName)
+	if err != nil {
+		return fmt.Errorf("Could not snapshot archived repository settings: %v", err)
+	}
+	drifts := markExpectedDrift(diffSettingsSnapshots(o.captured.settingsBefore, settingsAfter), loadedSettingsProfile, true)
+	printSettingsDriftReport(fmt.Sprintf("%s/%s", o.targetOwner, o.archivedName), drifts)
+	recordHistory(o.originalPath, history.KindVerification, verificationStatus(drifts), o.targetOwner, drifts)
+	return nil
+}
+
+// resume looks up the moved repository and restores the source data kept in the journal when
+// the journal records the repository as transferred by an earlier run
+func (o *archiveOperation) resume() error {
+	entry, ok := resumedEntry(o.originalPath)
+	if !ok {
+		return nil
+	}
+	moved, err := getMovedRepository(o.client, entry.Target)
+	if err != nil {
+		return err
+	}
+	if err := o.captured.resume(entry); err != nil {
+		return err
+	}
+	o.transferredID = moved.ID
+	o.visibility = moved.Visibility
+	return nil
+}
+
+// resumedArchiveResult stands in for the validation of a repository an earlier run already
+// transferred under its archived name; it goes straight to the steps left
+func resumedArchiveResult(entry journal.Entry, owner, repoName string) archiveResult {
+	archivedName := entry.Target[strings.Index(entry.Target, "/")+1:]
+	return archiveResult{
+		Repository:   entry.Repository,
+		OriginalName: repoName,
+		ArchivedName: archivedName,
+		Owner:        owner,
+		RepoName:     repoName,
+		Success:      true,
+		Mode:         "RESUMED",
+		UID:          strings.TrimPrefix(archivedName, repoName+"-"),
+		OriginalPath: entry.Repository,
+	}
+}
+
+// executeArchive performs the actual repository archive with renaming and metadata storage
+func executeArchive(client api.RESTClient, owner, repoName, targetOwner, archivedName, originalPath string, originChain, teams []string, size *reposize.Stats, verboseOutput bool) error {
+	if verboseOutput {
+		fmt.Fprintf(os.Stderr, "Archiving repository %s/%s as %s/%s...\n", owner, repoName, targetOwner, archivedName)
+		fmt.Fprintf(os.Stderr, "Original path will be stored: %s\n", originalPath)
+	}
+
+	operation := &archiveOperation{
+		client:        client,
+		owner:         owner,
+		repoName:      repoName,
+		targetOwner:   targetOwner,
+		archivedName:  archivedName,
+		originalPath:  originalPath,
+		originChain:   originChain,
+		teams:         teams,
+		size:          size,
+		verboseOutput: verboseOutput,
+	}
+	if err := operation.resume(); err != nil {
+		return err
+	}
+	if _, err := runOperationSteps(originalPath, operation.steps()); err != nil {
+		return err
 	}
 
 	if verboseOutput {
 		fmt.Fprintf(os.Stderr, "Archive completed successfully\n")
-		fmt.Fprintf(os.Stderr, "Repository archived from %s to %s/%s\n", originalPath, targetOwner, archivedName)
+		fmt.Fprintf(os.Stderr, "Repository archived from %s to %s/%s\n", originalPath, targetOwner, operation.archivedName)
 		fmt.Fprintf(os.Stderr, "Repository is now read-only and marked as archived\n")
 		fmt.Fprintf(os.Stderr, "Original path '%s' stored for restoration capability\n", originalPath)
 	}
@@ -639,7 +1048,7 @@ func executeArchive(client api.RESTClient, owner, repoName, targetOwner, archive
 // If the 'repo-origin' custom property is not defined in the target organization's schema,
 // a warning is reported and the operation continues without storing.
 func storeOriginalPathProperty(client api.RESTClient, targetOwner, repoName, originalPath string, verbose bool) error {
-	const propertyName = "repo-origin"
+	const propertyName = repoOriginProperty
 
 	if verbose {
 		fmt.Fprintf(os.Stderr, "Checking if custom property '%s' is defined in organization '%s'...\n", propertyName, targetOwner)
@@ -664,7 +1073,7 @@ func storeOriginalPathProperty(client api.RESTClient, targetOwner, repoName, ori
 
 	if !propExists {
 		fmt.Fprintf(os.Stderr, "⚠️  Warning: Organization '%s' does not have a '%s' custom property defined.\n", targetOwner, propertyName)
-		fmt.Fprintf(os.Stderr, "   Skipping origin tracking. To enable it, add a 'repo-origin' string property to the organization's custom property schema.\n")
+		fmt.Fprintf(os.Stderr, "   Skipping origin tracking. To enable it, add a 'repo-origin' string property to the organization's custom property schema, or use --origin-tracking topic or description.\n")
 		return nil
 	}
 
@@ -673,11 +1082,10 @@ func storeOriginalPathProperty(client api.RESTClient, targetOwner, repoName, ori
 		fmt.Fprintf(os.Stderr, "Storing original path as custom property '%s' = '%s'...\n", propertyName, originalPath)
 	}
 
+	// The property is defined but could not be set: the caller reports the half-migrated repository
 	err = setCustomProperty(client, targetOwner, repoName, propertyName, originalPath, verbose)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "⚠️  Warning: Failed to set custom property '%s': %v\n", propertyName, err)
-		fmt.Fprintf(os.Stderr, "   Origin tracking skipped.\n")
-		return nil
+		return fmt.Errorf("could not set custom property '%s': %v", propertyName, err)
 	}
 
 	if verbose {
@@ -686,6 +1094,73 @@ func storeOriginalPathProperty(client api.RESTClient, targetOwner, repoName, ori
 	return nil
 }
 
+// priorOrigins returns the origins a repository about to be archived already records, oldest
+// first: the repo-origin-history property, else the repo-origin property, the description
+// marker or the origin topic. A repository with any of them was archived before and restored
+// without clearing them. Origins that cannot be read are treated as absent.
+func priorOrigins(client api.RESTClient, owner, repo string) []string {
+	properties, err := getRepositoryPropertyValues(client, owner, repo)
+	if err != nil && verbose {
+		fmt.Fprintf(os.Stderr, "Could not read the custom properties of %s/%s: %v\n", owner, repo, err)
+	}
+	for name, values := range properties {
+		if !strings.EqualFold(name, repoOriginHistoryProperty) || len(values) == 0 {
+			continue
+		}
+		if chain, err := origin.ParseHistory(values[0]); err == nil && len(chain) > 0 {
+			return chain
+		}
+	}
+	for name, values := range properties {
+		if strings.EqualFold(name, repoOriginProperty) && len(values) > 0 && strings.TrimSpace(values[0]) != "" {
+			return []string{strings.TrimSpace(values[0])}
+		}
+	}
+
+	markers, err := readOriginMarkers(client, owner, repo)
+	if err != nil {
+		return nil
+	}
+	if path, ok := markers.descriptionOrigin(); ok {
+		return []string{path}
+	}
+	if topicOwner, topicName, ok := origin.ParseTopics(markers.Topics); ok {
+		return []string{fmt.Sprintf("%s/%s", topicOwner, topicName)}
+	}
+	return nil
+}
+
+// storeOriginHistory stores the origin chain of a repository archived more than once as a JSON
+// array in the 'repo-origin-history' custom property. Without that property in the target
+// organization's schema, a warning is reported and the chain is only kept in the history.
+func storeOriginHistory(client api.RESTClient, owner, repo string, chain []string, verbose bool) error {
+	var schema []map[string]interface{}
+	if err := client.Get(fmt.Sprintf("orgs/%s/properties/schema", owner), &schema); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: Could not retrieve custom property schema for '%s': %v\n", owner, err)
+		return nil
+	}
+	defined := false
+	for _, property := range schema {
+		if name, ok := property["property_name"].(string); ok && name == repoOriginHistoryProperty {
+			defined = true
+			break
+		}
+	}
+	if !defined {
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: Organization '%s' does not have a '%s' custom property defined.\n", owner, repoOriginHistoryProperty)
+		fmt.Fprintf(os.Stderr, "   The origin chain %s is only kept in the history. To store it on the repository, add a '%s' string property.\n", strings.Join(chain, " → "), repoOriginHistoryProperty)
+		return nil
+	}
+
+	if err := setCustomProperty(client, owner, repo, repoOriginHistoryProperty, origin.FormatHistory(chain), verbose); err != nil {
+		return fmt.Errorf("could not set custom property '%s': %v", repoOriginHistoryProperty, err)
+	}
+	if verbose {
+		fmt.Fprintf(os.Stderr, "✅ Origin chain stored in custom property '%s' = '%s'\n", repoOriginHistoryProperty, origin.FormatHistory(chain))
+	}
+	return nil
+}
+
 // setCustomProperty attempts to set a custom property on a repository
 func setCustomProperty(client api.RESTClient, owner, repo, propertyName, value string, verbose bool) error {
 	// Repository custom properties API endpoint
@@ -768,58 +1243,79 @@ func ensureCustomPropertyExists(client api.RESTClient, owner, propertyName strin
 	return nil
 }
 
-// addArchiveTopicFallback adds a topic to indicate the original repository (fallback method)
-func addArchiveTopicFallback(client api.RESTClient, owner, repo, originalPath string, verbose bool) error {
-	if verbose {
-		fmt.Fprintf(os.Stderr, "Using repository topics as fallback storage...\n")
-	}
-	
-	// Create a topic from the original path (GitHub topics have restrictions)
-	// Convert "github-innersource/gh-repo-transfer-test-main" to "archived-from-github-innersource-gh-repo-transfer-test-main"
-	topicValue := "archived-from-" + strings.ReplaceAll(strings.ToLower(originalPath), "/", "-")
-	
-	// Get current topics
-	url := fmt.Sprintf("repos/%s/%s/topics", owner, repo)
-	var currentTopics struct {
-		Names []string `json:"names"`
-	}
-	
-	err := client.Get(url, &currentTopics)
+// storeOriginTracking records the original path of a moved repository with each strategy of
+// --origin-tracking; verb is origin.Archived or origin.Transferred. A strategy that fails does
+// not stop the others; their errors are returned together.
+func storeOriginTracking(client api.RESTClient, owner, repo, originalPath, verb string, verbose bool) error {
+	strategies, err := origin.Strategies(originTracking)
 	if err != nil {
-		return fmt.Errorf("failed to get current topics: %v", err)
+		return err
+	}
+
+	var failures []string
+	for _, strategy := range strategies {
+		switch strategy {
+		case origin.StrategyProperty:
+			err = storeOriginalPathProperty(client, owner, repo, originalPath, verbose)
+		case origin.StrategyTopic:
+			err = addOriginTopic(client, owner, repo, originalPath, verb, verbose)
+		case origin.StrategyDescription:
+			err = updateDescriptionWithOrigin(client, owner, repo, originalPath, verb, verbose)
+		}
+		if err != nil {
+			failures = append(failures, err.Error())
+		}
 	}
-	
-	// Add our archive topic
-	newTopics := append(currentTopics.Names, topicValue)
-	
-	payload := map[string]interface{}{
-		"names": newTopics,
+	if len(failures) > 0 {
+		return fmt.Errorf("%s", strings.Join(failures, "; "))
 	}
-	
-	payloadBytes, err := json.Marshal(payload)
+	return nil
+}
+
+// originTrackingDescription describes the store-origin step for the selected --origin-tracking
+func originTrackingDescription() string {
+	switch strings.ToLower(originTracking) {
+	case origin.StrategyTopic:
+		return "Store the original path as a topic"
+	case origin.StrategyDescription:
+		return "Store the original path in the description"
+	case origin.StrategyAll:
+		return "Store the original path as the repo-origin property, a topic and in the description"
+	}
+	return "Store the original path as the repo-origin property"
+}
+
+// addOriginTopic adds a topic recording the original path (--origin-tracking topic), replacing
+// the origin topic of an earlier move
+func addOriginTopic(client api.RESTClient, owner, repo, originalPath, verb string, verbose bool) error {
+	topic, err := origin.Topic(verb, originalPath)
 	if err != nil {
-		return fmt.Errorf("failed to marshal topics payload: %v", err)
+		return fmt.Errorf("could not store the origin topic: %v", err)
 	}
-	
-	var response map[string]interface{}
-	err = client.Put(url, bytes.NewBuffer(payloadBytes), &response)
+
+	currentTopics, err := getRepositoryTopics(client, owner, repo)
 	if err != nil {
-		return fmt.Errorf("failed to update repository topics: %v", err)
+		return err
 	}
-	
+	newTopics := []string{topic}
+	for _, current := range currentTopics {
+		if !origin.IsTopic(current) {
+			newTopics = append(newTopics, current)
+		}
+	}
+	if err := setRepositoryTopics(client, owner, repo, newTopics); err != nil {
+		return err
+	}
+
 	if verbose {
-		fmt.Fprintf(os.Stderr, "Added topic: %s\n", topicValue)
+		fmt.Fprintf(os.Stderr, "Added topic: %s\n", topic)
 	}
-	
 	return nil
 }
 
-// updateDescriptionWithOrigin updates repository description to include origin info (fallback method)
-func updateDescriptionWithOrigin(client api.RESTClient, owner, repo, originalPath string, verbose bool) error {
-	if verbose {
-		fmt.Fprintf(os.Stderr, "Using repository description as fallback storage...\n")
-	}
-	
+// updateDescriptionWithOrigin appends a marker recording the original path to the repository
+// description (--origin-tracking description), e.g. "[ARCHIVED FROM: owner/repo]"
+func updateDescriptionWithOrigin(client api.RESTClient, owner, repo, originalPath, verb string, verbose bool) error {
 	// Get current repository information
 	url := fmt.Sprintf("repos/%s/%s", owner, repo)
 	var repoInfo struct {
@@ -830,37 +1326,36 @@ func updateDescriptionWithOrigin(client api.RESTClient, owner, repo, originalPat
 	if err != nil {
 		return fmt.Errorf("failed to get repository info: %v", err)
 	}
-	
-	// Prepare new description
-	originNote := fmt.Sprintf("[ARCHIVED FROM: %s]", originalPath)
-	var newDescription string
-	
-	if repoInfo.Description != nil && *repoInfo.Description != "" {
-		newDescription = fmt.Sprintf("%s %s", *repoInfo.Description, originNote)
-	} else {
-		newDescription = originNote
+
+	description := ""
+	if repoInfo.Description != nil {
+		description = *repoInfo.Description
 	}
-	
-	// Update repository description
-	updatePayload := map[string]interface{}{
-		"description": newDescription,
+	newDescription := origin.Describe(description, verb, originalPath)
+	if err := setRepositoryDescription(client, owner, repo, newDescription); err != nil {
+		return err
 	}
-	
-	payloadBytes, err := json.Marshal(updatePayload)
+
+	if verbose {
+		fmt.Fprintf(os.Stderr, "Updated description: %s\n", newDescription)
+	}
+	return nil
+}
+
+// setRepositoryDescription replaces the description of a repository
+func setRepositoryDescription(client api.RESTClient, owner, repo, description string) error {
+	payloadBytes, err := json.Marshal(map[string]interface{}{
+		"description": description,
+	})
 	if err != nil {
 		return fmt.Errorf("failed to marshal description update payload: %v", err)
 	}
 	
 	var response map[string]interface{}
-	err = client.Patch(url, bytes.NewBuffer(payloadBytes), &response)
+	err = client.Patch(fmt.Sprintf("repos/%s/%s", owner, repo), bytes.NewBuffer(payloadBytes), &response)
 	if err != nil {
 		return fmt.Errorf("failed to update repository description: %v", err)
 	}
-	
-	if verbose {
-		fmt.Fprintf(os.Stderr, "Updated description with origin: %s\n", newDescription)
-	}
-	
 	return nil
 }
 
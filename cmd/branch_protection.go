@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+
+	"github.com/jefeish/gh-repo-transfer/internal/ghclient"
+)
+
+// protectionActors are the users, teams and apps a branch protection rule refers to
+type protectionActors struct {
+	Users []struct {
+		Login string `json:"login"`
+	} `json:"users"`
+	Teams []struct {
+		Name string `json:"name"`
+		Slug string `json:"slug"`
+	} `json:"teams"`
+	Apps []struct {
+		Slug string `json:"slug"`
+	} `json:"apps"`
+}
+
+// protectionToggle is a branch protection setting the API returns as {"enabled": bool}
+type protectionToggle struct {
+	Enabled bool `json:"enabled"`
+}
+
+// branchProtection captures the protection rule of one branch for --migrate-branch-protection
+type branchProtection struct {
+	Branch               string
+	RequiredStatusChecks *struct {
+		Strict   bool     `json:"strict"`
+		Contexts []string `json:"contexts"`
+		Checks   []struct {
+			Context string `json:"context"`
+			AppID   *int64 `json:"app_id"`
+		} `json:"checks"`
+	} `json:"required_status_checks"`
+	RequiredPullRequestReviews *struct {
+		DismissalRestrictions        *protectionActors `json:"dismissal_restrictions"`
+		DismissStaleReviews          bool              `json:"dismiss_stale_reviews"`
+		RequireCodeOwnerReviews      bool              `json:"require_code_owner_reviews"`
+		RequiredApprovingReviewCount int               `json:"required_approving_review_count"`
+		RequireLastPushApproval      bool              `json:"require_last_push_approval"`
+		BypassPullRequestAllowances  *protectionActors `json:"bypass_pull_request_allowances"`
+	} `json:"required_pull_request_reviews"`
+	Restrictions                   *protectionActors `json:"restrictions"`
+	EnforceAdmins                  protectionToggle  `json:"enforce_admins"`
+	RequiredSignatures             protectionToggle  `json:"required_signatures"`
+	RequiredLinearHistory          protectionToggle  `json:"required_linear_history"`
+	AllowForcePushes               protectionToggle  `json:"allow_force_pushes"`
+	AllowDeletions                 protectionToggle  `json:"allow_deletions"`
+	BlockCreations                 protectionToggle  `json:"block_creations"`
+	RequiredConversationResolution protectionToggle  `json:"required_conversation_resolution"`
+	LockBranch                     protectionToggle  `json:"lock_branch"`
+	AllowForkSyncing               protectionToggle  `json:"allow_fork_syncing"`
+}
+
+// captureBranchProtections reads the protection rules of all protected branches (requires admin
+// access). A rule that cannot be read fails the capture, so no protection is silently lost.
+func captureBranchProtections(client api.RESTClient, owner, repo string) ([]branchProtection, error) {
+	var branches []struct {
+		Name string `json:"name"`
+	}
+	if err := ghclient.GetAll(&client, fmt.Sprintf("repos/%s/%s/branches?protected=true", owner, repo), &branches); err != nil {
+		return nil, fmt.Errorf("failed to list protected branches: %v", err)
+	}
+
+	var protections []branchProtection
+	for _, branch := range branches {
+		protection := branchProtection{Branch: branch.Name}
+		if err := client.Get(fmt.Sprintf("repos/%s/%s/branches/%s/protection", owner, repo, url.PathEscape(branch.Name)), &protection); err != nil {
+			return nil, fmt.Errorf("failed to read the protection of branch '%s': %v", branch.Name, err)
+		}
+		protections = append(protections, protection)
+	}
+	return protections, nil
+}
+
+// reapplyBranchProtections sets the captured protection rules on the moved repository. Teams are
+// matched in the destination organization under --team-matcher; teams without a match are left
+// out of the rule with a warning. Users and apps are kept by login and slug.
+func reapplyBranchProtections(client api.RESTClient, owner, repo string, protections []branchProtection, verboseOutput bool) error {
+	var failed []string
+	applied := 0
+	for _, protection := range protections {
+		payload := branchProtectionPayload(protection, func(name, slug string) (string, bool) {
+			if name == "" {
+				name = slug
+			}
+			target, ok := resolveTeamSlug(client, owner, name)
+			if !ok {
+				fmt.Fprintf(os.Stderr, "⚠️  Warning: Team '%s' of the protection of branch '%s' does not exist in %s; the rule loses this team\n", name, protection.Branch, owner)
+			}
+			return target, ok
+		})
+		payloadBytes, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+
+		endpoint := fmt.Sprintf("repos/%s/%s/branches/%s/protection", owner, repo, url.PathEscape(protection.Branch))
+		var response map[string]interface{}
+		if err := client.Put(endpoint, bytes.NewBuffer(payloadBytes), &response); err != nil {
+			failed = append(failed, fmt.Sprintf("%s (%v)", protection.Branch, err))
+			continue
+		}
+		if protection.RequiredSignatures.Enabled {
+			if err := client.Post(endpoint+"/required_signatures", nil, &response); err != nil {
+				failed = append(failed, fmt.Sprintf("%s signatures (%v)", protection.Branch, err))
+				continue
+			}
+		}
+		applied++
+		if verboseOutput {
+			fmt.Fprintf(os.Stderr, "✅ Protection of branch '%s' re-applied\n", protection.Branch)
+		}
+	}
+
+	if applied > 0 {
+		fmt.Printf("🛡️  Re-applied protection of %d branch(es) on %s/%s\n", applied, owner, repo)
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("could not re-apply the protection of %d branch(es): %v", len(failed), failed)
+	}
+	return nil
+}
+
+// branchProtectionPayload converts a captured rule into the body of the update branch
+// protection endpoint; mapTeam returns the destination slug of a team, or false to drop it
+func branchProtectionPayload(protection branchProtection, mapTeam func(name, slug string) (string, bool)) map[string]interface{} {
+	actors := func(source *protectionActors) map[string][]string {
+		result := map[string][]string{"users": {}, "teams": {}, "apps": {}}
+		for _, user := range source.Users {
+			result["users"] = append(result["users"], user.Login)
+		}
+		for _, team := range source.Teams {
+			if slug, ok := mapTeam(team.Name, team.Slug); ok {
+				result["teams"] = append(result["teams"], slug)
+			}
+		}
+		for _, app := range source.Apps {
+			result["apps"] = append(result["apps"], app.Slug)
+		}
+		return result
+	}
+
+	payload := map[string]interface{}{
+		"required_status_checks":           nil,
+		"enforce_admins":                   protection.EnforceAdmins.Enabled,
+		"required_pull_request_reviews":    nil,
+		"restrictions":                     nil,
+		"required_linear_history":          protection.RequiredLinearHistory.Enabled,
+		"allow_force_pushes":               protection.AllowForcePushes.Enabled,
+		"allow_deletions":                  protection.AllowDeletions.Enabled,
+		"block_creations":                  protection.BlockCreations.Enabled,
+		"required_conversation_resolution": protection.RequiredConversationResolution.Enabled,
+		"lock_branch":                      protection.LockBranch.Enabled,
+		"allow_fork_syncing":               protection.AllowForkSyncing.Enabled,
+	}
+	if checks := protection.RequiredStatusChecks; checks != nil {
+		// checks supersedes contexts and keeps the app each check is expected from
+		var required []map[string]interface{}
+		for _, check := range checks.Checks {
+			entry := map[string]interface{}{"context": check.Context}
+			if check.AppID != nil {
+				entry["app_id"] = *check.AppID
+			}
+			required = append(required, entry)
+		}
+		if len(checks.Checks) == 0 {
+			for _, context := range checks.Contexts {
+				required = append(required, map[string]interface{}{"context": context})
+			}
+		}
+		if required == nil {
+			required = []map[string]interface{}{}
+		}
+		payload["required_status_checks"] = map[string]interface{}{"strict": checks.Strict, "checks": required}
+	}
+	if reviews := protection.RequiredPullRequestReviews; reviews != nil {
+		review := map[string]interface{}{
+			"dismiss_stale_reviews":           reviews.DismissStaleReviews,
+			"require_code_owner_reviews":      reviews.RequireCodeOwnerReviews,
+			"required_approving_review_count": reviews.RequiredApprovingReviewCount,
+			"require_last_push_approval":      reviews.RequireLastPushApproval,
+		}
+		if reviews.DismissalRestrictions != nil {
+			review["dismissal_restrictions"] = actors(reviews.DismissalRestrictions)
+		}
+		if reviews.BypassPullRequestAllowances != nil {
+			review["bypass_pull_request_allowances"] = actors(reviews.BypassPullRequestAllowances)
+		}
+		payload["required_pull_request_reviews"] = review
+	}
+	if protection.Restrictions != nil {
+		payload["restrictions"] = actors(protection.Restrictions)
+	}
+	return payload
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+
+	"github.com/jefeish/gh-repo-transfer/internal/checks"
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+// publishReadinessCheck creates a check run summarizing the validation on the head of the
+// repository's default branch (--publish-check). Failures only produce a warning, since the
+// Checks API is only available to GitHub App tokens.
+func publishReadinessCheck(client api.RESTClient, repository string, validation *types.MigrationValidation) {
+	if validation == nil {
+		return
+	}
+	if err := createReadinessCheck(client, repository, validation); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: Could not publish check run on %s: %v\n", repository, err)
+	}
+}
+
+func createReadinessCheck(client api.RESTClient, repository string, validation *types.MigrationValidation) error {
+	parts := strings.Split(repository, "/")
+	owner, repo := parts[0], parts[1]
+
+	branch, err := getDefaultBranch(client, owner, repo)
+	if err != nil {
+		return err
+	}
+	var commit struct {
+		SHA string `json:"sha"`
+	}
+	if err := client.Get(fmt.Sprintf("repos/%s/%s/commits/%s", owner, repo, branch), &commit); err != nil {
+		return fmt.Errorf("failed to resolve the head of %s: %v", branch, err)
+	}
+
+	payload, err := json.Marshal(checks.Build(validation, commit.SHA))
+	if err != nil {
+		return err
+	}
+	var response struct {
+		HTMLURL string `json:"html_url"`
+	}
+	if err := client.Post(fmt.Sprintf("repos/%s/%s/check-runs", owner, repo), bytes.NewBuffer(payload), &response); err != nil {
+		return err
+	}
+	if verbose {
+		fmt.Fprintf(os.Stderr, "Published migration readiness check on %s: %s\n", repository, response.HTMLURL)
+	}
+	return nil
+}
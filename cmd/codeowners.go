@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+
+	"github.com/jefeish/gh-repo-transfer/internal/dependencies"
+	"github.com/jefeish/gh-repo-transfer/internal/ghclient"
+)
+
+// codeownersBranch is the branch the CODEOWNERS rewrite is proposed from
+const codeownersBranch = "repo-transfer/rewrite-codeowners"
+
+// rewriteCodeowners proposes a pull request on the default branch of a transferred repository
+// that replaces @sourceOrg/ team references in its CODEOWNERS file with @targetOrg/, for the
+// teams that exist in the target (including those --create created). References to other
+// teams are left as they are and reported.
+func rewriteCodeowners(client api.RESTClient, sourceOrg, targetOrg, repo string, verbose bool) error {
+	repository := fmt.Sprintf("%s/%s", targetOrg, repo)
+
+	var file struct {
+		Path    string `json:"path"`
+		SHA     string `json:"sha"`
+		Content string `json:"content"`
+	}
+	found := false
+	for _, location := range dependencies.CodeownersLocations {
+		if err := client.Get(fmt.Sprintf("repos/%s/contents/%s", repository, location), &file); err == nil {
+			found = true
+			break
+		}
+	}
+	if !found {
+		if verbose {
+			fmt.Fprintf(os.Stderr, "No CODEOWNERS file in %s, nothing to rewrite\n", repository)
+		}
+		return nil
+	}
+	content, err := base64.StdEncoding.DecodeString(file.Content)
+	if err != nil {
+		return fmt.Errorf("failed to decode %s: %v", file.Path, err)
+	}
+
+	var teams []struct {
+		Slug string `json:"slug"`
+	}
+	if err := ghclient.GetAll(&client, fmt.Sprintf("orgs/%s/teams", targetOrg), &teams); err != nil {
+		return fmt.Errorf("failed to list the teams of %s: %v", targetOrg, err)
+	}
+	slugs := make(map[string]bool, len(teams))
+	for _, team := range teams {
+		slugs[strings.ToLower(team.Slug)] = true
+	}
+
+	rewritten, changed, kept := dependencies.RewriteCODEOWNERS(string(content), sourceOrg, targetOrg, func(slug string) bool {
+		return slugs[strings.ToLower(slug)]
+	})
+	for _, reference := range kept {
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: %s in %s was not rewritten: the team does not exist in %s\n", reference, file.Path, targetOrg)
+	}
+	if len(changed) == 0 {
+		if verbose {
+			fmt.Fprintf(os.Stderr, "No @%s/ team references to rewrite in %s\n", sourceOrg, file.Path)
+		}
+		return nil
+	}
+
+	url, err := proposeFileChanges(client, repository, codeownersBranch, []fileChange{{Path: file.Path, SHA: file.SHA, Content: []byte(rewritten)}},
+		fmt.Sprintf("Point CODEOWNERS at the teams of %s", targetOrg),
+		codeownersPullRequestBody(sourceOrg, targetOrg, changed, kept))
+	if err != nil {
+		return fmt.Errorf("failed to propose the CODEOWNERS rewrite: %v", err)
+	}
+	fmt.Printf("   CODEOWNERS rewrite proposed (%d teams): %s\n", len(changed), url)
+	return nil
+}
+
+// codeownersPullRequestBody describes the rewritten and the remaining team references
+func codeownersPullRequestBody(sourceOrg, targetOrg string, changed, kept []string) string {
+	var body strings.Builder
+	fmt.Fprintf(&body, "This repository moved from %s to %s. CODEOWNERS names teams with their organization, so the teams of %s no longer resolve.\n\n", sourceOrg, targetOrg, sourceOrg)
+	body.WriteString("Rewritten to the teams of the same name in the new organization:\n\n")
+	for _, reference := range changed {
+		fmt.Fprintf(&body, "- `%s` → `@%s/%s`\n", reference, targetOrg, reference[strings.Index(reference, "/")+1:])
+	}
+	if len(kept) > 0 {
+		fmt.Fprintf(&body, "\nLeft unchanged because %s has no team of that name:\n\n", targetOrg)
+		for _, reference := range kept {
+			fmt.Fprintf(&body, "- `%s`\n", reference)
+		}
+	}
+	return body.String()
+}
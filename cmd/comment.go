@@ -0,0 +1,267 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/spf13/cobra"
+
+	"github.com/jefeish/gh-repo-transfer/internal/analyzer"
+	"github.com/jefeish/gh-repo-transfer/internal/checks"
+	"github.com/jefeish/gh-repo-transfer/internal/dependencies"
+	"github.com/jefeish/gh-repo-transfer/internal/ghclient"
+	"github.com/jefeish/gh-repo-transfer/internal/history"
+	"github.com/jefeish/gh-repo-transfer/internal/teams"
+	"github.com/jefeish/gh-repo-transfer/internal/validation"
+)
+
+var (
+	commentIssue          int
+	commentAllowedTargets []string
+)
+
+// readinessCommand is the slash command asking for the readiness against an organization
+const readinessCommand = "/migration-readiness"
+
+// trustedAssociations may name the target organization in a slash command
+var trustedAssociations = map[string]bool{"OWNER": true, "MEMBER": true, "COLLABORATOR": true}
+
+// commentCmd represents the comment command
+var commentCmd = &cobra.Command{
+	Use:   "comment [owner/repo] --target-org [org] --issue [number]",
+	Short: "Post the migration readiness of a repository as a sticky issue or PR comment",
+	Long: `Analyze a repository against a target organization and post the validation summary
+as a comment on an issue or pull request. The comment is updated in place on later runs
+(one comment per target organization), so it can be requested on demand with a slash
+command from a workflow.
+
+Inside GitHub Actions the repository defaults to GITHUB_REPOSITORY and the issue or pull
+request to the one of the triggering event. Without --target-org, the organization is
+taken from a slash command comment such as "/migration-readiness new-org" by an owner,
+member or collaborator of the repository (and with --allowed-targets, only a listed one).
+
+  gh repo-transfer comment owner/repo --target-org new-org --issue 42
+  gh repo-transfer comment                      # In an issue_comment workflow`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runComment,
+}
+
+func init() {
+	rootCmd.AddCommand(commentCmd)
+	commentCmd.Flags().IntVar(&commentIssue, "issue", 0, "Issue or pull request number to comment on (defaults to the one of the triggering event)")
+	commentCmd.Flags().StringSliceVar(&commentAllowedTargets, "allowed-targets", nil, "Organizations a slash command may name as the target (default any)")
+}
+
+// workflowEvent holds the parts of a GitHub Actions event payload the comment mode uses
+type workflowEvent struct {
+	Number int `json:"number"`
+	Issue  struct {
+		Number int `json:"number"`
+	} `json:"issue"`
+	PullRequest struct {
+		Number int `json:"number"`
+	} `json:"pull_request"`
+	Comment struct {
+		Body              string `json:"body"`
+		AuthorAssociation string `json:"author_association"`
+	} `json:"comment"`
+}
+
+func runComment(cmd *cobra.Command, args []string) error {
+	repository := os.Getenv("GITHUB_REPOSITORY")
+	if len(args) == 1 {
+		repository = args[0]
+	}
+	parts := strings.Split(repository, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return fmt.Errorf("repository must be given as 'owner/repo' or through GITHUB_REPOSITORY")
+	}
+	owner, repoName := parts[0], parts[1]
+
+	event, err := loadWorkflowEvent()
+	if err != nil {
+		return err
+	}
+	issue := commentIssue
+	if issue == 0 {
+		issue = event.issueNumber()
+	}
+	if issue == 0 {
+		return fmt.Errorf("--issue is required outside an issue, issue_comment or pull_request workflow")
+	}
+	target := targetOrg
+	if target == "" {
+		if target, err = slashCommandTarget(event); err != nil {
+			return err
+		}
+	}
+	if target == "" {
+		return fmt.Errorf("--target-org is required unless the triggering comment names it, e.g. %s new-org", readinessCommand)
+	}
+
+	client, err := ghclient.NewRESTClient()
+	if err != nil {
+		return fmt.Errorf("failed to create API client: %v", err)
+	}
+	if err := validation.LoadEffortWeights(effortWeightsPath); err != nil {
+		return err
+	}
+	if err := validation.LoadSuppressions(ignoreFilePath); err != nil {
+		return err
+	}
+	validation.SetCollisionAwareness(checkCollisions)
+	dependencies.SetEnterprise(enterpriseSlug)
+	validation.SetTargetEnterprise(resolvedTargetEnterprise())
+	matchMode, err := teams.ParseMatchMode(teamMatcher)
+	if err != nil {
+		return err
+	}
+	teams.SetMatchMode(matchMode)
+	if err := loadRunState(); err != nil {
+		return err
+	}
+	defer saveRunState()
+	if err := openHistoryStore(); err != nil {
+		return err
+	}
+	defer closeHistoryStore()
+
+	deps, err := analyzer.AnalyzeOrganizationalDependencies(*client, owner, repoName, verbose)
+	if err != nil {
+		return fmt.Errorf("failed to analyze organizational dependencies for %s: %v", repository, err)
+	}
+	recordHistory(repository, history.KindAnalysis, "completed", "", nil)
+	capabilities, err := scanTargetCapabilities(*client, target)
+	if err != nil {
+		return fmt.Errorf("failed to scan target organization: %v", err)
+	}
+	deps.Validation = validateWithState(deps, capabilities, false)
+
+	url, err := upsertReadinessComment(*client, owner, repoName, issue, target, checks.Comment(repository, deps.Validation))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("💬 Migration readiness of %s (%s) posted: %s\n", repository, deps.Validation.OverallReadiness, url)
+	return nil
+}
+
+// loadWorkflowEvent reads the event payload of the running workflow, if any
+func loadWorkflowEvent() (workflowEvent, error) {
+	var event workflowEvent
+	path := os.Getenv("GITHUB_EVENT_PATH")
+	if path == "" {
+		return event, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return event, fmt.Errorf("failed to read workflow event: %v", err)
+	}
+	if err := json.Unmarshal(data, &event); err != nil {
+		return event, fmt.Errorf("failed to parse workflow event: %v", err)
+	}
+	return event, nil
+}
+
+// issueNumber returns the issue or pull request the event belongs to
+func (e workflowEvent) issueNumber() int {
+	for _, number := range []int{e.Issue.Number, e.PullRequest.Number, e.Number} {
+		if number != 0 {
+			return number
+		}
+	}
+	return 0
+}
+
+// slashCommandArgument returns the argument of the readiness command on the first line of a
+// comment, e.g. "new-org" for "/migration-readiness new-org"
+func slashCommandArgument(body string) string {
+	firstLine := strings.SplitN(strings.TrimSpace(body), "\n", 2)[0]
+	fields := strings.Fields(firstLine)
+	if len(fields) < 2 || !strings.EqualFold(fields[0], readinessCommand) {
+		return ""
+	}
+	return fields[1]
+}
+
+// slashCommandTarget returns the target organization the triggering comment asks for. Only
+// owners, members and collaborators of the repository may ask, and with --allowed-targets
+// only for a listed organization.
+func slashCommandTarget(event workflowEvent) (string, error) {
+	target := slashCommandArgument(event.Comment.Body)
+	if target == "" {
+		return "", nil
+	}
+	if !trustedAssociations[strings.ToUpper(event.Comment.AuthorAssociation)] {
+		return "", fmt.Errorf("%s is only accepted from owners, members and collaborators of the repository (the comment author is %s)",
+			readinessCommand, strings.ToLower(event.Comment.AuthorAssociation))
+	}
+	if len(commentAllowedTargets) > 0 {
+		for _, allowed := range commentAllowedTargets {
+			if strings.EqualFold(allowed, target) {
+				return target, nil
+			}
+		}
+		return "", fmt.Errorf("%s is not one of the --allowed-targets", target)
+	}
+	return target, nil
+}
+
+// commentAuthor returns the login of the authenticated user, whose comments the sticky comment
+// may be. App installation tokens cannot read it; ok is false then.
+func commentAuthor(client api.RESTClient) (login string, ok bool) {
+	var user struct {
+		Login string `json:"login"`
+	}
+	if err := client.Get("user", &user); err != nil || user.Login == "" {
+		return "", false
+	}
+	return user.Login, true
+}
+
+// upsertReadinessComment updates the sticky readiness comment for the target or creates it,
+// and returns the comment URL
+func upsertReadinessComment(client api.RESTClient, owner, repo string, issue int, target, body string) (string, error) {
+	payload, err := json.Marshal(map[string]string{"body": body})
+	if err != nil {
+		return "", err
+	}
+	var response struct {
+		HTMLURL string `json:"html_url"`
+	}
+
+	var comments []struct {
+		ID   int64  `json:"id"`
+		Body string `json:"body"`
+		User struct {
+			Login string `json:"login"`
+			Type  string `json:"type"`
+		} `json:"user"`
+	}
+	if err := ghclient.GetAll(&client, fmt.Sprintf("repos/%s/%s/issues/%d/comments", owner, repo, issue), &comments); err != nil {
+		return "", fmt.Errorf("failed to list comments of #%d: %v", issue, err)
+	}
+	// Anyone can copy the marker into a comment; only our own comments are updated. Without the
+	// login (an app token), the sticky comment is the one a bot wrote.
+	login, known := commentAuthor(client)
+	for _, comment := range comments {
+		if !checks.IsReadinessComment(comment.Body, target) {
+			continue
+		}
+		if known && !strings.EqualFold(comment.User.Login, login) || !known && comment.User.Type != "Bot" {
+			continue
+		}
+		if err := client.Patch(fmt.Sprintf("repos/%s/%s/issues/comments/%d", owner, repo, comment.ID), bytes.NewBuffer(payload), &response); err != nil {
+			return "", fmt.Errorf("failed to update readiness comment: %v", err)
+		}
+		return response.HTMLURL, nil
+	}
+
+	if err := client.Post(fmt.Sprintf("repos/%s/%s/issues/%d/comments", owner, repo, issue), bytes.NewBuffer(payload), &response); err != nil {
+		return "", fmt.Errorf("failed to create readiness comment: %v", err)
+	}
+	return response.HTMLURL, nil
+}
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jefeish/gh-repo-transfer/internal/ghclient"
+	"github.com/jefeish/gh-repo-transfer/internal/orggap"
+)
+
+var (
+	compareSourceOrg string
+	compareShowExtra bool
+)
+
+// compareCmd represents the compare command
+var compareCmd = &cobra.Command{
+	Use:   "compare --source-org source-org --target-org target-org",
+	Short: "Report what the target organization lacks compared with the source",
+	Long: `Scan the source and target organizations and report the gaps between them, independently
+of any repository: teams, installed apps, org Actions secrets, variables and runners,
+repository policies and member privileges.
+
+Use it to prepare the target organization before the first transfer; deps --target-org
+then only reports what is specific to each repository.
+
+  gh repo-transfer compare --source-org source-org --target-org target-org
+  gh repo-transfer compare --source-org source-org -t target-org --show-extra --format json`,
+	Args: cobra.NoArgs,
+	RunE: runCompare,
+}
+
+func init() {
+	rootCmd.AddCommand(compareCmd)
+	compareCmd.Flags().StringVar(&compareSourceOrg, "source-org", "", "Source organization to compare with the target")
+	compareCmd.Flags().BoolVar(&compareShowExtra, "show-extra", false, "Also list items only the target organization has")
+	compareCmd.MarkFlagRequired("source-org")
+}
+
+func runCompare(cmd *cobra.Command, args []string) error {
+	if targetOrg == "" {
+		return fmt.Errorf("--target-org is required")
+	}
+	client, err := ghclient.NewRESTClient()
+	if err != nil {
+		return fmt.Errorf("failed to create API client: %v", err)
+	}
+	if err := loadRunState(); err != nil {
+		return err
+	}
+	defer saveRunState()
+
+	source, err := scanTargetCapabilities(*client, compareSourceOrg)
+	if err != nil {
+		return fmt.Errorf("failed to scan %s: %v", compareSourceOrg, err)
+	}
+	target, err := scanTargetCapabilities(*client, targetOrg)
+	if err != nil {
+		return fmt.Errorf("failed to scan %s: %v", targetOrg, err)
+	}
+
+	report := orggap.Compare(source, target)
+	if !compareShowExtra {
+		gaps := report.Gaps[:0]
+		for _, gap := range report.Gaps {
+			if gap.Status != orggap.StatusExtra {
+				gaps = append(gaps, gap)
+			}
+		}
+		report.Gaps = gaps
+	}
+	if report.Gaps == nil {
+		report.Gaps = []orggap.Gap{}
+	}
+
+	if outputFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+	printCompareReport(report)
+	return nil
+}
+
+// printCompareReport prints the gaps grouped by category
+func printCompareReport(report orggap.Report) {
+	fmt.Printf("🔍 Comparing %s → %s\n", report.Source, report.Target)
+	if len(report.Gaps) == 0 {
+		fmt.Printf("\n✅ No gaps: %s has everything %s has (%d items)\n", report.Target, report.Source, report.Matched)
+		return
+	}
+
+	category := ""
+	for _, gap := range report.Gaps {
+		if gap.Category != category {
+			category = gap.Category
+			fmt.Printf("\n%s\n", category)
+		}
+		var icon string
+		switch gap.Status {
+		case orggap.StatusMissing:
+			icon = "❌ missing in target"
+		case orggap.StatusDifferent:
+			icon = "⚠️  different"
+		default:
+			icon = "➕ only in target"
+		}
+		line := fmt.Sprintf("  %-40s %s", gap.Item, icon)
+		if gap.Detail != "" {
+			line += fmt.Sprintf(" (%s)", gap.Detail)
+		}
+		fmt.Println(line)
+	}
+
+	fmt.Printf("\nMissing: %d, different: %d", report.Count(orggap.StatusMissing), report.Count(orggap.StatusDifferent))
+	if compareShowExtra {
+		fmt.Printf(", only in target: %d", report.Count(orggap.StatusExtra))
+	}
+	fmt.Printf(", matching: %d\n", report.Matched)
+	if report.Count(orggap.StatusMissing) > 0 {
+		fmt.Println("Create missing variables, runner groups and secrets with sync-org; teams, apps and policies need manual setup.")
+	}
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jefeish/gh-repo-transfer/internal/config"
+	"github.com/jefeish/gh-repo-transfer/internal/validation"
+)
+
+// applyConfig reads the config file (--config, else the user's and the repository-local one) and
+// uses its defaults for the flags not given on the command line. Its severity rules and
+// excluded checks apply to every validation of the run, after the rules of --severity-overrides.
+func applyConfig(cmd *cobra.Command) error {
+	settings, files, err := config.Load(configPath)
+	if err != nil {
+		return err
+	}
+	if verbose && len(files) > 0 {
+		fmt.Fprintf(os.Stderr, "Using config %s\n", strings.Join(files, ", "))
+	}
+
+	concurrencyDefault := ""
+	if settings.Concurrency > 0 {
+		concurrencyDefault = strconv.Itoa(settings.Concurrency)
+	}
+	defaults := []struct {
+		flag  string
+		value string
+	}{
+		{"target-org", settings.TargetOrg},
+		{"concurrency", concurrencyDefault},
+		{"format", settings.Format},
+	}
+	for _, setting := range defaults {
+		flag := cmd.Flags().Lookup(setting.flag)
+		if setting.value == "" || flag == nil || flag.Changed {
+			continue
+		}
+		if err := flag.Value.Set(setting.value); err != nil {
+			return fmt.Errorf("config %s: %v", setting.flag, err)
+		}
+	}
+
+	// The first matching rule applies: --severity-overrides, then the rules and overrides of the config
+	var rules []validation.SeverityRule
+	if severityOverridesPath != "" {
+		if rules, err = validation.LoadSeverityRules(severityOverridesPath); err != nil {
+			return err
+		}
+	}
+	rules = append(rules, settings.SeverityRules...)
+	rules = append(rules, validation.SeverityRulesFromMap(settings.SeverityOverrides)...)
+	if err := validation.SetSeverityRules(rules); err != nil {
+		return fmt.Errorf("severity overrides: %v", err)
+	}
+	validation.SetExcludedChecks(settings.ExcludedChecks)
+	return nil
+}
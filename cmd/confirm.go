@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/term"
+)
+
+// confirmBatch makes the operator type the target organization name before a batch of at
+// least --confirm-threshold repositories is moved, so a mistyped glob or repository list
+// cannot start a mass transfer unnoticed. --yes (or --dry-run) skips the prompt; without a
+// terminal to ask on, the batch is refused.
+func confirmBatch(operation string, count int) error {
+	if dryRun || assumeYes || confirmThreshold <= 0 || count < confirmThreshold {
+		return nil
+	}
+	if !term.IsTerminal(os.Stdin) {
+		return fmt.Errorf("refusing to %s %d repositories without confirmation (at least --confirm-threshold %d): pass --yes to confirm non-interactively", operation, count, confirmThreshold)
+	}
+
+	fmt.Fprintf(os.Stderr, "\n⚠️  About to %s %d repositories to %s.\n", operation, count, targetOrg)
+	fmt.Fprintf(os.Stderr, "Type the target organization name (%s) to continue: ", targetOrg)
+	input, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && input == "" {
+		return fmt.Errorf("confirmation aborted: %v", err)
+	}
+	if !strings.EqualFold(strings.TrimSpace(input), targetOrg) {
+		return fmt.Errorf("confirmation did not match %q, nothing was changed", targetOrg)
+	}
+	return nil
+}
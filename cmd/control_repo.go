@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+
+	"github.com/jefeish/gh-repo-transfer/internal/ghclient"
+)
+
+// maxStatusDescription is the longest description GitHub accepts on a deployment status
+const maxStatusDescription = 140
+
+// controlRepoRef is the default branch of the --control-repo, resolved once per run
+var controlRepoRef string
+
+// migrationDeployment is the deployment created on the --control-repo for one repository
+type migrationDeployment struct {
+	endpoint    string // repos/{control}/deployments/{id}
+	environment string
+}
+
+// resolveControlRepo checks that the --control-repo exists and remembers its default branch,
+// which every migration deployment points at
+func resolveControlRepo(client api.RESTClient) error {
+	if controlRepo == "" {
+		return nil
+	}
+	if parts := strings.Split(controlRepo, "/"); len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return fmt.Errorf("invalid --control-repo '%s', expected owner/repo", controlRepo)
+	}
+
+	var repository struct {
+		DefaultBranch string `json:"default_branch"`
+	}
+	if err := client.Get(fmt.Sprintf("repos/%s", controlRepo), &repository); err != nil {
+		return fmt.Errorf("cannot access control repository %s: %v", controlRepo, err)
+	}
+	controlRepoRef = repository.DefaultBranch
+	return nil
+}
+
+// migrationEnvironment names the control repository environment of a repository, grouped by wave
+func migrationEnvironment(repository string) string {
+	if migrationWave != "" {
+		return fmt.Sprintf("migration/%s/%s", migrationWave, repository)
+	}
+	return "migration/" + repository
+}
+
+// startMigrationDeployment creates a deployment for the repository on the control repository
+// and marks it in progress. It returns nil when no control repository is configured; failures
+// only produce a warning so reporting never blocks a migration.
+func startMigrationDeployment(client api.RESTClient, repository, action, target string) *migrationDeployment {
+	if controlRepo == "" {
+		return nil
+	}
+
+	environment := migrationEnvironment(repository)
+	payloadBytes, err := json.Marshal(map[string]interface{}{
+		"ref":                    controlRepoRef,
+		"task":                   "migrate:" + action,
+		"environment":            environment,
+		"description":            truncateDescription(fmt.Sprintf("%s %s to %s", action, repository, target)),
+		"auto_merge":             false,
+		"required_contexts":      []string{},
+		"production_environment": false,
+		"payload": map[string]string{
+			"repository": repository,
+			"action":     action,
+			"target":     target,
+			"wave":       migrationWave,
+		},
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: failed to marshal deployment payload: %v\n", err)
+		return nil
+	}
+
+	var response struct {
+		ID int64 `json:"id"`
+	}
+	if err := client.Post(fmt.Sprintf("repos/%s/deployments", controlRepo), bytes.NewBuffer(payloadBytes), &response); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: failed to create deployment for %s on %s: %v\n", repository, controlRepo, err)
+		return nil
+	}
+
+	deployment := &migrationDeployment{
+		endpoint:    fmt.Sprintf("repos/%s/deployments/%d", controlRepo, response.ID),
+		environment: environment,
+	}
+	deployment.setStatus(client, "in_progress", fmt.Sprintf("%s of %s started", action, repository), "")
+	return deployment
+}
+
+// reportMigrationOutcome records a migration that was decided without starting a deployment
+// first, e.g. a repository blocked by validation
+func reportMigrationOutcome(client api.RESTClient, repository, action, target string, migrationErr error) {
+	startMigrationDeployment(client, repository, action, target).finish(client, migrationErr, "")
+}
+
+// finish marks the deployment successful (pointing at the new location) or failed
+func (d *migrationDeployment) finish(client api.RESTClient, migrationErr error, newPath string) {
+	if d == nil {
+		return
+	}
+
+	if migrationErr != nil {
+		d.setStatus(client, "failure", migrationErr.Error(), "")
+		return
+	}
+
+	environmentURL := ""
+	if newPath != "" {
+		environmentURL = ghclient.WebURL(newPath)
+	}
+	d.setStatus(client, "success", "Migrated to "+newPath, environmentURL)
+}
+
+func (d *migrationDeployment) setStatus(client api.RESTClient, state, description, environmentURL string) {
+	status := map[string]interface{}{
+		"state":       state,
+		"description": truncateDescription(description),
+		"environment": d.environment,
+	}
+	if artifactURL != "" {
+		status["log_url"] = artifactURL
+	}
+	if environmentURL != "" {
+		status["environment_url"] = environmentURL
+	}
+
+	payloadBytes, err := json.Marshal(status)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: failed to marshal deployment status: %v\n", err)
+		return
+	}
+
+	var response map[string]interface{}
+	if err := client.Post(d.endpoint+"/statuses", bytes.NewBuffer(payloadBytes), &response); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: failed to set deployment status '%s' on %s: %v\n", state, controlRepo, err)
+	} else if verbose {
+		fmt.Fprintf(os.Stderr, "📡 Reported '%s' for %s on %s\n", state, d.environment, controlRepo)
+	}
+}
+
+func truncateDescription(description string) string {
+	if len(description) <= maxStatusDescription {
+		return description
+	}
+	return description[:maxStatusDescription-3] + "..."
+}
@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+
+	"github.com/jefeish/gh-repo-transfer/internal/ghclient"
+)
+
+// getDefaultBranch retrieves the current default branch of a repository
+func getDefaultBranch(client api.RESTClient, owner, repo string) (string, error) {
+	var repoInfo struct {
+		DefaultBranch string `json:"default_branch"`
+	}
+
+	err := client.Get(fmt.Sprintf("repos/%s/%s", owner, repo), &repoInfo)
+	if err != nil {
+		return "", fmt.Errorf("failed to get repository info: %v", err)
+	}
+
+	return repoInfo.DefaultBranch, nil
+}
+
+// alignDefaultBranch renames the default branch of the transferred repository to the name
+// mandated by the target organization (--default-branch). GitHub's rename API retargets open
+// pull requests and moves branch protection rules automatically; repository rulesets that
+// reference the old branch by name are updated explicitly afterwards.
+func alignDefaultBranch(client api.RESTClient, owner, repo, desiredBranch string, verboseOutput bool) error {
+	if desiredBranch == "" {
+		return nil
+	}
+
+	currentBranch, err := getDefaultBranch(client, owner, repo)
+	if err != nil {
+		return err
+	}
+
+	if currentBranch == desiredBranch {
+		if verboseOutput {
+			fmt.Fprintf(os.Stderr, "✅ Default branch already '%s', no rename needed\n", desiredBranch)
+		}
+		return nil
+	}
+
+	if verboseOutput {
+		fmt.Fprintf(os.Stderr, "Renaming default branch '%s' → '%s'...\n", currentBranch, desiredBranch)
+	}
+
+	payloadBytes, err := json.Marshal(map[string]interface{}{
+		"new_name": desiredBranch,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal branch rename payload: %v", err)
+	}
+
+	var response map[string]interface{}
+	err = client.Post(fmt.Sprintf("repos/%s/%s/branches/%s/rename", owner, repo, currentBranch), bytes.NewBuffer(payloadBytes), &response)
+	if err != nil {
+		return fmt.Errorf("failed to rename branch '%s' to '%s': %v", currentBranch, desiredBranch, err)
+	}
+
+	fmt.Printf("   Default branch renamed: %s → %s\n", currentBranch, desiredBranch)
+
+	updated, err := retargetRulesetBranchConditions(client, owner, repo, currentBranch, desiredBranch, verboseOutput)
+	if err != nil {
+		return fmt.Errorf("branch renamed but rulesets could not be updated: %v", err)
+	}
+
+	if verboseOutput {
+		fmt.Fprintf(os.Stderr, "✅ Default branch rename completed (%d rulesets updated)\n", updated)
+	}
+
+	return nil
+}
+
+// retargetRulesetBranchConditions rewrites repository ruleset ref_name conditions that
+// reference the old branch name so they keep applying after the rename.
+// Organization-level rulesets are not modified; they are reported in verbose mode only.
+func retargetRulesetBranchConditions(client api.RESTClient, owner, repo, oldBranch, newBranch string, verboseOutput bool) (int, error) {
+	var rulesets []struct {
+		ID         int    `json:"id"`
+		Name       string `json:"name"`
+		SourceType string `json:"source_type"`
+	}
+
+	err := ghclient.GetAll(&client, fmt.Sprintf("repos/%s/%s/rulesets?includes_parents=false", owner, repo), &rulesets)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list repository rulesets: %v", err)
+	}
+
+	updated := 0
+	for _, ruleset := range rulesets {
+		if ruleset.SourceType != "" && ruleset.SourceType != "Repository" {
+			if verboseOutput {
+				fmt.Fprintf(os.Stderr, "Skipping inherited ruleset '%s' (%s)\n", ruleset.Name, ruleset.SourceType)
+			}
+			continue
+		}
+
+		var detailed struct {
+			Conditions struct {
+				RefName struct {
+					Include []string `json:"include"`
+					Exclude []string `json:"exclude"`
+				} `json:"ref_name"`
+			} `json:"conditions"`
+		}
+
+		endpoint := fmt.Sprintf("repos/%s/%s/rulesets/%d", owner, repo, ruleset.ID)
+		if err := client.Get(endpoint, &detailed); err != nil {
+			return updated, fmt.Errorf("failed to get ruleset '%s': %v", ruleset.Name, err)
+		}
+
+		include, includeChanged := renameBranchRefs(detailed.Conditions.RefName.Include, oldBranch, newBranch)
+		exclude, excludeChanged := renameBranchRefs(detailed.Conditions.RefName.Exclude, oldBranch, newBranch)
+		if !includeChanged && !excludeChanged {
+			continue
+		}
+
+		payloadBytes, err := json.Marshal(map[string]interface{}{
+			"conditions": map[string]interface{}{
+				"ref_name": map[string]interface{}{
+					"include": include,
+					"exclude": exclude,
+				},
+			},
+		})
+		if err != nil {
+			return updated, fmt.Errorf("failed to marshal ruleset payload: %v", err)
+		}
+
+		var response map[string]interface{}
+		if err := client.Put(endpoint, bytes.NewBuffer(payloadBytes), &response); err != nil {
+			return updated, fmt.Errorf("failed to update ruleset '%s': %v", ruleset.Name, err)
+		}
+
+		updated++
+		if verboseOutput {
+			fmt.Fprintf(os.Stderr, "✅ Ruleset '%s' now targets '%s'\n", ruleset.Name, newBranch)
+		}
+	}
+
+	return updated, nil
+}
+
+// renameBranchRefs replaces references to oldBranch (bare or fully qualified) with newBranch
+func renameBranchRefs(refs []string, oldBranch, newBranch string) ([]string, bool) {
+	changed := false
+	result := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		switch ref {
+		case oldBranch:
+			result = append(result, newBranch)
+			changed = true
+		case "refs/heads/" + oldBranch:
+			result = append(result, "refs/heads/"+newBranch)
+			changed = true
+		default:
+			result = append(result, ref)
+		}
+	}
+	return result, changed
+}
@@ -9,8 +9,16 @@ import (
 	"github.com/spf13/cobra"
 	
 	"github.com/jefeish/gh-repo-transfer/internal/analyzer"
+	"github.com/jefeish/gh-repo-transfer/internal/anonymize"
 	"github.com/jefeish/gh-repo-transfer/internal/batch"
+	"github.com/jefeish/gh-repo-transfer/internal/billing"
+	"github.com/jefeish/gh-repo-transfer/internal/dependencies"
+	"github.com/jefeish/gh-repo-transfer/internal/fingerprint"
+	"github.com/jefeish/gh-repo-transfer/internal/ghclient"
+	"github.com/jefeish/gh-repo-transfer/internal/history"
 	"github.com/jefeish/gh-repo-transfer/internal/output"
+	"github.com/jefeish/gh-repo-transfer/internal/redirects"
+	"github.com/jefeish/gh-repo-transfer/internal/teams"
 	"github.com/jefeish/gh-repo-transfer/internal/types"
 	"github.com/jefeish/gh-repo-transfer/internal/validation"
 )
@@ -43,6 +51,10 @@ against the target organization's capabilities.`,
 var targetOrgLocal string
 var separateFilesLocal bool
 
+// validationExitCode is the exit code deps --target-org reports for the validation outcome
+// (see validation.ExitCode); Execute exits with it when the command itself succeeded
+var validationExitCode int
+
 func init() {
 	rootCmd.AddCommand(depsCmd)
 	// Flags are now defined as persistent flags in root.go
@@ -50,6 +62,10 @@ func init() {
 
 func runDepsAnalysis(cmd *cobra.Command, args []string) error {
 	var repos []string
+	args, err := repositoryArgs(args)
+	if err != nil {
+		return err
+	}
 	
 	if len(args) == 0 {
 		// Try to get repo from current directory
@@ -70,11 +86,82 @@ func runDepsAnalysis(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	client, err := api.DefaultRESTClient()
+	if localPath != "" {
+		if len(repos) != 1 {
+			return fmt.Errorf("--local-path requires exactly one repository, got %d", len(repos))
+		}
+		if err := dependencies.SetLocalClone(repos[0], localPath); err != nil {
+			return err
+		}
+	}
+
+	if subdirectoryPath != "" {
+		if len(repos) != 1 {
+			return fmt.Errorf("--path requires exactly one repository, got %d", len(repos))
+		}
+		if err := dependencies.SetSubdirectory(repos[0], subdirectoryPath); err != nil {
+			return err
+		}
+	}
+
+	client, err := ghclient.NewRESTClient()
 	if err != nil {
 		return fmt.Errorf("failed to create API client: %v", err)
 	}
 
+	if err := validation.LoadEffortWeights(effortWeightsPath); err != nil {
+		return err
+	}
+	if err := validation.LoadSuppressions(ignoreFilePath); err != nil {
+		return err
+	}
+	if err := validateConcurrency(); err != nil {
+		return err
+	}
+	if err := validateClusterSimilarity(); err != nil {
+		return err
+	}
+	validation.SetCollisionAwareness(checkCollisions)
+	dependencies.SetEnterprise(enterpriseSlug)
+	validation.SetTargetEnterprise(resolvedTargetEnterprise())
+	matchMode, err := teams.ParseMatchMode(teamMatcher)
+	if err != nil {
+		return err
+	}
+	teams.SetMatchMode(matchMode)
+	failOnOutcome, err := validation.ParseFailOn(failOn)
+	if err != nil {
+		return err
+	}
+	mode, err := dependencies.ParseDeepMode(deepAnalysis)
+	if err != nil {
+		return err
+	}
+	if deepThreshold < 0 {
+		return fmt.Errorf("--deep-threshold must not be negative, got %d", deepThreshold)
+	}
+	dependencies.SetDeepMode(mode, deepThreshold)
+	if err := loadRunState(); err != nil {
+		return err
+	}
+	defer saveRunState()
+	if err := openHistoryStore(); err != nil {
+		return err
+	}
+	defer closeHistoryStore()
+
+	if redirectMapPath != "" && targetOrg == "" {
+		return fmt.Errorf("--redirect-map requires --target-org")
+	}
+	if targetName != "" {
+		if targetOrg == "" {
+			return fmt.Errorf("--target-name requires --target-org")
+		}
+		if len(repos) != 1 {
+			return fmt.Errorf("--target-name requires exactly one repository, got %d", len(repos))
+		}
+	}
+
 	// Group repositories by organization for efficient batch processing
 	orgRepos := groupReposByOrganization(repos)
 
@@ -87,6 +174,88 @@ func runDepsAnalysis(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Scan the target up front so each repository can be validated as soon as it is analyzed
+	var capabilities *types.TargetOrgCapabilities
+	if targetOrg != "" {
+		if verbose {
+			fmt.Fprintf(os.Stderr, "Performing validation against target organization: %s\n", targetOrg)
+		}
+		
+		capabilities, err = scanTargetCapabilities(*client, targetOrg)
+		if err != nil {
+			return fmt.Errorf("failed to scan target organization: %v", err)
+		}
+	}
+
+	// Candidates are compared with the reference repository as they complete
+	var referenceFingerprint fingerprint.Fingerprint
+	if referenceRepo != "" {
+		referenceFingerprint, err = analyzeReferenceRepo(*client, referenceRepo)
+		if err != nil {
+			return err
+		}
+	}
+
+	// With --per-repo, each repository's file is written as soon as its analysis completes
+	var fileWriter *output.RepoFileWriter
+	if separateFiles {
+		existingMode, err := output.ParseExistingFileMode(existingFiles)
+		if err != nil {
+			return err
+		}
+		fileWriter, err = output.NewRepoFileWriter(output.RepoFileWriterOptions{
+			Dir:      outputDir,
+			Existing: existingMode,
+			Fsync:    fsyncFiles,
+			Verbose:  verbose,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	// Hash identifying names so the report can be shared outside the organization
+	var anonymizer *anonymize.Anonymizer
+	if anonymizeReports {
+		if anonymizer, err = anonymize.New(); err != nil {
+			return err
+		}
+	}
+
+	var redirectMap []redirects.Redirect
+
+	completeRepository := func(deps *types.OrganizationalDependencies) error {
+		recordHistory(deps.Repository, history.KindAnalysis, "completed", "", nil)
+		if redirectMapPath != "" {
+			redirectMap = append(redirectMap, collectRedirects(*client, deps.Repository)...)
+		}
+		if capabilities != nil {
+			deps.Validation = validateForPlan(deps, capabilities, false)
+			if publishCheck {
+				publishReadinessCheck(*client, deps.Repository, deps.Validation)
+			}
+			if billingImpact {
+				estimateBillingImpact(*client, deps)
+			}
+			if targetName != "" {
+				parts := strings.SplitN(deps.Repository, "/", 2)
+				deps.WhatIf = dependencies.AnalyzeWhatIf(*client, parts[0], parts[1], targetOrg, targetName)
+			}
+		}
+		if referenceFingerprint != nil && !strings.EqualFold(deps.Repository, referenceRepo) {
+			deps.ReferenceComparison = fingerprint.Compare(fingerprint.Of(deps), referenceFingerprint, referenceRepo)
+		}
+		if fileWriter == nil {
+			return nil
+		}
+		if anonymizer != nil {
+			anonymizer.CollectDependencies(deps)
+			anonymizer.Apply(deps)
+		}
+		fileWriter.Write(deps)
+		return nil
+	}
+
 	// Process repositories with batch optimization when multiple repos from same org
 	var allDeps []*types.OrganizationalDependencies
 	
@@ -100,6 +269,9 @@ func runDepsAnalysis(cmd *cobra.Command, args []string) error {
 			if err != nil {
 				return fmt.Errorf("failed to analyze organizational dependencies for %s: %v", orgRepoList[0], err)
 			}
+			if err := completeRepository(deps); err != nil {
+				return err
+			}
 			allDeps = append(allDeps, deps)
 		} else {
 			// Multiple repositories from same organization - use batch analysis
@@ -109,10 +281,27 @@ func runDepsAnalysis(cmd *cobra.Command, args []string) error {
 			}
 			
 			batchAnalyzer := batch.NewBatchAnalyzer(*client, verbose)
+			batchAnalyzer.SetConcurrency(concurrency)
+			if useGraphQL {
+				graphQLClient, err := ghclient.NewGraphQLClient()
+				if err != nil {
+					return fmt.Errorf("failed to create GraphQL client: %v", err)
+				}
+				batchAnalyzer.UseGraphQL(ghclient.ProfiledGraphQL(graphQLClient, orgName, "graphql-scan"))
+			}
+			var completeErr error
+			batchAnalyzer.OnResult(func(result batch.BatchAnalysisResult) {
+				if result.Error == nil && completeErr == nil {
+					completeErr = completeRepository(result.Result)
+				}
+			})
 			orgResults, err := batchAnalyzer.AnalyzeRepositories(orgRepoList)
 			if err != nil {
 				return fmt.Errorf("failed to batch analyze repositories for organization %s: %v", orgName, err)
 			}
+			if completeErr != nil {
+				return completeErr
+			}
 			
 			// Convert BatchAnalysisResult to OrganizationalDependencies
 			for _, result := range orgResults {
@@ -124,27 +313,50 @@ func runDepsAnalysis(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// If target organization is specified, perform validation for all repositories
-	if targetOrg != "" {
-		if verbose {
-			fmt.Fprintf(os.Stderr, "Performing validation against target organization: %s\n", targetOrg)
+	if redirectMapPath != "" {
+		if err := redirects.WriteFile(redirectMapPath, redirectMap); err != nil {
+			return err
 		}
-		
-		capabilities, err := validation.ScanTargetOrganization(*client, targetOrg, verbose)
-		if err != nil {
-			return fmt.Errorf("failed to scan target organization: %v", err)
+		fmt.Fprintf(os.Stderr, "🔀 Wrote %d redirect(s) to %s\n", len(redirectMap), redirectMapPath)
+	}
+
+	// Group repositories with similar dependencies into migration waves
+	if clusterRepos {
+		clusters := fingerprint.AssignClusters(allDeps, clusterSimilarity)
+		if fileWriter != nil {
+			fileWriter.SetClusters(clusters)
 		}
-		
+	}
+
+	// CI gates read the outcome from the exit code
+	if capabilities != nil {
+		var validations []*types.MigrationValidation
 		for _, deps := range allDeps {
-			deps.Validation = validation.ValidateAgainstTarget(deps, capabilities, false)
+			validations = append(validations, deps.Validation)
+		}
+		validationExitCode = validation.ExitCode(validations, failOnOutcome)
+		if verbose && validationExitCode != validation.ExitReady {
+			fmt.Fprintf(os.Stderr, "Validation outcome exits with code %d\n", validationExitCode)
 		}
 	}
 
 	// Output results
-	if separateFiles {
-		// Output each repository to separate JSON files
-		return output.OutputSeparateFiles(allDeps, verbose)
-	} else if len(allDeps) == 1 {
+	if fileWriter != nil {
+		err := fileWriter.Close()
+		output.PrintSeparateFilesSummary(fileWriter.Count(), verbose)
+		return err
+	}
+
+	if anonymizer != nil {
+		for _, deps := range allDeps {
+			anonymizer.CollectDependencies(deps)
+		}
+		for _, deps := range allDeps {
+			anonymizer.Apply(deps)
+		}
+	}
+
+	if len(allDeps) == 1 {
 		// Single repository output
 		return output.OutputDependencies(allDeps[0], outputFormat)
 	} else {
@@ -153,6 +365,49 @@ func runDepsAnalysis(cmd *cobra.Command, args []string) error {
 	}
 }
 
+// estimateBillingImpact attaches the seat and committer estimate for the target org to deps
+func estimateBillingImpact(client api.RESTClient, deps *types.OrganizationalDependencies) {
+	parts := strings.Split(deps.Repository, "/")
+	impact, err := billing.Estimate(client, parts[0], parts[1], targetOrg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: could not estimate billing impact for %s: %v\n", deps.Repository, err)
+		return
+	}
+	deps.BillingImpact = impact
+}
+
+// validateClusterSimilarity checks that --cluster-similarity is a valid similarity
+func validateClusterSimilarity() error {
+	if clusterSimilarity < 0 || clusterSimilarity > 1 {
+		return fmt.Errorf("--cluster-similarity must be between 0 and 1, got %v", clusterSimilarity)
+	}
+	return nil
+}
+
+// validateConcurrency checks that --concurrency allows at least one worker
+func validateConcurrency() error {
+	if concurrency < 1 {
+		return fmt.Errorf("--concurrency must be at least 1, got %d", concurrency)
+	}
+	return nil
+}
+
+// analyzeReferenceRepo analyzes the --reference-repo and returns its dependency fingerprint
+func analyzeReferenceRepo(client api.RESTClient, repository string) (fingerprint.Fingerprint, error) {
+	parts := strings.Split(repository, "/")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("reference repository '%s' must be in format 'owner/repo'", repository)
+	}
+	if verbose {
+		fmt.Fprintf(os.Stderr, "Analyzing reference repository: %s\n", repository)
+	}
+	deps, err := analyzer.AnalyzeOrganizationalDependencies(client, parts[0], parts[1], verbose)
+	if err != nil {
+		return nil, fmt.Errorf("failed to analyze reference repository %s: %v", repository, err)
+	}
+	return fingerprint.Of(deps), nil
+}
+
 // groupReposByOrganization groups repositories by their organization for batch processing
 func groupReposByOrganization(repos []string) map[string][]string {
 	orgRepos := make(map[string][]string)
@@ -171,7 +426,7 @@ func groupReposByOrganization(repos []string) map[string][]string {
 }
 
 func getCurrentRepo() (string, error) {
-	client, err := api.DefaultRESTClient()
+	client, err := ghclient.NewRESTClient()
 	if err != nil {
 		return "", err
 	}
@@ -188,4 +443,20 @@ func getCurrentRepo() (string, error) {
 	}
 
 	return response.FullName, nil
-}
\ No newline at end of file
+}
+
+// collectRedirects builds the redirect map entries of a repository moving to --target-org under
+// its current name; a repository whose releases or Pages site cannot be read is skipped
+func collectRedirects(client api.RESTClient, repository string) []redirects.Redirect {
+	parts := strings.SplitN(repository, "/", 2)
+	newName := parts[1]
+	if targetName != "" {
+		newName = targetName
+	}
+	entries, err := redirects.Collect(client, parts[0], parts[1], fmt.Sprintf("%s/%s", targetOrg, newName))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: No redirects for %s: %v\n", repository, err)
+		return nil
+	}
+	return entries
+}
@@ -0,0 +1,344 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+
+	"github.com/jefeish/gh-repo-transfer/internal/dependencies"
+	"github.com/jefeish/gh-repo-transfer/internal/ghclient"
+)
+
+// environmentBranchPolicy captures an environment's deployment branch policy before the move
+type environmentBranchPolicy struct {
+	Environment          string
+	ProtectedBranches    bool
+	CustomBranchPolicies bool
+	Patterns             []deploymentBranchPattern
+}
+
+// deploymentBranchPattern is a single branch or tag name pattern allowed to deploy
+type deploymentBranchPattern struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// captureEnvironmentBranchPolicies reads the deployment branch policies of all environments
+func captureEnvironmentBranchPolicies(client api.RESTClient, owner, repo string) ([]environmentBranchPolicy, error) {
+	var response struct {
+		Environments []struct {
+			Name                   string `json:"name"`
+			DeploymentBranchPolicy *struct {
+				ProtectedBranches    bool `json:"protected_branches"`
+				CustomBranchPolicies bool `json:"custom_branch_policies"`
+			} `json:"deployment_branch_policy"`
+		} `json:"environments"`
+	}
+
+	err := ghclient.GetAllField(&client, fmt.Sprintf("repos/%s/%s/environments", owner, repo), "environments", &response.Environments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list environments: %v", err)
+	}
+
+	var policies []environmentBranchPolicy
+	for _, env := range response.Environments {
+		if env.DeploymentBranchPolicy == nil {
+			continue // Any branch can deploy, nothing to carry over
+		}
+
+		policy := environmentBranchPolicy{
+			Environment:          env.Name,
+			ProtectedBranches:    env.DeploymentBranchPolicy.ProtectedBranches,
+			CustomBranchPolicies: env.DeploymentBranchPolicy.CustomBranchPolicies,
+		}
+
+		if policy.CustomBranchPolicies {
+			var branchPolicies struct {
+				BranchPolicies []deploymentBranchPattern `json:"branch_policies"`
+			}
+			endpoint := fmt.Sprintf("repos/%s/%s/environments/%s/deployment-branch-policies", owner, repo, url.PathEscape(env.Name))
+			if err := ghclient.GetAllField(&client, endpoint, "branch_policies", &branchPolicies.BranchPolicies); err != nil {
+				return nil, fmt.Errorf("failed to get deployment branch policies for environment '%s': %v", env.Name, err)
+			}
+			policy.Patterns = branchPolicies.BranchPolicies
+		}
+
+		policies = append(policies, policy)
+	}
+
+	return policies, nil
+}
+
+// reapplyEnvironmentBranchPolicies recreates captured deployment branch policies in the destination.
+// Environments that are missing are created; patterns that already exist are left untouched.
+func reapplyEnvironmentBranchPolicies(client api.RESTClient, owner, repo string, policies []environmentBranchPolicy, verboseOutput bool) error {
+	if len(policies) == 0 {
+		return nil
+	}
+
+	if verboseOutput {
+		fmt.Fprintf(os.Stderr, "Re-applying deployment branch policies for %d environments...\n", len(policies))
+	}
+
+	var failures int
+	for _, policy := range policies {
+		if err := reapplyEnvironmentBranchPolicy(client, owner, repo, policy); err != nil {
+			failures++
+			fmt.Fprintf(os.Stderr, "⚠️  Warning: Environment '%s': %v\n", policy.Environment, err)
+			continue
+		}
+
+		if verboseOutput {
+			fmt.Fprintf(os.Stderr, "✅ Environment '%s' deployment branch policy applied\n", policy.Environment)
+		}
+	}
+
+	if failures > 0 {
+		return fmt.Errorf("%d of %d environment branch policies could not be re-applied", failures, len(policies))
+	}
+	return nil
+}
+
+func reapplyEnvironmentBranchPolicy(client api.RESTClient, owner, repo string, policy environmentBranchPolicy) error {
+	envEndpoint := fmt.Sprintf("repos/%s/%s/environments/%s", owner, repo, url.PathEscape(policy.Environment))
+
+	payloadBytes, err := json.Marshal(map[string]interface{}{
+		"deployment_branch_policy": map[string]bool{
+			"protected_branches":     policy.ProtectedBranches,
+			"custom_branch_policies": policy.CustomBranchPolicies,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal environment payload: %v", err)
+	}
+
+	var envResponse map[string]interface{}
+	if err := client.Put(envEndpoint, bytes.NewBuffer(payloadBytes), &envResponse); err != nil {
+		return fmt.Errorf("failed to update environment: %v", err)
+	}
+
+	if !policy.CustomBranchPolicies {
+		return nil
+	}
+
+	var existing struct {
+		BranchPolicies []deploymentBranchPattern `json:"branch_policies"`
+	}
+	if err := ghclient.GetAllField(&client, envEndpoint+"/deployment-branch-policies", "branch_policies", &existing.BranchPolicies); err != nil {
+		return fmt.Errorf("failed to read destination branch policies: %v", err)
+	}
+
+	present := make(map[deploymentBranchPattern]bool)
+	for _, pattern := range existing.BranchPolicies {
+		present[pattern] = true
+	}
+
+	for _, pattern := range policy.Patterns {
+		if present[pattern] {
+			continue
+		}
+
+		patternBytes, err := json.Marshal(pattern)
+		if err != nil {
+			return fmt.Errorf("failed to marshal branch policy payload: %v", err)
+		}
+
+		var patternResponse map[string]interface{}
+		if err := client.Post(envEndpoint+"/deployment-branch-policies", bytes.NewBuffer(patternBytes), &patternResponse); err != nil {
+			return fmt.Errorf("failed to create branch policy '%s': %v", pattern.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// environmentConfig captures an environment's protection rules, variables and secret names for
+// --migrate-environments. Deployment branch policies are carried over by
+// captureEnvironmentBranchPolicies for every move.
+type environmentConfig struct {
+	Name                   string
+	WaitTimer              int
+	PreventSelfReview      bool
+	Reviewers              []environmentReviewer
+	DeploymentBranchPolicy map[string]bool // nil: any branch can deploy
+	Variables              []actionsVariable
+	Secrets                []string
+}
+
+// environmentReviewer is a required reviewer. User IDs stay valid after the move; teams are
+// looked up by slug in the destination organization.
+type environmentReviewer struct {
+	Type  string // User or Team
+	ID    int64
+	Login string
+	Slug  string
+}
+
+// captureEnvironments reads the protection rules, variables and secret names of all environments
+func captureEnvironments(client api.RESTClient, owner, repo string) ([]environmentConfig, error) {
+	var environments []struct {
+		Name                   string                                   `json:"name"`
+		DeploymentBranchPolicy map[string]bool                          `json:"deployment_branch_policy"`
+		ProtectionRules        []dependencies.EnvironmentProtectionRule `json:"protection_rules"`
+	}
+	if err := ghclient.GetAllField(&client, fmt.Sprintf("repos/%s/%s/environments", owner, repo), "environments", &environments); err != nil {
+		return nil, fmt.Errorf("failed to list environments: %v", err)
+	}
+
+	var configs []environmentConfig
+	for _, env := range environments {
+		config := environmentConfig{Name: env.Name, DeploymentBranchPolicy: env.DeploymentBranchPolicy}
+		for _, rule := range env.ProtectionRules {
+			switch rule.Type {
+			case "wait_timer":
+				config.WaitTimer = rule.WaitTimer
+			case "required_reviewers":
+				config.PreventSelfReview = rule.PreventSelfReview
+				for _, reviewer := range rule.Reviewers {
+					config.Reviewers = append(config.Reviewers, environmentReviewer{
+						Type:  reviewer.Type,
+						ID:    reviewer.Reviewer.ID,
+						Login: reviewer.Reviewer.Login,
+						Slug:  reviewer.Reviewer.Slug,
+					})
+				}
+			}
+		}
+
+		envPath := fmt.Sprintf("repos/%s/%s/environments/%s", owner, repo, url.PathEscape(env.Name))
+		if err := ghclient.GetAllField(&client, envPath+"/variables", "variables", &config.Variables); err != nil {
+			return nil, fmt.Errorf("failed to list variables of environment '%s': %v", env.Name, err)
+		}
+		secrets, err := environmentSecretNames(client, envPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list secrets of environment '%s': %v", env.Name, err)
+		}
+		config.Secrets = secrets
+		configs = append(configs, config)
+	}
+	return configs, nil
+}
+
+func environmentSecretNames(client api.RESTClient, envPath string) ([]string, error) {
+	var secrets []struct {
+		Name string `json:"name"`
+	}
+	if err := ghclient.GetAllField(&client, envPath+"/secrets", "secrets", &secrets); err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, secret := range secrets {
+		names = append(names, secret.Name)
+	}
+	return names, nil
+}
+
+// recreateEnvironments applies the captured environments to the moved repository: protection
+// rules are set, missing variables are created with their values and missing secrets are
+// reported (or, with --prompt-secrets, asked for). Reviewer teams missing in the destination
+// organization are left out with a warning.
+func recreateEnvironments(client api.RESTClient, owner, repo string, configs []environmentConfig, verboseOutput bool) error {
+	var failed []string
+	recreated := 0
+	for _, config := range configs {
+		if err := recreateEnvironment(client, owner, repo, config, &failed); err != nil {
+			failed = append(failed, fmt.Sprintf("environment %s (%v)", config.Name, err))
+			continue
+		}
+		recreated++
+		if verboseOutput {
+			fmt.Fprintf(os.Stderr, "✅ Environment '%s' recreated\n", config.Name)
+		}
+	}
+	if recreated > 0 {
+		fmt.Printf("🌍 Recreated %d environment(s) on %s/%s\n", recreated, owner, repo)
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("could not recreate %d environment item(s): %v", len(failed), failed)
+	}
+	return nil
+}
+
+func recreateEnvironment(client api.RESTClient, owner, repo string, config environmentConfig, failed *[]string) error {
+	envPath := fmt.Sprintf("repos/%s/%s/environments/%s", owner, repo, url.PathEscape(config.Name))
+
+	var reviewers []map[string]interface{}
+	for _, reviewer := range config.Reviewers {
+		if reviewer.Type != "Team" {
+			reviewers = append(reviewers, map[string]interface{}{"type": reviewer.Type, "id": reviewer.ID})
+			continue
+		}
+		var team struct {
+			ID int64 `json:"id"`
+		}
+		if err := client.Get(fmt.Sprintf("orgs/%s/teams/%s", owner, reviewer.Slug), &team); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Warning: Reviewer team '%s' of environment '%s' does not exist in %s; the environment loses this reviewer\n", reviewer.Slug, config.Name, owner)
+			continue
+		}
+		reviewers = append(reviewers, map[string]interface{}{"type": "Team", "id": team.ID})
+	}
+
+	payload := map[string]interface{}{
+		"wait_timer":               config.WaitTimer,
+		"deployment_branch_policy": config.DeploymentBranchPolicy,
+	}
+	if len(reviewers) > 0 {
+		payload["reviewers"] = reviewers
+		payload["prevent_self_review"] = config.PreventSelfReview
+	}
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	var response map[string]interface{}
+	if err := client.Put(envPath, bytes.NewBuffer(payloadBytes), &response); err != nil {
+		return fmt.Errorf("failed to update environment: %v", err)
+	}
+
+	var present []actionsVariable
+	if err := ghclient.GetAllField(&client, envPath+"/variables", "variables", &present); err != nil {
+		return fmt.Errorf("failed to list variables: %v", err)
+	}
+	presentVariables := make(map[string]bool)
+	for _, variable := range present {
+		presentVariables[strings.ToUpper(variable.Name)] = true
+	}
+	for _, variable := range config.Variables {
+		if presentVariables[strings.ToUpper(variable.Name)] {
+			continue
+		}
+		variableBytes, err := json.Marshal(variable)
+		if err != nil {
+			return err
+		}
+		if err := client.Post(envPath+"/variables", bytes.NewBuffer(variableBytes), &response); err != nil {
+			*failed = append(*failed, fmt.Sprintf("variable %s of %s (%v)", variable.Name, config.Name, err))
+		}
+	}
+
+	presentSecrets, err := environmentSecretNames(client, envPath)
+	if err != nil {
+		return fmt.Errorf("failed to list secrets: %v", err)
+	}
+	existing := make(map[string]bool)
+	for _, secret := range presentSecrets {
+		existing[strings.ToUpper(secret)] = true
+	}
+	var missing []string
+	for _, secret := range config.Secrets {
+		if !existing[strings.ToUpper(secret)] {
+			missing = append(missing, secret)
+		}
+	}
+	missing = promptMissingSecrets(owner, repo, config.Name, missing, failed)
+	if len(missing) > 0 {
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: Environment '%s' of %s/%s is missing %d secret(s) that must be set again: %s\n", config.Name, owner, repo, len(missing), strings.Join(missing, ", "))
+		fmt.Fprintf(os.Stderr, "   Set them with 'gh secret set <name> --env %s --repo %s', or re-run with --prompt-secrets on a terminal.\n", config.Name, ghclient.RepoArg(owner, repo))
+	}
+	return nil
+}
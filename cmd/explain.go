@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jefeish/gh-repo-transfer/internal/explain"
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+var (
+	knowledgeBasePath string
+	explainList       bool
+)
+
+// explainCmd represents the explain command
+var explainCmd = &cobra.Command{
+	Use:   "explain <finding-kind>",
+	Short: "Explain why a validation finding has its status and how to remediate it",
+	Long: `Print why a validation status is assigned to a finding, which API data informed it
+and the remediation steps. Every validation result carries a finding kind next to its ID
+(shown in the table output and as "kind" in JSON), e.g. ci.create_secret; findings of the
+same kind share their explanation.
+
+The explanations come from a built-in knowledge base. Add or override entries with
+--knowledge-base, pointing to a YAML file or a directory of YAML files in the same format.
+
+  gh repo-transfer explain ci.create_secret
+  gh repo-transfer explain --list
+  gh repo-transfer explain governance.org_policy --knowledge-base kb/ --format json`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if explainList {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
+	RunE: runExplain,
+}
+
+func init() {
+	rootCmd.AddCommand(explainCmd)
+	explainCmd.Flags().StringVar(&knowledgeBasePath, "knowledge-base", "", "YAML file or directory of YAML files adding to or overriding the built-in explanations")
+	explainCmd.Flags().BoolVar(&explainList, "list", false, "List the known finding kinds")
+}
+
+func runExplain(cmd *cobra.Command, args []string) error {
+	kb, err := explain.Load(knowledgeBasePath)
+	if err != nil {
+		return err
+	}
+
+	if explainList {
+		for _, id := range kb.IDs() {
+			entry, _ := kb.Lookup(id)
+			fmt.Printf("%-28s %s\n", id, entry.Title)
+		}
+		return nil
+	}
+
+	entry, ok := kb.Lookup(args[0])
+	if !ok {
+		return fmt.Errorf("unknown finding kind '%s' (known: %s)", args[0], strings.Join(kb.Suggestions(args[0]), ", "))
+	}
+
+	if outputFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(entry)
+	}
+	printExplanation(entry)
+	return nil
+}
+
+// printExplanation prints an entry with its statuses in the order of increasing severity
+func printExplanation(entry explain.Entry) {
+	fmt.Printf("🔎 %s: %s\n\n", entry.ID, entry.Title)
+	fmt.Printf("%s\n", entry.Summary)
+
+	if len(entry.Statuses) > 0 {
+		fmt.Printf("\nWhy each status is assigned\n")
+		for _, status := range []types.ValidationStatus{
+			types.ValidationReady, types.ValidationSetupNeeded, types.ValidationReview,
+			types.ValidationWarning, types.ValidationBlocker, types.ValidationUnknown,
+		} {
+			if why, ok := entry.Statuses[string(status)]; ok {
+				fmt.Printf("  %-13s %s\n", status, why)
+			}
+		}
+	}
+
+	if len(entry.Data) > 0 {
+		fmt.Printf("\nData used\n")
+		for _, data := range entry.Data {
+			fmt.Printf("  • %s\n", data)
+		}
+	}
+
+	if len(entry.Remediation) > 0 {
+		fmt.Printf("\nRemediation\n")
+		for i, step := range entry.Remediation {
+			fmt.Printf("  %d. %s\n", i+1, step)
+		}
+	}
+
+	if entry.Source != "built-in" {
+		fmt.Printf("\n(from %s)\n", entry.Source)
+	}
+}
@@ -0,0 +1,216 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/spf13/cobra"
+
+	"github.com/jefeish/gh-repo-transfer/internal/ghclient"
+	"github.com/jefeish/gh-repo-transfer/internal/history"
+	"github.com/jefeish/gh-repo-transfer/internal/state"
+	"github.com/jefeish/gh-repo-transfer/pkg/utils"
+)
+
+// pendingArchiveTopic tags repositories transferred to the archive org that are not read-only yet
+const pendingArchiveTopic = "archive-pending"
+
+// archiveSoak is the parsed --archive-after soak period (0 archives immediately)
+var archiveSoak time.Duration
+
+// finalizeCmd sets the archived flag of repositories whose --archive-after soak period has passed
+var finalizeCmd = &cobra.Command{
+	Use:   "finalize [owner/repo...] --state-file state.json",
+	Short: "Set the archived flag on repositories whose --archive-after soak period has passed",
+	Long: `Finish two-phase archives started with 'archive --archive-after'.
+
+The archive command transfers and renames the repository right away but leaves it writable
+for the soak period, tags it '` + pendingArchiveTopic + `' and records it in the state file.
+finalize marks every repository whose soak period has passed as archived (read-only),
+removes the tag and forgets the pending archive. Repositories still in their soak period
+are listed with the time left.
+
+Run it by hand or on a schedule (e.g. a daily workflow) with the same --state-file.
+Arguments limit it to the given archived repositories.
+
+  gh repo-transfer finalize --state-file migration-state.json --dry-run
+  gh repo-transfer finalize archive-org/app-2JKLX9A7 --state-file migration-state.json`,
+	SilenceUsage: true,
+	RunE:         runFinalize,
+}
+
+func init() {
+	rootCmd.AddCommand(finalizeCmd)
+}
+
+// finalizeResult is the outcome for one pending archive
+type finalizeResult struct {
+	Repository   string    `json:"repository"`
+	OriginalPath string    `json:"original_path"`
+	ArchiveAfter time.Time `json:"archive_after"`
+	Status       string    `json:"status"` // waiting, would_archive, archived or failed
+	Error        string    `json:"error,omitempty"`
+}
+
+// parseArchiveAfter validates --archive-after; a soak period needs the state file to remember
+// the pending archive
+func parseArchiveAfter() error {
+	archiveSoak = 0
+	if archiveAfter == "" {
+		return nil
+	}
+	soak, err := utils.ParseDuration(archiveAfter)
+	if err != nil {
+		return fmt.Errorf("--archive-after: %v", err)
+	}
+	if soak > 0 && stateFilePath == "" {
+		return fmt.Errorf("--archive-after requires --state-file to record the pending archive for 'finalize'")
+	}
+	archiveSoak = soak
+	return nil
+}
+
+// readOnlyNote describes when an archived repository becomes read-only
+func readOnlyNote() string {
+	if archiveSoak > 0 {
+		return fmt.Sprintf("read-only after a %s soak period, via 'finalize'", archiveAfter)
+	}
+	return "read-only"
+}
+
+func runFinalize(cmd *cobra.Command, args []string) error {
+	if stateFilePath == "" {
+		return fmt.Errorf("--state-file is required to find the pending archives")
+	}
+
+	client, err := ghclient.NewRESTClient()
+	if err != nil {
+		return fmt.Errorf("failed to create API client: %v", err)
+	}
+
+	if err := loadRunState(); err != nil {
+		return err
+	}
+	defer saveRunState()
+	if err := openHistoryStore(); err != nil {
+		return err
+	}
+	defer closeHistoryStore()
+
+	selected := make(map[string]bool, len(args))
+	for _, repository := range args {
+		selected[strings.ToLower(repository)] = true
+	}
+
+	now := time.Now().UTC()
+	var results []finalizeResult
+	failed := 0
+	for _, pending := range runState.PendingArchiveList() {
+		if len(selected) > 0 && !selected[strings.ToLower(pending.Repository)] {
+			continue
+		}
+		result := finalizeResult{Repository: pending.Repository, OriginalPath: pending.OriginalPath, ArchiveAfter: pending.ArchiveAfter}
+		switch {
+		case !pending.Due(now):
+			result.Status = "waiting"
+		case dryRun:
+			result.Status = "would_archive"
+		default:
+			if err := finalizeArchive(*client, pending); err != nil {
+				result.Status = "failed"
+				result.Error = err.Error()
+				failed++
+			} else {
+				result.Status = "archived"
+			}
+		}
+		results = append(results, result)
+	}
+
+	if outputFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(results); err != nil {
+			return err
+		}
+	} else {
+		printFinalizeResults(results, now)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d pending archive(s) could not be finalized", failed)
+	}
+	return nil
+}
+
+// finalizeArchive sets the archived flag and clears the pending archive tag and record
+func finalizeArchive(client api.RESTClient, pending state.PendingArchive) error {
+	parts := strings.SplitN(pending.Repository, "/", 2)
+	if len(parts) != 2 {
+		return fmt.Errorf("invalid pending archive repository %q", pending.Repository)
+	}
+	owner, repo := parts[0], parts[1]
+
+	// Topics cannot be changed once the repository is read-only
+	if topics, err := getRepositoryTopics(client, owner, repo); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: Could not remove the %s topic from %s: %v\n", pendingArchiveTopic, pending.Repository, err)
+	} else {
+		var kept []string
+		for _, topic := range topics {
+			if topic != pendingArchiveTopic {
+				kept = append(kept, topic)
+			}
+		}
+		if len(kept) != len(topics) {
+			if err := setRepositoryTopics(client, owner, repo, kept); err != nil {
+				fmt.Fprintf(os.Stderr, "⚠️  Warning: Could not remove the %s topic from %s: %v\n", pendingArchiveTopic, pending.Repository, err)
+			}
+		}
+	}
+
+	if err := setRepositoryArchiveStatus(client, owner, repo, true, verbose); err != nil {
+		recordHistory(pending.OriginalPath, history.KindArchive, "failed", owner, map[string]string{"error": err.Error(), "archived_name": repo})
+		return err
+	}
+	runState.RemovePendingArchive(pending.Repository)
+	recordHistory(pending.OriginalPath, history.KindArchive, "finalized", owner, map[string]string{"archived_name": repo})
+	return nil
+}
+
+// printFinalizeResults lists the pending archives and what happened to them
+func printFinalizeResults(results []finalizeResult, now time.Time) {
+	if dryRun {
+		fmt.Printf("🔍 DRY RUN: Finalizing pending archives\n")
+	} else {
+		fmt.Printf("🗃️ Finalizing pending archives\n")
+	}
+	fmt.Printf("═════════════════════════════════════════\n")
+	if len(results) == 0 {
+		fmt.Printf("No pending archives in %s\n", stateFilePath)
+		return
+	}
+
+	counts := make(map[string]int)
+	for _, result := range results {
+		counts[result.Status]++
+		switch result.Status {
+		case "waiting":
+			left := result.ArchiveAfter.Sub(now).Round(time.Minute)
+			fmt.Printf("%-50s ⏳ WAITING (soak ends %s, in %s)\n", result.Repository, result.ArchiveAfter.Format(time.RFC3339), left)
+		case "would_archive":
+			fmt.Printf("%-50s ✅ WOULD ARCHIVE\n", result.Repository)
+		case "archived":
+			fmt.Printf("%-50s ✅ ARCHIVED (read-only)\n", result.Repository)
+		default:
+			fmt.Printf("%-50s ❌ FAILED\n", result.Repository)
+			fmt.Printf("  └─ ❌ %s\n", result.Error)
+		}
+	}
+
+	fmt.Printf("\nArchived: %d, would archive: %d, waiting: %d, failed: %d\n",
+		counts["archived"], counts["would_archive"], counts["waiting"], counts["failed"])
+}
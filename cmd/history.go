@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jefeish/gh-repo-transfer/internal/history"
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+// historyCmd represents the history command
+var historyCmd = &cobra.Command{
+	Use:   "history [owner/repo] --db [file]",
+	Short: "Show recorded analyses, validations, transfers and verifications",
+	Long: `Query the migration history recorded in the SQLite database given with --db.
+
+Every deps, transfer and archive run started with --db records its analyses,
+validations, transfers/archives and settings verifications with timestamps.
+
+  gh repo-transfer history --db migrations.db
+  gh repo-transfer history --db migrations.db --kind transfer --since 720h
+  gh repo-transfer history --db migrations.db owner/repo --format json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runHistory,
+}
+
+var (
+	historyKind  string
+	historySince time.Duration
+	historyLimit int
+)
+
+// historyStore is the database opened from --db for the current run (nil when unset)
+var historyStore *history.Store
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+	historyCmd.Flags().StringVar(&historyKind, "kind", "", "Only show events of this kind (analysis, validation, transfer, archive, verification, rename, tombstone)")
+	historyCmd.Flags().DurationVar(&historySince, "since", 0, "Only show events newer than this, e.g. 168h")
+	historyCmd.Flags().IntVar(&historyLimit, "limit", 0, "Only show the most recent N events")
+}
+
+// openHistoryStore opens the database named by --db
+func openHistoryStore() error {
+	var err error
+	historyStore, err = history.Open(historyDBPath)
+	if historyStore != nil {
+		watchInterrupts()
+	}
+	return err
+}
+
+// closeHistoryStore closes the history database
+func closeHistoryStore() {
+	store := historyStore
+	historyStore = nil
+	if err := store.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: %v\n", err)
+	}
+	stopWatchingInterrupts()
+}
+
+// recordHistory stores an event in the history database; failures only produce a warning
+func recordHistory(repository, kind, status, target string, details interface{}) {
+	if historyStore == nil {
+		return
+	}
+
+	event := history.Event{Repository: repository, Kind: kind, Status: status, Target: target}
+	if details != nil {
+		if data, err := json.Marshal(details); err == nil {
+			event.Details = string(data)
+		}
+	}
+	if err := historyStore.Record(event); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: %v\n", err)
+	}
+}
+
+// recordValidationHistory stores the outcome of a validation
+func recordValidationHistory(repository string, validation *types.MigrationValidation) {
+	if validation == nil {
+		return
+	}
+	recordHistory(repository, history.KindValidation, string(validation.OverallReadiness), validation.TargetOrganization, validation.Summary)
+}
+
+func runHistory(cmd *cobra.Command, args []string) error {
+	if historyDBPath == "" {
+		return fmt.Errorf("--db is required to query the migration history")
+	}
+	if _, err := os.Stat(historyDBPath); err != nil {
+		return fmt.Errorf("cannot open history database: %v", err)
+	}
+
+	if err := openHistoryStore(); err != nil {
+		return err
+	}
+	defer closeHistoryStore()
+
+	filter := history.Filter{Kind: historyKind, Limit: historyLimit}
+	if len(args) == 1 {
+		filter.Repository = args[0]
+	}
+	if historySince > 0 {
+		filter.Since = time.Now().Add(-historySince)
+	}
+
+	events, err := historyStore.Events(filter)
+	if err != nil {
+		return err
+	}
+
+	if outputFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(events)
+	}
+
+	if len(events) == 0 {
+		fmt.Printf("No recorded events\n")
+		return nil
+	}
+
+	fmt.Printf("📜 Migration History (%d events)\n", len(events))
+	fmt.Printf("═══════════════════════════════════════════════\n")
+	for _, event := range events {
+		target := ""
+		if event.Target != "" {
+			target = " → " + event.Target
+		}
+		fmt.Printf("%s  %-40s %-12s %s%s\n", event.Timestamp.Local().Format("2006-01-02 15:04:05"), event.Repository, event.Kind, event.Status, target)
+		// A single repository's timeline also shows what was recorded with each event
+		if filter.Repository != "" && event.Details != "" && event.Details != "null" {
+			fmt.Printf("                     %s\n", event.Details)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/jefeish/gh-repo-transfer/internal/ghclient"
+)
+
+// applyHostname points the API clients at --hostname (GH_HOST or gh's host without it) and
+// drops a HOST/ prefix from --target-org. A target on another host than the source is an error,
+// since repositories cannot be transferred between hosts.
+func applyHostname() error {
+	if hostname != "" {
+		ghclient.SetHost(hostname)
+	}
+	if targetOrg != "" {
+		org, err := ghclient.SplitHost(targetOrg, 1)
+		if err != nil {
+			return err
+		}
+		targetOrg = org
+	}
+	if verbose && !ghclient.IsGitHubCom() {
+		fmt.Fprintf(os.Stderr, "Using GitHub host %s\n", ghclient.Host())
+	}
+	return nil
+}
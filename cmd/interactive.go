@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/term"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+// reviewChoice is what the operator decided for a repository under --interactive
+type reviewChoice int
+
+const (
+	reviewProceed reviewChoice = iota // Move the repository, enforcing it past its blockers
+	reviewSkip                        // Leave the repository where it is
+	reviewAbort                       // Stop the batch before anything is moved
+)
+
+// reviewer asks the operator about each repository with findings, one repository at a time
+type reviewer struct {
+	input *bufio.Reader
+}
+
+// newReviewer returns a reviewer reading from the terminal, or nil without --interactive.
+// --interactive without a terminal to ask on is refused rather than silently moving on.
+func newReviewer() (*reviewer, error) {
+	if !interactive {
+		return nil, nil
+	}
+	if !term.IsTerminal(os.Stdin) {
+		return nil, fmt.Errorf("--interactive needs a terminal; use --enforce or an ignore file (--ignore-file) for unattended runs")
+	}
+	return &reviewer{input: bufio.NewReader(os.Stdin)}, nil
+}
+
+// reviewFindings lists the blockers, warnings and other open findings of a repository and asks
+// whether to enforce (blocked repositories) or proceed (others), skip it or abort the batch.
+// blockedBy is why the repository is blocked, nil when it is not. Repositories without
+// findings are not asked about.
+func (r *reviewer) reviewFindings(operation, repository string, validation *types.MigrationValidation, blockedBy error) (reviewChoice, error) {
+	findings := reviewableFindings(validation)
+	if len(findings) == 0 && blockedBy == nil {
+		return reviewProceed, nil
+	}
+
+	fmt.Fprintf(os.Stderr, "\n🔎 Review %s\n", repository)
+	if blockedBy != nil {
+		// The first line of the error says why, the blocker details follow below
+		fmt.Fprintf(os.Stderr, "   %s\n", strings.SplitN(blockedBy.Error(), "\n", 2)[0])
+	}
+	for _, finding := range findings {
+		fmt.Fprintf(os.Stderr, "   %s\n", finding)
+	}
+
+	question := fmt.Sprintf("Proceed with the %s of %s? [p]roceed, [s]kip, [a]bort: ", operation, repository)
+	answers := map[string]reviewChoice{"p": reviewProceed, "proceed": reviewProceed}
+	if blockedBy != nil {
+		question = fmt.Sprintf("%s is blocked. [e]nforce, [s]kip, [a]bort: ", repository)
+		answers = map[string]reviewChoice{"e": reviewProceed, "enforce": reviewProceed}
+	}
+	answers["s"], answers["skip"] = reviewSkip, reviewSkip
+	answers["a"], answers["abort"] = reviewAbort, reviewAbort
+
+	for {
+		fmt.Fprint(os.Stderr, question)
+		answer, err := r.input.ReadString('\n')
+		if err != nil && answer == "" {
+			return reviewAbort, fmt.Errorf("review aborted: %v", err)
+		}
+		if choice, ok := answers[strings.ToLower(strings.TrimSpace(answer))]; ok {
+			return choice, nil
+		}
+	}
+}
+
+// reviewableFindings formats the findings of a validation that are not ready, most severe first
+func reviewableFindings(validation *types.MigrationValidation) []string {
+	if validation == nil {
+		return nil
+	}
+	categories := []struct {
+		name    string
+		results []types.ValidationResult
+	}{
+		{"Code Dependencies", validation.CodeDependencies},
+		{"CI/CD Dependencies", validation.CIDependencies},
+		{"Access Permissions", validation.AccessPermissions},
+		{"Security Compliance", validation.SecurityCompliance},
+		{"Apps & Integrations", validation.AppsIntegrations},
+		{"Governance", validation.Governance},
+	}
+	statuses := []struct {
+		status types.ValidationStatus
+		marker string
+	}{
+		{types.ValidationBlocker, "❌"},
+		{types.ValidationWarning, "⚠️ "},
+		{types.ValidationSetupNeeded, "🔧"},
+		{types.ValidationReview, "👀"},
+		{types.ValidationUnknown, "❓"},
+	}
+
+	var findings []string
+	for _, status := range statuses {
+		for _, category := range categories {
+			for _, result := range category.results {
+				if result.Status != status.status {
+					continue
+				}
+				finding := fmt.Sprintf("%s [%s] %s: %s", status.marker, category.name, result.Item, result.Message)
+				if result.Recommendation != "" {
+					finding += fmt.Sprintf(" → %s", result.Recommendation)
+				}
+				findings = append(findings, finding)
+			}
+		}
+	}
+	return findings
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+
+	"github.com/jefeish/gh-repo-transfer/internal/journal"
+	"github.com/jefeish/gh-repo-transfer/internal/steps"
+)
+
+// runJournal records how far each repository of the current transfer or archive batch got
+// (nil during a dry run without --resume)
+var runJournal *journal.Journal
+
+// startRunJournal opens the journal of a batch operation and returns the repositories to
+// process. Without --resume a new journal is started for repos. With --resume the journal of
+// the previous run is continued: without repos its unfinished repositories are taken, and
+// repositories it records as completed are skipped.
+func startRunJournal(operation string, repos []string) ([]string, error) {
+	var err error
+	if !resumeRun {
+		if dryRun {
+			return repos, nil
+		}
+		if previous, err := journal.Open(journalDir, operation, targetOrg); err == nil {
+			if unfinished := previous.Unfinished(); len(unfinished) > 0 {
+				fmt.Fprintf(os.Stderr, "⚠️  Warning: replacing %s, in which %d repositories did not finish (use --resume to continue it)\n", previous.FilePath(), len(unfinished))
+			}
+		}
+		runJournal, err = journal.Create(journalDir, operation, targetOrg, repos)
+		return repos, err
+	}
+
+	runJournal, err = journal.Open(journalDir, operation, targetOrg)
+	if err != nil {
+		return nil, err
+	}
+	if dryRun {
+		runJournal.ReadOnly()
+	}
+	if len(repos) == 0 {
+		repos = runJournal.Unfinished()
+	} else if err := runJournal.Add(repos...); err != nil {
+		return nil, err
+	}
+
+	var pending []string
+	for _, repo := range repos {
+		entry, _ := runJournal.Entry(repo)
+		if entry.Phase == journal.PhaseCompleted {
+			fmt.Fprintf(os.Stderr, "⏭️  %s: completed by an earlier run, skipping\n", repo)
+			continue
+		}
+		if entry.Transferred() {
+			fmt.Fprintf(os.Stderr, "↪️  %s: resuming after phase '%s' (moved to %s)\n", repo, entry.Phase, entry.Target)
+		}
+		pending = append(pending, repo)
+	}
+	if len(pending) == 0 {
+		fmt.Printf("✅ Nothing to resume: every repository in %s has completed\n", runJournal.FilePath())
+	}
+	return pending, nil
+}
+
+// recordJournal reports a failure to update the journal as a warning; the repository
+// operations themselves are not affected
+func recordJournal(err error) {
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: %v\n", err)
+	}
+}
+
+// resumedEntry returns the journal entry of a repository an earlier run already transferred
+func resumedEntry(repository string) (journal.Entry, bool) {
+	entry, ok := runJournal.Entry(repository)
+	return entry, ok && entry.Transferred()
+}
+
+// restoreCaptured reads what a capture step of an earlier run recorded in the journal into v.
+// A capture step that completed without recording it leaves v unset, with a warning.
+func restoreCaptured(entry journal.Entry, step string, v interface{}) error {
+	found, err := entry.LoadCaptured(step, v)
+	if err != nil || found {
+		return err
+	}
+	for _, completed := range entry.Steps {
+		if completed == step {
+			fmt.Fprintf(os.Stderr, "⚠️  Warning: %s: the journal does not hold what %s read before the transfer; it is not restored\n", entry.Repository, step)
+			break
+		}
+	}
+	return nil
+}
+
+// movedRepository is the repository at its new path, looked up when resuming after the transfer
+type movedRepository struct {
+	ID         int    `json:"id"`
+	FullName   string `json:"full_name"`
+	Visibility string `json:"visibility"`
+}
+
+// getMovedRepository looks up a repository an earlier run transferred to target ("owner/name")
+func getMovedRepository(client api.RESTClient, target string) (movedRepository, error) {
+	var moved movedRepository
+	if err := client.Get(fmt.Sprintf("repos/%s", target), &moved); err != nil {
+		return moved, fmt.Errorf("could not find %s transferred by an earlier run: %v", target, err)
+	}
+	return moved, nil
+}
+
+// withoutCompletedSteps skips the steps the journal records as completed for a repository, for
+// dry-run output of a resumed batch
+func withoutCompletedSteps(repository string, operation []steps.Step) []steps.Step {
+	completed := runJournal.CompletedSteps(repository)
+	for i := range operation {
+		if completed[operation[i].Name] {
+			operation[i].Skip = true
+		}
+	}
+	return operation
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+
+	"github.com/jefeish/gh-repo-transfer/internal/policy"
+)
+
+// loadedPolicy holds the policy loaded from --policy-file (or the default policy) for the current run
+var loadedPolicy *policy.Policy
+
+// checkLegalHold refuses to move a repository that carries a legal hold marker of the policy.
+// It is checked before validation and is not bypassed by --enforce.
+func checkLegalHold(client api.RESTClient, owner, repo string) error {
+	if loadedPolicy == nil || !loadedPolicy.LegalHold.Enabled() {
+		return nil
+	}
+
+	var properties map[string][]string
+	if len(loadedPolicy.LegalHold.Properties) > 0 {
+		var err error
+		properties, err = getRepositoryPropertyValues(client, owner, repo)
+		// Repositories owned by users have no custom properties
+		if err != nil && !strings.Contains(err.Error(), "404") {
+			return fmt.Errorf("could not check legal hold: %v", err)
+		}
+	}
+	var topics []string
+	if len(loadedPolicy.LegalHold.Topics) > 0 {
+		var err error
+		topics, err = getRepositoryTopics(client, owner, repo)
+		if err != nil {
+			return fmt.Errorf("could not check legal hold: %v", err)
+		}
+	}
+
+	if reasons := loadedPolicy.LegalHold.Reasons(properties, topics); len(reasons) > 0 {
+		return fmt.Errorf("repository %s/%s is under legal hold (%s) and cannot be moved, even with --enforce",
+			owner, repo, strings.Join(reasons, ", "))
+	}
+	return nil
+}
+
+// getRepositoryPropertyValues reads the custom property values of a repository; single values
+// are returned as one-element lists
+func getRepositoryPropertyValues(client api.RESTClient, owner, repo string) (map[string][]string, error) {
+	var response []struct {
+		PropertyName string          `json:"property_name"`
+		Value        json.RawMessage `json:"value"`
+	}
+	if err := client.Get(fmt.Sprintf("repos/%s/%s/properties/values", owner, repo), &response); err != nil {
+		return nil, fmt.Errorf("failed to get custom property values: %v", err)
+	}
+
+	values := make(map[string][]string, len(response))
+	for _, property := range response {
+		// Unset properties have a null value
+		if string(property.Value) == "null" {
+			continue
+		}
+		var single string
+		if err := json.Unmarshal(property.Value, &single); err == nil {
+			values[property.PropertyName] = []string{single}
+			continue
+		}
+		var multiple []string
+		if err := json.Unmarshal(property.Value, &multiple); err == nil {
+			values[property.PropertyName] = multiple
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+
+	"github.com/jefeish/gh-repo-transfer/internal/steps"
+)
+
+// runOperationSteps runs the steps of a transfer or archive; failed optional steps are
+// reported as warnings and the operation continues. Each completed step is recorded in the
+// journal, and steps an earlier run completed after the transfer are skipped (see --resume);
+// steps restoring source data the journal does not keep fail then.
+func runOperationSteps(repository string, operation []steps.Step) ([]steps.Result, error) {
+	completed := runJournal.CompletedSteps(repository)
+	results, err := steps.Run(operation, steps.Options{
+		Completed: completed,
+		OnWarning: func(step string, err error) {
+			fmt.Fprintf(os.Stderr, "⚠️  Warning: %v\n", err)
+			recordJournal(runJournal.StepFailed(repository, step, err))
+		},
+		OnComplete: func(step string) {
+			recordJournal(runJournal.CompleteStep(repository, step))
+			if verbose {
+				fmt.Fprintf(os.Stderr, "✓ %s: %s\n", repository, step)
+			}
+		},
+	})
+	if err != nil {
+		rolledBack := false
+		for _, result := range results {
+			if result.Status == steps.StatusRolledBack {
+				rolledBack = true
+				fmt.Fprintf(os.Stderr, "↩️  %s: rolled back %s\n", repository, result.Step)
+			}
+		}
+		if entry, ok := runJournal.Entry(repository); ok && rolledBack {
+			// The repository is back at its source, so a resumed run starts it over
+			recordJournal(runJournal.Validated(repository, entry.Target, err))
+		} else {
+			recordJournal(runJournal.Fail(repository, err))
+		}
+		return results, err
+	}
+	recordJournal(runJournal.Complete(repository))
+	return results, nil
+}
+
+// formatStepPlan lists the steps an operation would run, for dry-run output
+func formatStepPlan(operation []steps.Step) string {
+	var names []string
+	for _, step := range steps.Plan(operation) {
+		names = append(names, step.Name)
+	}
+	return strings.Join(names, " → ")
+}
+
+// transferRepositoryBack moves a repository back to its original owner and name; it is the
+// rollback of a transfer when a later critical step fails
+func transferRepositoryBack(client api.RESTClient, owner, repo, originalOwner, originalName string) error {
+	payload, err := json.Marshal(map[string]interface{}{
+		"new_owner": originalOwner,
+		"new_name":  originalName,
+	})
+	if err != nil {
+		return err
+	}
+	var response map[string]interface{}
+	return client.Post(fmt.Sprintf("repos/%s/%s/transfer", owner, repo), bytes.NewBuffer(payload), &response)
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/term"
+	"github.com/spf13/cobra"
+
+	"github.com/jefeish/gh-repo-transfer/internal/sinks"
+)
+
+// reportExtensions names the captured report by --format
+var reportExtensions = map[string]string{
+	"json":     ".json",
+	"yaml":     ".yaml",
+	"xlsx":     ".xlsx",
+	"junit":    ".xml",
+	"markdown": ".md",
+	"html":     ".html",
+	"csv":      ".csv",
+}
+
+// outputCapture tees stdout into a buffer while --output-target is set
+type outputCapture struct {
+	sink     sinks.Sink
+	original *os.File
+	writer   *os.File
+	buffer   bytes.Buffer
+	done     chan struct{}
+}
+
+// activeCapture is the capture of the running command, nil without --output-target
+var activeCapture *outputCapture
+
+// startOutputTarget opens the --output-target and starts capturing stdout, so the report can be
+// stored next to the state files once the command finished
+func startOutputTarget() error {
+	if outputTarget == "" {
+		return nil
+	}
+	sink, err := sinks.Open(outputTarget, sinks.RunPrefix(time.Now()))
+	if err != nil {
+		return err
+	}
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return fmt.Errorf("failed to capture the output: %v", err)
+	}
+
+	capture := &outputCapture{sink: sink, original: os.Stdout, writer: writer, done: make(chan struct{})}
+	var destination io.Writer = io.MultiWriter(os.Stdout, &capture.buffer)
+	if outputFormat == "xlsx" && term.IsTerminal(os.Stdout) {
+		destination = &capture.buffer // The workbook only goes to the output target
+	}
+	go func() {
+		io.Copy(destination, reader)
+		reader.Close()
+		close(capture.done)
+	}()
+	os.Stdout = writer
+	activeCapture = capture
+	return nil
+}
+
+// finishOutputTarget stops capturing stdout and stores the report, state file, redirect map,
+// per-repository files, journal, history database and plan of the run in the --output-target
+func finishOutputTarget(cmd *cobra.Command) error {
+	capture := activeCapture
+	if capture == nil {
+		return nil
+	}
+	activeCapture = nil
+	capture.writer.Close()
+	<-capture.done
+	os.Stdout = capture.original
+
+	stored := 0
+	put := func(count int, err error) error {
+		stored += count
+		return err
+	}
+	var err error
+	if capture.buffer.Len() > 0 {
+		extension, ok := reportExtensions[outputFormat]
+		if !ok {
+			extension = ".txt"
+		}
+		err = put(1, capture.sink.Put("report"+extension, capture.buffer.Bytes()))
+	}
+	files := []string{stateFilePath, redirectMapPath, historyDBPath}
+	if cmd != nil && cmd.Name() == "plan" {
+		files = append(files, planOutputPath)
+	}
+	for _, file := range files {
+		if err == nil && file != "" {
+			err = put(sinks.PutFile(capture.sink, filepath.Base(file), file))
+		}
+	}
+	if err == nil && separateFiles {
+		err = put(sinks.PutDir(capture.sink, "per-repo", outputDir))
+	}
+	if err == nil && journalDir != "" {
+		err = put(sinks.PutDir(capture.sink, "journal", journalDir))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to store the run in %s: %v", capture.sink.Location(), err)
+	}
+
+	fmt.Fprintf(os.Stderr, "📦 Stored %d file(s) of this run in %s\n", stored, capture.sink.Location())
+	return nil
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+
+	"github.com/jefeish/gh-repo-transfer/internal/dependencies"
+)
+
+// restorePages enables GitHub Pages on the moved repository with the captured build source,
+// then sets the custom domain and HTTPS enforcement. A site that is already enabled only gets
+// its custom domain and HTTPS setting aligned, so running it twice is harmless.
+func restorePages(client api.RESTClient, owner, repo string, site *dependencies.PagesSite, verboseOutput bool) error {
+	if site == nil {
+		return nil
+	}
+	current, err := dependencies.ReadPages(client, owner, repo)
+	if err != nil {
+		return err
+	}
+
+	if current == nil {
+		create := map[string]interface{}{"build_type": site.BuildType}
+		if site.BuildType != "workflow" {
+			create["source"] = map[string]string{"branch": site.Branch, "path": site.Path}
+		}
+		payload, err := json.Marshal(create)
+		if err != nil {
+			return err
+		}
+		var response map[string]interface{}
+		if err := client.Post(fmt.Sprintf("repos/%s/%s/pages", owner, repo), bytes.NewBuffer(payload), &response); err != nil {
+			return fmt.Errorf("failed to enable Pages on %s/%s: %v", owner, repo, err)
+		}
+		fmt.Printf("📄 Enabled GitHub Pages on %s/%s\n", owner, repo)
+	} else if verboseOutput {
+		fmt.Fprintf(os.Stderr, "GitHub Pages is already enabled on %s/%s\n", owner, repo)
+	}
+
+	if current != nil && current.CNAME == site.CNAME && current.HTTPSEnforced == site.HTTPSEnforced {
+		return nil
+	}
+	update := map[string]interface{}{"cname": nil}
+	if site.CNAME != "" {
+		update["cname"] = site.CNAME
+	}
+	payload, err := json.Marshal(update)
+	if err != nil {
+		return err
+	}
+	if err := client.Put(fmt.Sprintf("repos/%s/%s/pages", owner, repo), bytes.NewBuffer(payload), nil); err != nil {
+		return fmt.Errorf("failed to set the Pages custom domain of %s/%s: %v", owner, repo, err)
+	}
+	if site.HTTPSEnforced {
+		payload, _ := json.Marshal(map[string]bool{"https_enforced": true})
+		if err := client.Put(fmt.Sprintf("repos/%s/%s/pages", owner, repo), bytes.NewBuffer(payload), nil); err != nil {
+			// The certificate of a custom domain is issued after DNS points at the new owner
+			fmt.Fprintf(os.Stderr, "⚠️  Warning: Could not enforce HTTPS for the Pages site of %s/%s yet: %v; enable it in the repository settings once the certificate is issued\n", owner, repo, err)
+		}
+	}
+	if site.CNAME != "" {
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: Point the DNS record of %s at the new owner's github.io domain for the Pages site of %s/%s\n", site.CNAME, owner, repo)
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+// standardPermissions are the base repository roles available in every organization
+var standardPermissions = map[string]bool{"pull": true, "triage": true, "push": true, "maintain": true, "admin": true}
+
+// permissionChange is a team whose permission on the moved repository differs from the source
+type permissionChange struct {
+	Team    string `json:"team"`
+	Source  string `json:"source"`
+	Applied string `json:"applied"` // "none" when the team has no access at all
+	Reason  string `json:"reason,omitempty"`
+}
+
+var (
+	// targetCustomRoles caches the custom repository roles of each target org
+	targetCustomRoles      = make(map[string]map[string]bool)
+	targetCustomRolesMutex sync.Mutex
+)
+
+// normalizePermission maps the role names used by the UI to the names returned by the API
+func normalizePermission(permission string) string {
+	switch strings.ToLower(permission) {
+	case "read":
+		return "pull"
+	case "write":
+		return "push"
+	}
+	return strings.ToLower(permission)
+}
+
+// planPermissionChanges predicts which source team permissions cannot be applied unchanged in the
+// target org: custom roles the target org does not define cannot be assigned, so those teams
+// keep whatever access the transfer gave them
+func planPermissionChanges(client api.RESTClient, org string, sourceTeams []types.Team) []permissionChange {
+	var changes []permissionChange
+	for _, team := range sourceTeams {
+		permission := normalizePermission(team.Permission)
+		if standardPermissions[permission] {
+			continue
+		}
+		roles, err := customRepositoryRoles(client, org)
+		if err != nil {
+			if verbose {
+				fmt.Fprintf(os.Stderr, "Warning: Could not list custom repository roles of %s: %v\n", org, err)
+			}
+			return changes
+		}
+		if !roles[permission] {
+			changes = append(changes, permissionChange{
+				Team:    team.Name,
+				Source:  team.Permission,
+				Applied: "none",
+				Reason:  fmt.Sprintf("custom role '%s' is not defined in %s", team.Permission, org),
+			})
+		}
+	}
+	return changes
+}
+
+// assignmentPermissionChanges lists the verified assignments whose effective permission differs
+// from the source permission
+func assignmentPermissionChanges(assignments []teamAssignment) []permissionChange {
+	var changes []permissionChange
+	for _, assignment := range assignments {
+		source := assignment.Team.Permission
+		if normalizePermission(assignment.Applied) == normalizePermission(source) {
+			continue
+		}
+		reason := "upgraded"
+		if permissionRank(assignment.Applied) < permissionRank(source) {
+			reason = "downgraded"
+		}
+		if assignment.Err != nil {
+			reason += fmt.Sprintf(" after %d attempts: %v", assignment.Attempts, assignment.Err)
+		}
+		changes = append(changes, permissionChange{Team: assignment.Team.Name, Source: source, Applied: assignment.Applied, Reason: reason})
+	}
+	return changes
+}
+
+// permissionDrifts turns permission changes into entries of the settings drift report
+func permissionDrifts(changes []permissionChange) []settingDrift {
+	var drifts []settingDrift
+	for _, change := range changes {
+		drifts = append(drifts, settingDrift{
+			Setting:  fmt.Sprintf("team %s permission", change.Team),
+			Before:   change.Source,
+			After:    change.Applied,
+			Expected: allowPermissionChange,
+		})
+	}
+	return drifts
+}
+
+// permissionRank orders the base roles; custom roles rank between push and maintain
+func permissionRank(permission string) int {
+	switch normalizePermission(permission) {
+	case "none":
+		return 0
+	case "pull":
+		return 1
+	case "triage":
+		return 2
+	case "push":
+		return 3
+	case "maintain":
+		return 5
+	case "admin":
+		return 6
+	}
+	return 4
+}
+
+// checkPermissionChanges prints the permission changes of a repository and returns an error
+// unless --allow-permission-change accepts them
+func checkPermissionChanges(repository string, changes []permissionChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	fmt.Printf("🔐 Team permission changes: %s\n%s\n", repository, formatPermissionChanges(changes))
+
+	if allowPermissionChange {
+		return nil
+	}
+	return fmt.Errorf("%d team permission(s) differ from the source (use --allow-permission-change to accept)", len(changes))
+}
+
+// formatPermissionChanges lists permission changes one per line as "team: source → applied (reason)"
+func formatPermissionChanges(changes []permissionChange) string {
+	var lines []string
+	for _, change := range changes {
+		line := fmt.Sprintf("     • %s: %s → %s", change.Team, change.Source, change.Applied)
+		if change.Reason != "" {
+			line += fmt.Sprintf(" (%s)", change.Reason)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func customRepositoryRoles(client api.RESTClient, org string) (map[string]bool, error) {
+	key := strings.ToLower(org)
+	targetCustomRolesMutex.Lock()
+	defer targetCustomRolesMutex.Unlock()
+
+	if roles, ok := targetCustomRoles[key]; ok {
+		return roles, nil
+	}
+
+	var response struct {
+		CustomRoles []struct {
+			Name string `json:"name"`
+		} `json:"custom_roles"`
+	}
+	if err := client.Get(fmt.Sprintf("orgs/%s/custom-repository-roles", org), &response); err != nil {
+		return nil, err
+	}
+
+	roles := make(map[string]bool)
+	for _, role := range response.CustomRoles {
+		roles[strings.ToLower(role.Name)] = true
+	}
+	targetCustomRoles[key] = roles
+	return roles, nil
+}
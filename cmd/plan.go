@@ -0,0 +1,316 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/spf13/cobra"
+
+	"github.com/jefeish/gh-repo-transfer/internal/analyzer"
+	"github.com/jefeish/gh-repo-transfer/internal/dependencies"
+	"github.com/jefeish/gh-repo-transfer/internal/ghclient"
+	"github.com/jefeish/gh-repo-transfer/internal/history"
+	"github.com/jefeish/gh-repo-transfer/internal/origin"
+	"github.com/jefeish/gh-repo-transfer/internal/plan"
+	"github.com/jefeish/gh-repo-transfer/internal/policy"
+	"github.com/jefeish/gh-repo-transfer/internal/steps"
+	"github.com/jefeish/gh-repo-transfer/internal/teams"
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+	"github.com/jefeish/gh-repo-transfer/internal/validation"
+)
+
+var (
+	planOutputPath string
+	planArchive    bool
+	planStagingOrg string
+)
+
+// planCmd writes a reviewable migration plan that 'apply' executes later
+var planCmd = &cobra.Command{
+	Use:   "plan [owner/repo...] --target-org X -o plan.json",
+	Short: "Write a reviewable migration plan for 'apply'",
+	Long: `Analyze and validate repositories and write everything a transfer (or, with --archive,
+an archive) would do to a JSON plan file: the dependencies, the validation against the
+target organization and the steps that would run per repository. Nothing is changed.
+
+Commit the plan to a pull request so the migration can be reviewed, then run it with
+'apply'. Apply uses the target organization and options recorded in the plan and aborts
+a repository whose validation regressed since the plan was made (unless --allow-drift).
+
+  gh repo-transfer plan owner/repo1 owner/repo2 --target-org new-org --assign -o plan.json
+  gh repo-transfer plan owner/repo --target-org archive-org --archive -o archive-plan.json
+  gh repo-transfer apply plan.json
+
+With --via, the transfer is planned in two hops through a staging (quarantine) org, each
+validated separately: 'apply' moves the repositories into the staging org and 'promote'
+moves approved ones on to the target org.
+
+  gh repo-transfer plan owner/repo --target-org final-org --via quarantine-org -o plan.json`,
+	SilenceUsage: true,
+	RunE:         runPlan,
+}
+
+func init() {
+	rootCmd.AddCommand(planCmd)
+	planCmd.Flags().StringVarP(&planOutputPath, "output", "o", "plan.json", "Plan file to write")
+	planCmd.Flags().BoolVar(&planArchive, "archive", false, "Plan an archive instead of a transfer")
+	planCmd.Flags().StringVar(&planStagingOrg, "via", "", "Staging org the transfer passes through for review; 'promote' runs the second hop")
+	planCmd.MarkFlagRequired("target-org")
+}
+
+func runPlan(cmd *cobra.Command, args []string) error {
+	repos, err := repositoryArgs(args)
+	if err != nil {
+		return err
+	}
+	if len(repos) == 0 {
+		currentRepo, err := getCurrentRepo()
+		if err != nil {
+			return fmt.Errorf("no repository specified and could not determine current repository: %v", err)
+		}
+		repos = []string{currentRepo}
+	}
+	for _, repo := range repos {
+		parts := strings.Split(repo, "/")
+		if len(parts) != 2 {
+			return fmt.Errorf("repository '%s' must be in format 'owner/repo'", repo)
+		}
+	}
+	if planStagingOrg != "" && (planArchive || strings.EqualFold(planStagingOrg, targetOrg)) {
+		return fmt.Errorf("--via needs a transfer plan and a staging org other than --target-org")
+	}
+
+	client, err := ghclient.NewRESTClient()
+	if err != nil {
+		return fmt.Errorf("failed to create API client: %v", err)
+	}
+
+	// Same setup as transfer/archive so the plan shows exactly what they would do
+	loadedSettingsProfile, err = loadSettingsProfile(settingsProfilePath)
+	if err != nil {
+		return err
+	}
+	loadedPolicy, err = policy.Load(policyFilePath)
+	if err != nil {
+		return err
+	}
+	if planArchive {
+		if err := parseArchiveAfter(); err != nil {
+			return err
+		}
+	}
+	if err := validation.LoadEffortWeights(effortWeightsPath); err != nil {
+		return err
+	}
+	if err := validation.LoadSuppressions(ignoreFilePath); err != nil {
+		return err
+	}
+	validation.SetCollisionAwareness(checkCollisions)
+	dependencies.SetEnterprise(enterpriseSlug)
+	validation.SetTargetEnterprise(resolvedTargetEnterprise())
+	matchMode, err := teams.ParseMatchMode(teamMatcher)
+	if err != nil {
+		return err
+	}
+	teams.SetMatchMode(matchMode)
+	if _, err := origin.Strategies(originTracking); err != nil {
+		return err
+	}
+	if err := loadRunState(); err != nil {
+		return err
+	}
+	defer saveRunState()
+	if err := openHistoryStore(); err != nil {
+		return err
+	}
+	defer closeHistoryStore()
+
+	if err := validateTargetOwner(*client, targetOrg); err != nil {
+		return fmt.Errorf("failed to validate target owner: %v", err)
+	}
+	capabilities, err := scanTargetCapabilities(*client, targetOrg)
+	if err != nil {
+		return fmt.Errorf("failed to scan target organization: %v", err)
+	}
+	var stagingCapabilities *types.TargetOrgCapabilities
+	if planStagingOrg != "" {
+		if err := validateTargetOwner(*client, planStagingOrg); err != nil {
+			return fmt.Errorf("failed to validate staging owner: %v", err)
+		}
+		stagingCapabilities, err = scanTargetCapabilities(*client, planStagingOrg)
+		if err != nil {
+			return fmt.Errorf("failed to scan staging organization: %v", err)
+		}
+	}
+
+	migrationPlan := &plan.Plan{
+		CreatedAt:  time.Now().UTC(),
+		ScannedAt:  capabilities.ScannedAt,
+		Operation:  plan.OperationTransfer,
+		TargetOrg:  targetOrg,
+		StagingOrg: planStagingOrg,
+		Options: plan.Options{
+			Assign:                  assign,
+			CreateTeams:             createTeams,
+			AddTopics:               addTopics,
+			RemoveTopics:            removeTopics,
+			DefaultBranch:           defaultBranch,
+			SettingsProfile:         settingsProfilePath,
+			Verify:                  verifySettings,
+			Announce:                announce,
+			CleanupSource:           cleanupSource,
+			CreateTombstone:         createTombstone,
+			RewriteCodeowners:       rewriteCodeownersRefs,
+			RewriteActions:          rewriteActionsRefs,
+			RewriteActionsDir:       rewriteActionsDir,
+			MigrateWebhooks:         migrateWebhooks,
+			MigratePages:            migratePages,
+			MigrateBranchProtection: migrateBranchProtection,
+			MigrateRulesets:         migrateRulesets,
+			MigrateEnvironments:     migrateEnvironments,
+			PatchRulesetIncludes:    patchRulesetIncludes,
+			AllowPermissionChange:   allowPermissionChange,
+			TeamMatcher:             teamMatcher,
+			PolicyFile:              policyFilePath,
+			Provision:               provision,
+			ProvisionValues:         provisionValuesPath,
+			OriginTracking:          originTracking,
+		},
+	}
+	if stagingCapabilities != nil && stagingCapabilities.ScannedAt.Before(migrationPlan.ScannedAt) {
+		migrationPlan.ScannedAt = stagingCapabilities.ScannedAt
+	}
+	if planArchive {
+		migrationPlan.Operation = plan.OperationArchive
+		migrationPlan.Options.ArchiveAfter = archiveAfter
+	}
+
+	for i, repo := range repos {
+		if len(repos) > 1 {
+			fmt.Fprintf(os.Stderr, "[%d/%d] Planning %s\n", i+1, len(repos), repo)
+		}
+		planned, err := planRepository(*client, repo, capabilities, stagingCapabilities)
+		if err != nil {
+			return err
+		}
+		migrationPlan.Repositories = append(migrationPlan.Repositories, planned)
+	}
+
+	if err := plan.Write(planOutputPath, migrationPlan); err != nil {
+		return err
+	}
+	printPlanSummary(migrationPlan)
+	return nil
+}
+
+// planRepository analyzes and validates one repository and lists the steps the operation would
+// run; a two-hop transfer is validated against the staging org and the target org
+func planRepository(client api.RESTClient, repository string, capabilities, stagingCapabilities *types.TargetOrgCapabilities) (plan.Repository, error) {
+	parts := strings.Split(repository, "/")
+	owner, repoName := parts[0], parts[1]
+	planned := plan.Repository{Repository: repository}
+
+	if err := validateSourceRepository(client, owner, repoName); err != nil {
+		return planned, fmt.Errorf("failed to validate source repository %s: %v", repository, err)
+	}
+	if err := checkLegalHold(client, owner, repoName); err != nil {
+		planned.Refused = err.Error()
+	}
+
+	deps, err := analyzer.AnalyzeOrganizationalDependencies(client, owner, repoName, verbose)
+	if err != nil {
+		return planned, fmt.Errorf("failed to analyze dependencies of %s: %v", repository, err)
+	}
+	recordHistory(repository, history.KindAnalysis, "completed", "", nil)
+	planned.Dependencies = deps
+
+	if planStagingOrg != "" {
+		restore := enterStagingHop(planStagingOrg)
+		planned.Staging = &plan.Hop{
+			Validation: validateForPlan(deps, stagingCapabilities, false),
+			Actions:    planActions(plannedTransferSteps(transferResult{Owner: owner, RepoName: repoName})),
+		}
+		restore()
+		// The second hop starts in the staging org
+		owner = planStagingOrg
+	}
+	planned.Validation = validateForPlan(deps, capabilities, assign)
+
+	var sourceTeams []string
+	if assign {
+		teamPermissions, err := getRepositoryTeams(client, parts[0], repoName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Warning: Could not retrieve teams of %s: %v\n", repository, err)
+		}
+		planned.Teams = teamPermissions
+		for _, team := range teamPermissions {
+			sourceTeams = append(sourceTeams, team.Name)
+		}
+	}
+
+	var operation []steps.Step
+	if planArchive {
+		// The archived name is fixed in the plan so reviewers see the final name
+		planned.ArchivedName = fmt.Sprintf("%s-%s", repoName, generateUID())
+		archive := &archiveOperation{owner: owner, repoName: repoName, targetOwner: targetOrg, archivedName: planned.ArchivedName, originalPath: repository, teams: sourceTeams}
+		operation = archive.steps()
+	} else {
+		operation = plannedTransferSteps(transferResult{Owner: owner, RepoName: repoName, Teams: sourceTeams})
+	}
+	planned.Actions = planActions(operation)
+	return planned, nil
+}
+
+// planActions lists the steps of an operation that would run
+func planActions(operation []steps.Step) []plan.Action {
+	var actions []plan.Action
+	for _, step := range steps.Plan(operation) {
+		actions = append(actions, plan.Action{Step: step.Name, Description: step.Description})
+	}
+	return actions
+}
+
+// formatPlanActions joins the step names of a plan
+func formatPlanActions(actions []plan.Action) string {
+	var names []string
+	for _, action := range actions {
+		names = append(names, action.Step)
+	}
+	return strings.Join(names, " → ")
+}
+
+// printPlanSummary lists the planned repositories and how to apply the plan
+func printPlanSummary(migrationPlan *plan.Plan) {
+	fmt.Printf("📝 Migration plan: %s %d repository(ies) to %s\n", migrationPlan.Operation, len(migrationPlan.Repositories), migrationPlan.TargetOrg)
+	fmt.Printf("═════════════════════════════════════════\n")
+
+	blocked := 0
+	for _, planned := range migrationPlan.Repositories {
+		switch {
+		case planned.Refused != "":
+			blocked++
+			fmt.Printf("%-50s ❌ REFUSED\n", planned.Repository)
+			fmt.Printf("  └─ ❌ %s\n", planned.Refused)
+		case planned.Blocked():
+			blocked++
+			fmt.Printf("%-50s ❌ BLOCKED (%d blockers)\n", planned.Repository, planned.Validation.Summary.Blockers)
+		default:
+			fmt.Printf("%-50s ✅ READY\n", planned.Repository)
+		}
+		if planned.ArchivedName != "" {
+			fmt.Printf("  └─ 🏷️  Archived name: %s\n", planned.ArchivedName)
+		}
+		if planned.Staging != nil {
+			fmt.Printf("  └─ 🚧 Hop 1 → %s (%d blockers): %s\n", migrationPlan.StagingOrg, planned.Staging.Validation.Summary.Blockers, formatPlanActions(planned.Staging.Actions))
+			fmt.Printf("  └─ 🚀 Hop 2 → %s (%d blockers, via 'promote'): %s\n", migrationPlan.TargetOrg, planned.Validation.Summary.Blockers, formatPlanActions(planned.Actions))
+			continue
+		}
+		fmt.Printf("  └─ 🪜 Steps: %s\n", formatPlanActions(planned.Actions))
+	}
+
+	fmt.Printf("\nWrote %s (%d ready, %d blocked)\n", planOutputPath, len(migrationPlan.Repositories)-blocked, blocked)
+	fmt.Printf("Review it, then run: gh repo-transfer apply %s\n", planOutputPath)
+}
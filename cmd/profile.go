@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jefeish/gh-repo-transfer/internal/ghclient"
+)
+
+// profileTotals sums the profile entries of one analyzer across repositories
+type profileTotals struct {
+	analyzer     string
+	repositories int
+	requests     int
+	duration     time.Duration
+	points       int
+}
+
+// printProfile reports the API requests, time and rate limit points each analyzer consumed per
+// repository (see --profile). It goes to stderr so it does not mix with json or yaml output.
+func printProfile(w io.Writer) {
+	profile := ghclient.ActiveProfile()
+	if profile == nil {
+		return
+	}
+	entries := profile.Entries()
+	if len(entries) == 0 {
+		fmt.Fprintf(w, "\n📊 API profile: no requests were made\n")
+		return
+	}
+
+	fmt.Fprintf(w, "\n📊 API profile\n")
+	fmt.Fprintf(w, "═════════════════════════════════════════\n")
+	fmt.Fprintf(w, "%-40s %-14s %9s %10s %8s\n", "REPOSITORY", "ANALYZER", "REQUESTS", "TIME", "POINTS")
+
+	var analyzers []*profileTotals
+	byAnalyzer := make(map[string]*profileTotals)
+	var total profileTotals
+	for _, entry := range entries {
+		repository := entry.Repository
+		if repository == "" {
+			repository = "(outside the analysis)"
+		}
+		fmt.Fprintf(w, "%-40s %-14s %9d %10s %8d\n", repository, entry.Analyzer, entry.Requests, formatProfileDuration(entry.Duration), entry.Points)
+
+		totals, ok := byAnalyzer[entry.Analyzer]
+		if !ok {
+			totals = &profileTotals{analyzer: entry.Analyzer}
+			byAnalyzer[entry.Analyzer] = totals
+			analyzers = append(analyzers, totals)
+		}
+		totals.repositories++
+		totals.requests += entry.Requests
+		totals.duration += entry.Duration
+		totals.points += entry.Points
+		total.requests += entry.Requests
+		total.duration += entry.Duration
+		total.points += entry.Points
+	}
+
+	fmt.Fprintf(w, "\nPer analyzer:\n")
+	fmt.Fprintf(w, "%-14s %12s %9s %10s %8s %15s\n", "ANALYZER", "REPOSITORIES", "REQUESTS", "TIME", "POINTS", "POINTS PER REPO")
+	for _, totals := range analyzers {
+		fmt.Fprintf(w, "%-14s %12d %9d %10s %8d %15.1f\n", totals.analyzer, totals.repositories, totals.requests,
+			formatProfileDuration(totals.duration), totals.points, float64(totals.points)/float64(totals.repositories))
+	}
+	fmt.Fprintf(w, "\nTotal: %d requests, %s in requests, %d rate limit points\n", total.requests, formatProfileDuration(total.duration), total.points)
+}
+
+// formatProfileDuration rounds a duration for the profile tables
+func formatProfileDuration(d time.Duration) string {
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(10 * time.Millisecond).String()
+}
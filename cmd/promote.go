@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/spf13/cobra"
+
+	"github.com/jefeish/gh-repo-transfer/internal/ghclient"
+	"github.com/jefeish/gh-repo-transfer/internal/plan"
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+// promotionApprovedTopic marks a staged repository as reviewed and ready for its final placement
+const promotionApprovedTopic = "promotion-approved"
+
+var (
+	skipApproval bool
+	// promoting is set while 'promote' runs the second hop of a two-hop plan
+	promoting bool
+)
+
+// promoteCmd runs the second hop of a two-hop plan: staging org to target org
+var promoteCmd = &cobra.Command{
+	Use:   "promote plan.json [owner/repo...]",
+	Short: "Move staged repositories of a two-hop plan to their final organization",
+	Long: `Run the second hop of a plan made with 'plan --via staging-org'.
+
+'apply' moves the repositories of such a plan into the staging (quarantine) org only.
+Once a staged repository has been reviewed there, a reviewer adds the '` + promotionApprovedTopic + `'
+topic to it; promote then transfers every approved repository from the staging org to the
+target org with the plan's options, assigning the source teams recorded in the plan, and
+removes the topic. Repositories without the topic are listed as awaiting approval.
+
+Each repository is validated again and compared with the plan's validation for the target
+org; regressions abort it unless --allow-drift. Arguments limit promote to the given
+source repositories of the plan.
+
+  gh repo-transfer promote plan.json --dry-run
+  gh repo-transfer promote plan.json acme/app --db migrations.db`,
+	Args:         cobra.MinimumNArgs(1),
+	SilenceUsage: true,
+	RunE:         runPromote,
+}
+
+func init() {
+	rootCmd.AddCommand(promoteCmd)
+	promoteCmd.Flags().BoolVar(&skipApproval, "skip-approval", false, "Promote staged repositories without the "+promotionApprovedTopic+" topic")
+}
+
+func runPromote(cmd *cobra.Command, args []string) error {
+	migrationPlan, err := plan.Read(args[0])
+	if err != nil {
+		return err
+	}
+	if migrationPlan.StagingOrg == "" {
+		return fmt.Errorf("plan file %s has no staging organization; use 'apply' to run it", args[0])
+	}
+	if err := usePlan(cmd, migrationPlan, args[0]); err != nil {
+		return err
+	}
+
+	selected := make(map[string]bool)
+	for _, repository := range args[1:] {
+		if _, ok := migrationPlan.Find(repository); !ok {
+			return fmt.Errorf("repository %s is not in plan file %s", repository, args[0])
+		}
+		selected[strings.ToLower(repository)] = true
+	}
+
+	client, err := ghclient.NewRESTClient()
+	if err != nil {
+		return fmt.Errorf("failed to create API client: %v", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "📝 Promoting from %s to %s (%s)\n", migrationPlan.StagingOrg, migrationPlan.TargetOrg, appliedPlanPath)
+	var approved []string
+	for _, planned := range migrationPlan.Repositories {
+		if len(selected) > 0 && !selected[strings.ToLower(planned.Repository)] {
+			continue
+		}
+		staged := migrationPlan.StagedPath(planned.Repository)
+		ready, reason := stagedForPromotion(*client, staged)
+		if !ready {
+			fmt.Fprintf(os.Stderr, "⏳ %s: %s\n", staged, reason)
+			continue
+		}
+		approved = append(approved, staged)
+	}
+	if len(approved) == 0 {
+		fmt.Printf("No staged repositories are approved for promotion (add the %s topic after review)\n", promotionApprovedTopic)
+		return nil
+	}
+
+	// The approval only matters in the staging org
+	removeTopics = append(removeTopics, promotionApprovedTopic)
+	promoting = true
+	return runTransfer(cmd, approved)
+}
+
+// stagedForPromotion reports whether a repository is in the staging org and approved
+func stagedForPromotion(client api.RESTClient, staged string) (bool, string) {
+	parts := strings.Split(staged, "/")
+	var repository struct {
+		FullName string `json:"full_name"`
+	}
+	if err := client.Get(fmt.Sprintf("repos/%s", staged), &repository); err != nil {
+		if strings.Contains(err.Error(), "404") {
+			return false, "not in the staging org (not applied yet, or already promoted)"
+		}
+		return false, fmt.Sprintf("could not be read: %v", err)
+	}
+	if skipApproval {
+		return true, ""
+	}
+	topics, err := getRepositoryTopics(client, parts[0], parts[1])
+	if err != nil {
+		return false, fmt.Sprintf("could not read topics: %v", err)
+	}
+	for _, topic := range topics {
+		if topic == promotionApprovedTopic {
+			return true, ""
+		}
+	}
+	return false, fmt.Sprintf("awaiting approval (no %s topic)", promotionApprovedTopic)
+}
+
+// enterStagingHop switches the options to the first hop of a two-hop transfer: the repository
+// only moves into the staging org, so teams, announcements, the settings profile and the
+// tombstone (pointing to the final location) wait for the final placement. It returns a
+// function restoring the options.
+func enterStagingHop(stagingOrg string) func() {
+	savedTarget, savedAssign, savedCreate, savedAnnounce, savedProfile, savedTombstone := targetOrg, assign, createTeams, announce, settingsProfilePath, createTombstone
+	targetOrg, assign, createTeams, announce, settingsProfilePath, createTombstone = stagingOrg, false, false, false, "", false
+	return func() {
+		targetOrg, assign, createTeams, announce, settingsProfilePath, createTombstone = savedTarget, savedAssign, savedCreate, savedAnnounce, savedProfile, savedTombstone
+	}
+}
+
+// repositoryTeams returns the team permissions to carry over for a repository. A promoted
+// repository has no teams in the staging org, so the source teams recorded in the plan are used.
+func repositoryTeams(client api.RESTClient, owner, repo string) ([]types.Team, error) {
+	if promoting {
+		if planned, ok := appliedPlan.FindStaged(fmt.Sprintf("%s/%s", owner, repo)); ok {
+			return planned.Teams, nil
+		}
+	}
+	return getRepositoryTeams(client, owner, repo)
+}
+
+// originalPathOf returns the path a repository had before the migration; for a promoted
+// repository that is its source path in the plan, not its staging path
+func originalPathOf(owner, repo string) string {
+	path := fmt.Sprintf("%s/%s", owner, repo)
+	if promoting {
+		if planned, ok := appliedPlan.FindStaged(path); ok {
+			return planned.Repository
+		}
+	}
+	return path
+}
@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/spf13/cobra"
+
+	"github.com/jefeish/gh-repo-transfer/internal/ghclient"
+	"github.com/jefeish/gh-repo-transfer/internal/properties"
+)
+
+var (
+	propertiesFrom string
+	propertiesTo   string
+)
+
+// propertiesCmd groups the custom property commands
+var propertiesCmd = &cobra.Command{
+	Use:   "properties",
+	Short: "Manage custom property definitions across organizations",
+}
+
+var propertiesSyncCmd = &cobra.Command{
+	Use:   "sync --from source-org --to target-org",
+	Short: "Copy custom property definitions from the source organization to the target",
+	Long: `Copy the custom property definitions (names, types, allowed values, defaults and
+descriptions) of the source organization to the target, so a repository's property values
+can be kept when it is transferred.
+
+Properties missing in the target are created. Existing properties are updated to match the
+source; allowed values are merged rather than replaced, so values already used in the
+target stay valid. A property with a different value type is reported as a conflict and
+left unchanged.
+
+  gh repo-transfer properties sync --from source-org --to target-org --dry-run`,
+	Args: cobra.NoArgs,
+	RunE: runPropertiesSync,
+}
+
+func init() {
+	rootCmd.AddCommand(propertiesCmd)
+	propertiesCmd.AddCommand(propertiesSyncCmd)
+	propertiesSyncCmd.Flags().StringVar(&propertiesFrom, "from", "", "Organization to copy the property definitions from")
+	propertiesSyncCmd.Flags().StringVar(&propertiesTo, "to", "", "Organization to copy the property definitions to (defaults to --target-org)")
+	propertiesSyncCmd.MarkFlagRequired("from")
+}
+
+// propertiesSyncResult is the outcome of a properties sync
+type propertiesSyncResult struct {
+	Source  string              `json:"source"`
+	Target  string              `json:"target"`
+	DryRun  bool                `json:"dry_run"`
+	Changes []properties.Change `json:"changes"`
+	Applied bool                `json:"applied"`
+}
+
+func runPropertiesSync(cmd *cobra.Command, args []string) error {
+	to := propertiesTo
+	if to == "" {
+		to = targetOrg
+	}
+	if to == "" {
+		return fmt.Errorf("--to is required")
+	}
+	if strings.EqualFold(to, propertiesFrom) {
+		return fmt.Errorf("--from and --to must be different organizations")
+	}
+
+	client, err := ghclient.NewRESTClient()
+	if err != nil {
+		return fmt.Errorf("failed to create API client: %v", err)
+	}
+
+	source, err := getPropertySchema(*client, propertiesFrom)
+	if err != nil {
+		return err
+	}
+	target, err := getPropertySchema(*client, to)
+	if err != nil {
+		return err
+	}
+
+	result := propertiesSyncResult{
+		Source:  propertiesFrom,
+		Target:  to,
+		DryRun:  dryRun,
+		Changes: properties.Diff(source, target),
+	}
+
+	var pending []properties.Definition
+	for _, change := range result.Changes {
+		if change.Status == properties.StatusCreate || change.Status == properties.StatusUpdate {
+			pending = append(pending, change.Definition)
+		}
+	}
+
+	var syncErr error
+	if !dryRun && len(pending) > 0 {
+		syncErr = upsertPropertySchema(*client, to, pending)
+		result.Applied = syncErr == nil
+	}
+
+	if outputFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(result); err != nil {
+			return err
+		}
+	} else {
+		printPropertiesSyncResult(result)
+	}
+
+	if syncErr != nil {
+		return fmt.Errorf("failed to update the custom property schema of %s: %v", to, syncErr)
+	}
+	return nil
+}
+
+// getPropertySchema reads the custom property definitions of an organization
+func getPropertySchema(client api.RESTClient, org string) ([]properties.Definition, error) {
+	var definitions []properties.Definition
+	if err := client.Get(fmt.Sprintf("orgs/%s/properties/schema", org), &definitions); err != nil {
+		return nil, fmt.Errorf("failed to get the custom property schema of %s: %v", org, err)
+	}
+	return definitions, nil
+}
+
+// upsertPropertySchema creates or updates property definitions in a single request
+func upsertPropertySchema(client api.RESTClient, org string, definitions []properties.Definition) error {
+	payloadBytes, err := json.Marshal(map[string]interface{}{"properties": definitions})
+	if err != nil {
+		return fmt.Errorf("failed to marshal custom property schema: %v", err)
+	}
+	var response []map[string]interface{}
+	return client.Patch(fmt.Sprintf("orgs/%s/properties/schema", org), bytes.NewBuffer(payloadBytes), &response)
+}
+
+// printPropertiesSyncResult prints the planned or applied changes as a table
+func printPropertiesSyncResult(result propertiesSyncResult) {
+	if result.DryRun {
+		fmt.Printf("🔍 DRY RUN: Syncing custom properties %s → %s\n", result.Source, result.Target)
+	} else {
+		fmt.Printf("🏷️  Syncing custom properties %s → %s\n", result.Source, result.Target)
+	}
+
+	counts := make(map[string]int)
+	for _, change := range result.Changes {
+		counts[change.Status]++
+		var icon string
+		switch change.Status {
+		case properties.StatusCreate:
+			icon = "➕"
+		case properties.StatusUpdate:
+			icon = "✏️ "
+		case properties.StatusConflict:
+			icon = "⚠️ "
+		default:
+			icon = "✅"
+		}
+		fmt.Printf("  %s %-30s %-9s %s\n", icon, change.Name, change.Status, change.Definition.ValueType)
+		for _, difference := range change.Differences {
+			fmt.Printf("      %s\n", difference)
+		}
+	}
+
+	fmt.Printf("\nCreate: %d, update: %d, unchanged: %d, conflicts: %d\n",
+		counts[properties.StatusCreate], counts[properties.StatusUpdate], counts[properties.StatusSame], counts[properties.StatusConflict])
+	if result.Applied {
+		fmt.Printf("✅ Updated the custom property schema of %s\n", result.Target)
+	}
+	if counts[properties.StatusConflict] > 0 {
+		fmt.Println("Properties with conflicting value types keep their target definition; values of transferred repositories may be dropped.")
+	}
+}
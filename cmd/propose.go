@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// fileChange is new content for a file of a repository; SHA is the blob being replaced
+type fileChange struct {
+	Path    string
+	SHA     string
+	Content []byte
+}
+
+// proposeFileChanges commits new content for files to a new branch created from the default
+// branch, one commit per file, and opens a pull request for it; it returns the pull request's URL
+func proposeFileChanges(client api.RESTClient, repository, branch string, files []fileChange, title, body string) (string, error) {
+	var repoInfo struct {
+		DefaultBranch string `json:"default_branch"`
+	}
+	if err := client.Get(fmt.Sprintf("repos/%s", repository), &repoInfo); err != nil {
+		return "", err
+	}
+	var head struct {
+		Object struct {
+			SHA string `json:"sha"`
+		} `json:"object"`
+	}
+	if err := client.Get(fmt.Sprintf("repos/%s/git/ref/heads/%s", repository, repoInfo.DefaultBranch), &head); err != nil {
+		return "", err
+	}
+
+	send := func(method, path string, fields map[string]interface{}) error {
+		payload, err := json.Marshal(fields)
+		if err != nil {
+			return err
+		}
+		var response map[string]interface{}
+		return client.Do(method, path, bytes.NewBuffer(payload), &response)
+	}
+	if err := send("POST", fmt.Sprintf("repos/%s/git/refs", repository), map[string]interface{}{
+		"ref": "refs/heads/" + branch,
+		"sha": head.Object.SHA,
+	}); err != nil {
+		return "", err
+	}
+	for _, file := range files {
+		message := title
+		if len(files) > 1 {
+			message = fmt.Sprintf("%s: %s", title, file.Path)
+		}
+		if err := send("PUT", fmt.Sprintf("repos/%s/contents/%s", repository, file.Path), map[string]interface{}{
+			"message": message,
+			"content": base64.StdEncoding.EncodeToString(file.Content),
+			"sha":     file.SHA,
+			"branch":  branch,
+		}); err != nil {
+			return "", err
+		}
+	}
+
+	payload, err := json.Marshal(map[string]string{
+		"title": title,
+		"head":  branch,
+		"base":  repoInfo.DefaultBranch,
+		"body":  body,
+	})
+	if err != nil {
+		return "", err
+	}
+	var pullRequest struct {
+		HTMLURL string `json:"html_url"`
+	}
+	if err := client.Post(fmt.Sprintf("repos/%s/pulls", repository), bytes.NewBuffer(payload), &pullRequest); err != nil {
+		return "", err
+	}
+	return pullRequest.HTMLURL, nil
+}
@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/cli/go-gh/v2/pkg/term"
+	"gopkg.in/yaml.v3"
+
+	"github.com/jefeish/gh-repo-transfer/internal/orgsync"
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+	"github.com/jefeish/gh-repo-transfer/internal/validation"
+)
+
+// provisionValues is the --provision-values file, giving the values of the organization
+// variables and secrets --provision creates
+type provisionValues struct {
+	Variables map[string]string `yaml:"variables"`
+	Secrets   map[string]string `yaml:"secrets"`
+}
+
+// provisioning tracks what --provision created during this run, so a batch creates each item
+// once and the repositories after the first see it as present. Its mutex also serializes the
+// terminal prompts of concurrently validated repositories.
+var provisioning struct {
+	mutex   sync.Mutex
+	values  *provisionValues
+	created map[validation.ProvisionItem]bool
+	failed  map[validation.ProvisionItem]bool
+}
+
+// provisionAndRevalidate creates the missing teams, organization variables and placeholder
+// secrets a validation reports in the target organization (--provision) and validates the
+// repository again against the capabilities including them. Without anything to create, the
+// validation is returned unchanged.
+func provisionAndRevalidate(client api.RESTClient, sourceOrg string, deps *types.OrganizationalDependencies, capabilities *types.TargetOrgCapabilities, result *types.MigrationValidation, drifts []validation.ValidationDrift) (*types.MigrationValidation, []validation.ValidationDrift, error) {
+	if !provision {
+		return result, drifts, nil
+	}
+	items := validation.ProvisionableItems(result)
+	if len(items) == 0 {
+		return result, drifts, nil
+	}
+
+	provisioned, err := provisionItems(client, sourceOrg, targetOrg, items)
+	if err != nil {
+		return result, drifts, err
+	}
+	if len(provisioned) == 0 {
+		return result, drifts, nil
+	}
+	result, drifts = validateAgainstPlan(deps, validation.WithProvisioned(capabilities, provisioned), assign)
+	return result, drifts, nil
+}
+
+// provisionItems creates the items not created earlier in this run and returns those present
+// now. Items that could not be created are reported once and left to the validation.
+func provisionItems(client api.RESTClient, sourceOrg, org string, items []validation.ProvisionItem) ([]validation.ProvisionItem, error) {
+	provisioning.mutex.Lock()
+	defer provisioning.mutex.Unlock()
+
+	if provisioning.values == nil {
+		values, err := loadProvisionValues(provisionValuesPath)
+		if err != nil {
+			return nil, err
+		}
+		provisioning.values = values
+		provisioning.created = make(map[validation.ProvisionItem]bool)
+		provisioning.failed = make(map[validation.ProvisionItem]bool)
+	}
+
+	var present, createdNow []validation.ProvisionItem
+	for _, item := range items {
+		key := validation.ProvisionItem{Kind: item.Kind, Name: strings.ToLower(item.Name)}
+		if provisioning.created[key] {
+			present = append(present, item)
+			continue
+		}
+		if provisioning.failed[key] {
+			continue
+		}
+
+		var err error
+		if dryRun {
+			err = checkProvisionable(client, sourceOrg, item)
+		} else {
+			err = provisionItem(client, sourceOrg, org, item)
+		}
+		if err != nil {
+			provisioning.failed[key] = true
+			fmt.Fprintf(os.Stderr, "⚠️  Warning: Could not provision %s '%s' in %s: %v\n", item.Kind, item.Name, org, err)
+			continue
+		}
+		provisioning.created[key] = true
+		present = append(present, item)
+		createdNow = append(createdNow, item)
+	}
+
+	if len(createdNow) > 0 {
+		var names []string
+		for _, item := range createdNow {
+			names = append(names, fmt.Sprintf("%s %s", item.Kind, item.Name))
+		}
+		if dryRun {
+			fmt.Printf("🧰 Would provision in %s: %s\n", org, strings.Join(names, ", "))
+		} else {
+			fmt.Printf("🧰 Provisioned in %s: %s\n", org, strings.Join(names, ", "))
+		}
+	}
+	return present, nil
+}
+
+// provisionItem creates a team, an organization variable or an organization secret. Secrets get
+// their value from the values file, else a placeholder that has to be replaced by the real
+// value afterwards. Variables get their value from the values file, else the source
+// organization, else the terminal.
+func provisionItem(client api.RESTClient, sourceOrg, org string, item validation.ProvisionItem) error {
+	switch item.Kind {
+	case validation.ProvisionTeam:
+		return createTeamInOrg(client, org, item.Name)
+	case validation.ProvisionSecret:
+		secret := orgsync.Item{Name: item.Name, Value: provisioning.values.Secrets[item.Name]}
+		return createOrgItem(client, org, orgsync.Action{Kind: orgsync.KindSecret, Item: secret})
+	case validation.ProvisionVariable:
+		variable, err := provisionVariable(client, sourceOrg, item.Name)
+		if err != nil {
+			return err
+		}
+		return createOrgItem(client, org, orgsync.Action{Kind: orgsync.KindVariable, Item: variable})
+	default:
+		return fmt.Errorf("unsupported kind %s", item.Kind)
+	}
+}
+
+// checkProvisionable reports, during a dry run, whether a variable would get a value
+func checkProvisionable(client api.RESTClient, sourceOrg string, item validation.ProvisionItem) error {
+	if item.Kind != validation.ProvisionVariable {
+		return nil
+	}
+	if _, ok := provisioning.values.Variables[item.Name]; ok || term.IsTerminal(os.Stdin) {
+		return nil
+	}
+	if _, ok := sourceVariable(client, sourceOrg, item.Name); ok {
+		return nil
+	}
+	return fmt.Errorf("no value in --provision-values or the source organization, and no terminal to ask on")
+}
+
+// provisionVariable finds the value of an organization variable to create
+func provisionVariable(client api.RESTClient, sourceOrg, name string) (orgsync.Item, error) {
+	if value, ok := provisioning.values.Variables[name]; ok {
+		return orgsync.Item{Name: name, Value: value}, nil
+	}
+	if variable, ok := sourceVariable(client, sourceOrg, name); ok {
+		return variable, nil
+	}
+
+	if !term.IsTerminal(os.Stdin) {
+		return orgsync.Item{}, fmt.Errorf("no value in --provision-values or the source organization, and no terminal to ask on")
+	}
+	fmt.Fprintf(os.Stderr, "Value of organization variable %s: ", name)
+	input, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && input == "" {
+		return orgsync.Item{}, fmt.Errorf("no value entered: %v", err)
+	}
+	return orgsync.Item{Name: name, Value: strings.TrimRight(input, "\r\n")}, nil
+}
+
+// sourceVariable reads an organization variable of the source organization. Variables shared
+// with selected repositories are created private, since the moved repository could not be
+// selected yet.
+func sourceVariable(client api.RESTClient, sourceOrg, name string) (orgsync.Item, bool) {
+	var source struct {
+		Value      string `json:"value"`
+		Visibility string `json:"visibility"`
+	}
+	if err := client.Get(fmt.Sprintf("orgs/%s/actions/variables/%s", sourceOrg, name), &source); err != nil {
+		if verbose {
+			fmt.Fprintf(os.Stderr, "Could not read variable '%s' of %s: %v\n", name, sourceOrg, err)
+		}
+		return orgsync.Item{}, false
+	}
+	if source.Visibility == "selected" {
+		source.Visibility = "private"
+	}
+	return orgsync.Item{Name: name, Value: source.Value, Visibility: source.Visibility}, true
+}
+
+// loadProvisionValues reads the --provision-values file; an empty path yields no values
+func loadProvisionValues(path string) (*provisionValues, error) {
+	values := &provisionValues{}
+	if path == "" {
+		return values, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read provision values %s: %v", path, err)
+	}
+	if err := yaml.Unmarshal(data, values); err != nil {
+		return nil, fmt.Errorf("failed to parse provision values %s: %v", path, err)
+	}
+	return values, nil
+}
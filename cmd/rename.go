@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/spf13/cobra"
+
+	"github.com/jefeish/gh-repo-transfer/internal/ghclient"
+	"github.com/jefeish/gh-repo-transfer/internal/history"
+	"github.com/jefeish/gh-repo-transfer/internal/impact"
+)
+
+var renameWithUID bool
+
+// renameCmd represents the rename command
+var renameCmd = &cobra.Command{
+	Use:   "rename owner/repo [new-name] [--uid]",
+	Short: "Rename a repository in place and report what refers to its old name",
+	Long: `Rename a repository within its organization and report every reference to the old
+name that is affected: workflows using its actions, submodules, Go module paths and
+imports, package manifests and packages linked to the repository.
+
+GitHub redirects git and API requests to the new name, but workflow 'uses:' references
+and Go module paths are not redirected and break immediately. Use --dry-run to see the
+report without renaming.
+
+  gh repo-transfer rename owner/repo new-name --dry-run
+  gh repo-transfer rename owner/repo --uid          # Append a unique suffix like archive does`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runRename,
+}
+
+func init() {
+	rootCmd.AddCommand(renameCmd)
+	renameCmd.Flags().BoolVar(&renameWithUID, "uid", false, "Append a unique time-based suffix to the name (same format as archive)")
+}
+
+// renameResult is the outcome of a rename, including its impact report
+type renameResult struct {
+	Repository string         `json:"repository"`
+	NewName    string         `json:"new_name"`
+	UID        string         `json:"uid,omitempty"`
+	Renamed    bool           `json:"renamed"`
+	DryRun     bool           `json:"dry_run"`
+	Impact     *impact.Report `json:"impact"`
+}
+
+func runRename(cmd *cobra.Command, args []string) error {
+	parts := strings.Split(args[0], "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return fmt.Errorf("repository '%s' must be in format 'owner/repo'", args[0])
+	}
+	owner, repoName := parts[0], parts[1]
+
+	newName := repoName
+	if len(args) == 2 {
+		newName = args[1]
+	}
+	uid := ""
+	if renameWithUID {
+		uid = generateUID()
+		newName = fmt.Sprintf("%s-%s", newName, uid)
+	}
+	if strings.EqualFold(newName, repoName) {
+		return fmt.Errorf("a new name or --uid is required to rename %s", args[0])
+	}
+
+	client, err := ghclient.NewRESTClient()
+	if err != nil {
+		return fmt.Errorf("failed to create API client: %v", err)
+	}
+	if err := openHistoryStore(); err != nil {
+		return err
+	}
+	defer closeHistoryStore()
+
+	if verbose {
+		fmt.Fprintf(os.Stderr, "Analyzing references to %s before renaming it to %s\n", args[0], newName)
+	}
+
+	result := renameResult{
+		Repository: args[0],
+		NewName:    newName,
+		UID:        uid,
+		DryRun:     dryRun,
+		Impact:     impact.Analyze(*client, owner, repoName, newName),
+	}
+
+	var renameErr error
+	if !dryRun {
+		renameErr = renameRepository(*client, owner, repoName, newName)
+		result.Renamed = renameErr == nil
+
+		status := "succeeded"
+		if renameErr != nil {
+			status = "failed"
+		}
+		recordHistory(args[0], history.KindRename, status, fmt.Sprintf("%s/%s", owner, newName), result.Impact)
+	}
+
+	if outputFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(result); err != nil {
+			return err
+		}
+	} else {
+		printRenameResult(result)
+	}
+
+	if renameErr != nil {
+		return fmt.Errorf("failed to rename %s: %v", args[0], renameErr)
+	}
+	return nil
+}
+
+// renameRepository renames a repository within its owner
+func renameRepository(client api.RESTClient, owner, repo, newName string) error {
+	payload, err := json.Marshal(map[string]string{"name": newName})
+	if err != nil {
+		return err
+	}
+	var response map[string]interface{}
+	return client.Patch(fmt.Sprintf("repos/%s/%s", owner, repo), bytes.NewBuffer(payload), &response)
+}
+
+// printRenameResult prints the rename outcome and its impact report as a table
+func printRenameResult(result renameResult) {
+	owner := strings.Split(result.Repository, "/")[0]
+	switch {
+	case result.DryRun:
+		fmt.Printf("🔍 DRY RUN: %s would be renamed to %s/%s\n", result.Repository, owner, result.NewName)
+	case result.Renamed:
+		fmt.Printf("✅ Renamed %s to %s/%s\n", result.Repository, owner, result.NewName)
+	default:
+		fmt.Printf("❌ Failed to rename %s\n", result.Repository)
+	}
+
+	report := result.Impact
+	fmt.Printf("\nReferences to the old name: %d (%d break after the rename)\n", len(report.References), report.Breaking())
+	for _, reference := range report.References {
+		icon := "↪️ "
+		if reference.Breaks {
+			icon = "❌"
+		}
+		location := reference.Path
+		if reference.Repository != "" {
+			location = fmt.Sprintf("%s: %s", reference.Repository, reference.Path)
+		}
+		fmt.Printf("  %s [%s] %s\n", icon, reference.Kind, location)
+		fmt.Printf("      → %s\n", reference.Action)
+	}
+	for _, check := range report.Incomplete {
+		fmt.Printf("  ⚠️  Could not check %s\n", check)
+	}
+}
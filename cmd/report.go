@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jefeish/gh-repo-transfer/internal/anonymize"
+	"github.com/jefeish/gh-repo-transfer/internal/fingerprint"
+	"github.com/jefeish/gh-repo-transfer/internal/output"
+)
+
+// reportCmd represents the report command
+var reportCmd = &cobra.Command{
+	Use:   "report --from-dir [directory]",
+	Short: "Recombine per-repository analysis files into aggregate reports",
+	Long: `Read the per-repository JSON files written by 'deps --per-repo' and produce the
+same aggregate output as a batch 'deps' run (batch summary, per-organization roll-up,
+effort estimates) without calling the GitHub API again.
+
+  gh repo-transfer report --from-dir analyses/
+  gh repo-transfer report --from-dir analyses/ --format json`,
+	RunE: runReport,
+}
+
+var reportFromDir string
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+	reportCmd.Flags().StringVar(&reportFromDir, "from-dir", "", "Directory containing per-repository JSON files (required)")
+	reportCmd.MarkFlagRequired("from-dir")
+}
+
+func runReport(cmd *cobra.Command, args []string) error {
+	if err := validateClusterSimilarity(); err != nil {
+		return err
+	}
+
+	allDeps, err := output.LoadRepoFiles(reportFromDir)
+	if err != nil {
+		return err
+	}
+
+	if verbose {
+		fmt.Fprintf(os.Stderr, "Loaded %d repository reports from %s\n", len(allDeps), reportFromDir)
+	}
+
+	if clusterRepos {
+		fingerprint.AssignClusters(allDeps, clusterSimilarity)
+	}
+
+	if anonymizeReports {
+		anonymizer, err := anonymize.New()
+		if err != nil {
+			return err
+		}
+		for _, deps := range allDeps {
+			anonymizer.CollectDependencies(deps)
+		}
+		for _, deps := range allDeps {
+			anonymizer.Apply(deps)
+		}
+	}
+
+	if len(allDeps) == 1 {
+		return output.OutputDependencies(allDeps[0], outputFormat)
+	}
+	return output.OutputMultipleDependencies(allDeps, outputFormat)
+}
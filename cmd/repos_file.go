@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/jefeish/gh-repo-transfer/internal/ghclient"
+	"github.com/jefeish/gh-repo-transfer/pkg/utils"
+)
+
+// repositoryArgs returns the repositories named on the command line followed by those of
+// --repos-file. An argument or --repos-file of "-" reads the list from stdin. Repositories
+// listed more than once are kept once, in the order they first appear. A HOST/ prefix naming
+// the run's host is dropped; one naming another host is an error.
+func repositoryArgs(args []string) ([]string, error) {
+	var repositories []string
+	readStdin := false
+	for _, arg := range args {
+		if arg == "-" {
+			readStdin = true
+			continue
+		}
+		repositories = append(repositories, arg)
+	}
+	if reposFilePath == "-" {
+		readStdin = true
+	} else if reposFilePath != "" {
+		file, err := os.Open(reposFilePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read repository list %s: %v", reposFilePath, err)
+		}
+		defer file.Close()
+		listed, err := readRepositoryList(file, reposFilePath)
+		if err != nil {
+			return nil, err
+		}
+		repositories = append(repositories, listed...)
+	}
+	if readStdin {
+		listed, err := readRepositoryList(os.Stdin, "stdin")
+		if err != nil {
+			return nil, err
+		}
+		if len(listed) == 0 {
+			return nil, fmt.Errorf("no repositories were read from stdin")
+		}
+		repositories = append(repositories, listed...)
+	}
+
+	seen := make(map[string]bool)
+	var unique []string
+	for _, repository := range repositories {
+		repository, err := ghclient.SplitHost(repository, 2)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[strings.ToLower(repository)] {
+			seen[strings.ToLower(repository)] = true
+			unique = append(unique, repository)
+		}
+	}
+	if verbose && len(unique) < len(repositories) {
+		fmt.Fprintf(os.Stderr, "Skipping %d repositories listed more than once\n", len(repositories)-len(unique))
+	}
+	return unique, nil
+}
+
+// readRepositoryList parses a repository list, naming its source in errors
+func readRepositoryList(r io.Reader, source string) ([]string, error) {
+	repositories, err := utils.ParseRepositoryList(r)
+	if err != nil {
+		return nil, fmt.Errorf("repository list %s: %v", source, err)
+	}
+	return repositories, nil
+}
@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+
+	"github.com/jefeish/gh-repo-transfer/internal/ghclient"
+	"github.com/jefeish/gh-repo-transfer/internal/rulesets"
+)
+
+// repositoryRulesets captures the rulesets of a repository before the move for --migrate-rulesets.
+// Inherited lists the organization rulesets that apply to it, which stay with the source org.
+type repositoryRulesets struct {
+	Rulesets  []rulesets.Ruleset
+	Inherited []string
+}
+
+// repositoryRulesetSummary is an entry of the repository rulesets list
+type repositoryRulesetSummary struct {
+	ID         int64  `json:"id"`
+	Name       string `json:"name"`
+	SourceType string `json:"source_type"`
+	Source     string `json:"source"`
+}
+
+// captureRepositoryRulesets reads the repository's own rulesets, naming their bypass actors so
+// they can be found again in the destination organization
+func captureRepositoryRulesets(client api.RESTClient, owner, repo string) (*repositoryRulesets, error) {
+	summaries, err := listRepositoryRulesets(client, owner, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	captured := &repositoryRulesets{}
+	var teams, apps, roles map[int64]string
+	for _, summary := range summaries {
+		if summary.SourceType != "" && summary.SourceType != "Repository" {
+			captured.Inherited = append(captured.Inherited, fmt.Sprintf("%s (%s %s)", summary.Name, strings.ToLower(summary.SourceType), summary.Source))
+			continue
+		}
+		var ruleset rulesets.Ruleset
+		if err := client.Get(fmt.Sprintf("repos/%s/%s/rulesets/%d", owner, repo, summary.ID), &ruleset); err != nil {
+			return nil, fmt.Errorf("failed to read ruleset '%s': %v", summary.Name, err)
+		}
+		if len(ruleset.BypassActors) > 0 && teams == nil {
+			teams, apps, roles = sourceActorNames(client, owner)
+		}
+		ruleset.BypassActors = rulesets.NameActors(ruleset.BypassActors, teams, apps, roles)
+		captured.Rulesets = append(captured.Rulesets, ruleset)
+	}
+	return captured, nil
+}
+
+// recreateRepositoryRulesets creates the captured rulesets on the moved repository. Rulesets that
+// already exist under the same name are left untouched, so running it twice is harmless. What
+// could not be recreated — inherited organization rulesets, bypass actors missing in the
+// destination, rules referencing source IDs and rejected rulesets — is reported.
+func recreateRepositoryRulesets(client api.RESTClient, owner, repo string, captured *repositoryRulesets, verboseOutput bool) error {
+	if captured == nil {
+		return nil
+	}
+	var report []string
+	for _, inherited := range captured.Inherited {
+		report = append(report, fmt.Sprintf("ruleset '%s' comes from the source organization; recreate it in %s with 'rulesets export' and 'rulesets import'", inherited, owner))
+	}
+
+	var failed []string
+	created := 0
+	if len(captured.Rulesets) > 0 {
+		summaries, err := listRepositoryRulesets(client, owner, repo)
+		if err != nil {
+			return err
+		}
+		existing := make(map[string]bool)
+		for _, summary := range summaries {
+			if summary.SourceType == "" || summary.SourceType == "Repository" {
+				existing[strings.ToLower(summary.Name)] = true
+			}
+		}
+
+		var target *rulesets.TargetActors
+		for _, ruleset := range captured.Rulesets {
+			if existing[strings.ToLower(ruleset.Name)] {
+				if verboseOutput {
+					fmt.Fprintf(os.Stderr, "Ruleset '%s' already exists on %s/%s\n", ruleset.Name, owner, repo)
+				}
+				continue
+			}
+
+			conditions, warnings, _ := rulesets.PortableConditions(ruleset.Conditions)
+			actors := ruleset.BypassActors
+			if len(actors) > 0 {
+				if target == nil {
+					actorIDs := targetActorIDs(client, owner)
+					target = &actorIDs
+				}
+				var actorWarnings []string
+				actors, actorWarnings = rulesets.RemapActors(actors, rulesets.ActorMap{}, *target)
+				warnings = append(warnings, actorWarnings...)
+			}
+			for _, rule := range rulesets.OrganizationSpecificRules(ruleset.Rules) {
+				warnings = append(warnings, fmt.Sprintf("rule '%s' references source repository or app IDs; review it", rule))
+			}
+			for _, warning := range warnings {
+				report = append(report, fmt.Sprintf("ruleset '%s': %s", ruleset.Name, warning))
+			}
+
+			payload, err := rulesetPayload(ruleset, conditions, actors)
+			if err != nil {
+				return err
+			}
+			var response map[string]interface{}
+			if err := client.Post(fmt.Sprintf("repos/%s/%s/rulesets", owner, repo), bytes.NewBuffer(payload), &response); err != nil {
+				failed = append(failed, fmt.Sprintf("%s (%v)", ruleset.Name, err))
+				continue
+			}
+			created++
+		}
+	}
+
+	if created > 0 {
+		fmt.Printf("📏 Recreated %d ruleset(s) on %s/%s\n", created, owner, repo)
+	}
+	for _, item := range report {
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: Not recreated on %s/%s: %s\n", owner, repo, item)
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("could not recreate %d ruleset(s): %v", len(failed), failed)
+	}
+	return nil
+}
+
+// listRepositoryRulesets lists the rulesets applying to a repository, including those of its
+// organization
+func listRepositoryRulesets(client api.RESTClient, owner, repo string) ([]repositoryRulesetSummary, error) {
+	var all []repositoryRulesetSummary
+	if err := ghclient.GetAll(&client, fmt.Sprintf("repos/%s/%s/rulesets?includes_parents=true", owner, repo), &all); err != nil {
+		return nil, fmt.Errorf("failed to list rulesets of %s/%s: %v", owner, repo, err)
+	}
+	return all, nil
+}
@@ -0,0 +1,368 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/spf13/cobra"
+
+	"github.com/jefeish/gh-repo-transfer/internal/archivename"
+	"github.com/jefeish/gh-repo-transfer/internal/ghclient"
+	"github.com/jefeish/gh-repo-transfer/internal/history"
+	"github.com/jefeish/gh-repo-transfer/internal/origin"
+	"github.com/jefeish/gh-repo-transfer/internal/steps"
+)
+
+var (
+	archivedNamePattern string
+	restoreOriginOwner  string
+)
+
+// Origin sources of a restore
+const (
+	originFromProperty    = "property"
+	originFromDescription = "description"
+	originFromTopic       = "topic"
+	originFromNamePattern = "name_pattern"
+)
+
+// repoOriginProperty is the custom property archive stores the original "owner/repo" in
+const repoOriginProperty = "repo-origin"
+
+// repoOriginHistoryProperty is the custom property archive stores the origin chain of a
+// repository archived more than once in, as a JSON array of "owner/repo" paths, oldest first
+const repoOriginHistoryProperty = "repo-origin-history"
+
+// restoreCmd reverses an archive: it moves the repository back to where it came from
+var restoreCmd = &cobra.Command{
+	Use:   "restore owner/archived-repo...",
+	Short: "Restore archived repositories to their original organization and name",
+	Long: `Reverse an archive. The original location is read from the '` + repoOriginProperty + `' custom
+property the archive command stored on the repository, else from the description marker or
+topic stored with --origin-tracking; the repository is then unarchived and transferred back
+to the original owner under its original name, and the description marker and topic are
+removed.
+
+Repositories archived by older versions may lack all of them. For them, the original name
+is taken from the archived name with --archived-name-pattern, a regular expression whose
+first (or "name") group captures the original name, and the owner from --origin-owner.
+The property always wins when it is set.
+
+The restore is refused when the original name is already taken in the original owner,
+unless it is taken by the tombstone archive --create-tombstone left there: the tombstone
+is deleted first (requires the delete_repo scope).
+Use --dry-run to see the planned steps.
+
+  gh repo-transfer restore archive-org/app-2JKLX9A7 --dry-run
+  gh repo-transfer restore archive-org/app-2JKLX9A7 archive-org/api-2JKLY1B4
+  gh repo-transfer restore archive-org/billing_archived_20230115 --origin-owner acme \
+    --archived-name-pattern '^(.+)_archived_\d{8}$'`,
+	Args:         cobra.MinimumNArgs(1),
+	SilenceUsage: true,
+	RunE:         runRestore,
+}
+
+func init() {
+	rootCmd.AddCommand(restoreCmd)
+	restoreCmd.Flags().StringVar(&archivedNamePattern, "archived-name-pattern", archivename.DefaultPattern, "Regular expression capturing the original name in its first (or \"name\") group, used when repo-origin is not set")
+	restoreCmd.Flags().StringVar(&restoreOriginOwner, "origin-owner", "", "Original owner used with --archived-name-pattern when repo-origin is not set")
+}
+
+// restoreResult is the outcome of restoring one archived repository
+type restoreResult struct {
+	Repository   string   `json:"repository"`              // Archived "owner/repo"
+	OriginalPath string   `json:"original_path"`           // Where it is restored to
+	OriginSource string   `json:"origin_source,omitempty"` // property, description, topic or name_pattern
+	Steps        []string `json:"steps,omitempty"`
+	Restored     bool     `json:"restored"`
+	DryRun       bool     `json:"dry_run"`
+	Error        string   `json:"error,omitempty"`
+}
+
+func runRestore(cmd *cobra.Command, args []string) error {
+	for _, repository := range args {
+		parts := strings.Split(repository, "/")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return fmt.Errorf("repository '%s' must be in format 'owner/repo'", repository)
+		}
+	}
+
+	namePattern, err := archivename.Compile(archivedNamePattern)
+	if err != nil {
+		return err
+	}
+
+	client, err := ghclient.NewRESTClient()
+	if err != nil {
+		return fmt.Errorf("failed to create API client: %v", err)
+	}
+	if err := loadRunState(); err != nil {
+		return err
+	}
+	defer saveRunState()
+	if err := openHistoryStore(); err != nil {
+		return err
+	}
+	defer closeHistoryStore()
+
+	var results []restoreResult
+	failed := 0
+	for _, repository := range args {
+		result := restoreRepository(*client, repository, namePattern)
+		if result.Error != "" {
+			failed++
+		}
+		results = append(results, result)
+	}
+
+	if outputFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(results); err != nil {
+			return err
+		}
+	} else {
+		printRestoreResults(results)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d repository(ies) could not be restored", failed)
+	}
+	return nil
+}
+
+// restoreRepository resolves the original location of an archived repository and moves it back
+func restoreRepository(client api.RESTClient, repository string, namePattern *regexp.Regexp) restoreResult {
+	parts := strings.Split(repository, "/")
+	owner, repoName := parts[0], parts[1]
+	result := restoreResult{Repository: repository, DryRun: dryRun}
+
+	// The description and topics may record the origin; they are cleared of it after the move
+	markers, err := readOriginMarkers(client, owner, repoName)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	originalPath, source, err := resolveArchiveOrigin(client, owner, repoName, markers, namePattern)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.OriginalPath = originalPath
+	result.OriginSource = source
+	originalParts := strings.Split(originalPath, "/")
+
+	// The transfer would fail (or collide) when something already uses the original name.
+	// A tombstone the archive left there is replaced; a free name redirects to the repository.
+	tombstone, err := findTombstone(client, originalPath, repository)
+	if err != nil {
+		result.Error = fmt.Sprintf("cannot restore to %s: %v", originalPath, err)
+		return result
+	}
+
+	operation := &restoreOperation{
+		client:        client,
+		owner:         owner,
+		repoName:      repoName,
+		originalOwner: originalParts[0],
+		originalName:  originalParts[1],
+		tombstone:     tombstone,
+		markers:       markers,
+	}
+	for _, step := range steps.Plan(operation.steps()) {
+		result.Steps = append(result.Steps, step.Name)
+	}
+	if dryRun {
+		return result
+	}
+
+	if _, err := runOperationSteps(repository, operation.steps()); err != nil {
+		result.Error = err.Error()
+		recordHistory(originalPath, history.KindRestore, "failed", originalParts[0], map[string]string{"archived_name": repository, "error": err.Error()})
+		return result
+	}
+	result.Restored = true
+	runState.RemovePendingArchive(repository)
+	recordHistory(originalPath, history.KindRestore, "succeeded", originalParts[0], map[string]string{"archived_name": repository})
+	return result
+}
+
+// resolveArchiveOrigin returns the original "owner/repo" of an archived repository and where
+// it was found. The repo-origin custom property is the source of truth; without it the
+// description marker, then the topic of --origin-tracking are read, and else the original
+// name is recovered from the archived name with namePattern and --origin-owner.
+func resolveArchiveOrigin(client api.RESTClient, owner, repo string, markers *originMarkers, namePattern *regexp.Regexp) (string, string, error) {
+	properties, err := getRepositoryPropertyValues(client, owner, repo)
+	if err != nil && !strings.Contains(err.Error(), "404") {
+		return "", "", fmt.Errorf("could not read the %s property of %s/%s: %v", repoOriginProperty, owner, repo, err)
+	}
+	for name, values := range properties {
+		if !strings.EqualFold(name, repoOriginProperty) || len(values) == 0 {
+			continue
+		}
+		path := strings.TrimSpace(values[0])
+		parts := strings.Split(path, "/")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return "", "", fmt.Errorf("the %s property of %s/%s is not an owner/repo path: %q", repoOriginProperty, owner, repo, path)
+		}
+		return path, originFromProperty, nil
+	}
+
+	if path, ok := markers.descriptionOrigin(); ok {
+		return path, originFromDescription, nil
+	}
+	if path, ok := markers.topicOrigin(namePattern, repo); ok {
+		return path, originFromTopic, nil
+	}
+
+	originalName, ok := archivename.Original(namePattern, repo)
+	if !ok {
+		return "", "", fmt.Errorf("%s/%s has no %s property and its name does not match --archived-name-pattern %q", owner, repo, repoOriginProperty, namePattern.String())
+	}
+	if restoreOriginOwner == "" {
+		return "", "", fmt.Errorf("%s/%s has no %s property; pass --origin-owner to restore it as %s", owner, repo, repoOriginProperty, originalName)
+	}
+	return fmt.Sprintf("%s/%s", restoreOriginOwner, originalName), originFromNamePattern, nil
+}
+
+// originMarkers are the description and topics of an archived repository, which hold the
+// original path when it was stored with --origin-tracking description or topic
+type originMarkers struct {
+	Description string   `json:"description"`
+	Topics      []string `json:"topics"`
+}
+
+// readOriginMarkers reads the description and topics of a repository
+func readOriginMarkers(client api.RESTClient, owner, repo string) (*originMarkers, error) {
+	var markers originMarkers
+	if err := client.Get(fmt.Sprintf("repos/%s/%s", owner, repo), &markers); err != nil {
+		return nil, fmt.Errorf("could not read %s/%s: %v", owner, repo, err)
+	}
+	return &markers, nil
+}
+
+// descriptionOrigin returns the original path recorded in the description
+func (m *originMarkers) descriptionOrigin() (string, bool) {
+	return origin.ParseDescription(m.Description)
+}
+
+// topicOrigin returns the original path recorded in an origin topic. The topic holds the
+// name in lower case with other characters than letters and digits replaced, so the name
+// recovered from the archived name is preferred when it matches the topic.
+func (m *originMarkers) topicOrigin(namePattern *regexp.Regexp, repo string) (string, bool) {
+	owner, topicName, ok := origin.ParseTopics(m.Topics)
+	if !ok {
+		return "", false
+	}
+	if originalName, ok := archivename.Original(namePattern, repo); ok && origin.TopicName(originalName) == topicName {
+		return fmt.Sprintf("%s/%s", owner, originalName), true
+	}
+	return fmt.Sprintf("%s/%s", owner, topicName), true
+}
+
+// hasMarkers reports whether the description or topics record an origin
+func (m *originMarkers) hasMarkers() bool {
+	_, inDescription := origin.ParseDescription(m.Description)
+	_, _, inTopics := origin.ParseTopics(m.Topics)
+	return inDescription || inTopics
+}
+
+// clearOriginMarkers removes the origin description marker and topic from a restored repository
+func clearOriginMarkers(client api.RESTClient, owner, repo string, markers *originMarkers) error {
+	if _, ok := origin.ParseDescription(markers.Description); ok {
+		if err := setRepositoryDescription(client, owner, repo, origin.StripDescription(markers.Description)); err != nil {
+			return err
+		}
+	}
+	if _, _, ok := origin.ParseTopics(markers.Topics); ok {
+		var topics []string
+		for _, topic := range markers.Topics {
+			if !origin.IsTopic(topic) {
+				topics = append(topics, topic)
+			}
+		}
+		if err := setRepositoryTopics(client, owner, repo, topics); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restoreOperation holds the state threaded through the steps of a restore
+type restoreOperation struct {
+	client        api.RESTClient
+	owner         string
+	repoName      string
+	originalOwner string
+	originalName  string
+	tombstone     bool           // A tombstone occupies the original path
+	markers       *originMarkers // Description and topics, cleared of their origin markers after the move
+}
+
+// steps lists the restore as a sequence: make the repository writable, then transfer it back
+// under its original name (the transfer renames it in the same request)
+func (o *restoreOperation) steps() []steps.Step {
+	return []steps.Step{
+		{Name: "unarchive", Description: "Unarchive the repository", Critical: true, Execute: func() error {
+			return setRepositoryArchiveStatus(o.client, o.owner, o.repoName, false, verbose)
+		}, Rollback: func() error {
+			return setRepositoryArchiveStatus(o.client, o.owner, o.repoName, true, verbose)
+		}},
+		{Name: "remove-tombstone", Description: "Delete the tombstone at the original path", Skip: !o.tombstone, Critical: true, Execute: func() error {
+			return deleteTombstone(o.client, fmt.Sprintf("%s/%s", o.originalOwner, o.originalName))
+		}, Rollback: func() error {
+			return createTombstoneRepository(o.client, o.originalOwner, o.originalName, fmt.Sprintf("%s/%s", o.owner, o.repoName), "")
+		}},
+		{Name: "transfer-back", Description: "Transfer the repository to its original owner and name", Critical: true, Execute: func() error {
+			if err := transferRepositoryBack(o.client, o.owner, o.repoName, o.originalOwner, o.originalName); err != nil {
+				return fmt.Errorf("failed to transfer %s/%s to %s/%s: %v", o.owner, o.repoName, o.originalOwner, o.originalName, err)
+			}
+			return nil
+		}},
+		{Name: "clear-origin-markers", Description: "Remove the origin description marker and topic", Skip: o.markers == nil || !o.markers.hasMarkers(), Execute: func() error {
+			return clearOriginMarkers(o.client, o.originalOwner, o.originalName, o.markers)
+		}},
+	}
+}
+
+// printRestoreResults prints the planned or completed restores as a table
+func printRestoreResults(results []restoreResult) {
+	if dryRun {
+		fmt.Printf("🔍 DRY RUN: Restoring archived repositories\n")
+	} else {
+		fmt.Printf("♻️  Restoring archived repositories\n")
+	}
+	fmt.Printf("═════════════════════════════════════════\n")
+
+	for _, result := range results {
+		switch {
+		case result.Error != "":
+			fmt.Printf("%-50s ❌ FAILED\n", result.Repository)
+			fmt.Printf("  └─ ❌ %s\n", result.Error)
+		case result.DryRun:
+			fmt.Printf("%-50s ✅ READY\n", result.Repository)
+			fmt.Printf("  └─ ✅ Would be restored to: %s%s\n", result.OriginalPath, originSourceNote(result.OriginSource))
+			fmt.Printf("  └─ 🪜 Steps: %s\n", strings.Join(result.Steps, " → "))
+		default:
+			fmt.Printf("%-50s ✅ RESTORED\n", result.Repository)
+			fmt.Printf("  └─ ✅ Restored to: %s%s\n", result.OriginalPath, originSourceNote(result.OriginSource))
+		}
+	}
+}
+
+// originSourceNote flags origins that were not read from the repo-origin property
+func originSourceNote(source string) string {
+	switch source {
+	case originFromNamePattern:
+		return " (from the archived name, no repo-origin property)"
+	case originFromDescription:
+		return " (from the description, no repo-origin property)"
+	case originFromTopic:
+		return " (from the origin topic, no repo-origin property)"
+	}
+	return ""
+}
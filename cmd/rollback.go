@@ -0,0 +1,280 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/spf13/cobra"
+
+	"github.com/jefeish/gh-repo-transfer/internal/ghclient"
+	"github.com/jefeish/gh-repo-transfer/internal/history"
+	"github.com/jefeish/gh-repo-transfer/internal/journal"
+	"github.com/jefeish/gh-repo-transfer/internal/steps"
+)
+
+// rollbackCmd reverts repositories a transfer or archive left half migrated
+var rollbackCmd = &cobra.Command{
+	Use:   "rollback <journal-file> [owner/repo...]",
+	Short: "Move half-migrated repositories of a transfer or archive back to their source",
+	Long: `Revert the repositories a transfer or archive left half migrated, using the journal the run
+wrote (see --journal-dir). A repository is half migrated when it was transferred but its
+remaining steps did not run, or a step such as the team assignment or storing the
+repo-origin property failed.
+
+The steps the journal records as completed are undone in reverse order with their
+compensating actions: the archived flag is cleared, a tombstone at the original path is
+deleted, and the repository is transferred back to its source owner and name. Team access
+collected from the source with --assign is granted again. Teams --create added to the
+target org for these repositories are deleted once no repository uses them.
+
+Name repositories after the journal file to roll back exactly those, including repositories
+that completed. Use --dry-run to see the planned steps.
+
+  gh repo-transfer rollback .repo-transfer-journal/transfer-new-org.json --dry-run
+  gh repo-transfer rollback .repo-transfer-journal/transfer-new-org.json
+  gh repo-transfer rollback .repo-transfer-journal/archive-archive-org.json acme/legacy-api`,
+	Args:         cobra.MinimumNArgs(1),
+	SilenceUsage: true,
+	RunE:         runRollback,
+}
+
+func init() {
+	rootCmd.AddCommand(rollbackCmd)
+}
+
+// rollbackResult is the outcome of rolling back one repository of a journal
+type rollbackResult struct {
+	Repository   string   `json:"repository"`              // Source "owner/repo" the repository returns to
+	Target       string   `json:"target"`                  // Where the run moved it
+	Steps        []string `json:"steps,omitempty"`         // Compensating actions, in the order they run
+	DeletedTeams []string `json:"deleted_teams,omitempty"` // Created teams deleted again
+	KeptTeams    []string `json:"kept_teams,omitempty"`    // Created teams still used by other repositories
+	RolledBack   bool     `json:"rolled_back"`
+	DryRun       bool     `json:"dry_run"`
+	Error        string   `json:"error,omitempty"`
+}
+
+func runRollback(cmd *cobra.Command, args []string) error {
+	var err error
+	runJournal, err = journal.Load(args[0])
+	if err != nil {
+		return err
+	}
+	if dryRun {
+		runJournal.ReadOnly()
+	}
+
+	repositories := args[1:]
+	for _, repository := range repositories {
+		if entry, ok := runJournal.Entry(repository); !ok || !entry.Transferred() {
+			return fmt.Errorf("%s was not transferred by the %s recorded in %s", repository, runJournal.Operation, args[0])
+		}
+	}
+	if len(repositories) == 0 {
+		repositories = runJournal.HalfMigrated()
+	}
+	if len(repositories) == 0 {
+		fmt.Printf("✅ Nothing to roll back: no repository in %s is half migrated\n", args[0])
+		return nil
+	}
+
+	client, err := ghclient.NewRESTClient()
+	if err != nil {
+		return fmt.Errorf("failed to create API client: %v", err)
+	}
+	if err := loadRunState(); err != nil {
+		return err
+	}
+	defer saveRunState()
+	if err := openHistoryStore(); err != nil {
+		return err
+	}
+	defer closeHistoryStore()
+
+	var results []rollbackResult
+	failed := 0
+	for _, repository := range repositories {
+		result := rollbackRepository(*client, repository)
+		if result.Error != "" {
+			failed++
+		}
+		results = append(results, result)
+	}
+	deleteUnusedCreatedTeams(*client, results)
+
+	if outputFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(results); err != nil {
+			return err
+		}
+	} else {
+		printRollbackResults(results)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d repository(ies) could not be rolled back", failed)
+	}
+	return nil
+}
+
+// rollbackRepository undoes the completed steps of a repository's transfer or archive
+func rollbackRepository(client api.RESTClient, repository string) rollbackResult {
+	entry, _ := runJournal.Entry(repository)
+	result := rollbackResult{Repository: repository, Target: entry.Target, DryRun: dryRun}
+	parts := strings.Split(repository, "/")
+	owner, repoName := parts[0], parts[1]
+
+	var operation []steps.Step
+	var err error
+	switch runJournal.Operation {
+	case "transfer":
+		transfer := &transferOperation{client: client, owner: owner, repo: repoName, targetOwner: runJournal.TargetOrg}
+		err = transfer.resume()
+		operation = transfer.steps()
+	case "archive":
+		archive := &archiveOperation{
+			client:        client,
+			owner:         owner,
+			repoName:      repoName,
+			targetOwner:   runJournal.TargetOrg,
+			archivedName:  entry.Target[strings.Index(entry.Target, "/")+1:],
+			originalPath:  repository,
+			verboseOutput: verbose,
+		}
+		err = archive.resume()
+		operation = archive.steps()
+	default:
+		err = fmt.Errorf("unknown operation '%s' in the journal", runJournal.Operation)
+	}
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	completed := runJournal.CompletedSteps(repository)
+	for i := len(operation) - 1; i >= 0; i-- {
+		if completed[operation[i].Name] && operation[i].Rollback != nil {
+			result.Steps = append(result.Steps, operation[i].Name)
+		}
+	}
+	if len(entry.Teams) > 0 {
+		result.Steps = append(result.Steps, "restore-source-teams")
+	}
+	if dryRun {
+		return result
+	}
+
+	if _, err := steps.Undo(operation, completed); err != nil {
+		result.Error = err.Error()
+		recordJournal(runJournal.Fail(repository, err))
+		recordHistory(repository, history.KindRollback, "failed", runJournal.TargetOrg, map[string]string{"target": entry.Target, "error": err.Error()})
+		return result
+	}
+
+	// The source org's team access did not move with the repository and is granted again
+	for _, assignment := range assignTeamsVerified(client, owner, repoName, entry.Teams) {
+		if assignment.Err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Warning: Team '%s' could not be given '%s' on %s again: %v\n", assignment.Team.Name, assignment.Team.Permission, repository, assignment.Err)
+		}
+	}
+
+	result.RolledBack = true
+	recordJournal(runJournal.RolledBack(repository))
+	recordHistory(repository, history.KindRollback, "succeeded", runJournal.TargetOrg, map[string]string{"target": entry.Target})
+	return result
+}
+
+// deleteUnusedCreatedTeams deletes the teams --create added to the target org for the rolled
+// back repositories, unless a repository still uses them, e.g. one of the batch that completed
+func deleteUnusedCreatedTeams(client api.RESTClient, results []rollbackResult) {
+	seen := make(map[string]bool)
+	for i := range results {
+		result := &results[i]
+		if result.Error != "" {
+			continue
+		}
+		entry, _ := runJournal.Entry(result.Repository)
+		for _, team := range entry.CreatedTeams {
+			if seen[strings.ToLower(team)] {
+				continue
+			}
+			seen[strings.ToLower(team)] = true
+
+			if result.DryRun {
+				result.Steps = append(result.Steps, fmt.Sprintf("delete-team %s (when unused)", team))
+				continue
+			}
+			deleted, err := deleteTeamIfUnused(client, runJournal.TargetOrg, team)
+			switch {
+			case err != nil:
+				fmt.Fprintf(os.Stderr, "⚠️  Warning: Could not delete team '%s' from %s: %v\n", team, runJournal.TargetOrg, err)
+				result.KeptTeams = append(result.KeptTeams, team)
+			case deleted:
+				result.DeletedTeams = append(result.DeletedTeams, team)
+			default:
+				result.KeptTeams = append(result.KeptTeams, team)
+			}
+		}
+	}
+}
+
+// deleteTeamIfUnused deletes a team of org that has no repositories left
+func deleteTeamIfUnused(client api.RESTClient, org, team string) (bool, error) {
+	slug, found := resolveTeamSlug(client, org, team)
+	if !found {
+		return false, fmt.Errorf("team not found")
+	}
+
+	var repositories []struct {
+		FullName string `json:"full_name"`
+	}
+	if err := ghclient.GetAll(&client, fmt.Sprintf("orgs/%s/teams/%s/repos", org, slug), &repositories); err != nil {
+		return false, err
+	}
+	if len(repositories) > 0 {
+		if verbose {
+			fmt.Fprintf(os.Stderr, "Keeping team '%s': still used by %d repositories\n", team, len(repositories))
+		}
+		return false, nil
+	}
+
+	if err := client.Delete(fmt.Sprintf("orgs/%s/teams/%s", org, slug), nil); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// printRollbackResults prints the planned or completed rollbacks as a table
+func printRollbackResults(results []rollbackResult) {
+	if dryRun {
+		fmt.Printf("🔍 DRY RUN: Rolling back half-migrated repositories\n")
+	} else {
+		fmt.Printf("↩️  Rolling back half-migrated repositories\n")
+	}
+	fmt.Printf("═════════════════════════════════════════\n")
+
+	for _, result := range results {
+		switch {
+		case result.Error != "":
+			fmt.Printf("%-50s ❌ FAILED\n", result.Repository)
+			fmt.Printf("  └─ ❌ %s\n", result.Error)
+		case result.DryRun:
+			fmt.Printf("%-50s ✅ READY\n", result.Repository)
+			fmt.Printf("  └─ ✅ Would be moved back from: %s\n", result.Target)
+			fmt.Printf("  └─ 🪜 Steps: %s\n", strings.Join(result.Steps, " → "))
+		default:
+			fmt.Printf("%-50s ✅ ROLLED BACK\n", result.Repository)
+			fmt.Printf("  └─ ✅ Moved back from: %s\n", result.Target)
+		}
+		if len(result.DeletedTeams) > 0 {
+			fmt.Printf("  └─ 🗑️  Deleted created teams: %s\n", strings.Join(result.DeletedTeams, ", "))
+		}
+		if len(result.KeptTeams) > 0 {
+			fmt.Printf("  └─ 👥 Kept created teams still in use: %s\n", strings.Join(result.KeptTeams, ", "))
+		}
+	}
+}
@@ -1,9 +1,17 @@
 package cmd
 
 import (
+	"fmt"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
+
+	"github.com/jefeish/gh-repo-transfer/internal/batch"
+	"github.com/jefeish/gh-repo-transfer/internal/dependencies"
+	"github.com/jefeish/gh-repo-transfer/internal/encryption"
+	"github.com/jefeish/gh-repo-transfer/internal/ghclient"
+	"github.com/jefeish/gh-repo-transfer/internal/journal"
 )
 
 var (
@@ -12,10 +20,78 @@ var (
 	sections     []string
 	targetOrg    string
 	separateFiles bool
+	outputDir    string
+	existingFiles string
+	fsyncFiles   bool
 	dryRun       bool
 	enforce      bool
 	assign       bool
 	createTeams  bool
+	addTopics    []string
+	removeTopics []string
+	defaultBranch string
+	settingsProfilePath string
+	verifySettings bool
+	announce     bool
+	anonymizeReports bool
+	effortWeightsPath string
+	stateFilePath string
+	revalidate   bool
+	maxCapabilityAge time.Duration
+	allowDrift   bool
+	historyDBPath string
+	controlRepo  string
+	migrationWave string
+	artifactURL  string
+	checkCollisions bool
+	teamMatcher  string
+	allowPermissionChange bool
+	cleanupSource bool
+	patchRulesetIncludes bool
+	referenceRepo string
+	clusterRepos bool
+	clusterSimilarity float64
+	publishCheck bool
+	enterpriseSlug string
+	targetEnterpriseSlug string
+	billingImpact bool
+	policyFilePath string
+	archiveAfter string
+	assumeYes    bool
+	confirmThreshold int
+	useGraphQL   bool
+	createTombstone bool
+	migrateWebhooks bool
+	migratePages    bool
+	migrateBranchProtection bool
+	migrateRulesets bool
+	promptSecrets   bool
+	redirectMapPath string
+	migrateEnvironments bool
+	concurrency  int
+	ignoreFilePath string
+	journalDir   string
+	resumeRun    bool
+	profileRun   bool
+	deepAnalysis string
+	deepThreshold int
+	reposFilePath string
+	hostname     string
+	localPath    string
+	outputTarget string
+	encryptKey   string
+	provision    bool
+	provisionValuesPath string
+	interactive  bool
+	originTracking string
+	configPath     string
+	severityOverridesPath string
+	failOn         string
+	subdirectoryPath string
+	targetName     string
+	rewriteCodeownersRefs bool
+	rewriteActionsRefs bool
+	rewriteActionsDir  string
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -28,15 +104,40 @@ repository governance configuration and organizational dependencies.
 This tool can perform two types of analysis:
 1. Governance inspection (rulesets, collaborators, security settings, etc.)
 2. Organizational dependencies analysis (code deps, CI/CD deps, access control, etc.)`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		ghclient.SetVerbose(verbose)
+		if profileRun {
+			ghclient.StartProfile()
+		}
+		// The config's target_org may carry a HOST/ prefix too, so it is read first
+		if err := applyConfig(cmd); err != nil {
+			return err
+		}
+		if err := applyHostname(); err != nil {
+			return err
+		}
+		if err := encryption.SetKey(encryptKey); err != nil {
+			return err
+		}
+		return startOutputTarget()
+	},
 	RunE: runInspect,
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 func Execute() {
-	err := rootCmd.Execute()
+	cmd, err := rootCmd.ExecuteC()
+	printProfile(os.Stderr)
+	if storeErr := finishOutputTarget(cmd); storeErr != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", storeErr)
+		err = storeErr
+	}
 	if err != nil {
 		os.Exit(1)
 	}
+	if validationExitCode != 0 {
+		os.Exit(validationExitCode)
+	}
 }
 
 func init() {
@@ -63,27 +164,149 @@ Examples:
   repo-transfer deps owner/repo                                  # Analyze single repository
   repo-transfer deps owner/repo1 owner/repo2 owner/repo3         # Batch analysis
   repo-transfer deps owner/repo --target-org target-org          # With automatic validation
+  repo-transfer deps owner/repo --config team.yaml               # Defaults and severity policies from a config file
+  repo-transfer deps owner/repo -t org --severity-overrides s.yaml # Remap what blocks and what warns
+  repo-transfer deps owner/repo -t org --fail-on blockers        # CI gate: exit 3 on blockers only
   repo-transfer deps owner/repo1 owner/repo2 --per-repo          # Output to individual files
+  repo-transfer report --from-dir analyses/                      # Aggregate previously written files
+  repo-transfer history --db migrations.db owner/repo            # Show the recorded migration timeline
+  repo-transfer status --db migrations.db --state-file plan.json # Show each repository's migration phase
+  repo-transfer comment owner/repo --target-org org --issue 42   # Post readiness as a sticky comment
+  repo-transfer rename owner/repo new-name --dry-run             # Report what refers to the old name
+  repo-transfer rulesets export --org src --file rulesets.json   # Export org rulesets for import elsewhere
+  repo-transfer sync-org --from src -t org --allowlist a.yaml    # Create allowlisted org Actions items
+  repo-transfer compare --source-org src -t org                  # Gap report between two organizations
+  repo-transfer explain ci.create_secret                         # Why a finding has its status, how to fix it
+  repo-transfer properties sync --from src --to org --dry-run    # Copy custom property definitions
+  repo-transfer deps owner/repo -t org --billing-impact          # Estimate added target seats/GHAS committers
+  repo-transfer deps org/repo1 org/repo2 org/repo3 --graphql     # Faster batch scan through GraphQL
+  repo-transfer deps org/repo -t new-org --redirect-map map.csv  # Export old → new URLs for a reverse proxy
   repo-transfer transfer owner/repo --target-org org             # Transfer repository
+  repo-transfer transfer owner/action -t org --create-tombstone  # Reserve the old name after the move
+  repo-transfer transfer owner/repo -t org --rewrite-codeowners  # Propose CODEOWNERS pointing at the new org's teams
+  repo-transfer transfer owner/repo -t org --rewrite-actions     # Propose workflows using the new org's actions
+  repo-transfer transfer owner/repo -t org --migrate-webhooks    # Recreate repository webhooks after the move
+  repo-transfer transfer owner/repo -t org --migrate-pages       # Restore GitHub Pages and its custom domain after the move
+  repo-transfer transfer owner/repo -t org --migrate-branch-protection # Re-apply branch protection rules after the move
+  repo-transfer transfer owner/repo -t org --migrate-rulesets    # Recreate repository rulesets after the move
+  repo-transfer transfer owner/repo -t org --prompt-secrets      # Set repository secrets the move dropped
+  repo-transfer transfer owner/repo -t org --provision           # Create missing teams, org variables and secrets first
+  repo-transfer transfer owner/repo -t org --migrate-environments # Recreate environment reviewers, timers, secrets and variables
+  repo-transfer plan owner/repo -t org -o plan.json              # Write a reviewable migration plan
+  repo-transfer apply plan.json                                  # Execute a reviewed plan
+  repo-transfer promote plan.json                                # Move approved staged repos of a --via plan on
   repo-transfer transfer owner/repo --target-org org --dry-run   # Preview transfer
   repo-transfer transfer owner/repo --target-org org --enforce   # Enforce transfer despite validation blockers
+  repo-transfer transfer --repos-file r.txt -t org --interactive # Decide per blocked repository: enforce, skip or abort
   repo-transfer transfer owner/repo --target-org org --assign    # Transfer and assign to same teams
+  repo-transfer transfer owner/repo -t org --policy-file p.yml   # Honor legal hold markers from a policy file
+  repo-transfer transfer --repos-file repos.txt -t org --yes     # Skip the large-batch confirmation (automation)
+  repo-transfer transfer --repos-file repos.txt -t org --resume  # Continue a batch an earlier run did not finish
+  repo-transfer archive owner/repo -t arch --archive-after 7d    # Archive read-only after a soak period (needs --state-file)
+  repo-transfer archive owner/repo -t arch --origin-tracking all # Record the original path as property, topic and description
+  repo-transfer finalize --state-file plan.json                  # Set the archived flag once the soak period passed
+  repo-transfer restore arch/repo-2JKLX9A7 --dry-run             # Move an archived repository back to its origin
+  repo-transfer rollback journal/transfer-org.json --dry-run     # Preview moving half-migrated repositories back
+  repo-transfer deps owner/repo1 owner/repo2 --profile           # Report API requests and rate limit points per analyzer
+  repo-transfer deps --repos-file repos.txt --deep auto          # Scan contents only where a cheap pass finds enough
+  repo-transfer deps - < repos.txt                               # Read the repositories from stdin
+  repo-transfer deps owner/repo --hostname ghes.example.com      # Analyze on GitHub Enterprise Server or GHE.com
+  repo-transfer deps owner/repo --local-path ~/src/repo          # Read code and workflows from a local clone
+  repo-transfer deps owner/mono --path services/foo/             # Report what a repository extracted from a subdirectory needs
+  repo-transfer deps owner/repo -t org --target-name web-v2      # What-if: module path, package scope and images under the new name
+  repo-transfer deps owner/repo -t org --output-target s3://b/ci # Store report and state files of the CI run in S3
+  repo-transfer plan owner/repo -t org --encrypt-key env:PLAN_KEY# Encrypt the plan, state and journal files
 
 {{if .HasAvailableSubCommands}}Use "{{.CommandPath}} [command] --help" for more information about a command.{{end}}
 `)
 	
-	rootCmd.PersistentFlags().StringVarP(&outputFormat, "format", "f", "table", "Output format (json, yaml, table)")
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "format", "f", "table", "Output format (json, yaml, table, xlsx, junit, markdown, html, csv)")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Enable verbose output")
 	rootCmd.PersistentFlags().StringVarP(&targetOrg, "target-org", "t", "", "Target organization for validation or transfer")
 	rootCmd.PersistentFlags().BoolVarP(&separateFiles, "per-repo", "p", false, "Output analysis to individual JSON files (deps only)")
+	rootCmd.PersistentFlags().StringVar(&outputDir, "output-dir", ".", "Directory for --per-repo files and their index.json manifest (deps only)")
+	rootCmd.PersistentFlags().StringVar(&existingFiles, "existing-files", "overwrite", "What to do with existing --per-repo files: overwrite, skip or append (deps only)")
+	rootCmd.PersistentFlags().BoolVar(&fsyncFiles, "fsync", false, "Flush each --per-repo file to disk before moving it into place (deps only)")
 	rootCmd.PersistentFlags().BoolVarP(&dryRun, "dry-run", "d", false, "Preview actions without executing (transfer only)")
 	rootCmd.PersistentFlags().BoolVarP(&enforce, "enforce", "e", false, "Enforce transfer action even if validation shows blockers (transfer only)")
 	rootCmd.PersistentFlags().BoolVarP(&assign, "assign", "a", false, "Apply existing teams after repository transfer (transfer only)")
 	rootCmd.PersistentFlags().BoolVarP(&createTeams, "create", "c", false, "Create teams in target org if they don't exist (transfer/archive only)")
+	rootCmd.PersistentFlags().StringSliceVar(&addTopics, "add-topics", nil, "Topics to add to the repository after the move, e.g. migrated,wave-3 (transfer/archive only)")
+	rootCmd.PersistentFlags().StringSliceVar(&removeTopics, "remove-topics", nil, "Topics to remove from the repository after the move (transfer/archive only)")
+	rootCmd.PersistentFlags().StringVar(&defaultBranch, "default-branch", "", "Default branch name mandated by the target org; renames the branch after the move (transfer/archive only)")
+	rootCmd.PersistentFlags().StringVar(&settingsProfilePath, "apply-settings-profile", "", "YAML profile of merge settings and feature toggles to apply after the move (transfer/archive only)")
+	rootCmd.PersistentFlags().BoolVar(&verifySettings, "verify", false, "Report repository/security settings that changed during the move (transfer/archive only)")
+	rootCmd.PersistentFlags().BoolVar(&announce, "announce", false, "Label open issues/PRs 'repo-migrated' and pin an issue announcing the new location (transfer/archive only)")
+	rootCmd.PersistentFlags().BoolVar(&anonymizeReports, "anonymize", false, "Replace org, repo, team and user names with hashed tokens in reports (deps only)")
+	rootCmd.PersistentFlags().StringVar(&effortWeightsPath, "effort-weights", "", "YAML file overriding remediation effort minutes per item type, e.g. create_team: 10")
+	rootCmd.PersistentFlags().StringVar(&stateFilePath, "state-file", "", "JSON state file used to cache validation results between runs")
+	rootCmd.PersistentFlags().BoolVar(&revalidate, "revalidate", false, "Ignore cached validation results in the state file and validate again")
+	rootCmd.PersistentFlags().DurationVar(&maxCapabilityAge, "max-capability-age", 0, "Reuse a target capability scan from the state file while younger than this, and refuse plans scanned longer ago on apply, e.g. 30m (0 always rescans)")
+	rootCmd.PersistentFlags().BoolVar(&allowDrift, "allow-drift", false, "Warn instead of aborting when validation regressed since the plan stored in the state file (transfer/archive only)")
+	rootCmd.PersistentFlags().StringVar(&historyDBPath, "db", "", "SQLite database recording analyses, validations, transfers and verifications (see 'history')")
+	rootCmd.PersistentFlags().StringVar(&controlRepo, "control-repo", "", "owner/repo on which a deployment per migrated repository reports progress (transfer/archive only)")
+	rootCmd.PersistentFlags().StringVar(&migrationWave, "wave", "", "Migration wave name used to group deployments on the --control-repo")
+	rootCmd.PersistentFlags().StringVar(&artifactURL, "artifact-url", "", "Link to result artifacts (e.g. the CI run) attached to every --control-repo deployment status")
+	rootCmd.PersistentFlags().BoolVar(&checkCollisions, "check-collisions", false, "Flag org secrets/variables that already exist under the same name in the target org as Review items instead of Ready")
+	rootCmd.PersistentFlags().StringVar(&teamMatcher, "team-matcher", "slug", "How source teams are matched to target teams: exact (name), slug (GitHub slug) or normalized (ignores '-' and '_')")
+	rootCmd.PersistentFlags().BoolVar(&allowPermissionChange, "allow-permission-change", false, "Proceed when a team's permission in the target differs from its source permission (transfer only)")
+	rootCmd.PersistentFlags().BoolVar(&cleanupSource, "cleanup-source", false, "Remove references to the moved repository left in the source org: org ruleset conditions and project items (transfer/archive only)")
+	rootCmd.PersistentFlags().BoolVar(&patchRulesetIncludes, "patch-ruleset-includes", false, "Add the archived name to target org rulesets that list the original repository name (archive only)")
+	rootCmd.PersistentFlags().StringVar(&referenceRepo, "reference-repo", "", "Already-migrated owner/repo whose dependencies are known; only dependencies it does not have are highlighted (deps only)")
+	rootCmd.PersistentFlags().BoolVar(&clusterRepos, "cluster", false, "Group repositories with similar secrets, variables, runners, actions, teams and apps into clusters (deps/report only)")
+	rootCmd.PersistentFlags().Float64Var(&clusterSimilarity, "cluster-similarity", 0.5, "Minimum similarity (0-1) for two repositories to share a --cluster")
+	rootCmd.PersistentFlags().BoolVar(&publishCheck, "publish-check", false, "Publish migration readiness as a check run on each source repository's default branch (deps with --target-org only)")
+	rootCmd.PersistentFlags().StringVar(&enterpriseSlug, "enterprise", "", "Enterprise slug for enterprise-level checks such as audit log streams (requires enterprise admin access)")
+	rootCmd.PersistentFlags().StringVar(&targetEnterpriseSlug, "target-enterprise", "", "Enterprise of the target org whose policies are checked during validation (defaults to --enterprise)")
+	rootCmd.PersistentFlags().StringVar(&policyFilePath, "policy-file", "", "YAML policy file, e.g. the custom properties and topics that put a repository on legal hold (transfer/archive only)")
+	rootCmd.PersistentFlags().StringVar(&archiveAfter, "archive-after", "", "Transfer now and leave the repository writable for this soak period, e.g. 7d; 'finalize' sets the archived flag afterwards (archive with --state-file only)")
+	rootCmd.PersistentFlags().BoolVar(&billingImpact, "billing-impact", false, "Estimate the seats and Advanced Security committers the transfer adds to the target org (deps with --target-org only)")
+	rootCmd.PersistentFlags().BoolVarP(&assumeYes, "yes", "y", false, "Skip the confirmation prompt for large batches, for automation (transfer/archive only)")
+	rootCmd.PersistentFlags().IntVar(&confirmThreshold, "confirm-threshold", 10, "Batches of at least this many repositories require typing the target org name to confirm; 0 disables the prompt (transfer/archive only)")
+	rootCmd.PersistentFlags().BoolVar(&useGraphQL, "graphql", false, "Read metadata, branch protections, teams and collaborators of many repositories with batched GraphQL queries (batch deps only)")
+	rootCmd.PersistentFlags().BoolVar(&createTombstone, "create-tombstone", false, "After the move, occupy the old path with an archived repository pointing to the new location so the name cannot be reused (transfer/archive only)")
+	rootCmd.PersistentFlags().BoolVar(&migrateWebhooks, "migrate-webhooks", false, "Recreate the repository's webhooks after the move; secrets have to be set again (transfer/archive only)")
+	rootCmd.PersistentFlags().BoolVar(&migratePages, "migrate-pages", false, "Restore GitHub Pages with its build source, custom domain and HTTPS setting after the move (transfer only)")
+	rootCmd.PersistentFlags().BoolVar(&migrateBranchProtection, "migrate-branch-protection", false, "Re-apply the branch protection rules after the move, matching their teams in the target org (transfer/archive only)")
+	rootCmd.PersistentFlags().BoolVar(&migrateRulesets, "migrate-rulesets", false, "Recreate the repository's own rulesets after the move and report what could not be recreated (transfer/archive only)")
+	rootCmd.PersistentFlags().BoolVar(&promptSecrets, "prompt-secrets", false, "Ask on the terminal for the value of each repository Actions secret missing after the move (transfer/archive only)")
+	rootCmd.PersistentFlags().BoolVar(&migrateEnvironments, "migrate-environments", false, "Recreate environment protection rules, required reviewers, wait timers, variables and secrets after the move (transfer/archive only)")
+	rootCmd.PersistentFlags().StringVar(&redirectMapPath, "redirect-map", "", "Write old → new URLs of release pages and assets, Pages sites and raw content to this CSV (or .json) file (deps with --target-org only)")
+	rootCmd.PersistentFlags().IntVar(&concurrency, "concurrency", batch.DefaultConcurrency, "Repositories analyzed and validated at once in batches (deps/transfer/archive)")
+	rootCmd.PersistentFlags().StringVar(&ignoreFilePath, "ignore-file", "", "File of finding IDs to suppress, each with an optional expires=YYYY-MM-DD and repo=owner/repo (default .repo-transfer-ignore when present)")
+	rootCmd.PersistentFlags().StringVar(&journalDir, "journal-dir", journal.DefaultDir, "Directory of the journal recording how far each repository of a batch got (transfer/archive only)")
+	rootCmd.PersistentFlags().BoolVar(&resumeRun, "resume", false, "Continue the batch recorded in the journal, skipping completed repositories and steps (transfer/archive only)")
+	rootCmd.PersistentFlags().BoolVar(&profileRun, "profile", false, "Report the API requests, time and rate limit points each analyzer consumed per repository on stderr")
+	rootCmd.PersistentFlags().StringVar(&deepAnalysis, "deep", string(dependencies.DeepAlways), "Which repositories get file content scanning and ruleset details: always, never, or auto after a cheap scoring pass (deps only)")
+	rootCmd.PersistentFlags().IntVar(&deepThreshold, "deep-threshold", dependencies.DefaultDeepThreshold, "Fast pass score from which --deep auto analyzes a repository in depth")
+	rootCmd.PersistentFlags().StringVar(&reposFilePath, "repos-file", "", "File with one owner/repo per line, # starts a comment; '-' reads stdin (deps/plan/transfer/archive)")
+	rootCmd.PersistentFlags().StringVar(&hostname, "hostname", "", "GitHub host: github.com, a GitHub Enterprise Server hostname or a GHE.com subdomain (default GH_HOST, else gh's host)")
+	rootCmd.PersistentFlags().StringVar(&localPath, "local-path", "", "Read the file contents of the analyzed repository from this local clone instead of the API (deps with one repository)")
+	rootCmd.PersistentFlags().StringVar(&outputTarget, "output-target", "", "Also store the report, state file, redirect map, per-repo files and journal of the run below a per-run prefix: file://DIR, s3://BUCKET/PREFIX or gs://BUCKET/PREFIX")
+	rootCmd.PersistentFlags().BoolVar(&provision, "provision", false, "Create missing teams, org variables and placeholder org secrets in the target before the move and report them (transfer/archive only)")
+	rootCmd.PersistentFlags().StringVar(&provisionValuesPath, "provision-values", "", "YAML file of 'variables' and 'secrets' values for --provision; variables default to the source org value, else are asked for")
+	rootCmd.PersistentFlags().BoolVar(&interactive, "interactive", false, "Review each repository's blockers and warnings after validation and choose enforce/proceed, skip or abort (transfer/archive only)")
+	rootCmd.PersistentFlags().StringVar(&originTracking, "origin-tracking", "property", "Where the original path is stored after the move: property (repo-origin), topic, description or all (transfer/archive only)")
+	rootCmd.PersistentFlags().StringVar(&configPath, "config", "", "Config file of defaults (target_org, concurrency, format), severity_overrides and excluded_checks (default ~/.config/repo-transfer/config.yaml, then .repo-transfer.yaml)")
+	rootCmd.PersistentFlags().StringVar(&severityOverridesPath, "severity-overrides", "", "YAML file of rules remapping the status of findings by ID, kind, category or item pattern before readiness is computed")
+	rootCmd.PersistentFlags().StringVar(&failOn, "fail-on", "", "Exit non-zero only on blockers (exit 3), or also on warnings (exit 4); by default blockers exit 3 and setup needed exits 2 (deps with --target-org only)")
+	rootCmd.PersistentFlags().StringVar(&subdirectoryPath, "path", "", "Scope the analysis of workflows, package files and CODEOWNERS to this subdirectory, for a repository planned to be extracted from it (deps with one repository)")
+	rootCmd.PersistentFlags().StringVar(&targetName, "target-name", "", "Planned name of the repository in the target organization; reports the module path, package scope and image names that change with it (deps with --target-org and one repository)")
+	rootCmd.PersistentFlags().BoolVar(&rewriteCodeownersRefs, "rewrite-codeowners", false, "After the move, open a pull request rewriting @source-org/ team references in CODEOWNERS to the teams of the target org that exist (transfer only)")
+	rootCmd.PersistentFlags().BoolVar(&rewriteActionsRefs, "rewrite-actions", false, "After the move, open a pull request rewriting uses: source-org/action@ref in workflows to the target org, for the actions that exist there (transfer only)")
+	rootCmd.PersistentFlags().StringVar(&rewriteActionsDir, "rewrite-actions-out", "", "Write the workflows --rewrite-actions would change to DIR/<repo> instead of opening a pull request (transfer only)")
+	rootCmd.PersistentFlags().StringVar(&encryptKey, "encrypt-key", "", "Key file (or env:NAME) encrypting state, plan, journal, --per-repo, redirect map, history database and ruleset export files with AES-256-GCM; encrypted files are decrypted on read")
 	rootCmd.Flags().StringSliceVarP(&sections, "sections", "s", nil, "Specific sections to inspect \n(rulesets, collaborators, teams, security, settings, labels, milestones)")
 }
 
 func runInspect(cmd *cobra.Command, args []string) error {
 	// Show help when no subcommand is provided
 	return cmd.Help()
-}
\ No newline at end of file
+}
+// resolvedTargetEnterprise returns the enterprise whose policies apply to the target org
+func resolvedTargetEnterprise() string {
+	if targetEnterpriseSlug != "" {
+		return targetEnterpriseSlug
+	}
+	return enterpriseSlug
+}
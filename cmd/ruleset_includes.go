@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+
+	"github.com/jefeish/gh-repo-transfer/internal/ghclient"
+	"github.com/jefeish/gh-repo-transfer/pkg/utils"
+)
+
+// repositoryNameCondition is the repository_name condition of an org ruleset
+type repositoryNameCondition struct {
+	Include   []string `json:"include"`
+	Exclude   []string `json:"exclude"`
+	Protected bool     `json:"protected,omitempty"`
+}
+
+// alignRulesetIncludes finds the target org rulesets that would apply to a repository under its
+// original name but not under its archived name. With --patch-ruleset-includes the archived name
+// is added to their include list; otherwise every affected ruleset is reported.
+func alignRulesetIncludes(client api.RESTClient, org, originalName, archivedName string, patch bool) error {
+	if originalName == archivedName {
+		return nil
+	}
+
+	var summaries []struct {
+		ID   int64  `json:"id"`
+		Name string `json:"name"`
+	}
+	if err := ghclient.GetAll(&client, fmt.Sprintf("orgs/%s/rulesets", org), &summaries); err != nil {
+		return fmt.Errorf("failed to list org rulesets of %s: %v", org, err)
+	}
+
+	for _, summary := range summaries {
+		endpoint := fmt.Sprintf("orgs/%s/rulesets/%d", org, summary.ID)
+		var ruleset struct {
+			Conditions map[string]json.RawMessage `json:"conditions"`
+		}
+		if err := client.Get(endpoint, &ruleset); err != nil {
+			return fmt.Errorf("failed to read ruleset '%s': %v", summary.Name, err)
+		}
+
+		raw, ok := ruleset.Conditions["repository_name"]
+		if !ok {
+			continue
+		}
+		var condition repositoryNameCondition
+		if err := json.Unmarshal(raw, &condition); err != nil {
+			return fmt.Errorf("failed to parse conditions of ruleset '%s': %v", summary.Name, err)
+		}
+		if !utils.RulesetCoversName(condition.Include, condition.Exclude, originalName) ||
+			utils.RulesetCoversName(condition.Include, condition.Exclude, archivedName) {
+			continue
+		}
+
+		if !patch {
+			fmt.Fprintf(os.Stderr, "⚠️  Warning: Org ruleset '%s' in %s covers '%s' but not the archived name '%s' (include: %v, exclude: %v); use --patch-ruleset-includes to add it\n",
+				summary.Name, org, originalName, archivedName, condition.Include, condition.Exclude)
+			continue
+		}
+
+		condition.Include = append(condition.Include, archivedName)
+		if !utils.RulesetCoversName(condition.Include, condition.Exclude, archivedName) {
+			fmt.Fprintf(os.Stderr, "⚠️  Warning: Org ruleset '%s' in %s excludes the archived name '%s' (exclude: %v); not patched\n",
+				summary.Name, org, archivedName, condition.Exclude)
+			continue
+		}
+
+		data, err := json.Marshal(condition)
+		if err != nil {
+			return fmt.Errorf("failed to marshal ruleset conditions: %v", err)
+		}
+		ruleset.Conditions["repository_name"] = data
+		payloadBytes, err := json.Marshal(map[string]interface{}{"conditions": ruleset.Conditions})
+		if err != nil {
+			return fmt.Errorf("failed to marshal ruleset conditions: %v", err)
+		}
+
+		var response map[string]interface{}
+		if err := client.Put(endpoint, bytes.NewBuffer(payloadBytes), &response); err != nil {
+			return fmt.Errorf("failed to update ruleset '%s': %v", summary.Name, err)
+		}
+		fmt.Printf("   └─ 📏 Added '%s' to the repository names of org ruleset '%s'\n", archivedName, summary.Name)
+	}
+
+	return nil
+}
@@ -0,0 +1,331 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/jefeish/gh-repo-transfer/internal/encryption"
+	"github.com/jefeish/gh-repo-transfer/internal/ghclient"
+	"github.com/jefeish/gh-repo-transfer/internal/rulesets"
+)
+
+var (
+	rulesetsOrg      string
+	rulesetsFile     string
+	rulesetsActorMap string
+	rulesetsReplace  bool
+)
+
+// rulesetsCmd groups the org ruleset export and import commands
+var rulesetsCmd = &cobra.Command{
+	Use:   "rulesets",
+	Short: "Export org rulesets and recreate them in another organization",
+	Long: `Export the rulesets of an organization to a JSON file and import them into another
+organization, so the target enforces the same rules before repositories arrive.
+
+Bypass actors are organization specific. The export records the slug of every team and
+app and the name of every custom repository role; the import looks them up in the target
+and drops (with a warning) any actor it cannot find. Use --actor-map to rename actors
+between organizations.
+
+  gh repo-transfer rulesets export --org source-org --file rulesets.json
+  gh repo-transfer rulesets import --org target-org --file rulesets.json --dry-run`,
+}
+
+var rulesetsExportCmd = &cobra.Command{
+	Use:   "export --org source-org [--file rulesets.json]",
+	Short: "Write the rulesets of an organization to a JSON file",
+	Args:  cobra.NoArgs,
+	RunE:  runRulesetsExport,
+}
+
+var rulesetsImportCmd = &cobra.Command{
+	Use:   "import --org target-org --file rulesets.json [--actor-map map.yaml] [--replace]",
+	Short: "Create exported rulesets in an organization",
+	Args:  cobra.NoArgs,
+	RunE:  runRulesetsImport,
+}
+
+func init() {
+	rootCmd.AddCommand(rulesetsCmd)
+	rulesetsCmd.AddCommand(rulesetsExportCmd)
+	rulesetsCmd.AddCommand(rulesetsImportCmd)
+
+	rulesetsCmd.PersistentFlags().StringVar(&rulesetsOrg, "org", "", "Organization to export rulesets from or import them into")
+	rulesetsExportCmd.Flags().StringVar(&rulesetsFile, "file", "", "File to write the export to (default stdout)")
+	rulesetsImportCmd.Flags().StringVar(&rulesetsFile, "file", "", "Export file to import")
+	rulesetsImportCmd.Flags().StringVar(&rulesetsActorMap, "actor-map", "", "YAML file renaming bypass actors: teams, apps and roles maps of source name to target name")
+	rulesetsImportCmd.Flags().BoolVar(&rulesetsReplace, "replace", false, "Update rulesets that already exist in the target under the same name instead of skipping them")
+	rulesetsCmd.MarkPersistentFlagRequired("org")
+	rulesetsImportCmd.MarkFlagRequired("file")
+}
+
+// orgRulesetSummary is an entry of the org rulesets list
+type orgRulesetSummary struct {
+	ID     int64  `json:"id"`
+	Name   string `json:"name"`
+	Source string `json:"source_type"`
+}
+
+func runRulesetsExport(cmd *cobra.Command, args []string) error {
+	client, err := ghclient.NewRESTClient()
+	if err != nil {
+		return fmt.Errorf("failed to create API client: %v", err)
+	}
+
+	summaries, err := listOrgRulesets(*client, rulesetsOrg)
+	if err != nil {
+		return err
+	}
+
+	teams, apps, roles := sourceActorNames(*client, rulesetsOrg)
+	export := rulesets.Export{Organization: rulesetsOrg, ExportedAt: time.Now().UTC()}
+	for _, summary := range summaries {
+		if summary.Source != "" && summary.Source != "Organization" {
+			continue
+		}
+		var ruleset rulesets.Ruleset
+		if err := client.Get(fmt.Sprintf("orgs/%s/rulesets/%d", rulesetsOrg, summary.ID), &ruleset); err != nil {
+			return fmt.Errorf("failed to read ruleset '%s': %v", summary.Name, err)
+		}
+		ruleset.BypassActors = rulesets.NameActors(ruleset.BypassActors, teams, apps, roles)
+		export.Rulesets = append(export.Rulesets, ruleset)
+		if verbose {
+			fmt.Fprintf(os.Stderr, "Exported ruleset '%s' (%d rules, %d bypass actors)\n", ruleset.Name, len(ruleset.Rules), len(ruleset.BypassActors))
+		}
+	}
+
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal rulesets: %v", err)
+	}
+	data = append(data, '\n')
+	if rulesetsFile == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	if data, err = encryption.Encrypt(data); err != nil {
+		return fmt.Errorf("failed to encrypt rulesets: %v", err)
+	}
+	if err := os.WriteFile(rulesetsFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %v", rulesetsFile, err)
+	}
+	fmt.Fprintf(os.Stderr, "📏 Exported %d rulesets from %s to %s\n", len(export.Rulesets), rulesetsOrg, rulesetsFile)
+	return nil
+}
+
+func runRulesetsImport(cmd *cobra.Command, args []string) error {
+	data, err := encryption.ReadFile(rulesetsFile)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %v", rulesetsFile, err)
+	}
+	var export rulesets.Export
+	if err := json.Unmarshal(data, &export); err != nil {
+		return fmt.Errorf("failed to parse %s: %v", rulesetsFile, err)
+	}
+
+	mapping, err := loadActorMap(rulesetsActorMap)
+	if err != nil {
+		return err
+	}
+
+	client, err := ghclient.NewRESTClient()
+	if err != nil {
+		return fmt.Errorf("failed to create API client: %v", err)
+	}
+
+	summaries, err := listOrgRulesets(*client, rulesetsOrg)
+	if err != nil {
+		return err
+	}
+	existing := make(map[string]int64)
+	for _, summary := range summaries {
+		existing[strings.ToLower(summary.Name)] = summary.ID
+	}
+	target := targetActorIDs(*client, rulesetsOrg)
+
+	if dryRun {
+		fmt.Printf("🔍 DRY RUN: Importing %d rulesets from %s into %s\n", len(export.Rulesets), export.Organization, rulesetsOrg)
+	}
+
+	failed := 0
+	for _, ruleset := range export.Rulesets {
+		conditions, warnings, portable := rulesets.PortableConditions(ruleset.Conditions)
+		if !portable {
+			fmt.Fprintf(os.Stderr, "⚠️  Warning: Ruleset '%s' only selects repositories by ID; skipped\n", ruleset.Name)
+			continue
+		}
+		actors, actorWarnings := rulesets.RemapActors(ruleset.BypassActors, mapping, target)
+		warnings = append(warnings, actorWarnings...)
+		for _, rule := range rulesets.OrganizationSpecificRules(ruleset.Rules) {
+			warnings = append(warnings, fmt.Sprintf("rule '%s' references source repository or app IDs; review it after the import", rule))
+		}
+		for _, warning := range warnings {
+			fmt.Fprintf(os.Stderr, "⚠️  Warning: Ruleset '%s': %s\n", ruleset.Name, warning)
+		}
+
+		id, exists := existing[strings.ToLower(ruleset.Name)]
+		action := "create"
+		if exists {
+			if !rulesetsReplace {
+				fmt.Printf("   ⏭️  Ruleset '%s' already exists in %s; skipped (use --replace to update it)\n", ruleset.Name, rulesetsOrg)
+				continue
+			}
+			action = "update"
+		}
+		if dryRun {
+			fmt.Printf("   Would %s ruleset '%s' (%s, %d rules, %d bypass actors)\n", action, ruleset.Name, ruleset.Enforcement, len(ruleset.Rules), len(actors))
+			continue
+		}
+
+		if err := putOrgRuleset(*client, rulesetsOrg, id, exists, ruleset, conditions, actors); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Failed to %s ruleset '%s': %v\n", action, ruleset.Name, err)
+			failed++
+			continue
+		}
+		done := "Created"
+		if exists {
+			done = "Updated"
+		}
+		fmt.Printf("   ✅ %s ruleset '%s' in %s\n", done, ruleset.Name, rulesetsOrg)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d rulesets could not be imported into %s", failed, len(export.Rulesets), rulesetsOrg)
+	}
+	return nil
+}
+
+// listOrgRulesets lists the rulesets of an organization
+func listOrgRulesets(client api.RESTClient, org string) ([]orgRulesetSummary, error) {
+	var all []orgRulesetSummary
+	if err := ghclient.GetAll(&client, fmt.Sprintf("orgs/%s/rulesets", org), &all); err != nil {
+		return nil, fmt.Errorf("failed to list org rulesets of %s: %v", org, err)
+	}
+	return all, nil
+}
+
+// putOrgRuleset creates a ruleset, or updates the existing one with the same name
+func putOrgRuleset(client api.RESTClient, org string, id int64, exists bool, ruleset rulesets.Ruleset, conditions map[string]json.RawMessage, actors []rulesets.BypassActor) error {
+	payloadBytes, err := rulesetPayload(ruleset, conditions, actors)
+	if err != nil {
+		return err
+	}
+
+	var response map[string]interface{}
+	if exists {
+		return client.Put(fmt.Sprintf("orgs/%s/rulesets/%d", org, id), bytes.NewBuffer(payloadBytes), &response)
+	}
+	return client.Post(fmt.Sprintf("orgs/%s/rulesets", org), bytes.NewBuffer(payloadBytes), &response)
+}
+
+// rulesetPayload is the body creating or updating a ruleset with the given conditions and actors
+func rulesetPayload(ruleset rulesets.Ruleset, conditions map[string]json.RawMessage, actors []rulesets.BypassActor) ([]byte, error) {
+	bypass := make([]map[string]interface{}, 0, len(actors))
+	for _, actor := range actors {
+		bypass = append(bypass, map[string]interface{}{
+			"actor_id":    actor.ActorID,
+			"actor_type":  actor.ActorType,
+			"bypass_mode": actor.BypassMode,
+		})
+	}
+	payload := map[string]interface{}{
+		"name":          ruleset.Name,
+		"enforcement":   ruleset.Enforcement,
+		"conditions":    conditions,
+		"rules":         ruleset.Rules,
+		"bypass_actors": bypass,
+	}
+	if ruleset.Target != "" {
+		payload["target"] = ruleset.Target
+	}
+	return json.Marshal(payload)
+}
+
+// loadActorMap reads the --actor-map YAML file
+func loadActorMap(path string) (rulesets.ActorMap, error) {
+	var mapping rulesets.ActorMap
+	if path == "" {
+		return mapping, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return mapping, fmt.Errorf("failed to read actor map %s: %v", path, err)
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&mapping); err != nil {
+		return mapping, fmt.Errorf("failed to parse actor map %s: %v", path, err)
+	}
+	return mapping, nil
+}
+
+// orgActor is a team, app installation or custom role of an organization
+type orgActor struct {
+	ID      int64  `json:"id"`
+	Slug    string `json:"slug"`
+	Name    string `json:"name"`
+	AppID   int64  `json:"app_id"`
+	AppSlug string `json:"app_slug"`
+}
+
+// listOrgActors lists the teams, app installations and custom repository roles of an org.
+// Lists that cannot be read (e.g. missing admin scope) are reported and left empty.
+func listOrgActors(client api.RESTClient, org string) (teams, apps, roles []orgActor) {
+	if err := ghclient.GetAll(&client, fmt.Sprintf("orgs/%s/teams", org), &teams); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: failed to list teams of %s: %v\n", org, err)
+	}
+
+	if err := ghclient.GetAllField(&client, fmt.Sprintf("orgs/%s/installations", org), "installations", &apps); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: failed to list app installations of %s: %v\n", org, err)
+	}
+
+	var customRoles struct {
+		CustomRoles []orgActor `json:"custom_roles"`
+	}
+	if err := client.Get(fmt.Sprintf("orgs/%s/custom-repository-roles", org), &customRoles); err != nil && verbose {
+		fmt.Fprintf(os.Stderr, "Could not list custom repository roles of %s: %v\n", org, err)
+	}
+	roles = customRoles.CustomRoles
+	return teams, apps, roles
+}
+
+// sourceActorNames maps the actor IDs of the source org to names that exist in any org
+func sourceActorNames(client api.RESTClient, org string) (teams, apps, roles map[int64]string) {
+	teams, apps, roles = make(map[int64]string), make(map[int64]string), make(map[int64]string)
+	teamList, appList, roleList := listOrgActors(client, org)
+	for _, team := range teamList {
+		teams[team.ID] = team.Slug
+	}
+	for _, app := range appList {
+		apps[app.AppID] = app.AppSlug
+	}
+	for _, role := range roleList {
+		roles[role.ID] = role.Name
+	}
+	return teams, apps, roles
+}
+
+// targetActorIDs maps the names of the target org's actors to their IDs
+func targetActorIDs(client api.RESTClient, org string) rulesets.TargetActors {
+	target := rulesets.TargetActors{Teams: make(map[string]int64), Apps: make(map[string]int64), Roles: make(map[string]int64)}
+	teamList, appList, roleList := listOrgActors(client, org)
+	for _, team := range teamList {
+		target.Teams[strings.ToLower(team.Slug)] = team.ID
+	}
+	for _, app := range appList {
+		target.Apps[strings.ToLower(app.AppSlug)] = app.AppID
+	}
+	for _, role := range roleList {
+		target.Roles[strings.ToLower(role.Name)] = role.ID
+	}
+	return target
+}
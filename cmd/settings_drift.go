@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+// settingsSnapshot captures the repository and security settings at a point in time
+type settingsSnapshot struct {
+	Repo     types.RepoSettings     `json:"repository_settings"`
+	Security types.SecuritySettings `json:"security_settings"`
+}
+
+// settingDrift describes a single setting whose value changed between two snapshots
+type settingDrift struct {
+	Setting  string `json:"setting"`
+	Before   string `json:"before"`
+	After    string `json:"after"`
+	Expected bool   `json:"expected"` // Changed on purpose by a post-transfer step
+}
+
+// snapshotRepositorySettings reads the current RepoSettings/SecuritySettings of a repository
+func snapshotRepositorySettings(client api.RESTClient, owner, repo string) (*settingsSnapshot, error) {
+	var repoInfo struct {
+		Private             bool   `json:"private"`
+		Archived            bool   `json:"archived"`
+		DefaultBranch       string `json:"default_branch"`
+		HasIssues           bool   `json:"has_issues"`
+		HasProjects         bool   `json:"has_projects"`
+		HasWiki             bool   `json:"has_wiki"`
+		AllowMergeCommit    bool   `json:"allow_merge_commit"`
+		AllowSquashMerge    bool   `json:"allow_squash_merge"`
+		AllowRebaseMerge    bool   `json:"allow_rebase_merge"`
+		DeleteBranchOnMerge bool   `json:"delete_branch_on_merge"`
+		SecurityAndAnalysis struct {
+			SecretScanning struct {
+				Status string `json:"status"`
+			} `json:"secret_scanning"`
+			SecretScanningPushProtection struct {
+				Status string `json:"status"`
+			} `json:"secret_scanning_push_protection"`
+		} `json:"security_and_analysis"`
+	}
+
+	err := client.Get(fmt.Sprintf("repos/%s/%s", owner, repo), &repoInfo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repository settings: %v", err)
+	}
+
+	snapshot := &settingsSnapshot{
+		Repo: types.RepoSettings{
+			Private:             repoInfo.Private,
+			Archived:            repoInfo.Archived,
+			DefaultBranch:       repoInfo.DefaultBranch,
+			HasIssues:           repoInfo.HasIssues,
+			HasProjects:         repoInfo.HasProjects,
+			HasWiki:             repoInfo.HasWiki,
+			AllowMergeCommit:    repoInfo.AllowMergeCommit,
+			AllowSquashMerge:    repoInfo.AllowSquashMerge,
+			AllowRebaseMerge:    repoInfo.AllowRebaseMerge,
+			DeleteBranchOnMerge: repoInfo.DeleteBranchOnMerge,
+		},
+		Security: types.SecuritySettings{
+			SecretScanning:               repoInfo.SecurityAndAnalysis.SecretScanning.Status == "enabled",
+			SecretScanningPushProtection: repoInfo.SecurityAndAnalysis.SecretScanningPushProtection.Status == "enabled",
+		},
+	}
+
+	// Vulnerability alerts return 204 when enabled and 404 when disabled
+	if err := client.Get(fmt.Sprintf("repos/%s/%s/vulnerability-alerts", owner, repo), nil); err == nil {
+		snapshot.Security.VulnerabilityAlerts = true
+	}
+
+	var fixes struct {
+		Enabled bool `json:"enabled"`
+	}
+	if err := client.Get(fmt.Sprintf("repos/%s/%s/automated-security-fixes", owner, repo), &fixes); err == nil {
+		snapshot.Security.AutomatedSecurityFixes = fixes.Enabled
+	}
+
+	return snapshot, nil
+}
+
+// diffSettingsSnapshots lists every setting that differs between the two snapshots.
+// Setting names use the json tags of RepoSettings/SecuritySettings.
+func diffSettingsSnapshots(before, after *settingsSnapshot) []settingDrift {
+	if before == nil || after == nil {
+		return nil
+	}
+
+	drifts := diffStructFields(reflect.ValueOf(before.Repo), reflect.ValueOf(after.Repo))
+	drifts = append(drifts, diffStructFields(reflect.ValueOf(before.Security), reflect.ValueOf(after.Security))...)
+	return drifts
+}
+
+func diffStructFields(before, after reflect.Value) []settingDrift {
+	var drifts []settingDrift
+	for i := 0; i < before.NumField(); i++ {
+		field := before.Type().Field(i)
+		beforeValue := fmt.Sprintf("%v", before.Field(i).Interface())
+		afterValue := fmt.Sprintf("%v", after.Field(i).Interface())
+		if beforeValue == afterValue {
+			continue
+		}
+
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "" {
+			name = field.Name
+		}
+		drifts = append(drifts, settingDrift{Setting: name, Before: beforeValue, After: afterValue})
+	}
+	return drifts
+}
+
+// markExpectedDrift flags changes that were requested on purpose (settings profile, default branch,
+// archive flag) so the report only highlights what GitHub changed implicitly
+func markExpectedDrift(drifts []settingDrift, profile *repoSettingsProfile, archived bool) []settingDrift {
+	intended := make(map[string]bool)
+	if profile != nil {
+		for key := range profile.settingsPayload() {
+			intended[key] = true
+		}
+	}
+	if defaultBranch != "" {
+		intended["default_branch"] = true
+	}
+	if archived {
+		intended["archived"] = true
+	}
+
+	for i := range drifts {
+		drifts[i].Expected = intended[drifts[i].Setting]
+	}
+	return drifts
+}
+
+// printSettingsDriftReport prints the drift between the pre-transfer snapshot and the live repository
+func printSettingsDriftReport(repository string, drifts []settingDrift) {
+	fmt.Printf("🔎 Settings drift report: %s\n", repository)
+
+	implicit := 0
+	for _, drift := range drifts {
+		if !drift.Expected {
+			implicit++
+		}
+	}
+
+	if len(drifts) == 0 {
+		fmt.Printf("   └─ ✅ No settings changed during the move\n")
+		return
+	}
+
+	for i, drift := range drifts {
+		prefix := "├─"
+		if i == len(drifts)-1 {
+			prefix = "└─"
+		}
+		marker := "⚠️ "
+		note := "changed implicitly"
+		if drift.Expected {
+			marker = "✅"
+			note = "requested"
+		}
+		fmt.Printf("   %s %s %s: %s → %s (%s)\n", prefix, marker, drift.Setting, drift.Before, drift.After, note)
+	}
+
+	if implicit > 0 {
+		fmt.Fprintf(os.Stderr, "⚠️  %d setting(s) on %s were changed implicitly by the move\n", implicit, repository)
+	}
+}
+
+// verificationStatus summarizes a drift report for the migration history
+func verificationStatus(drifts []settingDrift) string {
+	for _, drift := range drifts {
+		if !drift.Expected {
+			return "drifted"
+		}
+	}
+	return "clean"
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"gopkg.in/yaml.v3"
+)
+
+// repoSettingsProfile describes the repository settings standard required by a destination org.
+// Fields left out of the profile file are not changed on the repository.
+type repoSettingsProfile struct {
+	AllowSquashMerge    *bool `yaml:"allow_squash_merge" json:"allow_squash_merge,omitempty"`
+	AllowRebaseMerge    *bool `yaml:"allow_rebase_merge" json:"allow_rebase_merge,omitempty"`
+	AllowMergeCommit    *bool `yaml:"allow_merge_commit" json:"allow_merge_commit,omitempty"`
+	AllowAutoMerge      *bool `yaml:"allow_auto_merge" json:"allow_auto_merge,omitempty"`
+	DeleteBranchOnMerge *bool `yaml:"delete_branch_on_merge" json:"delete_branch_on_merge,omitempty"`
+	HasIssues           *bool `yaml:"has_issues" json:"has_issues,omitempty"`
+	HasWiki             *bool `yaml:"has_wiki" json:"has_wiki,omitempty"`
+	HasProjects         *bool `yaml:"has_projects" json:"has_projects,omitempty"`
+	HasDiscussions      *bool `yaml:"has_discussions" json:"has_discussions,omitempty"`
+}
+
+// loadedSettingsProfile holds the profile loaded from --apply-settings-profile for the current run
+var loadedSettingsProfile *repoSettingsProfile
+
+// loadSettingsProfile reads and validates a settings profile YAML file
+func loadSettingsProfile(path string) (*repoSettingsProfile, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read settings profile %s: %v", path, err)
+	}
+
+	var profile repoSettingsProfile
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&profile); err != nil && !errors.Is(err, io.EOF) { // An empty profile changes nothing
+		return nil, fmt.Errorf("failed to parse settings profile %s: %v", path, err)
+	}
+
+	if profile.AllowSquashMerge != nil && profile.AllowRebaseMerge != nil && profile.AllowMergeCommit != nil &&
+		!*profile.AllowSquashMerge && !*profile.AllowRebaseMerge && !*profile.AllowMergeCommit {
+		return nil, fmt.Errorf("settings profile %s disables every merge method; at least one must be allowed", path)
+	}
+
+	return &profile, nil
+}
+
+// settingsPayload converts the profile into a PATCH /repos payload containing only the configured fields
+func (p *repoSettingsProfile) settingsPayload() map[string]interface{} {
+	payload := make(map[string]interface{})
+
+	data, err := json.Marshal(p)
+	if err != nil {
+		return payload
+	}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return map[string]interface{}{}
+	}
+
+	return payload
+}
+
+// applySettingsProfile aligns merge settings and feature toggles of a repository with the profile
+func applySettingsProfile(client api.RESTClient, owner, repo string, profile *repoSettingsProfile, verboseOutput bool) error {
+	if profile == nil {
+		return nil
+	}
+
+	payload := profile.settingsPayload()
+	if len(payload) == 0 {
+		return nil
+	}
+
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal settings payload: %v", err)
+	}
+
+	if verboseOutput {
+		keys := make([]string, 0, len(payload))
+		for key := range payload {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		fmt.Fprintf(os.Stderr, "Applying settings profile to %s/%s:\n", owner, repo)
+		for _, key := range keys {
+			fmt.Fprintf(os.Stderr, "  - %s: %v\n", key, payload[key])
+		}
+	}
+
+	var response map[string]interface{}
+	err = client.Patch(fmt.Sprintf("repos/%s/%s", owner, repo), bytes.NewBuffer(payloadBytes), &response)
+	if err != nil {
+		return fmt.Errorf("failed to apply settings profile: %v", err)
+	}
+
+	if verboseOutput {
+		fmt.Fprintf(os.Stderr, "✅ Settings profile applied (%d settings)\n", len(payload))
+	}
+
+	return nil
+}
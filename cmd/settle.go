@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+
+	"github.com/jefeish/gh-repo-transfer/internal/reposize"
+)
+
+// settleInterval is the wait between checks whether a moved repository is available
+const settleInterval = 3 * time.Second
+
+// measureRepository reads the size and largest object of a repository about to be moved and
+// warns when they make the move slow or risky; nil when they could not be read
+func measureRepository(client api.RESTClient, owner, repo string) *reposize.Stats {
+	stats, err := reposize.Measure(client, owner, repo)
+	if err != nil && verbose {
+		fmt.Fprintf(os.Stderr, "Warning: Could not measure %s/%s: %v\n", owner, repo, err)
+	}
+	for _, warning := range stats.Warnings() {
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: %s/%s: %s\n", owner, repo, warning)
+	}
+	return stats
+}
+
+// waitForMovedRepository waits until a transferred repository can be read at its new path, so
+// the steps after the transfer do not run against a move GitHub is still completing. The wait
+// grows with the size of the repository (see reposize.SettleTimeout); minimum is waited in any
+// case. A repository that is not available in time is reported and the steps go ahead.
+func waitForMovedRepository(client api.RESTClient, path string, size *reposize.Stats, minimum time.Duration) {
+	if minimum > 0 {
+		time.Sleep(minimum)
+	}
+	timeout := size.SettleTimeout()
+	deadline := time.Now().Add(timeout)
+	for {
+		var repository struct {
+			FullName string `json:"full_name"`
+		}
+		err := client.Get(fmt.Sprintf("repos/%s", path), &repository)
+		if err == nil {
+			return
+		}
+		if time.Now().After(deadline) {
+			fmt.Fprintf(os.Stderr, "⚠️  Warning: %s was not available %s after the transfer: %v\n", path, timeout, err)
+			fmt.Fprintf(os.Stderr, "   The transfer may still be completing; failed steps can be resumed with --resume.\n")
+			return
+		}
+		if verbose {
+			fmt.Fprintf(os.Stderr, "Waiting for %s to become available...\n", path)
+		}
+		time.Sleep(settleInterval)
+	}
+}
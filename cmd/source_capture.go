@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+
+	"github.com/jefeish/gh-repo-transfer/internal/dependencies"
+	"github.com/jefeish/gh-repo-transfer/internal/journal"
+	"github.com/jefeish/gh-repo-transfer/internal/steps"
+)
+
+// sourceCapture holds what a move to another organization loses, read from the source
+// repository before the transfer, and restores it on the moved repository; transfer and archive
+// share it. Everything but the --verify snapshot is kept in the journal, so a run resumed after
+// the transfer restores it too.
+type sourceCapture struct {
+	settingsBefore    *settingsSnapshot
+	topics            []string
+	envBranchPolicies []environmentBranchPolicy
+	webhooks          []repositoryWebhook
+	branchProtections []branchProtection
+	rulesets          *repositoryRulesets
+	pages             *dependencies.PagesSite
+	actionsConfig     *repositoryActionsConfig
+	environments      []environmentConfig
+}
+
+// captureSteps lists the steps reading owner/repo before the transfer; journalKey is the
+// repository's journal entry. Webhooks, branch protection, rulesets, Pages and environments are
+// critical: moving without them would silently stop deliveries, leave branches or deployments
+// unprotected or the site's build source unknown.
+func (c *sourceCapture) captureSteps(client api.RESTClient, owner, repo, journalKey string, withPages, verboseOutput bool) []steps.Step {
+	// keep records what a step captured in the journal
+	keep := func(step string, captured interface{}) error {
+		return runJournal.SetCaptured(journalKey, step, captured)
+	}
+	// optional reports a capture that failed without stopping the move
+	optional := func(what string, err error) error {
+		if verboseOutput {
+			fmt.Fprintf(os.Stderr, "Warning: Could not %s: %v\n", what, err)
+		}
+		return nil
+	}
+
+	captureSteps := []steps.Step{
+		{Name: "snapshot-settings", Description: "Snapshot source settings for --verify", Skip: !verifySettings, Execute: func() error {
+			snapshot, err := snapshotRepositorySettings(client, owner, repo)
+			if err != nil {
+				return fmt.Errorf("Could not snapshot source settings, drift report disabled: %v", err)
+			}
+			c.settingsBefore = snapshot
+			return nil
+		}},
+		{Name: "capture-topics", Description: "Capture source topics", Execute: func() error {
+			var err error
+			if c.topics, err = getRepositoryTopics(client, owner, repo); err != nil {
+				return optional("retrieve source topics", err)
+			}
+			return keep("capture-topics", c.topics)
+		}},
+		{Name: "capture-environment-policies", Description: "Capture environment deployment branch policies", Execute: func() error {
+			var err error
+			if c.envBranchPolicies, err = captureEnvironmentBranchPolicies(client, owner, repo); err != nil {
+				return optional("capture environment branch policies", err)
+			}
+			return keep("capture-environment-policies", c.envBranchPolicies)
+		}},
+		{Name: "capture-actions-config", Description: "Capture repository Actions variables and secret names", Execute: func() error {
+			var err error
+			if c.actionsConfig, err = captureRepositoryActionsConfig(client, owner, repo); err != nil {
+				return optional("capture Actions variables and secrets", err)
+			}
+			return keep("capture-actions-config", c.actionsConfig)
+		}},
+		{Name: "capture-webhooks", Description: "Capture repository webhooks", Skip: !migrateWebhooks, Critical: true, Execute: func() error {
+			var err error
+			if c.webhooks, err = captureRepositoryWebhooks(client, owner, repo); err != nil {
+				return err
+			}
+			return keep("capture-webhooks", c.webhooks)
+		}},
+		{Name: "capture-branch-protection", Description: "Capture branch protection rules", Skip: !migrateBranchProtection, Critical: true, Execute: func() error {
+			var err error
+			if c.branchProtections, err = captureBranchProtections(client, owner, repo); err != nil {
+				return err
+			}
+			return keep("capture-branch-protection", c.branchProtections)
+		}},
+		{Name: "capture-rulesets", Description: "Capture repository rulesets", Skip: !migrateRulesets, Critical: true, Execute: func() error {
+			var err error
+			if c.rulesets, err = captureRepositoryRulesets(client, owner, repo); err != nil {
+				return err
+			}
+			return keep("capture-rulesets", c.rulesets)
+		}},
+	}
+	if withPages {
+		captureSteps = append(captureSteps, steps.Step{Name: "capture-pages", Description: "Capture the GitHub Pages configuration", Skip: !migratePages, Critical: true, Execute: func() error {
+			var err error
+			if c.pages, err = dependencies.ReadPages(client, owner, repo); err != nil {
+				return err
+			}
+			return keep("capture-pages", c.pages)
+		}})
+	}
+	return append(captureSteps, steps.Step{Name: "capture-environments", Description: "Capture environment protection rules, variables and secret names", Skip: !migrateEnvironments, Critical: true, Execute: func() error {
+		var err error
+		if c.environments, err = captureEnvironments(client, owner, repo); err != nil {
+			return err
+		}
+		return keep("capture-environments", c.environments)
+	}})
+}
+
+// restoreSteps lists the steps restoring what captureSteps read on the moved repository owner/repo
+func (c *sourceCapture) restoreSteps(client api.RESTClient, owner, repo string, withPages, verboseOutput bool) []steps.Step {
+	restoreSteps := []steps.Step{
+		{Name: "topics", Description: "Verify topics and apply --add-topics/--remove-topics", Execute: func() error {
+			if err := verifyAndAugmentTopics(client, c.topics, owner, repo, verboseOutput); err != nil {
+				return fmt.Errorf("Topic update failed: %v", err)
+			}
+			return nil
+		}},
+		{Name: "environments", Description: "Recreate environment protection rules, variables and secrets", Skip: !migrateEnvironments, Execute: func() error {
+			return recreateEnvironments(client, owner, repo, c.environments, verboseOutput)
+		}},
+		{Name: "environment-policies", Description: "Re-apply environment deployment branch policies", Execute: func() error {
+			return reapplyEnvironmentBranchPolicies(client, owner, repo, c.envBranchPolicies, verboseOutput)
+		}},
+		{Name: "actions-config", Description: "Recreate missing Actions variables and report missing secrets", Execute: func() error {
+			return restoreRepositoryActionsConfig(client, owner, repo, c.actionsConfig, verboseOutput)
+		}},
+		{Name: "webhooks", Description: "Recreate repository webhooks", Skip: !migrateWebhooks, Execute: func() error {
+			return recreateRepositoryWebhooks(client, owner, repo, c.webhooks, verboseOutput)
+		}},
+		{Name: "branch-protection", Description: "Re-apply branch protection rules", Skip: !migrateBranchProtection, Execute: func() error {
+			return reapplyBranchProtections(client, owner, repo, c.branchProtections, verboseOutput)
+		}},
+		{Name: "rulesets", Description: "Recreate repository rulesets", Skip: !migrateRulesets, Execute: func() error {
+			return recreateRepositoryRulesets(client, owner, repo, c.rulesets, verboseOutput)
+		}},
+	}
+	if withPages {
+		restoreSteps = append(restoreSteps, steps.Step{Name: "pages", Description: "Restore the GitHub Pages configuration", Skip: !migratePages, Execute: func() error {
+			return restorePages(client, owner, repo, c.pages, verboseOutput)
+		}})
+	}
+	return restoreSteps
+}
+
+// resume restores what the capture steps of an earlier run kept in the journal entry
+func (c *sourceCapture) resume(entry journal.Entry) error {
+	for _, captured := range []struct {
+		step string
+		v    interface{}
+	}{
+		{"capture-topics", &c.topics},
+		{"capture-environment-policies", &c.envBranchPolicies},
+		{"capture-actions-config", &c.actionsConfig},
+		{"capture-webhooks", &c.webhooks},
+		{"capture-branch-protection", &c.branchProtections},
+		{"capture-rulesets", &c.rulesets},
+		{"capture-pages", &c.pages},
+		{"capture-environments", &c.environments},
+	} {
+		if err := restoreCaptured(entry, captured.step, captured.v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,308 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+
+	"github.com/jefeish/gh-repo-transfer/internal/ghclient"
+)
+
+// sourceCleanupResult summarizes what --cleanup-source removed from the source organization
+type sourceCleanupResult struct {
+	RulesetsUpdated []string // Org rulesets that no longer list the repository
+	ProjectItems    int      // Items removed from source org projects
+	TrackingIssues  []string // Open issues in the source org that still mention the repository (not modified)
+}
+
+// cleanupSourceReferences removes references to a moved repository that are left behind in the
+// source organization: org ruleset conditions that list it by name or ID, and items of source org
+// projects pointing at its issues and pull requests. Tracking issues are only reported, since
+// closing issues other people own is not a decision the tool can make. Every step is best effort.
+func cleanupSourceReferences(client api.RESTClient, sourceOrg, repoName string, repoID int64, newPath string) {
+	var result sourceCleanupResult
+
+	rulesets, err := removeRepositoryFromOrgRulesets(client, sourceOrg, repoName, repoID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: Could not clean up org rulesets of %s: %v\n", sourceOrg, err)
+	}
+	result.RulesetsUpdated = rulesets
+
+	items, err := removeSourceProjectItems(sourceOrg, newPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: Could not clean up project items of %s: %v\n", sourceOrg, err)
+	}
+	result.ProjectItems = items
+
+	issues, err := findTrackingIssues(client, sourceOrg, fmt.Sprintf("%s/%s", sourceOrg, repoName))
+	if err != nil && verbose {
+		fmt.Fprintf(os.Stderr, "Warning: Could not search tracking issues in %s: %v\n", sourceOrg, err)
+	}
+	result.TrackingIssues = issues
+
+	printSourceCleanup(sourceOrg, result)
+}
+
+// removeRepositoryFromOrgRulesets drops the repository from the repository_name include list and
+// repository_id list of every org ruleset that names it explicitly. Patterns are left untouched.
+func removeRepositoryFromOrgRulesets(client api.RESTClient, org, repoName string, repoID int64) ([]string, error) {
+	var summaries []struct {
+		ID   int64  `json:"id"`
+		Name string `json:"name"`
+	}
+	if err := ghclient.GetAll(&client, fmt.Sprintf("orgs/%s/rulesets", org), &summaries); err != nil {
+		return nil, err
+	}
+
+	var updated []string
+	for _, summary := range summaries {
+		var ruleset struct {
+			Conditions map[string]json.RawMessage `json:"conditions"`
+		}
+		endpoint := fmt.Sprintf("orgs/%s/rulesets/%d", org, summary.ID)
+		if err := client.Get(endpoint, &ruleset); err != nil {
+			return updated, fmt.Errorf("failed to read ruleset '%s': %v", summary.Name, err)
+		}
+
+		changed, err := removeRepositoryFromConditions(ruleset.Conditions, repoName, repoID)
+		if err != nil {
+			return updated, fmt.Errorf("failed to parse conditions of ruleset '%s': %v", summary.Name, err)
+		}
+		if !changed {
+			continue
+		}
+
+		payloadBytes, err := json.Marshal(map[string]interface{}{"conditions": ruleset.Conditions})
+		if err != nil {
+			return updated, fmt.Errorf("failed to marshal ruleset conditions: %v", err)
+		}
+		var response map[string]interface{}
+		if err := client.Put(endpoint, bytes.NewBuffer(payloadBytes), &response); err != nil {
+			return updated, fmt.Errorf("failed to update ruleset '%s': %v", summary.Name, err)
+		}
+		updated = append(updated, summary.Name)
+	}
+	return updated, nil
+}
+
+// removeRepositoryFromConditions edits ruleset conditions in place and reports whether they changed
+func removeRepositoryFromConditions(conditions map[string]json.RawMessage, repoName string, repoID int64) (bool, error) {
+	changed := false
+
+	if raw, ok := conditions["repository_name"]; ok {
+		var byName map[string]interface{}
+		if err := json.Unmarshal(raw, &byName); err != nil {
+			return false, err
+		}
+		if include, ok := byName["include"].([]interface{}); ok {
+			var kept []interface{}
+			for _, entry := range include {
+				if name, ok := entry.(string); ok && strings.EqualFold(name, repoName) {
+					continue
+				}
+				kept = append(kept, entry)
+			}
+			if len(kept) != len(include) {
+				byName["include"] = append([]interface{}{}, kept...)
+				data, err := json.Marshal(byName)
+				if err != nil {
+					return false, err
+				}
+				conditions["repository_name"] = data
+				changed = true
+			}
+		}
+	}
+
+	if raw, ok := conditions["repository_id"]; ok && repoID != 0 {
+		var byID struct {
+			RepositoryIDs []int64 `json:"repository_ids"`
+		}
+		if err := json.Unmarshal(raw, &byID); err != nil {
+			return false, err
+		}
+		var kept []int64
+		for _, id := range byID.RepositoryIDs {
+			if id != repoID {
+				kept = append(kept, id)
+			}
+		}
+		if len(kept) != len(byID.RepositoryIDs) {
+			byID.RepositoryIDs = append([]int64{}, kept...)
+			data, err := json.Marshal(byID)
+			if err != nil {
+				return false, err
+			}
+			conditions["repository_id"] = data
+			changed = true
+		}
+	}
+
+	return changed, nil
+}
+
+// removeSourceProjectItems deletes items of the source org's projects whose issue or pull request
+// now lives in the moved repository
+func removeSourceProjectItems(org, newPath string) (int, error) {
+	client, err := ghclient.NewGraphQLClient()
+	if err != nil {
+		return 0, fmt.Errorf("failed to create GraphQL client: %v", err)
+	}
+
+	projects, err := listSourceProjects(client, org)
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	mutation := `mutation($project: ID!, $item: ID!) { deleteProjectV2Item(input: {projectId: $project, itemId: $item}) { deletedItemId } }`
+	for _, project := range projects {
+		// Items are collected before any is deleted, which would shift the pages
+		items, err := listMovedProjectItems(client, project, newPath)
+		if err != nil {
+			return removed, err
+		}
+		for _, item := range items {
+			var deleted map[string]interface{}
+			if err := client.Do(mutation, map[string]interface{}{"project": project, "item": item}, &deleted); err != nil {
+				return removed, fmt.Errorf("failed to remove project item: %v", err)
+			}
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+// graphQLPageInfo is the pageInfo of a GraphQL connection
+type graphQLPageInfo struct {
+	HasNextPage bool   `json:"hasNextPage"`
+	EndCursor   string `json:"endCursor"`
+}
+
+// listSourceProjects returns the IDs of all projects of the organization
+func listSourceProjects(client *api.GraphQLClient, org string) ([]string, error) {
+	query := `query($org: String!, $after: String) {
+  organization(login: $org) {
+    projectsV2(first: 50, after: $after) {
+      pageInfo { hasNextPage endCursor }
+      nodes { id }
+    }
+  }
+}`
+	var projects []string
+	variables := map[string]interface{}{"org": org, "after": nil}
+	for {
+		var response struct {
+			Organization struct {
+				ProjectsV2 struct {
+					PageInfo graphQLPageInfo `json:"pageInfo"`
+					Nodes    []struct {
+						ID string `json:"id"`
+					} `json:"nodes"`
+				} `json:"projectsV2"`
+			} `json:"organization"`
+		}
+		if err := client.Do(query, variables, &response); err != nil {
+			return nil, err
+		}
+		for _, project := range response.Organization.ProjectsV2.Nodes {
+			projects = append(projects, project.ID)
+		}
+		if !response.Organization.ProjectsV2.PageInfo.HasNextPage {
+			return projects, nil
+		}
+		variables["after"] = response.Organization.ProjectsV2.PageInfo.EndCursor
+	}
+}
+
+// listMovedProjectItems returns the IDs of the project's items whose issue or pull request
+// lives in the repository at newPath
+func listMovedProjectItems(client *api.GraphQLClient, project, newPath string) ([]string, error) {
+	query := `query($project: ID!, $after: String) {
+  node(id: $project) {
+    ... on ProjectV2 {
+      items(first: 100, after: $after) {
+        pageInfo { hasNextPage endCursor }
+        nodes {
+          id
+          content {
+            ... on Issue { repository { nameWithOwner } }
+            ... on PullRequest { repository { nameWithOwner } }
+          }
+        }
+      }
+    }
+  }
+}`
+	var items []string
+	variables := map[string]interface{}{"project": project, "after": nil}
+	for {
+		var response struct {
+			Node struct {
+				Items struct {
+					PageInfo graphQLPageInfo `json:"pageInfo"`
+					Nodes    []struct {
+						ID      string `json:"id"`
+						Content struct {
+							Repository struct {
+								NameWithOwner string `json:"nameWithOwner"`
+							} `json:"repository"`
+						} `json:"content"`
+					} `json:"nodes"`
+				} `json:"items"`
+			} `json:"node"`
+		}
+		if err := client.Do(query, variables, &response); err != nil {
+			return nil, err
+		}
+		for _, item := range response.Node.Items.Nodes {
+			if strings.EqualFold(item.Content.Repository.NameWithOwner, newPath) {
+				items = append(items, item.ID)
+			}
+		}
+		if !response.Node.Items.PageInfo.HasNextPage {
+			return items, nil
+		}
+		variables["after"] = response.Node.Items.PageInfo.EndCursor
+	}
+}
+
+// findTrackingIssues lists open issues in the source org that mention the repository's old path
+func findTrackingIssues(client api.RESTClient, org, oldPath string) ([]string, error) {
+	var response struct {
+		Items []struct {
+			Title   string `json:"title"`
+			HTMLURL string `json:"html_url"`
+		} `json:"items"`
+	}
+	query := fmt.Sprintf("search/issues?q=%%22%s%%22+org:%s+is:issue+is:open&per_page=20", oldPath, org)
+	if err := client.Get(query, &response); err != nil {
+		return nil, err
+	}
+
+	var issues []string
+	for _, item := range response.Items {
+		issues = append(issues, fmt.Sprintf("%s (%s)", item.Title, item.HTMLURL))
+	}
+	return issues, nil
+}
+
+func printSourceCleanup(sourceOrg string, result sourceCleanupResult) {
+	fmt.Printf("🧹 Source cleanup: %s\n", sourceOrg)
+	for _, ruleset := range result.RulesetsUpdated {
+		fmt.Printf("   ├─ Removed from org ruleset '%s'\n", ruleset)
+	}
+	fmt.Printf("   ├─ Removed %d project item(s)\n", result.ProjectItems)
+	if len(result.TrackingIssues) == 0 {
+		fmt.Printf("   └─ No open tracking issues mention the repository\n")
+		return
+	}
+	fmt.Printf("   └─ %d open issue(s) still mention the repository (review and close manually):\n", len(result.TrackingIssues))
+	for _, issue := range result.TrackingIssues {
+		fmt.Printf("      • %s\n", issue)
+	}
+}
@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+
+	"github.com/jefeish/gh-repo-transfer/internal/state"
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+	"github.com/jefeish/gh-repo-transfer/internal/validation"
+)
+
+// runState holds the state file loaded from --state-file for the current run (nil when unset)
+var runState *state.State
+
+// stopInterruptHandler stops the interrupt handling of watchInterrupts (nil when not started)
+var stopInterruptHandler func()
+
+// loadRunState locks and loads the state file named by --state-file; saveRunState releases
+// the lock. An interrupted run saves what it recorded so far, such as scheduled archives,
+// closes the history database and releases the lock too, so the next run is not locked out.
+func loadRunState() error {
+	var err error
+	runState, err = state.LoadLocked(stateFilePath)
+	if err != nil || runState == nil {
+		return err
+	}
+	watchInterrupts()
+	return nil
+}
+
+// watchInterrupts makes an interrupt save the state file and close the history database before
+// exiting, so neither keeps its lock and no decrypted history copy is left behind
+func watchInterrupts() {
+	if stopInterruptHandler != nil {
+		return
+	}
+	interrupted := make(chan os.Signal, 1)
+	signal.Notify(interrupted, os.Interrupt, syscall.SIGTERM)
+	stopInterruptHandler = func() {
+		signal.Stop(interrupted)
+		close(interrupted)
+	}
+	go func() {
+		if _, ok := <-interrupted; ok {
+			if runState != nil {
+				fmt.Fprintf(os.Stderr, "\n⚠️  Interrupted: saving %s before exiting\n", stateFilePath)
+			}
+			if err := runState.Save(); err != nil {
+				fmt.Fprintf(os.Stderr, "⚠️  Warning: %v\n", err)
+			}
+			closeHistoryStore()
+			runState.Unlock()
+			os.Exit(130)
+		}
+	}()
+}
+
+// stopWatchingInterrupts stops the interrupt handling once neither the state file nor the
+// history database is held any more
+func stopWatchingInterrupts() {
+	if stopInterruptHandler == nil || historyStore != nil || runState.Locked() {
+		return
+	}
+	stopInterruptHandler()
+	stopInterruptHandler = nil
+}
+
+// readRunState loads the state file named by --state-file without locking it, for commands
+// that only read it
+func readRunState() error {
+	var err error
+	runState, err = state.Load(stateFilePath)
+	return err
+}
+
+// saveRunState persists the state file and releases its lock; failures only produce a warning
+// since the repository operations themselves have already completed
+func saveRunState() {
+	if err := runState.Save(); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: %v\n", err)
+	}
+	if err := runState.Unlock(); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: %v\n", err)
+	}
+	stopWatchingInterrupts()
+}
+
+// validateWithState validates a repository, reusing the cached result from the state file when possible
+func validateWithState(deps *types.OrganizationalDependencies, capabilities *types.TargetOrgCapabilities, assignTeams bool) *types.MigrationValidation {
+	result, cached := validation.ValidateAgainstTargetCached(deps, capabilities, assignTeams, runState, revalidate)
+	if cached && verbose {
+		fmt.Fprintf(os.Stderr, "♻️  Reusing cached validation for %s (dependencies and target unchanged)\n", deps.Repository)
+	}
+	recordValidationHistory(deps.Repository, result)
+	return result
+}
+
+// validateForPlan validates a repository and records the result in the state file as the
+// reviewed plan baseline that transfer and archive check drift against (deps and plan only)
+func validateForPlan(deps *types.OrganizationalDependencies, capabilities *types.TargetOrgCapabilities, assignTeams bool) *types.MigrationValidation {
+	result := validateWithState(deps, capabilities, assignTeams)
+	validation.RecordPlan(runState, deps.Repository, capabilities.Organization, result)
+	return result
+}
+
+// validateAgainstPlan validates a repository and diffs the result against the plan baseline
+// in the state file, or in the plan file being applied. It returns the drift found since the
+// plan was made; the baseline itself is left as it is, so a regression keeps blocking.
+func validateAgainstPlan(deps *types.OrganizationalDependencies, capabilities *types.TargetOrgCapabilities, assignTeams bool) (*types.MigrationValidation, []validation.ValidationDrift) {
+	result := validateWithState(deps, capabilities, assignTeams)
+	planned := validation.PlannedValidation(runState, deps.Repository, capabilities.Organization)
+	// A plan being applied is the reviewed baseline and takes precedence over the state file
+	if reviewed := plannedValidation(deps.Repository); reviewed != nil {
+		planned = reviewed
+	}
+	return result, validation.DiffValidations(planned, result)
+}
+
+// checkPlanDrift reports validation drift since the plan and returns an error when items
+// regressed, unless --allow-drift downgrades the failure to a warning
+func checkPlanDrift(repository string, drifts []validation.ValidationDrift) error {
+	if len(drifts) == 0 {
+		return nil
+	}
+
+	regressions := 0
+	var lines []string
+	for _, drift := range drifts {
+		before, after := string(drift.Before), string(drift.After)
+		if before == "" {
+			before = "new"
+		}
+		if after == "" {
+			after = "gone"
+		}
+		marker := "✅"
+		if drift.IsRegression() {
+			marker = "❌"
+			regressions++
+		}
+		lines = append(lines, fmt.Sprintf("  %s [%s] %s: %s → %s", marker, drift.Category, drift.Item, before, after))
+	}
+
+	planSource := stateFilePath
+	if appliedPlan != nil {
+		planSource = appliedPlanPath
+	}
+	fmt.Fprintf(os.Stderr, "🔀 Validation drift for %s since the plan in %s:\n%s\n", repository, planSource, strings.Join(lines, "\n"))
+	if regressions == 0 {
+		return nil
+	}
+	if allowDrift {
+		fmt.Fprintf(os.Stderr, "⚠️  %d item(s) regressed since the plan, continuing because of --allow-drift\n", regressions)
+		return nil
+	}
+	return fmt.Errorf("%d validation item(s) regressed since the plan was made (re-run deps to update the plan, or use --allow-drift)", regressions)
+}
+
+// scanTargetCapabilities returns the target org capabilities, reusing the scan recorded in the
+// state file when it is younger than --max-capability-age and rescanning otherwise
+func scanTargetCapabilities(client api.RESTClient, org string) (*types.TargetOrgCapabilities, error) {
+	if cached, ok := runState.CachedCapabilities(org); ok && maxCapabilityAge > 0 {
+		age := time.Since(cached.ScannedAt)
+		if age <= maxCapabilityAge {
+			if verbose {
+				fmt.Fprintf(os.Stderr, "♻️  Reusing capability scan of %s from %s (age %s)\n", org, cached.ScannedAt.Format(time.RFC3339), age.Round(time.Second))
+			}
+			return cached, nil
+		}
+		fmt.Fprintf(os.Stderr, "⚠️  Capability scan of %s is %s old (max %s), rescanning\n", org, age.Round(time.Second), maxCapabilityAge)
+	}
+
+	capabilities, err := validation.ScanTargetOrganization(client, org, verbose)
+	if err != nil {
+		return nil, err
+	}
+	runState.StoreCapabilities(capabilities)
+	return capabilities, nil
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jefeish/gh-repo-transfer/internal/history"
+)
+
+// statusCmd represents the status command
+var statusCmd = &cobra.Command{
+	Use:   "status [owner/repo...] --db [file]",
+	Short: "Show the current migration phase of each repository",
+	Long: `Show how far each repository has progressed through the migration:
+
+  planned → analyzed → validated → approved → transferred → verified
+
+The phase is derived from the events recorded with --db. Repositories validated into
+the --state-file plan are listed as well, even before anything was recorded for them.
+
+  gh repo-transfer status --db migrations.db --state-file plan.json
+  gh repo-transfer status --db migrations.db owner/repo1 owner/repo2`,
+	RunE: runStatus,
+}
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+}
+
+// phaseIcons marks each phase in the table output
+var phaseIcons = map[history.Phase]string{
+	history.PhasePlanned:     "📝",
+	history.PhaseAnalyzed:    "🔍",
+	history.PhaseValidated:   "⚠️ ",
+	history.PhaseApproved:    "👍",
+	history.PhaseTransferred: "🚚",
+	history.PhaseVerified:    "✅",
+}
+
+func runStatus(cmd *cobra.Command, args []string) error {
+	if historyDBPath == "" {
+		return fmt.Errorf("--db is required to show the migration status")
+	}
+	if _, err := os.Stat(historyDBPath); err != nil {
+		return fmt.Errorf("cannot open history database: %v", err)
+	}
+
+	if err := readRunState(); err != nil {
+		return err
+	}
+	if err := openHistoryStore(); err != nil {
+		return err
+	}
+	defer closeHistoryStore()
+
+	events, err := historyStore.Events(history.Filter{})
+	if err != nil {
+		return err
+	}
+
+	statuses := history.Summarize(events, runState.Repositories())
+	if len(args) > 0 {
+		statuses = filterStatuses(statuses, args)
+	}
+
+	if outputFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(statuses)
+	}
+
+	if len(statuses) == 0 {
+		fmt.Printf("No planned or recorded repositories\n")
+		return nil
+	}
+
+	counts := make(map[history.Phase]int)
+	fmt.Printf("📋 Migration Status (%d repositories)\n", len(statuses))
+	fmt.Printf("═══════════════════════════════════════════════\n")
+	for _, status := range statuses {
+		counts[status.Phase]++
+		line := fmt.Sprintf("%s %-40s %-12s", phaseIcons[status.Phase], status.Repository, status.Phase)
+		if status.Target != "" {
+			line += " → " + status.Target
+		}
+		if status.Note != "" {
+			line += fmt.Sprintf(" (%s)", status.Note)
+		}
+		if !status.LastUpdated.IsZero() {
+			line += fmt.Sprintf("  [%s]", status.LastUpdated.Local().Format("2006-01-02 15:04"))
+		}
+		fmt.Println(strings.TrimRight(line, " "))
+	}
+
+	var totals []string
+	for _, phase := range []history.Phase{history.PhasePlanned, history.PhaseAnalyzed, history.PhaseValidated, history.PhaseApproved, history.PhaseTransferred, history.PhaseVerified} {
+		if counts[phase] > 0 {
+			totals = append(totals, fmt.Sprintf("%d %s", counts[phase], phase))
+		}
+	}
+	fmt.Printf("\n%s\n", strings.Join(totals, ", "))
+	return nil
+}
+
+// filterStatuses keeps only the statuses of the requested repositories
+func filterStatuses(statuses []history.RepositoryStatus, repositories []string) []history.RepositoryStatus {
+	wanted := make(map[string]bool)
+	for _, repository := range repositories {
+		wanted[strings.ToLower(repository)] = true
+	}
+
+	var filtered []history.RepositoryStatus
+	for _, status := range statuses {
+		if wanted[status.Repository] {
+			filtered = append(filtered, status)
+		}
+	}
+	return filtered
+}
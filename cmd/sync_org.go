@@ -0,0 +1,313 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/jefeish/gh-repo-transfer/internal/ghclient"
+	"github.com/jefeish/gh-repo-transfer/internal/orgsync"
+)
+
+var (
+	syncFromOrg        string
+	syncAllowlistPath  string
+	syncWriteAllowlist string
+	syncPlaceholder    string
+)
+
+// syncOrgCmd represents the sync-org command
+var syncOrgCmd = &cobra.Command{
+	Use:   "sync-org --from source-org --target-org target-org --allowlist allowlist.yaml",
+	Short: "Create allowlisted org variables, runner groups and secret placeholders in the target",
+	Long: `Compare the org-level Actions configuration of the source and target organizations and
+create what is missing in the target, limited to a curated allowlist:
+
+  - variables are created with the source value and visibility
+  - runner groups are created empty; runners must be registered separately
+  - secrets are created with a placeholder value, since secret values cannot be read
+
+Start by writing an allowlist of everything in the source, remove what should not be
+copied, then preview and run the sync:
+
+  gh repo-transfer sync-org --from source-org --write-allowlist allowlist.yaml
+  gh repo-transfer sync-org --from source-org --target-org target-org --allowlist allowlist.yaml --dry-run
+  gh repo-transfer sync-org --from source-org --target-org target-org --allowlist allowlist.yaml`,
+	Args: cobra.NoArgs,
+	RunE: runSyncOrg,
+}
+
+func init() {
+	rootCmd.AddCommand(syncOrgCmd)
+	syncOrgCmd.Flags().StringVar(&syncFromOrg, "from", "", "Source organization to copy from")
+	syncOrgCmd.Flags().StringVar(&syncAllowlistPath, "allowlist", "", "YAML file listing the variables, runner_groups and secrets to create in the target")
+	syncOrgCmd.Flags().StringVar(&syncWriteAllowlist, "write-allowlist", "", "Write an allowlist of everything in the source to this file for curation, then exit")
+	syncOrgCmd.Flags().StringVar(&syncPlaceholder, "secret-placeholder", "PLACEHOLDER", "Value given to secrets created in the target")
+	syncOrgCmd.MarkFlagRequired("from")
+}
+
+// syncOrgResult is the outcome of a sync-org run
+type syncOrgResult struct {
+	Source  string           `json:"source"`
+	Target  string           `json:"target"`
+	DryRun  bool             `json:"dry_run"`
+	Actions []orgsync.Action `json:"actions"`
+	Created int              `json:"created"`
+	Failed  []string         `json:"failed,omitempty"`
+}
+
+func runSyncOrg(cmd *cobra.Command, args []string) error {
+	client, err := ghclient.NewRESTClient()
+	if err != nil {
+		return fmt.Errorf("failed to create API client: %v", err)
+	}
+
+	source, err := orgActionsInventory(*client, syncFromOrg, true)
+	if err != nil {
+		return err
+	}
+
+	if syncWriteAllowlist != "" {
+		data, err := yaml.Marshal(orgsync.AllowlistFrom(source))
+		if err != nil {
+			return fmt.Errorf("failed to marshal allowlist: %v", err)
+		}
+		if err := os.WriteFile(syncWriteAllowlist, data, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %v", syncWriteAllowlist, err)
+		}
+		fmt.Fprintf(os.Stderr, "📝 Wrote the allowlist of %s to %s; remove entries that should not be created in the target\n", syncFromOrg, syncWriteAllowlist)
+		return nil
+	}
+
+	if targetOrg == "" {
+		return fmt.Errorf("--target-org is required (or use --write-allowlist to start an allowlist)")
+	}
+	if syncAllowlistPath == "" {
+		return fmt.Errorf("--allowlist is required; create one with --write-allowlist")
+	}
+	allow, err := loadSyncAllowlist(syncAllowlistPath)
+	if err != nil {
+		return err
+	}
+
+	target, err := orgActionsInventory(*client, targetOrg, false)
+	if err != nil {
+		return err
+	}
+
+	result := syncOrgResult{
+		Source:  syncFromOrg,
+		Target:  targetOrg,
+		DryRun:  dryRun,
+		Actions: orgsync.Build(source, target, allow),
+	}
+
+	if !dryRun {
+		for _, action := range result.Actions {
+			if action.Status != orgsync.StatusCreate {
+				continue
+			}
+			if err := createOrgItem(*client, targetOrg, action); err != nil {
+				fmt.Fprintf(os.Stderr, "❌ Failed to create %s '%s' in %s: %v\n", action.Kind, action.Item.Name, targetOrg, err)
+				result.Failed = append(result.Failed, action.Item.Name)
+				continue
+			}
+			result.Created++
+		}
+	}
+
+	if outputFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(result); err != nil {
+			return err
+		}
+	} else {
+		printSyncOrgResult(result)
+	}
+
+	if len(result.Failed) > 0 {
+		return fmt.Errorf("%d items could not be created in %s", len(result.Failed), targetOrg)
+	}
+	return nil
+}
+
+// loadSyncAllowlist reads the --allowlist YAML file
+func loadSyncAllowlist(path string) (orgsync.Allowlist, error) {
+	var allow orgsync.Allowlist
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return allow, fmt.Errorf("failed to read allowlist %s: %v", path, err)
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&allow); err != nil {
+		return allow, fmt.Errorf("failed to parse allowlist %s: %v", path, err)
+	}
+	return allow, nil
+}
+
+// orgActionsInventory lists the org variables, runner groups and secrets of an organization.
+// Variable values are only needed from the source.
+func orgActionsInventory(client api.RESTClient, org string, withValues bool) (orgsync.Inventory, error) {
+	var inventory orgsync.Inventory
+
+	var variables []struct {
+		Name       string `json:"name"`
+		Value      string `json:"value"`
+		Visibility string `json:"visibility"`
+	}
+	if err := ghclient.GetAllField(&client, fmt.Sprintf("orgs/%s/actions/variables", org), "variables", &variables); err != nil {
+		return inventory, fmt.Errorf("failed to list variables of %s: %v", org, err)
+	}
+	for _, variable := range variables {
+		item := orgsync.Item{Name: variable.Name, Visibility: variable.Visibility}
+		if withValues {
+			item.Value = variable.Value
+		}
+		inventory.Variables = append(inventory.Variables, item)
+	}
+
+	var runnerGroups []struct {
+		Name       string `json:"name"`
+		Visibility string `json:"visibility"`
+	}
+	if err := ghclient.GetAllField(&client, fmt.Sprintf("orgs/%s/actions/runner-groups", org), "runner_groups", &runnerGroups); err != nil {
+		return inventory, fmt.Errorf("failed to list runner groups of %s: %v", org, err)
+	}
+	for _, group := range runnerGroups {
+		inventory.RunnerGroups = append(inventory.RunnerGroups, orgsync.Item{Name: group.Name, Visibility: group.Visibility})
+	}
+
+	var secrets []struct {
+		Name       string `json:"name"`
+		Visibility string `json:"visibility"`
+	}
+	if err := ghclient.GetAllField(&client, fmt.Sprintf("orgs/%s/actions/secrets", org), "secrets", &secrets); err != nil {
+		return inventory, fmt.Errorf("failed to list secrets of %s: %v", org, err)
+	}
+	for _, secret := range secrets {
+		inventory.Secrets = append(inventory.Secrets, orgsync.Item{Name: secret.Name, Visibility: secret.Visibility})
+	}
+
+	return inventory, nil
+}
+
+// createOrgItem creates a variable, an empty runner group or a secret, set to the item's value
+// or else the placeholder. Items the
+// source shares with selected repositories are created without any repository selected,
+// since those repositories do not exist in the target yet.
+func createOrgItem(client api.RESTClient, org string, action orgsync.Action) error {
+	item := action.Item
+	visibility := item.Visibility
+	if visibility == "" {
+		visibility = "private"
+	}
+
+	var endpoint string
+	var payload map[string]interface{}
+	switch action.Kind {
+	case orgsync.KindVariable:
+		endpoint = fmt.Sprintf("orgs/%s/actions/variables", org)
+		payload = map[string]interface{}{"name": item.Name, "value": item.Value, "visibility": visibility}
+		if visibility == "selected" {
+			payload["selected_repository_ids"] = []int64{}
+		}
+	case orgsync.KindRunnerGroup:
+		endpoint = fmt.Sprintf("orgs/%s/actions/runner-groups", org)
+		payload = map[string]interface{}{"name": item.Name, "visibility": visibility}
+	case orgsync.KindSecret:
+		// Secret values must be encrypted with the org public key; gh does that for us
+		if visibility == "selected" {
+			visibility = "private"
+		}
+		value := item.Value
+		if value == "" {
+			value = syncPlaceholder
+		}
+		return ghSecretSet(strings.NewReader(value), item.Name, "--org", org, "--visibility", visibility)
+	default:
+		return fmt.Errorf("unsupported kind %s", action.Kind)
+	}
+
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	var response map[string]interface{}
+	return client.Post(endpoint, bytes.NewBuffer(payloadBytes), &response)
+}
+
+// ghSecretSet runs 'gh secret set', which encrypts the value with the right public key. The
+// value is passed on stdin, never on the command line, where the process list would show it.
+func ghSecretSet(value io.Reader, args ...string) error {
+	command, err := ghclient.Command(append([]string{"secret", "set"}, args...)...)
+	if err != nil {
+		return err
+	}
+	command.Stdin = value
+	var stderr bytes.Buffer
+	command.Stderr = &stderr
+	if err := command.Run(); err != nil {
+		return fmt.Errorf("%v: %s", err, bytes.TrimSpace(stderr.Bytes()))
+	}
+	return nil
+}
+
+// printSyncOrgResult prints the sync plan and its outcome as a table
+func printSyncOrgResult(result syncOrgResult) {
+	if result.DryRun {
+		fmt.Printf("🔍 DRY RUN: Syncing %s → %s\n", result.Source, result.Target)
+	} else {
+		fmt.Printf("🔄 Syncing %s → %s\n", result.Source, result.Target)
+	}
+
+	failed := make(map[string]bool)
+	for _, name := range result.Failed {
+		failed[name] = true
+	}
+
+	for _, action := range result.Actions {
+		var line string
+		switch action.Status {
+		case orgsync.StatusExists:
+			line = "⏭️  exists in target"
+		case orgsync.StatusMissingInSource:
+			line = "⚠️  not found in source"
+		case orgsync.StatusCreate:
+			switch {
+			case result.DryRun:
+				line = "➕ would create"
+			case failed[action.Item.Name]:
+				line = "❌ failed"
+			default:
+				line = "✅ created"
+			}
+			switch action.Kind {
+			case orgsync.KindRunnerGroup:
+				line += " (empty)"
+			case orgsync.KindSecret:
+				line += " (placeholder value)"
+			}
+			if action.Item.Visibility == "selected" {
+				line += ", no repositories selected yet"
+			}
+		}
+		fmt.Printf("  %-13s %-30s %s\n", action.Kind, action.Item.Name, line)
+	}
+
+	fmt.Printf("\nTo create: %d, already in target: %d, not in source: %d\n",
+		orgsync.Count(result.Actions, orgsync.StatusCreate),
+		orgsync.Count(result.Actions, orgsync.StatusExists),
+		orgsync.Count(result.Actions, orgsync.StatusMissingInSource))
+	if !result.DryRun && orgsync.Count(result.Actions, orgsync.StatusCreate) > 0 {
+		fmt.Println("Replace placeholder secret values and register runners before migrating repositories.")
+	}
+}
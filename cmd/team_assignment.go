@@ -5,10 +5,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"os/exec"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/jefeish/gh-repo-transfer/internal/dependencies"
+	"github.com/jefeish/gh-repo-transfer/internal/ghclient"
+	"github.com/jefeish/gh-repo-transfer/internal/teams"
 	"github.com/jefeish/gh-repo-transfer/internal/types"
 )
 
@@ -49,6 +53,9 @@ func assignTeamsToTransferredRepo(client api.RESTClient, sourceOwner, repoName,
 			if verbose {
 				fmt.Fprintf(os.Stderr, "Team '%s' exists in target org, proceeding with assignment\n", team.Name)
 			}
+		} else if team.IsIdpControlled && !teamExistsInTargetOrg(client, targetOwner, team.Name) {
+			warnIdPTeamNotCreated(team.Name)
+			continue
 		} else {
 			// Normal mode: try to create teams if they don't exist
 			if err := createOrUpdateTeamInTargetOrg(client, targetOwner, team); err != nil {
@@ -89,7 +96,7 @@ func getRepositoryTeams(client api.RESTClient, owner, repo string) ([]types.Team
 		} `json:"permissions"`
 	}
 
-	err := client.Get(fmt.Sprintf("repos/%s/%s/teams", owner, repo), &teams)
+	err := ghclient.GetAll(&client, fmt.Sprintf("repos/%s/%s/teams", owner, repo), &teams)
 	if err != nil {
 		return nil, err
 	}
@@ -98,8 +105,8 @@ func getRepositoryTeams(client api.RESTClient, owner, repo string) ([]types.Team
 	for _, team := range teams {
 		// Determine permission/role
 		permission := team.Permission
-		if permission == "" && team.RoleName != nil && *team.RoleName != "" {
-			// Use custom role name if available
+		if team.RoleName != nil && *team.RoleName != "" && (permission == "" || !standardPermissions[normalizePermission(*team.RoleName)]) {
+			// Use custom role name if available; "permission" only carries its base role
 			permission = *team.RoleName
 		} else if permission == "" {
 			// Fallback to inferring from permissions object
@@ -114,8 +121,13 @@ func getRepositoryTeams(client api.RESTClient, owner, repo string) ([]types.Team
 			}
 		}
 
-	// Check if team is IdP-controlled (LDAP/SAML synced)
-	isIdpControlled := team.LdapDn != nil && *team.LdapDn != ""
+	// Check if team is IdP-controlled (LDAP, team sync or EMU external groups)
+	ldapDN := ""
+	if team.LdapDn != nil {
+		ldapDN = *team.LdapDn
+	}
+	groups, _ := dependencies.TeamIdPGroups(client, owner, team.Slug, ldapDN)
+	isIdpControlled := len(groups) > 0
 
 		result = append(result, types.Team{
 		Name:            team.Name,
@@ -136,8 +148,10 @@ func createOrUpdateTeamInTargetOrg(client api.RESTClient, targetOrg string, team
 		Name string `json:"name"`
 	}
 
-	// Convert team name to slug format (lowercase, replace spaces with hyphens)
-	teamSlug := strings.ToLower(strings.ReplaceAll(team.Name, " ", "-"))
+	teamSlug, found := resolveTeamSlug(client, targetOrg, team.Name)
+	if !found {
+		teamSlug = teams.Slug(team.Name)
+	}
 
 	err := client.Get(fmt.Sprintf("orgs/%s/teams/%s", targetOrg, teamSlug), &existingTeam)
 	if err == nil {
@@ -170,6 +184,7 @@ func createOrUpdateTeamInTargetOrg(client api.RESTClient, targetOrg string, team
 	if err != nil {
 		return fmt.Errorf("failed to create team: %v", err)
 	}
+	rememberTargetTeam(targetOrg, createdTeam.Name, createdTeam.Slug)
 
 	if verbose {
 		fmt.Fprintf(os.Stderr, "✅ Created team '%s' in target organization\n", team.Name)
@@ -180,8 +195,7 @@ func createOrUpdateTeamInTargetOrg(client api.RESTClient, targetOrg string, team
 
 // assignTeamToRepository assigns a team to a repository with specified permissions
 func assignTeamToRepository(client api.RESTClient, targetOrg, teamName, repoName, permission string) error {
-	// Convert team name to slug format
-	teamSlug := strings.ToLower(strings.ReplaceAll(teamName, " ", "-"))
+	teamSlug, _ := resolveTeamSlug(client, targetOrg, teamName)
 
 	// GitHub API permission mapping
 	apiPermission := permission
@@ -212,35 +226,146 @@ func assignTeamToRepository(client api.RESTClient, targetOrg, teamName, repoName
 		fmt.Fprintf(os.Stderr, "  Permission: %s → %s\n", permission, apiPermission)
 	}
 
-	// Use gh CLI directly since we know it works
-	cmd := exec.Command("gh", "api", "-X", "PUT", endpoint, "--input", "-")
-	cmd.Stdin = strings.NewReader(payload)
-	
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("failed to assign team via gh CLI (endpoint: %s, payload: %s): %v - Output: %s", endpoint, payload, err, string(output))
+	// Through the client, so --hostname, the rate limiter and --profile apply
+	if err := client.Put(endpoint, strings.NewReader(payload), nil); err != nil {
+		return fmt.Errorf("failed to assign team (endpoint: %s, payload: %s): %v", endpoint, payload, err)
 	}
 
 	if verbose {
-		fmt.Fprintf(os.Stderr, "[DEBUG] Team assignment successful via gh CLI!\n")
+		fmt.Fprintf(os.Stderr, "[DEBUG] Team assignment successful!\n")
 	}
 
 	return nil
 }
 
-// teamExistsInTargetOrg checks if a team exists in the target organization
-func teamExistsInTargetOrg(client api.RESTClient, targetOrg, teamName string) bool {
-	var existingTeam struct {
-		ID   int    `json:"id"`
-		Slug string `json:"slug"`
-		Name string `json:"name"`
+const (
+	teamAssignmentConcurrency = 4 // Teams assigned in parallel per repository
+	teamAssignmentAttempts    = 4 // PUT + readback attempts per team before a mismatch is flagged
+)
+
+// teamAssignmentBackoff is the wait before the first retry; it doubles with every attempt
+var teamAssignmentBackoff = 2 * time.Second
+
+// teamAssignment is the outcome of assigning one team, verified by reading its permission back
+type teamAssignment struct {
+	Team     types.Team
+	Applied  string // Effective permission read back from the repository, "none" without access
+	Attempts int
+	Err      error
+}
+
+// assignTeamsVerified assigns the teams in parallel with bounded concurrency. Every assignment is
+// read back and retried with exponential backoff while the effective permission differs, which
+// also covers GitHub still finalizing a transfer. Results are returned in the order of teams.
+func assignTeamsVerified(client api.RESTClient, owner, repo string, teams []types.Team) []teamAssignment {
+	results := make([]teamAssignment, len(teams))
+	slots := make(chan struct{}, teamAssignmentConcurrency)
+
+	var wg sync.WaitGroup
+	for i, team := range teams {
+		wg.Add(1)
+		go func(index int, team types.Team) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			results[index] = assignTeamVerified(client, owner, repo, team)
+		}(i, team)
 	}
+	wg.Wait()
 
-	// Convert team name to slug format (lowercase, replace spaces with hyphens)
-	teamSlug := strings.ToLower(strings.ReplaceAll(teamName, " ", "-"))
+	return results
+}
 
-	err := client.Get(fmt.Sprintf("orgs/%s/teams/%s", targetOrg, teamSlug), &existingTeam)
-	return err == nil
+func assignTeamVerified(client api.RESTClient, owner, repo string, team types.Team) teamAssignment {
+	result := teamAssignment{Team: team, Applied: "none"}
+	delay := teamAssignmentBackoff
+
+	for attempt := 1; attempt <= teamAssignmentAttempts; attempt++ {
+		result.Attempts = attempt
+		result.Err = assignTeamToRepository(client, owner, team.Name, repo, team.Permission)
+		if result.Err == nil {
+			applied, err := readTeamPermission(client, owner, repo, team.Name)
+			if err != nil {
+				result.Err = err
+			} else {
+				result.Applied = applied
+				if normalizePermission(applied) == normalizePermission(team.Permission) {
+					return result
+				}
+				result.Err = fmt.Errorf("permission read back as '%s' instead of '%s'", applied, team.Permission)
+			}
+		}
+
+		if attempt < teamAssignmentAttempts {
+			if verbose {
+				fmt.Fprintf(os.Stderr, "Retrying team '%s' in %s (attempt %d/%d): %v\n", team.Name, delay, attempt+1, teamAssignmentAttempts, result.Err)
+			}
+			time.Sleep(delay)
+			delay *= 2
+		}
+	}
+
+	return result
+}
+
+// teamRepositoryClient asks for the repository media type, so checking a team's repository
+// returns the team's permission instead of an empty 204
+var teamRepositoryClient struct {
+	once   sync.Once
+	client *api.RESTClient
+	err    error
+}
+
+// readTeamPermission returns the effective permission of a team on a repository, "none" when
+// the team has no access. It checks the one team, so verifying N teams takes N calls.
+func readTeamPermission(client api.RESTClient, owner, repo, teamName string) (string, error) {
+	teamRepositoryClient.once.Do(func() {
+		teamRepositoryClient.client, teamRepositoryClient.err = ghclient.NewRESTClientAccepting("application/vnd.github.v3.repository+json")
+	})
+	if teamRepositoryClient.err != nil {
+		return "", teamRepositoryClient.err
+	}
+
+	teamSlug, _ := resolveTeamSlug(client, owner, teamName)
+	var repository struct {
+		RoleName    string `json:"role_name"`
+		Permissions struct {
+			Admin    bool `json:"admin"`
+			Maintain bool `json:"maintain"`
+			Push     bool `json:"push"`
+			Triage   bool `json:"triage"`
+			Pull     bool `json:"pull"`
+		} `json:"permissions"`
+	}
+	err := teamRepositoryClient.client.Get(fmt.Sprintf("orgs/%s/teams/%s/repos/%s/%s", owner, teamSlug, owner, repo), &repository)
+	if err != nil {
+		if strings.Contains(err.Error(), "404") {
+			return "none", nil
+		}
+		return "", fmt.Errorf("failed to read back the permission of team '%s' on %s/%s: %v", teamName, owner, repo, err)
+	}
+
+	switch {
+	case repository.RoleName != "":
+		return repository.RoleName, nil
+	case repository.Permissions.Admin:
+		return "admin", nil
+	case repository.Permissions.Maintain:
+		return "maintain", nil
+	case repository.Permissions.Push:
+		return "push", nil
+	case repository.Permissions.Triage:
+		return "triage", nil
+	case repository.Permissions.Pull:
+		return "pull", nil
+	}
+	return "none", nil
+}
+
+// teamExistsInTargetOrg checks if a team exists in the target organization
+func teamExistsInTargetOrg(client api.RESTClient, targetOrg, teamName string) bool {
+	_, found := resolveTeamSlug(client, targetOrg, teamName)
+	return found
 }
 
 // createTeamsInTargetOrg creates teams in target org that don't already exist (Step 0)
@@ -268,6 +393,10 @@ func createTeamsInTargetOrg(client api.RESTClient, sourceOwner, repoName, target
 			skippedCount++
 			continue
 		}
+		if team.IsIdpControlled {
+			warnIdPTeamNotCreated(team.Name)
+			continue
+		}
 
 		// Create team in target org
 		if verbose {
@@ -285,6 +414,7 @@ func createTeamsInTargetOrg(client api.RESTClient, sourceOwner, repoName, target
 		if verbose {
 			fmt.Fprintf(os.Stderr, "✅ Successfully created team '%s' in target org\n", team.Name)
 		}
+		recordJournal(runJournal.AddCreatedTeam(fmt.Sprintf("%s/%s", sourceOwner, repoName), team.Name))
 		createdCount++
 	}
 
@@ -295,6 +425,12 @@ func createTeamsInTargetOrg(client api.RESTClient, sourceOwner, repoName, target
 	return nil
 }
 
+// warnIdPTeamNotCreated explains why an IdP-synced team is not created in the target org
+func warnIdPTeamNotCreated(teamName string) {
+	fmt.Fprintf(os.Stderr, "⚠️  Warning: Team '%s' is synced from an IdP and was not created; its members cannot be copied. "+
+		"Create it in the target org and connect it to the same IdP group(s) so membership follows the IdP\n", teamName)
+}
+
 // createTeamInOrg creates a new team in the specified organization
 func createTeamInOrg(client api.RESTClient, targetOrg, teamName string) error {
 	// Create team payload
@@ -308,14 +444,14 @@ func createTeamInOrg(client api.RESTClient, targetOrg, teamName string) error {
 		return fmt.Errorf("failed to marshal team creation payload: %v", err)
 	}
 
-	// Use gh CLI to create team (since we know it works)
-	cmd := exec.Command("gh", "api", "-X", "POST", fmt.Sprintf("orgs/%s/teams", targetOrg), "--input", "-")
-	cmd.Stdin = strings.NewReader(string(payloadBytes))
-
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("failed to create team via gh CLI: %v - Output: %s", err, string(output))
+	var createdTeam struct {
+		Name string `json:"name"`
+		Slug string `json:"slug"`
+	}
+	if err := client.Post(fmt.Sprintf("orgs/%s/teams", targetOrg), bytes.NewReader(payloadBytes), &createdTeam); err != nil {
+		return fmt.Errorf("failed to create team: %v", err)
 	}
+	rememberTargetTeam(targetOrg, createdTeam.Name, createdTeam.Slug)
 
 	return nil
 }
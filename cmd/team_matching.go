@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+
+	"github.com/jefeish/gh-repo-transfer/internal/ghclient"
+	"github.com/jefeish/gh-repo-transfer/internal/teams"
+)
+
+// targetTeam is a team of a target organization as listed by the API
+type targetTeam struct {
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+var (
+	// targetTeams caches the teams of each target org so every lookup is a single list call per run
+	targetTeams      = make(map[string][]targetTeam)
+	targetTeamsMutex sync.Mutex
+)
+
+// resolveTeamSlug finds the slug of the target org team matching teamName under --team-matcher.
+// When no team matches it returns the slug GitHub would give a team of that name and false.
+func resolveTeamSlug(client api.RESTClient, org, teamName string) (string, bool) {
+	orgTeams, err := listTargetTeams(client, org)
+	if err != nil {
+		// Without the team list only a direct lookup of the derived slug is possible
+		if verbose {
+			fmt.Fprintf(os.Stderr, "Warning: Could not list teams of %s: %v\n", org, err)
+		}
+		slug := teams.Slug(teamName)
+		var team targetTeam
+		return slug, client.Get(fmt.Sprintf("orgs/%s/teams/%s", org, slug), &team) == nil
+	}
+
+	for _, team := range orgTeams {
+		if teams.Matches(teamName, team.Name, team.Slug) {
+			return team.Slug, true
+		}
+	}
+	return teams.Slug(teamName), false
+}
+
+// listTargetTeams returns all teams of an organization, fetching them on first use
+func listTargetTeams(client api.RESTClient, org string) ([]targetTeam, error) {
+	key := strings.ToLower(org)
+	targetTeamsMutex.Lock()
+	defer targetTeamsMutex.Unlock()
+
+	if cached, ok := targetTeams[key]; ok {
+		return cached, nil
+	}
+
+	var all []targetTeam
+	if err := ghclient.GetAll(&client, fmt.Sprintf("orgs/%s/teams", org), &all); err != nil {
+		return nil, err
+	}
+
+	targetTeams[key] = all
+	return all, nil
+}
+
+// rememberTargetTeam adds a newly created team to the cache so later lookups find it
+func rememberTargetTeam(org, name, slug string) {
+	if slug == "" {
+		return
+	}
+	key := strings.ToLower(org)
+	targetTeamsMutex.Lock()
+	defer targetTeamsMutex.Unlock()
+
+	if cached, ok := targetTeams[key]; ok {
+		targetTeams[key] = append(cached, targetTeam{Name: name, Slug: slug})
+	}
+}
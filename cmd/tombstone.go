@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+
+	"github.com/jefeish/gh-repo-transfer/internal/ghclient"
+	"github.com/jefeish/gh-repo-transfer/internal/history"
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+// createTombstoneRepository occupies the old path of a moved repository with an archived
+// repository whose README points to the new location, so nobody can register the old name
+// and serve different code to consumers still pinned to it. The tombstone ends GitHub's
+// redirect: references to the old path fail loudly instead of resolving to a squatter.
+// A tombstone left by an earlier run is kept, so re-running the operation is harmless.
+func createTombstoneRepository(client api.RESTClient, owner, repo, newPath, visibility string) error {
+	oldPath := fmt.Sprintf("%s/%s", owner, repo)
+	newOwner := strings.SplitN(newPath, "/", 2)[0]
+	if err := placeTombstone(client, owner, repo, newPath, visibility); err != nil {
+		recordHistory(oldPath, history.KindTombstone, "failed", newOwner, map[string]string{"new_path": newPath, "error": err.Error()})
+		return err
+	}
+	recordHistory(oldPath, history.KindTombstone, "succeeded", newOwner, map[string]string{"new_path": newPath})
+	return nil
+}
+
+// placeTombstone creates, fills and archives the tombstone repository
+func placeTombstone(client api.RESTClient, owner, repo, newPath, visibility string) error {
+	oldPath := fmt.Sprintf("%s/%s", owner, repo)
+	existing, err := findTombstone(client, oldPath, newPath)
+	if err != nil {
+		return fmt.Errorf("cannot create a tombstone: %v", err)
+	}
+	if existing {
+		fmt.Printf("🪦 Tombstone %s already points to %s\n", oldPath, newPath)
+		return nil
+	}
+
+	if visibility == "" {
+		visibility = "private"
+	}
+	create := map[string]interface{}{
+		"name":         repo,
+		"description":  tombstoneDescription(newPath),
+		"visibility":   visibility,
+		"has_issues":   false,
+		"has_projects": false,
+		"has_wiki":     false,
+	}
+	payload, err := json.Marshal(create)
+	if err != nil {
+		return err
+	}
+
+	// Repositories of a user account can only be created by that user, through user/repos
+	createPath := fmt.Sprintf("orgs/%s/repos", owner)
+	var account struct {
+		Type string `json:"type"`
+	}
+	if err := client.Get(fmt.Sprintf("users/%s", owner), &account); err == nil && account.Type == "User" {
+		createPath = "user/repos"
+	}
+	var created struct {
+		FullName string `json:"full_name"`
+	}
+	if err := client.Post(createPath, bytes.NewBuffer(payload), &created); err != nil {
+		return fmt.Errorf("failed to create tombstone %s: %v", oldPath, err)
+	}
+	if !strings.EqualFold(created.FullName, oldPath) {
+		// user/repos creates the repository for the authenticated user, whoever that is
+		return fmt.Errorf("tombstone was created as %s instead of %s; delete it and create the tombstone as %s", created.FullName, oldPath, owner)
+	}
+
+	readme := map[string]string{
+		"message": fmt.Sprintf("Tombstone: %s moved to %s", oldPath, newPath),
+		"content": base64.StdEncoding.EncodeToString([]byte(tombstoneReadme(oldPath, newPath))),
+	}
+	payload, err = json.Marshal(readme)
+	if err != nil {
+		return err
+	}
+	var response map[string]interface{}
+	if err := client.Put(fmt.Sprintf("repos/%s/contents/README.md", oldPath), bytes.NewBuffer(payload), &response); err != nil {
+		return fmt.Errorf("failed to write tombstone README: %v", err)
+	}
+
+	if err := setRepositoryArchiveStatus(client, owner, repo, true, verbose); err != nil {
+		return fmt.Errorf("failed to archive tombstone: %v", err)
+	}
+
+	fmt.Printf("🪦 Created tombstone %s pointing to %s\n", oldPath, newPath)
+	return nil
+}
+
+// findTombstone reports whether oldPath holds a tombstone pointing to newPath. While the old
+// path is free, GitHub redirects it to the moved repository; any other repository there
+// means the name is already taken.
+func findTombstone(client api.RESTClient, oldPath, newPath string) (bool, error) {
+	var existing struct {
+		FullName    string `json:"full_name"`
+		Description string `json:"description"`
+		Archived    bool   `json:"archived"`
+	}
+	if err := client.Get(fmt.Sprintf("repos/%s", oldPath), &existing); err != nil {
+		if strings.Contains(err.Error(), "404") {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to check %s: %v", oldPath, err)
+	}
+	if !strings.EqualFold(existing.FullName, oldPath) {
+		return false, nil // Redirect to the moved repository
+	}
+	if existing.Archived && existing.Description == tombstoneDescription(newPath) {
+		return true, nil
+	}
+	return false, fmt.Errorf("%s is already taken by another repository", oldPath)
+}
+
+// tombstoneDescription is the description that marks a repository as the tombstone of a move
+func tombstoneDescription(newPath string) string {
+	return fmt.Sprintf("Moved to %s", newPath)
+}
+
+// deleteTombstone removes the tombstone at oldPath so a repository can move back there
+// (requires the delete_repo scope)
+func deleteTombstone(client api.RESTClient, oldPath string) error {
+	if err := client.Delete(fmt.Sprintf("repos/%s", oldPath), nil); err != nil {
+		return fmt.Errorf("failed to delete tombstone %s (requires the delete_repo scope): %v", oldPath, err)
+	}
+	fmt.Printf("🪦 Deleted tombstone %s\n", oldPath)
+	return nil
+}
+
+// tombstoneReadme is the README of a tombstone repository
+func tombstoneReadme(oldPath, newPath string) string {
+	return fmt.Sprintf(`# This repository has moved
+
+%s now lives at [%s](%s).
+
+This archived placeholder keeps the old name reserved. References to %s, including
+actions and reusable workflows pinned to a commit SHA, no longer resolve here: update them
+to %s.
+`, oldPath, newPath, ghclient.WebURL(newPath), oldPath, newPath)
+}
+
+// warnPinnedActionConsumers warns that SHA-pinned consumers of a repository's actions depend
+// on the old name staying unused, and recommends --create-tombstone
+func warnPinnedActionConsumers(repository string, deps *types.OrganizationalDependencies) {
+	consumers := deps.ActionsCIDependencies.PinnedActionConsumers
+	if len(consumers) == 0 {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "⚠️  Warning: %d workflow(s) pin actions of %s to a commit SHA. They keep resolving through the redirect only until someone reuses the name %s:\n", len(consumers), repository, repository)
+	for _, consumer := range consumers {
+		fmt.Fprintf(os.Stderr, "   - %s\n", consumer)
+	}
+	if !createTombstone {
+		fmt.Fprintf(os.Stderr, "   Update them to the new path, and consider --create-tombstone to reserve the old name.\n")
+	}
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// getRepositoryTopics retrieves the topics currently set on a repository
+func getRepositoryTopics(client api.RESTClient, owner, repo string) ([]string, error) {
+	var response struct {
+		Names []string `json:"names"`
+	}
+
+	err := client.Get(fmt.Sprintf("repos/%s/%s/topics", owner, repo), &response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repository topics: %v", err)
+	}
+
+	return response.Names, nil
+}
+
+// setRepositoryTopics replaces all topics on a repository
+func setRepositoryTopics(client api.RESTClient, owner, repo string, topics []string) error {
+	if topics == nil {
+		topics = []string{}
+	}
+
+	payloadBytes, err := json.Marshal(map[string]interface{}{
+		"names": topics,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal topics payload: %v", err)
+	}
+
+	var response map[string]interface{}
+	err = client.Put(fmt.Sprintf("repos/%s/%s/topics", owner, repo), bytes.NewBuffer(payloadBytes), &response)
+	if err != nil {
+		return fmt.Errorf("failed to update repository topics: %v", err)
+	}
+
+	return nil
+}
+
+// mergeTopics applies the requested additions and removals to the current topic list.
+// GitHub stores topics in lowercase, so comparisons are case-insensitive and the
+// original order of existing topics is preserved.
+func mergeTopics(current, add, remove []string) []string {
+	removeSet := make(map[string]bool)
+	for _, topic := range remove {
+		removeSet[strings.ToLower(strings.TrimSpace(topic))] = true
+	}
+
+	seen := make(map[string]bool)
+	var merged []string
+	for _, topic := range append(append([]string{}, current...), add...) {
+		normalized := strings.ToLower(strings.TrimSpace(topic))
+		if normalized == "" || seen[normalized] || removeSet[normalized] {
+			continue
+		}
+		seen[normalized] = true
+		merged = append(merged, normalized)
+	}
+
+	return merged
+}
+
+// missingTopics returns the source topics that are no longer present on the target repository
+func missingTopics(sourceTopics, targetTopics []string) []string {
+	present := make(map[string]bool)
+	for _, topic := range targetTopics {
+		present[strings.ToLower(topic)] = true
+	}
+
+	var missing []string
+	for _, topic := range sourceTopics {
+		if !present[strings.ToLower(topic)] {
+			missing = append(missing, topic)
+		}
+	}
+	return missing
+}
+
+// hasTopicChanges reports whether --add-topics or --remove-topics was requested
+func hasTopicChanges() bool {
+	return len(addTopics) > 0 || len(removeTopics) > 0
+}
+
+// verifyAndAugmentTopics checks that the source topics survived the transfer, restores any
+// that were dropped and applies the --add-topics/--remove-topics changes in a single update.
+// Errors are reported as warnings by the caller; topics never fail a transfer.
+func verifyAndAugmentTopics(client api.RESTClient, sourceTopics []string, targetOwner, repoName string, verboseOutput bool) error {
+	targetTopics, err := getRepositoryTopics(client, targetOwner, repoName)
+	if err != nil {
+		return err
+	}
+
+	missing := missingTopics(sourceTopics, targetTopics)
+	if len(missing) > 0 {
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: %d topic(s) missing after transfer, restoring: %s\n", len(missing), strings.Join(missing, ", "))
+	} else if verboseOutput && len(sourceTopics) > 0 {
+		fmt.Fprintf(os.Stderr, "✅ All %d source topics survived the transfer\n", len(sourceTopics))
+	}
+
+	if len(missing) == 0 && !hasTopicChanges() {
+		return nil
+	}
+
+	merged := mergeTopics(append(targetTopics, missing...), addTopics, removeTopics)
+	if err := setRepositoryTopics(client, targetOwner, repoName, merged); err != nil {
+		return err
+	}
+
+	if verboseOutput {
+		fmt.Fprintf(os.Stderr, "✅ Repository topics set to: %s\n", strings.Join(merged, ", "))
+	}
+
+	return nil
+}
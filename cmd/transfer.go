@@ -6,14 +6,24 @@ import (
 	"fmt"
 	"os"
 	"strings"
-	"time"
 
 	"github.com/cli/go-gh/v2/pkg/api"
 	"github.com/spf13/cobra"
 
 	"github.com/jefeish/gh-repo-transfer/internal/analyzer"
+	"github.com/jefeish/gh-repo-transfer/internal/batch"
+	"github.com/jefeish/gh-repo-transfer/internal/dependencies"
+	"github.com/jefeish/gh-repo-transfer/internal/ghclient"
+	"github.com/jefeish/gh-repo-transfer/internal/history"
+	"github.com/jefeish/gh-repo-transfer/internal/journal"
+	"github.com/jefeish/gh-repo-transfer/internal/origin"
+	"github.com/jefeish/gh-repo-transfer/internal/policy"
+	"github.com/jefeish/gh-repo-transfer/internal/reposize"
+	"github.com/jefeish/gh-repo-transfer/internal/steps"
+	"github.com/jefeish/gh-repo-transfer/internal/teams"
 	"github.com/jefeish/gh-repo-transfer/internal/types"
 	"github.com/jefeish/gh-repo-transfer/internal/validation"
+	"github.com/jefeish/gh-repo-transfer/pkg/utils"
 )
 
 // transferCmd represents the transfer command
@@ -63,8 +73,12 @@ func init() {
 
 func runTransfer(cmd *cobra.Command, args []string) error {
 	var repos []string
+	args, err := repositoryArgs(args)
+	if err != nil {
+		return err
+	}
 	
-	if len(args) == 0 {
+	if len(args) == 0 && !resumeRun {
 		// Try to get repo from current directory
 		currentRepo, err := getCurrentRepo()
 		if err != nil {
@@ -83,6 +97,15 @@ func runTransfer(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// With --resume, the journal of the previous run decides which repositories are left
+	repos, err = startRunJournal("transfer", repos)
+	if err != nil {
+		return err
+	}
+	if len(repos) == 0 {
+		return nil
+	}
+
 	if verbose {
 		if len(repos) == 1 {
 			fmt.Fprintf(os.Stderr, "Preparing to transfer repository: %s to %s\n", repos[0], targetOrg)
@@ -91,11 +114,52 @@ func runTransfer(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	client, err := api.DefaultRESTClient()
+	client, err := ghclient.NewRESTClient()
 	if err != nil {
 		return fmt.Errorf("failed to create API client: %v", err)
 	}
 
+	// Load the settings profile up front so a bad file fails before any repository is moved
+	loadedSettingsProfile, err = loadSettingsProfile(settingsProfilePath)
+	if err != nil {
+		return err
+	}
+	loadedPolicy, err = policy.Load(policyFilePath)
+	if err != nil {
+		return err
+	}
+	if err := validation.LoadEffortWeights(effortWeightsPath); err != nil {
+		return err
+	}
+	if err := validation.LoadSuppressions(ignoreFilePath); err != nil {
+		return err
+	}
+	if err := validateConcurrency(); err != nil {
+		return err
+	}
+	validation.SetCollisionAwareness(checkCollisions)
+	dependencies.SetEnterprise(enterpriseSlug)
+	validation.SetTargetEnterprise(resolvedTargetEnterprise())
+	matchMode, err := teams.ParseMatchMode(teamMatcher)
+	if err != nil {
+		return err
+	}
+	teams.SetMatchMode(matchMode)
+	if _, err := origin.Strategies(originTracking); err != nil {
+		return err
+	}
+	if err := loadRunState(); err != nil {
+		return err
+	}
+	defer saveRunState()
+	if err := openHistoryStore(); err != nil {
+		return err
+	}
+	defer closeHistoryStore()
+	if err := resolveControlRepo(*client); err != nil {
+		return err
+	}
+
 	// Validate target owner exists (once for all repos)
 	if err := validateTargetOwner(*client, targetOrg); err != nil {
 		return fmt.Errorf("failed to validate target owner: %v", err)
@@ -114,7 +178,7 @@ func runTransfer(cmd *cobra.Command, args []string) error {
 		if verbose {
 			fmt.Fprintf(os.Stderr, "Scanning target organization capabilities: %s\n", targetOrg)
 		}
-		caps, err := validation.ScanTargetOrganization(*client, targetOrg, verbose)
+		caps, err := scanTargetCapabilities(*client, targetOrg)
 		if err != nil {
 			return fmt.Errorf("failed to scan target organization: %v", err)
 		}
@@ -126,7 +190,7 @@ func runTransfer(cmd *cobra.Command, args []string) error {
 		for _, repo := range repos {
 			parts := strings.Split(repo, "/")
 			owner, repoName := parts[0], parts[1]
-			sourceTeamPermissions, err := getRepositoryTeams(*client, owner, repoName)
+			sourceTeamPermissions, err := repositoryTeams(*client, owner, repoName)
 			if err != nil {
 				if verbose {
 					fmt.Fprintf(os.Stderr, "Warning: Could not retrieve team permissions for %s: %v\n", repo, err)
@@ -148,26 +212,38 @@ func runTransfer(cmd *cobra.Command, args []string) error {
 		fmt.Fprintf(os.Stderr, "Processing %d repositories across %d organizations\n", len(repos), len(orgRepos))
 	}
 
-	// Process each repository with optimizations
-	var results []transferResult
-	repoIndex := 0
+	// Validate repositories in parallel, up to --concurrency at a time; the moves themselves
+	// run one after another once the batch is confirmed
+	var ordered []string
 	for orgName, orgRepoList := range orgRepos {
 		if verbose && len(orgRepoList) > 1 {
 			fmt.Fprintf(os.Stderr, "\nProcessing %d repositories from organization: %s\n", len(orgRepoList), orgName)
 		}
-		
-		for _, repo := range orgRepoList {
-			parts := strings.Split(repo, "/")
-			owner, repoName := parts[0], parts[1]
+		ordered = append(ordered, orgRepoList...)
+	}
 
-			if len(repos) > 1 {
-				repoIndex++
-				fmt.Fprintf(os.Stderr, "\n[%d/%d] Processing %s\n", repoIndex, len(repos), repo)
-			}
+	results := make([]transferResult, len(ordered))
+	batch.ForEach(len(ordered), concurrency, func(index int) {
+		repo := ordered[index]
+		parts := strings.Split(repo, "/")
+		owner, repoName := parts[0], parts[1]
 
-			result := processRepoTransferOptimized(*client, owner, repoName, targetCapabilities)
-			results = append(results, result)
+		if len(repos) > 1 {
+			fmt.Fprintf(os.Stderr, "\n[%d/%d] Processing %s\n", index+1, len(repos), repo)
 		}
+
+		// A repository an earlier run transferred has no source left to validate
+		if entry, ok := resumedEntry(repo); ok {
+			results[index] = resumedTransferResult(entry, owner, repoName)
+			return
+		}
+		results[index] = processRepoTransferOptimized(*client, owner, repoName, targetCapabilities)
+		recordJournal(runJournal.Validated(repo, fmt.Sprintf("%s/%s", targetOrg, repoName), results[index].Error))
+	})
+
+	// With --interactive, the operator decides about each repository with findings
+	if err := reviewTransferResults(*client, results); err != nil {
+		return err
 	}
 
 	// Handle dry-run summary for multiple repos
@@ -271,203 +347,323 @@ func validateSourceRepository(client api.RESTClient, owner, repo string) error {
 	return nil
 }
 
-// executeTransfer performs the actual repository transfer
-func executeTransfer(client api.RESTClient, owner, repo, targetOwner string, teams []string, preservePermissions bool) error {
-	// Collect source team permissions before transfer if we need to preserve them
-	var sourceTeamPermissions []types.Team
-	if len(teams) > 0 && preservePermissions {
+// transferOperation holds the state threaded through the steps of a transfer
+type transferOperation struct {
+	client              api.RESTClient
+	owner               string
+	repo                string
+	targetOwner         string
+	teams               []string
+	preservePermissions bool
+	size                *reposize.Stats // Extends the wait for the moved repository
+
+	sourceTeamPermissions []types.Team
+	captured              sourceCapture
+	teamIDs               []int
+	transferredID         int
+	fullName              string
+	visibility            string
+	changes               []permissionChange
+	permissionErr         error
+}
+
+// steps lists the transfer as a sequence: capture what the move loses, transfer, then restore
+// and align the moved repository. Only the transfer itself is critical.
+func (o *transferOperation) steps() []steps.Step {
+	assignTeams := len(o.teams) > 0 && o.preservePermissions
+	operation := []steps.Step{
+		{Name: "collect-team-permissions", Description: "Collect source team permissions", Skip: !assignTeams, Execute: o.collectTeamPermissions},
+	}
+	operation = append(operation, o.captured.captureSteps(o.client, o.owner, o.repo, fmt.Sprintf("%s/%s", o.owner, o.repo), true, verbose)...)
+	operation = append(operation, []steps.Step{
+		{Name: "resolve-team-ids", Description: "Look up team IDs in the target organization", Skip: len(o.teams) == 0, Execute: o.resolveTeamIDs},
+		{Name: "transfer", Description: "Transfer the repository", Critical: true, Execute: o.transfer, Rollback: o.transferBack},
+		{Name: "store-origin", Description: originTrackingDescription(), Execute: o.storeOrigin},
+		{Name: "create-tombstone", Description: "Create an archived tombstone at the old path", Skip: !createTombstone, Execute: o.createTombstone, Rollback: func() error {
+			return deleteTombstone(o.client, originalPathOf(o.owner, o.repo))
+		}},
+		{Name: "cleanup-source", Description: "Remove references left in the source organization", Skip: !cleanupSource, Execute: func() error {
+			cleanupSourceReferences(o.client, o.owner, o.repo, int64(o.transferredID), o.fullName)
+			return nil
+		}},
+		{Name: "default-branch", Description: "Rename the default branch", Skip: defaultBranch == "", Execute: func() error {
+			if err := alignDefaultBranch(o.client, o.targetOwner, o.repo, defaultBranch, verbose); err != nil {
+				return fmt.Errorf("Default branch rename failed: %v", err)
+			}
+			return nil
+		}},
+	}...)
+	operation = append(operation, o.captured.restoreSteps(o.client, o.targetOwner, o.repo, true, verbose)...)
+	return append(operation, []steps.Step{
+		{Name: "announce", Description: "Announce the new location on open issues and pull requests", Skip: !announce, Execute: func() error {
+			if err := announceMigration(o.client, originalPathOf(o.owner, o.repo), o.targetOwner, o.repo, verbose); err != nil {
+				return fmt.Errorf("Migration announcement failed: %v", err)
+			}
+			return nil
+		}},
+		{Name: "settings-profile", Description: "Apply the settings profile", Skip: loadedSettingsProfile == nil, Execute: func() error {
+			return applySettingsProfile(o.client, o.targetOwner, o.repo, loadedSettingsProfile, verbose)
+		}},
+		{Name: "assign-teams", Description: "Assign teams with their source permissions", Skip: !assignTeams, Execute: o.assignTeams},
+		{Name: "rewrite-codeowners", Description: "Propose rewriting CODEOWNERS team references to the target organization", Skip: !rewriteCodeownersRefs, Execute: func() error {
+			return rewriteCodeowners(o.client, o.owner, o.targetOwner, o.repo, verbose)
+		}},
+		{Name: "rewrite-actions", Description: "Rewrite workflow references to actions of the source organization to the target organization", Skip: !rewriteActionsRefs && rewriteActionsDir == "", Execute: func() error {
+			return rewriteActions(o.client, o.owner, o.targetOwner, o.repo, rewriteActionsDir, verbose)
+		}},
+		{Name: "verify-settings", Description: "Report settings that changed during the move", Skip: !verifySettings, Restores: true, Execute: o.verifySettings},
+	}...)
+}
+
+// collectTeamPermissions records team permissions before the source repository disappears
+func (o *transferOperation) collectTeamPermissions() error {
+	if verbose {
+		fmt.Fprintf(os.Stderr, "Collecting team permissions from source repository before transfer...\n")
+	}
+	var err error
+	o.sourceTeamPermissions, err = repositoryTeams(o.client, o.owner, o.repo)
+	if err != nil {
 		if verbose {
-			fmt.Fprintf(os.Stderr, "Collecting team permissions from source repository before transfer...\n")
+			fmt.Fprintf(os.Stderr, "Warning: Could not retrieve team permissions: %v\n", err)
 		}
-		var err error
-		sourceTeamPermissions, err = getRepositoryTeams(client, owner, repo)
+		return nil
+	}
+	for _, team := range o.sourceTeamPermissions {
+		if verbose {
+			fmt.Fprintf(os.Stderr, "Source team '%s' has '%s' permission\n", team.Name, team.Permission)
+		}
+	}
+	// The source repository is gone after the transfer; a resumed run assigns the teams from the journal
+	recordJournal(runJournal.SetTeams(fmt.Sprintf("%s/%s", o.owner, o.repo), o.sourceTeamPermissions))
+	return nil
+}
+
+// resolveTeamIDs looks up the IDs of the teams included in the transfer payload
+func (o *transferOperation) resolveTeamIDs() error {
+	if verbose {
+		fmt.Fprintf(os.Stderr, "Looking up team IDs for: %v\n", o.teams)
+	}
+	for _, teamName := range o.teams {
+		teamId, err := getTeamIdByName(o.client, o.targetOwner, teamName)
 		if err != nil {
 			if verbose {
-				fmt.Fprintf(os.Stderr, "Warning: Could not retrieve team permissions: %v\n", err)
-			}
-		} else {
-			for _, team := range sourceTeamPermissions {
-				if verbose {
-					fmt.Fprintf(os.Stderr, "Source team '%s' has '%s' permission\n", team.Name, team.Permission)
-				}
+				fmt.Fprintf(os.Stderr, "Warning: Could not find team '%s' in target org: %v\n", teamName, err)
 			}
+			continue
+		}
+		o.teamIDs = append(o.teamIDs, teamId)
+		if verbose {
+			fmt.Fprintf(os.Stderr, "Found team '%s' with ID: %d\n", teamName, teamId)
 		}
 	}
+	return nil
+}
+
+// transfer performs the actual repository transfer
+func (o *transferOperation) transfer() error {
 	if verbose {
 		fmt.Fprintf(os.Stderr, "🔄 Initiating repository transfer...\n")
-		fmt.Fprintf(os.Stderr, "Source: %s/%s\n", owner, repo)
-		fmt.Fprintf(os.Stderr, "Target: %s\n", targetOwner)
+		fmt.Fprintf(os.Stderr, "Source: %s/%s\n", o.owner, o.repo)
+		fmt.Fprintf(os.Stderr, "Target: %s\n", o.targetOwner)
 	}
 
-	// Prepare transfer payload
 	transferPayload := map[string]interface{}{
-		"new_owner": targetOwner,
+		"new_owner": o.targetOwner,
 	}
-
-	// If teams are specified, look up their IDs in the target organization
-	if len(teams) > 0 {
+	// If teams are specified, include team_ids in the transfer payload (step 1)
+	if len(o.teamIDs) > 0 {
+		transferPayload["team_ids"] = o.teamIDs
 		if verbose {
-			fmt.Fprintf(os.Stderr, "Looking up team IDs for: %v\n", teams)
-		}
-		
-		var teamIds []int
-		for _, teamName := range teams {
-			teamId, err := getTeamIdByName(client, targetOwner, teamName)
-			if err != nil {
-				if verbose {
-					fmt.Fprintf(os.Stderr, "Warning: Could not find team '%s' in target org: %v\n", teamName, err)
-				}
-				continue
-			}
-			teamIds = append(teamIds, teamId)
-			if verbose {
-				fmt.Fprintf(os.Stderr, "Found team '%s' with ID: %d\n", teamName, teamId)
-			}
-		}
-		
-		// If teams are specified, include team_ids in the transfer payload (step 1)
-		if len(teamIds) > 0 {
-			transferPayload["team_ids"] = teamIds
-			if verbose {
-				fmt.Fprintf(os.Stderr, "Including %d team_ids in transfer payload: %v\n", len(teamIds), teamIds)
-			}
+			fmt.Fprintf(os.Stderr, "Including %d team_ids in transfer payload: %v\n", len(o.teamIDs), o.teamIDs)
 		}
 	}
 
-	// Marshal the payload
 	payloadBytes, err := json.Marshal(transferPayload)
 	if err != nil {
 		return fmt.Errorf("failed to marshal transfer payload: %v", err)
 	}
-
 	if verbose {
 		fmt.Fprintf(os.Stderr, "Transfer payload: %s\n", string(payloadBytes))
 	}
 
-	// Perform the actual repository transfer
 	var transferResponse struct {
-		ID          int    `json:"id"`
-		NodeID      string `json:"node_id"`
-		Name        string `json:"name"`
-		FullName    string `json:"full_name"`
-		Owner       struct {
-			Login string `json:"login"`
-		} `json:"owner"`
+		ID         int    `json:"id"`
+		FullName   string `json:"full_name"`
+		Visibility string `json:"visibility"`
 	}
-
-	err = client.Post(fmt.Sprintf("repos/%s/%s/transfer", owner, repo), bytes.NewBuffer(payloadBytes), &transferResponse)
+	err = o.client.Post(fmt.Sprintf("repos/%s/%s/transfer", o.owner, o.repo), bytes.NewBuffer(payloadBytes), &transferResponse)
 	if err != nil {
 		return fmt.Errorf("repository transfer failed: %v", err)
 	}
+	o.transferredID = transferResponse.ID
+	o.fullName = transferResponse.FullName
+	o.visibility = transferResponse.Visibility
 
 	fmt.Printf("✅ Repository transferred successfully!\n")
-	fmt.Printf("   New location: %s\n", transferResponse.FullName)
+	fmt.Printf("   New location: %s\n", o.fullName)
 
-	// Store the original path as a repository custom property (repo-origin)
-	originalPath := fmt.Sprintf("%s/%s", owner, repo)
+	// The steps after the transfer need the repository at its new path
+	waitForMovedRepository(o.client, fmt.Sprintf("%s/%s", o.targetOwner, o.repo), o.size, 0)
+	return nil
+}
+
+// transferBack returns the repository to its source owner
+func (o *transferOperation) transferBack() error {
+	return transferRepositoryBack(o.client, o.targetOwner, o.repo, o.owner, o.repo)
+}
+
+// storeOrigin stores the original path with the --origin-tracking strategies
+func (o *transferOperation) storeOrigin() error {
+	originalPath := originalPathOf(o.owner, o.repo)
 	if verbose {
 		fmt.Fprintf(os.Stderr, "Storing origin tracking: '%s'\n", originalPath)
 	}
-	if err := storeOriginalPathProperty(client, targetOwner, repo, originalPath, verbose); err != nil {
-		if verbose {
-			fmt.Fprintf(os.Stderr, "Warning: Origin tracking encountered an error: %v\n", err)
-		}
+	// A failure leaves the repository half migrated; it is a warning recorded in the journal (see rollback)
+	if err := storeOriginTracking(o.client, o.targetOwner, o.repo, originalPath, origin.Transferred, verbose); err != nil {
+		return fmt.Errorf("Origin tracking failed: %v", err)
 	}
+	return nil
+}
 
-	// Assign teams with their original permissions (pure two-step approach)
-	if len(teams) > 0 && preservePermissions && len(sourceTeamPermissions) > 0 {
-		if verbose {
-			fmt.Fprintf(os.Stderr, "Assigning teams with preserved permissions...\n")
-		}
-		
-		// Wait longer for transfer to complete fully and GitHub to update permissions  
-		time.Sleep(10 * time.Second)
-		
-		// Assign each team with its original permission
-		for _, originalTeam := range sourceTeamPermissions {
-			if verbose {
-				fmt.Fprintf(os.Stderr, "Assigning team '%s' with '%s' permission\n", originalTeam.Name, originalTeam.Permission)
-			}
-			
-			err = assignTeamToRepository(client, targetOwner, originalTeam.Name, repo, originalTeam.Permission)
-			if err != nil {
-				if verbose {
-					fmt.Fprintf(os.Stderr, "Warning: Failed to assign team '%s': %v\n", originalTeam.Name, err)
-				}
-			} else {
-				if verbose {
-					fmt.Fprintf(os.Stderr, "✅ Successfully assigned team '%s' with '%s' permission\n", originalTeam.Name, originalTeam.Permission)
-				}
-			}
-		}
-		
-		if verbose {
-			fmt.Fprintf(os.Stderr, "✅ Team assignment completed\n")
+// createTombstone reserves the original path; a promoted repository's original path is its
+// source path in the plan, which the first hop left free
+func (o *transferOperation) createTombstone() error {
+	oldPath := strings.SplitN(originalPathOf(o.owner, o.repo), "/", 2)
+	return createTombstoneRepository(o.client, oldPath[0], oldPath[1], o.fullName, o.visibility)
+}
+
+// assignTeams assigns teams with their original permissions (pure two-step approach)
+func (o *transferOperation) assignTeams() error {
+	if len(o.sourceTeamPermissions) == 0 {
+		return nil
+	}
+	if verbose {
+		fmt.Fprintf(os.Stderr, "Assigning teams with preserved permissions...\n")
+	}
+
+	// Every assignment is read back, so silent upgrades/downgrades are reported
+	assignments := assignPreCollectedTeamsToRepo(o.client, o.targetOwner, o.repo, o.sourceTeamPermissions)
+	o.changes = assignmentPermissionChanges(assignments)
+	o.permissionErr = checkPermissionChanges(o.fullName, o.changes)
+
+	var failed []string
+	for _, assignment := range assignments {
+		if assignment.Err != nil {
+			failed = append(failed, fmt.Sprintf("%s (%v)", assignment.Team.Name, assignment.Err))
 		}
 	}
+	if len(failed) > 0 {
+		return fmt.Errorf("Team assignment failed for %d team(s): %s", len(failed), strings.Join(failed, ", "))
+	}
+	return nil
+}
 
+// verifySettings reports settings GitHub changed implicitly during the move
+func (o *transferOperation) verifySettings() error {
+	if o.captured.settingsBefore == nil {
+		return nil
+	}
+	settingsAfter, err := snapshotRepositorySettings(o.client, o.targetOwner, o.repo)
+	if err != nil {
+		return fmt.Errorf("Could not snapshot transferred repository settings: %v", err)
+	}
+	drifts := markExpectedDrift(diffSettingsSnapshots(o.captured.settingsBefore, settingsAfter), loadedSettingsProfile, false)
+	drifts = append(drifts, permissionDrifts(o.changes)...)
+	printSettingsDriftReport(o.fullName, drifts)
+	recordHistory(fmt.Sprintf("%s/%s", o.owner, o.repo), history.KindVerification, verificationStatus(drifts), o.targetOwner, drifts)
 	return nil
 }
 
-// assignPreCollectedTeamsToRepo assigns teams to a repository using pre-collected team names
-// This is used when team information was collected before transfer but the source repo no longer exists
-func assignPreCollectedTeamsToRepo(client api.RESTClient, targetOwner, repoName string, teamNames []string) error {
-	if len(teamNames) == 0 {
-		if verbose {
-			fmt.Fprintf(os.Stderr, "No teams to assign\n")
-		}
+// resume restores what the steps an earlier run completed had collected, when the journal
+// records the repository as transferred: the source team permissions, the source data kept in
+// the journal and the moved repository
+func (o *transferOperation) resume() error {
+	entry, ok := resumedEntry(fmt.Sprintf("%s/%s", o.owner, o.repo))
+	if !ok {
 		return nil
 	}
+	moved, err := getMovedRepository(o.client, entry.Target)
+	if err != nil {
+		return err
+	}
+	o.sourceTeamPermissions = entry.Teams
+	if err := o.captured.resume(entry); err != nil {
+		return err
+	}
+	o.transferredID = moved.ID
+	o.fullName = moved.FullName
+	o.visibility = moved.Visibility
+	return nil
+}
 
-	if verbose {
-		fmt.Fprintf(os.Stderr, "Assigning %d pre-collected teams to repository...\n", len(teamNames))
+// resumedTransferResult stands in for the validation of a repository an earlier run already
+// transferred; it goes straight to the steps left
+func resumedTransferResult(entry journal.Entry, owner, repoName string) transferResult {
+	result := transferResult{
+		Repository:      entry.Repository,
+		Owner:           owner,
+		RepoName:        repoName,
+		Success:         true,
+		Mode:            "RESUMED",
+		TeamPermissions: entry.Teams,
+	}
+	for _, team := range entry.Teams {
+		result.Teams = append(result.Teams, team.Name)
 	}
+	return result
+}
 
-	for _, teamName := range teamNames {
-		// Convert team name to slug format
-		teamSlug := strings.ToLower(strings.ReplaceAll(teamName, " ", "-"))
+// plannedTransferSteps lists the steps a transfer of the result would run, for dry-run output
+func plannedTransferSteps(result transferResult) []steps.Step {
+	operation := &transferOperation{owner: result.Owner, repo: result.RepoName, targetOwner: targetOrg, preservePermissions: assign}
+	if assign {
+		operation.teams = result.Teams
+	} else {
+		operation.teams = teamIds
+	}
+	return withoutCompletedSteps(result.Repository, operation.steps())
+}
 
-		// Check if team exists in target organization
-		var teamResponse struct {
-			ID   int    `json:"id"`
-			Name string `json:"name"`
-		}
-		err := client.Get(fmt.Sprintf("orgs/%s/teams/%s", targetOwner, teamSlug), &teamResponse)
-		if err != nil {
-			if verbose {
-				fmt.Fprintf(os.Stderr, "Warning: Team '%s' not found in target org, skipping: %v\n", teamName, err)
-			}
-			continue
-		}
+// executeTransfer performs the actual repository transfer
+func executeTransfer(client api.RESTClient, owner, repo, targetOwner string, teams []string, preservePermissions bool, size *reposize.Stats) error {
+	operation := &transferOperation{
+		client:              client,
+		owner:               owner,
+		repo:                repo,
+		targetOwner:         targetOwner,
+		teams:               teams,
+		preservePermissions: preservePermissions,
+		size:                size,
+	}
+	if err := operation.resume(); err != nil {
+		return err
+	}
+	if _, err := runOperationSteps(fmt.Sprintf("%s/%s", owner, repo), operation.steps()); err != nil {
+		return err
+	}
+	return operation.permissionErr
+}
 
-		// Assign team to repository with default permissions (since we don't have original permissions)
-		// Note: This is a limitation when using transfer command - we only have team names, not permissions
-		assignPayload := map[string]interface{}{
-			"permission": "push", // Default to push/write permissions
-		}
+// assignPreCollectedTeamsToRepo assigns teams to a repository with the permissions collected
+// from the source repository before the transfer (the source repository no longer exists)
+func assignPreCollectedTeamsToRepo(client api.RESTClient, targetOwner, repoName string, sourceTeams []types.Team) []teamAssignment {
+	if verbose {
+		fmt.Fprintf(os.Stderr, "Assigning %d pre-collected teams to repository (up to %d in parallel)...\n", len(sourceTeams), teamAssignmentConcurrency)
+	}
 
-		payloadBytes, err := json.Marshal(assignPayload)
-		if err != nil {
-			if verbose {
-				fmt.Fprintf(os.Stderr, "Warning: Failed to marshal payload for team '%s': %v\n", teamName, err)
-			}
-			continue
-		}
+	assignments := assignTeamsVerified(client, targetOwner, repoName, sourceTeams)
 
-		err = client.Put(fmt.Sprintf("orgs/%s/teams/%s/repos/%s/%s", targetOwner, teamSlug, targetOwner, repoName), bytes.NewBuffer(payloadBytes), nil)
-		if err != nil {
-			if verbose {
-				fmt.Fprintf(os.Stderr, "Warning: Failed to assign team '%s' to repository: %v\n", teamName, err)
+	if verbose {
+		for _, assignment := range assignments {
+			if assignment.Err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: Team '%s' could not be assigned '%s': %v\n", assignment.Team.Name, assignment.Team.Permission, assignment.Err)
+			} else {
+				fmt.Fprintf(os.Stderr, "✅ Team '%s' has '%s' permission (verified)\n", assignment.Team.Name, assignment.Applied)
 			}
-			continue
-		}
-
-		if verbose {
-			fmt.Fprintf(os.Stderr, "✅ Successfully assigned team '%s' with 'push' permission\n", teamName)
 		}
+		fmt.Fprintf(os.Stderr, "✅ Team assignment completed\n")
 	}
-
-	return nil
+	return assignments
 }
 
 // transferResult holds the result of processing a single repository transfer
@@ -481,6 +677,11 @@ type transferResult struct {
 	Error             error
 	Mode              string
 	Teams             []string // Team names from source repository (populated when --assign is used)
+	TeamPermissions   []types.Team       // Source team permissions (populated when --assign is used)
+	PermissionChanges []permissionChange // Source permissions that cannot be applied in the target org
+	OpenItems         *openItemCounts
+	Size              *reposize.Stats // Repository size and largest object, nil when they could not be read
+	Enforceable       bool // Blocked by validation blockers or plan drift, which --interactive can enforce
 }
 
 // processRepoTransfer handles the transfer logic for a single repository
@@ -502,16 +703,17 @@ func processRepoTransferOptimized(client api.RESTClient, owner, repoName string,
 		if verbose {
 			fmt.Fprintf(os.Stderr, "Collecting team information from source repository for assignment...\n")
 		}
-		sourceTeams, err := getRepositoryTeams(client, owner, repoName)
+		sourceTeams, err := repositoryTeams(client, owner, repoName)
 		if err != nil {
 			if verbose {
 				fmt.Fprintf(os.Stderr, "Warning: Could not retrieve teams from source repository: %v\n", err)
 			}
 		} else {
+			result.TeamPermissions = sourceTeams
 			for _, team := range sourceTeams {
 				result.Teams = append(result.Teams, team.Name)
 				if verbose {
-					fmt.Fprintf(os.Stderr, "Found team '%s' in source repository\n", team.Name)
+					fmt.Fprintf(os.Stderr, "Found team '%s' with '%s' permission in source repository\n", team.Name, team.Permission)
 				}
 			}
 		}
@@ -524,6 +726,23 @@ func processRepoTransferOptimized(client api.RESTClient, owner, repoName string,
 		return result
 	}
 
+	// A legal hold is honored regardless of --enforce
+	if err := checkLegalHold(client, owner, repoName); err != nil {
+		result.Error = err
+		result.Success = false
+		return result
+	}
+
+	// Open issues/PRs advisory (best effort)
+	if counts, err := getOpenItemCounts(client, owner, repoName); err == nil {
+		result.OpenItems = counts
+	} else if verbose {
+		fmt.Fprintf(os.Stderr, "Warning: Could not count open issues/pull requests: %v\n", err)
+	}
+
+	// Size advisory (best effort) - large repositories take long to move
+	result.Size = measureRepository(client, owner, repoName)
+
 	// Perform dependency validation unless enforced
 	if !enforce {
 		if verbose {
@@ -537,6 +756,8 @@ func processRepoTransferOptimized(client api.RESTClient, owner, repoName string,
 			result.Success = false
 			return result
 		}
+		recordHistory(result.Repository, history.KindAnalysis, "completed", "", nil)
+		warnPinnedActionConsumers(result.Repository, deps)
 		
 		// If target org is specified, validate against it (use pre-scanned capabilities if available)
 		if targetOrg != "" {
@@ -548,7 +769,7 @@ func processRepoTransferOptimized(client api.RESTClient, owner, repoName string,
 				capabilities = targetCapabilities
 			} else {
 				// Fallback to individual scanning (single repo mode)
-				capabilities, err = validation.ScanTargetOrganization(client, targetOrg, verbose)
+				capabilities, err = scanTargetCapabilities(client, targetOrg)
 				if err != nil {
 					result.Error = fmt.Errorf("failed to scan target organization: %v", err)
 					result.Success = false
@@ -556,16 +777,31 @@ func processRepoTransferOptimized(client api.RESTClient, owner, repoName string,
 				}
 			}
 			
-			validationResult := validation.ValidateAgainstTarget(deps, capabilities, assign)
+			validationResult, drifts := validateAgainstPlan(deps, capabilities, assign)
+			validationResult, drifts, err = provisionAndRevalidate(client, owner, deps, capabilities, validationResult, drifts)
+			if err != nil {
+				result.Error = err
+				result.Success = false
+				return result
+			}
 			result.BlockerCount = validationResult.Summary.Blockers
 			result.ValidationDetails = validationResult
 			
 			if result.BlockerCount > 0 {
 				result.Mode = "BLOCKED"
 				result.Success = false
+				result.Enforceable = true
 				result.Error = fmt.Errorf("❌ Transfer blocked: %d validation blockers found\n%s", result.BlockerCount, formatValidationBlockers(validationResult))
 				return result
 			}
+
+			if err := checkPlanDrift(result.Repository, drifts); err != nil {
+				result.Mode = "BLOCKED"
+				result.Success = false
+				result.Enforceable = true
+				result.Error = fmt.Errorf("❌ Transfer blocked: %v", err)
+				return result
+			}
 			
 			if verbose {
 				fmt.Fprintf(os.Stderr, "✅ No transfer blockers found\n")
@@ -578,10 +814,65 @@ func processRepoTransferOptimized(client api.RESTClient, owner, repoName string,
 		}
 	}
 
+	return checkTransferPermissions(client, result)
+}
+
+// checkTransferPermissions blocks a repository whose source team permissions cannot be applied
+// unchanged in the target org, unless --allow-permission-change accepts them
+func checkTransferPermissions(client api.RESTClient, result transferResult) transferResult {
+	// Source permissions that cannot be applied unchanged need explicit approval
+	result.PermissionChanges = planPermissionChanges(client, targetOrg, result.TeamPermissions)
+	if len(result.PermissionChanges) > 0 && !allowPermissionChange {
+		result.Mode = "BLOCKED"
+		result.Success = false
+		result.Error = fmt.Errorf("❌ Transfer blocked: %d team permission(s) cannot be applied unchanged (use --allow-permission-change to accept)\n%s",
+			len(result.PermissionChanges), formatPermissionChanges(result.PermissionChanges))
+		return result
+	}
+
 	result.Success = true
 	return result
 }
 
+// reviewTransferResults lets the operator enforce, skip or abort each validated repository
+// with findings under --interactive, before anything is transferred
+func reviewTransferResults(client api.RESTClient, results []transferResult) error {
+	review, err := newReviewer()
+	if err != nil || review == nil {
+		return err
+	}
+	for i, result := range results {
+		if result.Mode == "RESUMED" || (!result.Success && !result.Enforceable) {
+			continue
+		}
+		var blockedBy error
+		if !result.Success {
+			blockedBy = result.Error
+		}
+		choice, err := review.reviewFindings("transfer", result.Repository, result.ValidationDetails, blockedBy)
+		if err != nil {
+			return err
+		}
+		switch choice {
+		case reviewAbort:
+			return fmt.Errorf("transfer aborted during review, no repository was transferred")
+		case reviewSkip:
+			results[i].Success = false
+			results[i].Mode = "SKIPPED"
+			results[i].Error = fmt.Errorf("skipped during review")
+		case reviewProceed:
+			if blockedBy == nil {
+				continue
+			}
+			result.Mode = "ENFORCED"
+			result.Error = nil
+			results[i] = checkTransferPermissions(client, result)
+		}
+		recordJournal(runJournal.Validated(result.Repository, fmt.Sprintf("%s/%s", targetOrg, result.RepoName), results[i].Error))
+	}
+	return nil
+}
+
 // formatValidationBlockers formats validation blocker details for error messages
 func formatValidationBlockers(validation *types.MigrationValidation) string {
 	if validation == nil {
@@ -627,6 +918,8 @@ func displayBatchTransferSummary(results []transferResult) error {
 	successCount := 0
 	blockedCount := 0
 	enforcedCount := 0
+	skippedCount := 0
+	effortMinutes := 0
 	
 	for _, result := range results {
 		status := "❌ FAIL"
@@ -635,6 +928,9 @@ func displayBatchTransferSummary(results []transferResult) error {
 			successCount++
 		} else if result.Mode == "BLOCKED" {
 			blockedCount++
+		} else if result.Mode == "SKIPPED" {
+			status = "⏭️  SKIPPED"
+			skippedCount++
 		}
 		
 		if result.Mode == "ENFORCED" {
@@ -642,21 +938,43 @@ func displayBatchTransferSummary(results []transferResult) error {
 		}
 		
 		fmt.Printf("%-50s %s (%s)\n", result.Repository, status, result.Mode)
-		if !result.Success && result.Error != nil {
+		if !result.Success && result.Error != nil && result.Mode != "SKIPPED" {
 			fmt.Printf("  └─ %v\n", result.Error)
 		}
+		if advisory := formatOpenItemsAdvisory(result.OpenItems); advisory != "" {
+			fmt.Printf("  └─ 📬 %s\n", advisory)
+		}
+		for _, warning := range result.Size.Warnings() {
+			fmt.Printf("  └─ 📦 %s\n", warning)
+		}
+		if result.Success {
+			fmt.Printf("  └─ 🪜 Steps: %s\n", formatStepPlan(plannedTransferSteps(result)))
+		}
+		if result.Success && len(result.PermissionChanges) > 0 {
+			fmt.Printf("  └─ 🔐 Accepted team permission changes:\n%s\n", formatPermissionChanges(result.PermissionChanges))
+		}
+		if result.ValidationDetails != nil && result.ValidationDetails.Effort != nil && result.ValidationDetails.Effort.TotalMinutes > 0 {
+			effortMinutes += result.ValidationDetails.Effort.TotalMinutes
+			fmt.Printf("  └─ ⏱️  Estimated remediation effort: %s\n", utils.FormatMinutes(result.ValidationDetails.Effort.TotalMinutes))
+		}
 	}
 	
 	fmt.Printf("\nSummary:\n")
 	fmt.Printf("  Total repositories: %d\n", len(results))
 	fmt.Printf("  Would succeed: %d\n", successCount)
-	fmt.Printf("  Would fail: %d\n", len(results)-successCount)
+	fmt.Printf("  Would fail: %d\n", len(results)-successCount-skippedCount)
+	if skippedCount > 0 {
+		fmt.Printf("  Skipped during review: %d\n", skippedCount)
+	}
 	if blockedCount > 0 {
 		fmt.Printf("  Blocked by validation: %d\n", blockedCount)
 	}
 	if enforcedCount > 0 {
 		fmt.Printf("  Enforced transfers: %d\n", enforcedCount)
 	}
+	if effortMinutes > 0 {
+		fmt.Printf("  Estimated remediation effort: %s\n", utils.FormatMinutes(effortMinutes))
+	}
 	fmt.Printf("  Target: %s\n", targetOrg)
 	
 	return nil
@@ -665,7 +983,18 @@ func displayBatchTransferSummary(results []transferResult) error {
 // handleBatchTransferResults processes actual transfer results
 func handleBatchTransferResults(client api.RESTClient, results []transferResult) error {
 	successCount := 0
+	skipped := 0
 	var failures []string
+
+	ready := 0
+	for _, result := range results {
+		if result.Success {
+			ready++
+		}
+	}
+	if err := confirmBatch("transfer", ready); err != nil {
+		return err
+	}
 	
 	for _, result := range results {
 		if result.Success {
@@ -710,14 +1039,26 @@ func handleBatchTransferResults(client api.RESTClient, results []transferResult)
 				teamsForTransfer = teamIds
 			}
 			
-			if err := executeTransfer(client, result.Owner, result.RepoName, targetOrg, teamsForTransfer, assign); err != nil {
+			deployment := startMigrationDeployment(client, result.Repository, "transfer", targetOrg)
+			err := executeTransfer(client, result.Owner, result.RepoName, targetOrg, teamsForTransfer, assign, result.Size)
+			deployment.finish(client, err, fmt.Sprintf("%s/%s", targetOrg, result.RepoName))
+			if err != nil {
 				failures = append(failures, fmt.Sprintf("%s: transfer execution failed: %v", result.Repository, err))
 				successCount-- // Decrement since this actually failed
+				recordHistory(result.Repository, history.KindTransfer, "failed", targetOrg, map[string]string{"error": err.Error()})
+			} else {
+				recordHistory(result.Repository, history.KindTransfer, "succeeded", targetOrg, nil)
 			}
+		} else if result.Mode == "SKIPPED" {
+			skipped++
 		} else {
 			failures = append(failures, fmt.Sprintf("%s: %v", result.Repository, result.Error))
+			reportMigrationOutcome(client, result.Repository, "transfer", targetOrg, result.Error)
 		}
 	}
+	if skipped > 0 {
+		fmt.Printf("⏭️  Skipped %d repositories during review\n", skipped)
+	}
 	
 	if len(failures) > 0 {
 		fmt.Printf("❌ Batch transfer completed with %d/%d failures:\n", len(failures), len(results))
@@ -733,8 +1074,7 @@ func handleBatchTransferResults(client api.RESTClient, results []transferResult)
 
 // getTeamIdByName looks up a team ID by name in the target organization
 func getTeamIdByName(client api.RESTClient, targetOrg, teamName string) (int, error) {
-	// Convert team name to slug format (lowercase, replace spaces with hyphens)
-	teamSlug := strings.ToLower(strings.ReplaceAll(teamName, " ", "-"))
+	teamSlug, _ := resolveTeamSlug(client, targetOrg, teamName)
 
 	var team struct {
 		ID   int    `json:"id"`
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+
+	"github.com/jefeish/gh-repo-transfer/internal/dependencies"
+	"github.com/jefeish/gh-repo-transfer/internal/ghclient"
+)
+
+// repositoryWebhook captures a repository webhook before the move. The API never returns
+// secrets, so HasSecret only records that one has to be set again.
+type repositoryWebhook struct {
+	Active      bool
+	Events      []string
+	URL         string
+	ContentType string
+	InsecureSSL string
+	HasSecret   bool
+}
+
+// captureRepositoryWebhooks reads the repository's webhooks (requires admin access)
+func captureRepositoryWebhooks(client api.RESTClient, owner, repo string) ([]repositoryWebhook, error) {
+	var hooks []struct {
+		Name   string   `json:"name"`
+		Active bool     `json:"active"`
+		Events []string `json:"events"`
+		Config struct {
+			URL         string `json:"url"`
+			ContentType string `json:"content_type"`
+			InsecureSSL string `json:"insecure_ssl"`
+			Secret      string `json:"secret"`
+		} `json:"config"`
+	}
+	if err := ghclient.GetAll(&client, fmt.Sprintf("repos/%s/%s/hooks", owner, repo), &hooks); err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %v", err)
+	}
+
+	var webhooks []repositoryWebhook
+	for _, hook := range hooks {
+		if hook.Name != "web" || hook.Config.URL == "" {
+			continue
+		}
+		webhooks = append(webhooks, repositoryWebhook{
+			Active:      hook.Active,
+			Events:      hook.Events,
+			URL:         hook.Config.URL,
+			ContentType: hook.Config.ContentType,
+			InsecureSSL: hook.Config.InsecureSSL,
+			HasSecret:   hook.Config.Secret != "",
+		})
+	}
+	return webhooks, nil
+}
+
+// recreateRepositoryWebhooks creates the captured webhooks on the moved repository. Webhooks
+// already delivering to the same URL are left untouched, so running it twice is harmless.
+func recreateRepositoryWebhooks(client api.RESTClient, owner, repo string, webhooks []repositoryWebhook, verboseOutput bool) error {
+	if len(webhooks) == 0 {
+		return nil
+	}
+	existing, err := captureRepositoryWebhooks(client, owner, repo)
+	if err != nil {
+		return err
+	}
+	present := make(map[string]bool)
+	for _, hook := range existing {
+		present[hook.URL] = true
+	}
+
+	var failed, needSecret []string
+	created := 0
+	for _, hook := range webhooks {
+		destination := dependencies.WebhookDestination(hook.URL)
+		if present[hook.URL] {
+			if verboseOutput {
+				fmt.Fprintf(os.Stderr, "Webhook to %s already exists on %s/%s\n", destination, owner, repo)
+			}
+			continue
+		}
+
+		config := map[string]string{"url": hook.URL}
+		if hook.ContentType != "" {
+			config["content_type"] = hook.ContentType
+		}
+		if hook.InsecureSSL != "" {
+			config["insecure_ssl"] = hook.InsecureSSL
+		}
+		payload, err := json.Marshal(map[string]interface{}{
+			"name":   "web",
+			"active": hook.Active,
+			"events": hook.Events,
+			"config": config,
+		})
+		if err != nil {
+			return err
+		}
+		var response map[string]interface{}
+		if err := client.Post(fmt.Sprintf("repos/%s/%s/hooks", owner, repo), bytes.NewBuffer(payload), &response); err != nil {
+			failed = append(failed, fmt.Sprintf("%s (%v)", destination, err))
+			continue
+		}
+		created++
+		if hook.HasSecret {
+			needSecret = append(needSecret, destination)
+		}
+	}
+
+	if created > 0 {
+		fmt.Printf("🪝 Recreated %d webhook(s) on %s/%s\n", created, owner, repo)
+	}
+	for _, destination := range needSecret {
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: Webhook to %s was created without its secret (the API does not return secrets); set it again in the repository settings\n", destination)
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("could not recreate %d webhook(s): %v", len(failed), failed)
+	}
+	return nil
+}
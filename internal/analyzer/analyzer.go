@@ -6,6 +6,7 @@ import (
 
 	"github.com/cli/go-gh/v2/pkg/api"
 	"github.com/jefeish/gh-repo-transfer/internal/dependencies"
+	"github.com/jefeish/gh-repo-transfer/internal/ghclient"
 	"github.com/jefeish/gh-repo-transfer/internal/types"
 )
 
@@ -17,15 +18,36 @@ func AnalyzeOrganizationalDependencies(client api.RESTClient, owner, repo string
 
 	deps := &types.OrganizationalDependencies{
 		Repository: fmt.Sprintf("%s/%s", owner, repo),
+		Path:       dependencies.Subdirectory(owner, repo),
 	}
 
-	// 1. Organization-Specific Code Dependencies
-	if verbose {
-		fmt.Fprintf(os.Stderr, "Analyzing code dependencies...\n")
+	// With --profile each analyzer gets its own client, so its requests are counted separately
+
+	// With --deep auto or never, a fast pass decides whether file contents, code search and
+	// ruleset details are read
+	deps.Depth = dependencies.FastPass(ghclient.Profiled(client, deps.Repository, "fast-pass"), owner, repo)
+	deep := deps.Depth == nil || deps.Depth.Deep
+	if !deep && verbose {
+		fmt.Fprintf(os.Stderr, "Fast pass score %d is below %d, skipping the deep analysis\n", deps.Depth.Score, deps.Depth.Threshold)
+	}
+
+	// List the repository's files once and fetch the ones the analyzers read concurrently
+	if deep {
+		if err := dependencies.PrefetchContents(ghclient.Profiled(client, deps.Repository, "contents"), owner, repo); err != nil && verbose {
+			fmt.Fprintf(os.Stderr, "Warning: failed to prefetch file contents: %v\n", err)
+		}
+		defer dependencies.ReleaseContents(owner, repo)
 	}
-	if err := dependencies.AnalyzeCodeDependencies(client, owner, repo, deps); err != nil {
+
+	// 1. Organization-Specific Code Dependencies (file contents only)
+	if deep {
 		if verbose {
-			fmt.Fprintf(os.Stderr, "Warning: failed to analyze code dependencies: %v\n", err)
+			fmt.Fprintf(os.Stderr, "Analyzing code dependencies...\n")
+		}
+		if err := dependencies.AnalyzeCodeDependencies(ghclient.Profiled(client, deps.Repository, "code"), owner, repo, deps); err != nil {
+			if verbose {
+				fmt.Fprintf(os.Stderr, "Warning: failed to analyze code dependencies: %v\n", err)
+			}
 		}
 	}
 
@@ -33,7 +55,11 @@ func AnalyzeOrganizationalDependencies(client api.RESTClient, owner, repo string
 	if verbose {
 		fmt.Fprintf(os.Stderr, "Analyzing CI/CD dependencies...\n")
 	}
-	if err := dependencies.AnalyzeActionsCIDependencies(client, owner, repo, deps); err != nil {
+	analyzeActionsCI := dependencies.AnalyzeActionsCIDependencies
+	if !deep {
+		analyzeActionsCI = dependencies.AnalyzeActionsCISettings
+	}
+	if err := analyzeActionsCI(ghclient.Profiled(client, deps.Repository, "actions-ci"), owner, repo, deps); err != nil {
 		if verbose {
 			fmt.Fprintf(os.Stderr, "Warning: failed to analyze Actions/CI dependencies: %v\n", err)
 		}
@@ -43,7 +69,7 @@ func AnalyzeOrganizationalDependencies(client api.RESTClient, owner, repo string
 	if verbose {
 		fmt.Fprintf(os.Stderr, "Analyzing access control dependencies...\n")
 	}
-	if err := dependencies.AnalyzeAccessPermissions(client, owner, repo, deps); err != nil {
+	if err := dependencies.AnalyzeAccessPermissions(ghclient.Profiled(client, deps.Repository, "access"), owner, repo, deps); err != nil {
 		if verbose {
 			fmt.Fprintf(os.Stderr, "Warning: failed to analyze access control dependencies: %v\n", err)
 		}
@@ -53,7 +79,7 @@ func AnalyzeOrganizationalDependencies(client api.RESTClient, owner, repo string
 	if verbose {
 		fmt.Fprintf(os.Stderr, "Analyzing security compliance dependencies...\n")
 	}
-	if err := dependencies.AnalyzeSecurityCompliance(client, owner, repo, deps); err != nil {
+	if err := dependencies.AnalyzeSecurityCompliance(ghclient.Profiled(client, deps.Repository, "security"), owner, repo, deps); err != nil {
 		if verbose {
 			fmt.Fprintf(os.Stderr, "Warning: failed to analyze security compliance dependencies: %v\n", err)
 		}
@@ -63,7 +89,7 @@ func AnalyzeOrganizationalDependencies(client api.RESTClient, owner, repo string
 	if verbose {
 		fmt.Fprintf(os.Stderr, "Analyzing apps and integrations dependencies...\n")
 	}
-	if err := dependencies.AnalyzeAppsIntegrations(client, owner, repo, deps); err != nil {
+	if err := dependencies.AnalyzeAppsIntegrations(ghclient.Profiled(client, deps.Repository, "apps"), owner, repo, deps); err != nil {
 		if verbose {
 			fmt.Fprintf(os.Stderr, "Warning: failed to analyze apps and integrations dependencies: %v\n", err)
 		}
@@ -73,7 +99,11 @@ func AnalyzeOrganizationalDependencies(client api.RESTClient, owner, repo string
 	if verbose {
 		fmt.Fprintf(os.Stderr, "Analyzing governance dependencies...\n")
 	}
-	if err := dependencies.AnalyzeOrgGovernance(client, owner, repo, deps); err != nil {
+	analyzeGovernance := dependencies.AnalyzeOrgGovernance
+	if !deep {
+		analyzeGovernance = dependencies.AnalyzeOrgGovernanceSettings
+	}
+	if err := analyzeGovernance(ghclient.Profiled(client, deps.Repository, "governance"), owner, repo, deps); err != nil {
 		if verbose {
 			fmt.Fprintf(os.Stderr, "Warning: failed to analyze governance dependencies: %v\n", err)
 		}
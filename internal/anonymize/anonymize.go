@@ -0,0 +1,291 @@
+package anonymize
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+// Anonymizer replaces organization, repository, team and user names with hashed tokens.
+// The same name maps to the same token within a run, so the repositories of one report stay
+// comparable; the hash is keyed with a random key per run, so tokens cannot be reversed by
+// hashing candidate names.
+type Anonymizer struct {
+	key    []byte
+	tokens map[string]string
+	// phrases are registered names that are not a single identifier, such as team display names
+	// with spaces, longest first
+	phrases []phrase
+}
+
+type phrase struct {
+	name    string
+	pattern *regexp.Regexp
+	token   string
+}
+
+// identifierPattern matches runs of characters GitHub allows in org, repo, team and user names
+var identifierPattern = regexp.MustCompile(`[A-Za-z0-9_.\-]+`)
+
+// New creates an empty Anonymizer with a random hashing key
+func New() (*Anonymizer, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to create the anonymization key: %v", err)
+	}
+	return &Anonymizer{key: key, tokens: make(map[string]string)}, nil
+}
+
+// Token returns the hashed token for a name, e.g. "team-1a2b3c4d"
+func (a *Anonymizer) Token(kind, name string) string {
+	mac := hmac.New(sha256.New, a.key)
+	mac.Write([]byte(strings.ToLower(name)))
+	return kind + "-" + hex.EncodeToString(mac.Sum(nil))[:8]
+}
+
+// Register adds a name of the given kind (org, repo, team, user) to the replacement set
+func (a *Anonymizer) Register(kind, name string) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return
+	}
+	key := strings.ToLower(name)
+	if _, exists := a.tokens[key]; exists {
+		return
+	}
+	token := a.Token(kind, name)
+	a.tokens[key] = token
+	if identifierPattern.FindString(name) == name {
+		return
+	}
+	a.phrases = append(a.phrases, phrase{name, regexp.MustCompile(`(?i)` + regexp.QuoteMeta(name)), token})
+	sort.SliceStable(a.phrases, func(i, j int) bool {
+		return len(a.phrases[i].name) > len(a.phrases[j].name)
+	})
+}
+
+// CollectDependencies registers every identifying name found in a dependency report
+func (a *Anonymizer) CollectDependencies(deps *types.OrganizationalDependencies) {
+	if deps == nil {
+		return
+	}
+
+	if parts := strings.SplitN(deps.Repository, "/", 2); len(parts) == 2 {
+		a.Register("org", parts[0])
+		a.Register("repo", parts[1])
+	}
+	if deps.Validation != nil {
+		a.Register("org", deps.Validation.TargetOrganization)
+	}
+	if deps.WhatIf != nil {
+		if parts := strings.SplitN(deps.WhatIf.TargetRepository, "/", 2); len(parts) == 2 {
+			a.Register("org", parts[0])
+			a.Register("repo", parts[1])
+		}
+	}
+
+	for _, team := range deps.AccessPermissions.Teams {
+		a.Register("team", nameBeforeQualifier(team))
+	}
+	for _, collaborator := range deps.AccessPermissions.IndividualCollaborators {
+		a.Register("user", nameBeforeQualifier(collaborator))
+	}
+	for _, reviewer := range deps.ActionsCIDependencies.EnvironmentReviewers {
+		name := strings.SplitN(reviewer, " (environment: ", 2)[0]
+		if team, ok := strings.CutPrefix(name, "team:"); ok {
+			a.Register("team", team)
+		} else {
+			a.Register("user", strings.TrimPrefix(name, "user:"))
+		}
+	}
+	for _, consumers := range [][]string{deps.ActionsCIDependencies.PinnedActionConsumers, deps.ActionsCIDependencies.DispatchConsumers} {
+		for _, consumer := range consumers {
+			repository := strings.SplitN(consumer, ":", 2)[0]
+			if parts := strings.SplitN(repository, "/", 2); len(parts) == 2 {
+				a.Register("org", parts[0])
+				a.Register("repo", parts[1])
+			}
+		}
+	}
+	if deps.BillingImpact != nil {
+		for _, login := range deps.BillingImpact.NewSeatUsers {
+			a.Register("user", login)
+		}
+		for _, login := range deps.BillingImpact.NewCommitters {
+			a.Register("user", login)
+		}
+	}
+	for _, requirement := range deps.AccessPermissions.CodeownersRequirements {
+		if address, ok := strings.CutPrefix(requirement, "Email: "); ok {
+			// The domain is kept, the mailbox is the user
+			a.Register("user", strings.SplitN(address, "@", 2)[0])
+			continue
+		}
+		idx := strings.Index(requirement, "@")
+		if idx == -1 {
+			continue
+		}
+		fields := strings.Fields(requirement[idx+1:])
+		if len(fields) == 0 {
+			continue
+		}
+		owner := fields[0]
+		if parts := strings.SplitN(owner, "/", 2); len(parts) == 2 {
+			a.Register("org", parts[0])
+			a.Register("team", parts[1])
+		} else {
+			a.Register("user", owner)
+		}
+	}
+}
+
+// String replaces the registered names in s with their tokens. Names that are not a single
+// identifier, such as "Platform Engineering", are replaced as whole strings first, longest
+// first; the others only match whole identifiers so "api" does not rewrite "api-gateway".
+// Organization names are replaced wherever they occur. Repository, team and user names can be
+// ordinary words, such as a team named "write", so they are only replaced where they identify
+// something: as the value itself ("platform", "octocat (write)", "team:platform (...)"), after
+// a registered organization ("acme/platform") or an @ ("@octocat"), or as the mailbox of an
+// email address.
+func (a *Anonymizer) String(s string) string {
+	if len(a.tokens) == 0 || s == "" {
+		return s
+	}
+	for _, p := range a.phrases {
+		s = replacePhrase(s, p)
+	}
+
+	var b strings.Builder
+	last, orgEnd := 0, -1
+	for _, match := range identifierPattern.FindAllStringIndex(s, -1) {
+		start, end := match[0], match[1]
+		token, ok := a.tokens[strings.ToLower(s[start:end])]
+		if !ok {
+			// Sentence punctuation is not part of the name
+			end = start + len(strings.TrimRight(s[start:end], "."))
+			token, ok = a.tokens[strings.ToLower(s[start:end])]
+		}
+		if !ok {
+			continue
+		}
+		kind := strings.SplitN(token, "-", 2)[0]
+		if !identifies(s, start, end, kind, orgEnd == start-1) {
+			continue
+		}
+		if kind == "org" && end < len(s) && s[end] == '/' {
+			orgEnd = end
+		}
+		b.WriteString(s[last:start])
+		b.WriteString(token)
+		last = end
+	}
+	if last == 0 {
+		return s
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+// identifies reports whether the name of a kind at s[start:end] identifies something rather
+// than being an ordinary word (see String); qualified is set when it follows "org/"
+func identifies(s string, start, end int, kind string, qualified bool) bool {
+	switch {
+	case kind == "org", qualified:
+		return true
+	case start > 0 && s[start-1] == '@':
+		return true
+	case kind == "user" && end < len(s) && s[end] == '@':
+		return true
+	}
+	if prefix := s[:start]; prefix != "" && prefix != "team:" && prefix != "user:" {
+		return false
+	}
+	return end == len(s) || strings.HasPrefix(s[end:], " (")
+}
+
+// Apply rewrites all string fields reachable from v (a pointer) in place
+func (a *Anonymizer) Apply(v interface{}) {
+	a.walk(reflect.ValueOf(v))
+}
+
+func (a *Anonymizer) walk(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			a.walk(v.Elem())
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				a.walk(v.Field(i))
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			a.walk(v.Index(i))
+		}
+	case reflect.Map:
+		// Map entries are not addressable, so each one is rewritten on a copy and stored again,
+		// under the rewritten key when the keys are strings
+		keys := v.MapKeys()
+		for _, key := range keys {
+			value := reflect.New(v.Type().Elem()).Elem()
+			value.Set(v.MapIndex(key))
+			a.walk(value)
+			newKey := key
+			if key.Kind() == reflect.String {
+				newKey = reflect.New(key.Type()).Elem()
+				newKey.SetString(a.String(key.String()))
+				if newKey.String() != key.String() {
+					v.SetMapIndex(key, reflect.Value{})
+				}
+			}
+			v.SetMapIndex(newKey, value)
+		}
+	case reflect.String:
+		if v.CanSet() {
+			v.SetString(a.String(v.String()))
+		}
+	}
+}
+
+// replacePhrase replaces the occurrences of a phrase that are not part of a longer identifier
+func replacePhrase(s string, p phrase) string {
+	var b strings.Builder
+	last := 0
+	for _, match := range p.pattern.FindAllStringIndex(s, -1) {
+		start, end := match[0], match[1]
+		if start > 0 && isIdentifierByte(s[start-1]) || end < len(s) && isIdentifierByte(s[end]) {
+			continue
+		}
+		b.WriteString(s[last:start])
+		b.WriteString(p.token)
+		last = end
+	}
+	if last == 0 {
+		return s
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+// isIdentifierByte reports whether c continues a name; a trailing "." is sentence punctuation
+func isIdentifierByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-'
+}
+
+// nameBeforeQualifier strips the " (permission)" suffix used in report items
+func nameBeforeQualifier(item string) string {
+	if idx := strings.Index(item, " ("); idx != -1 {
+		return item[:idx]
+	}
+	return item
+}
@@ -0,0 +1,135 @@
+package anonymize
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+func TestApplyDependencies(t *testing.T) {
+	deps := &types.OrganizationalDependencies{
+		Repository: "acme/widget",
+		AccessPermissions: types.AccessPermissions{
+			Teams:                   []string{"platform (admin)"},
+			IndividualCollaborators: []string{"octocat (write)"},
+			CodeownersRequirements:  []string{"Team: @acme/platform", "User: @octocat"},
+		},
+		CodeDependencies: types.CodeDependencies{
+			InternalRepositoryReferences: []string{"acme/widget-sdk", "See acme/widget."},
+		},
+	}
+
+	anonymizer := newAnonymizer(t)
+	anonymizer.CollectDependencies(deps)
+	anonymizer.Apply(deps)
+
+	org, repo, team, user := anonymizer.Token("org", "acme"), anonymizer.Token("repo", "widget"), anonymizer.Token("team", "platform"), anonymizer.Token("user", "octocat")
+
+	checks := [][2]string{
+		{deps.Repository, org + "/" + repo},
+		{deps.AccessPermissions.Teams[0], team + " (admin)"},
+		{deps.AccessPermissions.IndividualCollaborators[0], user + " (write)"},
+		{deps.AccessPermissions.CodeownersRequirements[0], "Team: @" + org + "/" + team},
+		{deps.AccessPermissions.CodeownersRequirements[1], "User: @" + user},
+		{deps.CodeDependencies.InternalRepositoryReferences[0], org + "/widget-sdk"},
+		{deps.CodeDependencies.InternalRepositoryReferences[1], "See " + org + "/" + repo + "."},
+	}
+	for _, check := range checks {
+		if check[0] != check[1] {
+			t.Errorf("got %q, want %q", check[0], check[1])
+		}
+	}
+}
+
+func TestApplyDisplayNamesAndMapKeys(t *testing.T) {
+	deps := &types.OrganizationalDependencies{
+		Repository: "acme/widget",
+		AccessPermissions: types.AccessPermissions{
+			Teams: []string{"Platform Engineering (admin)", "Platform (push)"},
+		},
+		CodeDependencies: types.CodeDependencies{
+			InternalRepositoryReferences: []string{"Owned by platform engineering and @Platform."},
+		},
+	}
+	capabilities := &types.TargetOrgCapabilities{
+		TeamSlugs: map[string]string{"Platform Engineering": "platform"},
+	}
+
+	anonymizer := newAnonymizer(t)
+	anonymizer.CollectDependencies(deps)
+	anonymizer.Apply(deps)
+	anonymizer.Apply(capabilities)
+
+	display, platform := anonymizer.Token("team", "Platform Engineering"), anonymizer.Token("team", "Platform")
+	checks := [][2]string{
+		{deps.AccessPermissions.Teams[0], display + " (admin)"},
+		{deps.AccessPermissions.Teams[1], platform + " (push)"},
+		{deps.CodeDependencies.InternalRepositoryReferences[0], "Owned by " + display + " and @" + platform + "."},
+	}
+	for _, check := range checks {
+		if check[0] != check[1] {
+			t.Errorf("got %q, want %q", check[0], check[1])
+		}
+	}
+	if slug, ok := capabilities.TeamSlugs[display]; !ok || slug != platform || len(capabilities.TeamSlugs) != 1 {
+		t.Errorf("map keys not anonymized: %v", capabilities.TeamSlugs)
+	}
+}
+
+func TestApplyKeepsWordsThatAreNames(t *testing.T) {
+	deps := &types.OrganizationalDependencies{
+		Repository: "acme/widget",
+		AccessPermissions: types.AccessPermissions{
+			Teams:                   []string{"write (admin)", "admin (write)"},
+			IndividualCollaborators: []string{"docs (read)"},
+		},
+		ActionsCIDependencies: types.ActionsCIDependencies{
+			EnvironmentReviewers: []string{"team:admin (environment: production)"},
+		},
+		CodeDependencies: types.CodeDependencies{
+			InternalRepositoryReferences: []string{"acme/write needs write access, see docs/admin.md", "Contact docs@example.com or @docs"},
+		},
+	}
+
+	anonymizer := newAnonymizer(t)
+	anonymizer.CollectDependencies(deps)
+	anonymizer.Apply(deps)
+
+	org, write, admin, docs := anonymizer.Token("org", "acme"), anonymizer.Token("team", "write"), anonymizer.Token("team", "admin"), anonymizer.Token("user", "docs")
+	checks := [][2]string{
+		{deps.AccessPermissions.Teams[0], write + " (admin)"},
+		{deps.AccessPermissions.Teams[1], admin + " (write)"},
+		{deps.AccessPermissions.IndividualCollaborators[0], docs + " (read)"},
+		{deps.ActionsCIDependencies.EnvironmentReviewers[0], "team:" + admin + " (environment: production)"},
+		{deps.CodeDependencies.InternalRepositoryReferences[0], org + "/" + write + " needs write access, see docs/admin.md"},
+		{deps.CodeDependencies.InternalRepositoryReferences[1], "Contact " + docs + "@example.com or @" + docs},
+	}
+	for _, check := range checks {
+		if check[0] != check[1] {
+			t.Errorf("got %q, want %q", check[0], check[1])
+		}
+	}
+}
+
+func TestTokenIsKeyedPerRun(t *testing.T) {
+	anonymizer := newAnonymizer(t)
+	if anonymizer.Token("team", "Platform") != anonymizer.Token("team", "platform") {
+		t.Error("Token() should be case-insensitive")
+	}
+	if token := anonymizer.Token("user", "octocat"); !strings.HasPrefix(token, "user-") || len(token) != len("user-")+8 {
+		t.Errorf("unexpected token format: %s", token)
+	}
+	if anonymizer.Token("user", "octocat") == newAnonymizer(t).Token("user", "octocat") {
+		t.Error("Token() should depend on the key of the run")
+	}
+}
+
+func newAnonymizer(t *testing.T) *Anonymizer {
+	t.Helper()
+	anonymizer, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return anonymizer
+}
@@ -0,0 +1,45 @@
+// Package archivename recovers the original name of an archived repository from its archived
+// name, for archives that carry no repo-origin property
+package archivename
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// DefaultPattern matches the "<name>-<UID>" names given by archive: a suffix of 6 to 10
+// upper-case letters and digits
+const DefaultPattern = `^(.+)-[A-Z0-9]{6,10}$`
+
+// Compile compiles an archived name pattern; it must capture the original name in its first
+// (or "name") group
+func Compile(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		pattern = DefaultPattern
+	}
+	expression, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid archived name pattern %q: %v", pattern, err)
+	}
+	if expression.NumSubexp() == 0 {
+		return nil, fmt.Errorf("archived name pattern %q must capture the original name in a group", pattern)
+	}
+	return expression, nil
+}
+
+// Original returns the original name captured by pattern, or false when the archived name
+// does not match or the captured name is empty
+func Original(pattern *regexp.Regexp, archivedName string) (string, bool) {
+	match := pattern.FindStringSubmatch(archivedName)
+	if match == nil {
+		return "", false
+	}
+	group := 1
+	if index := pattern.SubexpIndex("name"); index > 0 {
+		group = index
+	}
+	if match[group] == "" {
+		return "", false
+	}
+	return match[group], true
+}
@@ -0,0 +1,42 @@
+package archivename
+
+import "testing"
+
+func TestOriginal(t *testing.T) {
+	tests := []struct {
+		name         string
+		pattern      string
+		archivedName string
+		want         string
+		wantOK       bool
+	}{
+		{"default UID suffix", "", "payments-api-2JKLX9A7", "payments-api", true},
+		{"default without suffix", "", "payments-api", "", false},
+		{"default lower-case suffix", "", "payments-api-old", "", false},
+		{"older date suffix", `^(.+)_archived_\d{8}$`, "billing_archived_20230115", "billing", true},
+		{"named group", `^(archived-)?(?P<name>.+)-v\d+$`, "archived-web-v2", "web", true},
+		{"empty capture", `^(.*)-[A-Z]+$`, "-ABC", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pattern, err := Compile(tt.pattern)
+			if err != nil {
+				t.Fatalf("Compile() error = %v", err)
+			}
+			got, ok := Original(pattern, tt.archivedName)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("Original(%q) = %q, %v, want %q, %v", tt.archivedName, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestCompileRequiresGroup(t *testing.T) {
+	if _, err := Compile(`^.+-[A-Z0-9]{8}$`); err == nil {
+		t.Error("Compile() accepted a pattern without a capture group")
+	}
+	if _, err := Compile(`^(.+`); err == nil {
+		t.Error("Compile() accepted an invalid pattern")
+	}
+}
@@ -8,6 +8,8 @@ import (
 
 	"github.com/cli/go-gh/v2/pkg/api"
 	"github.com/jefeish/gh-repo-transfer/internal/dependencies"
+	"github.com/jefeish/gh-repo-transfer/internal/ghclient"
+	"github.com/jefeish/gh-repo-transfer/internal/graphqlscan"
 	"github.com/jefeish/gh-repo-transfer/internal/types"
 )
 
@@ -44,19 +46,45 @@ type BatchAnalysisResult struct {
 
 // BatchAnalyzer handles batch analysis of multiple repositories
 type BatchAnalyzer struct {
-	client  api.RESTClient
-	verbose bool
-	orgCtx  *OrganizationContext
+	client      api.RESTClient
+	verbose     bool
+	orgCtx      *OrganizationContext
+	concurrency int
+
+	// Optional GraphQL backend (--graphql) and what it read, keyed by lower-cased repository name
+	graphql graphqlscan.Querier
+	scanned map[string]*graphqlscan.Repository
+
+	onResult      func(BatchAnalysisResult)
+	onResultMutex sync.Mutex
 }
 
 // NewBatchAnalyzer creates a new batch analyzer
 func NewBatchAnalyzer(client api.RESTClient, verbose bool) *BatchAnalyzer {
 	return &BatchAnalyzer{
-		client:  client,
-		verbose: verbose,
+		client:      client,
+		verbose:     verbose,
+		concurrency: DefaultConcurrency,
 	}
 }
 
+// SetConcurrency limits how many repositories are analyzed at once (DefaultConcurrency by default)
+func (ba *BatchAnalyzer) SetConcurrency(concurrency int) {
+	ba.concurrency = concurrency
+}
+
+// OnResult registers a handler called as soon as each repository's analysis completes.
+// Calls are serialized, so the handler does not need its own locking.
+func (ba *BatchAnalyzer) OnResult(handler func(BatchAnalysisResult)) {
+	ba.onResult = handler
+}
+
+// UseGraphQL reads repository metadata, branch protection rules, teams and collaborators of
+// all repositories with batched GraphQL queries before the per-repository REST analysis
+func (ba *BatchAnalyzer) UseGraphQL(client graphqlscan.Querier) {
+	ba.graphql = client
+}
+
 // AnalyzeRepositories performs batch analysis on multiple repositories in the same organization
 func (ba *BatchAnalyzer) AnalyzeRepositories(repos []string) ([]BatchAnalysisResult, error) {
 	if len(repos) == 0 {
@@ -81,30 +109,33 @@ func (ba *BatchAnalyzer) AnalyzeRepositories(repos []string) ([]BatchAnalysisRes
 	}
 	ba.orgCtx = orgCtx
 
+	if ba.graphql != nil {
+		ba.scanRepositories(owner, repos)
+	}
+
 	// Step 2: Analyze each repository with shared org context
 	results := make([]BatchAnalysisResult, len(repos))
 	
-	// Use goroutines for parallel repository analysis
-	var wg sync.WaitGroup
-	for i, repo := range repos {
-		wg.Add(1)
-		go func(index int, repository string) {
-			defer wg.Done()
-			
-			if ba.verbose {
-				fmt.Fprintf(os.Stderr, "Analyzing repository: %s\n", repository)
-			}
-			
-			result, err := ba.analyzeRepositoryWithContext(repository)
-			results[index] = BatchAnalysisResult{
-				Repository: repository,
-				Result:     result,
-				Error:      err,
-			}
-		}(i, repo)
-	}
-	
-	wg.Wait()
+	// Analyze repositories in parallel on a bounded number of workers
+	ForEach(len(repos), ba.concurrency, func(index int) {
+		repository := repos[index]
+		if ba.verbose {
+			fmt.Fprintf(os.Stderr, "Analyzing repository: %s\n", repository)
+		}
+
+		result, err := ba.analyzeRepositoryWithContext(repository)
+		results[index] = BatchAnalysisResult{
+			Repository: repository,
+			Result:     result,
+			Error:      err,
+		}
+
+		if ba.onResult != nil {
+			ba.onResultMutex.Lock()
+			ba.onResult(results[index])
+			ba.onResultMutex.Unlock()
+		}
+	})
 	
 	if ba.verbose {
 		fmt.Fprintf(os.Stderr, "Batch analysis completed for %d repositories\n", len(repos))
@@ -198,6 +229,11 @@ func (ba *BatchAnalyzer) analyzeRepositoryWithContext(repoSpec string) (*types.O
 		return nil, err
 	}
 
+	scanned := ba.scanned[strings.ToLower(repo)]
+	if scanned != nil && !scanned.Found {
+		return nil, fmt.Errorf("repository %s not found", repoSpec)
+	}
+
 	deps := &types.OrganizationalDependencies{
 		Repository: repoSpec,
 	}
@@ -221,23 +257,42 @@ func (ba *BatchAnalyzer) analyzeRepositoryWithContext(repoSpec string) (*types.O
 		}
 	}
 
+	// With --deep auto or never, a fast pass decides whether file contents, code search and
+	// ruleset details are read
+	deps.Depth = dependencies.FastPass(ghclient.Profiled(ba.client, repoSpec, "fast-pass"), owner, repo)
+	deep := deps.Depth == nil || deps.Depth.Deep
+
+	// List the repository's files once and fetch the ones the analyzers read concurrently
+	if deep {
+		if err := dependencies.PrefetchContents(ghclient.Profiled(ba.client, repoSpec, "contents"), owner, repo); err != nil && ba.verbose {
+			addError(fmt.Errorf("file contents: %v", err))
+		}
+		defer dependencies.ReleaseContents(owner, repo)
+	}
+
 	// Repository-specific analyses (these must be done per repo)
 	
-	// 1. Code Dependencies (repository-specific)
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		err := dependencies.AnalyzeCodeDependencies(ba.client, owner, repo, deps)
-		if err != nil && ba.verbose {
-			addError(fmt.Errorf("code dependencies: %v", err))
-		}
-	}()
+	// 1. Code Dependencies (repository-specific, file contents only)
+	if deep {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := dependencies.AnalyzeCodeDependencies(ghclient.Profiled(ba.client, repoSpec, "code"), owner, repo, deps)
+			if err != nil && ba.verbose {
+				addError(fmt.Errorf("code dependencies: %v", err))
+			}
+		}()
+	}
 
 	// 2. CI/CD Dependencies (repository-specific)
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		err := dependencies.AnalyzeActionsCIDependencies(ba.client, owner, repo, deps)
+		analyzeActionsCI := dependencies.AnalyzeActionsCIDependencies
+		if !deep {
+			analyzeActionsCI = dependencies.AnalyzeActionsCISettings
+		}
+		err := analyzeActionsCI(ghclient.Profiled(ba.client, repoSpec, "actions-ci"), owner, repo, deps)
 		if err != nil && ba.verbose {
 			addError(fmt.Errorf("CI/CD dependencies: %v", err))
 		}
@@ -247,7 +302,11 @@ func (ba *BatchAnalyzer) analyzeRepositoryWithContext(repoSpec string) (*types.O
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		err := dependencies.AnalyzeAccessPermissions(ba.client, owner, repo, deps)
+		var prefetched *dependencies.PrefetchedAccess
+		if scanned != nil {
+			prefetched = &scanned.Access
+		}
+		err := dependencies.AnalyzeAccessPermissionsPrefetched(ghclient.Profiled(ba.client, repoSpec, "access"), owner, repo, deps, prefetched)
 		if err != nil && ba.verbose {
 			addError(fmt.Errorf("access permissions: %v", err))
 		}
@@ -257,17 +316,30 @@ func (ba *BatchAnalyzer) analyzeRepositoryWithContext(repoSpec string) (*types.O
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		err := dependencies.AnalyzeSecurityCompliance(ba.client, owner, repo, deps)
+		err := dependencies.AnalyzeSecurityCompliance(ghclient.Profiled(ba.client, repoSpec, "security"), owner, repo, deps)
 		if err != nil && ba.verbose {
 			addError(fmt.Errorf("security compliance: %v", err))
 		}
 	}()
 
-	// 5. Repository-specific Governance (Repository Policies and Repository Rulesets only)
+	// 5. Repository webhooks and Pages (repository-specific; org apps come from the context)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		client := ghclient.Profiled(ba.client, repoSpec, "apps")
+		if err := dependencies.AnalyzeRepositoryWebhooks(client, owner, repo, deps); err != nil && ba.verbose {
+			addError(fmt.Errorf("webhooks: %v", err))
+		}
+		if err := dependencies.AnalyzePages(client, owner, repo, deps); err != nil && ba.verbose {
+			addError(fmt.Errorf("pages: %v", err))
+		}
+	}()
+
+	// 6. Repository-specific Governance (Repository Policies and Repository Rulesets only)
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		err := ba.analyzeRepositorySpecificGovernance(owner, repo, deps)
+		err := ba.analyzeRepositorySpecificGovernance(owner, repo, deep, deps)
 		if err != nil && ba.verbose {
 			addError(fmt.Errorf("repository governance: %v", err))
 		}
@@ -275,6 +347,11 @@ func (ba *BatchAnalyzer) analyzeRepositoryWithContext(repoSpec string) (*types.O
 
 	wg.Wait()
 
+	// Branch protection rules are only read in bulk, by the GraphQL backend
+	if scanned != nil && scanned.BranchProtectionComplete {
+		dependencies.AddBranchProtectionPolicy(scanned.BranchProtectionRules, deps)
+	}
+
 	// Log warnings but don't fail
 	if len(errs) > 0 && ba.verbose {
 		for _, err := range errs {
@@ -285,21 +362,42 @@ func (ba *BatchAnalyzer) analyzeRepositoryWithContext(repoSpec string) (*types.O
 	return deps, nil
 }
 
+// scanRepositories runs the GraphQL scan; on failure the REST analysis reads everything itself
+func (ba *BatchAnalyzer) scanRepositories(owner string, repos []string) {
+	var names []string
+	for _, repoSpec := range repos {
+		if _, repo, err := parseRepository(repoSpec); err == nil {
+			names = append(names, repo)
+		}
+	}
+	if ba.verbose {
+		fmt.Fprintf(os.Stderr, "Scanning %d repositories with GraphQL (%d per query)...\n", len(names), graphqlscan.ChunkSize)
+	}
+	scanned, err := graphqlscan.Scan(ba.graphql, owner, names)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: GraphQL scan of %s failed, falling back to REST: %v\n", owner, err)
+		return
+	}
+	ba.scanned = scanned
+}
+
 // Helper functions for loading organization-level data
 func (ba *BatchAnalyzer) loadOrganizationApps(owner string, ctx *OrganizationContext) error {
-	return dependencies.AnalyzeAppsIntegrationsOrgLevel(ba.client, owner, &ctx.Apps)
+	return dependencies.AnalyzeAppsIntegrationsOrgLevel(ghclient.Profiled(ba.client, owner, "org-context"), owner, &ctx.Apps)
 }
 
 func (ba *BatchAnalyzer) loadOrganizationGovernance(owner string, ctx *OrganizationContext) error {
-	return dependencies.AnalyzeOrgGovernanceOrgLevel(ba.client, owner, &ctx.Governance)
+	return dependencies.AnalyzeOrgGovernanceOrgLevel(ghclient.Profiled(ba.client, owner, "org-context"), owner, &ctx.Governance)
 }
 
 func (ba *BatchAnalyzer) loadOrganizationInfo(owner string, ctx *OrganizationContext) error {
-	return ba.client.Get(fmt.Sprintf("orgs/%s", owner), &ctx.OrgInfo)
+	client := ghclient.Profiled(ba.client, owner, "org-context")
+	return client.Get(fmt.Sprintf("orgs/%s", owner), &ctx.OrgInfo)
 }
 
-// analyzeRepositorySpecificGovernance analyzes only the repository-specific governance parts
-func (ba *BatchAnalyzer) analyzeRepositorySpecificGovernance(owner, repo string, deps *types.OrganizationalDependencies) error {
+// analyzeRepositorySpecificGovernance analyzes only the repository-specific governance parts.
+// Commit signing, push rules and community health files are read by the deep analysis only.
+func (ba *BatchAnalyzer) analyzeRepositorySpecificGovernance(owner, repo string, deep bool, deps *types.OrganizationalDependencies) error {
 	// Filter organization-level rulesets to find ones that target this specific repository
 	if ba.orgCtx != nil {
 		if err := ba.filterOrgRulesetsForRepo(owner, repo, &ba.orgCtx.Governance, deps); err != nil {
@@ -309,6 +407,22 @@ func (ba *BatchAnalyzer) analyzeRepositorySpecificGovernance(owner, repo string,
 		}
 	}
 
+	if !deep {
+		return nil
+	}
+
+	// Signing requirements depend on the repository's default branch and settings
+	client := ghclient.Profiled(ba.client, owner+"/"+repo, "governance")
+	if err := dependencies.AnalyzeCommitSigning(client, owner, repo, deps); err != nil && ba.verbose {
+		fmt.Fprintf(os.Stderr, "Could not analyze commit signing for %s: %v\n", repo, err)
+	}
+	if err := dependencies.AnalyzePushRules(client, owner, repo, deps); err != nil && ba.verbose {
+		fmt.Fprintf(os.Stderr, "Could not analyze push rulesets for %s: %v\n", repo, err)
+	}
+	if err := dependencies.AnalyzeCommunityHealthFiles(client, owner, repo, deps); err != nil && ba.verbose {
+		fmt.Fprintf(os.Stderr, "Could not analyze community health files for %s: %v\n", repo, err)
+	}
+
 	return nil
 }
 
@@ -317,7 +431,8 @@ func (ba *BatchAnalyzer) loadSecurityCampaigns(owner string, ctx *OrganizationCo
 		ID   int    `json:"id"`
 		Name string `json:"name"`
 	}
-	err := ba.client.Get(fmt.Sprintf("orgs/%s/security/campaigns", owner), &campaigns)
+	client := ghclient.Profiled(ba.client, owner, "org-context")
+	err := ghclient.GetAll(&client, fmt.Sprintf("orgs/%s/security/campaigns", owner), &campaigns)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,35 @@
+package batch
+
+import "sync"
+
+// DefaultConcurrency is how many repositories are processed at once unless --concurrency says
+// otherwise; more parallel requests trip GitHub's secondary rate limits on large batches
+const DefaultConcurrency = 5
+
+// ForEach calls work for every index from 0 to count-1 on at most concurrency workers and
+// returns once all calls have completed. A concurrency below 1 processes one index at a time.
+func ForEach(count, concurrency int, work func(index int)) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	if concurrency > count {
+		concurrency = count
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for worker := 0; worker < concurrency; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				work(index)
+			}
+		}()
+	}
+	for index := 0; index < count; index++ {
+		indexes <- index
+	}
+	close(indexes)
+	wg.Wait()
+}
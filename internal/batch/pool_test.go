@@ -0,0 +1,67 @@
+package batch
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestForEach(t *testing.T) {
+	tests := []struct {
+		name        string
+		count       int
+		concurrency int
+		wantMax     int
+	}{
+		{"nothing", 0, 5, 0},
+		{"fewer items than workers", 3, 5, 3},
+		{"limited", 20, 4, 4},
+		{"sequential", 6, 1, 1},
+		{"invalid concurrency", 6, 0, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mutex sync.Mutex
+			running, maxRunning := 0, 0
+			seen := make([]int, tt.count)
+			release := make(chan struct{})
+			var releaseOnce sync.Once
+
+			done := make(chan struct{})
+			go func() {
+				ForEach(tt.count, tt.concurrency, func(index int) {
+					mutex.Lock()
+					running++
+					if running > maxRunning {
+						maxRunning = running
+					}
+					seen[index]++
+					full := running == tt.wantMax
+					mutex.Unlock()
+					// Hold the first workers until the limit is reached, so the test sees it
+					if full {
+						releaseOnce.Do(func() { close(release) })
+					}
+					<-release
+					mutex.Lock()
+					running--
+					mutex.Unlock()
+				})
+				close(done)
+			}()
+			if tt.wantMax == 0 {
+				close(release)
+			}
+			<-done
+
+			if maxRunning != tt.wantMax {
+				t.Errorf("ForEach() ran %d at once, want %d", maxRunning, tt.wantMax)
+			}
+			for index, calls := range seen {
+				if calls != 1 {
+					t.Errorf("ForEach() called index %d %d times, want 1", index, calls)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+// Package billing estimates the seat and GitHub Advanced Security committer changes a
+// repository transfer introduces to the target organization
+package billing
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+
+	"github.com/jefeish/gh-repo-transfer/internal/ghclient"
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+// Source is the people of a repository that may count towards the target's billing
+type Source struct {
+	Private        bool     // Private and internal repositories need seats for every collaborator
+	GHASEnabled    bool     // Advanced Security is enabled on the repository
+	Collaborators  []string // Direct collaborators
+	TeamMembers    []string // Members of the teams with access; they become members of the target org
+	GHASCommitters []string // Active committers counted for the repository in the source org
+}
+
+// Target is what the target organization already pays for
+type Target struct {
+	Members        []string
+	GHASCommitters []string
+}
+
+// Compute returns the users of source that are not yet billed by target. Team members always
+// need a seat because the teams are recreated in the target org; direct collaborators of a
+// public repository stay outside collaborators, which do not use a seat. Committers only
+// count when Advanced Security stays enabled on the repository.
+func Compute(source Source, target Target) *types.BillingImpact {
+	members := lowerSet(target.Members)
+	committers := lowerSet(target.GHASCommitters)
+
+	seatUsers := make(map[string]bool)
+	for _, login := range source.TeamMembers {
+		if !members[strings.ToLower(login)] {
+			seatUsers[strings.ToLower(login)] = true
+		}
+	}
+	if source.Private {
+		for _, login := range source.Collaborators {
+			if !members[strings.ToLower(login)] {
+				seatUsers[strings.ToLower(login)] = true
+			}
+		}
+	}
+
+	impact := &types.BillingImpact{NewSeatUsers: sortedKeys(seatUsers)}
+	impact.AdditionalSeats = len(impact.NewSeatUsers)
+
+	if source.GHASEnabled {
+		newCommitters := make(map[string]bool)
+		for _, login := range source.GHASCommitters {
+			if !committers[strings.ToLower(login)] {
+				newCommitters[strings.ToLower(login)] = true
+			}
+		}
+		impact.NewCommitters = sortedKeys(newCommitters)
+		impact.AdditionalGHASCommitters = len(impact.NewCommitters)
+	}
+	return impact
+}
+
+var (
+	targetCache      = make(map[string]*Target)
+	targetCacheMutex sync.Mutex
+)
+
+// Estimate reads the collaborators, team members and Advanced Security committers of owner/repo
+// and compares them with what targetOrg already pays for. Parts that cannot be read (the billing
+// endpoints need org admin access) are listed in the notes rather than failing the estimate.
+func Estimate(client api.RESTClient, owner, repo, targetOrg string) (*types.BillingImpact, error) {
+	var repository struct {
+		Visibility          string `json:"visibility"`
+		SecurityAndAnalysis struct {
+			AdvancedSecurity struct {
+				Status string `json:"status"`
+			} `json:"advanced_security"`
+		} `json:"security_and_analysis"`
+	}
+	if err := client.Get(fmt.Sprintf("repos/%s/%s", owner, repo), &repository); err != nil {
+		return nil, fmt.Errorf("failed to get repository %s/%s: %v", owner, repo, err)
+	}
+
+	var notes []string
+	source := Source{
+		Private:     repository.Visibility != "public",
+		GHASEnabled: repository.SecurityAndAnalysis.AdvancedSecurity.Status == "enabled",
+	}
+
+	collaborators, err := listLogins(client, fmt.Sprintf("repos/%s/%s/collaborators?affiliation=direct", owner, repo))
+	if err != nil {
+		notes = append(notes, fmt.Sprintf("collaborators not counted: %v", err))
+	}
+	source.Collaborators = collaborators
+
+	teamMembers, err := repositoryTeamMembers(client, owner, repo)
+	if err != nil {
+		notes = append(notes, fmt.Sprintf("team members not counted: %v", err))
+	}
+	source.TeamMembers = teamMembers
+
+	if source.GHASEnabled {
+		committers, err := advancedSecurityCommitters(client, owner, repo)
+		if err != nil {
+			notes = append(notes, fmt.Sprintf("Advanced Security committers not counted: %v", err))
+		}
+		source.GHASCommitters = committers
+	}
+
+	target, targetNotes := targetBilling(client, targetOrg)
+	notes = append(notes, targetNotes...)
+
+	impact := Compute(source, *target)
+	impact.Notes = notes
+	return impact, nil
+}
+
+// targetBilling reads the members and Advanced Security committers of the target org once per run
+func targetBilling(client api.RESTClient, org string) (*Target, []string) {
+	key := strings.ToLower(org)
+	targetCacheMutex.Lock()
+	defer targetCacheMutex.Unlock()
+	if target, ok := targetCache[key]; ok {
+		return target, nil
+	}
+
+	var notes []string
+	target := &Target{}
+	members, err := listLogins(client, fmt.Sprintf("orgs/%s/members", org))
+	if err != nil {
+		notes = append(notes, fmt.Sprintf("members of %s not read, every user is counted: %v", org, err))
+	}
+	target.Members = members
+
+	committers, err := advancedSecurityCommitters(client, org, "")
+	if err != nil {
+		notes = append(notes, fmt.Sprintf("Advanced Security committers of %s not read, every committer is counted: %v", org, err))
+	}
+	target.GHASCommitters = committers
+
+	targetCache[key] = target
+	return target, notes
+}
+
+// repositoryTeamMembers returns the members of every team with access to the repository
+func repositoryTeamMembers(client api.RESTClient, owner, repo string) ([]string, error) {
+	var teams []struct {
+		Slug string `json:"slug"`
+	}
+	if err := ghclient.GetAll(&client, fmt.Sprintf("repos/%s/%s/teams", owner, repo), &teams); err != nil {
+		return nil, err
+	}
+	var logins []string
+	for _, team := range teams {
+		members, err := listLogins(client, fmt.Sprintf("orgs/%s/teams/%s/members", owner, team.Slug))
+		if err != nil {
+			return logins, fmt.Errorf("team %s: %v", team.Slug, err)
+		}
+		logins = append(logins, members...)
+	}
+	return logins, nil
+}
+
+// advancedSecurityCommitters returns the active committers billed for the org, limited to one
+// repository unless repo is empty
+func advancedSecurityCommitters(client api.RESTClient, org, repo string) ([]string, error) {
+	var repositories []struct {
+		Name      string `json:"name"`
+		Breakdown []struct {
+			UserLogin string `json:"user_login"`
+		} `json:"advanced_security_committers_breakdown"`
+	}
+	if err := ghclient.GetAllField(&client, fmt.Sprintf("orgs/%s/settings/billing/advanced-security", org), "repositories", &repositories); err != nil {
+		return nil, err
+	}
+	var logins []string
+	for _, repository := range repositories {
+		if repo != "" && !strings.EqualFold(repository.Name, fmt.Sprintf("%s/%s", org, repo)) {
+			continue
+		}
+		for _, committer := range repository.Breakdown {
+			logins = append(logins, committer.UserLogin)
+		}
+	}
+	return logins, nil
+}
+
+// listLogins reads every page of a user list endpoint
+func listLogins(client api.RESTClient, path string) ([]string, error) {
+	var users []struct {
+		Login string `json:"login"`
+	}
+	if err := ghclient.GetAll(&client, path, &users); err != nil {
+		return nil, err
+	}
+	logins := make([]string, 0, len(users))
+	for _, user := range users {
+		logins = append(logins, user.Login)
+	}
+	return logins, nil
+}
+
+func lowerSet(logins []string) map[string]bool {
+	set := make(map[string]bool, len(logins))
+	for _, login := range logins {
+		set[strings.ToLower(login)] = true
+	}
+	return set
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
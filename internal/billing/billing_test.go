@@ -0,0 +1,56 @@
+package billing
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+func TestCompute(t *testing.T) {
+	target := Target{
+		Members:        []string{"alice", "Bob"},
+		GHASCommitters: []string{"alice"},
+	}
+
+	tests := []struct {
+		name   string
+		source Source
+		want   *types.BillingImpact
+	}{
+		{
+			name:   "existing members add nothing",
+			source: Source{Private: true, GHASEnabled: true, Collaborators: []string{"bob"}, TeamMembers: []string{"ALICE"}, GHASCommitters: []string{"alice"}},
+			want:   &types.BillingImpact{NewSeatUsers: []string{}, NewCommitters: []string{}},
+		},
+		{
+			name:   "private repository counts collaborators and team members once",
+			source: Source{Private: true, Collaborators: []string{"carol", "dave"}, TeamMembers: []string{"Carol", "erin"}},
+			want:   &types.BillingImpact{AdditionalSeats: 3, NewSeatUsers: []string{"carol", "dave", "erin"}},
+		},
+		{
+			name:   "public repository collaborators stay outside collaborators",
+			source: Source{Collaborators: []string{"carol"}, TeamMembers: []string{"erin"}},
+			want:   &types.BillingImpact{AdditionalSeats: 1, NewSeatUsers: []string{"erin"}},
+		},
+		{
+			name:   "committers only count with Advanced Security enabled",
+			source: Source{Private: true, GHASEnabled: true, TeamMembers: []string{"bob"}, GHASCommitters: []string{"alice", "bob"}},
+			want:   &types.BillingImpact{NewSeatUsers: []string{}, AdditionalGHASCommitters: 1, NewCommitters: []string{"bob"}},
+		},
+		{
+			name:   "committers ignored without Advanced Security",
+			source: Source{Private: true, GHASCommitters: []string{"bob"}},
+			want:   &types.BillingImpact{NewSeatUsers: []string{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Compute(tt.source, target)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Compute() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+// Package checks builds the GitHub Check Run that publishes a repository's migration readiness
+package checks
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+// maxAnnotations is the number of annotations GitHub accepts per check run request
+const maxAnnotations = 50
+
+// CheckRun is the payload of POST /repos/{owner}/{repo}/check-runs
+type CheckRun struct {
+	Name       string `json:"name"`
+	HeadSHA    string `json:"head_sha"`
+	Status     string `json:"status"`
+	Conclusion string `json:"conclusion"`
+	Output     Output `json:"output"`
+}
+
+// Output is the title, summary and annotations shown on the check run
+type Output struct {
+	Title       string       `json:"title"`
+	Summary     string       `json:"summary"`
+	Text        string       `json:"text,omitempty"`
+	Annotations []Annotation `json:"annotations,omitempty"`
+}
+
+// Annotation marks a blocker on the file it was found in
+type Annotation struct {
+	Path            string `json:"path"`
+	StartLine       int    `json:"start_line"`
+	EndLine         int    `json:"end_line"`
+	AnnotationLevel string `json:"annotation_level"`
+	Title           string `json:"title"`
+	Message         string `json:"message"`
+}
+
+// locationPattern extracts the file from the " (in path)" suffix analyzers append to items; a
+// job the item was found in follows the path after a comma, e.g. " (in ci.yml, job build)"
+var locationPattern = regexp.MustCompile(`\(in ([^,)]+)`)
+
+// Conclusion maps the overall readiness to a check run conclusion
+func Conclusion(readiness types.ValidationStatus) string {
+	switch readiness {
+	case types.ValidationReady:
+		return "success"
+	case types.ValidationBlocker:
+		return "failure"
+	default:
+		return "neutral"
+	}
+}
+
+// AnnotationPath returns the file a validation item was found in, or "" when it has none
+func AnnotationPath(item string) string {
+	if match := locationPattern.FindStringSubmatch(item); match != nil {
+		return strings.TrimSpace(match[1])
+	}
+	return ""
+}
+
+// Build creates the check run for a validated repository at headSHA. Every blocker is listed in
+// the text; blockers found in a file are also annotated on that file.
+func Build(validation *types.MigrationValidation, headSHA string) CheckRun {
+	summary := validation.Summary
+	run := CheckRun{
+		Name:       fmt.Sprintf("Migration readiness (%s)", validation.TargetOrganization),
+		HeadSHA:    headSHA,
+		Status:     "completed",
+		Conclusion: Conclusion(validation.OverallReadiness),
+	}
+	run.Output.Title = fmt.Sprintf("%s: %d blockers, %d setup needed, %d to review", validation.OverallReadiness, summary.Blockers, summary.SetupNeeded, summary.Review)
+
+	run.Output.Summary = SummaryMarkdown(validation)
+
+	for _, result := range allResults(validation) {
+		path := AnnotationPath(result.Item)
+		if result.Status != types.ValidationBlocker || path == "" || len(run.Output.Annotations) >= maxAnnotations {
+			continue
+		}
+		run.Output.Annotations = append(run.Output.Annotations, Annotation{
+			Path:            path,
+			StartLine:       1,
+			EndLine:         1,
+			AnnotationLevel: "failure",
+			Title:           fmt.Sprintf("Migration blocker: %s", result.Item),
+			Message:         strings.TrimSpace(result.Message + "\n" + result.Recommendation),
+		})
+	}
+	if blockers := BlockersMarkdown(validation); blockers != "" {
+		run.Output.Text = "### Blockers\n\n" + blockers
+	}
+	return run
+}
+
+// SummaryMarkdown renders the validation counts and effort as a markdown table
+func SummaryMarkdown(validation *types.MigrationValidation) string {
+	summary := validation.Summary
+	var b strings.Builder
+	fmt.Fprintf(&b, "Readiness for moving this repository to **%s**.\n\n", validation.TargetOrganization)
+	b.WriteString("| Status | Items |\n|--------|-------|\n")
+	fmt.Fprintf(&b, "| 🟢 Ready | %d |\n| 🟡 Setup needed | %d |\n| 🔴 Blockers | %d |\n| ⚠️ Warnings | %d |\n| ⚪ Review | %d |\n| ❓ Unknown | %d |\n",
+		summary.Ready, summary.SetupNeeded, summary.Blockers, summary.Warnings, summary.Review, summary.Unknown)
+	if validation.Effort != nil && validation.Effort.TotalMinutes > 0 {
+		fmt.Fprintf(&b, "\nEstimated remediation effort: %d minutes\n", validation.Effort.TotalMinutes)
+	}
+	return b.String()
+}
+
+// BlockersMarkdown lists every blocker with its recommendation, or returns "" when there are none
+func BlockersMarkdown(validation *types.MigrationValidation) string {
+	return itemsMarkdown(validation, types.ValidationBlocker)
+}
+
+// itemsMarkdown lists the validation items with the given status as a markdown list
+func itemsMarkdown(validation *types.MigrationValidation, status types.ValidationStatus) string {
+	var b strings.Builder
+	for _, result := range allResults(validation) {
+		if result.Status != status {
+			continue
+		}
+		fmt.Fprintf(&b, "- **%s**: %s", result.Item, result.Message)
+		if result.Recommendation != "" {
+			fmt.Fprintf(&b, " → %s", result.Recommendation)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// allResults flattens the validation results of every category
+func allResults(validation *types.MigrationValidation) []types.ValidationResult {
+	var results []types.ValidationResult
+	results = append(results, validation.CodeDependencies...)
+	results = append(results, validation.CIDependencies...)
+	results = append(results, validation.AccessPermissions...)
+	results = append(results, validation.SecurityCompliance...)
+	results = append(results, validation.AppsIntegrations...)
+	results = append(results, validation.Governance...)
+	return results
+}
@@ -0,0 +1,48 @@
+package checks
+
+import (
+	"testing"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+func TestConclusion(t *testing.T) {
+	tests := map[types.ValidationStatus]string{
+		types.ValidationReady:       "success",
+		types.ValidationBlocker:     "failure",
+		types.ValidationSetupNeeded: "neutral",
+		types.ValidationReview:      "neutral",
+	}
+	for readiness, want := range tests {
+		if got := Conclusion(readiness); got != want {
+			t.Errorf("Conclusion(%s) = %s, want %s", readiness, got, want)
+		}
+	}
+}
+
+func TestBuild(t *testing.T) {
+	validation := &types.MigrationValidation{
+		TargetOrganization: "new-org",
+		OverallReadiness:   types.ValidationBlocker,
+		Summary:            types.ValidationSummary{Blockers: 2, Ready: 1},
+		CIDependencies: []types.ValidationResult{
+			{Item: "Self-hosted runner: gpu-runner (in .github/workflows/train.yml, job train)", Status: types.ValidationBlocker, Message: "Runner not available"},
+			{Item: "NPM_TOKEN", Status: types.ValidationReady},
+		},
+		AppsIntegrations: []types.ValidationResult{
+			{Item: "custom-app", Status: types.ValidationBlocker, Message: "App not installed"},
+		},
+	}
+
+	run := Build(validation, "abc123")
+
+	if run.Conclusion != "failure" || run.HeadSHA != "abc123" || run.Status != "completed" {
+		t.Errorf("unexpected check run: %+v", run)
+	}
+	if len(run.Output.Annotations) != 1 || run.Output.Annotations[0].Path != ".github/workflows/train.yml" {
+		t.Errorf("annotations = %+v, want one on the workflow", run.Output.Annotations)
+	}
+	if run.Output.Text == "" {
+		t.Errorf("blockers are not listed in the text")
+	}
+}
@@ -0,0 +1,56 @@
+package checks
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+// commentMarker identifies the sticky readiness comment for a target organization, so
+// later runs update it instead of adding a new comment
+func commentMarker(target string) string {
+	return fmt.Sprintf("<!-- gh-repo-transfer:readiness target=%s -->", strings.ToLower(target))
+}
+
+// IsReadinessComment reports whether a comment body is the sticky readiness comment for target
+func IsReadinessComment(body, target string) bool {
+	return strings.Contains(body, commentMarker(target))
+}
+
+// Comment renders the sticky readiness comment for a validated repository
+func Comment(repository string, validation *types.MigrationValidation) string {
+	var b strings.Builder
+	b.WriteString(commentMarker(validation.TargetOrganization) + "\n")
+
+	icon := map[string]string{"success": "✅", "failure": "❌", "neutral": "⚠️"}[Conclusion(validation.OverallReadiness)]
+	fmt.Fprintf(&b, "## %s Migration readiness of `%s`: %s\n\n", icon, repository, validation.OverallReadiness)
+	b.WriteString(SummaryMarkdown(validation))
+
+	sections := []struct {
+		title  string
+		status types.ValidationStatus
+		open   bool
+	}{
+		{"🔴 Blockers", types.ValidationBlocker, true},
+		{"🟡 Setup needed", types.ValidationSetupNeeded, false},
+		{"⚪ Review", types.ValidationReview, false},
+		{"⚠️ Warnings", types.ValidationWarning, false},
+	}
+	for _, section := range sections {
+		items := itemsMarkdown(validation, section.status)
+		if items == "" {
+			continue
+		}
+		open := ""
+		if section.open {
+			open = " open"
+		}
+		fmt.Fprintf(&b, "\n<details%s><summary>%s</summary>\n\n%s\n</details>\n", open, section.title, items)
+	}
+
+	if !validation.CapabilitiesScannedAt.IsZero() {
+		fmt.Fprintf(&b, "\n<sub>Target scanned %s · updated by gh repo-transfer</sub>\n", validation.CapabilitiesScannedAt.UTC().Format("2006-01-02 15:04 MST"))
+	}
+	return b.String()
+}
@@ -0,0 +1,33 @@
+package checks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+func TestComment(t *testing.T) {
+	validation := &types.MigrationValidation{
+		TargetOrganization: "New-Org",
+		OverallReadiness:   types.ValidationBlocker,
+		AccessPermissions: []types.ValidationResult{
+			{Item: "platform", Status: types.ValidationBlocker, Message: "Team not found", Recommendation: "Create the team"},
+			{Item: "ops", Status: types.ValidationSetupNeeded, Message: "Team can be created"},
+		},
+	}
+
+	body := Comment("acme/web", validation)
+
+	if !IsReadinessComment(body, "new-org") {
+		t.Errorf("comment is not recognized as the sticky comment for new-org")
+	}
+	if IsReadinessComment(body, "other-org") {
+		t.Errorf("comment is recognized for a different target")
+	}
+	for _, want := range []string{"acme/web", "**platform**: Team not found → Create the team", "Setup needed", "<details open>"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("comment does not contain %q:\n%s", want, body)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+// Package config reads the defaults and policies of .repo-transfer.yaml, so a team does not
+// repeat the same flags on every run
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/jefeish/gh-repo-transfer/internal/validation"
+)
+
+// RepoFile is the repository-local config file, read from the working directory
+const RepoFile = ".repo-transfer.yaml"
+
+// Config holds the defaults of flags not given on the command line and the validation policies
+type Config struct {
+	TargetOrg   string `yaml:"target_org,omitempty"`
+	Concurrency int    `yaml:"concurrency,omitempty"`
+	Format      string `yaml:"format,omitempty"`
+	// Status per finding kind ("ci.configure_runner"), category ("ci") or finding ID
+	SeverityOverrides map[string]string `yaml:"severity_overrides,omitempty"`
+	// Rules remapping findings and items, applied before SeverityOverrides
+	SeverityRules []validation.SeverityRule `yaml:"severity_rules,omitempty"`
+	// Finding kinds, categories or IDs left out of validation
+	ExcludedChecks []string `yaml:"excluded_checks,omitempty"`
+}
+
+// UserFile returns the path of the user's config file, ~/.config/repo-transfer/config.yaml
+// (or under $XDG_CONFIG_HOME); "" when no home directory is known
+func UserFile() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "repo-transfer", "config.yaml")
+}
+
+// Load reads the config file at path. Without a path, the user's config file is read and the
+// repository-local RepoFile is layered on top: each setting of RepoFile replaces the user's.
+// Files that do not exist are skipped. The files read are returned for verbose output.
+func Load(path string) (*Config, []string, error) {
+	if path != "" {
+		config, err := readFile(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		return config, []string{path}, nil
+	}
+
+	config := &Config{}
+	var read []string
+	for _, candidate := range []string{UserFile(), RepoFile} {
+		if candidate == "" {
+			continue
+		}
+		if _, err := os.Stat(candidate); err != nil {
+			continue
+		}
+		layer, err := readFile(candidate)
+		if err != nil {
+			return nil, nil, err
+		}
+		config.merge(layer)
+		read = append(read, candidate)
+	}
+	return config, read, nil
+}
+
+// readFile parses one config file; unknown keys are refused so a typo does not go unnoticed
+func readFile(path string) (*Config, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config %s: %v", path, err)
+	}
+	defer file.Close()
+
+	config := &Config{}
+	decoder := yaml.NewDecoder(file)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(config); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse config %s: %v", path, err)
+	}
+	if config.Concurrency < 0 {
+		return nil, fmt.Errorf("%s: concurrency must not be negative", path)
+	}
+	return config, nil
+}
+
+// merge replaces the settings of c with those set in layer; severity overrides are merged
+// per key, severity rules and excluded checks are replaced as a whole
+func (c *Config) merge(layer *Config) {
+	if layer.TargetOrg != "" {
+		c.TargetOrg = layer.TargetOrg
+	}
+	if layer.Concurrency != 0 {
+		c.Concurrency = layer.Concurrency
+	}
+	if layer.Format != "" {
+		c.Format = layer.Format
+	}
+	if len(layer.SeverityOverrides) > 0 {
+		if c.SeverityOverrides == nil {
+			c.SeverityOverrides = make(map[string]string)
+		}
+		for key, status := range layer.SeverityOverrides {
+			c.SeverityOverrides[key] = status
+		}
+	}
+	if layer.SeverityRules != nil {
+		c.SeverityRules = layer.SeverityRules
+	}
+	if layer.ExcludedChecks != nil {
+		c.ExcludedChecks = layer.ExcludedChecks
+	}
+}
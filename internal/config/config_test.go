@@ -0,0 +1,69 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", home)
+	t.Setenv("HOME", home)
+	userFile := UserFile()
+	if err := os.MkdirAll(filepath.Dir(userFile), 0755); err != nil {
+		t.Fatal(err)
+	}
+	user := "target_org: acme-new\nconcurrency: 8\nseverity_overrides:\n  ci.configure_runner: blocker\n  security: warning\n"
+	if err := os.WriteFile(userFile, []byte(user), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	workdir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(workdir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	repo := "format: json\nseverity_overrides:\n  security: review\nexcluded_checks: [apps.pages_setup]\n"
+	if err := os.WriteFile(RepoFile, []byte(repo), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, files, err := Load("")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	want := &Config{
+		TargetOrg:         "acme-new",
+		Concurrency:       8,
+		Format:            "json",
+		SeverityOverrides: map[string]string{"ci.configure_runner": "blocker", "security": "review"},
+		ExcludedChecks:    []string{"apps.pages_setup"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Load() = %+v, want %+v", got, want)
+	}
+	if !reflect.DeepEqual(files, []string{userFile, RepoFile}) {
+		t.Errorf("Load() read %v", files)
+	}
+
+	// An explicit path is read alone
+	got, _, err = Load(RepoFile)
+	if err != nil || got.TargetOrg != "" || got.Format != "json" {
+		t.Errorf("Load(%q) = %+v, %v", RepoFile, got, err)
+	}
+
+	if err := os.WriteFile("typo.yaml", []byte("target-org: acme\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := Load("typo.yaml"); err == nil || !strings.Contains(err.Error(), "target-org") {
+		t.Errorf("Load() of an unknown key error = %v", err)
+	}
+}
@@ -1,23 +1,53 @@
 package dependencies
 
 import (
-	"encoding/base64"
 	"fmt"
-	"strings"
 
 	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/jefeish/gh-repo-transfer/internal/ghclient"
 	"github.com/jefeish/gh-repo-transfer/internal/types"
 )
 
+// AccessGrant is a team or user with a permission on a repository, as read ahead of the
+// analysis by a prefetching backend such as the GraphQL batch scan
+type AccessGrant struct {
+	Name       string // Team name or user login
+	Slug       string // Team slug (teams only)
+	Permission string // REST permission name, e.g. push for a team or write for a user
+}
+
+// PrefetchedAccess holds the teams and collaborators of a repository read ahead of the
+// analysis. Lists that were not completely prefetched are read from the REST API instead.
+type PrefetchedAccess struct {
+	Teams                 []AccessGrant
+	TeamsComplete         bool
+	Collaborators         []AccessGrant
+	CollaboratorsComplete bool
+}
+
 // AnalyzeAccessPermissions analyzes access control and permissions dependencies
 func AnalyzeAccessPermissions(client api.RESTClient, owner, repo string, deps *types.OrganizationalDependencies) error {
+	return AnalyzeAccessPermissionsPrefetched(client, owner, repo, deps, nil)
+}
+
+// AnalyzeAccessPermissionsPrefetched analyzes access control and permissions dependencies,
+// using the prefetched teams and collaborators where they are complete
+func AnalyzeAccessPermissionsPrefetched(client api.RESTClient, owner, repo string, deps *types.OrganizationalDependencies, prefetched *PrefetchedAccess) error {
 	// Analyze teams with access to the repository
-	if err := analyzeTeams(client, owner, repo, deps); err != nil {
+	if prefetched != nil && prefetched.TeamsComplete {
+		for _, team := range prefetched.Teams {
+			recordTeam(client, owner, team.Name, team.Slug, team.Permission, "", deps)
+		}
+	} else if err := analyzeTeams(client, owner, repo, deps); err != nil {
 		// Non-fatal error - might not have access to teams info
 	}
 
 	// Analyze individual collaborators
-	if err := analyzeCollaborators(client, owner, repo, deps); err != nil {
+	if prefetched != nil && prefetched.CollaboratorsComplete {
+		for _, collaborator := range prefetched.Collaborators {
+			recordCollaborator(owner, collaborator.Name, collaborator.Permission, deps)
+		}
+	} else if err := analyzeCollaborators(client, owner, repo, deps); err != nil {
 		// Non-fatal error - might not have access to collaborators info
 	}
 
@@ -31,6 +61,11 @@ func AnalyzeAccessPermissions(client api.RESTClient, owner, repo string, deps *t
 		// Non-fatal error - org roles might not be accessible
 	}
 
+	// Analyze deploy keys
+	if err := AnalyzeDeployKeys(client, owner, repo, deps); err != nil {
+		// Non-fatal error - listing deploy keys requires admin access
+	}
+
 	return nil
 }
 
@@ -38,6 +73,7 @@ func AnalyzeAccessPermissions(client api.RESTClient, owner, repo string, deps *t
 func analyzeTeams(client api.RESTClient, owner, repo string, deps *types.OrganizationalDependencies) error {
 	var teams []struct {
 		Name        string  `json:"name"`
+		Slug        string  `json:"slug"`
 		Permission  string  `json:"permission"`
 		RoleName    *string `json:"role_name"` // Custom organization role
 		LdapDn      *string `json:"ldap_dn"`   // LDAP distinguished name (present for IdP-synced teams)
@@ -48,7 +84,7 @@ func analyzeTeams(client api.RESTClient, owner, repo string, deps *types.Organiz
 		} `json:"permissions"`
 	}
 
-	err := client.Get(fmt.Sprintf("repos/%s/%s/teams", owner, repo), &teams)
+	err := ghclient.GetAll(&client, fmt.Sprintf("repos/%s/%s/teams", owner, repo), &teams)
 	if err != nil {
 		return err
 	}
@@ -72,19 +108,25 @@ func analyzeTeams(client api.RESTClient, owner, repo string, deps *types.Organiz
 			}
 		}
 		
-	// Check if team is IdP-controlled (LDAP/SAML synced)
-	idpIndicator := ""
-	if team.LdapDn != nil && *team.LdapDn != "" {
-		idpIndicator = " [IdP-synced]"
+	ldapDN := ""
+	if team.LdapDn != nil {
+		ldapDN = *team.LdapDn
 	}
-	
-	teamInfo := fmt.Sprintf("%s (%s)%s", team.Name, permission, idpIndicator)
-	deps.AccessPermissions.Teams = append(deps.AccessPermissions.Teams, teamInfo)
+	recordTeam(client, owner, team.Name, team.Slug, permission, ldapDN, deps)
 	}
 
 	return nil
 }
 
+// recordTeam adds a team with access to the repository, flagging IdP-controlled teams
+func recordTeam(client api.RESTClient, owner, name, slug, permission, ldapDN string, deps *types.OrganizationalDependencies) {
+	// Check if team is IdP-controlled (LDAP, team sync or EMU external groups)
+	groups, _ := TeamIdPGroups(client, owner, slug, ldapDN)
+
+	teamInfo := fmt.Sprintf("%s (%s)%s", name, permission, IdPIndicator(groups))
+	deps.AccessPermissions.Teams = append(deps.AccessPermissions.Teams, teamInfo)
+}
+
 // analyzeCollaborators analyzes individual collaborators
 func analyzeCollaborators(client api.RESTClient, owner, repo string, deps *types.OrganizationalDependencies) error {
 	var collaborators []struct {
@@ -98,17 +140,12 @@ func analyzeCollaborators(client api.RESTClient, owner, repo string, deps *types
 		RoleName *string `json:"role_name"` // Custom organization role
 	}
 
-	err := client.Get(fmt.Sprintf("repos/%s/%s/collaborators", owner, repo), &collaborators)
+	err := ghclient.GetAll(&client, fmt.Sprintf("repos/%s/%s/collaborators", owner, repo), &collaborators)
 	if err != nil {
 		return err
 	}
 
 	for _, collab := range collaborators {
-		// Skip the owner as they're not really a "dependency"
-		if collab.Login == owner {
-			continue
-		}
-		
 		// Determine permission/role
 		permission := collab.Permission
 		if permission == "" && collab.RoleName != nil && *collab.RoleName != "" {
@@ -127,82 +164,20 @@ func analyzeCollaborators(client api.RESTClient, owner, repo string, deps *types
 			}
 		}
 		
-		collabInfo := fmt.Sprintf("%s (%s)", collab.Login, permission)
-		deps.AccessPermissions.IndividualCollaborators = append(deps.AccessPermissions.IndividualCollaborators, collabInfo)
-	}
-
-	return nil
-}
-
-// analyzeCODEOWNERS analyzes the CODEOWNERS file for organizational dependencies
-func analyzeCODEOWNERS(client api.RESTClient, owner, repo string, deps *types.OrganizationalDependencies) error {
-	// Try different possible locations for CODEOWNERS
-	codeownersLocations := []string{
-		".github/CODEOWNERS",
-		"CODEOWNERS",
-		"docs/CODEOWNERS",
-	}
-
-	for _, location := range codeownersLocations {
-		var content struct {
-			Content string `json:"content"`
-		}
-
-		err := client.Get(fmt.Sprintf("repos/%s/%s/contents/%s", owner, repo, location), &content)
-		if err != nil {
-			continue
-		}
-
-		decoded, err := base64.StdEncoding.DecodeString(content.Content)
-		if err != nil {
-			continue
-		}
-
-		owners := parseCODEOWNERS(string(decoded), owner)
-		deps.AccessPermissions.CodeownersRequirements = append(deps.AccessPermissions.CodeownersRequirements, owners...)
-		break // Found CODEOWNERS file
+		recordCollaborator(owner, collab.Login, permission, deps)
 	}
 
 	return nil
 }
 
-// parseCODEOWNERS parses CODEOWNERS file content and extracts organizational dependencies
-func parseCODEOWNERS(content, owner string) []string {
-	var owners []string
-	lines := strings.Split(content, "\n")
-
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" || strings.HasPrefix(line, "#") {
-			continue
-		}
-
-		// Split by whitespace to get path and owners
-		parts := strings.Fields(line)
-		if len(parts) < 2 {
-			continue
-		}
-
-		// Skip the path (first part) and process owners
-		for _, ownerRef := range parts[1:] {
-			if strings.HasPrefix(ownerRef, "@") {
-				ownerName := strings.TrimPrefix(ownerRef, "@")
-				
-				// Check if it's a team reference (contains organization name)
-				if strings.Contains(ownerName, "/") {
-					owners = append(owners, fmt.Sprintf("Team: @%s", ownerName))
-				} else if strings.Contains(ownerName, owner) {
-					// Organization-specific user reference
-					owners = append(owners, fmt.Sprintf("CODEOWNERS user: @%s", ownerName))
-				} else {
-					// Individual user reference
-					owners = append(owners, fmt.Sprintf("User: @%s", ownerName))
-				}
-			}
-		}
+// recordCollaborator adds an individual collaborator of the repository
+func recordCollaborator(owner, login, permission string, deps *types.OrganizationalDependencies) {
+	// Skip the owner as they're not really a "dependency"
+	if login == owner {
+		return
 	}
-
-	return owners
+	collabInfo := fmt.Sprintf("%s (%s)", login, permission)
+	deps.AccessPermissions.IndividualCollaborators = append(deps.AccessPermissions.IndividualCollaborators, collabInfo)
 }
 
 // analyzeOrganizationRoles analyzes custom organization roles (Enterprise feature)
@@ -213,7 +188,7 @@ func analyzeOrganizationRoles(client api.RESTClient, owner, repo string, deps *t
 		Description string `json:"description"`
 	}
 
-	err := client.Get(fmt.Sprintf("orgs/%s/organization-roles", owner), &roles)
+	err := ghclient.GetAllField(&client, fmt.Sprintf("orgs/%s/organization-roles", owner), "roles", &roles)
 	if err != nil {
 		return err // Organization roles not accessible or not available
 	}
@@ -0,0 +1,40 @@
+package dependencies
+
+import (
+	"regexp"
+	"strings"
+)
+
+// RewriteActionReferences replaces the uses: references of a workflow file to actions and
+// reusable workflows of sourceOrg with the same path in targetOrg, for the repositories exists
+// reports in the target; the ref is kept. It returns the new content and the references
+// rewritten and left unchanged, each once and without their ref. Comment lines are not touched.
+func RewriteActionReferences(content, sourceOrg, targetOrg string, exists func(repo string) bool) (string, []string, []string) {
+	usesPattern := regexp.MustCompile(`(?i)(\buses:\s*['"]?)` + regexp.QuoteMeta(sourceOrg) + `/([A-Za-z0-9_.\-]+)((?:/[^@\s'"]*)?)@`)
+	var rewritten, kept []string
+	seen := make(map[string]bool)
+	record := func(list *[]string, reference string) {
+		if key := strings.ToLower(reference); !seen[key] {
+			seen[key] = true
+			*list = append(*list, reference)
+		}
+	}
+
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		lines[i] = usesPattern.ReplaceAllStringFunc(line, func(match string) string {
+			groups := usesPattern.FindStringSubmatch(match)
+			reference := strings.TrimSuffix(match[len(groups[1]):], "@")
+			if !exists(groups[2]) {
+				record(&kept, reference)
+				return match
+			}
+			record(&rewritten, reference)
+			return groups[1] + targetOrg + "/" + groups[2] + groups[3] + "@"
+		})
+	}
+	return strings.Join(lines, "\n"), rewritten, kept
+}
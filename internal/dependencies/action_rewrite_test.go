@@ -0,0 +1,49 @@
+package dependencies
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRewriteActionReferences(t *testing.T) {
+	content := `# Uses acme/deploy-action@v2 to ship
+jobs:
+  shared:
+    uses: Acme/workflows/.github/workflows/build.yml@main
+  deploy:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+      - uses: acme/deploy-action@v2 # pinned by tag
+      - uses: "acme/legacy-action/sub@1c608d11d69870c2092266b3f9a6f3abbf17002c"
+      - uses: acme/deploy-action@v3
+      - uses: acme-tools/lint@v1
+      - uses: docker://ghcr.io/acme/tool:1
+`
+	exists := func(repo string) bool { return repo == "workflows" || repo == "deploy-action" }
+	got, rewritten, kept := RewriteActionReferences(content, "acme", "acme-new", exists)
+
+	want := `# Uses acme/deploy-action@v2 to ship
+jobs:
+  shared:
+    uses: acme-new/workflows/.github/workflows/build.yml@main
+  deploy:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+      - uses: acme-new/deploy-action@v2 # pinned by tag
+      - uses: "acme/legacy-action/sub@1c608d11d69870c2092266b3f9a6f3abbf17002c"
+      - uses: acme-new/deploy-action@v3
+      - uses: acme-tools/lint@v1
+      - uses: docker://ghcr.io/acme/tool:1
+`
+	if got != want {
+		t.Errorf("content =\n%s\nwant\n%s", got, want)
+	}
+	if want := []string{"Acme/workflows/.github/workflows/build.yml", "acme/deploy-action"}; !reflect.DeepEqual(rewritten, want) {
+		t.Errorf("rewritten = %v, want %v", rewritten, want)
+	}
+	if want := []string{"acme/legacy-action/sub"}; !reflect.DeepEqual(kept, want) {
+		t.Errorf("kept = %v, want %v", kept, want)
+	}
+}
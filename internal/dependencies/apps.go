@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/jefeish/gh-repo-transfer/internal/ghclient"
 	"github.com/jefeish/gh-repo-transfer/internal/types"
 )
 
@@ -16,6 +17,16 @@ func AnalyzeAppsIntegrations(client api.RESTClient, owner, repo string, deps *ty
 		fmt.Printf("Debug: GitHub Apps analysis error: %v\n", err)
 	}
 
+	// Analyze repository webhooks
+	if err := AnalyzeRepositoryWebhooks(client, owner, repo, deps); err != nil {
+		// Non-fatal error - listing webhooks requires admin access
+	}
+
+	// Analyze the GitHub Pages site
+	if err := AnalyzePages(client, owner, repo, deps); err != nil {
+		// Non-fatal error - the Pages configuration might not be readable
+	}
+
 	// Note: Personal Access Tokens can't be easily detected through the API
 	// as they would require access to user settings, which isn't available
 	// This would need to be documented as a manual check
@@ -60,7 +71,7 @@ func analyzeRepoInstallations(client api.RESTClient, owner, repo string, deps *t
 		} `json:"installations"`
 	}
 
-	err := client.Get(fmt.Sprintf("repos/%s/%s/installations", owner, repo), &response)
+	err := ghclient.GetAllField(&client, fmt.Sprintf("repos/%s/%s/installations", owner, repo), "installations", &response.Installations)
 	if err != nil {
 		return err
 	}
@@ -90,7 +101,7 @@ func analyzeOrgInstallations(client api.RESTClient, owner, repo string, deps *ty
 		} `json:"installations"`
 	}
 
-	err := client.Get(fmt.Sprintf("orgs/%s/installations", owner), &response)
+	err := ghclient.GetAllField(&client, fmt.Sprintf("orgs/%s/installations", owner), "installations", &response.Installations)
 	if err != nil {
 		return err
 	}
@@ -1,13 +1,13 @@
 package dependencies
 
 import (
-	"encoding/base64"
 	"fmt"
+	"net/url"
 	"path"
-	"regexp"
 	"strings"
 
 	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/jefeish/gh-repo-transfer/internal/ghclient"
 	"github.com/jefeish/gh-repo-transfer/internal/types"
 )
 
@@ -18,6 +18,11 @@ func AnalyzeActionsCIDependencies(client api.RESTClient, owner, repo string, dep
 		// Non-fatal error - .github/workflows might not exist
 	}
 
+	// Find the runners bound to the organization's network or public IPs
+	if err := analyzeNetworkBoundRunners(client, owner, deps); err != nil {
+		// Non-fatal error - runner groups require organization admin access
+	}
+
 	// Analyze required workflows from repository rulesets
 	if err := analyzeRequiredWorkflows(client, owner, repo, deps); err != nil {
 		// Non-fatal error - rulesets might not be accessible
@@ -28,241 +33,254 @@ func AnalyzeActionsCIDependencies(client api.RESTClient, owner, repo string, dep
 		// Non-fatal error - environments might not be accessible
 	}
 
+	// Record repository-level secrets/variables and source org variable values, which tell
+	// whether a same-named secret or variable in the target org would change what CI sees
+	if err := analyzeSecretAndVariableSources(client, owner, repo, deps); err != nil {
+		// Non-fatal error - listing secrets requires admin access
+	}
+
+	// Find consumers pinning this repository's actions to a commit SHA
+	if err := analyzePinnedActionConsumers(client, owner, repo, deps); err != nil {
+		// Non-fatal error - code search might be rate limited
+	}
+
+	// Find workflows dispatching deployments to this repository's environments
+	if err := analyzeDispatchConsumers(client, owner, repo, deps); err != nil {
+		// Non-fatal error - code search might be rate limited
+	}
+
 	return nil
 }
 
 // analyzeWorkflows analyzes GitHub Actions workflow files
 func analyzeWorkflows(client api.RESTClient, owner, repo string, deps *types.OrganizationalDependencies) error {
-	var contents []struct {
-		Name string `json:"name"`
-		Type string `json:"type"`
-		Path string `json:"path"`
-	}
-
-	err := client.Get(fmt.Sprintf("repos/%s/%s/contents/.github/workflows", owner, repo), &contents)
+	workflows, err := readWorkflows(client, owner, repo)
 	if err != nil {
 		return err // .github/workflows doesn't exist
 	}
 
-	for _, item := range contents {
-		if item.Type == "file" && (strings.HasSuffix(item.Name, ".yml") || strings.HasSuffix(item.Name, ".yaml")) {
-			if err := analyzeWorkflowFile(client, owner, repo, item.Path, deps); err != nil {
-				continue // Skip files that can't be read
-			}
+	var calls workflowCalls
+	for _, workflow := range workflows {
+		fileCalls, err := analyzeWorkflowContent(workflow.content, path.Base(workflow.path), owner, deps)
+		if err != nil {
+			continue
 		}
+		calls.localActions = append(calls.localActions, fileCalls.localActions...)
+		calls.inherited = append(calls.inherited, fileCalls.inherited...)
 	}
 
+	// Follow the local actions the workflows run (uses: ./.github/actions/...)
+	analyzeLocalActions(client, owner, repo, calls.localActions, deps)
+
+	// Resolve the secrets of reusable workflows called with secrets: inherit
+	analyzeInheritedSecrets(client, owner, calls.inherited, deps)
+
 	return nil
 }
 
-func analyzeWorkflowFile(client api.RESTClient, owner, repo, workflowPath string, deps *types.OrganizationalDependencies) error {
-	var content struct {
-		Content string `json:"content"`
-	}
+// workflowFile is a workflow read from a repository
+type workflowFile struct {
+	path    string
+	content []byte
+}
 
-	err := client.Get(fmt.Sprintf("repos/%s/%s/contents/%s", owner, repo, workflowPath), &content)
+// readWorkflows reads the workflows of a repository. With --path these are the workflows of
+// the subdirectory and the root workflows referring to it.
+func readWorkflows(client api.RESTClient, owner, repo string) ([]workflowFile, error) {
+	contents, err := listRepositoryDir(client, owner, repo, ".github/workflows")
+	dir := Subdirectory(owner, repo)
+	if dir != "" {
+		// The workflows of the subdirectory become the extracted repository's own
+		scoped, scopedErr := listRepositoryDir(client, owner, repo, path.Join(dir, ".github/workflows"))
+		if scopedErr == nil {
+			contents, err = append(contents, scoped...), nil
+		}
+	}
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	decoded, err := base64.StdEncoding.DecodeString(content.Content)
-	if err != nil {
-		return err
+	var workflows []workflowFile
+	for _, item := range contents {
+		if item.Type != "file" || !(strings.HasSuffix(item.Name, ".yml") || strings.HasSuffix(item.Name, ".yaml")) {
+			continue
+		}
+		decoded, err := readRepositoryFile(client, owner, repo, item.Path)
+		if err != nil {
+			continue // Skip files that can't be read
+		}
+		if dir != "" && !inSubdirectory(dir, item.Path) && !refersToSubdirectory(decoded, dir) {
+			continue // A root workflow for other parts of the repository
+		}
+		workflows = append(workflows, workflowFile{path: item.Path, content: decoded})
 	}
+	return workflows, nil
+}
 
-	workflowContent := string(decoded)
-	workflowName := path.Base(workflowPath)
-
-	// Check for organization secrets
-	analyzeOrganizationSecrets(workflowContent, workflowName, deps)
-	
-	// Check for organization variables
-	analyzeOrganizationVariables(workflowContent, workflowName, deps)
-	
-	// Check for self-hosted runners
-	analyzeSelfHostedRunners(workflowContent, workflowName, deps)
-	
-	// Check for organization-specific actions
-	analyzeOrganizationSpecificActions(workflowContent, workflowName, owner, deps)
-	
-	// Check for cross-repo workflow triggers
-	analyzeCrossRepoTriggers(workflowContent, workflowName, owner, deps)
+// analyzeEnvironments analyzes repository environments for organizational dependencies: their
+// deployment branch policies and protection rules, the teams and users that review deployments,
+// and the environment secrets and variables
+func analyzeEnvironments(client api.RESTClient, owner, repo string, deps *types.OrganizationalDependencies) error {
+	// Note: This requires special API access and might not be available to all users
+	var environments struct {
+		Environments []struct {
+			Name                   string `json:"name"`
+			DeploymentBranchPolicy *struct {
+				ProtectedBranches    bool `json:"protected_branches"`
+				CustomBranchPolicies bool `json:"custom_branch_policies"`
+			} `json:"deployment_branch_policy"`
+			ProtectionRules []EnvironmentProtectionRule `json:"protection_rules"`
+		} `json:"environments"`
+	}
 
-	return nil
-}
+	err := ghclient.GetAllField(&client, fmt.Sprintf("repos/%s/%s/environments", owner, repo), "environments", &environments.Environments)
+	if err != nil {
+		return err // Environments not accessible
+	}
 
-func analyzeOrganizationSecrets(content, workflowName string, deps *types.OrganizationalDependencies) {
-	// Look for secrets.PATTERN usage
-	secretPattern := regexp.MustCompile(`secrets\.([A-Z_][A-Z0-9_]*)`)
-	matches := secretPattern.FindAllStringSubmatch(content, -1)
-	
-	for _, match := range matches {
-		if len(match) > 1 {
-			secretName := match[1]
-			secretRef := fmt.Sprintf("%s (in %s)", secretName, workflowName)
-			// Check if already added
-			found := false
-			for _, existing := range deps.ActionsCIDependencies.OrganizationSecrets {
-				if existing == secretRef {
-					found = true
-					break
+	ci := &deps.ActionsCIDependencies
+	for _, env := range environments.Environments {
+		var details []string
+		if env.DeploymentBranchPolicy != nil {
+			if env.DeploymentBranchPolicy.ProtectedBranches {
+				details = append(details, "deploys from: protected branches")
+			} else if env.DeploymentBranchPolicy.CustomBranchPolicies {
+				patterns, err := getDeploymentBranchPatterns(client, owner, repo, env.Name)
+				if err != nil || len(patterns) == 0 {
+					details = append(details, "deploys from: custom branch policies")
+				} else {
+					details = append(details, fmt.Sprintf("deploys from: %s", strings.Join(patterns, ", ")))
 				}
 			}
-			if !found {
-				deps.ActionsCIDependencies.OrganizationSecrets = append(deps.ActionsCIDependencies.OrganizationSecrets, secretRef)
-			}
 		}
-	}
-}
+		details = append(details, DescribeProtectionRules(env.ProtectionRules)...)
 
-func analyzeOrganizationVariables(content, workflowName string, deps *types.OrganizationalDependencies) {
-	// Look for vars.PATTERN usage
-	varPattern := regexp.MustCompile(`vars\.([A-Z_][A-Z0-9_]*)`)
-	matches := varPattern.FindAllStringSubmatch(content, -1)
-	
-	for _, match := range matches {
-		if len(match) > 1 {
-			varName := match[1]
-			varRef := fmt.Sprintf("%s (in %s)", varName, workflowName)
-			// Check if already added
-			found := false
-			for _, existing := range deps.ActionsCIDependencies.OrganizationVariables {
-				if existing == varRef {
-					found = true
-					break
-				}
+		envRef := fmt.Sprintf("Environment: %s", env.Name)
+		if len(details) > 0 {
+			envRef += fmt.Sprintf(" (%s)", strings.Join(details, "; "))
+		}
+		ci.EnvironmentDependencies = append(ci.EnvironmentDependencies, envRef)
+		ci.EnvironmentReviewers = append(ci.EnvironmentReviewers, EnvironmentReviewers(env.Name, env.ProtectionRules)...)
+
+		envPath := fmt.Sprintf("repos/%s/%s/environments/%s", owner, repo, url.PathEscape(env.Name))
+		var secrets []struct {
+			Name string `json:"name"`
+		}
+		if err := ghclient.GetAllField(&client, envPath+"/secrets", "secrets", &secrets); err == nil {
+			for _, secret := range secrets {
+				ci.EnvironmentSecrets = append(ci.EnvironmentSecrets, EnvironmentItem(secret.Name, env.Name))
 			}
-			if !found {
-				deps.ActionsCIDependencies.OrganizationVariables = append(deps.ActionsCIDependencies.OrganizationVariables, varRef)
+		}
+		var variables []struct {
+			Name string `json:"name"`
+		}
+		if err := ghclient.GetAllField(&client, envPath+"/variables", "variables", &variables); err == nil {
+			for _, variable := range variables {
+				ci.EnvironmentVariables = append(ci.EnvironmentVariables, EnvironmentItem(variable.Name, env.Name))
 			}
 		}
 	}
+
+	return nil
+}
+
+// EnvironmentProtectionRule is a protection rule of an environment as the REST API returns it
+type EnvironmentProtectionRule struct {
+	Type              string `json:"type"` // required_reviewers, wait_timer or branch_policy
+	WaitTimer         int    `json:"wait_timer"`
+	PreventSelfReview bool   `json:"prevent_self_review"`
+	Reviewers         []struct {
+		Type     string `json:"type"` // User or Team
+		Reviewer struct {
+			ID    int64  `json:"id"`
+			Login string `json:"login"`
+			Slug  string `json:"slug"`
+		} `json:"reviewer"`
+	} `json:"reviewers"`
 }
 
-func analyzeSelfHostedRunners(content, workflowName string, deps *types.OrganizationalDependencies) {
-	// Look for runs-on with self-hosted or custom runner labels
-	runnerPatterns := []string{
-		`runs-on:\s*self-hosted`,
-		`runs-on:\s*\[.*self-hosted.*\]`,
-		`runs-on:\s*([a-zA-Z][a-zA-Z0-9\-_]*)`, // Custom runner names (not ubuntu-latest, windows-latest, etc.)
-	}
-	
-	for _, pattern := range runnerPatterns {
-		re := regexp.MustCompile(pattern)
-		matches := re.FindAllStringSubmatch(content, -1)
-		
-		for _, match := range matches {
-			runnerInfo := ""
-			if strings.Contains(match[0], "self-hosted") {
-				runnerInfo = "self-hosted"
-			} else if len(match) > 1 && !isGitHubHostedRunner(match[1]) {
-				runnerInfo = match[1]
+// DescribeProtectionRules summarizes the wait timer and reviewer rules of an environment, e.g.
+// "wait timer: 30m", "2 reviewer(s), no self-review"
+func DescribeProtectionRules(rules []EnvironmentProtectionRule) []string {
+	var details []string
+	for _, rule := range rules {
+		switch rule.Type {
+		case "wait_timer":
+			if rule.WaitTimer > 0 {
+				details = append(details, fmt.Sprintf("wait timer: %dm", rule.WaitTimer))
 			}
-			
-			if runnerInfo != "" {
-				runnerRef := fmt.Sprintf("Self-hosted runner: %s (in %s)", runnerInfo, workflowName)
-				// Check if already added
-				found := false
-				for _, existing := range deps.ActionsCIDependencies.SelfHostedRunners {
-					if existing == runnerRef {
-						found = true
-						break
-					}
-				}
-				if !found {
-					deps.ActionsCIDependencies.SelfHostedRunners = append(deps.ActionsCIDependencies.SelfHostedRunners, runnerRef)
-				}
+		case "required_reviewers":
+			detail := fmt.Sprintf("%d reviewer(s)", len(rule.Reviewers))
+			if rule.PreventSelfReview {
+				detail += ", no self-review"
 			}
+			details = append(details, detail)
 		}
 	}
+	return details
 }
 
-func analyzeOrganizationSpecificActions(content, workflowName, owner string, deps *types.OrganizationalDependencies) {
-	// Look for actions from the same organization
-	actionPattern := regexp.MustCompile(fmt.Sprintf(`uses:\s*%s/([^@\s]+)`, owner))
-	matches := actionPattern.FindAllStringSubmatch(content, -1)
-	
-	for _, match := range matches {
-		if len(match) > 1 {
-			actionName := match[1]
-			actionRef := fmt.Sprintf("%s/%s (in %s)", owner, actionName, workflowName)
-			// Check if already added
-			found := false
-			for _, existing := range deps.ActionsCIDependencies.OrgSpecificActions {
-				if existing == actionRef {
-					found = true
-					break
-				}
-			}
-			if !found {
-				deps.ActionsCIDependencies.OrgSpecificActions = append(deps.ActionsCIDependencies.OrgSpecificActions, actionRef)
+// EnvironmentReviewers lists the required reviewers of an environment as "team:<slug>" or
+// "user:<login>" items of the environment
+func EnvironmentReviewers(environment string, rules []EnvironmentProtectionRule) []string {
+	var reviewers []string
+	for _, rule := range rules {
+		if rule.Type != "required_reviewers" {
+			continue
+		}
+		for _, reviewer := range rule.Reviewers {
+			if reviewer.Type == "Team" {
+				reviewers = append(reviewers, EnvironmentItem("team:"+reviewer.Reviewer.Slug, environment))
+			} else {
+				reviewers = append(reviewers, EnvironmentItem("user:"+reviewer.Reviewer.Login, environment))
 			}
 		}
 	}
+	return reviewers
 }
 
-func analyzeCrossRepoTriggers(content, workflowName, owner string, deps *types.OrganizationalDependencies) {
-	// Look for workflow_run or repository_dispatch events targeting same org repos
-	triggerPatterns := []string{
-		fmt.Sprintf(`repository_dispatch.*%s/`, owner),
-		fmt.Sprintf(`workflow_run.*%s/`, owner),
-	}
-	
-	for _, pattern := range triggerPatterns {
-		re := regexp.MustCompile(pattern)
-		if re.MatchString(content) {
-			triggerRef := fmt.Sprintf("Cross-repo trigger (in %s)", workflowName)
-			// Check if already added
-			found := false
-			for _, existing := range deps.ActionsCIDependencies.CrossRepoWorkflowTriggers {
-				if existing == triggerRef {
-					found = true
-					break
-				}
-			}
-			if !found {
-				deps.ActionsCIDependencies.CrossRepoWorkflowTriggers = append(deps.ActionsCIDependencies.CrossRepoWorkflowTriggers, triggerRef)
-			}
-		}
+// EnvironmentItem names an item of an environment, e.g. "DEPLOY_KEY (environment: production)"
+func EnvironmentItem(name, environment string) string {
+	return fmt.Sprintf("%s (environment: %s)", name, environment)
+}
+
+// SplitEnvironmentItem is the inverse of EnvironmentItem
+func SplitEnvironmentItem(item string) (name, environment string) {
+	idx := strings.LastIndex(item, " (environment: ")
+	if idx == -1 || !strings.HasSuffix(item, ")") {
+		return item, ""
 	}
+	return item[:idx], strings.TrimSuffix(item[idx+len(" (environment: "):], ")")
 }
 
-// analyzeEnvironments analyzes repository environments for organizational dependencies
-func analyzeEnvironments(client api.RESTClient, owner, repo string, deps *types.OrganizationalDependencies) error {
-	// Note: This requires special API access and might not be available to all users
-	var environments struct {
-		Environments []struct {
+// getDeploymentBranchPatterns lists the branch/tag name patterns allowed to deploy to an environment
+func getDeploymentBranchPatterns(client api.RESTClient, owner, repo, environment string) ([]string, error) {
+	var response struct {
+		BranchPolicies []struct {
 			Name string `json:"name"`
-		} `json:"environments"`
+			Type string `json:"type"`
+		} `json:"branch_policies"`
 	}
 
-	err := client.Get(fmt.Sprintf("repos/%s/%s/environments", owner, repo), &environments)
+	err := ghclient.GetAllField(&client, fmt.Sprintf("repos/%s/%s/environments/%s/deployment-branch-policies", owner, repo, url.PathEscape(environment)), "branch_policies", &response.BranchPolicies)
 	if err != nil {
-		return err // Environments not accessible
+		return nil, err
 	}
 
-	for _, env := range environments.Environments {
-		envRef := fmt.Sprintf("Environment: %s", env.Name)
-		deps.ActionsCIDependencies.EnvironmentDependencies = append(deps.ActionsCIDependencies.EnvironmentDependencies, envRef)
+	var patterns []string
+	for _, policy := range response.BranchPolicies {
+		if policy.Type == "tag" {
+			patterns = append(patterns, "tag:"+policy.Name)
+		} else {
+			patterns = append(patterns, policy.Name)
+		}
 	}
-
-	return nil
+	return patterns, nil
 }
 
-// isGitHubHostedRunner checks if a runner name is a GitHub-hosted runner
+// isGitHubHostedRunner checks if a runner label is a standard GitHub-hosted runner image
 func isGitHubHostedRunner(runner string) bool {
-	githubRunners := []string{
-		"ubuntu-latest", "ubuntu-22.04", "ubuntu-20.04",
-		"windows-latest", "windows-2022", "windows-2019",
-		"macos-latest", "macos-14", "macos-13", "macos-12",
-	}
-	
-	for _, gh := range githubRunners {
-		if runner == gh {
-			return true
-		}
-	}
-	return false
+	return githubHostedPattern.MatchString(runner)
 }
 
 // analyzeRequiredWorkflows analyzes workflow requirements from repository rulesets
@@ -275,7 +293,7 @@ func analyzeRequiredWorkflows(client api.RESTClient, owner, repo string, deps *t
 		Enforcement string `json:"enforcement"`
 	}
 
-	err := client.Get(fmt.Sprintf("repos/%s/%s/rulesets", owner, repo), &rulesets)
+	err := ghclient.GetAll(&client, fmt.Sprintf("repos/%s/%s/rulesets", owner, repo), &rulesets)
 	if err != nil {
 		return err // Repository rulesets not accessible
 	}
@@ -313,4 +331,84 @@ func analyzeRequiredWorkflows(client api.RESTClient, owner, repo string, deps *t
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+// analyzeSecretAndVariableSources lists the secrets and variables defined on the repository
+// itself and the source org values of the organization variables referenced by workflows.
+// Workflow references resolve to a repository-level secret or variable first, so references
+// to names the repository defines are not organization dependencies.
+func analyzeSecretAndVariableSources(client api.RESTClient, owner, repo string, deps *types.OrganizationalDependencies) error {
+	ci := &deps.ActionsCIDependencies
+
+	var secrets struct {
+		Secrets []struct {
+			Name string `json:"name"`
+		} `json:"secrets"`
+	}
+	if err := ghclient.GetAllField(&client, fmt.Sprintf("repos/%s/%s/actions/secrets", owner, repo), "secrets", &secrets.Secrets); err == nil {
+		for _, secret := range secrets.Secrets {
+			ci.RepositorySecrets = append(ci.RepositorySecrets, secret.Name)
+		}
+	}
+
+	var repoVariables struct {
+		Variables []struct {
+			Name string `json:"name"`
+		} `json:"variables"`
+	}
+	if err := ghclient.GetAllField(&client, fmt.Sprintf("repos/%s/%s/actions/variables", owner, repo), "variables", &repoVariables.Variables); err == nil {
+		for _, variable := range repoVariables.Variables {
+			ci.RepositoryVariables = append(ci.RepositoryVariables, variable.Name)
+		}
+	}
+
+	ci.OrganizationSecrets = WithoutRepositoryLevel(ci.OrganizationSecrets, ci.RepositorySecrets)
+	ci.OrganizationVariables = WithoutRepositoryLevel(ci.OrganizationVariables, ci.RepositoryVariables)
+	if len(ci.OrganizationVariables) == 0 {
+		return nil
+	}
+
+	var orgVariables struct {
+		Variables []struct {
+			Name  string `json:"name"`
+			Value string `json:"value"`
+		} `json:"variables"`
+	}
+	if err := ghclient.GetAllField(&client, fmt.Sprintf("orgs/%s/actions/variables", owner), "variables", &orgVariables.Variables); err != nil {
+		return err
+	}
+
+	referenced := make(map[string]bool)
+	for _, variable := range ci.OrganizationVariables {
+		referenced[strings.ToUpper(strings.SplitN(variable, " (in ", 2)[0])] = true
+	}
+	for _, variable := range orgVariables.Variables {
+		name := strings.ToUpper(variable.Name)
+		if referenced[name] {
+			if ci.OrganizationVariableValues == nil {
+				ci.OrganizationVariableValues = make(map[string]string)
+			}
+			ci.OrganizationVariableValues[name] = variable.Value
+		}
+	}
+
+	return nil
+}
+
+// WithoutRepositoryLevel drops the workflow references ("NAME (in workflow.yml)") whose name is
+// defined on the repository itself; names compare case-insensitively, like GitHub does
+func WithoutRepositoryLevel(references, repositoryNames []string) []string {
+	if len(repositoryNames) == 0 {
+		return references
+	}
+	defined := make(map[string]bool, len(repositoryNames))
+	for _, name := range repositoryNames {
+		defined[strings.ToUpper(name)] = true
+	}
+	var kept []string
+	for _, reference := range references {
+		if !defined[strings.ToUpper(strings.SplitN(reference, " (in ", 2)[0])] {
+			kept = append(kept, reference)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,232 @@
+package dependencies
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+func TestWithoutRepositoryLevel(t *testing.T) {
+	references := []string{"DEPLOY_TOKEN (in deploy.yml)", "NPM_TOKEN (in ci.yml)", "deploy_token (in release.yml)"}
+	tests := []struct {
+		name       string
+		repository []string
+		want       []string
+	}{
+		{"no repository secrets", nil, references},
+		{"repository secret shadows the org secret", []string{"Deploy_Token"}, []string{"NPM_TOKEN (in ci.yml)"}},
+		{"all defined on the repository", []string{"DEPLOY_TOKEN", "NPM_TOKEN"}, nil},
+		{"unrelated repository secret", []string{"OTHER"}, references},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := WithoutRepositoryLevel(references, tt.repository); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("WithoutRepositoryLevel(%v) = %v, want %v", tt.repository, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnvironmentProtectionRules(t *testing.T) {
+	var rules []EnvironmentProtectionRule
+	raw := `[
+		{"type": "wait_timer", "wait_timer": 30},
+		{"type": "required_reviewers", "prevent_self_review": true, "reviewers": [
+			{"type": "Team", "reviewer": {"id": 7, "slug": "platform"}},
+			{"type": "User", "reviewer": {"id": 9, "login": "octocat"}}]},
+		{"type": "branch_policy"}]`
+	if err := json.Unmarshal([]byte(raw), &rules); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := DescribeProtectionRules(rules), []string{"wait timer: 30m", "2 reviewer(s), no self-review"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("DescribeProtectionRules() = %v, want %v", got, want)
+	}
+	want := []string{"team:platform (environment: production)", "user:octocat (environment: production)"}
+	if got := EnvironmentReviewers("production", rules); !reflect.DeepEqual(got, want) {
+		t.Errorf("EnvironmentReviewers() = %v, want %v", got, want)
+	}
+}
+
+func TestSplitEnvironmentItem(t *testing.T) {
+	tests := []struct {
+		item, name, environment string
+	}{
+		{"DEPLOY_KEY (environment: production)", "DEPLOY_KEY", "production"},
+		{"team:platform (environment: staging (eu))", "team:platform", "staging (eu)"},
+		{"DEPLOY_KEY", "DEPLOY_KEY", ""},
+	}
+	for _, tt := range tests {
+		if name, environment := SplitEnvironmentItem(tt.item); name != tt.name || environment != tt.environment {
+			t.Errorf("SplitEnvironmentItem(%q) = %q, %q; want %q, %q", tt.item, name, environment, tt.name, tt.environment)
+		}
+	}
+}
+
+func TestAnalyzeWorkflowContent(t *testing.T) {
+	tests := []struct {
+		name      string
+		workflow  string
+		secrets   []string
+		variables []string
+		runners   []string
+		actions   []string
+		triggers  []string
+		attests   []string
+		local     []string
+		inherited []inheritedCall
+	}{
+		{
+			name: "comments and plain text are not references",
+			workflow: `
+# uses secrets.OLD_TOKEN and runs-on: legacy-runner
+on: push
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo "secrets.NOT_AN_EXPRESSION"
+      - run: echo ${{ secrets.NPM_TOKEN }} ${{ secrets.GITHUB_TOKEN }}
+`,
+			secrets: []string{"NPM_TOKEN (in ci.yml, job build)"},
+		},
+		{
+			name: "workflow env applies to every job, jobs are named",
+			workflow: `
+env:
+  REGION: ${{ vars.REGION }}
+jobs:
+  build:
+    runs-on: ubuntu-22.04
+    env:
+      TOKEN: ${{ secrets['DEPLOY_TOKEN'] }}
+    steps:
+      - run: echo $REGION
+  release:
+    if: vars.RELEASES_ENABLED == 'true'
+    runs-on: macos-14
+    steps:
+      - run: echo ${{ secrets.DEPLOY_TOKEN }} ${{ vars.REGION }}
+`,
+			secrets:   []string{"DEPLOY_TOKEN (in ci.yml, jobs build, release)"},
+			variables: []string{"REGION (in ci.yml)", "RELEASES_ENABLED (in ci.yml, job release)"},
+		},
+		{
+			name: "matrix runs-on with include",
+			workflow: `
+jobs:
+  test:
+    strategy:
+      matrix:
+        os: [ubuntu-latest, gpu-linux]
+        include:
+          - os: [self-hosted, arm64]
+    runs-on: ${{ matrix.os }}
+    steps:
+      - run: make test
+`,
+			runners: []string{"Self-hosted runner: gpu-linux (in ci.yml, job test)", "Self-hosted runner: self-hosted, arm64 (in ci.yml, job test)"},
+		},
+		{
+			name: "runner groups and unresolved expressions",
+			workflow: `
+jobs:
+  deploy:
+    runs-on:
+      group: production
+      labels: [linux]
+  dynamic:
+    runs-on: ${{ inputs.runner }}
+    steps:
+      - run: make
+`,
+			runners: []string{"Self-hosted runner: group production, linux (in ci.yml, job deploy)", "Self-hosted runner: ${{ inputs.runner }} (in ci.yml, job dynamic)"},
+		},
+		{
+			name: "organization actions, reusable workflows and dispatches",
+			workflow: `
+jobs:
+  shared:
+    uses: Acme/workflows/.github/workflows/build.yml@main
+    secrets: inherit
+  external:
+    uses: other/workflows/.github/workflows/lint.yml@v1
+    secrets: inherit
+  notify:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+      - uses: ./local-action
+      - uses: acme/deploy-action@v2
+      - uses: peter-evans/repository-dispatch@v3
+        with:
+          repository: acme/downstream
+      - run: gh workflow run release.yml --repo acme/releases
+`,
+			actions:   []string{"Acme/workflows/.github/workflows/build.yml (in ci.yml, job shared)", "acme/deploy-action (in ci.yml, job notify)"},
+			triggers:  []string{"Cross-repo trigger: acme/downstream (in ci.yml, job notify)", "Cross-repo trigger: acme/releases (in ci.yml, job notify)"},
+			local:     []string{"local-action"},
+			inherited: []inheritedCall{{caller: "ci.yml", job: "shared", workflow: "Acme/workflows/.github/workflows/build.yml", ref: "main"}},
+		},
+		{
+			name: "artifact attestations",
+			workflow: `
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/attest-build-provenance@v2
+        with:
+          subject-path: dist/app
+      - uses: actions/attest-sbom@v1
+  image:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/attest-build-provenance@1c608d11d69870c2092266b3f9a6f3abbf17002c
+      - uses: actions/attest-something-else@v1
+`,
+			attests: []string{"Artifact attestation: actions/attest-build-provenance (in ci.yml, jobs build, image)", "Artifact attestation: actions/attest-sbom (in ci.yml, job build)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deps := &types.OrganizationalDependencies{}
+			calls, err := analyzeWorkflowContent([]byte(tt.workflow), "ci.yml", "acme", deps)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(calls.localActions, tt.local) {
+				t.Errorf("local actions = %v, want %v", calls.localActions, tt.local)
+			}
+			if !reflect.DeepEqual(calls.inherited, tt.inherited) {
+				t.Errorf("inherited calls = %+v, want %+v", calls.inherited, tt.inherited)
+			}
+			ci := deps.ActionsCIDependencies
+			for _, check := range []struct {
+				field     string
+				got, want []string
+			}{
+				{"OrganizationSecrets", ci.OrganizationSecrets, tt.secrets},
+				{"OrganizationVariables", ci.OrganizationVariables, tt.variables},
+				{"SelfHostedRunners", ci.SelfHostedRunners, tt.runners},
+				{"OrgSpecificActions", ci.OrgSpecificActions, tt.actions},
+				{"CrossRepoWorkflowTriggers", ci.CrossRepoWorkflowTriggers, tt.triggers},
+				{"ArtifactAttestations", ci.ArtifactAttestations, tt.attests},
+			} {
+				if !reflect.DeepEqual(check.got, check.want) {
+					t.Errorf("%s = %v, want %v", check.field, check.got, check.want)
+				}
+			}
+		})
+	}
+}
+
+func TestAnalyzeWorkflowContentInvalid(t *testing.T) {
+	if _, err := analyzeWorkflowContent([]byte("jobs: [unclosed"), "broken.yml", "acme", &types.OrganizationalDependencies{}); err == nil {
+		t.Error("analyzeWorkflowContent() accepted an invalid workflow")
+	}
+}
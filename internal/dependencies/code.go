@@ -2,12 +2,12 @@
 package dependencies
 
 import (
-	"encoding/base64"
 	"fmt"
 	"regexp"
 	"strings"
 
 	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/jefeish/gh-repo-transfer/internal/ghclient"
 	"github.com/jefeish/gh-repo-transfer/internal/types"
 )
 
@@ -28,27 +28,26 @@ func AnalyzeCodeDependencies(client api.RESTClient, owner, repo string, deps *ty
 		// Non-fatal error - Dockerfiles might not exist
 	}
 
+	// Analyze README and docs/ for release, tag and badge URLs under the old organization
+	if err := analyzeDocumentationURLs(client, owner, repo, deps); err != nil {
+		// Non-fatal error - README or docs/ might not exist
+	}
+
 	return nil
 }
 
 // analyzeGitSubmodules checks for submodules pointing to the same organization
 func analyzeGitSubmodules(client api.RESTClient, owner, repo string, deps *types.OrganizationalDependencies) error {
-	var content struct {
-		Content string `json:"content"`
-	}
-
-	err := client.Get(fmt.Sprintf("repos/%s/%s/contents/.gitmodules", owner, repo), &content)
+	decoded, err := readRepositoryFile(client, owner, repo, ".gitmodules")
 	if err != nil {
 		return err // .gitmodules doesn't exist
 	}
 
-	decoded, err := base64.StdEncoding.DecodeString(content.Content)
-	if err != nil {
-		return err
-	}
-
 	gitmodulesContent := string(decoded)
 	lines := strings.Split(gitmodulesContent, "\n")
+	if dir := Subdirectory(owner, repo); dir != "" {
+		lines = submodulesIn(lines, dir)
+	}
 
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
@@ -72,20 +71,21 @@ func analyzeGitSubmodules(client api.RESTClient, owner, repo string, deps *types
 	return nil
 }
 
+// packageFiles are the package manager files checked for organization-specific registries
+var packageFiles = []string{
+	"package.json",     // npm
+	"pom.xml",         // Maven
+	"build.gradle",    // Gradle
+	"requirements.txt", // Python pip
+	"Pipfile",         // Python pipenv
+	"go.mod",          // Go modules
+	".npmrc",          // npm config
+}
+
 // analyzePackageFiles analyzes package files for organization-specific registries
 func analyzePackageFiles(client api.RESTClient, owner, repo string, deps *types.OrganizationalDependencies) error {
-	packageFiles := []string{
-		"package.json",     // npm
-		"pom.xml",         // Maven
-		"build.gradle",    // Gradle
-		"requirements.txt", // Python pip
-		"Pipfile",         // Python pipenv
-		"go.mod",          // Go modules
-		".npmrc",          // npm config
-	}
-
 	for _, file := range packageFiles {
-		if err := analyzePackageFile(client, owner, repo, file, deps); err != nil {
+		if err := analyzePackageFile(client, owner, repo, scopedPath(owner, repo, file), deps); err != nil {
 			// Non-fatal - file might not exist
 			continue
 		}
@@ -95,16 +95,7 @@ func analyzePackageFiles(client api.RESTClient, owner, repo string, deps *types.
 }
 
 func analyzePackageFile(client api.RESTClient, owner, repo, filename string, deps *types.OrganizationalDependencies) error {
-	var content struct {
-		Content string `json:"content"`
-	}
-
-	err := client.Get(fmt.Sprintf("repos/%s/%s/contents/%s", owner, repo, filename), &content)
-	if err != nil {
-		return err
-	}
-
-	decoded, err := base64.StdEncoding.DecodeString(content.Content)
+	decoded, err := readRepositoryFile(client, owner, repo, filename)
 	if err != nil {
 		return err
 	}
@@ -130,16 +121,17 @@ func analyzePackageFile(client api.RESTClient, owner, repo, filename string, dep
 	return nil
 }
 
+// dockerFiles are the container build files checked for organization container registries
+var dockerFiles = []string{
+	"Dockerfile",
+	"docker-compose.yml",
+	"docker-compose.yaml",
+}
+
 // analyzeDockerfiles checks for organization-specific container registries
 func analyzeDockerfiles(client api.RESTClient, owner, repo string, deps *types.OrganizationalDependencies) error {
-	dockerFiles := []string{
-		"Dockerfile",
-		"docker-compose.yml",
-		"docker-compose.yaml",
-	}
-
 	for _, file := range dockerFiles {
-		if err := analyzeDockerfile(client, owner, repo, file, deps); err != nil {
+		if err := analyzeDockerfile(client, owner, repo, scopedPath(owner, repo, file), deps); err != nil {
 			// Non-fatal - file might not exist
 			continue
 		}
@@ -149,22 +141,18 @@ func analyzeDockerfiles(client api.RESTClient, owner, repo string, deps *types.O
 }
 
 func analyzeDockerfile(client api.RESTClient, owner, repo, filename string, deps *types.OrganizationalDependencies) error {
-	var content struct {
-		Content string `json:"content"`
-	}
-
-	err := client.Get(fmt.Sprintf("repos/%s/%s/contents/%s", owner, repo, filename), &content)
+	decoded, err := readRepositoryFile(client, owner, repo, filename)
 	if err != nil {
 		return err
 	}
 
-	decoded, err := base64.StdEncoding.DecodeString(content.Content)
-	if err != nil {
-		return err
-	}
+	recordOrgRegistry(string(decoded), filename, owner, deps)
+	return nil
+}
 
-	fileContent := string(decoded)
-	
+// recordOrgRegistry records the organization-specific container registry content refers to,
+// if any; content is a Dockerfile, a compose file or an image reference
+func recordOrgRegistry(fileContent, filename, owner string, deps *types.OrganizationalDependencies) {
 	// Look for organization-specific container registries
 	registryPatterns := []string{
 		fmt.Sprintf(`%s\.azurecr\.io`, owner),
@@ -181,21 +169,92 @@ func analyzeDockerfile(client api.RESTClient, owner, repo, filename string, deps
 			break
 		}
 	}
-
-	return nil
 }
 
 // isOrganizationalRepo checks if a repository URL belongs to the same organization
 func isOrganizationalRepo(url, owner string) bool {
-	// Handle GitHub URLs
-	if strings.Contains(url, "github.com") {
+	// Handle GitHub URLs of the run's host
+	host := ghclient.Host()
+	if strings.Contains(url, host) {
 		parts := strings.Split(url, "/")
 		for i, part := range parts {
-			if part == "github.com" && i+1 < len(parts) {
+			if part == host && i+1 < len(parts) {
 				repoOwner := parts[i+1]
 				return repoOwner == owner
 			}
 		}
 	}
 	return false
-}
\ No newline at end of file
+}
+
+// docURLKinds maps URL path fragments to a human-readable kind, checked in order
+var docURLKinds = []struct {
+	fragment string
+	kind     string
+}{
+	{"/releases/download/", "release download"},
+	{"/releases", "release page"},
+	{"/archive/refs/tags/", "tag archive"},
+	{"/tags", "tag page"},
+	{"badge.svg", "workflow badge"},
+	{"img.shields.io", "shields.io badge"},
+}
+
+// analyzeDocumentationURLs scans the README and markdown files in docs/ for hard-coded
+// release, tag and badge URLs that point at the repository under its current organization
+func analyzeDocumentationURLs(client api.RESTClient, owner, repo string, deps *types.OrganizationalDependencies) error {
+	if decoded, readmePath, err := readAnalyzedReadme(client, owner, repo); err == nil {
+		refs := findDocumentationURLs(string(decoded), readmePath, owner, repo)
+		deps.CodeDependencies.DocumentationURLReferences = append(deps.CodeDependencies.DocumentationURLReferences, refs...)
+	}
+
+	docs, err := listRepositoryDir(client, owner, repo, scopedPath(owner, repo, "docs"))
+	if err != nil {
+		return err // docs/ doesn't exist
+	}
+
+	for _, item := range docs {
+		if item.Type != "file" || !strings.HasSuffix(strings.ToLower(item.Name), ".md") {
+			continue
+		}
+
+		decoded, err := readRepositoryFile(client, owner, repo, item.Path)
+		if err != nil {
+			continue
+		}
+
+		refs := findDocumentationURLs(string(decoded), item.Path, owner, repo)
+		deps.CodeDependencies.DocumentationURLReferences = append(deps.CodeDependencies.DocumentationURLReferences, refs...)
+	}
+
+	return nil
+}
+
+// findDocumentationURLs returns "file:line url (kind)" entries for release, tag and badge URLs
+// that reference owner/repo. Other links to the repository are ignored.
+func findDocumentationURLs(content, filename, owner, repo string) []string {
+	repoPath := regexp.QuoteMeta(owner) + "/" + regexp.QuoteMeta(repo)
+	// The URL is the first group; the repository name must end at a path separator or where the
+	// URL ends, so that owner/repo-other does not match. RE2 has no lookahead, so the character
+	// ending the URL is matched outside the group.
+	urlPattern := regexp.MustCompile(`(?i)(https?://(?:` + regexp.QuoteMeta(ghclient.Host()) + `/` + repoPath + `/[^\s)"'<>\]]*|img\.shields\.io/github/[a-z0-9\-/]+?/` + repoPath + `(?:[/?#][^\s)"'<>\]]*)?))(?:[\s)"'<>\]]|$)`)
+
+	var refs []string
+	for lineNum, line := range strings.Split(content, "\n") {
+		for _, submatch := range urlPattern.FindAllStringSubmatch(line, -1) {
+			match := submatch[1]
+			kind := ""
+			for _, candidate := range docURLKinds {
+				if strings.Contains(strings.ToLower(match), candidate.fragment) {
+					kind = candidate.kind
+					break
+				}
+			}
+			if kind == "" {
+				continue
+			}
+			refs = append(refs, fmt.Sprintf("%s:%d %s (%s)", filename, lineNum+1, match, kind))
+		}
+	}
+	return refs
+}
@@ -0,0 +1,32 @@
+package dependencies
+
+import "testing"
+
+func TestFindDocumentationURLs(t *testing.T) {
+	content := "# Project\n" +
+		"[![CI](https://github.com/acme/widget/actions/workflows/ci.yml/badge.svg)](https://github.com/acme/widget/actions)\n" +
+		"Download from https://github.com/acme/widget/releases/latest\n" +
+		"Source: https://github.com/acme/widget/archive/refs/tags/v1.0.tar.gz\n" +
+		"![version](https://img.shields.io/github/v/tag/acme/widget)\n" +
+		"![fork](https://img.shields.io/github/v/tag/acme/widget-foo) ![stars](https://img.shields.io/github/stars/acme/widget?style=social)\n" +
+		"See https://github.com/acme/other/releases for unrelated releases\n"
+
+	refs := findDocumentationURLs(content, "README.md", "acme", "widget")
+
+	expected := []string{
+		"README.md:2 https://github.com/acme/widget/actions/workflows/ci.yml/badge.svg (workflow badge)",
+		"README.md:3 https://github.com/acme/widget/releases/latest (release page)",
+		"README.md:4 https://github.com/acme/widget/archive/refs/tags/v1.0.tar.gz (tag archive)",
+		"README.md:5 https://img.shields.io/github/v/tag/acme/widget (shields.io badge)",
+		"README.md:6 https://img.shields.io/github/stars/acme/widget?style=social (shields.io badge)",
+	}
+
+	if len(refs) != len(expected) {
+		t.Fatalf("findDocumentationURLs() returned %d refs, want %d: %v", len(refs), len(expected), refs)
+	}
+	for i := range expected {
+		if refs[i] != expected[i] {
+			t.Errorf("refs[%d] = %q, want %q", i, refs[i], expected[i])
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package dependencies
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+// CodeownersLocations are the locations GitHub reads a CODEOWNERS file from, in order
+var CodeownersLocations = []string{
+	".github/CODEOWNERS",
+	"CODEOWNERS",
+	"docs/CODEOWNERS",
+}
+
+// Prefixes of the CODEOWNERS requirements
+const (
+	CodeownersTeam    = "Team: "          // "Team: @org/slug"
+	CodeownersUser    = "User: "          // "User: @login"
+	CodeownersEmail   = "Email: "         // "Email: someone@example.com"
+	CodeownersUnknown = "Unknown owner: " // "Unknown owner: @org/slug (team not found in org)"
+)
+
+// CodeownersRule is a rule of a CODEOWNERS file: a pattern and the owners of the matching files
+type CodeownersRule struct {
+	Line    int
+	Pattern string
+	Owners  []string // @login, @org/team-slug or email address; none clears the ownership
+}
+
+// analyzeCODEOWNERS reads the CODEOWNERS file GitHub uses for the repository and records every
+// owner its rules name, resolving teams and users in the source organization
+func analyzeCODEOWNERS(client api.RESTClient, owner, repo string, deps *types.OrganizationalDependencies) error {
+	location, content, err := readCODEOWNERS(client, owner, repo)
+	if err != nil {
+		return err
+	}
+
+	requirements := parseCODEOWNERS(content, owner)
+	deps.AccessPermissions.CodeownersFile = location
+	deps.AccessPermissions.CodeownersRequirements = append(deps.AccessPermissions.CodeownersRequirements,
+		resolveCodeowners(client, owner, requirements)...)
+	return nil
+}
+
+// readCODEOWNERS returns the location and content of the CODEOWNERS file of a repository, the
+// first one of CodeownersLocations that exists. With --path, a CODEOWNERS file of the
+// subdirectory becomes the extracted repository's own; otherwise only the rules of the root
+// file covering the subdirectory carry over.
+func readCODEOWNERS(client api.RESTClient, owner, repo string) (string, string, error) {
+	dir := Subdirectory(owner, repo)
+	if dir != "" {
+		for _, location := range CodeownersLocations {
+			if decoded, err := readRepositoryFile(client, owner, repo, path.Join(dir, location)); err == nil {
+				return path.Join(dir, location), string(decoded), nil
+			}
+		}
+	}
+
+	for _, location := range CodeownersLocations {
+		decoded, err := readRepositoryFile(client, owner, repo, location)
+		if err != nil {
+			continue
+		}
+		content := string(decoded)
+		if dir != "" {
+			content = scopeCODEOWNERS(content, dir)
+		}
+		return location, content, nil
+	}
+	return "", "", fmt.Errorf("no CODEOWNERS file in %s/%s", owner, repo)
+}
+
+// ParseCODEOWNERSRules parses the rules of a CODEOWNERS file. Comments, including those after a
+// rule, are left out; spaces escaped with a backslash belong to the pattern.
+func ParseCODEOWNERSRules(content string) []CodeownersRule {
+	var rules []CodeownersRule
+	for index, line := range strings.Split(content, "\n") {
+		fields := codeownersFields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		rules = append(rules, CodeownersRule{Line: index + 1, Pattern: fields[0], Owners: fields[1:]})
+	}
+	return rules
+}
+
+// codeownersFields splits a CODEOWNERS line at unescaped whitespace, up to a comment
+func codeownersFields(line string) []string {
+	var fields []string
+	var field strings.Builder
+	escaped := false
+	for _, r := range strings.TrimSpace(line) {
+		switch {
+		case escaped:
+			field.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			field.WriteRune(r)
+			escaped = true
+		case r == '#' && field.Len() == 0:
+			return fields
+		case r == ' ' || r == '\t':
+			if field.Len() > 0 {
+				fields = append(fields, field.String())
+				field.Reset()
+			}
+		default:
+			field.WriteRune(r)
+		}
+	}
+	if field.Len() > 0 {
+		fields = append(fields, field.String())
+	}
+	return fields
+}
+
+// parseCODEOWNERS returns the owners named by the rules of a CODEOWNERS file as requirements,
+// each once in the order they first appear: "Team: @org/slug", "User: @login" or "Email: address"
+func parseCODEOWNERS(content, owner string) []string {
+	var owners []string
+	seen := make(map[string]bool)
+	for _, rule := range ParseCODEOWNERSRules(content) {
+		for _, ownerRef := range rule.Owners {
+			var requirement string
+			switch name := strings.TrimPrefix(ownerRef, "@"); {
+			case strings.HasPrefix(ownerRef, "@") && strings.Contains(name, "/"):
+				requirement = CodeownersTeam + ownerRef
+			case strings.HasPrefix(ownerRef, "@"):
+				requirement = CodeownersUser + ownerRef
+			case strings.Contains(ownerRef, "@"):
+				requirement = CodeownersEmail + ownerRef
+			default:
+				continue // Not an owner
+			}
+			if key := strings.ToLower(requirement); !seen[key] {
+				seen[key] = true
+				owners = append(owners, requirement)
+			}
+		}
+	}
+	return owners
+}
+
+// resolveCodeowners looks up the teams of the source organization and the users named in
+// CODEOWNERS. Owners that do not exist are ignored by GitHub already and are reported as
+// unknown; owners that could not be looked up are kept as they are.
+func resolveCodeowners(client api.RESTClient, owner string, requirements []string) []string {
+	resolved := make([]string, 0, len(requirements))
+	for _, requirement := range requirements {
+		var err error
+		reason := ""
+		switch {
+		case strings.HasPrefix(requirement, CodeownersTeam):
+			org, slug, _ := strings.Cut(strings.TrimPrefix(requirement, CodeownersTeam+"@"), "/")
+			if !strings.EqualFold(org, owner) {
+				break // Teams of other organizations, e.g. the target, are validated there
+			}
+			var team struct {
+				Slug string `json:"slug"`
+			}
+			err = client.Get(fmt.Sprintf("orgs/%s/teams/%s", owner, slug), &team)
+			reason = fmt.Sprintf("team not found in %s", owner)
+		case strings.HasPrefix(requirement, CodeownersUser):
+			var user struct {
+				Login string `json:"login"`
+			}
+			err = client.Get(fmt.Sprintf("users/%s", strings.TrimPrefix(requirement, CodeownersUser+"@")), &user)
+			reason = "user not found"
+		}
+		if err != nil && strings.Contains(err.Error(), "404") {
+			ownerRef := requirement[strings.Index(requirement, "@"):]
+			requirement = fmt.Sprintf("%s%s (%s)", CodeownersUnknown, ownerRef, reason)
+		}
+		resolved = append(resolved, requirement)
+	}
+	return resolved
+}
+
+// RewriteCODEOWNERS replaces the team references of sourceOrg in a CODEOWNERS file with those
+// of targetOrg, for the teams exists reports in the target. It returns the new content and the
+// references rewritten and left unchanged, each once. Patterns and comments are not touched.
+func RewriteCODEOWNERS(content, sourceOrg, targetOrg string, exists func(slug string) bool) (string, []string, []string) {
+	teamPattern := regexp.MustCompile(`(?i)(^|\s)@` + regexp.QuoteMeta(sourceOrg) + `/([A-Za-z0-9_.\-]+)`)
+	var rewritten, kept []string
+	seen := make(map[string]bool)
+	record := func(list *[]string, reference string) {
+		if key := strings.ToLower(reference); !seen[key] {
+			seen[key] = true
+			*list = append(*list, reference)
+		}
+	}
+
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		fields := codeownersFields(line)
+		if len(fields) < 2 {
+			continue // Comment, blank line or rule without owners
+		}
+		// The owners follow the pattern and end where a comment starts
+		start := strings.Index(line, fields[0]) + len(fields[0])
+		end := len(line)
+		if comment := strings.Index(line[start:], "#"); comment >= 0 {
+			end = start + comment
+		}
+		owners := teamPattern.ReplaceAllStringFunc(line[start:end], func(match string) string {
+			groups := teamPattern.FindStringSubmatch(match)
+			reference := strings.TrimSpace(match)
+			if !exists(groups[2]) {
+				record(&kept, reference)
+				return match
+			}
+			record(&rewritten, reference)
+			return groups[1] + "@" + targetOrg + "/" + groups[2]
+		})
+		lines[i] = line[:start] + owners + line[end:]
+	}
+	return strings.Join(lines, "\n"), rewritten, kept
+}
@@ -0,0 +1,70 @@
+package dependencies
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCODEOWNERSRules(t *testing.T) {
+	content := "# Owners\n\n*       @acme/platform   # fallback\n/docs/My\\ Guide.md jane@acme.com @octocat\n/vendor/\n"
+	want := []CodeownersRule{
+		{Line: 3, Pattern: "*", Owners: []string{"@acme/platform"}},
+		{Line: 4, Pattern: `/docs/My\ Guide.md`, Owners: []string{"jane@acme.com", "@octocat"}},
+		{Line: 5, Pattern: "/vendor/", Owners: []string{}},
+	}
+	got := ParseCODEOWNERSRules(content)
+	if len(got) != len(want) {
+		t.Fatalf("ParseCODEOWNERSRules() = %+v, want %d rules", got, len(want))
+	}
+	for i := range want {
+		if got[i].Line != want[i].Line || got[i].Pattern != want[i].Pattern || len(got[i].Owners) != len(want[i].Owners) ||
+			(len(want[i].Owners) > 0 && !reflect.DeepEqual(got[i].Owners, want[i].Owners)) {
+			t.Errorf("rule %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestParseCODEOWNERS(t *testing.T) {
+	content := `* @acme/platform
+/web/ @acme/frontend @Octocat
+/api/ @ACME/Platform @octocat jane@acme.com # API owners
+/legacy/ @other-org/team
+`
+	want := []string{
+		"Team: @acme/platform",
+		"Team: @acme/frontend",
+		"User: @Octocat",
+		"Email: jane@acme.com",
+		"Team: @other-org/team",
+	}
+	if got := parseCODEOWNERS(content, "acme"); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseCODEOWNERS() = %v, want %v", got, want)
+	}
+}
+
+func TestRewriteCODEOWNERS(t *testing.T) {
+	content := `# Teams of @acme/platform own everything
+*            @acme/platform @octocat
+/web/        @ACME/frontend @acme/platform   # @acme/frontend reviews
+/docs/@acme/ @acme/docs
+/legacy/     @acme/retired
+`
+	exists := func(slug string) bool { return slug != "retired" }
+	got, rewritten, kept := RewriteCODEOWNERS(content, "acme", "acme-new", exists)
+
+	want := `# Teams of @acme/platform own everything
+*            @acme-new/platform @octocat
+/web/        @acme-new/frontend @acme-new/platform   # @acme/frontend reviews
+/docs/@acme/ @acme-new/docs
+/legacy/     @acme/retired
+`
+	if got != want {
+		t.Errorf("RewriteCODEOWNERS() content =\n%s\nwant\n%s", got, want)
+	}
+	if wantRewritten := []string{"@acme/platform", "@ACME/frontend", "@acme/docs"}; !reflect.DeepEqual(rewritten, wantRewritten) {
+		t.Errorf("rewritten = %v, want %v", rewritten, wantRewritten)
+	}
+	if wantKept := []string{"@acme/retired"}; !reflect.DeepEqual(kept, wantKept) {
+		t.Errorf("kept = %v, want %v", kept, wantKept)
+	}
+}
@@ -0,0 +1,75 @@
+package dependencies
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+// SignatureSourceBranchProtection is the signature requirement of a classic branch protection rule
+const SignatureSourceBranchProtection = "branch protection"
+
+// AnalyzeCommitSigning records what requires signed commits on the repository's default branch
+// (branch protection, repository or organization rulesets) and whether the organization and the
+// repository require sign-off on web-based commits
+func AnalyzeCommitSigning(client api.RESTClient, owner, repo string, deps *types.OrganizationalDependencies) error {
+	var repoInfo struct {
+		DefaultBranch            string `json:"default_branch"`
+		WebCommitSignoffRequired bool   `json:"web_commit_signoff_required"`
+	}
+	if err := client.Get(fmt.Sprintf("repos/%s/%s", owner, repo), &repoInfo); err != nil {
+		return fmt.Errorf("failed to get repository info: %v", err)
+	}
+	policy := &types.CommitSigningPolicy{RepositorySignoff: repoInfo.WebCommitSignoffRequired}
+
+	var orgInfo struct {
+		WebCommitSignoffRequired bool `json:"web_commit_signoff_required"`
+	}
+	if err := client.Get(fmt.Sprintf("orgs/%s", owner), &orgInfo); err == nil {
+		policy.OrganizationSignoff = orgInfo.WebCommitSignoffRequired
+	}
+
+	if repoInfo.DefaultBranch != "" {
+		branch := url.PathEscape(repoInfo.DefaultBranch)
+		var signatures struct {
+			Enabled bool `json:"enabled"`
+		}
+		if err := client.Get(fmt.Sprintf("repos/%s/%s/branches/%s/protection/required_signatures", owner, repo, branch), &signatures); err == nil && signatures.Enabled {
+			policy.SignaturesRequiredBy = append(policy.SignaturesRequiredBy, SignatureSourceBranchProtection)
+		}
+
+		var rules []struct {
+			Type              string `json:"type"`
+			RulesetSourceType string `json:"ruleset_source_type"`
+			RulesetID         int    `json:"ruleset_id"`
+		}
+		if err := client.Get(fmt.Sprintf("repos/%s/%s/rules/branches/%s", owner, repo, branch), &rules); err == nil {
+			for _, rule := range rules {
+				if rule.Type == "required_signatures" {
+					policy.SignaturesRequiredBy = append(policy.SignaturesRequiredBy, SignatureSource(rule.RulesetSourceType, rule.RulesetID))
+				}
+			}
+		}
+	}
+
+	deps.OrgGovernance.CommitSigning = policy
+	return nil
+}
+
+// SignatureSource describes the ruleset requiring signatures, e.g. "organization ruleset 42"
+func SignatureSource(sourceType string, rulesetID int) string {
+	if sourceType == "" {
+		sourceType = "Repository"
+	}
+	return fmt.Sprintf("%s ruleset %d", strings.ToLower(sourceType), rulesetID)
+}
+
+// MovesWithRepository reports whether a signature requirement is defined on the repository itself
+// (branch protection or repository ruleset) and therefore survives a transfer
+func MovesWithRepository(source string) bool {
+	return source == SignatureSourceBranchProtection || strings.HasPrefix(source, "repository ruleset")
+}
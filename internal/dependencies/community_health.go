@@ -0,0 +1,109 @@
+package dependencies
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+// communityHealthKinds are the community health files and templates an organization's .github
+// repository provides as defaults to repositories without their own
+var communityHealthKinds = []string{
+	"CODE_OF_CONDUCT",
+	"CONTRIBUTING",
+	"DISCUSSION_TEMPLATE",
+	"FUNDING",
+	"GOVERNANCE",
+	"ISSUE_TEMPLATE",
+	"PULL_REQUEST_TEMPLATE",
+	"SECURITY",
+	"SUPPORT",
+}
+
+// organizationHealthDefaults caches the community health defaults per organization, which
+// every repository of a batch inherits
+var organizationHealthDefaults = struct {
+	sync.Mutex
+	byOrganization map[string][]string
+}{byOrganization: make(map[string][]string)}
+
+// AnalyzeCommunityHealthFiles records the community health files and templates the repository
+// has, e.g. "CONTRIBUTING (repository)", and those it inherits from the organization's .github
+// repository, e.g. "SECURITY (default of acme/.github)"
+func AnalyzeCommunityHealthFiles(client api.RESTClient, owner, repo string, deps *types.OrganizationalDependencies) error {
+	own := CommunityHealthFiles(client, owner, repo)
+	for _, kind := range own {
+		deps.OrgGovernance.CommunityHealthFiles = append(deps.OrgGovernance.CommunityHealthFiles, fmt.Sprintf("%s (repository)", kind))
+	}
+	if strings.EqualFold(repo, ".github") {
+		return nil
+	}
+	for _, kind := range OrganizationHealthDefaults(client, owner) {
+		if !containsString(own, kind) {
+			deps.OrgGovernance.CommunityHealthFiles = append(deps.OrgGovernance.CommunityHealthFiles, fmt.Sprintf("%s (default of %s/.github)", kind, owner))
+		}
+	}
+	return nil
+}
+
+// OrganizationHealthDefaults returns the community health files and templates of an
+// organization's .github repository, read once per organization
+func OrganizationHealthDefaults(client api.RESTClient, organization string) []string {
+	key := strings.ToLower(organization)
+	organizationHealthDefaults.Lock()
+	kinds, ok := organizationHealthDefaults.byOrganization[key]
+	organizationHealthDefaults.Unlock()
+	if ok {
+		return kinds
+	}
+
+	kinds = CommunityHealthFiles(client, organization, ".github")
+	organizationHealthDefaults.Lock()
+	organizationHealthDefaults.byOrganization[key] = kinds
+	organizationHealthDefaults.Unlock()
+	return kinds
+}
+
+// CommunityHealthFiles returns the kinds of community health files and templates a repository
+// has in its root, .github or docs directory, sorted
+func CommunityHealthFiles(client api.RESTClient, owner, repo string) []string {
+	var kinds []string
+	for _, dir := range []string{"", ".github", "docs"} {
+		entries, err := listRepositoryDir(client, owner, repo, dir)
+		if err != nil {
+			continue // Directory doesn't exist
+		}
+		for _, entry := range entries {
+			if kind := communityHealthKind(entry); kind != "" && !containsString(kinds, kind) {
+				kinds = append(kinds, kind)
+			}
+		}
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// communityHealthKind returns the kind of community health file or template directory an entry
+// is, e.g. CONTRIBUTING for contributing.md, or "" for other entries
+func communityHealthKind(entry contentEntry) string {
+	name := strings.ToUpper(strings.TrimSuffix(entry.Name, path.Ext(entry.Name)))
+	for _, kind := range communityHealthKinds {
+		if name != kind {
+			continue
+		}
+		switch {
+		case kind == "DISCUSSION_TEMPLATE":
+			if entry.Type == "dir" {
+				return kind
+			}
+		case entry.Type == "file", entry.Type == "dir" && (kind == "ISSUE_TEMPLATE" || kind == "PULL_REQUEST_TEMPLATE"):
+			return kind
+		}
+	}
+	return ""
+}
@@ -0,0 +1,25 @@
+package dependencies
+
+import "testing"
+
+func TestCommunityHealthKind(t *testing.T) {
+	tests := []struct {
+		entry contentEntry
+		want  string
+	}{
+		{contentEntry{Name: "CONTRIBUTING.md", Type: "file"}, "CONTRIBUTING"},
+		{contentEntry{Name: "security.md", Type: "file"}, "SECURITY"},
+		{contentEntry{Name: "FUNDING.yml", Type: "file"}, "FUNDING"},
+		{contentEntry{Name: "ISSUE_TEMPLATE", Type: "dir"}, "ISSUE_TEMPLATE"},
+		{contentEntry{Name: "pull_request_template.md", Type: "file"}, "PULL_REQUEST_TEMPLATE"},
+		{contentEntry{Name: "DISCUSSION_TEMPLATE", Type: "dir"}, "DISCUSSION_TEMPLATE"},
+		{contentEntry{Name: "discussion_template.md", Type: "file"}, ""},
+		{contentEntry{Name: "SECURITY", Type: "dir"}, ""},
+		{contentEntry{Name: "README.md", Type: "file"}, ""},
+	}
+	for _, tt := range tests {
+		if got := communityHealthKind(tt.entry); got != tt.want {
+			t.Errorf("communityHealthKind(%+v) = %q, want %q", tt.entry, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package dependencies
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// localClone is the working tree the content analyzers read for one repository instead of
+// the contents API (see --local-path)
+var localClone struct {
+	repository string // owner/repo
+	path       string
+}
+
+// SetLocalClone makes the analyzers reading file contents (code, workflows, local actions and
+// CODEOWNERS) read them from a clone of repository ("owner/repo") at dir instead of the
+// contents API. Other repositories, and everything that is not file content, still come from
+// the API.
+func SetLocalClone(repository, dir string) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read local clone: %v", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("local clone %s is not a directory", dir)
+	}
+	localClone.repository, localClone.path = repository, dir
+	return nil
+}
+
+// contentEntry is a file or directory of a repository as the contents API lists it
+type contentEntry struct {
+	Name string `json:"name"`
+	Type string `json:"type"` // file or dir
+	Path string `json:"path"`
+}
+
+// localClonePath returns the local file of a repository path when the repository has a local
+// clone. Paths cannot leave the clone.
+func localClonePath(owner, repo, filePath string) (string, bool) {
+	if localClone.path == "" || !strings.EqualFold(localClone.repository, owner+"/"+repo) {
+		return "", false
+	}
+	return filepath.Join(localClone.path, filepath.FromSlash(path.Clean("/"+filePath))), true
+}
+
+// readRepositoryFile reads a file of a repository's default branch, from its local clone or
+// tree snapshot when it has one
+func readRepositoryFile(client api.RESTClient, owner, repo, filePath string) ([]byte, error) {
+	if local, ok := localClonePath(owner, repo, filePath); ok {
+		return os.ReadFile(local)
+	}
+	if tree := treeOf(owner, repo); tree != nil {
+		return tree.read(client, owner, repo, filePath)
+	}
+
+	var content struct {
+		Content string `json:"content"`
+	}
+	if err := client.Get(fmt.Sprintf("repos/%s/%s/contents/%s", owner, repo, filePath), &content); err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(content.Content)
+}
+
+// listRepositoryDir lists a directory of a repository's default branch, from its local clone or
+// tree snapshot when it has one
+func listRepositoryDir(client api.RESTClient, owner, repo, dir string) ([]contentEntry, error) {
+	if local, ok := localClonePath(owner, repo, dir); ok {
+		files, err := os.ReadDir(local)
+		if err != nil {
+			return nil, err
+		}
+		var entries []contentEntry
+		for _, file := range files {
+			entryType := "file"
+			if file.IsDir() {
+				entryType = "dir"
+			}
+			entries = append(entries, contentEntry{Name: file.Name(), Type: entryType, Path: path.Join(dir, file.Name())})
+		}
+		return entries, nil
+	}
+	if tree := treeOf(owner, repo); tree != nil {
+		return tree.list(owner, repo, dir)
+	}
+
+	var entries []contentEntry
+	err := client.Get(fmt.Sprintf("repos/%s/%s/contents/%s", owner, repo, dir), &entries)
+	return entries, err
+}
+
+// readRepositoryReadme reads the README GitHub shows for a repository and returns its path.
+// In a local clone or a tree snapshot it is looked for like GitHub does, in .github, the root
+// and docs.
+func readRepositoryReadme(client api.RESTClient, owner, repo string) ([]byte, string, error) {
+	if _, ok := localClonePath(owner, repo, ""); ok || treeOf(owner, repo) != nil {
+		for _, dir := range []string{".github", "", "docs"} {
+			entries, err := listRepositoryDir(client, owner, repo, dir)
+			if err != nil {
+				continue
+			}
+			for _, entry := range entries {
+				if entry.Type == "file" && strings.HasPrefix(strings.ToLower(entry.Name), "readme") {
+					content, err := readRepositoryFile(client, owner, repo, entry.Path)
+					return content, entry.Path, err
+				}
+			}
+		}
+		return nil, "", fmt.Errorf("no README in %s/%s", owner, repo)
+	}
+
+	var readme struct {
+		Path    string `json:"path"`
+		Content string `json:"content"`
+	}
+	if err := client.Get(fmt.Sprintf("repos/%s/%s/readme", owner, repo), &readme); err != nil {
+		return nil, "", err
+	}
+	content, err := base64.StdEncoding.DecodeString(readme.Content)
+	return content, readme.Path, err
+}
+
+// readAnalyzedReadme reads the README of the part of a repository being analyzed: the README
+// GitHub shows, or the one in the subdirectory the analysis is scoped to
+func readAnalyzedReadme(client api.RESTClient, owner, repo string) ([]byte, string, error) {
+	dir := Subdirectory(owner, repo)
+	if dir == "" {
+		return readRepositoryReadme(client, owner, repo)
+	}
+	entries, err := listRepositoryDir(client, owner, repo, dir)
+	if err != nil {
+		return nil, "", err
+	}
+	for _, entry := range entries {
+		if entry.Type == "file" && strings.HasPrefix(strings.ToLower(entry.Name), "readme") {
+			content, err := readRepositoryFile(client, owner, repo, entry.Path)
+			return content, entry.Path, err
+		}
+	}
+	return nil, "", fmt.Errorf("no README in %s of %s/%s", dir, owner, repo)
+}
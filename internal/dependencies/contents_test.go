@@ -0,0 +1,72 @@
+package dependencies
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+func TestLocalClone(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"README.md":                   "# Service",
+		".github/workflows/build.yml": "on: push",
+		"docs/guide.md":               "guide",
+	}
+	for name, content := range files {
+		local := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(local), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(local, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := SetLocalClone("Acme/Service", dir); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { localClone.repository, localClone.path = "", "" }()
+
+	var client api.RESTClient // Unused: every read is local
+	tests := []struct {
+		name    string
+		path    string
+		want    string
+		wantErr bool
+	}{
+		{"file", ".github/workflows/build.yml", "on: push", false},
+		{"leading slash", "/docs/guide.md", "guide", false},
+		{"escape stays in the clone", "../../README.md", "# Service", false},
+		{"missing", "go.mod", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readRepositoryFile(client, "acme", "service", tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("readRepositoryFile(%q) error = %v, wantErr %v", tt.path, err, tt.wantErr)
+			}
+			if string(got) != tt.want {
+				t.Errorf("readRepositoryFile(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+
+	entries, err := listRepositoryDir(client, "acme", "service", ".github")
+	if err != nil || len(entries) != 1 || entries[0] != (contentEntry{Name: "workflows", Type: "dir", Path: ".github/workflows"}) {
+		t.Errorf("listRepositoryDir(.github) = %v, %v", entries, err)
+	}
+
+	readme, readmePath, err := readRepositoryReadme(client, "acme", "service")
+	if err != nil || string(readme) != "# Service" || readmePath != "README.md" {
+		t.Errorf("readRepositoryReadme() = %q, %q, %v", readme, readmePath, err)
+	}
+
+	if _, ok := localClonePath("acme", "other", "README.md"); ok {
+		t.Errorf("localClonePath() used the clone for another repository")
+	}
+	if err := SetLocalClone("acme/service", filepath.Join(dir, "README.md")); err == nil {
+		t.Errorf("SetLocalClone() accepted a file")
+	}
+}
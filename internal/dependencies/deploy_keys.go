@@ -0,0 +1,36 @@
+package dependencies
+
+import (
+	"fmt"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+
+	"github.com/jefeish/gh-repo-transfer/internal/ghclient"
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+// AnalyzeDeployKeys records the repository's deploy keys, which have to be added again after
+// a transfer. Listing them requires admin access to the repository.
+func AnalyzeDeployKeys(client api.RESTClient, owner, repo string, deps *types.OrganizationalDependencies) error {
+	var keys []struct {
+		Title    string `json:"title"`
+		ReadOnly bool   `json:"read_only"`
+	}
+	if err := ghclient.GetAll(&client, fmt.Sprintf("repos/%s/%s/keys", owner, repo), &keys); err != nil {
+		return fmt.Errorf("failed to list deploy keys: %v", err)
+	}
+	for _, key := range keys {
+		deps.AccessPermissions.DeployKeys = append(deps.AccessPermissions.DeployKeys, FormatDeployKey(key.Title, key.ReadOnly))
+	}
+	return nil
+}
+
+// FormatDeployKey describes a deploy key without its public key, e.g.
+// "Deploy key: release-bot (read/write)"
+func FormatDeployKey(title string, readOnly bool) string {
+	access := "read/write"
+	if readOnly {
+		access = "read-only"
+	}
+	return fmt.Sprintf("Deploy key: %s (%s)", title, access)
+}
@@ -0,0 +1,176 @@
+package dependencies
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/jefeish/gh-repo-transfer/internal/ghclient"
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+// DeepMode decides which repositories get the expensive part of the analysis: reading file
+// contents, searching code and reading ruleset details (see --deep)
+type DeepMode string
+
+const (
+	DeepAlways DeepMode = "always" // Every repository is analyzed in depth
+	DeepAuto   DeepMode = "auto"   // Only repositories whose fast pass score reaches the threshold
+	DeepNever  DeepMode = "never"  // Only the fast pass and the analyses listing settings
+)
+
+// DefaultDeepThreshold is the fast pass score from which --deep auto analyzes a repository in depth
+const DefaultDeepThreshold = 5
+
+// deepMode and deepThreshold are the settings used by FastPass (see --deep, --deep-threshold)
+var (
+	deepMode      = DeepAlways
+	deepThreshold = DefaultDeepThreshold
+)
+
+// recentlyPushed is how recent the last push of an active repository is
+const recentlyPushed = 180 * 24 * time.Hour
+
+// ParseDeepMode validates a --deep value
+func ParseDeepMode(value string) (DeepMode, error) {
+	switch m := DeepMode(value); m {
+	case DeepAlways, DeepAuto, DeepNever:
+		return m, nil
+	}
+	return "", fmt.Errorf("invalid deep analysis mode '%s' (use auto, always or never)", value)
+}
+
+// SetDeepMode sets the mode and threshold used by FastPass
+func SetDeepMode(mode DeepMode, threshold int) {
+	deepMode, deepThreshold = mode, threshold
+}
+
+// FastPassSignals are the cheap facts a preliminary score is computed from
+type FastPassSignals struct {
+	Workflows  int
+	Teams      int
+	Visibility string // public, private or internal
+	PushedAt   time.Time
+}
+
+// FastPass reads a few cheap signals of a repository (its metadata, the number of workflows and
+// of teams) and decides whether it is analyzed in depth. With DeepAlways it returns nil without
+// sending a request. When the fast pass fails, the repository is analyzed in depth.
+func FastPass(client api.RESTClient, owner, repo string) *types.AnalysisDepth {
+	if deepMode == DeepAlways {
+		return nil
+	}
+
+	signals, err := readFastPassSignals(client, owner, repo)
+	if err != nil {
+		return &types.AnalysisDepth{
+			Mode:      string(deepMode),
+			Threshold: deepThreshold,
+			Deep:      deepMode == DeepAuto,
+			Signals:   []string{fmt.Sprintf("fast pass failed: %v", err)},
+		}
+	}
+	score, reasons := FastPassScore(signals, time.Now())
+	return &types.AnalysisDepth{
+		Mode:      string(deepMode),
+		Score:     score,
+		Threshold: deepThreshold,
+		Deep:      deepMode == DeepAuto && score >= deepThreshold,
+		Signals:   reasons,
+	}
+}
+
+// FastPassScore is the preliminary score of a repository: 3 points per workflow, since
+// workflows hold most secrets, runners and org actions; 1 per team; 2 for a private or internal
+// repository and 2 for a push within the last 180 days. It also returns what the score is made of.
+func FastPassScore(signals FastPassSignals, now time.Time) (int, []string) {
+	score := 0
+	var reasons []string
+	add := func(points int, reason string) {
+		if points > 0 {
+			score += points
+			reasons = append(reasons, fmt.Sprintf("%s (+%d)", reason, points))
+		}
+	}
+
+	add(3*signals.Workflows, fmt.Sprintf("%d workflow(s)", signals.Workflows))
+	add(signals.Teams, fmt.Sprintf("%d team(s)", signals.Teams))
+	if signals.Visibility == "private" || signals.Visibility == "internal" {
+		add(2, signals.Visibility)
+	}
+	if !signals.PushedAt.IsZero() && now.Sub(signals.PushedAt) < recentlyPushed {
+		add(2, "pushed within 180 days")
+	}
+	return score, reasons
+}
+
+// readFastPassSignals reads the signals of FastPassScore with three requests
+func readFastPassSignals(client api.RESTClient, owner, repo string) (FastPassSignals, error) {
+	var signals FastPassSignals
+
+	var repository struct {
+		Visibility string    `json:"visibility"`
+		PushedAt   time.Time `json:"pushed_at"`
+	}
+	if err := client.Get(fmt.Sprintf("repos/%s/%s", owner, repo), &repository); err != nil {
+		return signals, err
+	}
+	signals.Visibility = repository.Visibility
+	signals.PushedAt = repository.PushedAt
+
+	var workflows struct {
+		TotalCount int `json:"total_count"`
+	}
+	if err := client.Get(fmt.Sprintf("repos/%s/%s/actions/workflows?per_page=1", owner, repo), &workflows); err != nil {
+		return signals, err
+	}
+	signals.Workflows = workflows.TotalCount
+
+	var teams []struct {
+		Slug string `json:"slug"`
+	}
+	if err := ghclient.GetAll(&client, fmt.Sprintf("repos/%s/%s/teams", owner, repo), &teams); err != nil {
+		return signals, err
+	}
+	signals.Teams = len(teams)
+	return signals, nil
+}
+
+// AnalyzeActionsCISettings is the part of AnalyzeActionsCIDependencies that lists settings:
+// environments and the repository's secrets and variables. Workflow contents, required
+// workflows of rulesets and code search are left to the deep analysis.
+func AnalyzeActionsCISettings(client api.RESTClient, owner, repo string, deps *types.OrganizationalDependencies) error {
+	if err := analyzeEnvironments(client, owner, repo, deps); err != nil {
+		// Non-fatal error - environments might not be accessible
+	}
+	if err := analyzeSecretAndVariableSources(client, owner, repo, deps); err != nil {
+		// Non-fatal error - listing secrets requires admin access
+	}
+	return nil
+}
+
+// AnalyzeOrgGovernanceSettings is the part of AnalyzeOrgGovernance that reads organization
+// settings: member and security policies, event sinks and enterprise policies. Branch
+// protections, ruleset details, templates, commit signing and push rules are left to the deep
+// analysis.
+func AnalyzeOrgGovernanceSettings(client api.RESTClient, owner, repo string, deps *types.OrganizationalDependencies) error {
+	if err := checkSecurityAndMemberPolicies(client, owner, deps); err != nil {
+		if verbose := checkVerbose(); verbose {
+			fmt.Fprintf(os.Stderr, "Could not access organization policies: %v\n", err)
+		}
+	}
+	if err := analyzeSecurityPolicies(client, owner, deps); err != nil {
+		if verbose := checkVerbose(); verbose {
+			fmt.Fprintf(os.Stderr, "Could not access security policies: %v\n", err)
+		}
+	}
+	if err := analyzeEventSinks(client, owner, &deps.OrgGovernance); err != nil {
+		if verbose := checkVerbose(); verbose {
+			fmt.Fprintf(os.Stderr, "Could not analyze event sinks: %v\n", err)
+		}
+	}
+	analyzeEnterprisePolicies(client, &deps.OrgGovernance)
+	separatePoliciesForJSON(deps)
+	return nil
+}
@@ -0,0 +1,42 @@
+package dependencies
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFastPassScore(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		signals     FastPassSignals
+		wantScore   int
+		wantReasons []string
+	}{
+		{"empty public repository", FastPassSignals{Visibility: "public"}, 0, nil},
+		{"stale public docs", FastPassSignals{Teams: 1, Visibility: "public", PushedAt: now.AddDate(-2, 0, 0)}, 1, []string{"1 team(s) (+1)"}},
+		{"active private service", FastPassSignals{Workflows: 2, Teams: 3, Visibility: "private", PushedAt: now.AddDate(0, -1, 0)}, 13,
+			[]string{"2 workflow(s) (+6)", "3 team(s) (+3)", "private (+2)", "pushed within 180 days (+2)"}},
+		{"internal without workflows", FastPassSignals{Teams: 1, Visibility: "internal"}, 3, []string{"1 team(s) (+1)", "internal (+2)"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, reasons := FastPassScore(tt.signals, now)
+			if score != tt.wantScore || !reflect.DeepEqual(reasons, tt.wantReasons) {
+				t.Errorf("FastPassScore() = %d %v, want %d %v", score, reasons, tt.wantScore, tt.wantReasons)
+			}
+		})
+	}
+}
+
+func TestParseDeepMode(t *testing.T) {
+	for _, value := range []string{"auto", "always", "never"} {
+		if mode, err := ParseDeepMode(value); err != nil || string(mode) != value {
+			t.Errorf("ParseDeepMode(%q) = %q, %v", value, mode, err)
+		}
+	}
+	if _, err := ParseDeepMode("sometimes"); err == nil {
+		t.Error("ParseDeepMode(\"sometimes\") succeeded")
+	}
+}
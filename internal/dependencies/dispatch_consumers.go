@@ -0,0 +1,48 @@
+package dependencies
+
+import (
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+
+	"github.com/jefeish/gh-repo-transfer/internal/impact"
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+// analyzeDispatchConsumers records workflows in the organization that send repository_dispatch
+// events to the repository, which then deploys through its environments. Only repositories with
+// environments and a workflow running on repository_dispatch are searched, which keeps code
+// search (rate limited) out of most analyses.
+func analyzeDispatchConsumers(client api.RESTClient, owner, repo string, deps *types.OrganizationalDependencies) error {
+	ci := &deps.ActionsCIDependencies
+	if len(ci.EnvironmentDependencies) == 0 {
+		return nil
+	}
+	receives, err := impact.ReceivesDispatches(client, owner, repo)
+	if err != nil || !receives {
+		return err
+	}
+
+	var environments []string
+	for _, environment := range ci.EnvironmentDependencies {
+		environments = append(environments, environmentName(environment))
+	}
+	consumers, err := impact.FindDispatchConsumers(client, owner, repo, environments)
+	if err != nil {
+		return err
+	}
+	for _, consumer := range consumers {
+		ci.DispatchConsumers = append(ci.DispatchConsumers, consumer.String())
+	}
+	return nil
+}
+
+// environmentName extracts the name from an environment dependency,
+// "Environment: production (deploys from: main)" → "production"
+func environmentName(dependency string) string {
+	name := strings.TrimPrefix(dependency, "Environment: ")
+	if idx := strings.Index(name, " ("); idx != -1 {
+		name = name[:idx]
+	}
+	return name
+}
@@ -0,0 +1,34 @@
+package dependencies
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/jefeish/gh-repo-transfer/internal/enterprise"
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+// enterpriseSlug is the enterprise of the source organization (--enterprise); it enables
+// the audit log stream and enterprise policy checks
+var enterpriseSlug string
+
+// SetEnterprise sets the enterprise used for enterprise-level checks; empty disables them
+func SetEnterprise(slug string) {
+	enterpriseSlug = slug
+}
+
+// analyzeEnterprisePolicies records the enterprise policies that override the source org's settings
+func analyzeEnterprisePolicies(client api.RESTClient, governance *types.OrgGovernance) {
+	if enterpriseSlug == "" {
+		return
+	}
+	policies, err := enterprise.Policies(client, enterpriseSlug)
+	if err != nil {
+		if checkVerbose() {
+			fmt.Fprintf(os.Stderr, "Could not scan enterprise policies: %v\n", err)
+		}
+		return
+	}
+	governance.EnterprisePolicies = policies
+}
@@ -0,0 +1,105 @@
+package dependencies
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/jefeish/gh-repo-transfer/internal/ghclient"
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+// organizationOnlyEvents are webhook events that are not about a repository, so a hook
+// subscribed only to these keeps working when a repository leaves the organization
+var organizationOnlyEvents = map[string]bool{
+	"meta":                          true,
+	"membership":                    true,
+	"organization":                  true,
+	"org_block":                     true,
+	"team":                          true,
+	"installation":                  true,
+	"installation_target":           true,
+	"marketplace_purchase":          true,
+	"personal_access_token_request": true,
+	"projects_v2":                   true,
+	"projects_v2_item":              true,
+	"sponsorship":                   true,
+}
+
+// analyzeEventSinks finds org webhooks and audit log streams that receive events of the
+// organization's repositories. Events of a moved repository stop flowing to them.
+func analyzeEventSinks(client api.RESTClient, owner string, governance *types.OrgGovernance) error {
+	var hooks []struct {
+		Active bool     `json:"active"`
+		Events []string `json:"events"`
+		Config struct {
+			URL string `json:"url"`
+		} `json:"config"`
+	}
+	if err := ghclient.GetAll(&client, fmt.Sprintf("orgs/%s/hooks", owner), &hooks); err != nil {
+		return fmt.Errorf("failed to list org webhooks: %v", err)
+	}
+	for _, hook := range hooks {
+		events := RepositoryEvents(hook.Events)
+		if !hook.Active || len(events) == 0 {
+			continue
+		}
+		governance.EventSinks = append(governance.EventSinks,
+			fmt.Sprintf("Org webhook → %s (events: %s)", WebhookDestination(hook.Config.URL), strings.Join(events, ", ")))
+	}
+
+	if enterpriseSlug == "" {
+		return nil
+	}
+	var streams []struct {
+		StreamType    string `json:"stream_type"`
+		StreamDetails string `json:"stream_details"`
+		Enabled       bool   `json:"enabled"`
+	}
+	if err := ghclient.GetAll(&client, fmt.Sprintf("enterprises/%s/audit-log/streams", enterpriseSlug), &streams); err != nil {
+		if checkVerbose() {
+			fmt.Fprintf(os.Stderr, "Could not list audit log streams of enterprise %s: %v\n", enterpriseSlug, err)
+		}
+		return nil
+	}
+	for _, stream := range streams {
+		if !stream.Enabled {
+			continue
+		}
+		item := "Audit log stream → " + stream.StreamType
+		if stream.StreamDetails != "" {
+			item += fmt.Sprintf(" (%s)", stream.StreamDetails)
+		}
+		governance.EventSinks = append(governance.EventSinks, item)
+	}
+	return nil
+}
+
+// RepositoryEvents returns the events of a webhook that are about repositories, sorted.
+// A wildcard subscription covers every repository event.
+func RepositoryEvents(events []string) []string {
+	var repoEvents []string
+	for _, event := range events {
+		if event == "*" {
+			return []string{"*"}
+		}
+		if !organizationOnlyEvents[event] {
+			repoEvents = append(repoEvents, event)
+		}
+	}
+	sort.Strings(repoEvents)
+	return repoEvents
+}
+
+// WebhookDestination reduces a webhook URL to scheme, host and path, dropping credentials and
+// query parameters that often carry tokens
+func WebhookDestination(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host == "" {
+		return "(unknown destination)"
+	}
+	return fmt.Sprintf("%s://%s%s", parsed.Scheme, parsed.Host, parsed.Path)
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/jefeish/gh-repo-transfer/internal/ghclient"
 	"github.com/jefeish/gh-repo-transfer/internal/types"
 )
 
@@ -38,6 +39,37 @@ func AnalyzeOrgGovernance(client api.RESTClient, owner, repo string, deps *types
 		}
 	}
 
+	// Analyze org webhooks and audit log streams receiving this repository's events
+	if err := analyzeEventSinks(client, owner, &deps.OrgGovernance); err != nil {
+		if verbose := checkVerbose(); verbose {
+			fmt.Fprintf(os.Stderr, "Could not analyze event sinks: %v\n", err)
+		}
+	}
+
+	// Analyze enterprise policies overriding organization settings (--enterprise)
+	analyzeEnterprisePolicies(client, &deps.OrgGovernance)
+
+	// Analyze signed commit and web commit sign-off requirements
+	if err := AnalyzeCommitSigning(client, owner, repo, deps); err != nil {
+		if verbose := checkVerbose(); verbose {
+			fmt.Fprintf(os.Stderr, "Could not analyze commit signing: %v\n", err)
+		}
+	}
+
+	// Analyze push rulesets restricting file sizes, paths and extensions
+	if err := AnalyzePushRules(client, owner, repo, deps); err != nil {
+		if verbose := checkVerbose(); verbose {
+			fmt.Fprintf(os.Stderr, "Could not analyze push rulesets: %v\n", err)
+		}
+	}
+
+	// Analyze community health files, the repository's own and the organization defaults
+	if err := AnalyzeCommunityHealthFiles(client, owner, repo, deps); err != nil {
+		if verbose := checkVerbose(); verbose {
+			fmt.Fprintf(os.Stderr, "Could not analyze community health files: %v\n", err)
+		}
+	}
+
 	// Separate policies into repository policies and member privileges for JSON output
 	separatePoliciesForJSON(deps)
 
@@ -93,7 +125,7 @@ func analyzeRepoRulesets(client api.RESTClient, owner, repo string, deps *types.
 		fmt.Fprintf(os.Stderr, "Checking for repository rulesets via repos/%s/%s/rulesets\n", owner, repo)
 	}
 
-	err := client.Get(fmt.Sprintf("repos/%s/%s/rulesets", owner, repo), &rulesets)
+	err := ghclient.GetAll(&client, fmt.Sprintf("repos/%s/%s/rulesets", owner, repo), &rulesets)
 	if err != nil {
 		return err // Repository rulesets not accessible
 	}
@@ -117,7 +149,7 @@ func analyzeOrgRulesets(client api.RESTClient, owner, repo string, deps *types.O
 		fmt.Fprintf(os.Stderr, "Checking for organization-level rulesets via orgs/%s/rulesets\n", owner)
 	}
 
-	err := client.Get(fmt.Sprintf("orgs/%s/rulesets", owner), &rulesets)
+	err := ghclient.GetAll(&client, fmt.Sprintf("orgs/%s/rulesets", owner), &rulesets)
 	if err != nil {
 		return err // Organization rulesets not accessible
 	}
@@ -329,7 +361,7 @@ func analyzeBranchProtections(client api.RESTClient, owner, repo string, deps *t
 		Protected bool   `json:"protected"`
 	}
 
-	err := client.Get(fmt.Sprintf("repos/%s/%s/branches", owner, repo), &branches)
+	err := ghclient.GetAll(&client, fmt.Sprintf("repos/%s/%s/branches", owner, repo), &branches)
 	if err != nil {
 		return err
 	}
@@ -599,7 +631,7 @@ func analyzeBranchProtectionPolicies(client api.RESTClient, owner, repo string,
 		} `json:"protection"`
 	}
 	
-	err := client.Get(fmt.Sprintf("repos/%s/%s/branches", owner, repo), &branches)
+	err := ghclient.GetAll(&client, fmt.Sprintf("repos/%s/%s/branches", owner, repo), &branches)
 	if err != nil {
 		return err
 	}
@@ -608,13 +640,10 @@ func analyzeBranchProtectionPolicies(client api.RESTClient, owner, repo string,
 		fmt.Fprintf(os.Stderr, "Found %d branches, checking for protected branches\n", len(branches))
 	}
 
-	protectedBranches := 0
-	var restrictions []string
+	var rules []BranchProtectionRule
 
 	for _, branch := range branches {
 		if branch.Protection.Enabled {
-			protectedBranches++
-			
 			// Get detailed branch protection
 			var protection struct {
 				RequiredStatusChecks struct {
@@ -644,50 +673,108 @@ func analyzeBranchProtectionPolicies(client api.RESTClient, owner, repo string,
 				} `json:"allow_force_pushes"`
 			}
 			
+			rule := BranchProtectionRule{Pattern: branch.Name, Unreadable: true}
 			protErr := client.Get(fmt.Sprintf("repos/%s/%s/branches/%s/protection", owner, repo, branch.Name), &protection)
 			if protErr == nil {
-				if protection.RequiredPullRequestReviews.RequiredApprovingReviewCount > 0 {
-					restrictions = append(restrictions, fmt.Sprintf("Branch '%s': Requires %d approving reviews", 
-						branch.Name, protection.RequiredPullRequestReviews.RequiredApprovingReviewCount))
-				}
-				if protection.RequiredPullRequestReviews.RequireCodeOwnerReviews {
-					restrictions = append(restrictions, fmt.Sprintf("Branch '%s': Code owner reviews required", branch.Name))
-				}
-				if protection.EnforceAdmins.Enabled {
-					restrictions = append(restrictions, fmt.Sprintf("Branch '%s': Admin enforcement enabled", branch.Name))
-				}
-				if protection.RequiredLinearHistory.Enabled {
-					restrictions = append(restrictions, fmt.Sprintf("Branch '%s': Linear history required", branch.Name))
-				}
-				if protection.RequiredSignatures.Enabled {
-					restrictions = append(restrictions, fmt.Sprintf("Branch '%s': Signed commits required", branch.Name))
-				}
-				if !protection.AllowForcePushes.Enabled {
-					restrictions = append(restrictions, fmt.Sprintf("Branch '%s': Force pushes disabled", branch.Name))
-				}
-				if len(protection.RequiredStatusChecks.Contexts) > 0 || len(protection.RequiredStatusChecks.Checks) > 0 {
-					restrictions = append(restrictions, fmt.Sprintf("Branch '%s': Required status checks configured", branch.Name))
+				rule = BranchProtectionRule{
+					Pattern:                      branch.Name,
+					RequiredApprovingReviewCount: protection.RequiredPullRequestReviews.RequiredApprovingReviewCount,
+					RequiresCodeOwnerReviews:     protection.RequiredPullRequestReviews.RequireCodeOwnerReviews,
+					IsAdminEnforced:              protection.EnforceAdmins.Enabled,
+					RequiresLinearHistory:        protection.RequiredLinearHistory.Enabled,
+					RequiresCommitSignatures:     protection.RequiredSignatures.Enabled,
+					AllowsForcePushes:            protection.AllowForcePushes.Enabled,
+					RequiresStatusChecks:         len(protection.RequiredStatusChecks.Contexts) > 0 || len(protection.RequiredStatusChecks.Checks) > 0,
 				}
 			}
+			rules = append(rules, rule)
 		}
 	}
 
-	if protectedBranches > 0 {
-		policy := types.OrgPolicy{
-			Name:         "Branch Protection Policy",
-			Status:       "active",
-			Restrictions: restrictions,
-		}
+	if policy, ok := BranchProtectionPolicy(rules); ok {
 		deps.OrgGovernance.OrganizationPolicies = append(deps.OrgGovernance.OrganizationPolicies, policy)
 		
 		if verbose := checkVerbose(); verbose {
-			fmt.Fprintf(os.Stderr, "Found branch protection policy with %d protected branches\n", protectedBranches)
+			fmt.Fprintf(os.Stderr, "Found branch protection policy with %d protected branches\n", len(rules))
 		}
 	}
 
 	return nil
 }
 
+// BranchProtectionRule is the part of a branch protection rule the analysis reports. The
+// pattern is a branch name for REST protections and a name pattern for GraphQL rules.
+type BranchProtectionRule struct {
+	Pattern                      string
+	RequiredApprovingReviewCount int
+	RequiresCodeOwnerReviews     bool
+	IsAdminEnforced              bool
+	RequiresLinearHistory        bool
+	RequiresCommitSignatures     bool
+	AllowsForcePushes            bool
+	RequiresStatusChecks         bool
+	Unreadable                   bool // Protected, but the details could not be read
+}
+
+// BranchProtectionPolicy summarizes branch protection rules as a repository policy; ok is
+// false when there are no rules
+func BranchProtectionPolicy(rules []BranchProtectionRule) (policy types.OrgPolicy, ok bool) {
+	if len(rules) == 0 {
+		return types.OrgPolicy{}, false
+	}
+
+	var restrictions []string
+	for _, rule := range rules {
+		if rule.Unreadable {
+			continue
+		}
+		if rule.RequiredApprovingReviewCount > 0 {
+			restrictions = append(restrictions, fmt.Sprintf("Branch '%s': Requires %d approving reviews", 
+				rule.Pattern, rule.RequiredApprovingReviewCount))
+		}
+		if rule.RequiresCodeOwnerReviews {
+			restrictions = append(restrictions, fmt.Sprintf("Branch '%s': Code owner reviews required", rule.Pattern))
+		}
+		if rule.IsAdminEnforced {
+			restrictions = append(restrictions, fmt.Sprintf("Branch '%s': Admin enforcement enabled", rule.Pattern))
+		}
+		if rule.RequiresLinearHistory {
+			restrictions = append(restrictions, fmt.Sprintf("Branch '%s': Linear history required", rule.Pattern))
+		}
+		if rule.RequiresCommitSignatures {
+			restrictions = append(restrictions, fmt.Sprintf("Branch '%s': Signed commits required", rule.Pattern))
+		}
+		if !rule.AllowsForcePushes {
+			restrictions = append(restrictions, fmt.Sprintf("Branch '%s': Force pushes disabled", rule.Pattern))
+		}
+		if rule.RequiresStatusChecks {
+			restrictions = append(restrictions, fmt.Sprintf("Branch '%s': Required status checks configured", rule.Pattern))
+		}
+	}
+
+	return types.OrgPolicy{
+		Name:         "Branch Protection Policy",
+		Status:       "active",
+		Restrictions: restrictions,
+	}, true
+}
+
+// AddBranchProtectionPolicy adds the branch protection policy of prefetched rules to an
+// analysis that already separated its policies (see separatePoliciesForJSON)
+func AddBranchProtectionPolicy(rules []BranchProtectionRule, deps *types.OrganizationalDependencies) {
+	policy, ok := BranchProtectionPolicy(rules)
+	if !ok {
+		return
+	}
+	// Batch analyses share the policy slices of their organization context, so always copy
+	governance := &deps.OrgGovernance
+	if isRepositoryRuleset(policy) {
+		governance.RepositoryRulesets = append(governance.RepositoryRulesets[:len(governance.RepositoryRulesets):len(governance.RepositoryRulesets)], policy)
+	} else {
+		governance.RepositoryPolicies = append(governance.RepositoryPolicies[:len(governance.RepositoryPolicies):len(governance.RepositoryPolicies)], policy)
+	}
+}
+
 // analyzeRepositoryRulesetPolicies gets repository-level rulesets
 func analyzeRepositoryRulesetPolicies(client api.RESTClient, owner, repo string, deps *types.OrganizationalDependencies) error {
 	// Get repository rulesets
@@ -700,7 +787,7 @@ func analyzeRepositoryRulesetPolicies(client api.RESTClient, owner, repo string,
 		SourceType  string `json:"source_type"`
 	}
 
-	err := client.Get(fmt.Sprintf("repos/%s/%s/rulesets", owner, repo), &rulesets)
+	err := ghclient.GetAll(&client, fmt.Sprintf("repos/%s/%s/rulesets", owner, repo), &rulesets)
 	if err != nil {
 		return err
 	}
@@ -1056,7 +1143,7 @@ func analyzeOrgLevelRepositoryRulesets(client api.RESTClient, owner, repo string
 		} `json:"rules"`
 	}
 	
-	err := client.Get(fmt.Sprintf("orgs/%s/rulesets", owner), &rulesets)
+	err := ghclient.GetAll(&client, fmt.Sprintf("orgs/%s/rulesets", owner), &rulesets)
 	if err != nil {
 		if verbose := checkVerbose(); verbose {
 			fmt.Fprintf(os.Stderr, "Failed to get org rulesets: %v\n", err)
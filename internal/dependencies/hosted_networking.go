@@ -0,0 +1,171 @@
+package dependencies
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+
+	"github.com/jefeish/gh-repo-transfer/internal/ghclient"
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+// hostedRunner is a GitHub-hosted larger runner of an organization
+type hostedRunner struct {
+	Name            string   `json:"name"`
+	RunnerGroupID   int64    `json:"runner_group_id"`
+	PublicIPEnabled bool     `json:"public_ip_enabled"`
+	PublicIPs       []string `json:"-"`
+}
+
+// runnerNetworking is what an organization binds its runners to: the network configuration of
+// each runner group that uses private networking, and its hosted runners by name
+type runnerNetworking struct {
+	groupNetworks map[string]string // Lowercased group name → network configuration name
+	groupNames    map[int64]string
+	hosted        map[string]hostedRunner // Lowercased runner name → runner
+}
+
+var (
+	runnerNetworkingMu    sync.Mutex
+	runnerNetworkingByOrg = make(map[string]*runnerNetworking)
+)
+
+// analyzeNetworkBoundRunners finds the runners the workflows use that are bound to the
+// organization's network: runner groups with a hosted compute network configuration (Azure
+// private networking), and hosted runners with static public IPs that services allow-list.
+// Both belong to the organization and stay behind on a transfer.
+func analyzeNetworkBoundRunners(client api.RESTClient, owner string, deps *types.OrganizationalDependencies) error {
+	if len(deps.ActionsCIDependencies.SelfHostedRunners) == 0 {
+		return nil
+	}
+	networking, err := orgRunnerNetworking(client, owner)
+	if err != nil {
+		return err
+	}
+	bound, staticIP := networkBoundRunners(deps.ActionsCIDependencies.SelfHostedRunners, networking)
+	deps.ActionsCIDependencies.NetworkBoundRunnerGroups = append(deps.ActionsCIDependencies.NetworkBoundRunnerGroups, bound...)
+	deps.ActionsCIDependencies.StaticIPRunners = append(deps.ActionsCIDependencies.StaticIPRunners, staticIP...)
+	return nil
+}
+
+// orgRunnerNetworking reads the runner groups, network configurations and hosted runners of
+// an organization once per run
+func orgRunnerNetworking(client api.RESTClient, org string) (*runnerNetworking, error) {
+	runnerNetworkingMu.Lock()
+	defer runnerNetworkingMu.Unlock()
+	if networking, ok := runnerNetworkingByOrg[strings.ToLower(org)]; ok {
+		return networking, nil
+	}
+
+	var groups []struct {
+		ID                     int64  `json:"id"`
+		Name                   string `json:"name"`
+		NetworkConfigurationID string `json:"network_configuration_id"`
+	}
+	if err := ghclient.GetAllField(&client, fmt.Sprintf("orgs/%s/actions/runner-groups", org), "runner_groups", &groups); err != nil {
+		return nil, err
+	}
+	networking := &runnerNetworking{
+		groupNetworks: make(map[string]string),
+		groupNames:    make(map[int64]string),
+		hosted:        make(map[string]hostedRunner),
+	}
+	bound := false
+	for _, group := range groups {
+		networking.groupNames[group.ID] = group.Name
+		bound = bound || group.NetworkConfigurationID != ""
+	}
+
+	// Network configurations name the ID a group is bound to
+	if bound {
+		var configurations []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		}
+		err := ghclient.GetAllField(&client, fmt.Sprintf("orgs/%s/settings/network-configurations", org), "network_configurations", &configurations)
+		names := make(map[string]string)
+		if err == nil {
+			for _, configuration := range configurations {
+				names[configuration.ID] = configuration.Name
+			}
+		}
+		for _, group := range groups {
+			if group.NetworkConfigurationID == "" {
+				continue
+			}
+			name := names[group.NetworkConfigurationID]
+			if name == "" {
+				name = group.NetworkConfigurationID
+			}
+			networking.groupNetworks[strings.ToLower(group.Name)] = name
+		}
+	}
+
+	var runners []struct {
+		hostedRunner
+		PublicIPs []struct {
+			Prefix string `json:"prefix"`
+			Length int    `json:"length"`
+		} `json:"public_ips"`
+	}
+	if err := ghclient.GetAllField(&client, fmt.Sprintf("orgs/%s/actions/hosted-runners", org), "runners", &runners); err == nil {
+		for _, runner := range runners {
+			hosted := runner.hostedRunner
+			for _, ip := range runner.PublicIPs {
+				hosted.PublicIPs = append(hosted.PublicIPs, fmt.Sprintf("%s/%d", ip.Prefix, ip.Length))
+			}
+			networking.hosted[strings.ToLower(hosted.Name)] = hosted
+		}
+	}
+
+	runnerNetworkingByOrg[strings.ToLower(org)] = networking
+	return networking, nil
+}
+
+// networkBoundRunners returns the network-bound runner groups and the hosted runners with
+// static IPs among the runners of the workflows ("Self-hosted runner: labels (in ...)"). A job runs in
+// a group when runs-on names it, or when a label is the name of a hosted runner of the group.
+func networkBoundRunners(runners []string, networking *runnerNetworking) ([]string, []string) {
+	var bound, staticIP []string
+	seen := make(map[string]bool)
+	add := func(list *[]string, entry string) {
+		if !seen[entry] {
+			seen[entry] = true
+			*list = append(*list, entry)
+		}
+	}
+
+	for _, runner := range runners {
+		runner = strings.SplitN(runner, " (in ", 2)[0]
+		labels := strings.Split(strings.TrimPrefix(runner, "Self-hosted runner: "), ", ")
+		var groups []string
+		for _, label := range labels {
+			if group, ok := strings.CutPrefix(label, "group "); ok {
+				groups = append(groups, group)
+				continue
+			}
+			hosted, ok := networking.hosted[strings.ToLower(label)]
+			if !ok {
+				continue
+			}
+			if group := networking.groupNames[hosted.RunnerGroupID]; group != "" {
+				groups = append(groups, group)
+			}
+			if hosted.PublicIPEnabled {
+				entry := fmt.Sprintf("Hosted runner: %s", hosted.Name)
+				if len(hosted.PublicIPs) > 0 {
+					entry += fmt.Sprintf(" (static IPs: %s)", strings.Join(hosted.PublicIPs, ", "))
+				}
+				add(&staticIP, entry)
+			}
+		}
+		for _, group := range groups {
+			if network, ok := networking.groupNetworks[strings.ToLower(group)]; ok {
+				add(&bound, fmt.Sprintf("Runner group: %s (network configuration: %s)", group, network))
+			}
+		}
+	}
+	return bound, staticIP
+}
@@ -0,0 +1,49 @@
+package dependencies
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNetworkBoundRunners(t *testing.T) {
+	networking := &runnerNetworking{
+		groupNetworks: map[string]string{"private": "vnet-eastus"},
+		groupNames:    map[int64]string{1: "Default", 2: "Private"},
+		hosted: map[string]hostedRunner{
+			"deploy-runner": {Name: "deploy-runner", RunnerGroupID: 2, PublicIPEnabled: true, PublicIPs: []string{"20.1.2.0/28"}},
+			"build-runner":  {Name: "build-runner", RunnerGroupID: 1},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		runners  []string
+		bound    []string
+		staticIP []string
+	}{
+		{
+			name:    "group named in runs-on",
+			runners: []string{"Self-hosted runner: group Private, linux (in deploy.yml, job release)"},
+			bound:   []string{"Runner group: Private (network configuration: vnet-eastus)"},
+		},
+		{
+			name:     "hosted runner of a bound group",
+			runners:  []string{"Self-hosted runner: deploy-runner (in ci.yml, jobs build, test)", "Self-hosted runner: Deploy-Runner"},
+			bound:    []string{"Runner group: Private (network configuration: vnet-eastus)"},
+			staticIP: []string{"Hosted runner: deploy-runner (static IPs: 20.1.2.0/28)"},
+		},
+		{
+			name:    "runner without private networking",
+			runners: []string{"Self-hosted runner: build-runner", "Self-hosted runner: self-hosted, linux"},
+		},
+	}
+	for _, tt := range tests {
+		bound, staticIP := networkBoundRunners(tt.runners, networking)
+		if !reflect.DeepEqual(bound, tt.bound) {
+			t.Errorf("%s: bound = %v, want %v", tt.name, bound, tt.bound)
+		}
+		if !reflect.DeepEqual(staticIP, tt.staticIP) {
+			t.Errorf("%s: static IP = %v, want %v", tt.name, staticIP, tt.staticIP)
+		}
+	}
+}
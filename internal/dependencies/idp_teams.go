@@ -0,0 +1,59 @@
+package dependencies
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// IdP group sources of a team's membership
+const (
+	IdPSourceLDAP           = "ldap"            // GitHub Enterprise Server LDAP sync
+	IdPSourceTeamSync       = "team_sync"       // Team synchronization with Entra ID or Okta
+	IdPSourceExternalGroups = "external_groups" // Enterprise Managed Users SCIM groups
+)
+
+// TeamIdPGroups returns the IdP groups a team's membership is synced from and how it is synced.
+// A team without group mappings (or an org without team sync) returns no groups.
+func TeamIdPGroups(client api.RESTClient, org, slug, ldapDN string) ([]string, string) {
+	if ldapDN != "" {
+		return []string{ldapDN}, IdPSourceLDAP
+	}
+
+	var external struct {
+		Groups []struct {
+			GroupName string `json:"group_name"`
+		} `json:"groups"`
+	}
+	if err := client.Get(fmt.Sprintf("orgs/%s/teams/%s/external-groups", org, slug), &external); err == nil && len(external.Groups) > 0 {
+		var groups []string
+		for _, group := range external.Groups {
+			groups = append(groups, group.GroupName)
+		}
+		return groups, IdPSourceExternalGroups
+	}
+
+	var mappings struct {
+		Groups []struct {
+			GroupName string `json:"group_name"`
+		} `json:"groups"`
+	}
+	if err := client.Get(fmt.Sprintf("orgs/%s/teams/%s/team-sync/group-mappings", org, slug), &mappings); err == nil && len(mappings.Groups) > 0 {
+		var groups []string
+		for _, group := range mappings.Groups {
+			groups = append(groups, group.GroupName)
+		}
+		return groups, IdPSourceTeamSync
+	}
+
+	return nil, ""
+}
+
+// IdPIndicator formats the IdP groups of a team for report items, e.g. " [IdP-synced: eng; ops]"
+func IdPIndicator(groups []string) string {
+	if len(groups) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" [IdP-synced: %s]", strings.Join(groups, "; "))
+}
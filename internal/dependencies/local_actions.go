@@ -0,0 +1,133 @@
+package dependencies
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+	"gopkg.in/yaml.v3"
+)
+
+// actionManifest is an action.yml as far as the local action analysis reads it
+type actionManifest struct {
+	Runs struct {
+		Using string         `yaml:"using"` // composite, docker or node20
+		Image string         `yaml:"image"` // Dockerfile path or docker:// image of a Docker action
+		Steps []workflowStep `yaml:"steps"`
+	} `yaml:"runs"`
+}
+
+// actionManifestNames are the file names an action's metadata can have
+var actionManifestNames = []string{"action.yml", "action.yaml"}
+
+// localActionPath returns the directory of a local action reference (uses: ./path) relative to
+// the repository root. Local reusable workflows are analyzed as workflows and are skipped.
+func localActionPath(reference string) (string, bool) {
+	reference = strings.TrimSpace(reference)
+	if !strings.HasPrefix(reference, "./") {
+		return "", false
+	}
+	dir := path.Clean(strings.TrimPrefix(reference, "./"))
+	if dir == "." || strings.HasPrefix(dir, "../") || strings.HasSuffix(dir, ".yml") || strings.HasSuffix(dir, ".yaml") {
+		return "", false
+	}
+	return dir, true
+}
+
+// analyzeLocalActions analyzes the local actions workflows run, and the local actions those
+// run in turn: composite actions like workflow steps, Docker actions through their Dockerfile.
+// Findings name the action's metadata file, e.g. "NPM_TOKEN (in .github/actions/publish/action.yml)".
+func analyzeLocalActions(client api.RESTClient, owner, repo string, actions []string, deps *types.OrganizationalDependencies) {
+	visited := make(map[string]bool)
+	for len(actions) > 0 {
+		dir := actions[0]
+		actions = actions[1:]
+		if visited[dir] {
+			continue
+		}
+		visited[dir] = true
+
+		content, manifestPath, err := readActionManifest(client, owner, repo, dir)
+		if err != nil {
+			continue // Not in the repository, e.g. checked out from another one by the job
+		}
+		nested, dockerfile, err := analyzeActionContent(content, manifestPath, owner, deps)
+		if err != nil {
+			continue // Skip metadata that can't be parsed
+		}
+		if dockerfile != "" {
+			if err := analyzeDockerfile(client, owner, repo, dockerfile, deps); err != nil {
+				// Non-fatal - the Dockerfile might be generated
+			}
+		}
+		actions = append(actions, nested...)
+	}
+}
+
+// readActionManifest reads the metadata file of the action in dir
+func readActionManifest(client api.RESTClient, owner, repo, dir string) ([]byte, string, error) {
+	var lastErr error
+	for _, name := range actionManifestNames {
+		manifestPath := path.Join(dir, name)
+		decoded, err := readRepositoryFile(client, owner, repo, manifestPath)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		return decoded, manifestPath, nil
+	}
+	return nil, "", lastErr
+}
+
+// analyzeActionContent parses an action's metadata file. The steps of a composite action are
+// analyzed like the steps of a workflow job: the secrets and variables they reference, the
+// organization's actions they use and the dispatches they send. A Docker action's image is
+// checked for an organization registry. It returns the local actions the steps run and the
+// Dockerfile a Docker action is built from, relative to the repository root.
+func analyzeActionContent(content []byte, manifestPath, owner string, deps *types.OrganizationalDependencies) ([]string, string, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return nil, "", fmt.Errorf("failed to parse action %s: %v", manifestPath, err)
+	}
+	if len(document.Content) == 0 || document.Content[0].Kind != yaml.MappingNode {
+		return nil, "", fmt.Errorf("action %s is not a YAML mapping", manifestPath)
+	}
+	var manifest actionManifest
+	if err := document.Content[0].Decode(&manifest); err != nil {
+		return nil, "", fmt.Errorf("failed to parse action %s: %v", manifestPath, err)
+	}
+
+	switch strings.ToLower(manifest.Runs.Using) {
+	case "composite":
+		ci := &deps.ActionsCIDependencies
+		findings := &workflowFindings{workflow: manifestPath, jobs: make(map[workflowFinding][]string)}
+		// Input defaults and step expressions alike; findings name the action, not a job
+		addContextReferences(document.Content[0], "", ci, findings)
+		job := workflowJob{Steps: manifest.Runs.Steps}
+		addJobActions(job, "", owner, ci, findings)
+		addJobDispatches(job, "", owner, ci, findings)
+		addJobAttestations(job, "", ci, findings)
+		findings.write()
+
+		var nested []string
+		for _, step := range manifest.Runs.Steps {
+			if action, ok := localActionPath(step.Uses); ok && !containsString(nested, action) {
+				nested = append(nested, action)
+			}
+		}
+		return nested, "", nil
+	case "docker":
+		image := strings.TrimSpace(manifest.Runs.Image)
+		if strings.HasPrefix(image, "docker://") {
+			recordOrgRegistry(image, manifestPath, owner, deps)
+			return nil, "", nil
+		}
+		if image == "" {
+			return nil, "", nil
+		}
+		return nil, path.Join(path.Dir(manifestPath), image), nil
+	}
+	return nil, "", nil
+}
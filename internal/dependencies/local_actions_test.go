@@ -0,0 +1,109 @@
+package dependencies
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+func TestLocalActionPath(t *testing.T) {
+	tests := []struct {
+		reference string
+		want      string
+		ok        bool
+	}{
+		{"./.github/actions/setup", ".github/actions/setup", true},
+		{"./tools/lint/", "tools/lint", true},
+		{"./.github/workflows/build.yml", "", false},
+		{"./../outside", "", false},
+		{"acme/setup@v1", "", false},
+		{"docker://alpine:3", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := localActionPath(tt.reference)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("localActionPath(%q) = %q, %v, want %q, %v", tt.reference, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestAnalyzeActionContent(t *testing.T) {
+	tests := []struct {
+		name       string
+		action     string
+		nested     []string
+		dockerfile string
+		variables  []string
+		actions    []string
+		registries []string
+	}{
+		{
+			name: "composite action",
+			action: `
+name: publish
+inputs:
+  region:
+    default: ${{ vars.REGION }}
+runs:
+  using: composite
+  steps:
+    - uses: acme/sign-action@v1
+    - uses: ./.github/actions/login
+    - run: echo done
+      shell: bash
+`,
+			nested:    []string{".github/actions/login"},
+			variables: []string{"REGION (in .github/actions/publish/action.yml)"},
+			actions:   []string{"acme/sign-action (in .github/actions/publish/action.yml)"},
+		},
+		{
+			name: "docker action built from a Dockerfile",
+			action: `
+runs:
+  using: docker
+  image: Dockerfile
+`,
+			dockerfile: ".github/actions/publish/Dockerfile",
+		},
+		{
+			name: "docker action from an organization registry",
+			action: `
+runs:
+  using: docker
+  image: docker://ghcr.io/acme/publisher:2
+`,
+			registries: []string{`ghcr\.io/acme (in .github/actions/publish/action.yml)`},
+		},
+		{
+			name: "javascript action",
+			action: `
+runs:
+  using: node20
+  main: index.js
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deps := &types.OrganizationalDependencies{}
+			nested, dockerfile, err := analyzeActionContent([]byte(tt.action), ".github/actions/publish/action.yml", "acme", deps)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(nested, tt.nested) || dockerfile != tt.dockerfile {
+				t.Errorf("analyzeActionContent() = %v, %q, want %v, %q", nested, dockerfile, tt.nested, tt.dockerfile)
+			}
+			if got := deps.ActionsCIDependencies.OrganizationVariables; !reflect.DeepEqual(got, tt.variables) {
+				t.Errorf("OrganizationVariables = %v, want %v", got, tt.variables)
+			}
+			if got := deps.ActionsCIDependencies.OrgSpecificActions; !reflect.DeepEqual(got, tt.actions) {
+				t.Errorf("OrgSpecificActions = %v, want %v", got, tt.actions)
+			}
+			if got := deps.CodeDependencies.OrgSpecificContainerRegistries; !reflect.DeepEqual(got, tt.registries) {
+				t.Errorf("OrgSpecificContainerRegistries = %v, want %v", got, tt.registries)
+			}
+		})
+	}
+}
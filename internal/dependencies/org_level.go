@@ -2,9 +2,11 @@ package dependencies
 
 import (
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/jefeish/gh-repo-transfer/internal/ghclient"
 	"github.com/jefeish/gh-repo-transfer/internal/types"
 )
 
@@ -31,7 +33,7 @@ func AnalyzeAppsIntegrationsOrgLevel(client api.RESTClient, owner string, apps *
 		} `json:"installations"`
 	}
 
-	err := client.Get(fmt.Sprintf("orgs/%s/installations", owner), &response)
+	err := ghclient.GetAllField(&client, fmt.Sprintf("orgs/%s/installations", owner), "installations", &response.Installations)
 	if err != nil {
 		return fmt.Errorf("failed to get organization app installations: %v", err)
 	}
@@ -67,6 +69,14 @@ func AnalyzeOrgGovernanceOrgLevel(client api.RESTClient, owner string, governanc
 		return fmt.Errorf("could not analyze organization templates: %v", err)
 	}
 
+	// Analyze org webhooks and audit log streams; not fatal, hooks need admin:org_hook
+	if err := analyzeEventSinks(client, owner, governance); err != nil && checkVerbose() {
+		fmt.Fprintf(os.Stderr, "Could not analyze event sinks: %v\n", err)
+	}
+
+	// Analyze enterprise policies overriding organization settings (--enterprise)
+	analyzeEnterprisePolicies(client, governance)
+
 	// Separate policies for JSON output
 	separatePoliciesForJSONOrgLevel(governance)
 
@@ -200,7 +210,7 @@ func analyzeOrgRepositoryRulesets(client api.RESTClient, owner string, governanc
 		} `json:"rules"`
 	}
 	
-	err := client.Get(fmt.Sprintf("orgs/%s/rulesets", owner), &rulesets)
+	err := ghclient.GetAll(&client, fmt.Sprintf("orgs/%s/rulesets", owner), &rulesets)
 	if err != nil {
 		return nil // Non-fatal - rulesets might not be accessible
 	}
@@ -0,0 +1,91 @@
+package dependencies
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+// PagesSite is the GitHub Pages configuration of a repository
+type PagesSite struct {
+	URL           string
+	BuildType     string // legacy (built from a branch) or workflow
+	Branch        string
+	Path          string
+	CNAME         string // Custom domain, empty for none
+	HTTPSEnforced bool
+}
+
+// ReadPages reads the GitHub Pages configuration of a repository; it returns nil when the
+// repository has no Pages site
+func ReadPages(client api.RESTClient, owner, repo string) (*PagesSite, error) {
+	var pages struct {
+		HTMLURL   string  `json:"html_url"`
+		BuildType string  `json:"build_type"`
+		CNAME     *string `json:"cname"`
+		Source    *struct {
+			Branch string `json:"branch"`
+			Path   string `json:"path"`
+		} `json:"source"`
+		HTTPSEnforced bool `json:"https_enforced"`
+	}
+	if err := client.Get(fmt.Sprintf("repos/%s/%s/pages", owner, repo), &pages); err != nil {
+		if strings.Contains(err.Error(), "404") {
+			return nil, nil // Pages is not enabled
+		}
+		return nil, fmt.Errorf("failed to read the Pages configuration: %v", err)
+	}
+
+	site := &PagesSite{URL: pages.HTMLURL, BuildType: pages.BuildType, HTTPSEnforced: pages.HTTPSEnforced}
+	if site.BuildType == "" {
+		site.BuildType = "legacy"
+	}
+	if pages.CNAME != nil {
+		site.CNAME = *pages.CNAME
+	}
+	if pages.Source != nil {
+		site.Branch, site.Path = pages.Source.Branch, pages.Source.Path
+	}
+	return site, nil
+}
+
+// AnalyzePages records the repository's GitHub Pages site: its build source, custom domain and
+// HTTPS enforcement. Its URL changes with the owner unless it has a custom domain, and an
+// organization site (<owner>.github.io) stops being one.
+func AnalyzePages(client api.RESTClient, owner, repo string, deps *types.OrganizationalDependencies) error {
+	site, err := ReadPages(client, owner, repo)
+	if err != nil || site == nil {
+		return err
+	}
+	deps.AppsIntegrations.Pages = append(deps.AppsIntegrations.Pages, FormatPages(owner, repo, site))
+	return nil
+}
+
+// FormatPages describes a Pages site, e.g. "GitHub Pages: https://acme.github.io/docs/ (source:
+// branch main /docs, custom domain: docs.acme.com, HTTPS enforced)"
+func FormatPages(owner, repo string, site *PagesSite) string {
+	details := []string{"source: workflow"}
+	if site.BuildType != "workflow" {
+		details[0] = fmt.Sprintf("source: branch %s %s", site.Branch, site.Path)
+	}
+	if site.CNAME != "" {
+		details = append(details, "custom domain: "+site.CNAME)
+	}
+	if IsOrganizationSite(owner, repo) {
+		details = append(details, "organization site")
+	}
+	if site.HTTPSEnforced {
+		details = append(details, "HTTPS enforced")
+	} else {
+		details = append(details, "HTTPS not enforced")
+	}
+	return fmt.Sprintf("GitHub Pages: %s (%s)", site.URL, strings.Join(details, ", "))
+}
+
+// IsOrganizationSite reports whether a repository is its owner's Pages site, <owner>.github.io
+func IsOrganizationSite(owner, repo string) bool {
+	return strings.EqualFold(repo, owner+".github.io")
+}
@@ -0,0 +1,26 @@
+package dependencies
+
+import "testing"
+
+func TestFormatPages(t *testing.T) {
+	tests := []struct {
+		name string
+		repo string
+		site PagesSite
+		want string
+	}{
+		{"branch source", "web", PagesSite{URL: "https://acme.github.io/web/", BuildType: "legacy", Branch: "gh-pages", Path: "/", HTTPSEnforced: true},
+			"GitHub Pages: https://acme.github.io/web/ (source: branch gh-pages /, HTTPS enforced)"},
+		{"custom domain", "docs", PagesSite{URL: "https://docs.acme.com/", BuildType: "workflow", CNAME: "docs.acme.com"},
+			"GitHub Pages: https://docs.acme.com/ (source: workflow, custom domain: docs.acme.com, HTTPS not enforced)"},
+		{"organization site", "Acme.github.io", PagesSite{URL: "https://acme.github.io/", BuildType: "workflow", HTTPSEnforced: true},
+			"GitHub Pages: https://acme.github.io/ (source: workflow, organization site, HTTPS enforced)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatPages("acme", tt.repo, &tt.site); got != tt.want {
+				t.Errorf("FormatPages() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
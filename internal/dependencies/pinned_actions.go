@@ -0,0 +1,27 @@
+package dependencies
+
+import (
+	"github.com/cli/go-gh/v2/pkg/api"
+
+	"github.com/jefeish/gh-repo-transfer/internal/impact"
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+// analyzePinnedActionConsumers records workflows in the organization that use the repository's
+// actions or reusable workflows pinned to a commit SHA. Only repositories publishing actions
+// are searched, which keeps code search (rate limited) out of most analyses.
+func analyzePinnedActionConsumers(client api.RESTClient, owner, repo string, deps *types.OrganizationalDependencies) error {
+	publishes, err := impact.PublishesActions(client, owner, repo)
+	if err != nil || !publishes {
+		return err
+	}
+
+	uses, err := impact.FindPinnedUses(client, owner, repo)
+	if err != nil {
+		return err
+	}
+	for _, use := range uses {
+		deps.ActionsCIDependencies.PinnedActionConsumers = append(deps.ActionsCIDependencies.PinnedActionConsumers, use.String())
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package dependencies
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+
+	"github.com/jefeish/gh-repo-transfer/internal/ghclient"
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+// RulesetRule is a rule of a ruleset with its raw parameters
+type RulesetRule struct {
+	Type       string          `json:"type"`
+	Parameters json.RawMessage `json:"parameters"`
+}
+
+// AnalyzePushRules records the active push rulesets applying to the repository, its own and
+// those inherited from the organization, with their file restrictions
+func AnalyzePushRules(client api.RESTClient, owner, repo string, deps *types.OrganizationalDependencies) error {
+	var rulesets []struct {
+		ID          int    `json:"id"`
+		Name        string `json:"name"`
+		Target      string `json:"target"`
+		Enforcement string `json:"enforcement"`
+		SourceType  string `json:"source_type"`
+	}
+	if err := ghclient.GetAll(&client, fmt.Sprintf("repos/%s/%s/rulesets?includes_parents=true", owner, repo), &rulesets); err != nil {
+		return err
+	}
+
+	for _, ruleset := range rulesets {
+		if ruleset.Target != "push" || ruleset.Enforcement != "active" {
+			continue
+		}
+		var detailed struct {
+			Rules []RulesetRule `json:"rules"`
+		}
+		if err := client.Get(fmt.Sprintf("repos/%s/%s/rulesets/%d", owner, repo, ruleset.ID), &detailed); err != nil {
+			continue
+		}
+		pushRuleset := ParsePushRuleset(ruleset.Name, strings.ToLower(ruleset.SourceType), detailed.Rules)
+		deps.OrgGovernance.PushRulesets = append(deps.OrgGovernance.PushRulesets, pushRuleset)
+	}
+	return nil
+}
+
+// ParsePushRuleset reads the file restrictions of a push ruleset's rules
+func ParsePushRuleset(name, source string, rules []RulesetRule) types.PushRuleset {
+	ruleset := types.PushRuleset{Name: name, Source: source}
+	for _, rule := range rules {
+		var params struct {
+			MaxFileSize              int      `json:"max_file_size"`
+			MaxFilePathLength        int      `json:"max_file_path_length"`
+			RestrictedFilePaths      []string `json:"restricted_file_paths"`
+			RestrictedFileExtensions []string `json:"restricted_file_extensions"`
+		}
+		if len(rule.Parameters) > 0 {
+			if err := json.Unmarshal(rule.Parameters, &params); err != nil {
+				continue
+			}
+		}
+		switch rule.Type {
+		case "max_file_size":
+			ruleset.MaxFileSizeMB = params.MaxFileSize
+		case "max_file_path_length":
+			ruleset.MaxFilePathLength = params.MaxFilePathLength
+		case "file_path_restriction":
+			ruleset.RestrictedPaths = append(ruleset.RestrictedPaths, params.RestrictedFilePaths...)
+		case "file_extension_restriction":
+			ruleset.RestrictedExtensions = append(ruleset.RestrictedExtensions, params.RestrictedFileExtensions...)
+		}
+	}
+	return ruleset
+}
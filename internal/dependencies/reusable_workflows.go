@@ -0,0 +1,157 @@
+package dependencies
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+	"gopkg.in/yaml.v3"
+)
+
+// inheritedCall is a job calling a reusable workflow of the organization with secrets: inherit
+type inheritedCall struct {
+	caller   string // Workflow file of the calling job
+	job      string
+	workflow string // owner/repo/path of the reusable workflow
+	ref      string // Empty for the default branch
+}
+
+// workflowRef returns the ref of a uses: reference, "" when it has none
+func workflowRef(reference string) string {
+	if parts := strings.SplitN(strings.TrimSpace(reference), "@", 2); len(parts) == 2 {
+		return parts[1]
+	}
+	return ""
+}
+
+// analyzeInheritedSecrets resolves the reusable workflows of the organization that jobs call
+// with secrets: inherit, which hides what they use, and records the secrets each one needs as
+// secrets of the calling job, e.g. "NPM_TOKEN (in release.yml, job publish, inherited by
+// acme/shared/.github/workflows/publish.yml)". Reusable workflows calling further ones with
+// secrets: inherit are followed.
+func analyzeInheritedSecrets(client api.RESTClient, owner string, calls []inheritedCall, deps *types.OrganizationalDependencies) {
+	ci := &deps.ActionsCIDependencies
+	resolved := make(map[string][]string)
+	for _, call := range calls {
+		for _, name := range inheritedSecretNames(client, owner, call.workflow, call.ref, resolved) {
+			item := fmt.Sprintf("%s (in %s, job %s, inherited by %s)", name, call.caller, call.job, call.workflow)
+			if !containsString(ci.OrganizationSecrets, item) {
+				ci.OrganizationSecrets = append(ci.OrganizationSecrets, item)
+			}
+		}
+	}
+}
+
+// inheritedSecretNames returns the secrets a reusable workflow needs, including those of the
+// reusable workflows it calls with secrets: inherit. resolved caches the names per workflow and
+// ref; a workflow that can't be read needs none.
+func inheritedSecretNames(client api.RESTClient, owner, workflow, ref string, resolved map[string][]string) []string {
+	key := workflow + "@" + ref
+	if names, ok := resolved[key]; ok {
+		return names
+	}
+	resolved[key] = nil // Ends cycles of workflows calling each other
+
+	content, err := readReusableWorkflow(client, workflow, ref)
+	if err != nil {
+		return nil
+	}
+	names, nested, err := reusableWorkflowSecrets(content, workflow, owner)
+	if err != nil {
+		return nil
+	}
+	for _, call := range nested {
+		names = appendSecretNames(names, inheritedSecretNames(client, owner, call.workflow, call.ref, resolved)...)
+	}
+	resolved[key] = names
+	return names
+}
+
+// readReusableWorkflow reads a reusable workflow ("owner/repo/path") at ref
+func readReusableWorkflow(client api.RESTClient, workflow, ref string) ([]byte, error) {
+	parts := strings.SplitN(workflow, "/", 3)
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("'%s' is not a reusable workflow", workflow)
+	}
+	endpoint := fmt.Sprintf("repos/%s/%s/contents/%s", parts[0], parts[1], parts[2])
+	if ref != "" {
+		endpoint += "?ref=" + url.QueryEscape(ref)
+	}
+	var content struct {
+		Content string `json:"content"`
+	}
+	if err := client.Get(endpoint, &content); err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(content.Content)
+}
+
+// reusableWorkflowSecrets parses a reusable workflow and returns the secrets it needs, those
+// declared under on.workflow_call.secrets and those its expressions reference, and its own
+// calls of the organization's reusable workflows with secrets: inherit
+func reusableWorkflowSecrets(content []byte, workflow, owner string) ([]string, []inheritedCall, error) {
+	root, err := parseWorkflow(content, workflow)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var names []string
+	if declared := mappingValue(mappingValue(mappingValue(root, "on"), "workflow_call"), "secrets"); declared != nil && declared.Kind == yaml.MappingNode {
+		for i := 0; i < len(declared.Content); i += 2 {
+			names = appendSecretNames(names, declared.Content[i].Value)
+		}
+	}
+	walkContextReferences(root, func(context, name string) {
+		if context == "secrets" && !builtinSecrets[strings.ToUpper(name)] {
+			names = appendSecretNames(names, name)
+		}
+	})
+
+	var nested []inheritedCall
+	if jobs := mappingValue(root, "jobs"); jobs != nil && jobs.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(jobs.Content); i += 2 {
+			var job workflowJob
+			if err := jobs.Content[i+1].Decode(&job); err != nil || !job.inheritsSecrets() {
+				continue
+			}
+			if called, ok := organizationAction(job.Uses, owner); ok {
+				nested = append(nested, inheritedCall{caller: workflow, job: jobs.Content[i].Value, workflow: called, ref: workflowRef(job.Uses)})
+			}
+		}
+	}
+	return names, nested, nil
+}
+
+// mappingValue returns the value of key in a mapping node, nil when node is no mapping or
+// has no such key
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// appendSecretNames appends the names not yet in names; secret names are case-insensitive
+func appendSecretNames(names []string, additions ...string) []string {
+	for _, addition := range additions {
+		found := false
+		for _, name := range names {
+			if strings.EqualFold(name, addition) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			names = append(names, addition)
+		}
+	}
+	return names
+}
@@ -0,0 +1,63 @@
+package dependencies
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReusableWorkflowSecrets(t *testing.T) {
+	workflow := `
+on:
+  workflow_call:
+    inputs:
+      environment:
+        type: string
+    secrets:
+      NPM_TOKEN:
+        required: true
+      SLACK_WEBHOOK:
+        required: false
+jobs:
+  publish:
+    runs-on: ubuntu-latest
+    steps:
+      # secrets.IN_A_COMMENT is not used
+      - run: npm publish
+        env:
+          NODE_AUTH_TOKEN: ${{ secrets.npm_token }}
+          SIGNING_KEY: ${{ secrets.SIGNING_KEY }}
+          TOKEN: ${{ secrets.GITHUB_TOKEN }}
+  notify:
+    uses: acme/shared/.github/workflows/notify.yml@v2
+    secrets: inherit
+  scan:
+    uses: vendor/scanner/.github/workflows/scan.yml@v1
+    secrets: inherit
+`
+	names, nested, err := reusableWorkflowSecrets([]byte(workflow), "acme/shared/.github/workflows/publish.yml", "acme")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"NPM_TOKEN", "SLACK_WEBHOOK", "SIGNING_KEY"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("secrets = %v, want %v", names, want)
+	}
+	want := []inheritedCall{{caller: "acme/shared/.github/workflows/publish.yml", job: "notify", workflow: "acme/shared/.github/workflows/notify.yml", ref: "v2"}}
+	if !reflect.DeepEqual(nested, want) {
+		t.Errorf("nested calls = %+v, want %+v", nested, want)
+	}
+}
+
+func TestWorkflowRef(t *testing.T) {
+	tests := []struct {
+		reference, want string
+	}{
+		{"acme/shared/.github/workflows/build.yml@v1", "v1"},
+		{"acme/shared/.github/workflows/build.yml@refs/heads/main", "refs/heads/main"},
+		{"acme/shared/.github/workflows/build.yml", ""},
+	}
+	for _, tt := range tests {
+		if got := workflowRef(tt.reference); got != tt.want {
+			t.Errorf("workflowRef(%q) = %q, want %q", tt.reference, got, tt.want)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/jefeish/gh-repo-transfer/internal/ghclient"
 	"github.com/jefeish/gh-repo-transfer/internal/types"
 )
 
@@ -34,7 +35,7 @@ func analyzeSecurityCampaigns(client api.RESTClient, owner, repo string, deps *t
 	}
 
 	// Try to get security campaigns (this endpoint might not exist or be accessible)
-	err := client.Get(fmt.Sprintf("orgs/%s/security/campaigns", owner), &campaigns)
+	err := ghclient.GetAll(&client, fmt.Sprintf("orgs/%s/security/campaigns", owner), &campaigns)
 	if err != nil {
 		return err // Security campaigns not accessible or not available
 	}
@@ -0,0 +1,133 @@
+package dependencies
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// subdirectory is the part of one repository the content analyzers are scoped to, for a
+// repository planned to be extracted from it (see --path)
+var subdirectory struct {
+	repository string // owner/repo
+	dir        string // e.g. "services/foo", without leading or trailing slash
+}
+
+// SetSubdirectory scopes the analyzers reading file contents of repository ("owner/repo") to
+// dir: package files, Dockerfiles, the README and docs/ are read from dir, submodules and
+// CODEOWNERS rules outside it are left out, and only the workflows of dir/.github/workflows and
+// the root workflows referring to dir are analyzed. Everything that is not file content, such
+// as teams, secrets and environments, is still analyzed for the whole repository.
+func SetSubdirectory(repository, dir string) error {
+	cleaned := strings.Trim(path.Clean("/"+strings.TrimSpace(dir)), "/")
+	if cleaned == "" {
+		return fmt.Errorf("--path %q does not name a subdirectory", dir)
+	}
+	subdirectory.repository, subdirectory.dir = repository, cleaned
+	return nil
+}
+
+// Subdirectory returns the subdirectory the analysis of a repository is scoped to, "" for the
+// whole repository
+func Subdirectory(owner, repo string) string {
+	if subdirectory.dir == "" || !strings.EqualFold(subdirectory.repository, owner+"/"+repo) {
+		return ""
+	}
+	return subdirectory.dir
+}
+
+// scopedPath returns the path of a file relative to the subdirectory a repository's analysis is
+// scoped to, or the path itself without one
+func scopedPath(owner, repo, filePath string) string {
+	if dir := Subdirectory(owner, repo); dir != "" {
+		return path.Join(dir, filePath)
+	}
+	return filePath
+}
+
+// inSubdirectory reports whether a repository path lies in dir
+func inSubdirectory(dir, filePath string) bool {
+	filePath = strings.Trim(path.Clean("/"+filePath), "/")
+	return filePath == dir || strings.HasPrefix(filePath, dir+"/")
+}
+
+// refersToSubdirectory reports whether a root workflow refers to dir, e.g. in its path filters
+// or a working-directory, and so belongs to the extracted repository as well
+func refersToSubdirectory(content []byte, dir string) bool {
+	for _, line := range strings.Split(string(content), "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		if index := strings.Index(line, dir); index >= 0 {
+			// "services/foo" must not match "services/foobar"
+			rest := line[index+len(dir):]
+			if rest == "" || strings.ContainsAny(rest[:1], "/'\" *\r") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// scopeCODEOWNERS keeps the comments and the rules of a CODEOWNERS file that can cover files in
+// dir; rules anchored to other directories are dropped
+func scopeCODEOWNERS(content, dir string) string {
+	var kept []string
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") || codeownersRuleCovers(fields[0], dir) {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
+
+// codeownersRuleCovers reports whether a CODEOWNERS pattern can match files in dir. Patterns
+// without a slash except at the end (e.g. "*.js", "docs/") match at any depth; others are
+// relative to the root and are compared with dir segment by segment.
+func codeownersRuleCovers(pattern, dir string) bool {
+	trimmed := strings.Trim(pattern, "/")
+	if trimmed == "" || trimmed == "*" || trimmed == "**" {
+		return true
+	}
+	if !strings.HasPrefix(pattern, "/") && !strings.Contains(trimmed, "/") {
+		return true
+	}
+
+	patternSegments := strings.Split(trimmed, "/")
+	dirSegments := strings.Split(dir, "/")
+	for i := 0; i < len(patternSegments) && i < len(dirSegments); i++ {
+		if patternSegments[i] == "**" {
+			return true
+		}
+		if matched, err := path.Match(patternSegments[i], dirSegments[i]); err != nil || !matched {
+			return false
+		}
+	}
+	// The pattern names dir, a directory above it, or files inside it
+	return true
+}
+
+// submodulesIn keeps the .gitmodules lines of the submodules whose path lies in dir
+func submodulesIn(lines []string, dir string) []string {
+	var kept, section []string
+	keep := false
+	flush := func() {
+		if keep {
+			kept = append(kept, section...)
+		}
+		section, keep = nil, false
+	}
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") {
+			flush()
+		}
+		if key, value, ok := strings.Cut(trimmed, "="); ok && strings.TrimSpace(key) == "path" {
+			keep = inSubdirectory(dir, strings.TrimSpace(value))
+		}
+		section = append(section, line)
+	}
+	flush()
+	return kept
+}
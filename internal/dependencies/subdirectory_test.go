@@ -0,0 +1,102 @@
+package dependencies
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSetSubdirectory(t *testing.T) {
+	defer func() { subdirectory.repository, subdirectory.dir = "", "" }()
+
+	if err := SetSubdirectory("acme/platform", "./services/foo/"); err != nil {
+		t.Fatalf("SetSubdirectory() error = %v", err)
+	}
+	if got := Subdirectory("Acme", "Platform"); got != "services/foo" {
+		t.Errorf("Subdirectory() = %q, want services/foo", got)
+	}
+	if got := Subdirectory("acme", "other"); got != "" {
+		t.Errorf("Subdirectory() of another repository = %q", got)
+	}
+	if got := scopedPath("acme", "platform", "package.json"); got != "services/foo/package.json" {
+		t.Errorf("scopedPath() = %q", got)
+	}
+	if err := SetSubdirectory("acme/platform", "/"); err == nil {
+		t.Errorf("SetSubdirectory() accepted the repository root")
+	}
+}
+
+func TestCodeownersRuleCovers(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    bool
+	}{
+		{"*", true},
+		{"*.js", true},
+		{"docs/", true},
+		{"/services/", true},
+		{"/services/foo/", true},
+		{"services/foo/src/", true},
+		{"/services/*/", true},
+		{"/services/**/", true},
+		{"/services/foobar/", false},
+		{"/services/bar/", false},
+		{"/web/", false},
+		{"web/app/", false},
+	}
+	for _, tt := range tests {
+		if got := codeownersRuleCovers(tt.pattern, "services/foo"); got != tt.want {
+			t.Errorf("codeownersRuleCovers(%q) = %v, want %v", tt.pattern, got, tt.want)
+		}
+	}
+}
+
+func TestScopeCODEOWNERS(t *testing.T) {
+	content := "# Owners\n* @acme/platform\n/web/ @acme/frontend\n/services/foo/ @acme/foo-team\n"
+	got := parseCODEOWNERS(scopeCODEOWNERS(content, "services/foo"), "acme")
+	for _, owner := range got {
+		if strings.Contains(owner, "frontend") {
+			t.Errorf("scoped CODEOWNERS kept %q", owner)
+		}
+	}
+	if len(got) == 0 {
+		t.Errorf("scoped CODEOWNERS kept no owners")
+	}
+}
+
+func TestRefersToSubdirectory(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    bool
+	}{
+		{"paths filter", "on:\n  push:\n    paths:\n      - 'services/foo/**'\n", true},
+		{"working directory", "    working-directory: services/foo\n", true},
+		{"similar directory", "      - 'services/foobar/**'\n", false},
+		{"comment", "# services/foo is built elsewhere\n", false},
+	}
+	for _, tt := range tests {
+		if got := refersToSubdirectory([]byte(tt.content), "services/foo"); got != tt.want {
+			t.Errorf("%s: refersToSubdirectory() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSubmodulesIn(t *testing.T) {
+	gitmodules := `[submodule "shared"]
+	path = shared/proto
+	url = https://github.com/acme/proto
+[submodule "vendor"]
+	path = services/foo/vendor/lib
+	url = https://github.com/acme/lib
+`
+	var urls []string
+	for _, line := range submodulesIn(strings.Split(gitmodules, "\n"), "services/foo") {
+		if key, value, ok := strings.Cut(strings.TrimSpace(line), "="); ok && strings.TrimSpace(key) == "url" {
+			urls = append(urls, strings.TrimSpace(value))
+		}
+	}
+	if want := []string{"https://github.com/acme/lib"}; !reflect.DeepEqual(urls, want) {
+		t.Errorf("submodulesIn() urls = %v, want %v", urls, want)
+	}
+}
@@ -0,0 +1,190 @@
+package dependencies
+
+import (
+	"encoding/base64"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// contentConcurrency is how many blobs are fetched at once, across all repositories analyzed
+// concurrently
+const contentConcurrency = 8
+
+// contentSlots limits the blob requests in flight to contentConcurrency
+var contentSlots = make(chan struct{}, contentConcurrency)
+
+// repositoryTree is a snapshot of a repository's default branch from the Git Trees API. The
+// files the content analyzers read are fetched up front; other files are fetched on demand.
+type repositoryTree struct {
+	blobs map[string]string         // Path of each file -> blob SHA
+	dirs  map[string][]contentEntry // Directory ("" for the root) -> its entries
+
+	mutex    sync.Mutex
+	contents map[string][]byte // Blob SHA -> content
+}
+
+// gitTreeEntry is an entry of a recursive Git tree listing
+type gitTreeEntry struct {
+	Path string `json:"path"`
+	Type string `json:"type"` // blob, tree or commit (submodule)
+	SHA  string `json:"sha"`
+}
+
+// repositoryTrees holds the snapshots of the repositories being analyzed
+var repositoryTrees = struct {
+	sync.Mutex
+	byRepository map[string]*repositoryTree
+}{byRepository: make(map[string]*repositoryTree)}
+
+// PrefetchContents lists the default branch of a repository with one recursive Git tree
+// request and fetches the files the content analyzers read (package files, Dockerfiles,
+// workflows, action manifests, READMEs, docs/ and CODEOWNERS) concurrently as blobs, so they
+// are not read one contents request at a time. Until ReleaseContents, the analyzers read the
+// repository from this snapshot. When the tree is too large to list at once, the error says so
+// and the analyzers keep using the contents API.
+func PrefetchContents(client api.RESTClient, owner, repo string) error {
+	if _, ok := localClonePath(owner, repo, ""); ok {
+		return nil // Read from the local clone
+	}
+
+	var listing struct {
+		Tree      []gitTreeEntry `json:"tree"`
+		Truncated bool           `json:"truncated"`
+	}
+	if err := client.Get(fmt.Sprintf("repos/%s/%s/git/trees/HEAD?recursive=1", owner, repo), &listing); err != nil {
+		return err
+	}
+	if listing.Truncated {
+		return fmt.Errorf("the tree of %s/%s is too large to list at once, reading files one by one", owner, repo)
+	}
+
+	tree := newRepositoryTree(listing.Tree)
+	var wanted []string
+	for filePath, sha := range tree.blobs {
+		if prefetchedContent(filePath) {
+			wanted = append(wanted, sha)
+		}
+	}
+	var wg sync.WaitGroup
+	for _, sha := range wanted {
+		wg.Add(1)
+		contentSlots <- struct{}{}
+		go func(sha string) {
+			defer wg.Done()
+			defer func() { <-contentSlots }()
+			tree.blob(client, owner, repo, sha) // Failures are retried when the file is read
+		}(sha)
+	}
+	wg.Wait()
+
+	repositoryTrees.Lock()
+	repositoryTrees.byRepository[strings.ToLower(owner+"/"+repo)] = tree
+	repositoryTrees.Unlock()
+	return nil
+}
+
+// ReleaseContents drops the snapshot PrefetchContents took of a repository
+func ReleaseContents(owner, repo string) {
+	repositoryTrees.Lock()
+	delete(repositoryTrees.byRepository, strings.ToLower(owner+"/"+repo))
+	repositoryTrees.Unlock()
+}
+
+// treeOf returns the snapshot of a repository, nil when it has none
+func treeOf(owner, repo string) *repositoryTree {
+	repositoryTrees.Lock()
+	defer repositoryTrees.Unlock()
+	return repositoryTrees.byRepository[strings.ToLower(owner+"/"+repo)]
+}
+
+// newRepositoryTree indexes the entries of a recursive tree listing by path and directory
+func newRepositoryTree(entries []gitTreeEntry) *repositoryTree {
+	tree := &repositoryTree{
+		blobs:    make(map[string]string),
+		dirs:     map[string][]contentEntry{"": nil},
+		contents: make(map[string][]byte),
+	}
+	for _, entry := range entries {
+		entryType := "file"
+		switch entry.Type {
+		case "blob":
+			tree.blobs[entry.Path] = entry.SHA
+		case "tree":
+			entryType = "dir"
+			if _, ok := tree.dirs[entry.Path]; !ok {
+				tree.dirs[entry.Path] = nil
+			}
+		case "commit":
+			entryType = "submodule"
+		}
+		dir := path.Dir(entry.Path)
+		if dir == "." {
+			dir = ""
+		}
+		tree.dirs[dir] = append(tree.dirs[dir], contentEntry{Name: path.Base(entry.Path), Type: entryType, Path: entry.Path})
+	}
+	return tree
+}
+
+// prefetchedContent reports whether PrefetchContents fetches a file up front
+func prefetchedContent(filePath string) bool {
+	dir, name := path.Dir(filePath), strings.ToLower(path.Base(filePath))
+	switch {
+	case filePath == ".gitmodules", containsString(packageFiles, filePath), containsString(dockerFiles, filePath), containsString(CodeownersLocations, filePath):
+		return true
+	case dir == ".github/workflows":
+		return strings.HasSuffix(name, ".yml") || strings.HasSuffix(name, ".yaml")
+	case containsString(actionManifestNames, name):
+		return true
+	case strings.HasPrefix(name, "readme") && (dir == "." || dir == ".github" || dir == "docs"):
+		return true
+	}
+	return dir == "docs" && strings.HasSuffix(name, ".md")
+}
+
+// read returns the content of a file, fetching its blob when it was not prefetched
+func (t *repositoryTree) read(client api.RESTClient, owner, repo, filePath string) ([]byte, error) {
+	sha, ok := t.blobs[strings.Trim(path.Clean("/"+filePath), "/")]
+	if !ok {
+		return nil, fmt.Errorf("%s not found in %s/%s", filePath, owner, repo)
+	}
+	return t.blob(client, owner, repo, sha)
+}
+
+// list returns the entries of a directory
+func (t *repositoryTree) list(owner, repo, dir string) ([]contentEntry, error) {
+	entries, ok := t.dirs[strings.Trim(path.Clean("/"+dir), "/")]
+	if !ok {
+		return nil, fmt.Errorf("%s not found in %s/%s", dir, owner, repo)
+	}
+	return entries, nil
+}
+
+// blob returns the content of a blob, fetching it once
+func (t *repositoryTree) blob(client api.RESTClient, owner, repo, sha string) ([]byte, error) {
+	t.mutex.Lock()
+	content, ok := t.contents[sha]
+	t.mutex.Unlock()
+	if ok {
+		return content, nil
+	}
+
+	var blob struct {
+		Content string `json:"content"`
+	}
+	if err := client.Get(fmt.Sprintf("repos/%s/%s/git/blobs/%s", owner, repo, sha), &blob); err != nil {
+		return nil, err
+	}
+	content, err := base64.StdEncoding.DecodeString(blob.Content)
+	if err != nil {
+		return nil, err
+	}
+	t.mutex.Lock()
+	t.contents[sha] = content
+	t.mutex.Unlock()
+	return content, nil
+}
@@ -0,0 +1,71 @@
+package dependencies
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+func TestPrefetchedContent(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"package.json", true},
+		{"services/api/package.json", false},
+		{"Dockerfile", true},
+		{".gitmodules", true},
+		{".github/CODEOWNERS", true},
+		{".github/workflows/ci.yml", true},
+		{".github/workflows/scripts/build.sh", false},
+		{".github/actions/publish/action.yml", true},
+		{"README.md", true},
+		{"docs/readme.rst", true},
+		{"services/api/README.md", false},
+		{"docs/guide.md", true},
+		{"docs/images/logo.png", false},
+		{"src/main.go", false},
+	}
+	for _, tt := range tests {
+		if got := prefetchedContent(tt.path); got != tt.want {
+			t.Errorf("prefetchedContent(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestRepositoryTree(t *testing.T) {
+	tree := newRepositoryTree([]gitTreeEntry{
+		{Path: "README.md", Type: "blob", SHA: "r1"},
+		{Path: ".github", Type: "tree", SHA: "t1"},
+		{Path: ".github/workflows", Type: "tree", SHA: "t2"},
+		{Path: ".github/workflows/ci.yml", Type: "blob", SHA: "w1"},
+		{Path: "vendor/lib", Type: "commit", SHA: "c1"},
+	})
+	tree.contents["w1"] = []byte("on: push")
+	var client api.RESTClient // Unused: the blob is cached
+
+	entries, err := tree.list("acme", "web", "")
+	want := []contentEntry{
+		{Name: "README.md", Type: "file", Path: "README.md"},
+		{Name: ".github", Type: "dir", Path: ".github"},
+	}
+	if err != nil || !reflect.DeepEqual(entries, want) {
+		t.Errorf("list(root) = %v, %v, want %v", entries, err, want)
+	}
+	entries, err = tree.list("acme", "web", "/vendor/")
+	if err != nil || !reflect.DeepEqual(entries, []contentEntry{{Name: "lib", Type: "submodule", Path: "vendor/lib"}}) {
+		t.Errorf("list(vendor) = %v, %v", entries, err)
+	}
+	if _, err := tree.list("acme", "web", "docs"); err == nil {
+		t.Errorf("list(docs) found a missing directory")
+	}
+
+	content, err := tree.read(client, "acme", "web", ".github/workflows/ci.yml")
+	if err != nil || string(content) != "on: push" {
+		t.Errorf("read(ci.yml) = %q, %v", content, err)
+	}
+	if _, err := tree.read(client, "acme", "web", "go.mod"); err == nil {
+		t.Errorf("read(go.mod) found a missing file")
+	}
+}
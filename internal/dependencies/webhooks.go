@@ -0,0 +1,43 @@
+package dependencies
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+
+	"github.com/jefeish/gh-repo-transfer/internal/ghclient"
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+// AnalyzeRepositoryWebhooks records the repository's own webhooks, which do not come along
+// with a transfer. Listing them requires admin access to the repository.
+func AnalyzeRepositoryWebhooks(client api.RESTClient, owner, repo string, deps *types.OrganizationalDependencies) error {
+	var hooks []struct {
+		Active bool     `json:"active"`
+		Events []string `json:"events"`
+		Config struct {
+			URL string `json:"url"`
+		} `json:"config"`
+	}
+	if err := ghclient.GetAll(&client, fmt.Sprintf("repos/%s/%s/hooks", owner, repo), &hooks); err != nil {
+		return fmt.Errorf("failed to list repository webhooks: %v", err)
+	}
+	for _, hook := range hooks {
+		deps.AppsIntegrations.Webhooks = append(deps.AppsIntegrations.Webhooks, FormatRepositoryWebhook(hook.Config.URL, hook.Events, hook.Active))
+	}
+	return nil
+}
+
+// FormatRepositoryWebhook describes a repository webhook without credentials, e.g.
+// "Repository webhook → https://ci.example.com/hook (events: pull_request, push)"
+func FormatRepositoryWebhook(rawURL string, events []string, active bool) string {
+	sorted := append([]string(nil), events...)
+	sort.Strings(sorted)
+	item := fmt.Sprintf("Repository webhook → %s (events: %s)", WebhookDestination(rawURL), strings.Join(sorted, ", "))
+	if !active {
+		item += " [inactive]"
+	}
+	return item
+}
@@ -0,0 +1,167 @@
+package dependencies
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+
+	"github.com/jefeish/gh-repo-transfer/internal/ghclient"
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+// Kinds of names derived from a repository's path
+const (
+	ConsequenceGoModule       = "go_module"       // Module path github.com/owner/repo
+	ConsequencePackageScope   = "package_scope"   // npm scope @owner of GitHub Packages
+	ConsequenceContainerImage = "container_image" // Image ghcr.io/owner/repo
+)
+
+// AnalyzeWhatIf reports the names derived from the path of owner/repo that change when it
+// becomes targetOrg/targetName: its Go module path, its npm package scope and the container
+// images its workflows and Dockerfiles publish or pull. Nothing has to exist in the target
+// yet; the target is only asked whether the name is already taken.
+func AnalyzeWhatIf(client api.RESTClient, owner, repo, targetOrg, targetName string) *types.WhatIf {
+	whatIf := &types.WhatIf{TargetRepository: fmt.Sprintf("%s/%s", targetOrg, targetName)}
+
+	var existing struct {
+		FullName string `json:"full_name"`
+	}
+	if err := client.Get(fmt.Sprintf("repos/%s/%s", targetOrg, targetName), &existing); err == nil {
+		whatIf.NameTaken = true
+	}
+
+	planned := plannedPath{owner: owner, repo: repo, targetOrg: targetOrg, targetName: targetName, dir: Subdirectory(owner, repo)}
+
+	if decoded, err := readRepositoryFile(client, owner, repo, scopedPath(owner, repo, "go.mod")); err == nil {
+		if consequence, ok := planned.goModule(string(decoded)); ok {
+			consequence.Path = scopedPath(owner, repo, "go.mod")
+			whatIf.Consequences = append(whatIf.Consequences, consequence)
+		}
+	}
+	if decoded, err := readRepositoryFile(client, owner, repo, scopedPath(owner, repo, "package.json")); err == nil {
+		if consequence, ok := planned.packageScope(decoded); ok {
+			consequence.Path = scopedPath(owner, repo, "package.json")
+			whatIf.Consequences = append(whatIf.Consequences, consequence)
+		}
+	}
+
+	for _, file := range dockerFiles {
+		filePath := scopedPath(owner, repo, file)
+		if decoded, err := readRepositoryFile(client, owner, repo, filePath); err == nil {
+			whatIf.Consequences = append(whatIf.Consequences, planned.containerImages(string(decoded), filePath)...)
+		}
+	}
+	workflows, err := readWorkflows(client, owner, repo)
+	if err != nil {
+		whatIf.Notes = append(whatIf.Notes, fmt.Sprintf("Workflows could not be read: %v", err))
+	}
+	for _, workflow := range workflows {
+		whatIf.Consequences = append(whatIf.Consequences, planned.containerImages(string(workflow.content), workflow.path)...)
+	}
+
+	return whatIf
+}
+
+// plannedPath is the current and the planned path of a repository
+type plannedPath struct {
+	owner, repo           string
+	targetOrg, targetName string
+	dir                   string // Subdirectory the repository is extracted from (--path)
+}
+
+// goModule returns the change of the module path declared in a go.mod. The module of an
+// extracted subdirectory becomes the root module of the new repository.
+func (p plannedPath) goModule(content string) (types.NameConsequence, bool) {
+	current := fmt.Sprintf("%s/%s/%s", ghclient.Host(), p.owner, p.repo)
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[0] != "module" {
+			continue
+		}
+		module := strings.Trim(fields[1], `"`)
+		if !strings.EqualFold(module, current) && !strings.HasPrefix(strings.ToLower(module), strings.ToLower(current)+"/") {
+			return types.NameConsequence{}, false
+		}
+		suffix := module[len(current):]
+		if p.dir != "" {
+			suffix = strings.TrimPrefix(suffix, "/"+p.dir)
+		}
+		return types.NameConsequence{
+			Kind:    ConsequenceGoModule,
+			Current: module,
+			Planned: fmt.Sprintf("%s/%s/%s%s", ghclient.Host(), p.targetOrg, p.targetName, suffix),
+			Action:  "Change the module path and update the imports of the module and its consumers",
+		}, true
+	}
+	return types.NameConsequence{}, false
+}
+
+// packageScope returns the change of an npm package scoped to the organization. GitHub Packages
+// only accepts packages whose scope is the owner of the repository publishing them.
+func (p plannedPath) packageScope(content []byte) (types.NameConsequence, bool) {
+	var manifest struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return types.NameConsequence{}, false
+	}
+	scope, name, ok := strings.Cut(manifest.Name, "/")
+	if !ok || !strings.EqualFold(scope, "@"+p.owner) {
+		return types.NameConsequence{}, false
+	}
+	return types.NameConsequence{
+		Kind:    ConsequencePackageScope,
+		Current: manifest.Name,
+		Planned: fmt.Sprintf("@%s/%s", strings.ToLower(p.targetOrg), name),
+		Action:  "Rename the package to the new scope, update the registry configuration and the dependencies of its consumers",
+	}, true
+}
+
+// derivedImagePattern matches image names built from the workflow context, which follow the
+// repository's path
+var derivedImagePattern = regexp.MustCompile(`ghcr\.io/\$\{\{\s*github\.(repository|repository_owner)\s*\}\}[^\s"']*`)
+
+// containerImages returns the changes of the GitHub Container Registry images named after the
+// repository in a workflow or Dockerfile, once per image and file
+func (p plannedPath) containerImages(content, filePath string) []types.NameConsequence {
+	var consequences []types.NameConsequence
+	seen := make(map[string]bool)
+	add := func(current, planned, action string) {
+		if seen[current] {
+			return
+		}
+		seen[current] = true
+		consequences = append(consequences, types.NameConsequence{
+			Kind: ConsequenceContainerImage, Path: filePath, Current: current, Planned: planned, Action: action,
+		})
+	}
+
+	currentImage := fmt.Sprintf("ghcr.io/%s/%s", strings.ToLower(p.owner), strings.ToLower(p.repo))
+	plannedImage := fmt.Sprintf("ghcr.io/%s/%s", strings.ToLower(p.targetOrg), strings.ToLower(p.targetName))
+	literal := regexp.MustCompile(`(?i)ghcr\.io/` + regexp.QuoteMeta(p.owner) + `/` + regexp.QuoteMeta(p.repo) + `\b[^\s"']*`)
+
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		for _, match := range literal.FindAllString(line, -1) {
+			rest := match[len(currentImage):]
+			if rest != "" && !strings.ContainsAny(rest[:1], ":@/") {
+				continue // Another repository's image, e.g. ghcr.io/acme/web-api for acme/web
+			}
+			add(match, plannedImage+rest, "Update the image reference; the image already published stays under the old name")
+		}
+		for _, match := range derivedImagePattern.FindAllStringSubmatch(line, -1) {
+			if match[1] == "repository" {
+				add(currentImage, plannedImage, "Derived from github.repository: the workflow publishes under the new name, pulls of the old image must be updated")
+			} else {
+				add(fmt.Sprintf("ghcr.io/%s", strings.ToLower(p.owner)), fmt.Sprintf("ghcr.io/%s", strings.ToLower(p.targetOrg)),
+					"Derived from github.repository_owner: the workflow publishes under the target organization, pulls of the old images must be updated")
+			}
+		}
+	}
+	return consequences
+}
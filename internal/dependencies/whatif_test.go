@@ -0,0 +1,70 @@
+package dependencies
+
+import (
+	"testing"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+func TestPlannedGoModule(t *testing.T) {
+	tests := []struct {
+		name    string
+		dir     string
+		content string
+		want    string
+		ok      bool
+	}{
+		{"root module", "", "module github.com/acme/web\n\ngo 1.22\n", "github.com/acme-new/web-v2", true},
+		{"major version", "", "module github.com/acme/web/v3\n", "github.com/acme-new/web-v2/v3", true},
+		{"extracted subdirectory", "services/foo", "module github.com/acme/web/services/foo\n", "github.com/acme-new/web-v2", true},
+		{"other module", "", "module github.com/acme/webhooks\n", "", false},
+		{"vanity path", "", "module go.acme.dev/web\n", "", false},
+	}
+	for _, tt := range tests {
+		planned := plannedPath{owner: "acme", repo: "web", targetOrg: "acme-new", targetName: "web-v2", dir: tt.dir}
+		got, ok := planned.goModule(tt.content)
+		if ok != tt.ok || got.Planned != tt.want {
+			t.Errorf("%s: goModule() = %q, %v; want %q, %v", tt.name, got.Planned, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestPlannedPackageScope(t *testing.T) {
+	planned := plannedPath{owner: "Acme", repo: "web", targetOrg: "Acme-New", targetName: "web-v2"}
+	if got, ok := planned.packageScope([]byte(`{"name": "@acme/web-ui", "version": "1.0.0"}`)); !ok || got.Planned != "@acme-new/web-ui" {
+		t.Errorf("packageScope() = %+v, %v", got, ok)
+	}
+	for _, content := range []string{`{"name": "web-ui"}`, `{"name": "@other/web-ui"}`, `not json`} {
+		if got, ok := planned.packageScope([]byte(content)); ok {
+			t.Errorf("packageScope(%s) = %+v", content, got)
+		}
+	}
+}
+
+func TestPlannedContainerImages(t *testing.T) {
+	planned := plannedPath{owner: "acme", repo: "web", targetOrg: "acme-new", targetName: "web-v2"}
+	content := `jobs:
+  build:
+    steps:
+      - run: docker push ghcr.io/${{ github.repository }}:latest
+      - run: docker pull ghcr.io/acme/web:1.2 && docker pull ghcr.io/acme/web-api:1.0
+      # ghcr.io/acme/web:old
+      - uses: docker/build-push-action@v6
+        with:
+          tags: ghcr.io/${{ github.repository_owner }}/tools
+`
+	got := planned.containerImages(content, ".github/workflows/release.yml")
+	want := []types.NameConsequence{
+		{Current: "ghcr.io/acme/web", Planned: "ghcr.io/acme-new/web-v2"},
+		{Current: "ghcr.io/acme/web:1.2", Planned: "ghcr.io/acme-new/web-v2:1.2"},
+		{Current: "ghcr.io/acme", Planned: "ghcr.io/acme-new"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("containerImages() = %+v, want %d images", got, len(want))
+	}
+	for i := range want {
+		if got[i].Current != want[i].Current || got[i].Planned != want[i].Planned || got[i].Kind != ConsequenceContainerImage {
+			t.Errorf("image %d = %+v, want %s → %s", i, got[i], want[i].Current, want[i].Planned)
+		}
+	}
+}
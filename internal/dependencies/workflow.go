@@ -0,0 +1,432 @@
+package dependencies
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+	"gopkg.in/yaml.v3"
+)
+
+// workflowJob is a job of a GitHub Actions workflow as far as the CI/CD analysis reads it.
+// Fields that take several shapes, runs-on and the matrix, stay YAML nodes and are evaluated
+// by runnerLabelSets.
+type workflowJob struct {
+	RunsOn   yaml.Node `yaml:"runs-on"`
+	Uses     string    `yaml:"uses"`    // Reusable workflow called by the job
+	Secrets  yaml.Node `yaml:"secrets"` // Secrets passed to it: a mapping or inherit
+	Strategy struct {
+		Matrix yaml.Node `yaml:"matrix"`
+	} `yaml:"strategy"`
+	Steps []workflowStep `yaml:"steps"`
+}
+
+// inheritsSecrets reports whether the job passes all of the caller's secrets to the reusable
+// workflow it calls
+func (j workflowJob) inheritsSecrets() bool {
+	return j.Uses != "" && j.Secrets.Kind == yaml.ScalarNode && j.Secrets.Value == "inherit"
+}
+
+// workflowStep is a step of a job
+type workflowStep struct {
+	Uses string               `yaml:"uses"`
+	Run  string               `yaml:"run"`
+	With map[string]yaml.Node `yaml:"with"`
+}
+
+// builtinSecrets are provided by GitHub Actions itself and are no organization dependency
+var builtinSecrets = map[string]bool{"GITHUB_TOKEN": true}
+
+var (
+	// expressionPattern matches the ${{ }} expressions of a value
+	expressionPattern = regexp.MustCompile(`\$\{\{(.*?)\}\}`)
+	// contextReferencePattern matches secrets.NAME, vars.NAME and secrets['NAME'] in an expression
+	contextReferencePattern = regexp.MustCompile(`\b(secrets|vars)(?:\.([A-Za-z_][A-Za-z0-9_]*)|\[\s*['"]([A-Za-z_][A-Za-z0-9_]*)['"]\s*\])`)
+	// matrixReferencePattern matches a runs-on value that is a single matrix expression
+	matrixReferencePattern = regexp.MustCompile(`^\$\{\{\s*matrix\.([A-Za-z0-9_.-]+)\s*\}\}$`)
+	// githubHostedPattern matches the standard GitHub-hosted runner images; larger runners have
+	// names chosen by the organization and are organization dependencies
+	githubHostedPattern = regexp.MustCompile(`^(ubuntu-(latest|\d\d\.04)(-arm)?|windows-(latest|20\d\d|11-arm)|macos-(latest|\d\d)(-large|-xlarge|-intel)?)$`)
+)
+
+// workflowFindings collects the findings of one workflow file, attributed to the jobs they
+// were found in, in the order they were found
+type workflowFindings struct {
+	workflow string
+	order    []workflowFinding
+	jobs     map[workflowFinding][]string // Jobs of a finding; nil when found outside of jobs
+}
+
+// workflowFinding is one item of a dependency list, before its location is appended
+type workflowFinding struct {
+	list *[]string
+	item string
+}
+
+// add records an item found in a job, or in the workflow itself when job is ""
+func (f *workflowFindings) add(list *[]string, item, job string) {
+	key := workflowFinding{list: list, item: item}
+	jobs, seen := f.jobs[key]
+	if !seen {
+		f.order = append(f.order, key)
+	}
+	switch {
+	case job == "" || (seen && jobs == nil):
+		// Found in the workflow itself, which applies to every job
+		f.jobs[key] = nil
+	case !containsString(jobs, job):
+		f.jobs[key] = append(jobs, job)
+	}
+}
+
+// write appends the items to their lists with a location suffix, e.g.
+// "DEPLOY_TOKEN (in deploy.yml, jobs build, release)"
+func (f *workflowFindings) write() {
+	for _, key := range f.order {
+		location := f.workflow
+		switch jobs := f.jobs[key]; len(jobs) {
+		case 0:
+		case 1:
+			location += ", job " + jobs[0]
+		default:
+			location += ", jobs " + strings.Join(jobs, ", ")
+		}
+		reference := fmt.Sprintf("%s (in %s)", key.item, location)
+		if !containsString(*key.list, reference) {
+			*key.list = append(*key.list, reference)
+		}
+	}
+}
+
+// analyzeWorkflowContent parses a workflow file and records the organization secrets and
+// variables its expressions reference, the self-hosted runners its jobs run on (evaluating
+// matrices), the organization's actions and reusable workflows it uses, the dispatches it
+// sends to other repositories of the organization and the artifact attestations it creates. Findings name the jobs they were found in.
+// It returns what the workflow calls that is analyzed on its own.
+func analyzeWorkflowContent(content []byte, workflowName, owner string, deps *types.OrganizationalDependencies) (workflowCalls, error) {
+	var calls workflowCalls
+	root, err := parseWorkflow(content, workflowName)
+	if err != nil {
+		return calls, err
+	}
+
+	ci := &deps.ActionsCIDependencies
+	findings := &workflowFindings{workflow: workflowName, jobs: make(map[workflowFinding][]string)}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+		if key.Value != "jobs" {
+			// Workflow-level env, concurrency and run-name apply to every job
+			addContextReferences(value, "", ci, findings)
+			continue
+		}
+		if value.Kind != yaml.MappingNode {
+			continue
+		}
+		for j := 0; j+1 < len(value.Content); j += 2 {
+			jobID, jobNode := value.Content[j].Value, value.Content[j+1]
+			var job workflowJob
+			if err := jobNode.Decode(&job); err != nil {
+				return calls, fmt.Errorf("failed to parse job %s of workflow %s: %v", jobID, workflowName, err)
+			}
+			addContextReferences(jobNode, jobID, ci, findings)
+			addJobRunners(job, jobID, ci, findings)
+			addJobActions(job, jobID, owner, ci, findings)
+			addJobDispatches(job, jobID, owner, ci, findings)
+			addJobAttestations(job, jobID, ci, findings)
+			for _, step := range job.Steps {
+				if action, ok := localActionPath(step.Uses); ok && !containsString(calls.localActions, action) {
+					calls.localActions = append(calls.localActions, action)
+				}
+			}
+			if job.inheritsSecrets() {
+				if called, ok := organizationAction(job.Uses, owner); ok {
+					calls.inherited = append(calls.inherited, inheritedCall{
+						caller: workflowName, job: jobID, workflow: called, ref: workflowRef(job.Uses)})
+				}
+			}
+		}
+	}
+	findings.write()
+	return calls, nil
+}
+
+// parseWorkflow parses a workflow file into its top-level mapping
+func parseWorkflow(content []byte, workflowName string) (*yaml.Node, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return nil, fmt.Errorf("failed to parse workflow %s: %v", workflowName, err)
+	}
+	if len(document.Content) == 0 || document.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("workflow %s is not a YAML mapping", workflowName)
+	}
+	return document.Content[0], nil
+}
+
+// workflowCalls is what a workflow file calls that is analyzed on its own
+type workflowCalls struct {
+	localActions []string        // uses: ./path of steps, see analyzeLocalActions
+	inherited    []inheritedCall // Organization reusable workflows called with secrets: inherit
+}
+
+// addContextReferences records the secrets and variables referenced by the expressions in a
+// node. Only expressions count: ${{ }} anywhere, and the whole value of if conditions.
+func addContextReferences(node *yaml.Node, job string, ci *types.ActionsCIDependencies, findings *workflowFindings) {
+	walkContextReferences(node, func(context, name string) {
+		if context == "secrets" {
+			if !builtinSecrets[strings.ToUpper(name)] {
+				findings.add(&ci.OrganizationSecrets, name, job)
+			}
+		} else {
+			findings.add(&ci.OrganizationVariables, name, job)
+		}
+	})
+}
+
+// walkContextReferences calls reference with the context ("secrets" or "vars") and name of
+// every reference in the expressions of a node
+func walkContextReferences(node *yaml.Node, reference func(context, name string)) {
+	var walk func(node *yaml.Node, condition bool)
+	walk = func(node *yaml.Node, condition bool) {
+		switch node.Kind {
+		case yaml.ScalarNode:
+			expressions := []string{node.Value}
+			if !condition {
+				expressions = nil
+				for _, match := range expressionPattern.FindAllStringSubmatch(node.Value, -1) {
+					expressions = append(expressions, match[1])
+				}
+			}
+			for _, expression := range expressions {
+				for _, match := range contextReferencePattern.FindAllStringSubmatch(expression, -1) {
+					reference(match[1], match[2]+match[3])
+				}
+			}
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				walk(node.Content[i+1], node.Content[i].Value == "if")
+			}
+		case yaml.SequenceNode, yaml.DocumentNode:
+			for _, child := range node.Content {
+				walk(child, false)
+			}
+		case yaml.AliasNode:
+			if node.Alias != nil {
+				walk(node.Alias, condition)
+			}
+		}
+	}
+	walk(node, false)
+}
+
+// addJobRunners records the self-hosted runners a job can run on. A runs-on that cannot be
+// evaluated, e.g. one taken from an input, is recorded as written so it gets reviewed.
+func addJobRunners(job workflowJob, jobID string, ci *types.ActionsCIDependencies, findings *workflowFindings) {
+	for _, runner := range runnerLabelSets(&job.RunsOn, &job.Strategy.Matrix) {
+		if len(runner) == 1 && isGitHubHostedRunner(runner[0]) {
+			continue
+		}
+		findings.add(&ci.SelfHostedRunners, "Self-hosted runner: "+strings.Join(runner, ", "), jobID)
+	}
+}
+
+// runnerLabelSets evaluates runs-on into the label sets the job can run on: one per matrix
+// combination when runs-on refers to the matrix. A runner group is returned as "group NAME"
+// followed by its labels.
+func runnerLabelSets(runsOn *yaml.Node, matrix *yaml.Node) [][]string {
+	switch runsOn.Kind {
+	case yaml.ScalarNode:
+		if runsOn.Value == "" {
+			return nil
+		}
+		return resolveRunnerLabel(runsOn, matrix)
+	case yaml.SequenceNode:
+		sets := [][]string{nil}
+		for _, item := range runsOn.Content {
+			var next [][]string
+			for _, set := range sets {
+				for _, labels := range resolveRunnerLabel(item, matrix) {
+					next = append(next, append(append([]string(nil), set...), labels...))
+				}
+			}
+			sets = next
+		}
+		return sets
+	case yaml.MappingNode:
+		var group string
+		var labels *yaml.Node
+		for i := 0; i+1 < len(runsOn.Content); i += 2 {
+			switch runsOn.Content[i].Value {
+			case "group":
+				group = runsOn.Content[i+1].Value
+			case "labels":
+				labels = runsOn.Content[i+1]
+			}
+		}
+		sets := [][]string{nil}
+		if labels != nil {
+			sets = runnerLabelSets(labels, matrix)
+		}
+		if group != "" {
+			for i := range sets {
+				sets[i] = append([]string{"group " + group}, sets[i]...)
+			}
+		}
+		return sets
+	}
+	return nil
+}
+
+// resolveRunnerLabel evaluates one runs-on label; a ${{ matrix.key }} expression gives one
+// label set per value of the key in the matrix and its include entries
+func resolveRunnerLabel(label *yaml.Node, matrix *yaml.Node) [][]string {
+	if label.Kind != yaml.ScalarNode {
+		return runnerLabelSets(label, matrix)
+	}
+	match := matrixReferencePattern.FindStringSubmatch(strings.TrimSpace(label.Value))
+	if match == nil {
+		return [][]string{{label.Value}}
+	}
+	values := matrixValues(matrix, strings.Split(match[1], "."))
+	if len(values) == 0 {
+		return [][]string{{label.Value}}
+	}
+	var sets [][]string
+	for _, value := range values {
+		for _, set := range runnerLabelSets(value, nil) {
+			if !containsLabelSet(sets, set) {
+				sets = append(sets, set)
+			}
+		}
+	}
+	return sets
+}
+
+// matrixValues returns the values a matrix property path, e.g. ["config", "runner"], takes in
+// the matrix and its include entries. A matrix given as an expression has no known values.
+func matrixValues(matrix *yaml.Node, path []string) []*yaml.Node {
+	if matrix == nil || matrix.Kind != yaml.MappingNode {
+		return nil
+	}
+	var values []*yaml.Node
+	for i := 0; i+1 < len(matrix.Content); i += 2 {
+		key, value := matrix.Content[i].Value, matrix.Content[i+1]
+		switch {
+		case key == "include" && value.Kind == yaml.SequenceNode:
+			for _, entry := range value.Content {
+				values = append(values, propertyValues(entry, path)...)
+			}
+		case key == path[0] && value.Kind == yaml.SequenceNode:
+			for _, item := range value.Content {
+				values = append(values, propertyValues(item, path[1:])...)
+			}
+		}
+	}
+	return values
+}
+
+// propertyValues follows a property path into a mapping; an empty path returns node itself
+func propertyValues(node *yaml.Node, path []string) []*yaml.Node {
+	if len(path) == 0 {
+		return []*yaml.Node{node}
+	}
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == path[0] {
+			return propertyValues(node.Content[i+1], path[1:])
+		}
+	}
+	return nil
+}
+
+// addJobActions records the actions of the organization the job's steps use, and the
+// organization's reusable workflow the job calls
+func addJobActions(job workflowJob, jobID, owner string, ci *types.ActionsCIDependencies, findings *workflowFindings) {
+	uses := []string{job.Uses}
+	for _, step := range job.Steps {
+		uses = append(uses, step.Uses)
+	}
+	for _, reference := range uses {
+		if action, ok := organizationAction(reference, owner); ok {
+			findings.add(&ci.OrgSpecificActions, action, jobID)
+		}
+	}
+}
+
+// organizationAction returns the action or reusable workflow of a uses: reference without its
+// ref, when it belongs to owner; local (./) and docker:// references never do
+func organizationAction(reference, owner string) (string, bool) {
+	reference = strings.TrimSpace(reference)
+	if reference == "" || strings.HasPrefix(reference, "./") || strings.HasPrefix(reference, "docker://") {
+		return "", false
+	}
+	action := strings.SplitN(reference, "@", 2)[0]
+	parts := strings.SplitN(action, "/", 2)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], owner) {
+		return "", false
+	}
+	return action, true
+}
+
+// dispatchTargetPatterns match dispatches a run script sends to another repository: REST
+// calls to the dispatches endpoints and gh workflow run --repo; %s is the organization
+var dispatchTargetPatterns = []string{
+	`repos/(%s/[A-Za-z0-9_.-]+)/(?:dispatches|actions/workflows/[^/\s]+/dispatches)`,
+	`gh\s+workflow\s+run\b[^\n]*?(?:--repo[= ]|-R\s*)(%s/[A-Za-z0-9_.-]+)`,
+}
+
+// addJobDispatches records the repositories of the organization the job sends
+// repository_dispatch or workflow_dispatch events to
+func addJobDispatches(job workflowJob, jobID, owner string, ci *types.ActionsCIDependencies, findings *workflowFindings) {
+	for _, step := range job.Steps {
+		if strings.Contains(strings.ToLower(step.Uses), "dispatch") {
+			if repository, ok := step.With["repository"]; ok {
+				if parts := strings.SplitN(repository.Value, "/", 2); len(parts) == 2 && strings.EqualFold(parts[0], owner) {
+					findings.add(&ci.CrossRepoWorkflowTriggers, "Cross-repo trigger: "+repository.Value, jobID)
+				}
+			}
+		}
+		for _, pattern := range dispatchTargetPatterns {
+			re := regexp.MustCompile(`(?i)` + fmt.Sprintf(pattern, regexp.QuoteMeta(owner)))
+			for _, match := range re.FindAllStringSubmatch(step.Run, -1) {
+				findings.add(&ci.CrossRepoWorkflowTriggers, "Cross-repo trigger: "+match[1], jobID)
+			}
+		}
+	}
+}
+
+// attestationActions create artifact attestations, signed for the repository and the workflow
+// that ran them
+var attestationActions = []string{"actions/attest-build-provenance", "actions/attest-sbom", "actions/attest"}
+
+// addJobAttestations records the artifact attestations the job's steps create
+func addJobAttestations(job workflowJob, jobID string, ci *types.ActionsCIDependencies, findings *workflowFindings) {
+	for _, step := range job.Steps {
+		action := strings.TrimSpace(strings.SplitN(step.Uses, "@", 2)[0])
+		for _, attestation := range attestationActions {
+			if strings.EqualFold(action, attestation) {
+				findings.add(&ci.ArtifactAttestations, "Artifact attestation: "+attestation, jobID)
+			}
+		}
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, existing := range values {
+		if existing == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsLabelSet(sets [][]string, set []string) bool {
+	for _, existing := range sets {
+		if strings.Join(existing, "\x00") == strings.Join(set, "\x00") {
+			return true
+		}
+	}
+	return false
+}
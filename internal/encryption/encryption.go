@@ -0,0 +1,184 @@
+// Package encryption protects the files that describe an organization's topology — state files,
+// plans, journals, per-repository reports, redirect maps, the history database and ruleset
+// exports — with AES-256-GCM (see --encrypt-key). Reading is
+// transparent: encrypted files are recognized by their header and decrypted with the configured
+// key, plain files are read as before.
+package encryption
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// header starts every encrypted file; the salt, nonce and sealed data follow it
+const header = "repo-transfer-encrypted-v1\n"
+
+const (
+	saltSize   = 16
+	iterations = 600000 // PBKDF2-HMAC-SHA256 rounds deriving the file key from the key material
+)
+
+// active holds the key material of --encrypt-key and the file keys derived from it per salt
+var active = struct {
+	sync.Mutex
+	secret    []byte
+	writeSalt []byte // One salt per run, so rewriting a journal does not derive a key each time
+	keys      map[string][]byte
+}{keys: make(map[string][]byte)}
+
+// SetKey configures the key of --encrypt-key: env:NAME reads it from an environment variable,
+// anything else is the path of a key file. Surrounding whitespace is ignored; an empty spec
+// disables encryption.
+func SetKey(spec string) error {
+	var secret []byte
+	switch {
+	case spec == "":
+	case strings.HasPrefix(spec, "env:"):
+		name := strings.TrimPrefix(spec, "env:")
+		secret = []byte(strings.TrimSpace(os.Getenv(name)))
+		if len(secret) == 0 {
+			return fmt.Errorf("encryption key variable %s is not set", name)
+		}
+	default:
+		data, err := os.ReadFile(spec)
+		if err != nil {
+			return fmt.Errorf("failed to read encryption key: %v", err)
+		}
+		secret = bytes.TrimSpace(data)
+		if len(secret) == 0 {
+			return fmt.Errorf("encryption key file %s is empty", spec)
+		}
+	}
+
+	active.Lock()
+	defer active.Unlock()
+	active.secret = secret
+	active.writeSalt = nil
+	active.keys = make(map[string][]byte)
+	return nil
+}
+
+// Enabled reports whether an encryption key is configured
+func Enabled() bool {
+	active.Lock()
+	defer active.Unlock()
+	return active.secret != nil
+}
+
+// IsEncrypted reports whether data was written by Encrypt
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(header))
+}
+
+// Encrypt seals data with the configured key; without a key data is returned unchanged
+func Encrypt(data []byte) ([]byte, error) {
+	active.Lock()
+	defer active.Unlock()
+	if active.secret == nil {
+		return data, nil
+	}
+	if active.writeSalt == nil {
+		active.writeSalt = make([]byte, saltSize)
+		if _, err := rand.Read(active.writeSalt); err != nil {
+			return nil, fmt.Errorf("failed to generate salt: %v", err)
+		}
+	}
+	aead, err := fileCipher(active.writeSalt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %v", err)
+	}
+
+	sealed := append([]byte(header), active.writeSalt...)
+	sealed = append(sealed, nonce...)
+	return aead.Seal(sealed, nonce, data, []byte(header)), nil
+}
+
+// Decrypt opens data written by Encrypt; data without the header is returned unchanged
+func Decrypt(data []byte) ([]byte, error) {
+	if !IsEncrypted(data) {
+		return data, nil
+	}
+	active.Lock()
+	defer active.Unlock()
+	if active.secret == nil {
+		return nil, fmt.Errorf("file is encrypted; pass the key with --encrypt-key")
+	}
+
+	body := data[len(header):]
+	if len(body) < saltSize {
+		return nil, fmt.Errorf("encrypted file is truncated")
+	}
+	aead, err := fileCipher(body[:saltSize])
+	if err != nil {
+		return nil, err
+	}
+	body = body[saltSize:]
+	if len(body) < aead.NonceSize() {
+		return nil, fmt.Errorf("encrypted file is truncated")
+	}
+	plain, err := aead.Open(nil, body[:aead.NonceSize()], body[aead.NonceSize():], []byte(header))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt: wrong key or modified file")
+	}
+	return plain, nil
+}
+
+// ReadFile reads a file, decrypting it when it is encrypted
+func ReadFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Decrypt(data)
+}
+
+// fileCipher returns the AES-256-GCM cipher of the key derived for salt; the caller holds the lock
+func fileCipher(salt []byte) (cipher.AEAD, error) {
+	key, ok := active.keys[string(salt)]
+	if !ok {
+		key = pbkdf2SHA256(active.secret, salt, iterations, 32)
+		active.keys[string(salt)] = key
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// pbkdf2SHA256 derives a key of length bytes from password and salt (RFC 8018, PBKDF2 with
+// HMAC-SHA256), so passphrases can serve as key material
+func pbkdf2SHA256(password, salt []byte, rounds, length int) []byte {
+	prf := hmac.New(sha256.New, password)
+	var key []byte
+	for block := uint32(1); len(key) < length; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		binary.Write(prf, binary.BigEndian, block)
+		u := prf.Sum(nil)
+		t := append([]byte(nil), u...)
+		for i := 1; i < rounds; i++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for j := range t {
+				t[j] ^= u[j]
+			}
+		}
+		key = append(key, t...)
+	}
+	return key[:length]
+}
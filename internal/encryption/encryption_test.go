@@ -0,0 +1,102 @@
+package encryption
+
+import (
+	"bytes"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPBKDF2SHA256(t *testing.T) {
+	// Test vector of RFC 7914, section 11
+	want := "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc49ca9cccf179b645991664b39d77ef317c71b845b1e30bd509112041d3a19783"
+	if got := hex.EncodeToString(pbkdf2SHA256([]byte("passwd"), []byte("salt"), 1, 64)); got != want {
+		t.Errorf("pbkdf2SHA256() = %s, want %s", got, want)
+	}
+}
+
+func TestSetKey(t *testing.T) {
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "key")
+	if err := os.WriteFile(keyFile, []byte("correct horse battery staple\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	emptyFile := filepath.Join(dir, "empty")
+	if err := os.WriteFile(emptyFile, []byte("\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("REPO_TRANSFER_TEST_KEY", "s3cret")
+	t.Setenv("REPO_TRANSFER_TEST_EMPTY", "")
+	defer SetKey("")
+
+	tests := []struct {
+		spec        string
+		wantEnabled bool
+		wantErr     bool
+	}{
+		{"", false, false},
+		{keyFile, true, false},
+		{"env:REPO_TRANSFER_TEST_KEY", true, false},
+		{"env:REPO_TRANSFER_TEST_EMPTY", false, true},
+		{emptyFile, false, true},
+		{filepath.Join(dir, "missing"), false, true},
+	}
+	for _, tt := range tests {
+		SetKey("")
+		err := SetKey(tt.spec)
+		if (err != nil) != tt.wantErr || Enabled() != tt.wantEnabled {
+			t.Errorf("SetKey(%q) error = %v, enabled = %v, want error %v, enabled %v", tt.spec, err, Enabled(), tt.wantErr, tt.wantEnabled)
+		}
+	}
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	t.Setenv("REPO_TRANSFER_TEST_KEY", "s3cret")
+	t.Setenv("REPO_TRANSFER_TEST_OTHER", "other")
+	defer SetKey("")
+	plain := []byte(`{"version":1,"target_org":"acme"}`)
+
+	if err := SetKey("env:REPO_TRANSFER_TEST_KEY"); err != nil {
+		t.Fatal(err)
+	}
+	sealed, err := Encrypt(plain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tampered := append([]byte(nil), sealed...)
+	tampered[len(tampered)-1] ^= 1
+
+	tests := []struct {
+		name    string
+		key     string
+		data    []byte
+		want    []byte
+		wantErr bool
+	}{
+		{"same key", "env:REPO_TRANSFER_TEST_KEY", sealed, plain, false},
+		{"plain file with key", "env:REPO_TRANSFER_TEST_KEY", plain, plain, false},
+		{"plain file without key", "", plain, plain, false},
+		{"modified file", "env:REPO_TRANSFER_TEST_KEY", tampered, nil, true},
+		{"truncated file", "env:REPO_TRANSFER_TEST_KEY", sealed[:len(header)+4], nil, true},
+		{"wrong key", "env:REPO_TRANSFER_TEST_OTHER", sealed, nil, true},
+		{"no key", "", sealed, nil, true},
+	}
+	for _, tt := range tests {
+		if err := SetKey(tt.key); err != nil {
+			t.Fatal(err)
+		}
+		got, err := Decrypt(tt.data)
+		if (err != nil) != tt.wantErr || !bytes.Equal(got, tt.want) {
+			t.Errorf("%s: Decrypt() = %q, %v, want %q, error %v", tt.name, got, err, tt.want, tt.wantErr)
+		}
+	}
+
+	if !IsEncrypted(sealed) || bytes.Contains(sealed, []byte("acme")) {
+		t.Errorf("Encrypt() did not seal the data: %q", sealed)
+	}
+	SetKey("")
+	if unchanged, err := Encrypt(plain); err != nil || !bytes.Equal(unchanged, plain) {
+		t.Errorf("Encrypt() without key = %q, %v", unchanged, err)
+	}
+}
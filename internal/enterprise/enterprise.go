@@ -0,0 +1,195 @@
+// Package enterprise reads the enterprise-level policies that override organization settings:
+// Actions permissions, repository creation/visibility policies and GHAS defaults
+package enterprise
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+
+	"github.com/jefeish/gh-repo-transfer/internal/ghclient"
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+// Policy names
+const (
+	PolicyActions            = "Enterprise Actions policy"
+	PolicyRepositoryCreation = "Enterprise repository creation policy"
+	PolicyVisibilityChange   = "Enterprise repository visibility change policy"
+	PolicyPrivateForking     = "Enterprise private repository forking policy"
+	PolicyDefaultPermission  = "Enterprise default repository permission policy"
+	PolicyRepositoryDeletion = "Enterprise repository deletion policy"
+	PolicyAdvancedSecurity   = "Enterprise Advanced Security for new repositories"
+	PolicySecretScanning     = "Enterprise secret scanning for new repositories"
+	PolicyPushProtection     = "Enterprise push protection for new repositories"
+)
+
+// NoPolicy is the status of a setting the enterprise leaves to its organizations
+const NoPolicy = "no_policy"
+
+var (
+	cache      = make(map[string][]types.OrgPolicy)
+	cacheMutex sync.Mutex
+)
+
+// Policies reads the policies of an enterprise; results are cached for the run. Each part is read
+// separately, so a token that can only read some of them still returns those. An error is only
+// returned when none could be read.
+func Policies(client api.RESTClient, slug string) ([]types.OrgPolicy, error) {
+	key := strings.ToLower(slug)
+	cacheMutex.Lock()
+	defer cacheMutex.Unlock()
+	if policies, ok := cache[key]; ok {
+		return policies, nil
+	}
+
+	var policies []types.OrgPolicy
+	var errs []string
+
+	if policy, err := actionsPolicy(client, slug); err != nil {
+		errs = append(errs, err.Error())
+	} else {
+		policies = append(policies, policy)
+	}
+
+	if repoPolicies, err := repositoryPolicies(slug); err != nil {
+		errs = append(errs, err.Error())
+	} else {
+		policies = append(policies, repoPolicies...)
+	}
+
+	if securityPolicies, err := securityPolicies(client, slug); err != nil {
+		errs = append(errs, err.Error())
+	} else {
+		policies = append(policies, securityPolicies...)
+	}
+
+	if len(policies) == 0 {
+		return nil, fmt.Errorf("could not read policies of enterprise %s: %s", slug, strings.Join(errs, "; "))
+	}
+	cache[key] = policies
+	return policies, nil
+}
+
+// actionsPolicy reads which organizations may use Actions and which actions are allowed
+func actionsPolicy(client api.RESTClient, slug string) (types.OrgPolicy, error) {
+	var permissions struct {
+		EnabledOrganizations string `json:"enabled_organizations"`
+		AllowedActions       string `json:"allowed_actions"`
+	}
+	if err := client.Get(fmt.Sprintf("enterprises/%s/actions/permissions", slug), &permissions); err != nil {
+		return types.OrgPolicy{}, fmt.Errorf("actions permissions: %v", err)
+	}
+
+	policy := types.OrgPolicy{Name: PolicyActions, Status: permissions.AllowedActions}
+	switch permissions.EnabledOrganizations {
+	case "none":
+		policy.Status = "disabled"
+	case "selected":
+		policy.Restrictions = append(policy.Restrictions, "Actions enabled for selected organizations only")
+	}
+
+	if permissions.AllowedActions == "selected" {
+		var selected struct {
+			GitHubOwnedAllowed bool     `json:"github_owned_allowed"`
+			VerifiedAllowed    bool     `json:"verified_allowed"`
+			PatternsAllowed    []string `json:"patterns_allowed"`
+		}
+		if err := client.Get(fmt.Sprintf("enterprises/%s/actions/permissions/selected-actions", slug), &selected); err == nil {
+			if selected.GitHubOwnedAllowed {
+				policy.Restrictions = append(policy.Restrictions, "GitHub-owned actions allowed")
+			}
+			if selected.VerifiedAllowed {
+				policy.Restrictions = append(policy.Restrictions, "Verified creator actions allowed")
+			}
+			for _, pattern := range selected.PatternsAllowed {
+				policy.Restrictions = append(policy.Restrictions, "Allowed: "+pattern)
+			}
+		}
+	}
+	return policy, nil
+}
+
+// repositoryPolicies reads the repository management policies, only available through GraphQL
+func repositoryPolicies(slug string) ([]types.OrgPolicy, error) {
+	client, err := ghclient.NewGraphQLClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GraphQL client: %v", err)
+	}
+
+	var response struct {
+		Enterprise *struct {
+			OwnerInfo *struct {
+				MembersCanCreateRepositoriesSetting         string `json:"membersCanCreateRepositoriesSetting"`
+				MembersCanChangeRepositoryVisibilitySetting string `json:"membersCanChangeRepositoryVisibilitySetting"`
+				AllowPrivateRepositoryForkingSetting        string `json:"allowPrivateRepositoryForkingSetting"`
+				DefaultRepositoryPermissionSetting          string `json:"defaultRepositoryPermissionSetting"`
+				MembersCanDeleteRepositoriesSetting         string `json:"membersCanDeleteRepositoriesSetting"`
+			} `json:"ownerInfo"`
+		} `json:"enterprise"`
+	}
+	query := `query($slug: String!) {
+  enterprise(slug: $slug) {
+    ownerInfo {
+      membersCanCreateRepositoriesSetting
+      membersCanChangeRepositoryVisibilitySetting
+      allowPrivateRepositoryForkingSetting
+      defaultRepositoryPermissionSetting
+      membersCanDeleteRepositoriesSetting
+    }
+  }
+}`
+	if err := client.Do(query, map[string]interface{}{"slug": slug}, &response); err != nil {
+		return nil, fmt.Errorf("repository policies: %v", err)
+	}
+	if response.Enterprise == nil || response.Enterprise.OwnerInfo == nil {
+		return nil, fmt.Errorf("repository policies: enterprise owner access required")
+	}
+
+	info := response.Enterprise.OwnerInfo
+	return []types.OrgPolicy{
+		{Name: PolicyRepositoryCreation, Status: settingValue(info.MembersCanCreateRepositoriesSetting)},
+		{Name: PolicyVisibilityChange, Status: settingValue(info.MembersCanChangeRepositoryVisibilitySetting)},
+		{Name: PolicyPrivateForking, Status: settingValue(info.AllowPrivateRepositoryForkingSetting)},
+		{Name: PolicyDefaultPermission, Status: settingValue(info.DefaultRepositoryPermissionSetting)},
+		{Name: PolicyRepositoryDeletion, Status: settingValue(info.MembersCanDeleteRepositoriesSetting)},
+	}, nil
+}
+
+// securityPolicies reads the GHAS features enabled by default for new repositories
+func securityPolicies(client api.RESTClient, slug string) ([]types.OrgPolicy, error) {
+	var settings struct {
+		AdvancedSecurity *bool `json:"advanced_security_enabled_for_new_repositories"`
+		SecretScanning   *bool `json:"secret_scanning_enabled_for_new_repositories"`
+		PushProtection   *bool `json:"secret_scanning_push_protection_enabled_for_new_repositories"`
+	}
+	if err := client.Get(fmt.Sprintf("enterprises/%s/code_security_and_analysis", slug), &settings); err != nil {
+		return nil, fmt.Errorf("code security settings: %v", err)
+	}
+	return []types.OrgPolicy{
+		{Name: PolicyAdvancedSecurity, Status: enabledValue(settings.AdvancedSecurity)},
+		{Name: PolicySecretScanning, Status: enabledValue(settings.SecretScanning)},
+		{Name: PolicyPushProtection, Status: enabledValue(settings.PushProtection)},
+	}, nil
+}
+
+// settingValue converts a GraphQL setting value such as NO_POLICY to "no_policy"
+func settingValue(value string) string {
+	if value == "" {
+		return NoPolicy
+	}
+	return strings.ToLower(value)
+}
+
+func enabledValue(value *bool) string {
+	switch {
+	case value == nil:
+		return NoPolicy
+	case *value:
+		return "enabled"
+	default:
+		return "disabled"
+	}
+}
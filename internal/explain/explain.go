@@ -0,0 +1,128 @@
+// Package explain holds the knowledge base behind the explain command: for each finding kind
+// of the validation (see validation.FindingKind), why a status is assigned, which API data
+// informs it and how to remediate it. The built-in entries can be extended or overridden
+// with YAML files of the same format.
+package explain
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/jefeish/gh-repo-transfer/internal/validation"
+)
+
+//go:embed knowledge.yaml
+var builtinKnowledge []byte
+
+// Entry explains one finding ID
+type Entry struct {
+	ID          string            `yaml:"id" json:"id"`
+	Title       string            `yaml:"title" json:"title"`
+	Summary     string            `yaml:"summary" json:"summary"`
+	Statuses    map[string]string `yaml:"statuses,omitempty" json:"statuses,omitempty"` // Why each validation status is assigned
+	Data        []string          `yaml:"data,omitempty" json:"data,omitempty"`         // API data the validation reads
+	Remediation []string          `yaml:"remediation,omitempty" json:"remediation,omitempty"`
+	Source      string            `yaml:"-" json:"source"` // "built-in" or the file the entry was read from
+}
+
+// KnowledgeBase maps finding IDs to their explanation
+type KnowledgeBase struct {
+	entries map[string]Entry
+}
+
+// Load reads the built-in knowledge base and then, when path is set, the YAML file or the
+// *.yaml/*.yml files of the directory at path. Entries with an existing ID replace it.
+func Load(path string) (*KnowledgeBase, error) {
+	kb := &KnowledgeBase{entries: make(map[string]Entry)}
+	if err := kb.add(builtinKnowledge, "built-in"); err != nil {
+		return nil, err
+	}
+	if path == "" {
+		return kb, nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read knowledge base %s: %v", path, err)
+	}
+	files := []string{path}
+	if info.IsDir() {
+		files = nil
+		for _, pattern := range []string{"*.yaml", "*.yml"} {
+			matches, _ := filepath.Glob(filepath.Join(path, pattern))
+			files = append(files, matches...)
+		}
+		sort.Strings(files)
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read knowledge base %s: %v", file, err)
+		}
+		if err := kb.add(data, file); err != nil {
+			return nil, err
+		}
+	}
+	return kb, nil
+}
+
+func (kb *KnowledgeBase) add(data []byte, source string) error {
+	var entries []Entry
+	if err := yaml.NewDecoder(bytes.NewReader(data)).Decode(&entries); err != nil {
+		return fmt.Errorf("failed to parse knowledge base %s: %v", source, err)
+	}
+	for i, entry := range entries {
+		if entry.ID == "" || entry.Title == "" {
+			return fmt.Errorf("knowledge base %s: entry %d needs an id and a title", source, i+1)
+		}
+		entry.ID = strings.ToLower(entry.ID)
+		entry.Source = source
+		kb.entries[entry.ID] = entry
+	}
+	return nil
+}
+
+// Lookup returns the entry of a finding kind (case-insensitive). IDs of earlier versions, such
+// as "ci.create_secret-3f9a1c2e", are explained by the entry of their kind.
+func (kb *KnowledgeBase) Lookup(id string) (Entry, bool) {
+	id = strings.ToLower(strings.TrimSpace(id))
+	if entry, ok := kb.entries[id]; ok {
+		return entry, true
+	}
+	kind, _ := validation.SplitFindingID(id)
+	entry, ok := kb.entries[kind]
+	return entry, ok
+}
+
+// Suggestions returns the known IDs sharing the category of id, or all IDs when none does
+func (kb *KnowledgeBase) Suggestions(id string) []string {
+	kind, _ := validation.SplitFindingID(strings.TrimSpace(id))
+	category := strings.ToLower(strings.SplitN(kind, ".", 2)[0])
+	var matches []string
+	for _, known := range kb.IDs() {
+		if strings.HasPrefix(known, category+".") {
+			matches = append(matches, known)
+		}
+	}
+	if len(matches) == 0 {
+		return kb.IDs()
+	}
+	return matches
+}
+
+// IDs returns every known finding ID, sorted
+func (kb *KnowledgeBase) IDs() []string {
+	ids := make([]string, 0, len(kb.entries))
+	for id := range kb.entries {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
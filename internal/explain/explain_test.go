@@ -0,0 +1,100 @@
+package explain
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestBuiltinCoversFindingIDs(t *testing.T) {
+	kb, err := Load("")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	// Every ID validation.FindingID can return
+	ids := []string{
+		"apps.install_app", "apps.custom_app", "apps.recreate_webhook", "apps.pages_setup",
+		"access.create_team", "access.idp_team", "access.invite_user", "access.readd_deploy_key",
+		"access.codeowners_update",
+		"ci.create_secret", "ci.create_variable", "ci.configure_runner", "ci.workflow_policy",
+		"ci.pin_update", "ci.create_team", "ci.invite_user", "ci.attestation_verify", "ci.manual_review",
+		"governance.org_policy", "governance.copy_template", "governance.event_sink",
+		"code.code_rewrite", "code.doc_url_rewrite",
+		"security.security_setup",
+	}
+	for _, id := range ids {
+		entry, ok := kb.Lookup(id)
+		if !ok {
+			t.Errorf("no built-in entry for %s", id)
+			continue
+		}
+		if entry.Summary == "" || len(entry.Statuses) == 0 || len(entry.Data) == 0 || len(entry.Remediation) == 0 {
+			t.Errorf("built-in entry %s is incomplete", id)
+		}
+	}
+}
+
+func TestLoadExtension(t *testing.T) {
+	dir := t.TempDir()
+	extension := `
+- id: CI.Create_Secret
+  title: Secret from the vault
+  summary: Our secrets come from the vault.
+- id: custom.license_check
+  title: License check
+`
+	if err := os.WriteFile(filepath.Join(dir, "ours.yaml"), []byte(extension), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		path      string
+		id        string
+		wantTitle string
+		wantOK    bool
+	}{
+		{"built-in", "", "ci.create_secret", "Organization Actions secret used by workflows", true},
+		{"overridden from a directory", dir, "ci.create_secret", "Secret from the vault", true},
+		{"added from a file", filepath.Join(dir, "ours.yaml"), "custom.license_check", "License check", true},
+		{"full finding ID", "", "ci.create_secret-3f9a1c2e", "Organization Actions secret used by workflows", true},
+		{"unknown", dir, "ci.unknown", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kb, err := Load(tt.path)
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			entry, ok := kb.Lookup(tt.id)
+			if ok != tt.wantOK || entry.Title != tt.wantTitle {
+				t.Errorf("Lookup(%s) = %q, %v, want %q, %v", tt.id, entry.Title, ok, tt.wantTitle, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bad.yaml")
+	if err := os.WriteFile(path, []byte("- summary: no id\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Error("Load() accepted an entry without id")
+	}
+}
+
+func TestSuggestions(t *testing.T) {
+	kb, err := Load("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"code.code_rewrite", "code.doc_url_rewrite"}
+	if got := kb.Suggestions("code.rewrite"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Suggestions() = %v, want %v", got, want)
+	}
+	if got := kb.Suggestions("nothing"); len(got) != len(kb.IDs()) {
+		t.Errorf("Suggestions() of an unknown category = %d IDs, want all %d", len(got), len(kb.IDs()))
+	}
+}
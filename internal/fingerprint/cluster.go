@@ -0,0 +1,174 @@
+package fingerprint
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+// ClusterTypes are the dependency types that tie repositories into one migration wave:
+// repositories sharing secrets, runners, actions, teams and apps are easiest to move together
+var ClusterTypes = []string{
+	"ci.organization_secrets",
+	"ci.organization_variables",
+	"ci.self_hosted_runners",
+	"ci.organization_specific_actions",
+	"access.teams",
+	"apps.installed_github_apps",
+}
+
+// Select returns the part of the fingerprint covering the given dependency types
+func (f Fingerprint) Select(dependencyTypes []string) Fingerprint {
+	selected := Fingerprint{}
+	for _, dependencyType := range dependencyTypes {
+		if items := f[dependencyType]; len(items) > 0 {
+			selected[dependencyType] = items
+		}
+	}
+	return selected
+}
+
+// Similarity is the Jaccard index of two sorted key sets; two empty sets are identical
+func Similarity(a, b []string) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+	shared := len(intersect(a, b))
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// Cluster groups repositories whose fingerprints are at least threshold similar to another
+// member of the group (single linkage). Clusters are named cluster-1, cluster-2, ... from the
+// largest to the smallest; ties are ordered by their first repository.
+func Cluster(fingerprints map[string]Fingerprint, threshold float64) []types.RepositoryCluster {
+	repositories := make([]string, 0, len(fingerprints))
+	keys := make(map[string][]string, len(fingerprints))
+	for repository, fingerprint := range fingerprints {
+		repositories = append(repositories, repository)
+		keys[repository] = fingerprint.Select(ClusterTypes).Keys()
+	}
+	sort.Strings(repositories)
+
+	// Union-find over every pair that is similar enough
+	parent := make([]int, len(repositories))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	for i := range repositories {
+		for j := i + 1; j < len(repositories); j++ {
+			if Similarity(keys[repositories[i]], keys[repositories[j]]) >= threshold {
+				parent[find(j)] = find(i)
+			}
+		}
+	}
+
+	groups := make(map[int][]string)
+	for i, repository := range repositories {
+		root := find(i)
+		groups[root] = append(groups[root], repository)
+	}
+
+	var clusters []types.RepositoryCluster
+	for _, members := range groups {
+		shared := keys[members[0]]
+		for _, member := range members[1:] {
+			shared = intersect(shared, keys[member])
+		}
+		clusters = append(clusters, types.RepositoryCluster{Repositories: members, SharedDependencies: shared})
+	}
+	sort.Slice(clusters, func(i, j int) bool {
+		if len(clusters[i].Repositories) != len(clusters[j].Repositories) {
+			return len(clusters[i].Repositories) > len(clusters[j].Repositories)
+		}
+		return clusters[i].Repositories[0] < clusters[j].Repositories[0]
+	})
+	for i := range clusters {
+		clusters[i].Name = fmt.Sprintf("cluster-%d", i+1)
+	}
+	return clusters
+}
+
+// intersect returns the keys present in both sorted slices
+func intersect(a, b []string) []string {
+	var shared []string
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] == b[j]:
+			shared = append(shared, a[i])
+			i++
+			j++
+		case a[i] < b[j]:
+			i++
+		default:
+			j++
+		}
+	}
+	return shared
+}
+
+// AssignClusters clusters the repositories and records each repository's cluster in its report
+func AssignClusters(allDeps []*types.OrganizationalDependencies, threshold float64) []types.RepositoryCluster {
+	fingerprints := make(map[string]Fingerprint, len(allDeps))
+	for _, deps := range allDeps {
+		fingerprints[deps.Repository] = Of(deps)
+	}
+	clusters := Cluster(fingerprints, threshold)
+
+	names := make(map[string]string, len(allDeps))
+	for _, cluster := range clusters {
+		for _, repository := range cluster.Repositories {
+			names[repository] = cluster.Name
+		}
+	}
+	for _, deps := range allDeps {
+		deps.Cluster = names[deps.Repository]
+	}
+	return clusters
+}
+
+// Clusters rebuilds the cluster list from the cluster recorded in each report, e.g. for
+// reports loaded from per-repository files
+func Clusters(allDeps []*types.OrganizationalDependencies) []types.RepositoryCluster {
+	byName := make(map[string]*types.RepositoryCluster)
+	var names []string
+	shared := make(map[string][]string)
+	for _, deps := range allDeps {
+		if deps.Cluster == "" {
+			continue
+		}
+		keys := Of(deps).Select(ClusterTypes).Keys()
+		cluster, ok := byName[deps.Cluster]
+		if !ok {
+			cluster = &types.RepositoryCluster{Name: deps.Cluster}
+			byName[deps.Cluster] = cluster
+			names = append(names, deps.Cluster)
+			shared[deps.Cluster] = keys
+		} else {
+			shared[deps.Cluster] = intersect(shared[deps.Cluster], keys)
+		}
+		cluster.Repositories = append(cluster.Repositories, deps.Repository)
+	}
+
+	var clusters []types.RepositoryCluster
+	for _, name := range names {
+		cluster := byName[name]
+		sort.Strings(cluster.Repositories)
+		cluster.SharedDependencies = shared[name]
+		clusters = append(clusters, *cluster)
+	}
+	sort.Slice(clusters, func(i, j int) bool {
+		if len(clusters[i].Repositories) != len(clusters[j].Repositories) {
+			return len(clusters[i].Repositories) > len(clusters[j].Repositories)
+		}
+		return clusters[i].Repositories[0] < clusters[j].Repositories[0]
+	})
+	return clusters
+}
@@ -0,0 +1,59 @@
+package fingerprint
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b []string
+		want float64
+	}{
+		{nil, nil, 1},
+		{[]string{"a"}, nil, 0},
+		{[]string{"a", "b"}, []string{"a", "b"}, 1},
+		{[]string{"a", "b", "c"}, []string{"b", "c", "d"}, 0.5},
+	}
+
+	for _, tt := range tests {
+		if got := Similarity(tt.a, tt.b); got != tt.want {
+			t.Errorf("Similarity(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestAssignClusters(t *testing.T) {
+	repository := func(name string, secrets, teams []string) *types.OrganizationalDependencies {
+		deps := &types.OrganizationalDependencies{Repository: name}
+		deps.ActionsCIDependencies.OrganizationSecrets = secrets
+		deps.AccessPermissions.Teams = teams
+		return deps
+	}
+	allDeps := []*types.OrganizationalDependencies{
+		repository("acme/web", []string{"NPM_TOKEN"}, []string{"frontend"}),
+		repository("acme/admin", []string{"NPM_TOKEN"}, []string{"frontend"}),
+		repository("acme/docs", []string{"NPM_TOKEN", "ALGOLIA_KEY"}, []string{"frontend"}),
+		repository("acme/ml", []string{"GPU_TOKEN"}, []string{"data"}),
+	}
+
+	clusters := AssignClusters(allDeps, 0.6)
+
+	if len(clusters) != 2 {
+		t.Fatalf("got %d clusters, want 2: %+v", len(clusters), clusters)
+	}
+	if want := []string{"acme/admin", "acme/docs", "acme/web"}; !reflect.DeepEqual(clusters[0].Repositories, want) {
+		t.Errorf("cluster-1 = %v, want %v", clusters[0].Repositories, want)
+	}
+	if want := []string{"access.teams:frontend", "ci.organization_secrets:npm_token"}; !reflect.DeepEqual(clusters[0].SharedDependencies, want) {
+		t.Errorf("shared = %v, want %v", clusters[0].SharedDependencies, want)
+	}
+	if allDeps[3].Cluster != "cluster-2" || allDeps[0].Cluster != "cluster-1" {
+		t.Errorf("assigned clusters %q, %q", allDeps[0].Cluster, allDeps[3].Cluster)
+	}
+	if rebuilt := Clusters(allDeps); !reflect.DeepEqual(rebuilt, clusters) {
+		t.Errorf("Clusters() = %+v, want %+v", rebuilt, clusters)
+	}
+}
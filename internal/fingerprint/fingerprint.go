@@ -0,0 +1,145 @@
+// Package fingerprint reduces a dependency report to comparable sets of dependencies per type,
+// so repositories can be compared with a reference repository or grouped by similarity.
+package fingerprint
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+// Fingerprint maps a dependency type (e.g. "ci.organization_secrets") to its normalized items
+type Fingerprint map[string][]string
+
+// locationSuffix matches the " (in path)" suffix analyzers append to an item
+var locationSuffix = regexp.MustCompile(`\s+\(in [^)]*\)$`)
+
+// Of builds the fingerprint of a dependency report. Items are lowercased, the source
+// location suffix is dropped, and references to the repository's own organization are
+// replaced with "{org}", so a repository already migrated to another organization
+// still matches its siblings.
+func Of(deps *types.OrganizationalDependencies) Fingerprint {
+	owner := strings.ToLower(strings.Split(deps.Repository, "/")[0])
+	fingerprint := Fingerprint{}
+	add := func(dependencyType string, items []string) {
+		for _, item := range items {
+			fingerprint[dependencyType] = append(fingerprint[dependencyType], Normalize(item, owner))
+		}
+	}
+	addPolicies := func(dependencyType string, policies []types.OrgPolicy) {
+		for _, policy := range policies {
+			fingerprint[dependencyType] = append(fingerprint[dependencyType], Normalize(policy.Name, owner))
+		}
+	}
+
+	add("code.internal_repository_references", deps.CodeDependencies.InternalRepositoryReferences)
+	add("code.git_submodules", deps.CodeDependencies.GitSubmodules)
+	add("code.organization_package_registries", deps.CodeDependencies.OrgPackageRegistries)
+	add("code.hardcoded_organization_references", deps.CodeDependencies.HardcodedOrgReferences)
+	add("code.organization_specific_container_registries", deps.CodeDependencies.OrgSpecificContainerRegistries)
+	add("code.documentation_url_references", deps.CodeDependencies.DocumentationURLReferences)
+	add("ci.organization_secrets", deps.ActionsCIDependencies.OrganizationSecrets)
+	add("ci.organization_variables", deps.ActionsCIDependencies.OrganizationVariables)
+	add("ci.self_hosted_runners", deps.ActionsCIDependencies.SelfHostedRunners)
+	add("ci.network_bound_runner_groups", deps.ActionsCIDependencies.NetworkBoundRunnerGroups)
+	add("ci.static_ip_runners", deps.ActionsCIDependencies.StaticIPRunners)
+	add("ci.artifact_attestations", deps.ActionsCIDependencies.ArtifactAttestations)
+	add("ci.environment_dependencies", deps.ActionsCIDependencies.EnvironmentDependencies)
+	add("ci.organization_specific_actions", deps.ActionsCIDependencies.OrgSpecificActions)
+	add("ci.required_workflows", deps.ActionsCIDependencies.RequiredWorkflows)
+	add("ci.cross_repo_workflow_triggers", deps.ActionsCIDependencies.CrossRepoWorkflowTriggers)
+	add("ci.repository_secrets", deps.ActionsCIDependencies.RepositorySecrets)
+	add("ci.repository_variables", deps.ActionsCIDependencies.RepositoryVariables)
+	add("ci.environment_reviewers", deps.ActionsCIDependencies.EnvironmentReviewers)
+	add("access.teams", deps.AccessPermissions.Teams)
+	add("access.individual_collaborators", deps.AccessPermissions.IndividualCollaborators)
+	add("access.organization_roles", deps.AccessPermissions.OrganizationRoles)
+	add("access.codeowners_requirements", deps.AccessPermissions.CodeownersRequirements)
+	add("access.deploy_keys", deps.AccessPermissions.DeployKeys)
+	add("security.security_campaigns", deps.SecurityCompliance.SecurityCampaigns)
+	add("apps.installed_github_apps", deps.AppsIntegrations.InstalledGitHubApps)
+	add("apps.personal_access_tokens", deps.AppsIntegrations.PersonalAccessTokens)
+	add("apps.webhooks", deps.AppsIntegrations.Webhooks)
+	add("apps.pages", deps.AppsIntegrations.Pages)
+	addPolicies("governance.repository_policies", deps.OrgGovernance.RepositoryPolicies)
+	addPolicies("governance.repository_rulesets", deps.OrgGovernance.RepositoryRulesets)
+	add("governance.required_status_checks", deps.OrgGovernance.RequiredStatusChecks)
+
+	for dependencyType, items := range fingerprint {
+		fingerprint[dependencyType] = unique(items)
+	}
+	return fingerprint
+}
+
+// Normalize makes an item comparable across repositories and organizations
+func Normalize(item, owner string) string {
+	normalized := strings.ToLower(strings.TrimSpace(locationSuffix.ReplaceAllString(item, "")))
+	if owner != "" {
+		normalized = strings.ReplaceAll(normalized, owner+"/", "{org}/")
+	}
+	return normalized
+}
+
+// Types returns the dependency types present in the fingerprint, sorted
+func (f Fingerprint) Types() []string {
+	var dependencyTypes []string
+	for dependencyType, items := range f {
+		if len(items) > 0 {
+			dependencyTypes = append(dependencyTypes, dependencyType)
+		}
+	}
+	sort.Strings(dependencyTypes)
+	return dependencyTypes
+}
+
+// Keys returns every dependency as "type:item", sorted
+func (f Fingerprint) Keys() []string {
+	var keys []string
+	for dependencyType, items := range f {
+		for _, item := range items {
+			keys = append(keys, dependencyType+":"+item)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Compare reports the dependencies of candidate that the reference does not have: whole
+// dependency types the reference never needed, and new items of types it already has
+func Compare(candidate, reference Fingerprint, referenceName string) *types.ReferenceComparison {
+	comparison := &types.ReferenceComparison{Reference: referenceName}
+	for _, dependencyType := range candidate.Types() {
+		known := make(map[string]bool, len(reference[dependencyType]))
+		for _, item := range reference[dependencyType] {
+			known[item] = true
+		}
+		if len(known) == 0 {
+			comparison.NovelTypes = append(comparison.NovelTypes, dependencyType)
+		}
+		for _, item := range candidate[dependencyType] {
+			if known[item] {
+				comparison.Shared++
+				continue
+			}
+			if comparison.NovelItems == nil {
+				comparison.NovelItems = make(map[string][]string)
+			}
+			comparison.NovelItems[dependencyType] = append(comparison.NovelItems[dependencyType], item)
+		}
+	}
+	return comparison
+}
+
+// unique sorts items and removes duplicates
+func unique(items []string) []string {
+	sort.Strings(items)
+	result := items[:0]
+	for i, item := range items {
+		if i == 0 || item != items[i-1] {
+			result = append(result, item)
+		}
+	}
+	return result
+}
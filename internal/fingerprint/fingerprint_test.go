@@ -0,0 +1,56 @@
+package fingerprint
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		item  string
+		owner string
+		want  string
+	}{
+		{"NPM_TOKEN (in .github/workflows/ci.yml)", "acme", "npm_token"},
+		{"acme/shared-actions/setup@v2", "acme", "{org}/shared-actions/setup@v2"},
+		{"ghcr.io/acme/base:latest", "acme", "ghcr.io/{org}/base:latest"},
+		{"Platform Team", "", "platform team"},
+	}
+
+	for _, tt := range tests {
+		if got := Normalize(tt.item, tt.owner); got != tt.want {
+			t.Errorf("Normalize(%q, %q) = %q, want %q", tt.item, tt.owner, got, tt.want)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	reference := &types.OrganizationalDependencies{Repository: "new-org/service-a"}
+	reference.ActionsCIDependencies.OrganizationSecrets = []string{"NPM_TOKEN (in .github/workflows/ci.yml)"}
+	reference.ActionsCIDependencies.OrgSpecificActions = []string{"new-org/actions/build@v1"}
+	reference.AccessPermissions.Teams = []string{"platform"}
+
+	candidate := &types.OrganizationalDependencies{Repository: "old-org/service-b"}
+	candidate.ActionsCIDependencies.OrganizationSecrets = []string{"NPM_TOKEN (in .github/workflows/release.yml)", "SONAR_TOKEN"}
+	candidate.ActionsCIDependencies.OrgSpecificActions = []string{"old-org/actions/build@v1"}
+	candidate.AccessPermissions.Teams = []string{"Platform"}
+	candidate.ActionsCIDependencies.SelfHostedRunners = []string{"gpu"}
+
+	comparison := Compare(Of(candidate), Of(reference), reference.Repository)
+
+	if comparison.Shared != 3 {
+		t.Errorf("Shared = %d, want 3", comparison.Shared)
+	}
+	if want := []string{"ci.self_hosted_runners"}; !reflect.DeepEqual(comparison.NovelTypes, want) {
+		t.Errorf("NovelTypes = %v, want %v", comparison.NovelTypes, want)
+	}
+	want := map[string][]string{
+		"ci.organization_secrets": {"sonar_token"},
+		"ci.self_hosted_runners":  {"gpu"},
+	}
+	if !reflect.DeepEqual(comparison.NovelItems, want) {
+		t.Errorf("NovelItems = %v, want %v", comparison.NovelItems, want)
+	}
+}
@@ -0,0 +1,101 @@
+package ghclient
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/cli/go-gh/v2"
+	"github.com/cli/go-gh/v2/pkg/auth"
+)
+
+// GitHubHost is the host of github.com
+const GitHubHost = "github.com"
+
+// host is the GitHub host every client talks to; empty uses GH_HOST or gh's default host
+var host string
+
+// SetHost points the clients created from now on at a GitHub host: github.com, a GitHub
+// Enterprise Server hostname or a GHE.com subdomain (see --hostname). The token is the one gh
+// stores for that host, or GH_ENTERPRISE_TOKEN / GH_TOKEN.
+func SetHost(hostname string) {
+	host = NormalizeHost(hostname)
+}
+
+// Host returns the GitHub host of the run: the one set with SetHost, else GH_HOST, else the
+// host gh is logged in to, else github.com
+func Host() string {
+	if host != "" {
+		return host
+	}
+	defaultHost, _ := auth.DefaultHost()
+	return NormalizeHost(defaultHost)
+}
+
+// NormalizeHost lowercases a hostname and drops a scheme, path and the api. prefix of
+// github.com's API, so "https://GitHub.example.com/" and "github.example.com" compare equal
+func NormalizeHost(hostname string) string {
+	hostname = strings.ToLower(strings.TrimSpace(hostname))
+	hostname = strings.TrimPrefix(strings.TrimPrefix(hostname, "https://"), "http://")
+	hostname = strings.SplitN(hostname, "/", 2)[0]
+	if hostname == "api."+GitHubHost {
+		return GitHubHost
+	}
+	return hostname
+}
+
+// IsGitHubCom reports whether the run talks to github.com rather than GitHub Enterprise Server
+// or a GHE.com data residency host
+func IsGitHubCom() bool {
+	return Host() == GitHubHost
+}
+
+// WebURL returns the web URL of a path on the run's host, e.g. "https://github.com/owner/repo"
+func WebURL(path string) string {
+	return fmt.Sprintf("https://%s/%s", Host(), strings.TrimPrefix(path, "/"))
+}
+
+// RawURL returns the URL prefix raw file contents of a repository ("owner/repo") are served
+// under, ending in a slash: raw.githubusercontent.com on github.com, the repository's /raw/
+// path on other hosts
+func RawURL(path string) string {
+	if IsGitHubCom() {
+		return fmt.Sprintf("https://raw.githubusercontent.com/%s/", path)
+	}
+	return WebURL(path + "/raw/")
+}
+
+// SplitHost splits an optional HOST/ prefix off a repository ("HOST/OWNER/REPO") or an
+// organization ("HOST/ORG"), as gh accepts them; parts is 2 for repositories and 1 for
+// organizations. A prefix must name the run's host: transfers, and the validation preparing
+// them, cannot cross GitHub hosts.
+func SplitHost(value string, parts int) (string, error) {
+	segments := strings.Split(value, "/")
+	if len(segments) != parts+1 {
+		return value, nil
+	}
+	prefix := NormalizeHost(segments[0])
+	if prefix != Host() {
+		return "", fmt.Errorf("%s is on %s but this run uses %s (--hostname): repositories cannot be transferred or validated across GitHub hosts; migrate them with GitHub Enterprise Importer instead", value, prefix, Host())
+	}
+	return strings.Join(segments[1:], "/"), nil
+}
+
+// Command prepares a gh subprocess talking to the host of the run. SetHost only reaches the
+// clients of this process, so the subprocess gets it as GH_HOST; repositories passed with
+// --repo should carry it too (see RepoArg).
+func Command(args ...string) (*exec.Cmd, error) {
+	ghPath, err := gh.Path()
+	if err != nil {
+		return nil, err
+	}
+	command := exec.Command(ghPath, args...)
+	command.Env = append(os.Environ(), "GH_HOST="+Host())
+	return command, nil
+}
+
+// RepoArg returns the --repo argument of a gh subprocess for a repository on the host of the run
+func RepoArg(owner, repo string) string {
+	return fmt.Sprintf("%s/%s/%s", Host(), owner, repo)
+}
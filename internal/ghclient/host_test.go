@@ -0,0 +1,68 @@
+package ghclient
+
+import "testing"
+
+func TestNormalizeHost(t *testing.T) {
+	tests := []struct {
+		hostname, want string
+	}{
+		{"github.com", "github.com"},
+		{"https://GitHub.Example.com/", "github.example.com"},
+		{"api.github.com", "github.com"},
+		{" acme.ghe.com ", "acme.ghe.com"},
+	}
+	for _, tt := range tests {
+		if got := NormalizeHost(tt.hostname); got != tt.want {
+			t.Errorf("NormalizeHost(%q) = %q, want %q", tt.hostname, got, tt.want)
+		}
+	}
+}
+
+func TestHostURLs(t *testing.T) {
+	defer SetHost("")
+
+	SetHost("github.com")
+	if got, want := RawURL("acme/web"), "https://raw.githubusercontent.com/acme/web/"; got != want {
+		t.Errorf("RawURL() = %q, want %q", got, want)
+	}
+	SetHost("ghes.example.com")
+	if got, want := WebURL("acme/web"), "https://ghes.example.com/acme/web"; got != want {
+		t.Errorf("WebURL() = %q, want %q", got, want)
+	}
+	if got, want := RawURL("acme/web"), "https://ghes.example.com/acme/web/raw/"; got != want {
+		t.Errorf("RawURL() = %q, want %q", got, want)
+	}
+}
+
+func TestRepoArg(t *testing.T) {
+	defer SetHost("")
+	SetHost("ghes.example.com")
+	if got, want := RepoArg("acme", "web"), "ghes.example.com/acme/web"; got != want {
+		t.Errorf("RepoArg() = %q, want %q", got, want)
+	}
+}
+
+func TestSplitHost(t *testing.T) {
+	defer SetHost("")
+	SetHost("ghes.example.com")
+
+	tests := []struct {
+		value   string
+		parts   int
+		want    string
+		wantErr bool
+	}{
+		{"acme/web", 2, "acme/web", false},
+		{"GHES.example.com/acme/web", 2, "acme/web", false},
+		{"github.com/acme/web", 2, "", true},
+		{"new-org", 1, "new-org", false},
+		{"ghes.example.com/new-org", 1, "new-org", false},
+		{"acme.ghe.com/new-org", 1, "", true},
+	}
+	for _, tt := range tests {
+		got, err := SplitHost(tt.value, tt.parts)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("SplitHost(%q, %d) = %q, %v, want %q, error %v", tt.value, tt.parts, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+// Package ghclient holds helpers shared by everything that talks to the GitHub REST API,
+// most importantly reading every page of list endpoints
+package ghclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// PageSize is the page size requested from list endpoints (the REST API maximum). Endpoints
+// with a lower maximum, such as Actions variables, return fewer items and more pages.
+const PageSize = 100
+
+// Requester is the part of *api.RESTClient the helpers use
+type Requester interface {
+	Request(method string, path string, body io.Reader) (*http.Response, error)
+}
+
+// GetAll reads every page of a list endpoint that returns a JSON array into out, a pointer to
+// a slice, following the rel="next" links of the Link header. It is the paginated
+// counterpart of client.Get.
+func GetAll(client Requester, path string, out interface{}) error {
+	items, err := sliceOf(out)
+	if err != nil {
+		return err
+	}
+	return eachPage(client, path, func(body []byte) error {
+		page := reflect.New(items.Type())
+		if err := json.Unmarshal(body, page.Interface()); err != nil {
+			return err
+		}
+		items.Set(reflect.AppendSlice(items, page.Elem()))
+		return nil
+	})
+}
+
+// GetAllField reads every page of a list endpoint that wraps its items in an object, e.g.
+// {"total_count": 2, "secrets": [...]}, collecting the items of the given field into out
+func GetAllField(client Requester, path, field string, out interface{}) error {
+	items, err := sliceOf(out)
+	if err != nil {
+		return err
+	}
+	return eachPage(client, path, func(body []byte) error {
+		var page map[string]json.RawMessage
+		if err := json.Unmarshal(body, &page); err != nil {
+			return err
+		}
+		raw, ok := page[field]
+		if !ok {
+			return nil
+		}
+		pageItems := reflect.New(items.Type())
+		if err := json.Unmarshal(raw, pageItems.Interface()); err != nil {
+			return err
+		}
+		items.Set(reflect.AppendSlice(items, pageItems.Elem()))
+		return nil
+	})
+}
+
+// Count returns how many items a list endpoint returns with one request: the page is one item
+// long, so the page number of the rel="last" link is the item count.
+func Count(client Requester, path string) (int, error) {
+	separator := "?"
+	if strings.Contains(path, "?") {
+		separator = "&"
+	}
+	response, err := client.Request(http.MethodGet, path+separator+"per_page=1", nil)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+	if last := lastPage(response.Header.Get("Link")); last > 0 {
+		return last, nil
+	}
+
+	var items []json.RawMessage
+	if err := json.NewDecoder(response.Body).Decode(&items); err != nil {
+		return 0, fmt.Errorf("failed to parse %s: %v", path, err)
+	}
+	return len(items), nil
+}
+
+var lastLinkPattern = regexp.MustCompile(`<([^>]+)>;\s*rel="last"`)
+
+// lastPage returns the page number of the rel="last" link of a Link header, 0 without one
+func lastPage(link string) int {
+	match := lastLinkPattern.FindStringSubmatch(link)
+	if match == nil {
+		return 0
+	}
+	parsed, err := url.Parse(match[1])
+	if err != nil {
+		return 0
+	}
+	page, _ := strconv.Atoi(parsed.Query().Get("page"))
+	return page
+}
+
+// sliceOf returns the (emptied) slice out points to
+func sliceOf(out interface{}) (reflect.Value, error) {
+	value := reflect.ValueOf(out)
+	if value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Slice {
+		return reflect.Value{}, fmt.Errorf("ghclient: expected a pointer to a slice, got %T", out)
+	}
+	items := value.Elem()
+	items.Set(reflect.MakeSlice(items.Type(), 0, 0))
+	return items, nil
+}
+
+// eachPage requests a list endpoint page by page and hands each response body to handle
+func eachPage(client Requester, path string, handle func(body []byte) error) error {
+	next := WithPageSize(path)
+	for next != "" {
+		response, err := client.Request(http.MethodGet, next, nil)
+		if err != nil {
+			return err
+		}
+		body, err := io.ReadAll(response.Body)
+		response.Body.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %v", next, err)
+		}
+		if err := handle(body); err != nil {
+			return fmt.Errorf("failed to parse %s: %v", next, err)
+		}
+		next = NextPage(response.Header.Get("Link"))
+	}
+	return nil
+}
+
+// WithPageSize adds per_page=PageSize to a path that does not set a page size
+func WithPageSize(path string) string {
+	parsed, err := url.Parse(path)
+	if err != nil || parsed.Query().Has("per_page") {
+		return path
+	}
+	separator := "?"
+	if strings.Contains(path, "?") {
+		separator = "&"
+	}
+	return fmt.Sprintf("%s%sper_page=%d", path, separator, PageSize)
+}
+
+var nextLinkPattern = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// NextPage returns the URL of the next page from a Link header ("" on the last page)
+func NextPage(link string) string {
+	match := nextLinkPattern.FindStringSubmatch(link)
+	if match == nil {
+		return ""
+	}
+	return match[1]
+}
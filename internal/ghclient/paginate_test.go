@@ -0,0 +1,155 @@
+package ghclient
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fakeClient serves canned pages by request path
+type fakeClient struct {
+	pages     map[string]string // path -> body
+	links     map[string]string // path -> Link header
+	requested []string
+}
+
+func (f *fakeClient) Request(method string, path string, body io.Reader) (*http.Response, error) {
+	f.requested = append(f.requested, path)
+	content, ok := f.pages[path]
+	if !ok {
+		return nil, fmt.Errorf("HTTP 404: Not Found (%s)", path)
+	}
+	header := http.Header{}
+	if link := f.links[path]; link != "" {
+		header.Set("Link", link)
+	}
+	return &http.Response{StatusCode: 200, Header: header, Body: io.NopCloser(strings.NewReader(content))}, nil
+}
+
+func TestGetAll(t *testing.T) {
+	client := &fakeClient{
+		pages: map[string]string{
+			"orgs/acme/teams?per_page=100":                               `[{"name": "a"}, {"name": "b"}]`,
+			"https://api.github.com/orgs/acme/teams?per_page=100&page=2": `[{"name": "c"}]`,
+		},
+		links: map[string]string{
+			"orgs/acme/teams?per_page=100": `<https://api.github.com/orgs/acme/teams?per_page=100&page=2>; rel="next", <https://api.github.com/orgs/acme/teams?per_page=100&page=2>; rel="last"`,
+		},
+	}
+
+	type team struct {
+		Name string `json:"name"`
+	}
+	var got []team
+	if err := GetAll(client, "orgs/acme/teams", &got); err != nil {
+		t.Fatalf("GetAll() error = %v", err)
+	}
+	want := []team{{"a"}, {"b"}, {"c"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetAll() = %v, want %v", got, want)
+	}
+	if len(client.requested) != 2 {
+		t.Errorf("requested %v, want 2 pages", client.requested)
+	}
+
+	if err := GetAll(client, "orgs/missing/teams", &got); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("GetAll() error = %v, want the 404 to surface", err)
+	}
+}
+
+func TestGetAllField(t *testing.T) {
+	client := &fakeClient{
+		pages: map[string]string{
+			"repos/acme/app/actions/variables?per_page=100":          `{"total_count": 3, "variables": [{"name": "A"}, {"name": "B"}]}`,
+			"https://api.github.com/repositories/1/variables?page=2": `{"total_count": 3, "variables": [{"name": "C"}]}`,
+			"repos/acme/empty/actions/variables?per_page=100":        `{"total_count": 0, "variables": null}`,
+		},
+		links: map[string]string{
+			"repos/acme/app/actions/variables?per_page=100": `<https://api.github.com/repositories/1/variables?page=2>; rel="next"`,
+		},
+	}
+
+	type variable struct {
+		Name string `json:"name"`
+	}
+	var got []variable
+	if err := GetAllField(client, "repos/acme/app/actions/variables", "variables", &got); err != nil {
+		t.Fatalf("GetAllField() error = %v", err)
+	}
+	if want := []variable{{"A"}, {"B"}, {"C"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetAllField() = %v, want %v", got, want)
+	}
+
+	var empty []variable
+	if err := GetAllField(client, "repos/acme/empty/actions/variables", "variables", &empty); err != nil || len(empty) != 0 {
+		t.Errorf("GetAllField() = %v, %v; want no items", empty, err)
+	}
+	if err := GetAllField(client, "repos/acme/empty/actions/variables", "variables", empty); err == nil {
+		t.Errorf("GetAllField() accepted a non-pointer")
+	}
+}
+
+func TestWithPageSize(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"orgs/acme/repos", "orgs/acme/repos?per_page=100"},
+		{"repos/acme/app/rulesets?includes_parents=false", "repos/acme/app/rulesets?includes_parents=false&per_page=100"},
+		{"orgs/acme/actions/variables?per_page=30", "orgs/acme/actions/variables?per_page=30"},
+	}
+	for _, tt := range tests {
+		if got := WithPageSize(tt.path); got != tt.want {
+			t.Errorf("WithPageSize(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestNextPage(t *testing.T) {
+	tests := []struct {
+		name string
+		link string
+		want string
+	}{
+		{"none", "", ""},
+		{"next and last", `<https://api.github.com/x?page=2>; rel="next", <https://api.github.com/x?page=5>; rel="last"`, "https://api.github.com/x?page=2"},
+		{"last page", `<https://api.github.com/x?page=1>; rel="prev", <https://api.github.com/x?page=1>; rel="first"`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NextPage(tt.link); got != tt.want {
+				t.Errorf("NextPage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCount(t *testing.T) {
+	client := &fakeClient{
+		pages: map[string]string{
+			"repos/acme/api/pulls?state=open&per_page=1":  `[{"number": 9}]`,
+			"repos/acme/web/pulls?state=open&per_page=1":  `[{"number": 3}]`,
+			"repos/acme/docs/pulls?state=open&per_page=1": `[]`,
+		},
+		links: map[string]string{
+			"repos/acme/api/pulls?state=open&per_page=1": `<https://api.github.com/repositories/1/pulls?state=open&per_page=1&page=2>; rel="next", <https://api.github.com/repositories/1/pulls?state=open&per_page=1&page=42>; rel="last"`,
+		},
+	}
+
+	tests := []struct {
+		repo string
+		want int
+	}{
+		{"api", 42},
+		{"web", 1},
+		{"docs", 0},
+	}
+	for _, tt := range tests {
+		if got, err := Count(client, "repos/acme/"+tt.repo+"/pulls?state=open"); err != nil || got != tt.want {
+			t.Errorf("Count(%s) = %d, %v; want %d", tt.repo, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package ghclient
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// OtherAnalyzer collects the requests of a profiled run that no analyzer made, e.g. validation
+// and the transfer itself
+const OtherAnalyzer = "other"
+
+// Profile counts the API requests, the time spent in them and the rate limit points they cost
+// per repository and analyzer (see --profile)
+type Profile struct {
+	base http.RoundTripper
+
+	mutex   sync.Mutex
+	entries []*ProfileEntry // In the order they were first used
+}
+
+// ProfileEntry is what one analyzer consumed for one repository
+type ProfileEntry struct {
+	Repository string // Empty for requests outside of an analysis
+	Analyzer   string
+	Requests   int
+	Duration   time.Duration // Summed over the requests, including rate limit waits
+	Points     int           // Rate limit points, see requestPoints
+}
+
+// NewProfile counts the requests sent through base
+func NewProfile(base http.RoundTripper) *Profile {
+	return &Profile{base: base}
+}
+
+// activeProfile is the profile of the run, nil unless profiling was started
+var activeProfile *Profile
+
+// StartProfile profiles every client created from now on; clients handed to an analyzer are
+// attributed with Profiled, all others count as OtherAnalyzer
+func StartProfile() *Profile {
+	activeProfile = NewProfile(sharedTransport)
+	return activeProfile
+}
+
+// ActiveProfile returns the profile of the run, or nil when profiling is off
+func ActiveProfile() *Profile {
+	return activeProfile
+}
+
+// Profiled returns a client whose requests count towards an analyzer of a repository in the
+// active profile. Without profiling, client itself is returned.
+func Profiled(client api.RESTClient, repository, analyzer string) api.RESTClient {
+	if activeProfile == nil {
+		return client
+	}
+	profiled, err := api.NewRESTClient(api.ClientOptions{Host: Host(), Transport: activeProfile.Transport(repository, analyzer)})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: could not profile %s of %s: %v\n", analyzer, repository, err)
+		return client
+	}
+	return *profiled
+}
+
+// ProfiledGraphQL is Profiled for GraphQL clients
+func ProfiledGraphQL(client *api.GraphQLClient, repository, analyzer string) *api.GraphQLClient {
+	if activeProfile == nil {
+		return client
+	}
+	profiled, err := api.NewGraphQLClient(api.ClientOptions{Host: Host(), Transport: activeProfile.Transport(repository, analyzer)})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: could not profile %s of %s: %v\n", analyzer, repository, err)
+		return client
+	}
+	return profiled
+}
+
+// clientTransport is the transport of new clients: the shared rate limiter, counted as
+// OtherAnalyzer while profiling
+func clientTransport() http.RoundTripper {
+	if activeProfile != nil {
+		return activeProfile.Transport("", OtherAnalyzer)
+	}
+	return sharedTransport
+}
+
+// Transport returns a transport counting its requests towards an analyzer of a repository
+func (p *Profile) Transport(repository, analyzer string) http.RoundTripper {
+	return &profileTransport{profile: p, entry: p.entry(repository, analyzer)}
+}
+
+// Entries returns a copy of the counts, in the order the analyzers were first used
+func (p *Profile) Entries() []ProfileEntry {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	entries := make([]ProfileEntry, 0, len(p.entries))
+	for _, entry := range p.entries {
+		if entry.Requests > 0 {
+			entries = append(entries, *entry)
+		}
+	}
+	return entries
+}
+
+// entry returns the counts of an analyzer of a repository, adding them when missing
+func (p *Profile) entry(repository, analyzer string) *ProfileEntry {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	for _, entry := range p.entries {
+		if entry.Repository == repository && entry.Analyzer == analyzer {
+			return entry
+		}
+	}
+	entry := &ProfileEntry{Repository: repository, Analyzer: analyzer}
+	p.entries = append(p.entries, entry)
+	return entry
+}
+
+// profileTransport counts the requests of one analyzer of one repository
+type profileTransport struct {
+	profile *Profile
+	entry   *ProfileEntry
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *profileTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	start := time.Now()
+	response, err := t.profile.base.RoundTrip(request)
+	elapsed := time.Since(start)
+
+	t.profile.mutex.Lock()
+	defer t.profile.mutex.Unlock()
+	t.entry.Requests++
+	t.entry.Duration += elapsed
+	if err == nil {
+		t.entry.Points += requestPoints(response)
+	}
+	return response, err
+}
+
+// requestPoints estimates the rate limit points a response cost: one per request, except
+// 304 Not Modified answers to conditional requests, which GitHub does not count. GraphQL
+// queries count with their minimum cost of one point.
+func requestPoints(response *http.Response) int {
+	if response.StatusCode == http.StatusNotModified {
+		return 0
+	}
+	return 1
+}
@@ -0,0 +1,56 @@
+package ghclient
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestProfile(t *testing.T) {
+	base := &cannedTransport{responses: []*http.Response{
+		cannedResponse(200, "[]"),
+		cannedResponse(304, ""),
+		cannedResponse(404, `{"message":"Not Found"}`),
+		cannedResponse(200, `{"data":{}}`),
+		cannedResponse(200, "{}"),
+	}}
+	profile := NewProfile(base)
+	code := profile.Transport("acme/web", "code")
+	requests := []struct {
+		transport http.RoundTripper
+		url       string
+	}{
+		{code, "https://api.github.com/repos/acme/web/contents/go.mod"},
+		{code, "https://api.github.com/repos/acme/web/contents/go.mod"},
+		{profile.Transport("acme/web", "security"), "https://api.github.com/repos/acme/web/code-scanning/alerts"},
+		{profile.Transport("acme", "graphql-scan"), "https://api.github.com/graphql"},
+		{profile.Transport("acme/web", "code"), "https://api.github.com/repos/acme/web/contents/package.json"},
+	}
+	for _, r := range requests {
+		request, _ := http.NewRequest(http.MethodGet, r.url, nil)
+		if _, err := r.transport.RoundTrip(request); err != nil {
+			t.Fatalf("RoundTrip(%s) error = %v", r.url, err)
+		}
+	}
+	profile.Transport("acme/api", "apps") // Never used, not reported
+
+	tests := []struct {
+		repository   string
+		analyzer     string
+		wantRequests int
+		wantPoints   int
+	}{
+		{"acme/web", "code", 3, 2}, // The 304 answer costs no point
+		{"acme/web", "security", 1, 1},
+		{"acme", "graphql-scan", 1, 1},
+	}
+	entries := profile.Entries()
+	if len(entries) != len(tests) {
+		t.Fatalf("Entries() = %+v, want %d entries", entries, len(tests))
+	}
+	for i, tt := range tests {
+		entry := entries[i]
+		if entry.Repository != tt.repository || entry.Analyzer != tt.analyzer || entry.Requests != tt.wantRequests || entry.Points != tt.wantPoints {
+			t.Errorf("entry %d = %+v, want %s/%s with %d requests and %d points", i, entry, tt.repository, tt.analyzer, tt.wantRequests, tt.wantPoints)
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package ghclient
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// MaxRateLimitRetries is how often a rate-limited request is retried before its response is returned
+var MaxRateLimitRetries = 5
+
+// RateLimitBackoff is the wait before the first retry of a secondary rate limit that does not say
+// how long to wait; it doubles with every attempt up to maxRateLimitBackoff
+var RateLimitBackoff = 30 * time.Second
+
+const maxRateLimitBackoff = 5 * time.Minute
+
+// RateLimitTransport retries requests GitHub rejects for exceeding a rate limit (429, or 403
+// with an exhausted budget or a secondary rate limit message), waiting for Retry-After, the
+// budget reset or an exponential backoff. It tracks the remaining budget per resource (core,
+// graphql, search) from the X-RateLimit headers, holds requests back while a budget is
+// exhausted, and reports the budget in verbose mode.
+type RateLimitTransport struct {
+	Base    http.RoundTripper
+	Verbose bool
+
+	sleep func(time.Duration)
+	now   func() time.Time
+
+	mutex   sync.Mutex
+	budgets map[string]*rateBudget
+}
+
+// rateBudget is the last known budget of one rate limit resource
+type rateBudget struct {
+	limit     int
+	remaining int
+	reset     time.Time
+	reported  int // Tenth of the limit last reported in verbose mode
+}
+
+// NewRateLimitTransport wraps base (http.DefaultTransport when nil)
+func NewRateLimitTransport(base http.RoundTripper, verbose bool) *RateLimitTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &RateLimitTransport{
+		Base:    base,
+		Verbose: verbose,
+		sleep:   time.Sleep,
+		now:     time.Now,
+		budgets: make(map[string]*rateBudget),
+	}
+}
+
+// sharedTransport is used by every client, since all requests of a run count against the
+// same budgets of the token
+var sharedTransport = NewRateLimitTransport(nil, false)
+
+// SetVerbose turns the budget reports of the shared rate limiter on or off
+func SetVerbose(verbose bool) {
+	sharedTransport.mutex.Lock()
+	sharedTransport.Verbose = verbose
+	sharedTransport.mutex.Unlock()
+}
+
+// NewRESTClient returns a REST client for the run's host (see Host) and its token whose requests
+// go through the shared rate limiter. It replaces api.DefaultRESTClient; a gh http_unix_socket
+// is not used.
+func NewRESTClient() (*api.RESTClient, error) {
+	return api.NewRESTClient(api.ClientOptions{Host: Host(), Transport: clientTransport()})
+}
+
+// NewRESTClientAccepting returns a REST client like NewRESTClient that asks for the given media
+// type, e.g. application/vnd.github.v3.repository+json
+func NewRESTClientAccepting(mediaType string) (*api.RESTClient, error) {
+	return api.NewRESTClient(api.ClientOptions{Host: Host(), Transport: clientTransport(), Headers: map[string]string{"Accept": mediaType}})
+}
+
+// NewGraphQLClient returns a GraphQL client whose requests go through the shared rate limiter
+func NewGraphQLClient() (*api.GraphQLClient, error) {
+	return api.NewGraphQLClient(api.ClientOptions{Host: Host(), Transport: clientTransport()})
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *RateLimitTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	resource := requestResource(request)
+	// A body that cannot be read again rules out retrying
+	replayable := request.Body == nil || request.Body == http.NoBody || request.GetBody != nil
+
+	for attempt := 0; ; attempt++ {
+		t.waitForBudget(resource)
+
+		attemptRequest := request
+		if attempt > 0 && request.GetBody != nil {
+			body, err := request.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			attemptRequest = request.Clone(request.Context())
+			attemptRequest.Body = body
+		}
+
+		response, err := t.Base.RoundTrip(attemptRequest)
+		if err != nil {
+			return nil, err
+		}
+		t.record(response)
+
+		wait, limited := t.retryAfter(response, attempt)
+		if !limited || !replayable || attempt >= MaxRateLimitRetries {
+			return response, nil
+		}
+		io.Copy(io.Discard, response.Body)
+		response.Body.Close()
+
+		fmt.Fprintf(os.Stderr, "⏳ GitHub rate limit hit (%s %s, HTTP %d), retrying in %s (%d/%d)\n",
+			request.Method, request.URL.Path, response.StatusCode, wait.Round(time.Second), attempt+1, MaxRateLimitRetries)
+		t.sleep(wait)
+	}
+}
+
+// requestResource guesses the rate limit resource a request counts against
+func requestResource(request *http.Request) string {
+	path := request.URL.Path
+	switch {
+	case strings.HasSuffix(path, "/graphql"):
+		return "graphql"
+	case strings.HasPrefix(path, "/search/") || strings.Contains(path, "/api/v3/search/"):
+		return "search"
+	default:
+		return "core"
+	}
+}
+
+// waitForBudget holds a request back until the reset while the resource's budget is exhausted
+func (t *RateLimitTransport) waitForBudget(resource string) {
+	t.mutex.Lock()
+	budget := t.budgets[resource]
+	var wait time.Duration
+	if budget != nil && budget.remaining == 0 {
+		wait = budget.reset.Sub(t.now()) + time.Second
+	}
+	t.mutex.Unlock()
+
+	if wait > 0 {
+		fmt.Fprintf(os.Stderr, "⏳ GitHub %s API budget exhausted, waiting %s for the reset\n", resource, wait.Round(time.Second))
+		t.sleep(wait)
+	}
+}
+
+// record keeps the budget reported by the X-RateLimit headers of a response
+func (t *RateLimitTransport) record(response *http.Response) {
+	limit, errLimit := strconv.Atoi(response.Header.Get("X-RateLimit-Limit"))
+	remaining, errRemaining := strconv.Atoi(response.Header.Get("X-RateLimit-Remaining"))
+	if errLimit != nil || errRemaining != nil || limit <= 0 {
+		return
+	}
+	resource := response.Header.Get("X-RateLimit-Resource")
+	if resource == "" {
+		resource = requestResource(response.Request)
+	}
+	reset := t.now()
+	if seconds, err := strconv.ParseInt(response.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		reset = time.Unix(seconds, 0)
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	budget, ok := t.budgets[resource]
+	if !ok {
+		budget = &rateBudget{reported: 11}
+		t.budgets[resource] = budget
+	}
+	if reset.After(budget.reset) {
+		// A new window started; report its budget again
+		budget.reported = 11
+	}
+	budget.limit, budget.remaining, budget.reset = limit, remaining, reset
+
+	if tenth := remaining * 10 / limit; t.Verbose && tenth < budget.reported {
+		budget.reported = tenth
+		fmt.Fprintf(os.Stderr, "📉 GitHub %s API budget: %d/%d requests left, resets at %s\n",
+			resource, remaining, limit, reset.Local().Format("15:04:05"))
+	}
+}
+
+// retryAfter reports whether a response is a rate limit rejection and how long to wait
+func (t *RateLimitTransport) retryAfter(response *http.Response, attempt int) (time.Duration, bool) {
+	if response.StatusCode != http.StatusTooManyRequests && response.StatusCode != http.StatusForbidden {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(response.Header.Get("Retry-After")); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if response.Header.Get("X-RateLimit-Remaining") == "0" {
+		if seconds, err := strconv.ParseInt(response.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			wait := time.Unix(seconds, 0).Sub(t.now()) + time.Second
+			if wait < time.Second {
+				wait = time.Second
+			}
+			return wait, true
+		}
+		return backoff(attempt), true
+	}
+	if response.StatusCode == http.StatusTooManyRequests {
+		return backoff(attempt), true
+	}
+
+	// Secondary rate limits answer 403 with a message; other 403s are permission errors
+	body, err := io.ReadAll(response.Body)
+	response.Body.Close()
+	response.Body = io.NopCloser(bytes.NewReader(body))
+	if err == nil && strings.Contains(strings.ToLower(string(body)), "rate limit") {
+		return backoff(attempt), true
+	}
+	return 0, false
+}
+
+func backoff(attempt int) time.Duration {
+	wait := RateLimitBackoff << attempt
+	if wait > maxRateLimitBackoff || wait <= 0 {
+		wait = maxRateLimitBackoff
+	}
+	return wait
+}
@@ -0,0 +1,128 @@
+package ghclient
+
+import (
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// cannedTransport answers requests with the queued responses in order
+type cannedTransport struct {
+	responses []*http.Response
+	bodies    []string // Request bodies received
+}
+
+func (c *cannedTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	if request.Body != nil {
+		body, _ := io.ReadAll(request.Body)
+		c.bodies = append(c.bodies, string(body))
+	}
+	response := c.responses[0]
+	c.responses = c.responses[1:]
+	response.Request = request
+	return response, nil
+}
+
+func cannedResponse(status int, body string, headers ...string) *http.Response {
+	header := http.Header{}
+	for i := 0; i+1 < len(headers); i += 2 {
+		header.Set(headers[i], headers[i+1])
+	}
+	return &http.Response{StatusCode: status, Header: header, Body: io.NopCloser(strings.NewReader(body))}
+}
+
+func TestRateLimitTransport(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	reset := strconv.FormatInt(now.Add(90*time.Second).Unix(), 10)
+
+	tests := []struct {
+		name       string
+		responses  []*http.Response
+		wantStatus int
+		wantWaits  []time.Duration
+	}{
+		{"success", []*http.Response{cannedResponse(200, "{}")}, 200, nil},
+		{"permission error is not retried", []*http.Response{cannedResponse(403, `{"message":"Resource not accessible by integration"}`)}, 403, nil},
+		{"retry after", []*http.Response{
+			cannedResponse(429, "", "Retry-After", "7"),
+			cannedResponse(200, "{}"),
+		}, 200, []time.Duration{7 * time.Second}},
+		{"exhausted budget waits for the reset", []*http.Response{
+			cannedResponse(403, `{"message":"API rate limit exceeded"}`, "X-RateLimit-Limit", "5000", "X-RateLimit-Remaining", "0", "X-RateLimit-Reset", reset),
+			cannedResponse(200, "{}"),
+		}, 200, []time.Duration{91 * time.Second}},
+		{"secondary rate limit backs off exponentially", []*http.Response{
+			cannedResponse(403, `{"message":"You have exceeded a secondary rate limit"}`),
+			cannedResponse(403, `{"message":"You have exceeded a secondary rate limit"}`),
+			cannedResponse(201, "{}"),
+		}, 201, []time.Duration{time.Second, 2 * time.Second}},
+		{"gives up after the retries", []*http.Response{
+			cannedResponse(429, ""), cannedResponse(429, ""), cannedResponse(429, ""),
+		}, 429, []time.Duration{time.Second, 2 * time.Second}},
+	}
+
+	defer func(retries int, base time.Duration) {
+		MaxRateLimitRetries, RateLimitBackoff = retries, base
+	}(MaxRateLimitRetries, RateLimitBackoff)
+	MaxRateLimitRetries, RateLimitBackoff = 2, time.Second
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := &cannedTransport{responses: tt.responses}
+			transport := NewRateLimitTransport(base, false)
+			var waits []time.Duration
+			clock := now
+			transport.sleep = func(d time.Duration) {
+				waits = append(waits, d)
+				clock = clock.Add(d)
+			}
+			transport.now = func() time.Time { return clock }
+
+			request, _ := http.NewRequest(http.MethodPost, "https://api.github.com/repos/o/r/labels", strings.NewReader(`{"name":"x"}`))
+			response, err := transport.RoundTrip(request)
+			if err != nil {
+				t.Fatalf("RoundTrip() error = %v", err)
+			}
+			if response.StatusCode != tt.wantStatus {
+				t.Errorf("RoundTrip() status = %d, want %d", response.StatusCode, tt.wantStatus)
+			}
+			if !reflect.DeepEqual(waits, tt.wantWaits) {
+				t.Errorf("RoundTrip() waited %v, want %v", waits, tt.wantWaits)
+			}
+			for _, body := range base.bodies {
+				if body != `{"name":"x"}` {
+					t.Errorf("request body sent as %q on a retry", body)
+				}
+			}
+			if tt.wantStatus == 403 {
+				// The body of a response that is not retried can still be read
+				if body, _ := io.ReadAll(response.Body); !strings.Contains(string(body), "Resource not accessible") {
+					t.Errorf("response body = %q", body)
+				}
+			}
+		})
+	}
+}
+
+func TestRequestResource(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"https://api.github.com/graphql", "graphql"},
+		{"https://ghes.example.com/api/graphql", "graphql"},
+		{"https://api.github.com/search/code?q=x", "search"},
+		{"https://ghes.example.com/api/v3/search/code?q=x", "search"},
+		{"https://api.github.com/repos/o/r", "core"},
+	}
+	for _, tt := range tests {
+		request, _ := http.NewRequest(http.MethodGet, tt.url, nil)
+		if got := requestResource(request); got != tt.want {
+			t.Errorf("requestResource(%s) = %s, want %s", tt.url, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,313 @@
+// Package graphqlscan reads the metadata, branch protection rules, teams and collaborators of
+// many repositories with a few GraphQL queries instead of several REST requests per repository.
+// It backs the --graphql batch analysis; anything a query could not read completely is left to
+// the REST analyzers.
+package graphqlscan
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+
+	"github.com/jefeish/gh-repo-transfer/internal/dependencies"
+)
+
+// ChunkSize is the number of repositories read per query. Each repository asks for up to 100
+// collaborators and branch protection rules, which keeps a query well below the API's node limit.
+const ChunkSize = 25
+
+// connectionSize is the page size of the nested connections (the GraphQL maximum)
+const connectionSize = 100
+
+// Querier is the part of *api.GraphQLClient the scan uses
+type Querier interface {
+	Do(query string, variables map[string]interface{}, response interface{}) error
+}
+
+// Repository is what the scan read about one repository
+type Repository struct {
+	NameWithOwner string
+	Found         bool // False when the repository does not exist or is not visible
+	Visibility    string
+	IsArchived    bool
+	DefaultBranch string
+
+	Access dependencies.PrefetchedAccess
+
+	BranchProtectionRules    []dependencies.BranchProtectionRule
+	BranchProtectionComplete bool
+}
+
+// Scan reads the repositories (names without the owner) of one organization. The result is
+// keyed by lower-cased repository name.
+func Scan(client Querier, owner string, repos []string) (map[string]*Repository, error) {
+	scanned := make(map[string]*Repository, len(repos))
+	for start := 0; start < len(repos); start += ChunkSize {
+		end := start + ChunkSize
+		if end > len(repos) {
+			end = len(repos)
+		}
+		if err := scanChunk(client, owner, repos[start:end], scanned); err != nil {
+			return nil, err
+		}
+	}
+
+	teamGrants, complete, err := scanTeams(client, owner)
+	if err != nil {
+		return nil, err
+	}
+	for name, repository := range scanned {
+		repository.Access.Teams = teamGrants[name]
+		repository.Access.TeamsComplete = complete && repository.Found
+	}
+	return scanned, nil
+}
+
+// BuildRepositoriesQuery returns a query reading the repositories under the aliases r0, r1, ...
+// with their names passed as the variables $r0, $r1, ...
+func BuildRepositoriesQuery(count int) string {
+	var query strings.Builder
+	query.WriteString("query($owner: String!")
+	for i := 0; i < count; i++ {
+		fmt.Fprintf(&query, ", $r%d: String!", i)
+	}
+	query.WriteString(") {\n")
+	for i := 0; i < count; i++ {
+		fmt.Fprintf(&query, "  r%d: repository(owner: $owner, name: $r%d) { ...repositoryFields }\n", i, i)
+	}
+	query.WriteString("}\n")
+	fmt.Fprintf(&query, `fragment repositoryFields on Repository {
+  nameWithOwner
+  visibility
+  isArchived
+  defaultBranchRef { name }
+  collaborators(first: %d) {
+    pageInfo { hasNextPage }
+    edges { permission node { login } }
+  }
+  branchProtectionRules(first: %d) {
+    pageInfo { hasNextPage }
+    nodes {
+      pattern
+      requiredApprovingReviewCount
+      requiresCodeOwnerReviews
+      isAdminEnforced
+      requiresLinearHistory
+      requiresCommitSignatures
+      allowsForcePushes
+      requiresStatusChecks
+    }
+  }
+}
+`, connectionSize, connectionSize)
+	return query.String()
+}
+
+// repositoryNode is the JSON shape of repositoryFields
+type repositoryNode struct {
+	NameWithOwner    string `json:"nameWithOwner"`
+	Visibility       string `json:"visibility"`
+	IsArchived       bool   `json:"isArchived"`
+	DefaultBranchRef *struct {
+		Name string `json:"name"`
+	} `json:"defaultBranchRef"`
+	Collaborators *struct {
+		PageInfo pageInfo `json:"pageInfo"`
+		Edges    []struct {
+			Permission string `json:"permission"`
+			Node       struct {
+				Login string `json:"login"`
+			} `json:"node"`
+		} `json:"edges"`
+	} `json:"collaborators"`
+	BranchProtectionRules *struct {
+		PageInfo pageInfo `json:"pageInfo"`
+		Nodes    []struct {
+			Pattern                      string `json:"pattern"`
+			RequiredApprovingReviewCount int    `json:"requiredApprovingReviewCount"`
+			RequiresCodeOwnerReviews     bool   `json:"requiresCodeOwnerReviews"`
+			IsAdminEnforced              bool   `json:"isAdminEnforced"`
+			RequiresLinearHistory        bool   `json:"requiresLinearHistory"`
+			RequiresCommitSignatures     bool   `json:"requiresCommitSignatures"`
+			AllowsForcePushes            bool   `json:"allowsForcePushes"`
+			RequiresStatusChecks         bool   `json:"requiresStatusChecks"`
+		} `json:"nodes"`
+	} `json:"branchProtectionRules"`
+}
+
+type pageInfo struct {
+	HasNextPage bool   `json:"hasNextPage"`
+	EndCursor   string `json:"endCursor"`
+}
+
+// scanChunk reads up to ChunkSize repositories with one query
+func scanChunk(client Querier, owner string, repos []string, scanned map[string]*Repository) error {
+	variables := map[string]interface{}{"owner": owner}
+	for i, repo := range repos {
+		variables[fmt.Sprintf("r%d", i)] = repo
+	}
+
+	var response map[string]*repositoryNode
+	err := client.Do(BuildRepositoriesQuery(len(repos)), variables, &response)
+	if err := partialErrors(err); err != nil {
+		return err
+	}
+
+	for i, repo := range repos {
+		scanned[strings.ToLower(repo)] = repositoryFrom(owner, repo, response[fmt.Sprintf("r%d", i)])
+	}
+	return nil
+}
+
+// partialErrors drops the errors GraphQL reports next to partial data: a repository that is not
+// found, or a field the token may not read (e.g. collaborators without push access), comes back
+// as null and is handled per repository
+func partialErrors(err error) error {
+	var graphQLErr *api.GraphQLError
+	if err == nil || !errors.As(err, &graphQLErr) {
+		return err
+	}
+	for _, item := range graphQLErr.Errors {
+		if len(item.Path) == 0 {
+			return err
+		}
+	}
+	return nil
+}
+
+// repositoryFrom converts a repository node; nil means the repository was not found
+func repositoryFrom(owner, repo string, node *repositoryNode) *Repository {
+	repository := &Repository{NameWithOwner: fmt.Sprintf("%s/%s", owner, repo)}
+	if node == nil {
+		return repository
+	}
+	repository.Found = true
+	repository.NameWithOwner = node.NameWithOwner
+	repository.Visibility = strings.ToLower(node.Visibility)
+	repository.IsArchived = node.IsArchived
+	if node.DefaultBranchRef != nil {
+		repository.DefaultBranch = node.DefaultBranchRef.Name
+	}
+
+	if node.Collaborators != nil && !node.Collaborators.PageInfo.HasNextPage {
+		repository.Access.CollaboratorsComplete = true
+		for _, edge := range node.Collaborators.Edges {
+			repository.Access.Collaborators = append(repository.Access.Collaborators, dependencies.AccessGrant{
+				Name:       edge.Node.Login,
+				Permission: strings.ToLower(edge.Permission),
+			})
+		}
+	}
+
+	if node.BranchProtectionRules != nil && !node.BranchProtectionRules.PageInfo.HasNextPage {
+		repository.BranchProtectionComplete = true
+		for _, rule := range node.BranchProtectionRules.Nodes {
+			repository.BranchProtectionRules = append(repository.BranchProtectionRules, dependencies.BranchProtectionRule{
+				Pattern:                      rule.Pattern,
+				RequiredApprovingReviewCount: rule.RequiredApprovingReviewCount,
+				RequiresCodeOwnerReviews:     rule.RequiresCodeOwnerReviews,
+				IsAdminEnforced:              rule.IsAdminEnforced,
+				RequiresLinearHistory:        rule.RequiresLinearHistory,
+				RequiresCommitSignatures:     rule.RequiresCommitSignatures,
+				AllowsForcePushes:            rule.AllowsForcePushes,
+				RequiresStatusChecks:         rule.RequiresStatusChecks,
+			})
+		}
+	}
+	return repository
+}
+
+// teamsQuery reads a page of the organization's teams with the repositories they can access
+var teamsQuery = fmt.Sprintf(`query($owner: String!, $after: String) {
+  organization(login: $owner) {
+    teams(first: %d, after: $after) {
+      pageInfo { hasNextPage endCursor }
+      nodes {
+        name
+        slug
+        repositories(first: %d) {
+          pageInfo { hasNextPage }
+          edges { permission node { name } }
+        }
+      }
+    }
+  }
+}`, connectionSize, connectionSize)
+
+type teamsResponse struct {
+	Organization *struct {
+		Teams struct {
+			PageInfo pageInfo `json:"pageInfo"`
+			Nodes    []struct {
+				Name         string `json:"name"`
+				Slug         string `json:"slug"`
+				Repositories struct {
+					PageInfo pageInfo `json:"pageInfo"`
+					Edges    []struct {
+						Permission string `json:"permission"`
+						Node       struct {
+							Name string `json:"name"`
+						} `json:"node"`
+					} `json:"edges"`
+				} `json:"repositories"`
+			} `json:"nodes"`
+		} `json:"teams"`
+	} `json:"organization"`
+}
+
+// scanTeams maps the organization's repositories (lower-cased names) to the teams with access.
+// Repositories have no teams connection, so the teams are read from the organization side;
+// complete is false when a team has more repositories than one page, or owner is a user.
+func scanTeams(client Querier, owner string) (grants map[string][]dependencies.AccessGrant, complete bool, err error) {
+	grants = make(map[string][]dependencies.AccessGrant)
+	complete = true
+	variables := map[string]interface{}{"owner": owner, "after": nil}
+	for {
+		var response teamsResponse
+		if err := partialErrors(client.Do(teamsQuery, variables, &response)); err != nil {
+			return nil, false, err
+		}
+		if response.Organization == nil {
+			return grants, false, nil
+		}
+		teams := response.Organization.Teams
+		for _, team := range teams.Nodes {
+			if team.Repositories.PageInfo.HasNextPage {
+				complete = false
+			}
+			for _, edge := range team.Repositories.Edges {
+				name := strings.ToLower(edge.Node.Name)
+				grants[name] = append(grants[name], dependencies.AccessGrant{
+					Name:       team.Name,
+					Slug:       team.Slug,
+					Permission: TeamPermission(edge.Permission),
+				})
+			}
+		}
+		if !teams.PageInfo.HasNextPage {
+			break
+		}
+		variables["after"] = teams.PageInfo.EndCursor
+	}
+
+	for name := range grants {
+		sort.SliceStable(grants[name], func(i, j int) bool { return grants[name][i].Name < grants[name][j].Name })
+	}
+	return grants, complete, nil
+}
+
+// TeamPermission converts a GraphQL repository permission to the name the REST teams endpoint
+// reports (READ is pull, WRITE is push)
+func TeamPermission(permission string) string {
+	switch strings.ToUpper(permission) {
+	case "READ":
+		return "pull"
+	case "WRITE":
+		return "push"
+	default:
+		return strings.ToLower(permission)
+	}
+}
@@ -0,0 +1,164 @@
+package graphqlscan
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+
+	"github.com/jefeish/gh-repo-transfer/internal/dependencies"
+)
+
+// fakeQuerier answers repository queries from canned nodes and teams queries from canned pages
+type fakeQuerier struct {
+	repositories map[string]string // repository name -> node JSON (missing: not found)
+	teamPages    []string          // teams responses, one per page
+	queries      int
+}
+
+func (f *fakeQuerier) Do(query string, variables map[string]interface{}, response interface{}) error {
+	f.queries++
+	if strings.Contains(query, "organization(login: $owner)") {
+		page := 0
+		if after, ok := variables["after"].(string); ok {
+			page = len(after) // cursors are "x", "xx", ...
+		}
+		return json.Unmarshal([]byte(f.teamPages[page]), response)
+	}
+
+	data := make(map[string]json.RawMessage)
+	var graphQLErr *api.GraphQLError
+	for alias, name := range variables {
+		if alias == "owner" {
+			continue
+		}
+		node, ok := f.repositories[strings.ToLower(name.(string))]
+		if !ok {
+			data[alias] = json.RawMessage("null")
+			if graphQLErr == nil {
+				graphQLErr = &api.GraphQLError{}
+			}
+			graphQLErr.Errors = append(graphQLErr.Errors, api.GraphQLErrorItem{Type: "NOT_FOUND", Path: []interface{}{alias}})
+			continue
+		}
+		data[alias] = json.RawMessage(node)
+	}
+	body, _ := json.Marshal(data)
+	if err := json.Unmarshal(body, response); err != nil {
+		return err
+	}
+	if graphQLErr != nil {
+		return graphQLErr
+	}
+	return nil
+}
+
+func TestBuildRepositoriesQuery(t *testing.T) {
+	query := BuildRepositoriesQuery(2)
+	for _, want := range []string{
+		"query($owner: String!, $r0: String!, $r1: String!)",
+		"r0: repository(owner: $owner, name: $r0) { ...repositoryFields }",
+		"r1: repository(owner: $owner, name: $r1) { ...repositoryFields }",
+		"fragment repositoryFields on Repository",
+		"collaborators(first: 100)",
+		"branchProtectionRules(first: 100)",
+	} {
+		if !strings.Contains(query, want) {
+			t.Errorf("BuildRepositoriesQuery(2) is missing %q:\n%s", want, query)
+		}
+	}
+	if strings.Contains(query, "$r2") {
+		t.Errorf("BuildRepositoriesQuery(2) declares a third repository")
+	}
+}
+
+func TestScan(t *testing.T) {
+	client := &fakeQuerier{
+		repositories: map[string]string{
+			"app": `{"nameWithOwner": "acme/app", "visibility": "PRIVATE", "defaultBranchRef": {"name": "main"},
+				"collaborators": {"pageInfo": {"hasNextPage": false}, "edges": [{"permission": "WRITE", "node": {"login": "octocat"}}]},
+				"branchProtectionRules": {"pageInfo": {"hasNextPage": false}, "nodes": [{"pattern": "main", "requiredApprovingReviewCount": 2, "requiresStatusChecks": true}]}}`,
+			"big": `{"nameWithOwner": "acme/big", "visibility": "INTERNAL", "isArchived": true,
+				"collaborators": {"pageInfo": {"hasNextPage": true}, "edges": []},
+				"branchProtectionRules": null}`,
+		},
+		teamPages: []string{
+			`{"organization": {"teams": {"pageInfo": {"hasNextPage": true, "endCursor": "x"}, "nodes": [
+				{"name": "Platform", "slug": "platform", "repositories": {"pageInfo": {"hasNextPage": false}, "edges": [{"permission": "ADMIN", "node": {"name": "App"}}]}}]}}}`,
+			`{"organization": {"teams": {"pageInfo": {"hasNextPage": false}, "nodes": [
+				{"name": "Devs", "slug": "devs", "repositories": {"pageInfo": {"hasNextPage": false}, "edges": [{"permission": "WRITE", "node": {"name": "app"}}, {"permission": "READ", "node": {"name": "big"}}]}}]}}}`,
+		},
+	}
+
+	scanned, err := Scan(client, "acme", []string{"app", "Big", "missing"})
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if client.queries != 3 {
+		t.Errorf("Scan() made %d queries, want 1 repository query and 2 team pages", client.queries)
+	}
+
+	app := scanned["app"]
+	if !app.Found || app.Visibility != "private" || app.DefaultBranch != "main" || app.IsArchived {
+		t.Errorf("app metadata = %+v", app)
+	}
+	wantAccess := dependencies.PrefetchedAccess{
+		Teams:                 []dependencies.AccessGrant{{Name: "Devs", Slug: "devs", Permission: "push"}, {Name: "Platform", Slug: "platform", Permission: "admin"}},
+		TeamsComplete:         true,
+		Collaborators:         []dependencies.AccessGrant{{Name: "octocat", Permission: "write"}},
+		CollaboratorsComplete: true,
+	}
+	if !reflect.DeepEqual(app.Access, wantAccess) {
+		t.Errorf("app access = %+v, want %+v", app.Access, wantAccess)
+	}
+	wantRules := []dependencies.BranchProtectionRule{{Pattern: "main", RequiredApprovingReviewCount: 2, RequiresStatusChecks: true}}
+	if !app.BranchProtectionComplete || !reflect.DeepEqual(app.BranchProtectionRules, wantRules) {
+		t.Errorf("app rules = %+v (complete %v), want %+v", app.BranchProtectionRules, app.BranchProtectionComplete, wantRules)
+	}
+
+	big := scanned["big"]
+	if !big.Found || !big.IsArchived || big.Access.CollaboratorsComplete || big.BranchProtectionComplete {
+		t.Errorf("big = %+v, want truncated collaborators and unreadable rules left to REST", big)
+	}
+	if !big.Access.TeamsComplete || len(big.Access.Teams) != 1 || big.Access.Teams[0].Permission != "pull" {
+		t.Errorf("big teams = %+v", big.Access.Teams)
+	}
+
+	if missing := scanned["missing"]; missing.Found || missing.Access.TeamsComplete {
+		t.Errorf("missing = %+v, want not found", missing)
+	}
+}
+
+func TestScanTeamsIncomplete(t *testing.T) {
+	client := &fakeQuerier{
+		repositories: map[string]string{"app": `{"nameWithOwner": "acme/app"}`},
+		teamPages: []string{
+			`{"organization": {"teams": {"pageInfo": {"hasNextPage": false}, "nodes": [
+				{"name": "All", "slug": "all", "repositories": {"pageInfo": {"hasNextPage": true}, "edges": []}}]}}}`,
+		},
+	}
+	scanned, err := Scan(client, "acme", []string{"app"})
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if scanned["app"].Access.TeamsComplete {
+		t.Errorf("teams complete although a team has more repositories than one page")
+	}
+
+	userOwned := &fakeQuerier{repositories: client.repositories, teamPages: []string{`{"organization": null}`}}
+	scanned, err = Scan(userOwned, "octocat", []string{"app"})
+	if err != nil || scanned["app"].Access.TeamsComplete {
+		t.Errorf("Scan() of a user's repositories = %+v, %v; want teams left to REST", scanned["app"], err)
+	}
+}
+
+func TestTeamPermission(t *testing.T) {
+	tests := map[string]string{"READ": "pull", "TRIAGE": "triage", "WRITE": "push", "MAINTAIN": "maintain", "ADMIN": "admin"}
+	for permission, want := range tests {
+		if got := TeamPermission(permission); got != want {
+			t.Errorf("TeamPermission(%q) = %q, want %q", permission, got, want)
+		}
+	}
+}
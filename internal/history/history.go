@@ -0,0 +1,283 @@
+package history
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jefeish/gh-repo-transfer/internal/encryption"
+
+	// Pure Go SQLite driver, keeps the extension free of cgo
+	_ "modernc.org/sqlite"
+)
+
+// Event kinds recorded by the commands
+const (
+	KindAnalysis     = "analysis"
+	KindValidation   = "validation"
+	KindTransfer     = "transfer"
+	KindArchive      = "archive"
+	KindVerification = "verification"
+	KindRename       = "rename"
+	KindRestore      = "restore"
+	KindTombstone    = "tombstone"
+	KindRollback     = "rollback"
+)
+
+// timestampLayout has a fixed width so timestamps stored as text sort chronologically
+const timestampLayout = "2006-01-02T15:04:05.000000000Z07:00"
+
+// Event is a single entry in the migration history
+type Event struct {
+	ID         int64     `json:"id"`
+	Timestamp  time.Time `json:"timestamp"`
+	Repository string    `json:"repository"`
+	Kind       string    `json:"kind"`
+	Status     string    `json:"status"`
+	Target     string    `json:"target,omitempty"`
+	Details    string    `json:"details,omitempty"`
+}
+
+// Filter narrows down the events returned by Events
+type Filter struct {
+	Repository string
+	Kind       string
+	Since      time.Time
+	Limit      int
+}
+
+// Store records migration events in an embedded SQLite database. With --encrypt-key the
+// database file is stored encrypted: Open locks it and decrypts it into a working copy next to
+// it that only the user can read, and Close encrypts the copy back over the file and removes it.
+type Store struct {
+	db        *sql.DB
+	path      string
+	plainPath string // Decrypted working copy, empty without encryption
+}
+
+const schema = `
+CREATE TABLE IF NOT EXISTS events (
+	id         INTEGER PRIMARY KEY AUTOINCREMENT,
+	timestamp  TEXT    NOT NULL,
+	repository TEXT    NOT NULL,
+	kind       TEXT    NOT NULL,
+	status     TEXT    NOT NULL,
+	target     TEXT    NOT NULL DEFAULT '',
+	details    TEXT    NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS events_repository ON events (repository, timestamp);
+`
+
+// LockPath is the lock file an encrypted database holds while a run works on its copy; it is
+// created exclusively, so overlapping runs cannot overwrite each other's events
+func LockPath(path string) string {
+	return path + ".lock"
+}
+
+// Open opens (and if needed creates) the history database at path. An empty path yields a
+// nil store, on which every method is a no-op.
+func Open(path string) (*Store, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	store := &Store{path: path}
+	dbPath := path
+	if encryption.Enabled() {
+		if err := store.decryptCopy(); err != nil {
+			return nil, err
+		}
+		dbPath = store.plainPath
+	} else if isEncryptedFile(path) {
+		return nil, fmt.Errorf("history database %s is encrypted; pass the key with --encrypt-key", path)
+	}
+
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		store.release()
+		return nil, fmt.Errorf("failed to open history database %s: %v", path, err)
+	}
+	// SQLite allows a single writer; serializing through one connection avoids SQLITE_BUSY
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		store.release()
+		return nil, fmt.Errorf("failed to initialize history database %s: %v", path, err)
+	}
+
+	store.db = db
+	return store, nil
+}
+
+// Close closes the database and, when it is encrypted, writes the working copy back encrypted:
+// to a temporary file renamed over the database, so a crash never leaves it half written
+func (s *Store) Close() error {
+	if s == nil {
+		return nil
+	}
+	err := s.db.Close()
+	if s.plainPath == "" {
+		return err
+	}
+	defer s.release()
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(s.plainPath)
+	if err != nil {
+		return fmt.Errorf("failed to read history database copy: %v", err)
+	}
+	if data, err = encryption.Encrypt(data); err != nil {
+		return fmt.Errorf("failed to encrypt history database %s: %v", s.path, err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), "."+filepath.Base(s.path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to write history database %s: %v", s.path, err)
+	}
+	defer os.Remove(tmp.Name()) // No-op once the rename succeeded
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write history database %s: %v", s.path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write history database %s: %v", s.path, err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to write history database %s: %v", s.path, err)
+	}
+	return nil
+}
+
+// decryptCopy locks the encrypted database and writes its decrypted content (if it exists yet;
+// a plain database is copied as is and encrypted on Close) to a working copy next to it
+func (s *Store) decryptCopy() error {
+	lock, err := os.OpenFile(LockPath(s.path), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if errors.Is(err, os.ErrExist) {
+		return fmt.Errorf("history database %s is in use by another run; delete %s if it is no longer running", s.path, LockPath(s.path))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock history database %s: %v", s.path, err)
+	}
+	fmt.Fprintf(lock, "%d\n", os.Getpid())
+	lock.Close()
+
+	data, err := encryption.ReadFile(s.path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		os.Remove(LockPath(s.path))
+		return fmt.Errorf("failed to read history database %s: %v", s.path, err)
+	}
+	// CreateTemp creates the file readable by the user only
+	plain, err := os.CreateTemp(filepath.Dir(s.path), "."+filepath.Base(s.path)+".plain-*")
+	if err != nil {
+		os.Remove(LockPath(s.path))
+		return fmt.Errorf("failed to create history database copy: %v", err)
+	}
+	s.plainPath = plain.Name()
+	_, err = plain.Write(data)
+	if closeErr := plain.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		s.release()
+		return fmt.Errorf("failed to write history database copy: %v", err)
+	}
+	return nil
+}
+
+// isEncryptedFile reports whether the file at path starts like a file written by encryption.Encrypt
+func isEncryptedFile(path string) bool {
+	file, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+	start := make([]byte, 64)
+	n, _ := file.Read(start)
+	return encryption.IsEncrypted(start[:n])
+}
+
+// release removes the decrypted working copy, with SQLite's journal of it, and the lock
+func (s *Store) release() {
+	if s.plainPath == "" {
+		return
+	}
+	os.Remove(s.plainPath)
+	os.Remove(s.plainPath + "-journal")
+	os.Remove(LockPath(s.path))
+	s.plainPath = ""
+}
+
+// Record stores an event. A zero timestamp is replaced with the current time.
+func (s *Store) Record(event Event) error {
+	if s == nil {
+		return nil
+	}
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now().UTC()
+	}
+
+	_, err := s.db.Exec(
+		`INSERT INTO events (timestamp, repository, kind, status, target, details) VALUES (?, ?, ?, ?, ?, ?)`,
+		event.Timestamp.UTC().Format(timestampLayout), strings.ToLower(event.Repository), event.Kind, event.Status, event.Target, event.Details,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record %s event for %s: %v", event.Kind, event.Repository, err)
+	}
+	return nil
+}
+
+// Events returns the events matching the filter in chronological order
+func (s *Store) Events(filter Filter) ([]Event, error) {
+	if s == nil {
+		return nil, nil
+	}
+
+	query := `SELECT id, timestamp, repository, kind, status, target, details FROM events`
+	var conditions []string
+	var args []interface{}
+	if filter.Repository != "" {
+		conditions = append(conditions, "repository = ?")
+		args = append(args, strings.ToLower(filter.Repository))
+	}
+	if filter.Kind != "" {
+		conditions = append(conditions, "kind = ?")
+		args = append(args, filter.Kind)
+	}
+	if !filter.Since.IsZero() {
+		conditions = append(conditions, "timestamp >= ?")
+		args = append(args, filter.Since.UTC().Format(timestampLayout))
+	}
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	if filter.Limit > 0 {
+		// Keep the most recent events but still return them oldest first
+		query = fmt.Sprintf("SELECT * FROM (%s ORDER BY timestamp DESC, id DESC LIMIT %d) ORDER BY timestamp, id", query, filter.Limit)
+	} else {
+		query += " ORDER BY timestamp, id"
+	}
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query history: %v", err)
+	}
+	defer rows.Close()
+
+	var events []Event
+	for rows.Next() {
+		var event Event
+		var timestamp string
+		if err := rows.Scan(&event.ID, &timestamp, &event.Repository, &event.Kind, &event.Status, &event.Target, &event.Details); err != nil {
+			return nil, fmt.Errorf("failed to read history: %v", err)
+		}
+		event.Timestamp, _ = time.Parse(timestampLayout, timestamp)
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
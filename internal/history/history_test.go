@@ -0,0 +1,101 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jefeish/gh-repo-transfer/internal/encryption"
+)
+
+func TestStoreRecordAndQuery(t *testing.T) {
+	store, err := Open(filepath.Join(t.TempDir(), "migrations.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	events := []Event{
+		{Timestamp: start, Repository: "acme/widget", Kind: KindAnalysis, Status: "completed"},
+		{Timestamp: start.Add(time.Hour), Repository: "acme/widget", Kind: KindValidation, Status: "ready", Target: "new-org"},
+		{Timestamp: start.Add(2 * time.Hour), Repository: "acme/other", Kind: KindAnalysis, Status: "completed"},
+		{Timestamp: start.Add(3 * time.Hour), Repository: "Acme/Widget", Kind: KindTransfer, Status: "succeeded", Target: "new-org"},
+	}
+	for _, event := range events {
+		if err := store.Record(event); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	widget, err := store.Events(Filter{Repository: "acme/widget"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(widget) != 3 || widget[0].Kind != KindAnalysis || widget[2].Kind != KindTransfer {
+		t.Errorf("unexpected widget timeline: %+v", widget)
+	}
+
+	latest, err := store.Events(Filter{Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(latest) != 2 || latest[0].Repository != "acme/other" || !latest[1].Timestamp.Equal(start.Add(3*time.Hour)) {
+		t.Errorf("unexpected latest events: %+v", latest)
+	}
+
+	var nilStore *Store
+	if err := nilStore.Record(events[0]); err != nil {
+		t.Errorf("nil store Record() error = %v", err)
+	}
+}
+
+func TestStoreEncrypted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "migrations.db")
+	t.Setenv("HISTORY_TEST_KEY", "s3cret")
+	if err := encryption.SetKey("env:HISTORY_TEST_KEY"); err != nil {
+		t.Fatal(err)
+	}
+	defer encryption.SetKey("")
+
+	for _, repository := range []string{"acme/widget", "acme/other"} {
+		store, err := Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := store.Record(Event{Repository: repository, Kind: KindAnalysis, Status: "completed"}); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.Close(); err != nil {
+			t.Fatalf("Close() error = %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil || !encryption.IsEncrypted(data) || strings.Contains(string(data), "acme/widget") {
+		t.Fatalf("history database is not encrypted: %v", err)
+	}
+	store, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if events, err := store.Events(Filter{}); err != nil || len(events) != 2 {
+		t.Errorf("Events() = %+v, %v; want both runs' events", events, err)
+	}
+	if _, err := Open(path); err == nil || !strings.Contains(err.Error(), "in use by another run") {
+		t.Errorf("second Open() error = %v, want the database locked", err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(path)); len(entries) != 1 {
+		t.Errorf("files left next to the database after Close: %v", entries)
+	}
+
+	encryption.SetKey("")
+	if _, err := Open(path); err == nil || !strings.Contains(err.Error(), "--encrypt-key") {
+		t.Errorf("Open() without the key error = %v, want a hint to --encrypt-key", err)
+	}
+}
@@ -0,0 +1,130 @@
+package history
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// Phase is the furthest migration step a repository has reached
+type Phase string
+
+const (
+	PhasePlanned     Phase = "planned"     // Known from the state file, nothing recorded yet
+	PhaseAnalyzed    Phase = "analyzed"    // Dependencies analyzed
+	PhaseValidated   Phase = "validated"   // Validated against the target, blockers remain
+	PhaseApproved    Phase = "approved"    // Latest validation found no blockers
+	PhaseTransferred Phase = "transferred" // Transferred or archived
+	PhaseVerified    Phase = "verified"    // Settings verified without implicit drift
+)
+
+// phaseOrder ranks the phases so a repository never moves backwards once it has been moved
+var phaseOrder = map[Phase]int{
+	PhasePlanned:     0,
+	PhaseAnalyzed:    1,
+	PhaseValidated:   2,
+	PhaseApproved:    3,
+	PhaseTransferred: 4,
+	PhaseVerified:    5,
+}
+
+// RepositoryStatus is the current migration state of a repository derived from its events
+type RepositoryStatus struct {
+	Repository  string    `json:"repository"`
+	Phase       Phase     `json:"phase"`
+	Target      string    `json:"target,omitempty"`
+	Note        string    `json:"note,omitempty"` // Why the repository is stuck, e.g. a failed transfer
+	LastEvent   string    `json:"last_event,omitempty"`
+	LastUpdated time.Time `json:"last_updated,omitempty"`
+}
+
+// Summarize derives the current phase of every repository from its events, which must be in
+// chronological order. Repositories in planned without any event are reported as planned.
+// The result is sorted by repository.
+func Summarize(events []Event, planned []string) []RepositoryStatus {
+	statuses := make(map[string]*RepositoryStatus)
+	for _, repository := range planned {
+		key := strings.ToLower(repository)
+		statuses[key] = &RepositoryStatus{Repository: key, Phase: PhasePlanned}
+	}
+
+	for _, event := range events {
+		status, ok := statuses[event.Repository]
+		if !ok {
+			status = &RepositoryStatus{Repository: event.Repository, Phase: PhasePlanned}
+			statuses[event.Repository] = status
+		}
+		applyEvent(status, event)
+	}
+
+	result := make([]RepositoryStatus, 0, len(statuses))
+	for _, status := range statuses {
+		result = append(result, *status)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Repository < result[j].Repository
+	})
+	return result
+}
+
+func applyEvent(status *RepositoryStatus, event Event) {
+	status.LastEvent = event.Kind + ": " + event.Status
+	status.LastUpdated = event.Timestamp
+	if event.Target != "" {
+		status.Target = event.Target
+	}
+
+	moved := phaseOrder[status.Phase] >= phaseOrder[PhaseTransferred]
+	switch event.Kind {
+	case KindAnalysis:
+		if !moved {
+			status.advance(PhaseAnalyzed)
+		}
+	case KindValidation:
+		if moved {
+			return
+		}
+		// A later validation replaces the earlier verdict, so approval can be withdrawn
+		if event.Status == "blocker" || event.Status == "unknown" {
+			status.Phase = PhaseValidated
+			status.Note = "blockers found"
+		} else {
+			status.Phase = PhaseApproved
+			status.Note = ""
+		}
+	case KindTransfer, KindArchive:
+		if event.Status == "succeeded" {
+			status.advance(PhaseTransferred)
+			status.Note = ""
+		} else if !moved {
+			status.Note = event.Kind + " " + event.Status
+		}
+	case KindRestore:
+		// A restored repository is back in its source organization and starts over
+		if event.Status == "succeeded" {
+			status.Phase = PhasePlanned
+			status.Note = "restored from archive"
+		}
+	case KindRollback:
+		if event.Status == "succeeded" {
+			status.Phase = PhasePlanned
+			status.Note = "rolled back"
+		} else {
+			status.Note = "rollback " + event.Status
+		}
+	case KindVerification:
+		if event.Status == "clean" {
+			status.advance(PhaseVerified)
+			status.Note = ""
+		} else {
+			status.Note = "settings " + event.Status
+		}
+	}
+}
+
+// advance moves the status to phase unless it is already further along
+func (s *RepositoryStatus) advance(phase Phase) {
+	if phaseOrder[phase] > phaseOrder[s.Phase] {
+		s.Phase = phase
+	}
+}
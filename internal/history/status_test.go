@@ -0,0 +1,68 @@
+package history
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSummarize(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	event := func(offset int, repository, kind, status string) Event {
+		return Event{Timestamp: start.Add(time.Duration(offset) * time.Minute), Repository: repository, Kind: kind, Status: status, Target: "new-org"}
+	}
+
+	events := []Event{
+		event(0, "acme/analyzed", KindAnalysis, "completed"),
+		event(1, "acme/blocked", KindAnalysis, "completed"),
+		event(2, "acme/blocked", KindValidation, "ready"),
+		event(3, "acme/blocked", KindValidation, "blocker"),
+		event(4, "acme/approved", KindValidation, "warning"),
+		event(5, "acme/failed", KindValidation, "ready"),
+		event(6, "acme/failed", KindTransfer, "failed"),
+		event(7, "acme/moved", KindValidation, "ready"),
+		event(8, "acme/moved", KindTransfer, "succeeded"),
+		event(9, "acme/moved", KindValidation, "blocker"),
+		event(10, "acme/verified", KindArchive, "succeeded"),
+		event(11, "acme/verified", KindVerification, "clean"),
+		event(12, "acme/drifted", KindTransfer, "succeeded"),
+		event(13, "acme/drifted", KindVerification, "drifted"),
+		event(14, "acme/restored", KindArchive, "succeeded"),
+		event(15, "acme/restored", KindRestore, "succeeded"),
+		event(16, "acme/reverted", KindTransfer, "failed"),
+		event(17, "acme/reverted", KindRollback, "succeeded"),
+	}
+
+	want := map[string]struct {
+		phase Phase
+		note  string
+	}{
+		"acme/analyzed": {PhaseAnalyzed, ""},
+		"acme/approved": {PhaseApproved, ""},
+		"acme/blocked":  {PhaseValidated, "blockers found"},
+		"acme/drifted":  {PhaseTransferred, "settings drifted"},
+		"acme/failed":   {PhaseApproved, "transfer failed"},
+		"acme/moved":    {PhaseTransferred, ""},
+		"acme/planned":  {PhasePlanned, ""},
+		"acme/restored": {PhasePlanned, "restored from archive"},
+		"acme/reverted": {PhasePlanned, "rolled back"},
+		"acme/verified": {PhaseVerified, ""},
+	}
+
+	statuses := Summarize(events, []string{"Acme/Planned", "acme/moved"})
+	if len(statuses) != len(want) {
+		t.Fatalf("got %d statuses, want %d: %+v", len(statuses), len(want), statuses)
+	}
+	for i, status := range statuses {
+		if i > 0 && statuses[i-1].Repository >= status.Repository {
+			t.Errorf("statuses not sorted: %s before %s", statuses[i-1].Repository, status.Repository)
+		}
+		expected, ok := want[status.Repository]
+		if !ok {
+			t.Errorf("unexpected repository %s", status.Repository)
+			continue
+		}
+		if status.Phase != expected.phase || status.Note != expected.note {
+			t.Errorf("%s: got phase %s (%q), want %s (%q)", status.Repository, status.Phase, status.Note, expected.phase, expected.note)
+		}
+	}
+}
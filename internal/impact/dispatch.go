@@ -0,0 +1,159 @@
+package impact
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// DispatchConsumer is a workflow in another repository that sends a repository_dispatch event to
+// the repository, typically to deploy through one of its environments. The dispatch targets the
+// repository's path and is authorized by a token or app of the sending organization, so it
+// needs updating after a move.
+type DispatchConsumer struct {
+	Repository   string   `json:"repository"` // Repository containing the dispatch
+	Path         string   `json:"path"`
+	Line         int      `json:"line"`
+	EventType    string   `json:"event_type,omitempty"`
+	Environments []string `json:"environments,omitempty"` // Environments of the target named in the file
+}
+
+// String describes the consumer, e.g.
+// "acme/app: .github/workflows/release.yml:14 dispatches deploy (environments: production)"
+func (c DispatchConsumer) String() string {
+	description := fmt.Sprintf("%s: %s:%d dispatches", c.Repository, c.Path, c.Line)
+	if c.EventType != "" {
+		description += " " + c.EventType
+	} else {
+		description += " an event"
+	}
+	if len(c.Environments) > 0 {
+		description += fmt.Sprintf(" (environments: %s)", strings.Join(c.Environments, ", "))
+	}
+	return description
+}
+
+// ReceivesDispatches reports whether one of the repository's workflows runs on repository_dispatch
+func ReceivesDispatches(client api.RESTClient, owner, repo string) (bool, error) {
+	var tree struct {
+		Tree []struct {
+			Path string `json:"path"`
+			Type string `json:"type"`
+			SHA  string `json:"sha"`
+		} `json:"tree"`
+	}
+	if err := client.Get(fmt.Sprintf("repos/%s/%s/git/trees/HEAD?recursive=1", owner, repo), &tree); err != nil {
+		if strings.Contains(err.Error(), "409") {
+			return false, nil // Empty repository
+		}
+		return false, err
+	}
+
+	for _, entry := range tree.Tree {
+		base := path.Base(entry.Path)
+		if entry.Type != "blob" || path.Dir(entry.Path) != ".github/workflows" || !(strings.HasSuffix(base, ".yml") || strings.HasSuffix(base, ".yaml")) {
+			continue
+		}
+		content, err := readBlob(client, owner, repo, entry.SHA)
+		if err != nil {
+			continue
+		}
+		if strings.Contains(content, "repository_dispatch") {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// FindDispatchConsumers searches the organization's workflows for repository_dispatch events
+// sent to owner/repo (via code search) and reads each match to find the dispatching lines.
+// environments are the repository's environment names to look for in the consumers.
+func FindDispatchConsumers(client api.RESTClient, owner, repo string, environments []string) ([]DispatchConsumer, error) {
+	items, err := searchOrgCode(client, owner, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	var consumers []DispatchConsumer
+	for _, item := range items {
+		if strings.EqualFold(item.Repository.FullName, fmt.Sprintf("%s/%s", owner, repo)) {
+			continue
+		}
+		if kind, _, _ := Classify(item.Path, owner, repo); kind != KindWorkflow {
+			continue
+		}
+		content, err := readFile(client, item.Repository.FullName, item.Path)
+		if err != nil {
+			continue
+		}
+		for _, consumer := range ParseDispatches(content, owner, repo, environments) {
+			consumer.Repository = item.Repository.FullName
+			consumer.Path = item.Path
+			consumers = append(consumers, consumer)
+		}
+	}
+	return consumers, nil
+}
+
+// eventTypePattern matches the event type of a dispatch: event-type: deploy (dispatch actions),
+// "event_type": "deploy" (REST payloads) or -f event_type=deploy (gh api)
+var eventTypePattern = regexp.MustCompile(`event[-_]type["']?\s*[:=]\s*["']?([\w./${}-]+)`)
+
+// dispatchWindow is how many lines around a dispatch are searched for its event type
+const dispatchWindow = 8
+
+// ParseDispatches returns the lines of a workflow file that send a repository_dispatch event to
+// owner/repo: REST calls to repos/owner/repo/dispatches (gh api, curl) and 'repository:'
+// inputs of a repository-dispatch action. Each dispatch lists the given environments the file
+// names, since the event payload usually carries the environment to deploy to.
+func ParseDispatches(content, owner, repo string, environments []string) []DispatchConsumer {
+	target := strings.ToLower(fmt.Sprintf("%s/%s", owner, repo))
+	lower := strings.ToLower(content)
+	usesDispatchAction := strings.Contains(lower, "repository-dispatch@")
+	lines := strings.Split(content, "\n")
+
+	var named []string
+	for _, environment := range environments {
+		pattern := regexp.MustCompile(`(?i)(^|[^\w-])` + regexp.QuoteMeta(environment) + `($|[^\w-])`)
+		if pattern.MatchString(content) {
+			named = append(named, environment)
+		}
+	}
+
+	var consumers []DispatchConsumer
+	for i, line := range lines {
+		lowerLine := strings.ToLower(line)
+		restCall := strings.Contains(lowerLine, "repos/"+target+"/dispatches")
+		actionInput := false
+		if usesDispatchAction {
+			trimmed := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(lowerLine), "- "))
+			if strings.HasPrefix(trimmed, "repository:") {
+				value := strings.Trim(strings.TrimSpace(strings.TrimPrefix(trimmed, "repository:")), `"'`)
+				actionInput = value == target
+			}
+		}
+		if !restCall && !actionInput {
+			continue
+		}
+		consumers = append(consumers, DispatchConsumer{Line: i + 1, EventType: nearbyEventType(lines, i), Environments: named})
+	}
+	return consumers
+}
+
+// nearbyEventType returns the event type closest to line i within dispatchWindow lines
+func nearbyEventType(lines []string, i int) string {
+	for distance := 0; distance <= dispatchWindow; distance++ {
+		for _, j := range []int{i + distance, i - distance} {
+			if j < 0 || j >= len(lines) {
+				continue
+			}
+			if match := eventTypePattern.FindStringSubmatch(lines[j]); match != nil {
+				return match[1]
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,83 @@
+package impact
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseDispatches(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []DispatchConsumer
+	}{
+		{
+			name: "dispatch action",
+			content: `on: push
+jobs:
+  deploy:
+    steps:
+      - uses: peter-evans/repository-dispatch@v3
+        with:
+          token: ${{ secrets.DEPLOY_TOKEN }}
+          repository: Acme/Deployments
+          event-type: deploy
+          client-payload: '{"environment": "production"}'
+`,
+			want: []DispatchConsumer{{Line: 8, EventType: "deploy", Environments: []string{"production"}}},
+		},
+		{
+			name: "gh api call",
+			content: `steps:
+  - run: gh api repos/acme/deployments/dispatches -f event_type=release -f client_payload[env]=staging
+`,
+			want: []DispatchConsumer{{Line: 2, EventType: "release", Environments: []string{"staging"}}},
+		},
+		{
+			name: "curl without event type nearby",
+			content: `steps:
+  - run: |
+      curl -X POST https://api.github.com/repos/acme/deployments/dispatches -d @payload.json
+`,
+			want: []DispatchConsumer{{Line: 3}},
+		},
+		{
+			name: "other repository",
+			content: `steps:
+  - uses: peter-evans/repository-dispatch@v3
+    with:
+      repository: acme/deployments-old
+      event-type: deploy
+  - run: gh api repos/acme/other/dispatches -f event_type=deploy
+`,
+		},
+		{
+			name: "repository input without a dispatch action",
+			content: `steps:
+  - uses: actions/checkout@v4
+    with:
+      repository: acme/deployments
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseDispatches(tt.content, "acme", "deployments", []string{"production", "staging", "prod-eu"})
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseDispatches() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDispatchConsumerString(t *testing.T) {
+	consumer := DispatchConsumer{Repository: "acme/app", Path: ".github/workflows/release.yml", Line: 14, EventType: "deploy", Environments: []string{"production"}}
+	if got, want := consumer.String(), "acme/app: .github/workflows/release.yml:14 dispatches deploy (environments: production)"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	consumer = DispatchConsumer{Repository: "acme/app", Path: ".github/workflows/release.yml", Line: 3}
+	if got, want := consumer.String(), "acme/app: .github/workflows/release.yml:3 dispatches an event"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,204 @@
+package impact
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+
+	"github.com/jefeish/gh-repo-transfer/internal/ghclient"
+)
+
+// Reference kinds found by the impact analysis
+const (
+	KindWorkflow  = "workflow"  // uses: owner/repo/...@ref in a workflow
+	KindDispatch  = "dispatch"  // repository_dispatch event sent to owner/repo by a workflow
+	KindSubmodule = "submodule" // .gitmodules entry
+	KindGoModule  = "go_module" // Module path or import of github.com/owner/repo
+	KindPackage   = "package"   // Package manifest or package linked to the repository
+	KindOther     = "other"     // Any other mention of owner/repo
+)
+
+// Reference is a place that refers to the repository by its current path
+type Reference struct {
+	Kind       string `json:"kind"`
+	Repository string `json:"repository"` // Repository containing the reference ("" for the renamed repository's own packages)
+	Path       string `json:"path,omitempty"`
+	URL        string `json:"url,omitempty"`
+	Breaks     bool   `json:"breaks"` // Not covered by GitHub's rename redirects
+	Action     string `json:"action"`
+}
+
+// Report is the impact of renaming a repository
+type Report struct {
+	Repository string      `json:"repository"`
+	NewName    string      `json:"new_name"`
+	References []Reference `json:"references"`
+	Incomplete []string    `json:"incomplete,omitempty"` // Checks that could not run
+}
+
+// Breaking returns the number of references that stop working after the rename
+func (r *Report) Breaking() int {
+	count := 0
+	for _, reference := range r.References {
+		if reference.Breaks {
+			count++
+		}
+	}
+	return count
+}
+
+// Analyze finds what refers to owner/repo and would be affected by renaming it to newName:
+// the repository's own Go module path and linked packages, and workflows, submodules, Go
+// imports and package manifests in other repositories of the organization (via code search).
+func Analyze(client api.RESTClient, owner, repo, newName string) *Report {
+	report := &Report{Repository: fmt.Sprintf("%s/%s", owner, repo), NewName: newName}
+
+	if err := analyzeOwnModule(client, owner, repo, newName, report); err != nil {
+		report.Incomplete = append(report.Incomplete, fmt.Sprintf("go.mod: %v", err))
+	}
+	if err := analyzeLinkedPackages(client, owner, repo, report); err != nil {
+		report.Incomplete = append(report.Incomplete, fmt.Sprintf("packages: %v", err))
+	}
+	if err := analyzeConsumers(client, owner, repo, newName, report); err != nil {
+		report.Incomplete = append(report.Incomplete, fmt.Sprintf("code search: %v", err))
+	}
+
+	return report
+}
+
+// analyzeOwnModule checks whether the repository's go.mod declares a module path that contains the old name
+func analyzeOwnModule(client api.RESTClient, owner, repo, newName string, report *Report) error {
+	var content struct {
+		Content string `json:"content"`
+	}
+	if err := client.Get(fmt.Sprintf("repos/%s/%s/contents/go.mod", owner, repo), &content); err != nil {
+		return nil // No go.mod at the root
+	}
+	decoded, err := base64.StdEncoding.DecodeString(content.Content)
+	if err != nil {
+		return err
+	}
+
+	oldPath := fmt.Sprintf("%s/%s/%s", ghclient.Host(), owner, repo)
+	for _, line := range strings.Split(string(decoded), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == "module" && (strings.EqualFold(fields[1], oldPath) || strings.HasPrefix(strings.ToLower(fields[1]), strings.ToLower(oldPath)+"/")) {
+			newPath := fmt.Sprintf("%s/%s/%s%s", ghclient.Host(), owner, newName, fields[1][len(oldPath):])
+			report.References = append(report.References, Reference{
+				Kind:       KindGoModule,
+				Repository: report.Repository,
+				Path:       "go.mod",
+				Breaks:     true,
+				Action:     fmt.Sprintf("Change the module path to %s and update its imports", newPath),
+			})
+			break
+		}
+	}
+	return nil
+}
+
+// analyzeLinkedPackages lists container and npm packages linked to the repository
+func analyzeLinkedPackages(client api.RESTClient, owner, repo string, report *Report) error {
+	for _, packageType := range []string{"container", "npm"} {
+		var packages []struct {
+			Name       string `json:"name"`
+			HTMLURL    string `json:"html_url"`
+			Repository *struct {
+				FullName string `json:"full_name"`
+			} `json:"repository"`
+		}
+		if err := ghclient.GetAll(&client, fmt.Sprintf("orgs/%s/packages?package_type=%s", owner, packageType), &packages); err != nil {
+			return err
+		}
+		for _, pkg := range packages {
+			if pkg.Repository == nil || !strings.EqualFold(pkg.Repository.FullName, report.Repository) {
+				continue
+			}
+			report.References = append(report.References, Reference{
+				Kind:   KindPackage,
+				Path:   fmt.Sprintf("%s package %s", packageType, pkg.Name),
+				URL:    pkg.HTMLURL,
+				Action: "Package keeps its name and link; update publishing workflows that derive the name from the repository",
+			})
+		}
+	}
+	return nil
+}
+
+// codeSearchItem is a file found by code search
+type codeSearchItem struct {
+	Path       string `json:"path"`
+	HTMLURL    string `json:"html_url"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+// searchOrgCode searches the organization's code for mentions of owner/repo
+func searchOrgCode(client api.RESTClient, owner, repo string) ([]codeSearchItem, error) {
+	var results struct {
+		Items []codeSearchItem `json:"items"`
+	}
+	query := url.QueryEscape(fmt.Sprintf(`"%s/%s" org:%s`, owner, repo, owner))
+	if err := client.Get(fmt.Sprintf("search/code?q=%s&per_page=100", query), &results); err != nil {
+		return nil, err
+	}
+	return results.Items, nil
+}
+
+// analyzeConsumers searches the organization's code for mentions of owner/repo
+func analyzeConsumers(client api.RESTClient, owner, repo, newName string, report *Report) error {
+	items, err := searchOrgCode(client, owner, repo)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		if strings.EqualFold(item.Repository.FullName, report.Repository) {
+			continue // References inside the repository itself are covered separately
+		}
+		kind, breaks, action := Classify(item.Path, owner, newName)
+		if kind == KindWorkflow {
+			if content, err := readFile(client, item.Repository.FullName, item.Path); err == nil && len(ParseDispatches(content, owner, repo, nil)) > 0 {
+				kind, breaks = KindDispatch, true
+				action = fmt.Sprintf("Send the repository_dispatch event to %s/%s (POST requests do not follow the rename redirect)", owner, newName)
+			}
+		}
+		report.References = append(report.References, Reference{
+			Kind:       kind,
+			Repository: item.Repository.FullName,
+			Path:       item.Path,
+			URL:        item.HTMLURL,
+			Breaks:     breaks,
+			Action:     action,
+		})
+	}
+	return nil
+}
+
+// Classify determines the kind of reference from the path of the file containing it, whether it
+// breaks after a rename and what to do about it. Git and API requests are redirected after a
+// rename, so plain URLs and submodules keep working until the old name is reused; workflow
+// `uses:` references and Go module paths are not redirected.
+func Classify(filePath, owner, newName string) (string, bool, string) {
+	newPath := fmt.Sprintf("%s/%s", owner, newName)
+	base := path.Base(filePath)
+
+	switch {
+	case strings.HasPrefix(filePath, ".github/workflows/") || base == "action.yml" || base == "action.yaml":
+		return KindWorkflow, true, fmt.Sprintf("Update 'uses:' to %s", newPath)
+	case base == ".gitmodules":
+		return KindSubmodule, false, fmt.Sprintf("Update the submodule URL to %s (redirected until the old name is reused)", newPath)
+	case base == "go.mod" || base == "go.sum" || strings.HasSuffix(base, ".go"):
+		return KindGoModule, true, fmt.Sprintf("Update the module requirement and imports to %s/%s", ghclient.Host(), newPath)
+	case base == "package.json" || base == "pom.xml" || base == "build.gradle" || base == "requirements.txt" ||
+		base == "Gemfile" || base == "Cargo.toml" || base == "pyproject.toml" || strings.HasSuffix(base, ".csproj"):
+		return KindPackage, false, fmt.Sprintf("Update the dependency to %s (redirected until the old name is reused)", newPath)
+	default:
+		return KindOther, false, fmt.Sprintf("Update the reference to %s", newPath)
+	}
+}
@@ -0,0 +1,28 @@
+package impact
+
+import "testing"
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		path   string
+		kind   string
+		breaks bool
+	}{
+		{".github/workflows/ci.yml", KindWorkflow, true},
+		{"actions/deploy/action.yml", KindWorkflow, true},
+		{".gitmodules", KindSubmodule, false},
+		{"go.mod", KindGoModule, true},
+		{"internal/client/client.go", KindGoModule, true},
+		{"web/package.json", KindPackage, false},
+		{"docs/README.md", KindOther, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			kind, breaks, action := Classify(tt.path, "acme", "widget-v2")
+			if kind != tt.kind || breaks != tt.breaks || action == "" {
+				t.Errorf("Classify(%q) = %s, %v, %q; want %s, %v", tt.path, kind, breaks, action, tt.kind, tt.breaks)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package impact
+
+import (
+	"encoding/base64"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// PinnedUse is a workflow or action in another repository that uses one of the repository's
+// actions or reusable workflows pinned to a full commit SHA. After a transfer or rename the
+// pin keeps resolving through GitHub's redirect only until someone reuses the old name.
+type PinnedUse struct {
+	Repository string `json:"repository"` // Repository containing the reference
+	Path       string `json:"path"`
+	Line       int    `json:"line"`
+	Uses       string `json:"uses"` // owner/repo[/path] as written
+	SHA        string `json:"sha"`
+}
+
+// String describes the use, e.g. "acme/app: .github/workflows/ci.yml:12 pins acme/setup@0123456789ab"
+func (u PinnedUse) String() string {
+	return fmt.Sprintf("%s: %s:%d pins %s@%s", u.Repository, u.Path, u.Line, u.Uses, shortSHA(u.SHA))
+}
+
+// PublishesActions reports whether other repositories can 'uses:' the repository: it contains
+// an action.yml/action.yaml (at the root or in a subdirectory) or a reusable workflow
+func PublishesActions(client api.RESTClient, owner, repo string) (bool, error) {
+	var tree struct {
+		Tree []struct {
+			Path string `json:"path"`
+			Type string `json:"type"`
+			SHA  string `json:"sha"`
+		} `json:"tree"`
+	}
+	if err := client.Get(fmt.Sprintf("repos/%s/%s/git/trees/HEAD?recursive=1", owner, repo), &tree); err != nil {
+		if strings.Contains(err.Error(), "409") {
+			return false, nil // Empty repository
+		}
+		return false, err
+	}
+
+	var workflows []string
+	for _, entry := range tree.Tree {
+		if entry.Type != "blob" {
+			continue
+		}
+		base := path.Base(entry.Path)
+		if base == "action.yml" || base == "action.yaml" {
+			return true, nil
+		}
+		if path.Dir(entry.Path) == ".github/workflows" && (strings.HasSuffix(base, ".yml") || strings.HasSuffix(base, ".yaml")) {
+			workflows = append(workflows, entry.SHA)
+		}
+	}
+
+	for _, sha := range workflows {
+		content, err := readBlob(client, owner, repo, sha)
+		if err != nil {
+			continue
+		}
+		if strings.Contains(content, "workflow_call") {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// FindPinnedUses searches the organization's workflows and actions for SHA-pinned uses of
+// owner/repo (via code search) and reads each match to find the pinned lines
+func FindPinnedUses(client api.RESTClient, owner, repo string) ([]PinnedUse, error) {
+	items, err := searchOrgCode(client, owner, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	var uses []PinnedUse
+	for _, item := range items {
+		if strings.EqualFold(item.Repository.FullName, fmt.Sprintf("%s/%s", owner, repo)) {
+			continue
+		}
+		if kind, _, _ := Classify(item.Path, owner, repo); kind != KindWorkflow {
+			continue
+		}
+		content, err := readFile(client, item.Repository.FullName, item.Path)
+		if err != nil {
+			continue
+		}
+		for _, use := range ParsePinnedUses(content, owner, repo) {
+			use.Repository = item.Repository.FullName
+			use.Path = item.Path
+			uses = append(uses, use)
+		}
+	}
+	return uses, nil
+}
+
+// fullSHAPattern matches a full commit SHA; shorter refs are tags or branches
+var fullSHAPattern = regexp.MustCompile(`^[0-9a-fA-F]{40}$`)
+
+// ParsePinnedUses returns the 'uses:' lines of a workflow or action file that pin owner/repo
+// (or one of its subdirectory actions or reusable workflows) to a full commit SHA
+func ParsePinnedUses(content, owner, repo string) []PinnedUse {
+	target := strings.ToLower(fmt.Sprintf("%s/%s", owner, repo))
+	var uses []PinnedUse
+	for i, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimPrefix(strings.TrimSpace(line), "- ")
+		if !strings.HasPrefix(trimmed, "uses:") {
+			continue
+		}
+		value := strings.TrimSpace(strings.TrimPrefix(trimmed, "uses:"))
+		if idx := strings.Index(value, " #"); idx != -1 {
+			value = value[:idx]
+		}
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+
+		at := strings.LastIndex(value, "@")
+		if at == -1 || !fullSHAPattern.MatchString(value[at+1:]) {
+			continue
+		}
+		reference := value[:at]
+		lower := strings.ToLower(reference)
+		if lower != target && !strings.HasPrefix(lower, target+"/") {
+			continue
+		}
+		uses = append(uses, PinnedUse{Line: i + 1, Uses: reference, SHA: value[at+1:]})
+	}
+	return uses
+}
+
+// readFile reads a file of a repository through the contents API
+func readFile(client api.RESTClient, fullName, filePath string) (string, error) {
+	var content struct {
+		Content string `json:"content"`
+	}
+	if err := client.Get(fmt.Sprintf("repos/%s/contents/%s", fullName, filePath), &content); err != nil {
+		return "", err
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(content.Content, "\n", ""))
+	if err != nil {
+		return "", err
+	}
+	return string(decoded), nil
+}
+
+// readBlob reads a git blob of a repository
+func readBlob(client api.RESTClient, owner, repo, sha string) (string, error) {
+	var blob struct {
+		Content string `json:"content"`
+	}
+	if err := client.Get(fmt.Sprintf("repos/%s/%s/git/blobs/%s", owner, repo, sha), &blob); err != nil {
+		return "", err
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(blob.Content, "\n", ""))
+	if err != nil {
+		return "", err
+	}
+	return string(decoded), nil
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 12 {
+		return sha[:12]
+	}
+	return sha
+}
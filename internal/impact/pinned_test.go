@@ -0,0 +1,42 @@
+package impact
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePinnedUses(t *testing.T) {
+	const sha = "0123456789abcdef0123456789abcdef01234567"
+	content := `name: ci
+on: push
+jobs:
+  build:
+    uses: acme/setup/.github/workflows/build.yml@` + sha + `
+  test:
+    steps:
+      - uses: acme/setup@` + sha + ` # v1.2.0
+      - uses: "Acme/Setup/node@` + sha + `"
+      - uses: acme/setup@v1
+      - uses: acme/setup@0123456
+      - uses: acme/setup-extra@` + sha + `
+      - uses: other/setup@` + sha + `
+`
+	want := []PinnedUse{
+		{Line: 5, Uses: "acme/setup/.github/workflows/build.yml", SHA: sha},
+		{Line: 8, Uses: "acme/setup", SHA: sha},
+		{Line: 9, Uses: "Acme/Setup/node", SHA: sha},
+	}
+	if got := ParsePinnedUses(content, "acme", "setup"); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParsePinnedUses() = %+v, want %+v", got, want)
+	}
+	if got := ParsePinnedUses("steps:\n  - run: echo acme/setup@"+sha+"\n", "acme", "setup"); len(got) != 0 {
+		t.Errorf("ParsePinnedUses() matched a line without uses: %+v", got)
+	}
+}
+
+func TestPinnedUseString(t *testing.T) {
+	use := PinnedUse{Repository: "acme/app", Path: ".github/workflows/ci.yml", Line: 12, Uses: "acme/setup", SHA: "0123456789abcdef0123456789abcdef01234567"}
+	if got, want := use.String(), "acme/app: .github/workflows/ci.yml:12 pins acme/setup@0123456789ab"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
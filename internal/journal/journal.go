@@ -0,0 +1,419 @@
+// Package journal records how far each repository of a batch transfer or archive got, so an
+// interrupted run can be resumed (see --resume). The journal is a JSON file in a working
+// directory, rewritten after every completed step.
+package journal
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jefeish/gh-repo-transfer/internal/encryption"
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+// DefaultDir is the working directory journals are written to when --journal-dir is not given
+const DefaultDir = ".repo-transfer-journal"
+
+// currentVersion is bumped whenever the layout of the journal changes incompatibly
+const currentVersion = 1
+
+// Phase is the last milestone a repository reached
+type Phase string
+
+const (
+	PhasePending       Phase = ""
+	PhaseValidated     Phase = "validated"
+	PhaseTransferred   Phase = "transferred"
+	PhasePropertiesSet Phase = "properties-set"
+	PhaseTeamsAssigned Phase = "teams-assigned"
+	PhaseCompleted     Phase = "completed"
+	PhaseRolledBack    Phase = "rolled-back" // Moved back to its source by the rollback command
+)
+
+// stepPhases maps the operation steps (see internal/steps) that mark a milestone to it
+var stepPhases = map[string]Phase{
+	"transfer":     PhaseTransferred,
+	"store-origin": PhasePropertiesSet,
+	"assign-teams": PhaseTeamsAssigned,
+}
+
+// Journal is the progress of one batch operation towards one target org
+type Journal struct {
+	Version      int       `json:"version"`
+	Operation    string    `json:"operation"` // "transfer" or "archive"
+	TargetOrg    string    `json:"target_org"`
+	StartedAt    time.Time `json:"started_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	Repositories []*Entry  `json:"repositories"` // In batch order
+
+	path     string
+	readOnly bool
+	mutex    sync.Mutex
+}
+
+// Entry is the progress of one repository
+type Entry struct {
+	Repository   string                     `json:"repository"`
+	Phase        Phase                      `json:"phase"`
+	Steps        []string                   `json:"steps,omitempty"`         // Completed operation steps, in order
+	FailedSteps  []string                   `json:"failed_steps,omitempty"`  // Optional steps that failed; the operation continued
+	Target       string                     `json:"target,omitempty"`        // "owner/name" after the move
+	Teams        []types.Team               `json:"teams,omitempty"`         // Source team permissions collected before the move
+	CreatedTeams []string                   `json:"created_teams,omitempty"` // Teams --create added to the target org for the repository
+	Captured     map[string]json.RawMessage `json:"captured,omitempty"`      // Source data read before the move, by capture step
+	Error        string                     `json:"error,omitempty"`         // Why the last run stopped for this repository
+	UpdatedAt    time.Time                  `json:"updated_at"`
+}
+
+// Transferred reports whether the repository already left its source owner
+func (e Entry) Transferred() bool {
+	switch e.Phase {
+	case PhaseTransferred, PhasePropertiesSet, PhaseTeamsAssigned, PhaseCompleted:
+		return true
+	}
+	return false
+}
+
+// HalfMigrated reports whether the repository was transferred but did not complete, or
+// completed with failed steps, e.g. its teams could not be assigned
+func (e Entry) HalfMigrated() bool {
+	return e.Transferred() && (e.Phase != PhaseCompleted || len(e.FailedSteps) > 0)
+}
+
+// Path returns the journal file of an operation towards a target org inside dir
+func Path(dir, operation, targetOrg string) string {
+	return filepath.Join(dir, fmt.Sprintf("%s-%s.json", operation, strings.ToLower(targetOrg)))
+}
+
+// Create starts a new journal for the repositories, replacing an earlier one at the same path
+func Create(dir, operation, targetOrg string, repositories []string) (*Journal, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create journal directory %s: %v", dir, err)
+	}
+
+	now := time.Now().UTC()
+	j := &Journal{
+		Version:   currentVersion,
+		Operation: operation,
+		TargetOrg: targetOrg,
+		StartedAt: now,
+		UpdatedAt: now,
+		path:      Path(dir, operation, targetOrg),
+	}
+	for _, repository := range repositories {
+		j.Repositories = append(j.Repositories, &Entry{Repository: repository, UpdatedAt: now})
+	}
+	return j, j.save()
+}
+
+// Open reads the journal of an operation towards a target org written by an earlier run
+func Open(dir, operation, targetOrg string) (*Journal, error) {
+	path := Path(dir, operation, targetOrg)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, fmt.Errorf("no journal to resume at %s", path)
+	}
+	return Load(path)
+}
+
+// Load reads the journal file at path
+func Load(path string) (*Journal, error) {
+	data, err := encryption.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read journal %s: %v", path, err)
+	}
+
+	j := &Journal{path: path}
+	if err := json.Unmarshal(data, j); err != nil {
+		return nil, fmt.Errorf("failed to parse journal %s: %v", path, err)
+	}
+	if j.Version != currentVersion {
+		return nil, fmt.Errorf("journal %s has unsupported version %d (expected %d)", path, j.Version, currentVersion)
+	}
+	return j, nil
+}
+
+// ReadOnly stops the journal from being written, e.g. while previewing a resume with --dry-run
+func (j *Journal) ReadOnly() {
+	if j != nil {
+		j.readOnly = true
+	}
+}
+
+// FilePath returns the file the journal is stored in
+func (j *Journal) FilePath() string {
+	if j == nil {
+		return ""
+	}
+	return j.path
+}
+
+// Unfinished lists the repositories that have neither completed nor been rolled back, in batch order
+func (j *Journal) Unfinished() []string {
+	if j == nil {
+		return nil
+	}
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	var repositories []string
+	for _, entry := range j.Repositories {
+		if entry.Phase != PhaseCompleted && entry.Phase != PhaseRolledBack {
+			repositories = append(repositories, entry.Repository)
+		}
+	}
+	return repositories
+}
+
+// HalfMigrated lists the repositories that were transferred but did not complete, or completed
+// with failed steps, in batch order
+func (j *Journal) HalfMigrated() []string {
+	if j == nil {
+		return nil
+	}
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	var repositories []string
+	for _, entry := range j.Repositories {
+		if entry.HalfMigrated() {
+			repositories = append(repositories, entry.Repository)
+		}
+	}
+	return repositories
+}
+
+// Entry returns a copy of the progress of a repository
+func (j *Journal) Entry(repository string) (Entry, bool) {
+	if j == nil {
+		return Entry{}, false
+	}
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	if entry := j.find(repository); entry != nil {
+		copied := *entry
+		copied.Steps = append([]string(nil), entry.Steps...)
+		copied.FailedSteps = append([]string(nil), entry.FailedSteps...)
+		copied.Teams = append([]types.Team(nil), entry.Teams...)
+		copied.CreatedTeams = append([]string(nil), entry.CreatedTeams...)
+		if entry.Captured != nil {
+			copied.Captured = make(map[string]json.RawMessage, len(entry.Captured))
+			for step, data := range entry.Captured {
+				copied.Captured[step] = data
+			}
+		}
+		return copied, true
+	}
+	return Entry{}, false
+}
+
+// Add appends repositories that are not in the journal yet
+func (j *Journal) Add(repositories ...string) error {
+	return j.update(func() {
+		for _, repository := range repositories {
+			if j.find(repository) == nil {
+				j.Repositories = append(j.Repositories, &Entry{Repository: repository, UpdatedAt: time.Now().UTC()})
+			}
+		}
+	})
+}
+
+// Validated records the outcome of validating a repository and where it will be moved to
+func (j *Journal) Validated(repository, target string, validationErr error) error {
+	return j.updateEntry(repository, func(entry *Entry) {
+		entry.Phase = PhaseValidated
+		entry.Target = target
+		entry.Steps = nil
+		entry.FailedSteps = nil
+		entry.Captured = nil
+		entry.Error = ""
+		if validationErr != nil {
+			entry.Error = validationErr.Error()
+		}
+	})
+}
+
+// SetTarget records the path a repository was moved to, when it differs from the planned one
+func (j *Journal) SetTarget(repository, target string) error {
+	return j.updateEntry(repository, func(entry *Entry) {
+		entry.Target = target
+	})
+}
+
+// SetTeams records the source team permissions, which are gone once the repository moved
+func (j *Journal) SetTeams(repository string, teams []types.Team) error {
+	return j.updateEntry(repository, func(entry *Entry) {
+		entry.Teams = teams
+	})
+}
+
+// SetCaptured records what a capture step read from the source repository. The source is gone
+// once the repository moved, so a resumed run restores the data from the journal.
+func (j *Journal) SetCaptured(repository, step string, data interface{}) error {
+	if j == nil {
+		return nil
+	}
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to record %s of %s in the journal: %v", step, repository, err)
+	}
+	return j.updateEntry(repository, func(entry *Entry) {
+		if entry.Captured == nil {
+			entry.Captured = make(map[string]json.RawMessage)
+		}
+		entry.Captured[step] = encoded
+	})
+}
+
+// LoadCaptured decodes what a capture step recorded into v; it reports whether the step
+// recorded anything
+func (e Entry) LoadCaptured(step string, v interface{}) (bool, error) {
+	encoded, ok := e.Captured[step]
+	if !ok {
+		return false, nil
+	}
+	if err := json.Unmarshal(encoded, v); err != nil {
+		return true, fmt.Errorf("failed to read %s of %s from the journal: %v", step, e.Repository, err)
+	}
+	return true, nil
+}
+
+// AddCreatedTeam records a team created in the target org for a repository, which the
+// rollback command deletes again once no repository uses it
+func (j *Journal) AddCreatedTeam(repository, team string) error {
+	return j.updateEntry(repository, func(entry *Entry) {
+		entry.CreatedTeams = append(entry.CreatedTeams, team)
+	})
+}
+
+// CompleteStep records a completed operation step and the milestone it marks
+func (j *Journal) CompleteStep(repository, step string) error {
+	return j.updateEntry(repository, func(entry *Entry) {
+		entry.Steps = append(entry.Steps, step)
+		if phase, ok := stepPhases[step]; ok {
+			entry.Phase = phase
+		}
+	})
+}
+
+// StepFailed records an optional step that failed while the operation continued
+func (j *Journal) StepFailed(repository, step string, err error) error {
+	return j.updateEntry(repository, func(entry *Entry) {
+		entry.FailedSteps = append(entry.FailedSteps, step)
+		entry.Error = fmt.Sprintf("%s: %v", step, err)
+	})
+}
+
+// Complete records that every step of a repository has run; the error of a failed optional
+// step is kept
+func (j *Journal) Complete(repository string) error {
+	return j.updateEntry(repository, func(entry *Entry) {
+		entry.Phase = PhaseCompleted
+		if len(entry.FailedSteps) == 0 {
+			entry.Error = ""
+		}
+	})
+}
+
+// RolledBack records that a repository was moved back to its source; a resumed run only
+// starts it over when it is named on the command line
+func (j *Journal) RolledBack(repository string) error {
+	return j.updateEntry(repository, func(entry *Entry) {
+		entry.Phase = PhaseRolledBack
+		entry.Steps = nil
+		entry.FailedSteps = nil
+		entry.Error = ""
+	})
+}
+
+// Fail records why the operation stopped for a repository
+func (j *Journal) Fail(repository string, err error) error {
+	return j.updateEntry(repository, func(entry *Entry) {
+		entry.Error = err.Error()
+	})
+}
+
+// CompletedSteps returns the steps to skip when the operation of a repository is resumed.
+// Steps before the transfer only read from the source repository, so a repository that was
+// not transferred yet starts over.
+func (j *Journal) CompletedSteps(repository string) map[string]bool {
+	entry, ok := j.Entry(repository)
+	if !ok || !entry.Transferred() {
+		return nil
+	}
+	completed := make(map[string]bool, len(entry.Steps))
+	for _, step := range entry.Steps {
+		completed[step] = true
+	}
+	return completed
+}
+
+// find returns the entry of a repository; the caller holds the mutex
+func (j *Journal) find(repository string) *Entry {
+	for _, entry := range j.Repositories {
+		if strings.EqualFold(entry.Repository, repository) {
+			return entry
+		}
+	}
+	return nil
+}
+
+// updateEntry changes the entry of a repository, adding it when missing, and saves the journal
+func (j *Journal) updateEntry(repository string, change func(entry *Entry)) error {
+	return j.update(func() {
+		entry := j.find(repository)
+		if entry == nil {
+			entry = &Entry{Repository: repository}
+			j.Repositories = append(j.Repositories, entry)
+		}
+		change(entry)
+		entry.UpdatedAt = time.Now().UTC()
+	})
+}
+
+// update applies a change under the mutex and saves the journal
+func (j *Journal) update(change func()) error {
+	if j == nil {
+		return nil
+	}
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	change()
+	j.UpdatedAt = time.Now().UTC()
+	return j.save()
+}
+
+// save writes the journal; the caller holds the mutex. The file is replaced atomically so an
+// interrupted run never leaves a truncated journal behind.
+func (j *Journal) save() error {
+	if j.readOnly {
+		return nil
+	}
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal journal: %v", err)
+	}
+	if data, err = encryption.Encrypt(data); err != nil {
+		return fmt.Errorf("failed to encrypt journal: %v", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(j.path), filepath.Base(j.path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary journal file: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write journal: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write journal: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), j.path); err != nil {
+		return fmt.Errorf("failed to replace journal %s: %v", j.path, err)
+	}
+	return nil
+}
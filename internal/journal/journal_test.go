@@ -0,0 +1,141 @@
+package journal
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+func TestResume(t *testing.T) {
+	dir := t.TempDir()
+	j, err := Create(dir, "transfer", "Acme-New", []string{"acme/web", "acme/api", "acme/docs"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	// acme/web completes, acme/api stops after the transfer, acme/docs fails validation
+	j.Validated("acme/web", "acme-new/web", nil)
+	for _, step := range []string{"capture-topics", "transfer", "store-origin", "assign-teams"} {
+		j.CompleteStep("acme/web", step)
+	}
+	j.Complete("acme/web")
+	j.Validated("acme/api", "acme-new/api", nil)
+	j.SetTeams("acme/api", []types.Team{{Name: "core", Permission: "push"}})
+	j.CompleteStep("acme/api", "capture-topics")
+	j.SetCaptured("acme/api", "capture-branch-protection", []map[string]string{{"branch": "main"}})
+	j.CompleteStep("acme/api", "transfer")
+	j.Fail("acme/api", errors.New("store-origin: connection reset"))
+	j.Validated("acme/docs", "acme-new/docs", errors.New("2 blockers"))
+
+	resumed, err := Open(dir, "transfer", "acme-new")
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if got, want := resumed.Unfinished(), []string{"acme/api", "acme/docs"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Unfinished() = %v, want %v", got, want)
+	}
+
+	tests := []struct {
+		repository      string
+		wantPhase       Phase
+		wantTransferred bool
+		wantCompleted   map[string]bool
+	}{
+		{"acme/web", PhaseCompleted, true, map[string]bool{"capture-topics": true, "transfer": true, "store-origin": true, "assign-teams": true}},
+		{"acme/api", PhaseTransferred, true, map[string]bool{"capture-topics": true, "transfer": true}},
+		{"acme/docs", PhaseValidated, false, nil},
+		{"acme/unknown", PhasePending, false, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.repository, func(t *testing.T) {
+			entry, _ := resumed.Entry(tt.repository)
+			if entry.Phase != tt.wantPhase || entry.Transferred() != tt.wantTransferred {
+				t.Errorf("phase = %q (transferred %v), want %q (%v)", entry.Phase, entry.Transferred(), tt.wantPhase, tt.wantTransferred)
+			}
+			if got := resumed.CompletedSteps(tt.repository); !reflect.DeepEqual(got, tt.wantCompleted) {
+				t.Errorf("CompletedSteps() = %v, want %v", got, tt.wantCompleted)
+			}
+		})
+	}
+
+	api, _ := resumed.Entry("acme/api")
+	if len(api.Teams) != 1 || api.Teams[0].Name != "core" || api.Error == "" {
+		t.Errorf("acme/api entry = %+v, want its teams and error", api)
+	}
+	var protections []map[string]string
+	if found, err := api.LoadCaptured("capture-branch-protection", &protections); !found || err != nil || len(protections) != 1 || protections[0]["branch"] != "main" {
+		t.Errorf("LoadCaptured() = %v, %v, %v, want the captured branch protection", protections, found, err)
+	}
+	if found, _ := api.LoadCaptured("capture-rulesets", &protections); found {
+		t.Error("LoadCaptured() found a capture step that recorded nothing")
+	}
+}
+
+func TestHalfMigrated(t *testing.T) {
+	j, err := Create(t.TempDir(), "transfer", "acme-new", []string{"acme/done", "acme/teams", "acme/stopped", "acme/blocked"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, repository := range []string{"acme/done", "acme/teams", "acme/stopped"} {
+		j.Validated(repository, "acme-new/"+repository[5:], nil)
+		j.CompleteStep(repository, "transfer")
+	}
+	j.Complete("acme/done")
+	j.StepFailed("acme/teams", "assign-teams", errors.New("team core not found"))
+	j.Complete("acme/teams")
+	j.Validated("acme/blocked", "acme-new/blocked", errors.New("2 blockers"))
+
+	if got, want := j.HalfMigrated(), []string{"acme/teams", "acme/stopped"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("HalfMigrated() = %v, want %v", got, want)
+	}
+	if teams, _ := j.Entry("acme/teams"); teams.Error != "assign-teams: team core not found" {
+		t.Errorf("error of a completed repository with a failed step = %q", teams.Error)
+	}
+
+	j.RolledBack("acme/stopped")
+	if got, want := j.HalfMigrated(), []string{"acme/teams"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("HalfMigrated() after the rollback = %v, want %v", got, want)
+	}
+	if got, want := j.Unfinished(), []string{"acme/blocked"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Unfinished() after the rollback = %v, want %v", got, want)
+	}
+}
+
+func TestReadOnly(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := Create(dir, "archive", "acme-archive", []string{"acme/old"}); err != nil {
+		t.Fatal(err)
+	}
+	preview, err := Open(dir, "archive", "acme-archive")
+	if err != nil {
+		t.Fatal(err)
+	}
+	preview.ReadOnly()
+	preview.Complete("acme/old")
+
+	reopened, err := Open(dir, "archive", "acme-archive")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := reopened.Unfinished(); len(got) != 1 {
+		t.Errorf("a read-only journal was written: Unfinished() = %v", got)
+	}
+}
+
+func TestOpenMissing(t *testing.T) {
+	if _, err := Open(t.TempDir(), "transfer", "acme-new"); err == nil {
+		t.Error("Open() of a missing journal succeeded")
+	}
+}
+
+func TestNilJournal(t *testing.T) {
+	var j *Journal
+	if err := j.CompleteStep("acme/web", "transfer"); err != nil {
+		t.Errorf("CompleteStep() on a nil journal = %v", err)
+	}
+	if j.CompletedSteps("acme/web") != nil || j.Unfinished() != nil {
+		t.Error("a nil journal reported progress")
+	}
+}
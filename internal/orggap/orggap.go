@@ -0,0 +1,193 @@
+// Package orggap compares the capabilities of a source and a target organization (teams,
+// apps, Actions secrets, variables and runners, policies and member privileges) independently
+// of any repository, so the target can be prepared before the first transfer
+package orggap
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+// Categories of the gap report
+const (
+	CategoryTeams            = "teams"
+	CategoryApps             = "apps"
+	CategorySecrets          = "secrets"
+	CategoryVariables        = "variables"
+	CategoryRunners          = "runners"
+	CategoryPolicies         = "policies"
+	CategoryMemberPrivileges = "member_privileges"
+)
+
+// Gap statuses
+const (
+	StatusMissing   = "missing"   // In the source, not in the target
+	StatusDifferent = "different" // In both, configured differently
+	StatusExtra     = "extra"     // Only in the target
+)
+
+// Gap is one difference between the organizations
+type Gap struct {
+	Category string `json:"category"`
+	Item     string `json:"item"`
+	Status   string `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+}
+
+// Report is the gap analysis of a source against a target organization
+type Report struct {
+	Source  string `json:"source"`
+	Target  string `json:"target"`
+	Gaps    []Gap  `json:"gaps"`
+	Matched int    `json:"matched"` // Items present and configured alike in both
+}
+
+// Count returns how many gaps have the given status
+func (r Report) Count(status string) int {
+	count := 0
+	for _, gap := range r.Gaps {
+		if gap.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+// Compare builds the gap report of source against target. Names are compared
+// case-insensitively; gaps are sorted by category, status and item.
+func Compare(source, target *types.TargetOrgCapabilities) Report {
+	report := Report{Source: source.Organization, Target: target.Organization}
+
+	report.compareNames(CategoryTeams, source.Teams, target.Teams, nil)
+	report.compareNames(CategoryApps, source.Apps, target.Apps, nil)
+	report.compareNames(CategorySecrets, source.Secrets, target.Secrets, func(name string) string {
+		return differs("visibility", source.SecretVisibility[strings.ToUpper(name)], target.SecretVisibility[strings.ToUpper(name)])
+	})
+	report.compareNames(CategoryVariables, source.Variables, target.Variables, func(name string) string {
+		return differs("value", source.VariableValues[strings.ToUpper(name)], target.VariableValues[strings.ToUpper(name)])
+	})
+	report.compareNames(CategoryRunners, source.Runners, target.Runners, nil)
+	report.compareNames(CategoryPolicies, policyNames(source.RepositoryPolicies), policyNames(target.RepositoryPolicies), nil)
+	report.compareNames(CategoryPolicies, signedCommitPolicy(source), signedCommitPolicy(target), nil)
+	report.compareMemberPrivileges(source.MemberPrivileges, target.MemberPrivileges)
+
+	sort.SliceStable(report.Gaps, func(i, j int) bool {
+		a, b := report.Gaps[i], report.Gaps[j]
+		if a.Category != b.Category {
+			return a.Category < b.Category
+		}
+		if a.Status != b.Status {
+			return statusOrder(a.Status) < statusOrder(b.Status)
+		}
+		return strings.ToLower(a.Item) < strings.ToLower(b.Item)
+	})
+	return report
+}
+
+// compareNames records the names missing in the target and the names only the target has.
+// detail, when set, describes how an item present in both differs ("" when alike).
+func (r *Report) compareNames(category string, source, target []string, detail func(name string) string) {
+	inTarget := make(map[string]bool)
+	for _, name := range target {
+		inTarget[strings.ToLower(name)] = true
+	}
+	inSource := make(map[string]bool)
+	for _, name := range source {
+		key := strings.ToLower(name)
+		if inSource[key] {
+			continue
+		}
+		inSource[key] = true
+		switch {
+		case !inTarget[key]:
+			r.Gaps = append(r.Gaps, Gap{Category: category, Item: name, Status: StatusMissing})
+		case detail != nil && detail(name) != "":
+			r.Gaps = append(r.Gaps, Gap{Category: category, Item: name, Status: StatusDifferent, Detail: detail(name)})
+		default:
+			r.Matched++
+		}
+	}
+	for _, name := range target {
+		if key := strings.ToLower(name); !inSource[key] {
+			inSource[key] = true
+			r.Gaps = append(r.Gaps, Gap{Category: category, Item: name, Status: StatusExtra})
+		}
+	}
+}
+
+// compareMemberPrivileges records the org-wide member settings that differ
+func (r *Report) compareMemberPrivileges(source, target types.OrgMemberPrivileges) {
+	settings := []struct {
+		name           string
+		source, target string
+	}{
+		{"Two-factor authentication required", yesNo(source.TwoFactorRequired), yesNo(target.TwoFactorRequired)},
+		{"Web commit sign-off required", yesNo(source.WebCommitSignoffRequired), yesNo(target.WebCommitSignoffRequired)},
+		{"Members can create repositories", yesNo(source.CanCreateRepos), yesNo(target.CanCreateRepos)},
+		{"Members can fork private repositories", yesNo(source.CanForkPrivateRepos), yesNo(target.CanForkPrivateRepos)},
+		{"Default repository permission", source.DefaultPermission, target.DefaultPermission},
+	}
+	for _, setting := range settings {
+		if detail := differs("", setting.source, setting.target); detail != "" {
+			r.Gaps = append(r.Gaps, Gap{Category: CategoryMemberPrivileges, Item: setting.name, Status: StatusDifferent, Detail: detail})
+		} else {
+			r.Matched++
+		}
+	}
+}
+
+// differs describes a difference, e.g. "visibility: all in source, private in target"
+func differs(label, source, target string) string {
+	if source == target {
+		return ""
+	}
+	if source == "" {
+		source = "unset"
+	}
+	if target == "" {
+		target = "unset"
+	}
+	description := fmt.Sprintf("%s in source, %s in target", source, target)
+	if label != "" {
+		description = label + ": " + description
+	}
+	return description
+}
+
+func policyNames(policies []types.OrgPolicy) []string {
+	var names []string
+	for _, policy := range policies {
+		names = append(names, policy.Name)
+	}
+	return names
+}
+
+// signedCommitPolicy lists a pseudo-policy for organizations with a ruleset requiring signed
+// commits, so that a requirement only one side has shows up whatever the rulesets are named
+func signedCommitPolicy(capabilities *types.TargetOrgCapabilities) []string {
+	if len(capabilities.SignedCommitRulesets) == 0 {
+		return nil
+	}
+	return []string{"Signed commits required by a ruleset"}
+}
+
+func yesNo(value bool) string {
+	if value {
+		return "yes"
+	}
+	return "no"
+}
+
+func statusOrder(status string) int {
+	switch status {
+	case StatusMissing:
+		return 0
+	case StatusDifferent:
+		return 1
+	default:
+		return 2
+	}
+}
@@ -0,0 +1,54 @@
+package orggap
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+func TestCompare(t *testing.T) {
+	source := &types.TargetOrgCapabilities{
+		Organization:         "source",
+		Teams:                []string{"Platform", "Release Managers"},
+		Apps:                 []string{"Renovate"},
+		Secrets:              []string{"NPM_TOKEN", "SONAR_TOKEN"},
+		SecretVisibility:     map[string]string{"NPM_TOKEN": "all", "SONAR_TOKEN": "private"},
+		Variables:            []string{"REGION"},
+		VariableValues:       map[string]string{"REGION": "eu-west-1"},
+		Runners:              []string{"gpu-1"},
+		MemberPrivileges:     types.OrgMemberPrivileges{TwoFactorRequired: true, DefaultPermission: "read"},
+		SignedCommitRulesets: []string{"signed"},
+	}
+	target := &types.TargetOrgCapabilities{
+		Organization:     "target",
+		Teams:            []string{"platform", "Security"},
+		Apps:             []string{"Renovate"},
+		Secrets:          []string{"npm_token"},
+		SecretVisibility: map[string]string{"NPM_TOKEN": "selected"},
+		Variables:        []string{"REGION"},
+		VariableValues:   map[string]string{"REGION": "eu-west-1"},
+		MemberPrivileges: types.OrgMemberPrivileges{TwoFactorRequired: true, DefaultPermission: "none"},
+	}
+
+	report := Compare(source, target)
+	want := []Gap{
+		{Category: CategoryMemberPrivileges, Item: "Default repository permission", Status: StatusDifferent, Detail: "read in source, none in target"},
+		{Category: CategoryPolicies, Item: "Signed commits required by a ruleset", Status: StatusMissing},
+		{Category: CategoryRunners, Item: "gpu-1", Status: StatusMissing},
+		{Category: CategorySecrets, Item: "SONAR_TOKEN", Status: StatusMissing},
+		{Category: CategorySecrets, Item: "NPM_TOKEN", Status: StatusDifferent, Detail: "visibility: all in source, selected in target"},
+		{Category: CategoryTeams, Item: "Release Managers", Status: StatusMissing},
+		{Category: CategoryTeams, Item: "Security", Status: StatusExtra},
+	}
+	if !reflect.DeepEqual(report.Gaps, want) {
+		t.Errorf("Compare() gaps =\n%+v\nwant\n%+v", report.Gaps, want)
+	}
+	// Platform, Renovate, REGION and four member privileges
+	if report.Matched != 7 {
+		t.Errorf("Compare() matched = %d, want 7", report.Matched)
+	}
+	if got := report.Count(StatusMissing); got != 4 {
+		t.Errorf("Count(missing) = %d, want 4", got)
+	}
+}
@@ -0,0 +1,135 @@
+// Package orgsync compares the org-level Actions configuration (variables, runner groups and
+// secrets) of a source and target organization and plans what to create in the target
+package orgsync
+
+import (
+	"sort"
+	"strings"
+)
+
+// Resource kinds
+const (
+	KindVariable    = "variable"
+	KindRunnerGroup = "runner_group"
+	KindSecret      = "secret"
+)
+
+// Plan statuses
+const (
+	StatusCreate          = "create"            // Missing in the target and allowlisted
+	StatusExists          = "exists"            // Already present in the target
+	StatusMissingInSource = "missing_in_source" // Allowlisted but not found in the source
+)
+
+// Item is an org variable, runner group or secret. Value is only known for variables
+// and for secrets given a value by --provision.
+type Item struct {
+	Name       string `json:"name"`
+	Visibility string `json:"visibility,omitempty"`
+	Value      string `json:"-"`
+}
+
+// Inventory is the Actions configuration of one organization
+type Inventory struct {
+	Variables    []Item
+	RunnerGroups []Item
+	Secrets      []Item
+}
+
+// Allowlist names the source items that may be created in the target (--allowlist)
+type Allowlist struct {
+	Variables    []string `yaml:"variables" json:"variables"`
+	RunnerGroups []string `yaml:"runner_groups" json:"runner_groups"`
+	Secrets      []string `yaml:"secrets" json:"secrets"`
+}
+
+// Action is one entry of a sync plan
+type Action struct {
+	Kind   string `json:"kind"`
+	Item   Item   `json:"item"`
+	Status string `json:"status"`
+}
+
+// AllowlistFrom lists every item of the source, as the starting point of a curated allowlist.
+// The default runner group exists in every organization and is left out.
+func AllowlistFrom(source Inventory) Allowlist {
+	var runnerGroups []string
+	for _, name := range names(source.RunnerGroups) {
+		if !strings.EqualFold(name, "Default") {
+			runnerGroups = append(runnerGroups, name)
+		}
+	}
+	return Allowlist{
+		Variables:    names(source.Variables),
+		RunnerGroups: runnerGroups,
+		Secrets:      names(source.Secrets),
+	}
+}
+
+// Build plans the sync of the allowlisted source items into the target. Names are compared
+// case-insensitively, as GitHub does for all three kinds.
+func Build(source, target Inventory, allow Allowlist) []Action {
+	var actions []Action
+	actions = append(actions, plan(KindVariable, source.Variables, target.Variables, allow.Variables)...)
+	actions = append(actions, plan(KindRunnerGroup, source.RunnerGroups, target.RunnerGroups, allow.RunnerGroups)...)
+	actions = append(actions, plan(KindSecret, source.Secrets, target.Secrets, allow.Secrets)...)
+	return actions
+}
+
+// Count returns how many actions have the given status
+func Count(actions []Action, status string) int {
+	count := 0
+	for _, action := range actions {
+		if action.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+func plan(kind string, source, target []Item, allowed []string) []Action {
+	sourceItems := index(source)
+	targetItems := index(target)
+
+	var actions []Action
+	seen := make(map[string]bool)
+	for _, name := range allowed {
+		key := strings.ToLower(strings.TrimSpace(name))
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		item, ok := sourceItems[key]
+		_, exists := targetItems[key]
+		switch {
+		case !ok:
+			actions = append(actions, Action{Kind: kind, Item: Item{Name: name}, Status: StatusMissingInSource})
+		case exists:
+			actions = append(actions, Action{Kind: kind, Item: item, Status: StatusExists})
+		default:
+			actions = append(actions, Action{Kind: kind, Item: item, Status: StatusCreate})
+		}
+	}
+	sort.SliceStable(actions, func(i, j int) bool {
+		return strings.ToLower(actions[i].Item.Name) < strings.ToLower(actions[j].Item.Name)
+	})
+	return actions
+}
+
+func index(items []Item) map[string]Item {
+	indexed := make(map[string]Item, len(items))
+	for _, item := range items {
+		indexed[strings.ToLower(item.Name)] = item
+	}
+	return indexed
+}
+
+func names(items []Item) []string {
+	var list []string
+	for _, item := range items {
+		list = append(list, item.Name)
+	}
+	sort.Strings(list)
+	return list
+}
@@ -0,0 +1,59 @@
+package orgsync
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuild(t *testing.T) {
+	source := Inventory{
+		Variables:    []Item{{Name: "DEPLOY_ENV", Visibility: "all", Value: "prod"}, {Name: "REGION", Visibility: "private", Value: "eu"}},
+		RunnerGroups: []Item{{Name: "Default", Visibility: "all"}, {Name: "gpu", Visibility: "selected"}},
+		Secrets:      []Item{{Name: "NPM_TOKEN", Visibility: "private"}},
+	}
+	target := Inventory{
+		Variables: []Item{{Name: "region", Visibility: "all"}},
+	}
+
+	tests := []struct {
+		name  string
+		allow Allowlist
+		want  []Action
+	}{
+		{
+			name:  "empty allowlist plans nothing",
+			allow: Allowlist{},
+		},
+		{
+			name:  "creates missing, skips existing and reports unknown names",
+			allow: Allowlist{Variables: []string{"REGION", "DEPLOY_ENV", "deploy_env"}, RunnerGroups: []string{"gpu"}, Secrets: []string{"NPM_TOKEN", "SONAR_TOKEN"}},
+			want: []Action{
+				{Kind: KindVariable, Item: source.Variables[0], Status: StatusCreate},
+				{Kind: KindVariable, Item: source.Variables[1], Status: StatusExists},
+				{Kind: KindRunnerGroup, Item: source.RunnerGroups[1], Status: StatusCreate},
+				{Kind: KindSecret, Item: source.Secrets[0], Status: StatusCreate},
+				{Kind: KindSecret, Item: Item{Name: "SONAR_TOKEN"}, Status: StatusMissingInSource},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Build(source, target, tt.allow)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Build() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAllowlistFrom(t *testing.T) {
+	source := Inventory{
+		Variables:    []Item{{Name: "B"}, {Name: "A"}},
+		RunnerGroups: []Item{{Name: "Default"}, {Name: "gpu"}},
+	}
+	want := Allowlist{Variables: []string{"A", "B"}, RunnerGroups: []string{"gpu"}}
+	if got := AllowlistFrom(source); !reflect.DeepEqual(got, want) {
+		t.Errorf("AllowlistFrom() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,149 @@
+// Package origin encodes the original "owner/repo" of a moved repository in the forms
+// --origin-tracking stores it in: a custom property, a topic or a description marker, and
+// the history of repositories archived more than once
+package origin
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Origin tracking strategies
+const (
+	StrategyProperty    = "property"
+	StrategyTopic       = "topic"
+	StrategyDescription = "description"
+	StrategyAll         = "all"
+)
+
+// Verbs naming the move in topics and description markers
+const (
+	Archived    = "archived"
+	Transferred = "transferred"
+)
+
+// maxTopicLength is the longest topic GitHub accepts
+const maxTopicLength = 50
+
+// Strategies returns the strategies selected by an --origin-tracking value; "all" selects
+// property, topic and description
+func Strategies(tracking string) ([]string, error) {
+	switch strings.ToLower(strings.TrimSpace(tracking)) {
+	case "", StrategyProperty:
+		return []string{StrategyProperty}, nil
+	case StrategyTopic:
+		return []string{StrategyTopic}, nil
+	case StrategyDescription:
+		return []string{StrategyDescription}, nil
+	case StrategyAll:
+		return []string{StrategyProperty, StrategyTopic, StrategyDescription}, nil
+	}
+	return nil, fmt.Errorf("invalid origin tracking %q: use property, topic, description or all", tracking)
+}
+
+// Topic returns the topic recording the original path, e.g. "archived-from-acme--web-app".
+// Owner names cannot contain two hyphens in a row, so the first "--" separates owner and
+// name. Topics only allow lower-case letters, digits and hyphens: other characters of the
+// name become hyphens. Paths whose topic would exceed 50 characters yield an error.
+func Topic(verb, originalPath string) (string, error) {
+	owner, name, ok := strings.Cut(originalPath, "/")
+	if !ok || owner == "" || name == "" {
+		return "", fmt.Errorf("%q is not an owner/repo path", originalPath)
+	}
+	topic := fmt.Sprintf("%s-from-%s--%s", verb, strings.ToLower(owner), TopicName(name))
+	if len(topic) > maxTopicLength {
+		return "", fmt.Errorf("topic %q is longer than %d characters", topic, maxTopicLength)
+	}
+	return topic, nil
+}
+
+// TopicName returns a repository name as it appears in an origin topic
+func TopicName(name string) string {
+	var topicName strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			topicName.WriteRune(r)
+		} else {
+			topicName.WriteRune('-')
+		}
+	}
+	return topicName.String()
+}
+
+// ParseTopics finds an origin topic among a repository's topics and returns the original
+// owner (in lower case) and the name as it appears in the topic
+func ParseTopics(topics []string) (string, string, bool) {
+	for _, topic := range topics {
+		for _, verb := range []string{Archived, Transferred} {
+			rest, ok := strings.CutPrefix(topic, verb+"-from-")
+			if !ok {
+				continue
+			}
+			if owner, name, ok := strings.Cut(rest, "--"); ok && owner != "" && name != "" {
+				return owner, name, true
+			}
+		}
+	}
+	return "", "", false
+}
+
+// IsTopic reports whether a topic is an origin topic
+func IsTopic(topic string) bool {
+	_, _, ok := ParseTopics([]string{topic})
+	return ok
+}
+
+// descriptionMarker matches the marker Describe adds, e.g. "[ARCHIVED FROM: acme/web-app]"
+var descriptionMarker = regexp.MustCompile(`\s*\[(?:ARCHIVED|TRANSFERRED) FROM: ([^\s/\]]+/[^\s/\]]+)\]`)
+
+// Describe returns the description with a marker recording the original path appended,
+// replacing the marker of an earlier move
+func Describe(description, verb, originalPath string) string {
+	marker := fmt.Sprintf("[%s FROM: %s]", strings.ToUpper(verb), originalPath)
+	if description = StripDescription(description); description == "" {
+		return marker
+	}
+	return description + " " + marker
+}
+
+// ParseDescription returns the original path recorded in a description
+func ParseDescription(description string) (string, bool) {
+	match := descriptionMarker.FindStringSubmatch(description)
+	if match == nil {
+		return "", false
+	}
+	return match[1], true
+}
+
+// StripDescription removes the origin marker from a description
+func StripDescription(description string) string {
+	return strings.TrimSpace(descriptionMarker.ReplaceAllString(description, ""))
+}
+
+// Chain returns the origin history of a repository moved from originalPath: the origins
+// recorded on it by earlier moves, oldest first, followed by originalPath unless it is the
+// last of them already
+func Chain(earlier []string, originalPath string) []string {
+	chain := append([]string(nil), earlier...)
+	if len(chain) == 0 || !strings.EqualFold(chain[len(chain)-1], originalPath) {
+		chain = append(chain, originalPath)
+	}
+	return chain
+}
+
+// FormatHistory returns an origin chain as the JSON array stored in the history property
+func FormatHistory(chain []string) string {
+	data, _ := json.Marshal(chain)
+	return string(data)
+}
+
+// ParseHistory parses the JSON array of the history property
+func ParseHistory(value string) ([]string, error) {
+	var chain []string
+	if err := json.Unmarshal([]byte(value), &chain); err != nil {
+		return nil, fmt.Errorf("origin history %q is not a JSON array of owner/repo paths: %v", value, err)
+	}
+	return chain, nil
+}
@@ -0,0 +1,117 @@
+package origin
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestStrategies(t *testing.T) {
+	tests := []struct {
+		tracking string
+		want     []string
+		wantErr  bool
+	}{
+		{"", []string{StrategyProperty}, false},
+		{"property", []string{StrategyProperty}, false},
+		{"Topic", []string{StrategyTopic}, false},
+		{"description", []string{StrategyDescription}, false},
+		{"all", []string{StrategyProperty, StrategyTopic, StrategyDescription}, false},
+		{"label", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := Strategies(tt.tracking)
+		if (err != nil) != tt.wantErr || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Strategies(%q) = %v, %v, want %v, error %v", tt.tracking, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestTopic(t *testing.T) {
+	tests := []struct {
+		verb      string
+		path      string
+		want      string
+		wantOwner string
+		wantName  string
+		wantErr   bool
+	}{
+		{Archived, "Acme/web-app", "archived-from-acme--web-app", "acme", "web-app", false},
+		{Transferred, "my-org/Tools_v2.0", "transferred-from-my-org--tools-v2-0", "my-org", "tools-v2-0", false},
+		{Archived, "acme/" + strings.Repeat("x", 40), "", "", "", true},
+		{Archived, "web-app", "", "", "", true},
+	}
+	for _, tt := range tests {
+		got, err := Topic(tt.verb, tt.path)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("Topic(%q, %q) = %q, %v, want %q, error %v", tt.verb, tt.path, got, err, tt.want, tt.wantErr)
+			continue
+		}
+		if tt.wantErr {
+			continue
+		}
+		owner, name, ok := ParseTopics([]string{"go", got})
+		if !ok || owner != tt.wantOwner || name != tt.wantName {
+			t.Errorf("ParseTopics(%q) = %q, %q, %v, want %q, %q", got, owner, name, ok, tt.wantOwner, tt.wantName)
+		}
+	}
+
+	if _, _, ok := ParseTopics([]string{"archived", "archived-from-acme"}); ok {
+		t.Errorf("ParseTopics() found an origin in topics without one")
+	}
+}
+
+func TestDescription(t *testing.T) {
+	tests := []struct {
+		description string
+		verb        string
+		path        string
+		want        string
+	}{
+		{"", Archived, "acme/web", "[ARCHIVED FROM: acme/web]"},
+		{"Web frontend", Transferred, "acme/web", "Web frontend [TRANSFERRED FROM: acme/web]"},
+		{"Web frontend [TRANSFERRED FROM: acme/web]", Archived, "corp/web", "Web frontend [ARCHIVED FROM: corp/web]"},
+	}
+	for _, tt := range tests {
+		got := Describe(tt.description, tt.verb, tt.path)
+		if got != tt.want {
+			t.Errorf("Describe(%q, %q, %q) = %q, want %q", tt.description, tt.verb, tt.path, got, tt.want)
+		}
+		if path, ok := ParseDescription(got); !ok || path != tt.path {
+			t.Errorf("ParseDescription(%q) = %q, %v, want %q", got, path, ok, tt.path)
+		}
+		if stripped := StripDescription(got); stripped != StripDescription(tt.description) {
+			t.Errorf("StripDescription(%q) = %q", got, stripped)
+		}
+	}
+
+	if _, ok := ParseDescription("Moved to acme/web"); ok {
+		t.Errorf("ParseDescription() found an origin in a description without a marker")
+	}
+}
+
+func TestChain(t *testing.T) {
+	tests := []struct {
+		earlier []string
+		path    string
+		want    []string
+	}{
+		{nil, "acme/web", []string{"acme/web"}},
+		{[]string{"old/web"}, "acme/web", []string{"old/web", "acme/web"}},
+		{[]string{"old/web", "acme/web"}, "Acme/Web", []string{"old/web", "acme/web"}},
+	}
+	for _, tt := range tests {
+		got := Chain(tt.earlier, tt.path)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Chain(%v, %q) = %v, want %v", tt.earlier, tt.path, got, tt.want)
+		}
+		parsed, err := ParseHistory(FormatHistory(got))
+		if err != nil || !reflect.DeepEqual(parsed, got) {
+			t.Errorf("ParseHistory(FormatHistory(%v)) = %v, %v", got, parsed, err)
+		}
+	}
+
+	if _, err := ParseHistory("acme/web"); err == nil {
+		t.Errorf("ParseHistory() accepted a plain path")
+	}
+}
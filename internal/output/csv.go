@@ -0,0 +1,53 @@
+package output
+
+import (
+	"encoding/csv"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+// outputCSV writes the batch summary as CSV to stdout
+func outputCSV(allDeps []*types.OrganizationalDependencies) error {
+	return WriteCSV(os.Stdout, allDeps)
+}
+
+// WriteCSV writes one row per repository with its dependency count per category, its blocker
+// count and its overall readiness, for sorting and filtering a batch in a spreadsheet.
+// Repositories analyzed without --target-org have the readiness not_validated and no blockers.
+func WriteCSV(w io.Writer, allDeps []*types.OrganizationalDependencies) error {
+	header := []string{"Repository", "Target Organization", "Overall Readiness", "Blockers"}
+	for _, category := range workbookCategories {
+		header = append(header, category.sheet)
+	}
+	header = append(header, "Total Dependencies")
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	for _, deps := range allDeps {
+		target, readiness, blockers := "", notValidated, 0
+		if v := deps.Validation; v != nil {
+			target, readiness, blockers = v.TargetOrganization, string(v.OverallReadiness), v.Summary.Blockers
+		}
+		row := []string{deps.Repository, target, readiness, strconv.Itoa(blockers)}
+		total := 0
+		for _, category := range workbookCategories {
+			count := 0
+			for _, items := range category.dependencies(deps) {
+				count += len(items)
+			}
+			total += count
+			row = append(row, strconv.Itoa(count))
+		}
+		row = append(row, strconv.Itoa(total))
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
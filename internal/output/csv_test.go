@@ -0,0 +1,28 @@
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"reflect"
+	"testing"
+)
+
+func TestWriteCSV(t *testing.T) {
+	var buffer bytes.Buffer
+	if err := WriteCSV(&buffer, readinessReportFixture()); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&buffer).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := [][]string{
+		{"Repository", "Target Organization", "Overall Readiness", "Blockers", "Code", "CI-CD", "Access", "Security", "Apps", "Governance", "Total Dependencies"},
+		{"acme/web", "new-org", "blocker", "1", "0", "0", "0", "0", "0", "0", "0"},
+		{"acme/api", "", "not_validated", "0", "0", "1", "0", "0", "0", "0", "1"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("WriteCSV() rows = %v, want %v", rows, want)
+	}
+}
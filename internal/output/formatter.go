@@ -4,9 +4,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/jefeish/gh-repo-transfer/internal/fingerprint"
 	"github.com/jefeish/gh-repo-transfer/internal/types"
+	"github.com/jefeish/gh-repo-transfer/pkg/utils"
 	"gopkg.in/yaml.v3"
 )
 
@@ -19,6 +23,16 @@ func OutputDependencies(deps *types.OrganizationalDependencies, format string) e
 		return outputYAML(deps)
 	case "table":
 		return outputTable(deps)
+	case "xlsx":
+		return outputXLSX([]*types.OrganizationalDependencies{deps})
+	case "junit":
+		return outputJUnit([]*types.OrganizationalDependencies{deps})
+	case "markdown", "md":
+		return outputMarkdown([]*types.OrganizationalDependencies{deps})
+	case "html":
+		return outputHTML([]*types.OrganizationalDependencies{deps})
+	case "csv":
+		return outputCSV([]*types.OrganizationalDependencies{deps})
 	default:
 		return fmt.Errorf("unsupported output format: %s", format)
 	}
@@ -33,6 +47,16 @@ func OutputMultipleDependencies(allDeps []*types.OrganizationalDependencies, for
 		return outputMultipleYAML(allDeps)
 	case "table":
 		return outputMultipleTable(allDeps)
+	case "xlsx":
+		return outputXLSX(allDeps)
+	case "junit":
+		return outputJUnit(allDeps)
+	case "markdown", "md":
+		return outputMarkdown(allDeps)
+	case "html":
+		return outputHTML(allDeps)
+	case "csv":
+		return outputCSV(allDeps)
 	default:
 		return fmt.Errorf("unsupported output format: %s", format)
 	}
@@ -54,43 +78,85 @@ func outputTable(deps *types.OrganizationalDependencies) error {
 	fmt.Printf("🔍 Organizational Dependencies Analysis\n")
 	fmt.Printf("════════════════════════════════════════\n\n")
 
+	// Say which part of a monorepo the file contents were read from
+	if deps.Path != "" {
+		fmt.Printf("📁 Scoped to %s/ of %s: workflows, package files and CODEOWNERS of the planned repository\n\n", deps.Path, deps.Repository)
+	}
+
 	// Show validation summary if present
 	if deps.Validation != nil {
 		printValidationSummary(deps.Validation)
 	}
 
+	// Show what this repository needs beyond its reference repository
+	if deps.ReferenceComparison != nil {
+		printReferenceComparison(deps.ReferenceComparison)
+	}
+
+	// Show the seats and committers the transfer adds to the target
+	if deps.BillingImpact != nil {
+		printBillingImpact(deps.BillingImpact)
+	}
+
+	// Show the names that change with the planned path
+	if deps.WhatIf != nil {
+		printWhatIf(deps.WhatIf)
+	}
+
+	// Say which parts a fast pass left out
+	if deps.Depth != nil && !deps.Depth.Deep {
+		printFastPassOnly(deps.Depth)
+	}
+
 	// Count dependencies
 	totalDeps := 0
 	codeDeps := countDependencies(deps.CodeDependencies.InternalRepositoryReferences,
 		deps.CodeDependencies.GitSubmodules,
 		deps.CodeDependencies.OrgPackageRegistries,
 		deps.CodeDependencies.HardcodedOrgReferences,
-		deps.CodeDependencies.OrgSpecificContainerRegistries)
+		deps.CodeDependencies.OrgSpecificContainerRegistries,
+		deps.CodeDependencies.DocumentationURLReferences)
 	
 	ciDeps := countDependencies(deps.ActionsCIDependencies.OrganizationSecrets,
 		deps.ActionsCIDependencies.OrganizationVariables,
 		deps.ActionsCIDependencies.SelfHostedRunners,
+		deps.ActionsCIDependencies.NetworkBoundRunnerGroups,
+		deps.ActionsCIDependencies.StaticIPRunners,
+		deps.ActionsCIDependencies.ArtifactAttestations,
 		deps.ActionsCIDependencies.EnvironmentDependencies,
 		deps.ActionsCIDependencies.OrgSpecificActions,
 		deps.ActionsCIDependencies.RequiredWorkflows,
-		deps.ActionsCIDependencies.CrossRepoWorkflowTriggers)
+		deps.ActionsCIDependencies.CrossRepoWorkflowTriggers,
+		deps.ActionsCIDependencies.RepositorySecrets,
+		deps.ActionsCIDependencies.RepositoryVariables,
+		deps.ActionsCIDependencies.EnvironmentReviewers,
+		deps.ActionsCIDependencies.EnvironmentSecrets,
+		deps.ActionsCIDependencies.EnvironmentVariables,
+		deps.ActionsCIDependencies.PinnedActionConsumers,
+		deps.ActionsCIDependencies.DispatchConsumers)
 	
 	accessDeps := countDependencies(deps.AccessPermissions.Teams,
 		deps.AccessPermissions.IndividualCollaborators,
 		deps.AccessPermissions.OrganizationRoles,
 		deps.AccessPermissions.OrganizationMembership,
-		deps.AccessPermissions.CodeownersRequirements)
+		deps.AccessPermissions.CodeownersRequirements,
+		deps.AccessPermissions.DeployKeys)
 	
 	securityDeps := countDependencies(deps.SecurityCompliance.SecurityCampaigns)
 	
 	appsDeps := countDependencies(deps.AppsIntegrations.InstalledGitHubApps,
-		deps.AppsIntegrations.PersonalAccessTokens)
+		deps.AppsIntegrations.PersonalAccessTokens,
+		deps.AppsIntegrations.Webhooks,
+		deps.AppsIntegrations.Pages)
 	
 	govDeps := countPolicyDependencies(deps.OrgGovernance.OrganizationPolicies) +
 		len(deps.OrgGovernance.RepositoryRulesets) +
+		len(deps.OrgGovernance.EnterprisePolicies) +
+		len(deps.OrgGovernance.PushRulesets) +
 		countDependencies(deps.OrgGovernance.IssueTemplates,
 		deps.OrgGovernance.PullRequestTemplates,
-		deps.OrgGovernance.RequiredStatusChecks)
+		deps.OrgGovernance.RequiredStatusChecks,
+		deps.OrgGovernance.EventSinks)
 	
 	totalDeps = codeDeps + ciDeps + accessDeps + securityDeps + appsDeps + govDeps
 
@@ -117,16 +183,27 @@ func outputTable(deps *types.OrganizationalDependencies) error {
 		"Organization Package Registries": deps.CodeDependencies.OrgPackageRegistries,
 		"Hard-coded Organization References": deps.CodeDependencies.HardcodedOrgReferences,
 		"Organization Container Registries": deps.CodeDependencies.OrgSpecificContainerRegistries,
+		"Documentation URLs (releases, tags, badges)": deps.CodeDependencies.DocumentationURLReferences,
 	}, true)
 	
 	printDependencySection("🔄 GitHub Actions & CI/CD Dependencies", ciDeps, map[string][]string{
 		"Organization Secrets": deps.ActionsCIDependencies.OrganizationSecrets,
 		"Organization Variables": deps.ActionsCIDependencies.OrganizationVariables,
 		"Self-hosted Runners": deps.ActionsCIDependencies.SelfHostedRunners,
+		"Network-bound Runner Groups": deps.ActionsCIDependencies.NetworkBoundRunnerGroups,
+		"Static IP Runners": deps.ActionsCIDependencies.StaticIPRunners,
+		"Artifact Attestations": deps.ActionsCIDependencies.ArtifactAttestations,
 		"Environment Dependencies": deps.ActionsCIDependencies.EnvironmentDependencies,
 		"Organization-specific Actions": deps.ActionsCIDependencies.OrgSpecificActions,
 		"Required Workflows": deps.ActionsCIDependencies.RequiredWorkflows,
 		"Cross-repo Workflow Triggers": deps.ActionsCIDependencies.CrossRepoWorkflowTriggers,
+		"Repository Secrets": deps.ActionsCIDependencies.RepositorySecrets,
+		"Repository Variables": deps.ActionsCIDependencies.RepositoryVariables,
+		"Environment Reviewers": deps.ActionsCIDependencies.EnvironmentReviewers,
+		"Environment Secrets": deps.ActionsCIDependencies.EnvironmentSecrets,
+		"Environment Variables": deps.ActionsCIDependencies.EnvironmentVariables,
+		"Pinned Action Consumers": deps.ActionsCIDependencies.PinnedActionConsumers,
+		"Dispatch Consumers": deps.ActionsCIDependencies.DispatchConsumers,
 	}, true)
 	
 	printDependencySection("🔐 Access Control & Permissions", accessDeps, map[string][]string{
@@ -135,6 +212,7 @@ func outputTable(deps *types.OrganizationalDependencies) error {
 		"Organization Roles": deps.AccessPermissions.OrganizationRoles,
 		"Organization Membership": deps.AccessPermissions.OrganizationMembership,
 		"CODEOWNERS Requirements": deps.AccessPermissions.CodeownersRequirements,
+		"Deploy Keys": deps.AccessPermissions.DeployKeys,
 	}, true)
 	
 	printDependencySection("🛡️  Security & Compliance Dependencies", securityDeps, map[string][]string{
@@ -144,6 +222,8 @@ func outputTable(deps *types.OrganizationalDependencies) error {
 	printDependencySection("🔗 GitHub Apps & Integrations", appsDeps, map[string][]string{
 		"Installed GitHub Apps": deps.AppsIntegrations.InstalledGitHubApps,
 		"Personal Access Tokens": deps.AppsIntegrations.PersonalAccessTokens,
+		"Repository Webhooks": deps.AppsIntegrations.Webhooks,
+		"GitHub Pages": deps.AppsIntegrations.Pages,
 	}, true)
 	
 	// Custom governance section with separated policies and privileges
@@ -187,7 +267,11 @@ func printValidationSummary(validation *types.MigrationValidation) {
 	
 	// Overall status
 	statusEmoji := getStatusEmoji(validation.OverallReadiness)
-	fmt.Printf("Overall Readiness: %s %s\n\n", statusEmoji, validation.OverallReadiness)
+	fmt.Printf("Overall Readiness: %s %s\n", statusEmoji, validation.OverallReadiness)
+	if !validation.CapabilitiesScannedAt.IsZero() {
+		fmt.Printf("Target Scanned: %s\n", validation.CapabilitiesScannedAt.Format(time.RFC3339))
+	}
+	fmt.Printf("\n")
 	
 	// Summary counts
 	fmt.Printf("📊 Validation Summary:\n")
@@ -195,9 +279,15 @@ func printValidationSummary(validation *types.MigrationValidation) {
 	fmt.Printf("├─ 🟡 Setup Needed: %d\n", validation.Summary.SetupNeeded)
 	fmt.Printf("├─ 🔴 Blockers: %d\n", validation.Summary.Blockers)
 	fmt.Printf("├─ ⚪ Manual Review: %d\n", validation.Summary.Review)
+	if validation.Summary.Suppressed > 0 {
+		fmt.Printf("├─ 🔕 Suppressed: %d\n", validation.Summary.Suppressed)
+	}
 	fmt.Printf("└─ ❓ Unknown: %d\n\n", validation.Summary.Unknown)
 	
 	fmt.Printf("🎯 Total Items Validated: %d\n", validation.Summary.Total)
+	if validation.Effort != nil && validation.Effort.TotalMinutes > 0 {
+		fmt.Printf("⏱️  Estimated Remediation Effort: %s\n", utils.FormatMinutes(validation.Effort.TotalMinutes))
+	}
 	fmt.Printf("════════════════════════════════════════\n\n")
 	
 	// Show detailed validation results if there are issues
@@ -206,6 +296,95 @@ func printValidationSummary(validation *types.MigrationValidation) {
 	}
 }
 
+// printReferenceComparison lists the dependencies the reference repository does not have
+func printReferenceComparison(comparison *types.ReferenceComparison) {
+	fmt.Printf("🧬 Compared with Reference: %s\n", comparison.Reference)
+	fmt.Printf("════════════════════════════════════════\n")
+	fmt.Printf("Shared with reference: %d\n", comparison.Shared)
+
+	if len(comparison.NovelItems) == 0 {
+		fmt.Printf("✅ No dependencies beyond the reference repository\n")
+		fmt.Printf("════════════════════════════════════════\n\n")
+		return
+	}
+
+	novelTypes := make(map[string]bool, len(comparison.NovelTypes))
+	for _, dependencyType := range comparison.NovelTypes {
+		novelTypes[dependencyType] = true
+	}
+	var dependencyTypes []string
+	for dependencyType := range comparison.NovelItems {
+		dependencyTypes = append(dependencyTypes, dependencyType)
+	}
+	sort.Strings(dependencyTypes)
+
+	fmt.Printf("Novel dependencies (review these):\n")
+	for _, dependencyType := range dependencyTypes {
+		marker := ""
+		if novelTypes[dependencyType] {
+			marker = " 🆕 new type"
+		}
+		fmt.Printf("  • %s%s\n", dependencyType, marker)
+		for _, item := range comparison.NovelItems[dependencyType] {
+			fmt.Printf("      - %s\n", item)
+		}
+	}
+	fmt.Printf("════════════════════════════════════════\n\n")
+}
+
+// printBillingImpact shows the estimated additional seats and Advanced Security committers
+func printBillingImpact(impact *types.BillingImpact) {
+	fmt.Printf("💳 Billing Impact (informational)\n")
+	fmt.Printf("════════════════════════════════════════\n")
+	fmt.Printf("├─ Additional seats: %d\n", impact.AdditionalSeats)
+	for _, login := range impact.NewSeatUsers {
+		fmt.Printf("│    • %s\n", login)
+	}
+	fmt.Printf("└─ Additional GHAS active committers: %d\n", impact.AdditionalGHASCommitters)
+	for _, login := range impact.NewCommitters {
+		fmt.Printf("     • %s\n", login)
+	}
+	for _, note := range impact.Notes {
+		fmt.Printf("⚠️  %s\n", note)
+	}
+	fmt.Printf("════════════════════════════════════════\n\n")
+}
+
+// printWhatIf shows the names derived from the repository's path that change under its planned path
+func printWhatIf(whatIf *types.WhatIf) {
+	fmt.Printf("🔮 What-if: %s (informational)\n", whatIf.TargetRepository)
+	fmt.Printf("════════════════════════════════════════\n")
+	if whatIf.NameTaken {
+		fmt.Printf("❌ %s already exists; choose another name\n", whatIf.TargetRepository)
+	}
+	if len(whatIf.Consequences) == 0 {
+		fmt.Printf("└─ No module path, package scope or image name is derived from the repository's path\n")
+	}
+	for i, consequence := range whatIf.Consequences {
+		branch, indent := "├─", "│  "
+		if i == len(whatIf.Consequences)-1 {
+			branch, indent = "└─", "   "
+		}
+		fmt.Printf("%s [%s] %s: %s → %s\n", branch, consequence.Kind, consequence.Path, consequence.Current, consequence.Planned)
+		fmt.Printf("%s  %s\n", indent, consequence.Action)
+	}
+	for _, note := range whatIf.Notes {
+		fmt.Printf("⚠️  %s\n", note)
+	}
+	fmt.Printf("════════════════════════════════════════\n\n")
+}
+
+// printFastPassOnly explains that a repository was not analyzed in depth (--deep)
+func printFastPassOnly(depth *types.AnalysisDepth) {
+	fmt.Printf("⚡ Fast pass only (--deep %s, score %d, threshold %d)\n", depth.Mode, depth.Score, depth.Threshold)
+	fmt.Printf("════════════════════════════════════════\n")
+	for _, signal := range depth.Signals {
+		fmt.Printf("├─ %s\n", signal)
+	}
+	fmt.Printf("└─ File contents, code search, branch protections and ruleset details were not analyzed\n")
+	fmt.Printf("════════════════════════════════════════\n\n")
+}
+
 // printDetailedValidation shows detailed validation results
 func printDetailedValidation(validation *types.MigrationValidation) {
 	fmt.Printf("📋 Detailed Validation Results\n")
@@ -257,11 +436,21 @@ func printValidationCategory(title string, results []types.ValidationResult) {
 				indentPrefix = "   "
 			}
 			
-			if result.Message != "" {
-				fmt.Printf("%s   📝 %s\n", indentPrefix, result.Message)
+			if result.Suppression != nil {
+				fmt.Printf("%s   🔕 %s\n", indentPrefix, suppressionNote(result.Suppression))
+			} else {
+				if result.Message != "" {
+					fmt.Printf("%s   📝 %s\n", indentPrefix, result.Message)
+				}
+				if result.Recommendation != "" {
+					fmt.Printf("%s   💡 %s\n", indentPrefix, result.Recommendation)
+				}
+			}
+			if result.Override != nil {
+				fmt.Printf("%s   ⚖️  %s\n", indentPrefix, overrideNote(result.Override))
 			}
-			if result.Recommendation != "" {
-				fmt.Printf("%s   💡 %s\n", indentPrefix, result.Recommendation)
+			if result.ID != "" {
+				fmt.Printf("%s   🔎 %s: gh repo-transfer explain %s\n", indentPrefix, result.ID, result.Kind)
 			}
 		}
 	}
@@ -280,6 +469,8 @@ func getStatusEmoji(status types.ValidationStatus) string {
 		return "🔴"
 	case types.ValidationReview:
 		return "⚪"
+	case types.ValidationSuppressed:
+		return "🔕"
 	default:
 		return "❓"
 	}
@@ -368,6 +559,39 @@ func printGovernanceDependencies(governance types.OrgGovernance, totalCount int)
 		}{"Required Status Checks", governance.RequiredStatusChecks})
 	}
 
+	if len(governance.EnterprisePolicies) > 0 {
+		var formattedEnterprisePolicies []string
+		for _, policy := range governance.EnterprisePolicies {
+			policyInfo := fmt.Sprintf("%s (status: %s)", policy.Name, policy.Status)
+			for i, restriction := range policy.Restrictions {
+				if i == len(policy.Restrictions)-1 {
+					policyInfo += "\n     └─ " + restriction
+				} else {
+					policyInfo += "\n     ├─ " + restriction
+				}
+			}
+			formattedEnterprisePolicies = append(formattedEnterprisePolicies, policyInfo)
+		}
+		sections = append(sections, struct {
+			name  string
+			items []string
+		}{"Enterprise Policies", formattedEnterprisePolicies})
+	}
+
+	if len(governance.EventSinks) > 0 {
+		sections = append(sections, struct {
+			name  string
+			items []string
+		}{"Event Sinks", governance.EventSinks})
+	}
+
+	if len(governance.PushRulesets) > 0 {
+		sections = append(sections, struct {
+			name  string
+			items []string
+		}{"Push Rulesets", pushRulesetDescriptions(governance.PushRulesets)})
+	}
+
 	// Print all sections
 	for sectionIdx, section := range sections {
 		isLastSection := sectionIdx == len(sections)-1
@@ -532,8 +756,24 @@ func outputMultipleTable(allDeps []*types.OrganizationalDependencies) error {
 			fmt.Printf("    %s: %d repositories\n", status, count)
 		}
 	}
+	if summary.EffortMinutes > 0 {
+		fmt.Printf("  Estimated remediation effort: %s\n", utils.FormatMinutes(summary.EffortMinutes))
+	}
+	if summary.AdditionalSeats > 0 || summary.AdditionalGHASCommitters > 0 {
+		fmt.Printf("  Additional target seats: %d, GHAS active committers: %d\n", summary.AdditionalSeats, summary.AdditionalGHASCommitters)
+	}
+	if summary.FastPassOnly > 0 {
+		fmt.Printf("  Fast pass only (--deep): %d repositories\n", summary.FastPassOnly)
+	}
 	fmt.Printf("\n")
 
+	if len(summary.Organizations) > 0 {
+		printOrgSummaries(summary.Organizations)
+	}
+	if len(summary.Clusters) > 0 {
+		printClusters(summary.Clusters)
+	}
+
 	// Output each repository's analysis
 	for i, deps := range allDeps {
 		fmt.Printf("📦 Repository %d: %s\n", i+1, deps.Repository)
@@ -559,8 +799,33 @@ type BatchSummary struct {
 	TotalOrganizations int            `json:"total_organizations" yaml:"total_organizations"`
 	TotalDependencies  int            `json:"total_dependencies" yaml:"total_dependencies"`
 	ValidationSummary  map[string]int `json:"validation_summary,omitempty" yaml:"validation_summary,omitempty"`
+	EffortMinutes      int            `json:"estimated_effort_minutes,omitempty" yaml:"estimated_effort_minutes,omitempty"`
+	AdditionalSeats    int            `json:"additional_seats,omitempty" yaml:"additional_seats,omitempty"` // Unique users across repositories (--billing-impact)
+	AdditionalGHASCommitters int      `json:"additional_ghas_committers,omitempty" yaml:"additional_ghas_committers,omitempty"`
+	Organizations      []OrgSummary   `json:"organizations,omitempty" yaml:"organizations,omitempty"` // Only when more than one source org
+	Clusters           []types.RepositoryCluster `json:"clusters,omitempty" yaml:"clusters,omitempty"` // Only with --cluster
+	FastPassOnly       int            `json:"fast_pass_only,omitempty" yaml:"fast_pass_only,omitempty"` // Repositories not analyzed in depth (--deep)
 }
 
+// OrgSummary rolls up the results of one source organization for executive reporting
+type OrgSummary struct {
+	Organization      string             `json:"organization" yaml:"organization"`
+	Repositories      int                `json:"repositories" yaml:"repositories"`
+	ReposWithBlockers int                `json:"repositories_with_blockers" yaml:"repositories_with_blockers"`
+	Blockers          int                `json:"blockers" yaml:"blockers"`
+	EffortMinutes     int                `json:"estimated_effort_minutes" yaml:"estimated_effort_minutes"`
+	TopBlockerTypes   []BlockerTypeCount `json:"top_blocker_types,omitempty" yaml:"top_blocker_types,omitempty"`
+}
+
+// BlockerTypeCount counts blockers sharing the same validation message
+type BlockerTypeCount struct {
+	Type  string `json:"type" yaml:"type"`
+	Count int    `json:"count" yaml:"count"`
+}
+
+// maxTopBlockerTypes limits how many blocker types are listed per organization
+const maxTopBlockerTypes = 3
+
 func generateBatchSummary(allDeps []*types.OrganizationalDependencies) BatchSummary {
 	summary := BatchSummary{
 		TotalRepositories: len(allDeps),
@@ -569,6 +834,8 @@ func generateBatchSummary(allDeps []*types.OrganizationalDependencies) BatchSumm
 	
 	orgs := make(map[string]bool)
 	totalDeps := 0
+	seatUsers := make(map[string]bool)
+	committers := make(map[string]bool)
 	
 	for _, deps := range allDeps {
 		// Extract organization from repository name
@@ -597,61 +864,157 @@ func generateBatchSummary(allDeps []*types.OrganizationalDependencies) BatchSumm
 		
 		// Count validation status if available
 		if deps.Validation != nil {
-			// Count items across all validation categories
-			allValidations := append(deps.Validation.CodeDependencies, 
-				append(deps.Validation.CIDependencies,
-					append(deps.Validation.AccessPermissions,
-						append(deps.Validation.SecurityCompliance,
-							append(deps.Validation.AppsIntegrations, deps.Validation.Governance...)...)...)...)...)
-			
-			for _, validation := range allValidations {
+			for _, validation := range allValidationResults(deps.Validation) {
 				summary.ValidationSummary[string(validation.Status)]++
 			}
+			if deps.Validation.Effort != nil {
+				summary.EffortMinutes += deps.Validation.Effort.TotalMinutes
+			}
+		}
+
+		if deps.Depth != nil && !deps.Depth.Deep {
+			summary.FastPassOnly++
+		}
+
+		// The same person only needs one seat however many repositories they work on
+		if deps.BillingImpact != nil {
+			for _, login := range deps.BillingImpact.NewSeatUsers {
+				seatUsers[login] = true
+			}
+			for _, login := range deps.BillingImpact.NewCommitters {
+				committers[login] = true
+			}
 		}
 	}
+	summary.AdditionalSeats = len(seatUsers)
+	summary.AdditionalGHASCommitters = len(committers)
 	
 	summary.TotalOrganizations = len(orgs)
 	summary.TotalDependencies = totalDeps
+	if len(orgs) > 1 {
+		summary.Organizations = generateOrgSummaries(allDeps)
+	}
+	summary.Clusters = fingerprint.Clusters(allDeps)
 	
 	return summary
 }
 
-// OutputSeparateFiles outputs each repository analysis to individual JSON files
-func OutputSeparateFiles(allDeps []*types.OrganizationalDependencies, verbose bool) error {
-	if verbose {
-		fmt.Fprintf(os.Stderr, "Creating separate JSON files for %d repositories\n", len(allDeps))
-	}
-	
+// generateOrgSummaries groups repositories by source organization, sorted by organization name
+func generateOrgSummaries(allDeps []*types.OrganizationalDependencies) []OrgSummary {
+	byOrg := make(map[string]*OrgSummary)
+	blockerTypes := make(map[string]map[string]int)
+	var orgNames []string
+
 	for _, deps := range allDeps {
-		// Generate safe filename from repository name
-		filename := generateSafeFilename(deps.Repository) + ".json"
-		
-		if verbose {
-			fmt.Fprintf(os.Stderr, "Writing %s\n", filename)
+		org := strings.Split(deps.Repository, "/")[0]
+		orgSummary, exists := byOrg[org]
+		if !exists {
+			orgSummary = &OrgSummary{Organization: org}
+			byOrg[org] = orgSummary
+			blockerTypes[org] = make(map[string]int)
+			orgNames = append(orgNames, org)
 		}
-		
-		// Create file
-		file, err := os.Create(filename)
-		if err != nil {
-			return fmt.Errorf("failed to create file %s: %v", filename, err)
+		orgSummary.Repositories++
+
+		if deps.Validation == nil {
+			continue
 		}
-		defer file.Close()
-		
-		// Write JSON to file
-		encoder := json.NewEncoder(file)
-		encoder.SetIndent("", "  ")
-		if err := encoder.Encode(deps); err != nil {
-			return fmt.Errorf("failed to write JSON to file %s: %v", filename, err)
+		if deps.Validation.Effort != nil {
+			orgSummary.EffortMinutes += deps.Validation.Effort.TotalMinutes
+		}
+		repoBlockers := 0
+		for _, result := range allValidationResults(deps.Validation) {
+			if result.Status != types.ValidationBlocker {
+				continue
+			}
+			repoBlockers++
+			blockerTypes[org][result.Message]++
+		}
+		if repoBlockers > 0 {
+			orgSummary.ReposWithBlockers++
+			orgSummary.Blockers += repoBlockers
 		}
 	}
-	
+
+	sort.Strings(orgNames)
+	summaries := make([]OrgSummary, 0, len(orgNames))
+	for _, org := range orgNames {
+		orgSummary := byOrg[org]
+		orgSummary.TopBlockerTypes = topBlockerTypes(blockerTypes[org], maxTopBlockerTypes)
+		summaries = append(summaries, *orgSummary)
+	}
+	return summaries
+}
+
+// topBlockerTypes returns the most frequent blocker types, ties broken alphabetically
+func topBlockerTypes(counts map[string]int, limit int) []BlockerTypeCount {
+	var ranked []BlockerTypeCount
+	for blockerType, count := range counts {
+		ranked = append(ranked, BlockerTypeCount{Type: blockerType, Count: count})
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Count != ranked[j].Count {
+			return ranked[i].Count > ranked[j].Count
+		}
+		return ranked[i].Type < ranked[j].Type
+	})
+	if len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+	return ranked
+}
+
+// allValidationResults flattens the validation results of every category
+func allValidationResults(validation *types.MigrationValidation) []types.ValidationResult {
+	var results []types.ValidationResult
+	results = append(results, validation.CodeDependencies...)
+	results = append(results, validation.CIDependencies...)
+	results = append(results, validation.AccessPermissions...)
+	results = append(results, validation.SecurityCompliance...)
+	results = append(results, validation.AppsIntegrations...)
+	results = append(results, validation.Governance...)
+	return results
+}
+
+// printOrgSummaries prints the per-organization executive roll-up
+func printOrgSummaries(summaries []OrgSummary) {
+	fmt.Printf("🏢 Executive Summary by Organization:\n")
+	for i, org := range summaries {
+		prefix := "├─"
+		indent := "│ "
+		if i == len(summaries)-1 {
+			prefix = "└─"
+			indent = "  "
+		}
+		fmt.Printf("  %s %s: %d repositories, %d with blockers (%d blockers), est. effort %s\n",
+			prefix, org.Organization, org.Repositories, org.ReposWithBlockers, org.Blockers, utils.FormatMinutes(org.EffortMinutes))
+		for _, blocker := range org.TopBlockerTypes {
+			fmt.Printf("  %s   • %s (%d)\n", indent, blocker.Type, blocker.Count)
+		}
+	}
+	fmt.Printf("\n")
+}
+
+// printClusters lists the dependency similarity clusters, largest first
+func printClusters(clusters []types.RepositoryCluster) {
+	fmt.Printf("🧩 Dependency Clusters:\n")
+	for _, cluster := range clusters {
+		fmt.Printf("  %s (%d repositories)\n", cluster.Name, len(cluster.Repositories))
+		fmt.Printf("    Repositories: %s\n", strings.Join(cluster.Repositories, ", "))
+		if len(cluster.SharedDependencies) > 0 {
+			fmt.Printf("    Shared: %s\n", strings.Join(cluster.SharedDependencies, ", "))
+		}
+	}
+	fmt.Printf("\n")
+}
+
+// PrintSeparateFilesSummary reports how many per-repository files were created
+func PrintSeparateFilesSummary(count int, verbose bool) {
 	if verbose {
-		fmt.Fprintf(os.Stderr, "Successfully created %d JSON files\n", len(allDeps))
+		fmt.Fprintf(os.Stderr, "Successfully created %d JSON files\n", count)
 	} else {
-		fmt.Printf("Created %d individual JSON files\n", len(allDeps))
+		fmt.Printf("Created %d individual JSON files\n", count)
 	}
-	
-	return nil
 }
 
 // generateSafeFilename converts repository name to filesystem-safe filename
@@ -670,4 +1033,52 @@ func generateSafeFilename(repoName string) string {
 	
 	// Add prefix for clarity and avoid conflicts
 	return "repo-analysis_" + safe
-}
\ No newline at end of file
+}
+
+// pushRulesetDescriptions describes push rulesets with their restrictions, e.g.
+// "large files (organization): max file size 50 MB, blocked extensions .exe"
+func pushRulesetDescriptions(rulesets []types.PushRuleset) []string {
+	var descriptions []string
+	for _, ruleset := range rulesets {
+		var restrictions []string
+		if ruleset.MaxFileSizeMB > 0 {
+			restrictions = append(restrictions, fmt.Sprintf("max file size %d MB", ruleset.MaxFileSizeMB))
+		}
+		if ruleset.MaxFilePathLength > 0 {
+			restrictions = append(restrictions, fmt.Sprintf("max file path length %d", ruleset.MaxFilePathLength))
+		}
+		if len(ruleset.RestrictedPaths) > 0 {
+			restrictions = append(restrictions, "blocked paths "+strings.Join(ruleset.RestrictedPaths, ", "))
+		}
+		if len(ruleset.RestrictedExtensions) > 0 {
+			restrictions = append(restrictions, "blocked extensions "+strings.Join(ruleset.RestrictedExtensions, ", "))
+		}
+		description := fmt.Sprintf("%s (%s)", ruleset.Name, ruleset.Source)
+		if len(restrictions) > 0 {
+			description += ": " + strings.Join(restrictions, "; ")
+		}
+		descriptions = append(descriptions, description)
+	}
+	return descriptions
+}
+
+// overrideNote describes a severity override, e.g. "Overridden from warning by ci.configure_runner: GPU jobs"
+func overrideNote(override *types.Override) string {
+	note := fmt.Sprintf("Overridden from %s by %s", override.Status, override.Rule)
+	if override.Reason != "" {
+		note += ": " + override.Reason
+	}
+	return note
+}
+
+// suppressionNote describes a suppression, e.g. "Suppressed blocker until 2026-12-31: vault"
+func suppressionNote(suppression *types.Suppression) string {
+	note := fmt.Sprintf("Suppressed %s", suppression.Status)
+	if suppression.Expires != "" {
+		note += " until " + suppression.Expires
+	}
+	if suppression.Reason != "" {
+		note += ": " + suppression.Reason
+	}
+	return note
+}
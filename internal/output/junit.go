@@ -0,0 +1,132 @@
+package output
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+// junitTestSuites is the root element of a JUnit XML report
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+// junitTestSuite holds the validation items of one repository
+type junitTestSuite struct {
+	Name       string          `xml:"name,attr"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Skipped    int             `xml:"skipped,attr"`
+	Properties []junitProperty `xml:"properties>property,omitempty"`
+	Cases      []junitTestCase `xml:"testcase"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// junitTestCase is one validation item
+type junitTestCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Text    string `xml:",chardata"`
+}
+
+// outputJUnit writes the validation results as JUnit XML to stdout
+func outputJUnit(allDeps []*types.OrganizationalDependencies) error {
+	return WriteJUnit(os.Stdout, allDeps)
+}
+
+// WriteJUnit writes one test suite per repository and one test case per validation item:
+// blockers are failures, review and unknown items are skipped, everything else passes
+func WriteJUnit(w io.Writer, allDeps []*types.OrganizationalDependencies) error {
+	report := junitTestSuites{Name: "migration-readiness"}
+	for _, deps := range allDeps {
+		suite := junitSuite(deps)
+		report.Suites = append(report.Suites, suite)
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.Skipped += suite.Skipped
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return fmt.Errorf("failed to encode JUnit XML: %v", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// junitSuite converts the validation of one repository into a test suite
+func junitSuite(deps *types.OrganizationalDependencies) junitTestSuite {
+	suite := junitTestSuite{Name: deps.Repository}
+	if deps.Validation == nil {
+		suite.Properties = append(suite.Properties, junitProperty{Name: "validated", Value: "false"})
+		return suite
+	}
+	suite.Properties = append(suite.Properties,
+		junitProperty{Name: "target_organization", Value: deps.Validation.TargetOrganization},
+		junitProperty{Name: "overall_readiness", Value: string(deps.Validation.OverallReadiness)},
+	)
+
+	categories := []struct {
+		name    string
+		results []types.ValidationResult
+	}{
+		{"code_dependencies", deps.Validation.CodeDependencies},
+		{"ci_dependencies", deps.Validation.CIDependencies},
+		{"access_permissions", deps.Validation.AccessPermissions},
+		{"security_compliance", deps.Validation.SecurityCompliance},
+		{"apps_integrations", deps.Validation.AppsIntegrations},
+		{"governance", deps.Validation.Governance},
+	}
+	for _, category := range categories {
+		for _, result := range category.results {
+			testCase := junitTestCase{
+				ClassName: deps.Repository + "." + category.name,
+				Name:      result.Item,
+			}
+			details := result.Message
+			if result.Recommendation != "" {
+				details += "\nRecommendation: " + result.Recommendation
+			}
+			switch result.Status {
+			case types.ValidationBlocker:
+				testCase.Failure = &junitMessage{Message: result.Message, Type: string(result.Status), Text: details}
+				suite.Failures++
+			case types.ValidationSuppressed:
+				testCase.Skipped = &junitMessage{Message: suppressionNote(result.Suppression)}
+				suite.Skipped++
+			case types.ValidationReview, types.ValidationUnknown:
+				testCase.Skipped = &junitMessage{Message: fmt.Sprintf("%s: %s", result.Status, result.Message)}
+				suite.Skipped++
+			default:
+				testCase.SystemOut = fmt.Sprintf("%s: %s", result.Status, details)
+			}
+			suite.Cases = append(suite.Cases, testCase)
+			suite.Tests++
+		}
+	}
+	return suite
+}
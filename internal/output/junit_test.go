@@ -0,0 +1,51 @@
+package output
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+func TestWriteJUnit(t *testing.T) {
+	deps := &types.OrganizationalDependencies{Repository: "acme/web", Validation: &types.MigrationValidation{
+		TargetOrganization: "new-org",
+		OverallReadiness:   types.ValidationBlocker,
+		AccessPermissions: []types.ValidationResult{
+			{Item: "platform", Status: types.ValidationBlocker, Message: "Team not found", Recommendation: "Create the team"},
+			{Item: "ops", Status: types.ValidationReady},
+		},
+		CIDependencies: []types.ValidationResult{
+			{Item: "NPM_TOKEN", Status: types.ValidationReview, Message: "Secret exists in both orgs"},
+		},
+	}}
+	unvalidated := &types.OrganizationalDependencies{Repository: "acme/api"}
+
+	var buffer bytes.Buffer
+	if err := WriteJUnit(&buffer, []*types.OrganizationalDependencies{deps, unvalidated}); err != nil {
+		t.Fatal(err)
+	}
+
+	var report junitTestSuites
+	if err := xml.Unmarshal(buffer.Bytes(), &report); err != nil {
+		t.Fatalf("invalid XML: %v\n%s", err, buffer.String())
+	}
+	if report.Tests != 3 || report.Failures != 1 || report.Skipped != 1 || len(report.Suites) != 2 {
+		t.Errorf("totals = %d tests, %d failures, %d skipped, %d suites", report.Tests, report.Failures, report.Skipped, len(report.Suites))
+	}
+
+	cases := map[string]junitTestCase{}
+	for _, testCase := range report.Suites[0].Cases {
+		cases[testCase.Name] = testCase
+	}
+	if cases["platform"].Failure == nil || cases["platform"].ClassName != "acme/web.access_permissions" {
+		t.Errorf("blocker is not a failure: %+v", cases["platform"])
+	}
+	if cases["NPM_TOKEN"].Skipped == nil {
+		t.Errorf("review item is not skipped: %+v", cases["NPM_TOKEN"])
+	}
+	if cases["ops"].Failure != nil || cases["ops"].Skipped != nil {
+		t.Errorf("ready item does not pass: %+v", cases["ops"])
+	}
+}
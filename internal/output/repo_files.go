@@ -0,0 +1,313 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jefeish/gh-repo-transfer/internal/encryption"
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+// ManifestFilename is the index written next to the per-repository files
+const ManifestFilename = "index.json"
+
+// ExistingFileMode controls what happens when a per-repository file already exists
+type ExistingFileMode string
+
+const (
+	ExistingOverwrite ExistingFileMode = "overwrite" // Replace the file with the new report
+	ExistingSkip      ExistingFileMode = "skip"      // Keep the existing file untouched
+	ExistingAppend    ExistingFileMode = "append"    // Keep earlier reports, file becomes a JSON array
+)
+
+// ParseExistingFileMode validates a --existing-files value
+func ParseExistingFileMode(value string) (ExistingFileMode, error) {
+	switch mode := ExistingFileMode(value); mode {
+	case ExistingOverwrite, ExistingSkip, ExistingAppend:
+		return mode, nil
+	}
+	return "", fmt.Errorf("invalid existing file mode '%s' (use overwrite, skip or append)", value)
+}
+
+// Manifest indexes the per-repository files of a run
+type Manifest struct {
+	GeneratedAt  time.Time       `json:"generated_at"`
+	Repositories []ManifestEntry `json:"repositories"`
+}
+
+// ManifestEntry describes one per-repository file
+type ManifestEntry struct {
+	Repository       string                 `json:"repository"`
+	File             string                 `json:"file"`
+	Action           string                 `json:"action"` // written, appended or skipped
+	OverallReadiness types.ValidationStatus `json:"overall_readiness,omitempty"`
+	Blockers         int                    `json:"blockers,omitempty"`
+	Cluster          string                 `json:"cluster,omitempty"`
+}
+
+// RepoFileWriterOptions configures a RepoFileWriter
+type RepoFileWriterOptions struct {
+	Dir      string
+	Existing ExistingFileMode
+	Fsync    bool // Flush every file to disk before it is renamed into place
+	Verbose  bool
+}
+
+// RepoFileWriter writes each repository's analysis to its own JSON file as soon as it is
+// available, so an interrupted batch keeps everything analyzed so far. Files are written to a
+// temporary file and renamed into place, so readers never see a partially written report.
+// With --encrypt-key, the files and the manifest are written encrypted.
+type RepoFileWriter struct {
+	options RepoFileWriterOptions
+	entries []ManifestEntry
+	errs    []error
+	mutex   sync.Mutex
+}
+
+// NewRepoFileWriter creates the output directory (if needed) and returns a writer for it
+func NewRepoFileWriter(options RepoFileWriterOptions) (*RepoFileWriter, error) {
+	if options.Dir == "" {
+		options.Dir = "."
+	}
+	if options.Existing == "" {
+		options.Existing = ExistingOverwrite
+	}
+	if err := os.MkdirAll(options.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory %s: %v", options.Dir, err)
+	}
+	return &RepoFileWriter{options: options}, nil
+}
+
+// Write stores a single repository's analysis and records it for the manifest. Failures do not
+// stop the batch; they are collected and returned together by Close.
+func (w *RepoFileWriter) Write(deps *types.OrganizationalDependencies) {
+	filename := generateSafeFilename(deps.Repository) + ".json"
+	path := filepath.Join(w.options.Dir, filename)
+
+	action, err := w.writeReport(path, deps)
+	if err != nil {
+		w.mutex.Lock()
+		w.errs = append(w.errs, fmt.Errorf("%s: %v", deps.Repository, err))
+		w.mutex.Unlock()
+		return
+	}
+
+	if w.options.Verbose {
+		fmt.Fprintf(os.Stderr, "%s %s\n", action, path)
+	}
+
+	entry := ManifestEntry{Repository: deps.Repository, File: filename, Action: action}
+	if deps.Validation != nil {
+		entry.OverallReadiness = deps.Validation.OverallReadiness
+		entry.Blockers = deps.Validation.Summary.Blockers
+	}
+
+	w.mutex.Lock()
+	w.entries = append(w.entries, entry)
+	w.mutex.Unlock()
+}
+
+func (w *RepoFileWriter) writeReport(path string, deps *types.OrganizationalDependencies) (string, error) {
+	existing, err := encryption.ReadFile(path)
+	exists := err == nil
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("failed to read existing file %s: %v", path, err)
+	}
+
+	var report interface{} = deps
+	action := "written"
+	if exists {
+		switch w.options.Existing {
+		case ExistingSkip:
+			return "skipped", nil
+		case ExistingAppend:
+			reports, err := appendReport(existing, deps)
+			if err != nil {
+				return "", fmt.Errorf("cannot append to %s: %v", path, err)
+			}
+			report = reports
+			action = "appended"
+		}
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode JSON for %s: %v", path, err)
+	}
+	if err := writeFileAtomic(path, append(data, '\n'), w.options.Fsync); err != nil {
+		return "", err
+	}
+	return action, nil
+}
+
+// appendReport adds a report to the content of an existing file, which holds either a single
+// report object or an array of earlier reports
+func appendReport(existing []byte, deps *types.OrganizationalDependencies) ([]json.RawMessage, error) {
+	var reports []json.RawMessage
+	trimmed := bytes.TrimSpace(existing)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &reports); err != nil {
+			return nil, err
+		}
+	} else if len(trimmed) > 0 {
+		if !json.Valid(trimmed) {
+			return nil, fmt.Errorf("existing content is not valid JSON")
+		}
+		reports = append(reports, json.RawMessage(trimmed))
+	}
+
+	data, err := json.Marshal(deps)
+	if err != nil {
+		return nil, err
+	}
+	return append(reports, json.RawMessage(data)), nil
+}
+
+// Count returns the number of repository files handled so far (written, appended or skipped)
+func (w *RepoFileWriter) Count() int {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return len(w.entries)
+}
+
+// SetClusters records the dependency cluster of each repository in the manifest; clusters
+// are only known once every repository was analyzed, so they are not in the files themselves
+func (w *RepoFileWriter) SetClusters(clusters []types.RepositoryCluster) {
+	names := make(map[string]string)
+	for _, cluster := range clusters {
+		for _, repository := range cluster.Repositories {
+			names[repository] = cluster.Name
+		}
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	for i := range w.entries {
+		w.entries[i].Cluster = names[w.entries[i].Repository]
+	}
+}
+
+// Close writes the manifest index listing every repository file and returns all write
+// failures of the run joined into one error
+func (w *RepoFileWriter) Close() error {
+	w.mutex.Lock()
+	entries := append([]ManifestEntry(nil), w.entries...)
+	errs := append([]error(nil), w.errs...)
+	w.mutex.Unlock()
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Repository < entries[j].Repository
+	})
+
+	data, err := json.MarshalIndent(Manifest{GeneratedAt: time.Now().UTC(), Repositories: entries}, "", "  ")
+	if err != nil {
+		errs = append(errs, fmt.Errorf("failed to marshal manifest: %v", err))
+	} else if err := writeFileAtomic(filepath.Join(w.options.Dir, ManifestFilename), append(data, '\n'), w.options.Fsync); err != nil {
+		errs = append(errs, err)
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%d per-repository file(s) could not be written:\n%v", len(errs), errors.Join(errs...))
+	}
+	return nil
+}
+
+// writeFileAtomic writes data, encrypted when a key is configured, to a temporary file in the
+// same directory and renames it over path, optionally syncing it to disk first
+func writeFileAtomic(path string, data []byte, fsync bool) error {
+	data, err := encryption.Encrypt(data)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt %s: %v", path, err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file for %s: %v", path, err)
+	}
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName) // No-op once the rename succeeded
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %v", path, err)
+	}
+	if fsync {
+		if err := tmp.Sync(); err != nil {
+			tmp.Close()
+			return fmt.Errorf("failed to sync %s: %v", path, err)
+		}
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close %s: %v", path, err)
+	}
+	if err := os.Chmod(tmpName, 0o644); err != nil {
+		return fmt.Errorf("failed to set permissions on %s: %v", path, err)
+	}
+	if err := os.Rename(tmpName, path); err != nil {
+		return fmt.Errorf("failed to move %s into place: %v", path, err)
+	}
+	return nil
+}
+
+// LoadRepoFiles reads every repo-analysis_*.json report previously written to dir, including
+// files from earlier runs that are not in the latest manifest. For files written in append
+// mode only the latest report is returned.
+func LoadRepoFiles(dir string) ([]*types.OrganizationalDependencies, error) {
+	files, err := repoFilesIn(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no per-repository reports found in %s", dir)
+	}
+
+	var allDeps []*types.OrganizationalDependencies
+	for _, file := range files {
+		data, err := encryption.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %v", file, err)
+		}
+
+		trimmed := bytes.TrimSpace(data)
+		if len(trimmed) > 0 && trimmed[0] == '[' {
+			var reports []json.RawMessage
+			if err := json.Unmarshal(trimmed, &reports); err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %v", file, err)
+			}
+			if len(reports) == 0 {
+				continue
+			}
+			trimmed = reports[len(reports)-1]
+		}
+
+		var deps types.OrganizationalDependencies
+		if err := json.Unmarshal(trimmed, &deps); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %v", file, err)
+		}
+		allDeps = append(allDeps, &deps)
+	}
+
+	return allDeps, nil
+}
+
+func repoFilesIn(dir string) ([]string, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return nil, fmt.Errorf("cannot read report directory: %v", err)
+	}
+	matches, err := filepath.Glob(filepath.Join(dir, "repo-analysis_*.json"))
+	if err != nil {
+		return nil, err
+	}
+	files := make([]string, 0, len(matches))
+	for _, match := range matches {
+		files = append(files, filepath.Base(match))
+	}
+	sort.Strings(files)
+	return files, nil
+}
@@ -0,0 +1,102 @@
+package output
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jefeish/gh-repo-transfer/internal/encryption"
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+func TestRepoFileWriterExistingModes(t *testing.T) {
+	deps := &types.OrganizationalDependencies{Repository: "acme/widget"}
+	path := func(dir string) string { return filepath.Join(dir, "repo-analysis_acme_widget.json") }
+
+	tests := []struct {
+		mode        ExistingFileMode
+		wantReports int // 0 means the file must still hold the original content
+	}{
+		{ExistingOverwrite, 1},
+		{ExistingSkip, 0},
+		{ExistingAppend, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			dir := t.TempDir()
+			original := []byte(`{"repository": "acme/widget"}`)
+			if err := os.WriteFile(path(dir), original, 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			writer, err := NewRepoFileWriter(RepoFileWriterOptions{Dir: dir, Existing: tt.mode})
+			if err != nil {
+				t.Fatal(err)
+			}
+			writer.Write(deps)
+			if err := writer.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+
+			data, err := os.ReadFile(path(dir))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			switch tt.wantReports {
+			case 0:
+				if string(data) != string(original) {
+					t.Errorf("file was modified in skip mode: %s", data)
+				}
+			case 1:
+				var report types.OrganizationalDependencies
+				if err := json.Unmarshal(data, &report); err != nil {
+					t.Errorf("expected a single report object: %v", err)
+				}
+			default:
+				var reports []json.RawMessage
+				if err := json.Unmarshal(data, &reports); err != nil || len(reports) != tt.wantReports {
+					t.Errorf("expected %d reports, got %d (err %v)", tt.wantReports, len(reports), err)
+				}
+			}
+
+			if _, err := os.Stat(filepath.Join(dir, ManifestFilename)); err != nil {
+				t.Errorf("manifest not written: %v", err)
+			}
+		})
+	}
+}
+
+func TestRepoFileWriterEncrypted(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("REPO_FILES_TEST_KEY", "s3cret")
+	if err := encryption.SetKey("env:REPO_FILES_TEST_KEY"); err != nil {
+		t.Fatal(err)
+	}
+	defer encryption.SetKey("")
+
+	for i := 0; i < 2; i++ {
+		writer, err := NewRepoFileWriter(RepoFileWriterOptions{Dir: dir, Existing: ExistingAppend})
+		if err != nil {
+			t.Fatal(err)
+		}
+		writer.Write(&types.OrganizationalDependencies{Repository: "acme/widget"})
+		if err := writer.Close(); err != nil {
+			t.Fatalf("Close() error = %v", err)
+		}
+	}
+
+	for _, file := range []string{"repo-analysis_acme_widget.json", ManifestFilename} {
+		data, err := os.ReadFile(filepath.Join(dir, file))
+		if err != nil || !encryption.IsEncrypted(data) || strings.Contains(string(data), "acme/widget") {
+			t.Errorf("%s is not encrypted: %q, %v", file, data, err)
+		}
+	}
+	reports, err := LoadRepoFiles(dir)
+	if err != nil || len(reports) != 1 || reports[0].Repository != "acme/widget" {
+		t.Errorf("LoadRepoFiles() = %v, %v", reports, err)
+	}
+}
@@ -0,0 +1,302 @@
+package output
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+	"github.com/jefeish/gh-repo-transfer/pkg/utils"
+)
+
+// readinessReport is the content of the markdown and html reports, shaped for sharing in
+// change tickets: a summary per repository, every blocker with its recommendation, and the
+// details of each dependency category
+type readinessReport struct {
+	Generated     string
+	Repositories  []reportRepository
+	Blockers      []reportBlocker
+	Validated     bool // At least one repository was validated against a target organization
+	EffortTotal   string
+	Organizations []OrgSummary // Only when more than one source org
+}
+
+// reportRepository is one repository of a readiness report
+type reportRepository struct {
+	Name       string
+	Target     string // Empty when the repository was not validated
+	Readiness  string
+	Summary    types.ValidationSummary
+	Effort     string
+	Categories []reportCategory
+}
+
+// reportCategory is a dependency category of a repository: its validation results, or the
+// dependencies found when the repository was not validated
+type reportCategory struct {
+	Name         string
+	Results      []types.ValidationResult
+	Dependencies []reportDependencyGroup
+}
+
+// reportDependencyGroup is one dependency list of a category, e.g. Organization Secrets
+type reportDependencyGroup struct {
+	Label string
+	Items []string
+}
+
+// reportBlocker is a blocker listed in the report's blocker table
+type reportBlocker struct {
+	Repository     string
+	Category       string
+	Item           string
+	Message        string
+	Recommendation string
+}
+
+// buildReadinessReport collects the report content of a batch of dependency reports
+func buildReadinessReport(allDeps []*types.OrganizationalDependencies, generated time.Time) readinessReport {
+	report := readinessReport{Generated: generated.UTC().Format("2006-01-02 15:04 MST")}
+	effortMinutes := 0
+	for _, deps := range allDeps {
+		repository := reportRepository{Name: deps.Repository, Readiness: notValidated}
+		if v := deps.Validation; v != nil {
+			report.Validated = true
+			repository.Target = v.TargetOrganization
+			repository.Readiness = string(v.OverallReadiness)
+			repository.Summary = v.Summary
+			if v.Effort != nil {
+				repository.Effort = utils.FormatMinutes(v.Effort.TotalMinutes)
+				effortMinutes += v.Effort.TotalMinutes
+			}
+		}
+		for _, category := range workbookCategories {
+			section := reportCategory{Name: category.sheet}
+			if deps.Validation != nil {
+				section.Results = category.validation(deps.Validation)
+				for _, result := range section.Results {
+					if result.Status == types.ValidationBlocker {
+						report.Blockers = append(report.Blockers, reportBlocker{
+							Repository: deps.Repository, Category: category.sheet, Item: result.Item,
+							Message: result.Message, Recommendation: result.Recommendation,
+						})
+					}
+				}
+			} else {
+				dependencies := category.dependencies(deps)
+				for _, label := range sortedKeys(dependencies) {
+					if len(dependencies[label]) > 0 {
+						section.Dependencies = append(section.Dependencies, reportDependencyGroup{Label: label, Items: dependencies[label]})
+					}
+				}
+			}
+			if len(section.Results) > 0 || len(section.Dependencies) > 0 {
+				repository.Categories = append(repository.Categories, section)
+			}
+		}
+		report.Repositories = append(report.Repositories, repository)
+	}
+	if effortMinutes > 0 {
+		report.EffortTotal = utils.FormatMinutes(effortMinutes)
+	}
+	if organizations := generateOrgSummaries(allDeps); len(organizations) > 1 {
+		report.Organizations = organizations
+	}
+	return report
+}
+
+// outputMarkdown writes the readiness report as markdown to stdout
+func outputMarkdown(allDeps []*types.OrganizationalDependencies) error {
+	return WriteMarkdown(os.Stdout, allDeps)
+}
+
+// outputHTML writes the readiness report as a standalone html page to stdout
+func outputHTML(allDeps []*types.OrganizationalDependencies) error {
+	return WriteHTML(os.Stdout, allDeps)
+}
+
+// WriteMarkdown writes the migration readiness report of a batch of dependency reports as
+// GitHub-flavored markdown
+func WriteMarkdown(w io.Writer, allDeps []*types.OrganizationalDependencies) error {
+	report := buildReadinessReport(allDeps, time.Now())
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# Migration Readiness Report\n\n")
+	fmt.Fprintf(&b, "Generated %s for %d repositories.", report.Generated, len(report.Repositories))
+	if report.EffortTotal != "" {
+		fmt.Fprintf(&b, " Estimated remediation effort: **%s**.", report.EffortTotal)
+	}
+	fmt.Fprintf(&b, "\n\n## Summary\n\n")
+	if report.Validated {
+		fmt.Fprintf(&b, "| Repository | Target | Readiness | Ready | Setup Needed | Blockers | Warnings | Review | Effort |\n")
+		fmt.Fprintf(&b, "|---|---|---|---:|---:|---:|---:|---:|---:|\n")
+		for _, repository := range report.Repositories {
+			fmt.Fprintf(&b, "| %s | %s | %s | %d | %d | %d | %d | %d | %s |\n", markdownCell(repository.Name), markdownCell(repository.Target),
+				strings.TrimSpace(readinessEmoji(repository.Readiness)+" "+repository.Readiness), repository.Summary.Ready, repository.Summary.SetupNeeded,
+				repository.Summary.Blockers, repository.Summary.Warnings, repository.Summary.Review, repository.Effort)
+		}
+	} else {
+		fmt.Fprintf(&b, "| Repository | Dependencies |\n|---|---:|\n")
+		for _, repository := range report.Repositories {
+			fmt.Fprintf(&b, "| %s | %d |\n", markdownCell(repository.Name), repository.DependencyCount())
+		}
+		fmt.Fprintf(&b, "\nThe repositories were not validated; run with `--target-org` for readiness and blockers.\n")
+	}
+
+	if len(report.Organizations) > 0 {
+		fmt.Fprintf(&b, "\n## By Organization\n\n")
+		fmt.Fprintf(&b, "| Organization | Repositories | With Blockers | Blockers | Effort | Top Blockers |\n|---|---:|---:|---:|---:|---|\n")
+		for _, org := range report.Organizations {
+			var topBlockers []string
+			for _, blocker := range org.TopBlockerTypes {
+				topBlockers = append(topBlockers, fmt.Sprintf("%s (%d)", blocker.Type, blocker.Count))
+			}
+			fmt.Fprintf(&b, "| %s | %d | %d | %d | %s | %s |\n", markdownCell(org.Organization), org.Repositories, org.ReposWithBlockers,
+				org.Blockers, utils.FormatMinutes(org.EffortMinutes), markdownCell(strings.Join(topBlockers, "; ")))
+		}
+	}
+
+	if report.Validated {
+		fmt.Fprintf(&b, "\n## Blockers\n\n")
+		if len(report.Blockers) == 0 {
+			fmt.Fprintf(&b, "No blockers. ✅\n")
+		} else {
+			fmt.Fprintf(&b, "| Repository | Category | Item | Problem | Recommendation |\n|---|---|---|---|---|\n")
+			for _, blocker := range report.Blockers {
+				fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n", markdownCell(blocker.Repository), blocker.Category,
+					markdownCell(blocker.Item), markdownCell(blocker.Message), markdownCell(blocker.Recommendation))
+			}
+		}
+	}
+
+	for _, repository := range report.Repositories {
+		fmt.Fprintf(&b, "\n## %s\n", repository.Name)
+		if repository.Target != "" {
+			fmt.Fprintf(&b, "\n%s **%s** for `%s`", readinessEmoji(repository.Readiness), repository.Readiness, repository.Target)
+			if repository.Effort != "" {
+				fmt.Fprintf(&b, ", estimated effort %s", repository.Effort)
+			}
+			fmt.Fprintf(&b, ".\n")
+		}
+		if len(repository.Categories) == 0 {
+			fmt.Fprintf(&b, "\nNo organizational dependencies found.\n")
+		}
+		for _, category := range repository.Categories {
+			fmt.Fprintf(&b, "\n### %s\n\n", category.Name)
+			if len(category.Results) > 0 {
+				fmt.Fprintf(&b, "| Item | Status | Message | Recommendation |\n|---|---|---|---|\n")
+				for _, result := range category.Results {
+					fmt.Fprintf(&b, "| %s | %s %s | %s | %s |\n", markdownCell(result.Item), getStatusEmoji(result.Status), result.Status,
+						markdownCell(result.Message), markdownCell(result.Recommendation))
+				}
+			}
+			for _, group := range category.Dependencies {
+				fmt.Fprintf(&b, "- **%s**: %s\n", group.Label, markdownCell(strings.Join(group.Items, ", ")))
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// markdownCell escapes a value for a markdown table cell; < and > are escaped so that
+// GitHub does not take them for html
+func markdownCell(value string) string {
+	value = strings.NewReplacer("|", `\|`, "<", "&lt;", ">", "&gt;").Replace(value)
+	return strings.ReplaceAll(strings.TrimSpace(value), "\n", "<br>")
+}
+
+// readinessEmoji is the status emoji of an overall readiness, empty when not validated
+func readinessEmoji(readiness string) string {
+	if readiness == notValidated {
+		return ""
+	}
+	return getStatusEmoji(types.ValidationStatus(readiness))
+}
+
+// DependencyCount counts the dependencies of an unvalidated repository
+func (r reportRepository) DependencyCount() int {
+	count := 0
+	for _, category := range r.Categories {
+		for _, group := range category.Dependencies {
+			count += len(group.Items)
+		}
+	}
+	return count
+}
+
+// WriteHTML writes the migration readiness report of a batch of dependency reports as a
+// standalone html page with inline styles
+func WriteHTML(w io.Writer, allDeps []*types.OrganizationalDependencies) error {
+	return htmlReport.Execute(w, buildReadinessReport(allDeps, time.Now()))
+}
+
+var htmlReport = template.Must(template.New("report").Funcs(template.FuncMap{
+	"emoji":   readinessEmoji,
+	"minutes": utils.FormatMinutes,
+	"statusEmoji": func(status types.ValidationStatus) string {
+		return getStatusEmoji(status)
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Migration Readiness Report</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #1f2328; }
+table { border-collapse: collapse; margin: 0.5em 0 1.5em; }
+th, td { border: 1px solid #d0d7de; padding: 4px 8px; text-align: left; vertical-align: top; }
+th { background: #f6f8fa; }
+td.number { text-align: right; }
+.blocker { background: #ffebe9; }
+.warning, .review { background: #fff8c5; }
+.setup_needed { background: #fff1c2; }
+.ready { background: #dafbe1; }
+</style>
+</head>
+<body>
+<h1>Migration Readiness Report</h1>
+<p>Generated {{.Generated}} for {{len .Repositories}} repositories.{{if .EffortTotal}} Estimated remediation effort: <strong>{{.EffortTotal}}</strong>.{{end}}</p>
+
+<h2>Summary</h2>
+{{if .Validated}}<table>
+<tr><th>Repository</th><th>Target</th><th>Readiness</th><th>Ready</th><th>Setup Needed</th><th>Blockers</th><th>Warnings</th><th>Review</th><th>Effort</th></tr>
+{{range .Repositories}}<tr><td><a href="#{{.Name}}">{{.Name}}</a></td><td>{{.Target}}</td><td class="{{.Readiness}}">{{emoji .Readiness}} {{.Readiness}}</td><td class="number">{{.Summary.Ready}}</td><td class="number">{{.Summary.SetupNeeded}}</td><td class="number">{{.Summary.Blockers}}</td><td class="number">{{.Summary.Warnings}}</td><td class="number">{{.Summary.Review}}</td><td>{{.Effort}}</td></tr>
+{{end}}</table>
+
+<h2>Blockers</h2>
+{{if .Blockers}}<table>
+<tr><th>Repository</th><th>Category</th><th>Item</th><th>Problem</th><th>Recommendation</th></tr>
+{{range .Blockers}}<tr><td>{{.Repository}}</td><td>{{.Category}}</td><td>{{.Item}}</td><td>{{.Message}}</td><td>{{.Recommendation}}</td></tr>
+{{end}}</table>
+{{else}}<p>No blockers. ✅</p>
+{{end}}{{else}}<table>
+<tr><th>Repository</th><th>Dependencies</th></tr>
+{{range .Repositories}}<tr><td><a href="#{{.Name}}">{{.Name}}</a></td><td class="number">{{.DependencyCount}}</td></tr>
+{{end}}</table>
+<p>The repositories were not validated; run with <code>--target-org</code> for readiness and blockers.</p>
+{{end}}{{if .Organizations}}
+<h2>By Organization</h2>
+<table>
+<tr><th>Organization</th><th>Repositories</th><th>With Blockers</th><th>Blockers</th><th>Effort</th><th>Top Blockers</th></tr>
+{{range .Organizations}}<tr><td>{{.Organization}}</td><td class="number">{{.Repositories}}</td><td class="number">{{.ReposWithBlockers}}</td><td class="number">{{.Blockers}}</td><td>{{minutes .EffortMinutes}}</td><td>{{range $i, $blocker := .TopBlockerTypes}}{{if $i}}; {{end}}{{$blocker.Type}} ({{$blocker.Count}}){{end}}</td></tr>
+{{end}}</table>
+{{end}}
+{{range .Repositories}}<h2 id="{{.Name}}">{{.Name}}</h2>
+{{if .Target}}<p>{{emoji .Readiness}} <strong>{{.Readiness}}</strong> for <code>{{.Target}}</code>{{if .Effort}}, estimated effort {{.Effort}}{{end}}.</p>
+{{end}}{{if not .Categories}}<p>No organizational dependencies found.</p>
+{{end}}{{range .Categories}}<h3>{{.Name}}</h3>
+{{if .Results}}<table>
+<tr><th>Item</th><th>Status</th><th>Message</th><th>Recommendation</th></tr>
+{{range .Results}}<tr><td>{{.Item}}</td><td class="{{.Status}}">{{statusEmoji .Status}} {{.Status}}</td><td>{{.Message}}</td><td>{{.Recommendation}}</td></tr>
+{{end}}</table>
+{{end}}{{if .Dependencies}}<ul>
+{{range .Dependencies}}<li><strong>{{.Label}}</strong>: {{range $i, $item := .Items}}{{if $i}}, {{end}}{{$item}}{{end}}</li>
+{{end}}</ul>
+{{end}}{{end}}{{end}}</body>
+</html>
+`))
@@ -0,0 +1,94 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+func readinessReportFixture() []*types.OrganizationalDependencies {
+	validated := &types.OrganizationalDependencies{Repository: "acme/web", Validation: &types.MigrationValidation{
+		TargetOrganization: "new-org",
+		OverallReadiness:   types.ValidationBlocker,
+		Summary:            types.ValidationSummary{Ready: 1, Blockers: 1, Total: 2},
+		Effort:             &types.EffortEstimate{TotalMinutes: 75},
+		AccessPermissions: []types.ValidationResult{
+			{Item: "platform|ops", Status: types.ValidationBlocker, Message: "Team not found", Recommendation: "Create the team <platform>"},
+			{Item: "ops", Status: types.ValidationReady},
+		},
+	}}
+	unvalidated := &types.OrganizationalDependencies{Repository: "acme/api"}
+	unvalidated.ActionsCIDependencies.OrganizationSecrets = []string{"NPM_TOKEN (in ci.yml, job build)"}
+	return []*types.OrganizationalDependencies{validated, unvalidated}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	var buffer bytes.Buffer
+	if err := WriteMarkdown(&buffer, readinessReportFixture()); err != nil {
+		t.Fatal(err)
+	}
+	report := buffer.String()
+	for _, want := range []string{
+		"# Migration Readiness Report",
+		"Estimated remediation effort: **1h 15m**",
+		"| acme/web | new-org | 🔴 blocker | 1 | 0 | 1 | 0 | 0 | 1h 15m |",
+		"## Blockers",
+		`| acme/web | Access | platform\|ops | Team not found | Create the team &lt;platform&gt; |`,
+		"## acme/api",
+		"- **Organization Secrets**: NPM_TOKEN (in ci.yml, job build)",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("markdown report lacks %q:\n%s", want, report)
+		}
+	}
+}
+
+func TestReportOrganizations(t *testing.T) {
+	allDeps := append(readinessReportFixture(), &types.OrganizationalDependencies{Repository: "globex/billing"})
+
+	var markdown bytes.Buffer
+	if err := WriteMarkdown(&markdown, allDeps); err != nil {
+		t.Fatal(err)
+	}
+	if want := "| acme | 2 | 1 | 1 | 1h 15m | Team not found (1) |"; !strings.Contains(markdown.String(), want) {
+		t.Errorf("markdown report lacks %q:\n%s", want, markdown.String())
+	}
+
+	var html bytes.Buffer
+	if err := WriteHTML(&html, allDeps); err != nil {
+		t.Fatal(err)
+	}
+	if want := "<td>globex</td>"; !strings.Contains(html.String(), want) {
+		t.Errorf("html report lacks %q:\n%s", want, html.String())
+	}
+
+	// A single organization needs no roll-up
+	markdown.Reset()
+	if err := WriteMarkdown(&markdown, readinessReportFixture()); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(markdown.String(), "## By Organization") {
+		t.Errorf("markdown report of one organization has a roll-up:\n%s", markdown.String())
+	}
+}
+
+func TestWriteHTML(t *testing.T) {
+	var buffer bytes.Buffer
+	if err := WriteHTML(&buffer, readinessReportFixture()); err != nil {
+		t.Fatal(err)
+	}
+	report := buffer.String()
+	for _, want := range []string{
+		"<h1>Migration Readiness Report</h1>",
+		`<h2 id="acme/web">acme/web</h2>`,
+		"Create the team &lt;platform&gt;",
+		`<td class="blocker">`,
+		"<strong>Organization Secrets</strong>: NPM_TOKEN (in ci.yml, job build)",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("html report lacks %q:\n%s", want, report)
+		}
+	}
+}
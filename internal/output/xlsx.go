@@ -0,0 +1,377 @@
+package output
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/term"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+// worksheet is one sheet of an xlsx workbook. Values are strings or ints; the header row is
+// bold, frozen and filterable, and the status column is colored by validation status.
+type worksheet struct {
+	name         string
+	header       []string
+	rows         [][]interface{}
+	statusColumn int // Zero-based column colored by status, -1 for none
+}
+
+// statusFormats maps validation statuses to the differential formats in xlsxStyles (dxfId)
+var statusFormats = []struct {
+	status types.ValidationStatus
+	dxfID  int
+}{
+	{types.ValidationBlocker, 0},
+	{types.ValidationWarning, 1},
+	{types.ValidationReview, 1},
+	{types.ValidationSetupNeeded, 2},
+	{types.ValidationReady, 3},
+	{types.ValidationUnknown, 4},
+	{types.ValidationSuppressed, 4},
+}
+
+// notValidated marks dependency rows of repositories analyzed without --target-org
+const notValidated = "not_validated"
+
+// outputXLSX writes a workbook with a summary sheet and one sheet per dependency category
+func outputXLSX(allDeps []*types.OrganizationalDependencies) error {
+	if term.FromEnv().IsTerminalOutput() {
+		return fmt.Errorf("xlsx output is binary; redirect it to a file, e.g. --format xlsx > report.xlsx")
+	}
+	var buffer bytes.Buffer
+	if err := WriteWorkbook(&buffer, allDeps); err != nil {
+		return err
+	}
+	_, err := os.Stdout.Write(buffer.Bytes())
+	return err
+}
+
+// WriteWorkbook writes the xlsx workbook of a batch of dependency reports
+func WriteWorkbook(w io.Writer, allDeps []*types.OrganizationalDependencies) error {
+	sheets := []worksheet{summarySheet(allDeps)}
+	for _, category := range workbookCategories {
+		sheets = append(sheets, categorySheet(category, allDeps))
+	}
+	return writeXLSX(w, sheets)
+}
+
+// workbookCategory is a dependency category with its validation results and dependency lists
+type workbookCategory struct {
+	sheet        string
+	validation   func(*types.MigrationValidation) []types.ValidationResult
+	dependencies func(*types.OrganizationalDependencies) map[string][]string
+}
+
+var workbookCategories = []workbookCategory{
+	{"Code", func(v *types.MigrationValidation) []types.ValidationResult { return v.CodeDependencies }, func(d *types.OrganizationalDependencies) map[string][]string {
+		return map[string][]string{
+			"Internal Repository References":     d.CodeDependencies.InternalRepositoryReferences,
+			"Git Submodules":                     d.CodeDependencies.GitSubmodules,
+			"Organization Package Registries":    d.CodeDependencies.OrgPackageRegistries,
+			"Hard-coded Organization References": d.CodeDependencies.HardcodedOrgReferences,
+			"Organization Container Registries":  d.CodeDependencies.OrgSpecificContainerRegistries,
+			"Documentation URLs":                 d.CodeDependencies.DocumentationURLReferences,
+		}
+	}},
+	{"CI-CD", func(v *types.MigrationValidation) []types.ValidationResult { return v.CIDependencies }, func(d *types.OrganizationalDependencies) map[string][]string {
+		return map[string][]string{
+			"Organization Secrets":          d.ActionsCIDependencies.OrganizationSecrets,
+			"Organization Variables":        d.ActionsCIDependencies.OrganizationVariables,
+			"Self-hosted Runners":           d.ActionsCIDependencies.SelfHostedRunners,
+			"Network-bound Runner Groups":   d.ActionsCIDependencies.NetworkBoundRunnerGroups,
+			"Static IP Runners":             d.ActionsCIDependencies.StaticIPRunners,
+			"Artifact Attestations":         d.ActionsCIDependencies.ArtifactAttestations,
+			"Environment Dependencies":      d.ActionsCIDependencies.EnvironmentDependencies,
+			"Organization-specific Actions": d.ActionsCIDependencies.OrgSpecificActions,
+			"Required Workflows":            d.ActionsCIDependencies.RequiredWorkflows,
+			"Cross-repo Workflow Triggers":  d.ActionsCIDependencies.CrossRepoWorkflowTriggers,
+			"Repository Secrets":            d.ActionsCIDependencies.RepositorySecrets,
+			"Repository Variables":          d.ActionsCIDependencies.RepositoryVariables,
+			"Environment Reviewers":         d.ActionsCIDependencies.EnvironmentReviewers,
+			"Environment Secrets":           d.ActionsCIDependencies.EnvironmentSecrets,
+			"Environment Variables":         d.ActionsCIDependencies.EnvironmentVariables,
+			"Pinned Action Consumers":       d.ActionsCIDependencies.PinnedActionConsumers,
+			"Dispatch Consumers":            d.ActionsCIDependencies.DispatchConsumers,
+		}
+	}},
+	{"Access", func(v *types.MigrationValidation) []types.ValidationResult { return v.AccessPermissions }, func(d *types.OrganizationalDependencies) map[string][]string {
+		return map[string][]string{
+			"Teams":                    d.AccessPermissions.Teams,
+			"Individual Collaborators": d.AccessPermissions.IndividualCollaborators,
+			"Organization Roles":       d.AccessPermissions.OrganizationRoles,
+			"Organization Membership":  d.AccessPermissions.OrganizationMembership,
+			"CODEOWNERS Requirements":  d.AccessPermissions.CodeownersRequirements,
+			"Deploy Keys":              d.AccessPermissions.DeployKeys,
+		}
+	}},
+	{"Security", func(v *types.MigrationValidation) []types.ValidationResult { return v.SecurityCompliance }, func(d *types.OrganizationalDependencies) map[string][]string {
+		return map[string][]string{
+			"Security Campaigns": d.SecurityCompliance.SecurityCampaigns,
+		}
+	}},
+	{"Apps", func(v *types.MigrationValidation) []types.ValidationResult { return v.AppsIntegrations }, func(d *types.OrganizationalDependencies) map[string][]string {
+		return map[string][]string{
+			"Installed GitHub Apps":  d.AppsIntegrations.InstalledGitHubApps,
+			"Personal Access Tokens": d.AppsIntegrations.PersonalAccessTokens,
+			"Repository Webhooks":    d.AppsIntegrations.Webhooks,
+			"GitHub Pages":           d.AppsIntegrations.Pages,
+		}
+	}},
+	{"Governance", func(v *types.MigrationValidation) []types.ValidationResult { return v.Governance }, func(d *types.OrganizationalDependencies) map[string][]string {
+		return map[string][]string{
+			"Repository Policies":    policyNames(d.OrgGovernance.RepositoryPolicies),
+			"Repository Rulesets":    policyNames(d.OrgGovernance.RepositoryRulesets),
+			"Issue Templates":        d.OrgGovernance.IssueTemplates,
+			"Pull Request Templates": d.OrgGovernance.PullRequestTemplates,
+			"Required Status Checks": d.OrgGovernance.RequiredStatusChecks,
+			"Event Sinks":            d.OrgGovernance.EventSinks,
+			"Enterprise Policies":    policyNames(d.OrgGovernance.EnterprisePolicies),
+			"Push Rulesets":          pushRulesetDescriptions(d.OrgGovernance.PushRulesets),
+		}
+	}},
+}
+
+// summarySheet has one row per repository with its readiness and validation counts
+func summarySheet(allDeps []*types.OrganizationalDependencies) worksheet {
+	sheet := worksheet{
+		name:         "Summary",
+		header:       []string{"Repository", "Organization", "Overall Readiness", "Ready", "Setup Needed", "Blockers", "Warnings", "Review", "Unknown", "Suppressed", "Effort (minutes)", "Cluster"},
+		statusColumn: 2,
+	}
+	for _, deps := range allDeps {
+		row := []interface{}{deps.Repository, strings.Split(deps.Repository, "/")[0], notValidated, 0, 0, 0, 0, 0, 0, 0, 0, deps.Cluster}
+		if v := deps.Validation; v != nil {
+			effort := 0
+			if v.Effort != nil {
+				effort = v.Effort.TotalMinutes
+			}
+			row = []interface{}{deps.Repository, strings.Split(deps.Repository, "/")[0], string(v.OverallReadiness),
+				v.Summary.Ready, v.Summary.SetupNeeded, v.Summary.Blockers, v.Summary.Warnings, v.Summary.Review, v.Summary.Unknown, v.Summary.Suppressed, effort, deps.Cluster}
+		}
+		sheet.rows = append(sheet.rows, row)
+	}
+	return sheet
+}
+
+// categorySheet lists the validation results of one category, or the raw dependencies of
+// repositories that were not validated
+func categorySheet(category workbookCategory, allDeps []*types.OrganizationalDependencies) worksheet {
+	sheet := worksheet{
+		name:         category.sheet,
+		header:       []string{"Repository", "Item", "Status", "Message", "Recommendation", "Finding", "Kind"},
+		statusColumn: 2,
+	}
+	for _, deps := range allDeps {
+		if deps.Validation != nil {
+			for _, result := range category.validation(deps.Validation) {
+				sheet.rows = append(sheet.rows, []interface{}{deps.Repository, result.Item, string(result.Status), result.Message, result.Recommendation, result.ID, result.Kind})
+			}
+			continue
+		}
+		dependencies := category.dependencies(deps)
+		for _, label := range sortedKeys(dependencies) {
+			for _, item := range dependencies[label] {
+				sheet.rows = append(sheet.rows, []interface{}{deps.Repository, item, notValidated, label, ""})
+			}
+		}
+	}
+	return sheet
+}
+
+// policyNames returns the names of policies
+func policyNames(policies []types.OrgPolicy) []string {
+	var names []string
+	for _, policy := range policies {
+		names = append(names, policy.Name)
+	}
+	return names
+}
+
+// writeXLSX writes a minimal SpreadsheetML package; strings are stored inline so no shared
+// string table is needed
+func writeXLSX(w io.Writer, sheets []worksheet) error {
+	archive := zip.NewWriter(w)
+
+	var overrides, workbookSheets, relationships, definedNames strings.Builder
+	for i, sheet := range sheets {
+		fmt.Fprintf(&overrides, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i+1)
+		fmt.Fprintf(&workbookSheets, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, escapeXML(sheet.name), i+1, i+1)
+		fmt.Fprintf(&relationships, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i+1, i+1)
+		fmt.Fprintf(&definedNames, `<definedName name="_xlnm._FilterDatabase" localSheetId="%d" hidden="1">'%s'!$A$1:$%s$%d</definedName>`,
+			i, escapeXML(sheet.name), columnName(len(sheet.header)-1), len(sheet.rows)+1)
+	}
+	fmt.Fprintf(&relationships, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`, len(sheets)+1)
+
+	parts := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+			`<Default Extension="xml" ContentType="application/xml"/>` +
+			`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+			`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+			overrides.String() + `</Types>`},
+		{"_rels/.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+			`</Relationships>`},
+		{"xl/workbook.xml", xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets>` + workbookSheets.String() + `</sheets><definedNames>` + definedNames.String() + `</definedNames></workbook>`},
+		{"xl/_rels/workbook.xml.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			relationships.String() + `</Relationships>`},
+		{"xl/styles.xml", xlsxStyles},
+	}
+	for i, sheet := range sheets {
+		parts = append(parts, struct {
+			name    string
+			content string
+		}{fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1), sheetXML(sheet)})
+	}
+
+	for _, part := range parts {
+		file, err := archive.Create(part.name)
+		if err != nil {
+			return fmt.Errorf("failed to write workbook: %v", err)
+		}
+		if _, err := io.WriteString(file, part.content); err != nil {
+			return fmt.Errorf("failed to write workbook: %v", err)
+		}
+	}
+	return archive.Close()
+}
+
+// sheetXML renders a worksheet with a frozen, filterable header row and status coloring
+func sheetXML(sheet worksheet) string {
+	var b strings.Builder
+	lastColumn := columnName(len(sheet.header) - 1)
+	lastRow := len(sheet.rows) + 1
+
+	b.WriteString(xml.Header)
+	b.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">`)
+	fmt.Fprintf(&b, `<dimension ref="A1:%s%d"/>`, lastColumn, lastRow)
+	b.WriteString(`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>`)
+	b.WriteString(`<cols>`)
+	for i, title := range sheet.header {
+		fmt.Fprintf(&b, `<col min="%d" max="%d" width="%d" customWidth="1"/>`, i+1, i+1, columnWidth(sheet, i, title))
+	}
+	b.WriteString(`</cols><sheetData>`)
+
+	header := make([]interface{}, len(sheet.header))
+	for i, title := range sheet.header {
+		header[i] = title
+	}
+	writeRow(&b, 1, header, 1)
+	for i, row := range sheet.rows {
+		writeRow(&b, i+2, row, 0)
+	}
+	b.WriteString(`</sheetData>`)
+
+	fmt.Fprintf(&b, `<autoFilter ref="A1:%s%d"/>`, lastColumn, lastRow)
+	if sheet.statusColumn >= 0 && len(sheet.rows) > 0 {
+		column := columnName(sheet.statusColumn)
+		fmt.Fprintf(&b, `<conditionalFormatting sqref="%s2:%s%d">`, column, column, lastRow)
+		for i, format := range statusFormats {
+			fmt.Fprintf(&b, `<cfRule type="cellIs" dxfId="%d" priority="%d" operator="equal"><formula>"%s"</formula></cfRule>`,
+				format.dxfID, i+1, format.status)
+		}
+		b.WriteString(`</conditionalFormatting>`)
+	}
+	b.WriteString(`</worksheet>`)
+	return b.String()
+}
+
+// writeRow renders one row; style 1 is the bold header style
+func writeRow(b *strings.Builder, rowNumber int, values []interface{}, style int) {
+	fmt.Fprintf(b, `<row r="%d">`, rowNumber)
+	for i, value := range values {
+		ref := fmt.Sprintf("%s%d", columnName(i), rowNumber)
+		styleAttr := ""
+		if style != 0 {
+			styleAttr = fmt.Sprintf(` s="%d"`, style)
+		}
+		switch v := value.(type) {
+		case int:
+			fmt.Fprintf(b, `<c r="%s"%s><v>%d</v></c>`, ref, styleAttr, v)
+		default:
+			fmt.Fprintf(b, `<c r="%s"%s t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, ref, styleAttr, escapeXML(fmt.Sprint(v)))
+		}
+	}
+	b.WriteString(`</row>`)
+}
+
+// columnWidth sizes a column to its longest value, within reasonable bounds
+func columnWidth(sheet worksheet, column int, title string) int {
+	width := len(title)
+	for _, row := range sheet.rows {
+		if column < len(row) {
+			if length := len(fmt.Sprint(row[column])); length > width {
+				width = length
+			}
+		}
+	}
+	if width < 10 {
+		width = 10
+	}
+	if width > 80 {
+		width = 80
+	}
+	return width + 2
+}
+
+// columnName converts a zero-based column index to its spreadsheet letters (0 → A, 26 → AA)
+func columnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+// escapeXML escapes text for use in element content and attribute values
+func escapeXML(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// sortedKeys returns the keys of a dependency map in a stable order
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// xlsxStyles defines the bold header style and the status colors used by conditional formatting:
+// 0 blocker (red), 1 warning/review (amber), 2 setup needed (yellow), 3 ready (green), 4 unknown (grey)
+var xlsxStyles = xml.Header + `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="2"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/></cellXfs>` +
+	`<cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles>` +
+	`<dxfs count="5">` +
+	statusDxf("FF9C0006", "FFFFC7CE") +
+	statusDxf("FF9C5700", "FFFFE0B2") +
+	statusDxf("FF7F6000", "FFFFEB9C") +
+	statusDxf("FF006100", "FFC6EFCE") +
+	statusDxf("FF3F3F3F", "FFD9D9D9") +
+	`</dxfs></styleSheet>`
+
+// statusDxf renders a differential format with a font and background color
+func statusDxf(fontColor, fillColor string) string {
+	return `<dxf><font><color rgb="` + fontColor + `"/></font><fill><patternFill><bgColor rgb="` + fillColor + `"/></patternFill></fill></dxf>`
+}
@@ -0,0 +1,78 @@
+package output
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+func TestColumnName(t *testing.T) {
+	tests := map[int]string{0: "A", 2: "C", 25: "Z", 26: "AA", 27: "AB", 701: "ZZ", 702: "AAA"}
+	for index, want := range tests {
+		if got := columnName(index); got != want {
+			t.Errorf("columnName(%d) = %q, want %q", index, got, want)
+		}
+	}
+}
+
+func TestWriteWorkbook(t *testing.T) {
+	validated := &types.OrganizationalDependencies{Repository: "acme/web", Validation: &types.MigrationValidation{
+		OverallReadiness: types.ValidationBlocker,
+		Summary:          types.ValidationSummary{Blockers: 1, Total: 1},
+		AccessPermissions: []types.ValidationResult{
+			{Item: "Team: <platform> & ops", Status: types.ValidationBlocker, Message: "Team not found"},
+		},
+	}}
+	unvalidated := &types.OrganizationalDependencies{Repository: "acme/api"}
+	unvalidated.ActionsCIDependencies.OrganizationSecrets = []string{"NPM_TOKEN"}
+
+	var buffer bytes.Buffer
+	if err := WriteWorkbook(&buffer, []*types.OrganizationalDependencies{validated, unvalidated}); err != nil {
+		t.Fatal(err)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatalf("workbook is not a zip archive: %v", err)
+	}
+
+	contents := make(map[string]string)
+	for _, file := range archive.File {
+		reader, err := file.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(reader)
+		reader.Close()
+		contents[file.Name] = string(data)
+
+		// Every part must be well-formed XML
+		decoder := xml.NewDecoder(bytes.NewReader(data))
+		for {
+			if _, err := decoder.Token(); err == io.EOF {
+				break
+			} else if err != nil {
+				t.Fatalf("%s is not well-formed: %v", file.Name, err)
+			}
+		}
+	}
+
+	// Summary plus one sheet per category
+	if want := 1 + len(workbookCategories); strings.Count(contents["xl/workbook.xml"], "<sheet ") != want {
+		t.Errorf("workbook does not list %d sheets: %s", want, contents["xl/workbook.xml"])
+	}
+	if !strings.Contains(contents["xl/worksheets/sheet4.xml"], "Team: &lt;platform&gt; &amp; ops") {
+		t.Errorf("access sheet does not contain the escaped validation item")
+	}
+	if !strings.Contains(contents["xl/worksheets/sheet3.xml"], "NPM_TOKEN") || !strings.Contains(contents["xl/worksheets/sheet3.xml"], notValidated) {
+		t.Errorf("CI-CD sheet does not list the unvalidated dependency")
+	}
+	if !strings.Contains(contents["xl/worksheets/sheet1.xml"], `<autoFilter ref="A1:L3"/>`) {
+		t.Errorf("summary sheet has no filter over its rows")
+	}
+}
@@ -0,0 +1,220 @@
+// Package plan reads and writes migration plan files: the dependencies, validation and
+// intended actions of a transfer or archive, written by 'plan' and executed by 'apply' so the
+// migration can be reviewed (e.g. in a pull request) before it runs
+package plan
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jefeish/gh-repo-transfer/internal/encryption"
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+// currentVersion is bumped whenever the layout of a plan file changes incompatibly
+const currentVersion = 1
+
+// Operations a plan can describe
+const (
+	OperationTransfer = "transfer"
+	OperationArchive  = "archive"
+)
+
+// Plan is a reviewable migration plan
+type Plan struct {
+	Version      int          `json:"version"`
+	CreatedAt    time.Time    `json:"created_at"`
+	Operation    string       `json:"operation"` // transfer or archive
+	TargetOrg    string       `json:"target_org"`
+	StagingOrg   string       `json:"staging_org,omitempty"` // Intermediate org of a two-hop transfer; 'promote' runs the second hop
+	ScannedAt    time.Time    `json:"scanned_at,omitempty"`  // When the target (and staging) org capabilities were scanned; the older scan
+	Options      Options      `json:"options"`
+	Repositories []Repository `json:"repositories"`
+}
+
+// Options are the command options the plan was made with; apply runs with the same options
+type Options struct {
+	Assign                  bool     `json:"assign,omitempty"`
+	CreateTeams             bool     `json:"create_teams,omitempty"`
+	AddTopics               []string `json:"add_topics,omitempty"`
+	RemoveTopics            []string `json:"remove_topics,omitempty"`
+	DefaultBranch           string   `json:"default_branch,omitempty"`
+	SettingsProfile         string   `json:"settings_profile,omitempty"` // Path of the profile, read again on apply
+	Verify                  bool     `json:"verify,omitempty"`
+	Announce                bool     `json:"announce,omitempty"`
+	CleanupSource           bool     `json:"cleanup_source,omitempty"`
+	CreateTombstone         bool     `json:"create_tombstone,omitempty"`
+	RewriteCodeowners       bool     `json:"rewrite_codeowners,omitempty"`
+	RewriteActions          bool     `json:"rewrite_actions,omitempty"`
+	RewriteActionsDir       string   `json:"rewrite_actions_dir,omitempty"`
+	MigrateWebhooks         bool     `json:"migrate_webhooks,omitempty"`
+	MigratePages            bool     `json:"migrate_pages,omitempty"`
+	MigrateBranchProtection bool     `json:"migrate_branch_protection,omitempty"`
+	MigrateRulesets         bool     `json:"migrate_rulesets,omitempty"`
+	MigrateEnvironments     bool     `json:"migrate_environments,omitempty"`
+	PatchRulesetIncludes    bool     `json:"patch_ruleset_includes,omitempty"`
+	AllowPermissionChange   bool     `json:"allow_permission_change,omitempty"`
+	ArchiveAfter            string   `json:"archive_after,omitempty"`
+	TeamMatcher             string   `json:"team_matcher,omitempty"`
+	PolicyFile              string   `json:"policy_file,omitempty"`
+	Provision               bool     `json:"provision,omitempty"`
+	ProvisionValues         string   `json:"provision_values,omitempty"` // Path of the values file, read again on apply
+	OriginTracking          string   `json:"origin_tracking,omitempty"`
+}
+
+// Repository is the plan for one repository
+type Repository struct {
+	Repository   string                            `json:"repository"`
+	ArchivedName string                            `json:"archived_name,omitempty"` // Name in the target org (archive only)
+	Refused      string                            `json:"refused,omitempty"`       // Why the repository cannot be moved at all, e.g. a legal hold
+	Dependencies *types.OrganizationalDependencies `json:"dependencies"`
+	Teams        []types.Team                      `json:"teams,omitempty"`   // Source team permissions (with --assign); the second hop assigns these
+	Staging      *Hop                              `json:"staging,omitempty"` // First hop into the staging org (two-hop plans only)
+	Validation   *types.MigrationValidation        `json:"validation"`        // Validation against the target org
+	Actions      []Action                          `json:"actions"`           // Steps into the target org
+}
+
+// Hop is the validation and steps of one move of a multi-hop plan
+type Hop struct {
+	Validation *types.MigrationValidation `json:"validation"`
+	Actions    []Action                   `json:"actions"`
+}
+
+// Action is a step the operation will run for a repository
+type Action struct {
+	Step        string `json:"step"`
+	Description string `json:"description"`
+}
+
+// Blocked reports whether the repository would not be moved as planned: it is refused or
+// validation of any hop found blockers
+func (r Repository) Blocked() bool {
+	if r.Staging != nil && r.Staging.Validation != nil && r.Staging.Validation.Summary.Blockers > 0 {
+		return true
+	}
+	return r.Refused != "" || (r.Validation != nil && r.Validation.Summary.Blockers > 0)
+}
+
+// ScanAge returns how old the capability scans the plan was validated against are at now. Plans
+// written without ScannedAt fall back to the oldest scan time of their validations; ok is false
+// when neither is known.
+func (p *Plan) ScanAge(now time.Time) (age time.Duration, ok bool) {
+	scannedAt := p.ScannedAt
+	if scannedAt.IsZero() {
+		for _, planned := range p.Repositories {
+			for _, validation := range []*types.MigrationValidation{planned.Validation, planned.stagingValidation()} {
+				if validation == nil || validation.CapabilitiesScannedAt.IsZero() {
+					continue
+				}
+				if scannedAt.IsZero() || validation.CapabilitiesScannedAt.Before(scannedAt) {
+					scannedAt = validation.CapabilitiesScannedAt
+				}
+			}
+		}
+	}
+	if scannedAt.IsZero() {
+		return 0, false
+	}
+	return now.Sub(scannedAt), true
+}
+
+// stagingValidation returns the validation of the first hop, nil for one-hop plans
+func (r Repository) stagingValidation() *types.MigrationValidation {
+	if r.Staging == nil {
+		return nil
+	}
+	return r.Staging.Validation
+}
+
+// StagedPath returns where a repository lives in the staging org between the two hops
+func (p *Plan) StagedPath(repository string) string {
+	name := repository
+	if i := strings.LastIndex(repository, "/"); i >= 0 {
+		name = repository[i+1:]
+	}
+	return fmt.Sprintf("%s/%s", p.StagingOrg, name)
+}
+
+// FindStaged returns the plan of the repository staged at the given path
+func (p *Plan) FindStaged(path string) (Repository, bool) {
+	if p == nil || p.StagingOrg == "" {
+		return Repository{}, false
+	}
+	for _, planned := range p.Repositories {
+		if strings.EqualFold(p.StagedPath(planned.Repository), path) {
+			return planned, true
+		}
+	}
+	return Repository{}, false
+}
+
+// Find returns the plan of a repository, matched case-insensitively
+func (p *Plan) Find(repository string) (Repository, bool) {
+	if p == nil {
+		return Repository{}, false
+	}
+	for _, planned := range p.Repositories {
+		if strings.EqualFold(planned.Repository, repository) {
+			return planned, true
+		}
+	}
+	return Repository{}, false
+}
+
+// Names returns the planned repositories in plan order
+func (p *Plan) Names() []string {
+	names := make([]string, 0, len(p.Repositories))
+	for _, planned := range p.Repositories {
+		names = append(names, planned.Repository)
+	}
+	return names
+}
+
+// Write stores the plan as indented JSON so it diffs well in review
+func Write(path string, p *Plan) error {
+	p.Version = currentVersion
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal plan: %v", err)
+	}
+	if data, err = encryption.Encrypt(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to encrypt plan: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write plan file %s: %v", path, err)
+	}
+	return nil
+}
+
+// Read loads and checks a plan file
+func Read(path string) (*Plan, error) {
+	data, err := encryption.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plan file %s: %v", path, err)
+	}
+	var p Plan
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("failed to parse plan file %s: %v", path, err)
+	}
+	if p.Version != currentVersion {
+		return nil, fmt.Errorf("plan file %s has unsupported version %d (expected %d)", path, p.Version, currentVersion)
+	}
+	if p.Operation != OperationTransfer && p.Operation != OperationArchive {
+		return nil, fmt.Errorf("plan file %s has unknown operation %q", path, p.Operation)
+	}
+	if p.TargetOrg == "" || len(p.Repositories) == 0 {
+		return nil, fmt.Errorf("plan file %s has no target organization or repositories", path)
+	}
+	if p.StagingOrg != "" && (p.Operation != OperationTransfer || strings.EqualFold(p.StagingOrg, p.TargetOrg)) {
+		return nil, fmt.Errorf("plan file %s has an invalid staging organization %s (transfers only, distinct from the target)", path, p.StagingOrg)
+	}
+	for _, planned := range p.Repositories {
+		if p.Operation == OperationArchive && planned.ArchivedName == "" {
+			return nil, fmt.Errorf("plan file %s has no archived name for %s", path, planned.Repository)
+		}
+	}
+	return &p, nil
+}
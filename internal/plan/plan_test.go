@@ -0,0 +1,151 @@
+package plan
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jefeish/gh-repo-transfer/internal/encryption"
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+func TestWriteRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plan.json")
+	want := &Plan{
+		CreatedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		ScannedAt: time.Date(2026, 1, 2, 3, 1, 0, 0, time.UTC),
+		Operation: OperationArchive,
+		TargetOrg: "archive-org",
+		Options:   Options{Assign: true, AddTopics: []string{"archived"}},
+		Repositories: []Repository{{
+			Repository:   "acme/app",
+			ArchivedName: "app-2JKLX9A7",
+			Validation:   &types.MigrationValidation{TargetOrganization: "archive-org", Summary: types.ValidationSummary{Blockers: 1}},
+			Actions:      []Action{{Step: "transfer", Description: "Transfer the repository"}},
+		}},
+	}
+
+	if err := Write(path, want); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	got, err := Read(path)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Read() = %+v, want %+v", got, want)
+	}
+
+	planned, ok := got.Find("ACME/App")
+	if !ok || !planned.Blocked() {
+		t.Errorf("Find() = %+v, %v; want the blocked acme/app plan", planned, ok)
+	}
+}
+
+func TestWriteReadEncrypted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plan.json")
+	t.Setenv("PLAN_TEST_KEY", "s3cret")
+	if err := encryption.SetKey("env:PLAN_TEST_KEY"); err != nil {
+		t.Fatal(err)
+	}
+	defer encryption.SetKey("")
+
+	want := &Plan{Operation: OperationTransfer, TargetOrg: "new-org", Repositories: []Repository{{Repository: "acme/app"}}}
+	if err := Write(path, want); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || !encryption.IsEncrypted(data) || strings.Contains(string(data), "new-org") {
+		t.Fatalf("plan file is not encrypted: %q, %v", data, err)
+	}
+	if got, err := Read(path); err != nil || got.TargetOrg != "new-org" {
+		t.Errorf("Read() = %+v, %v", got, err)
+	}
+
+	encryption.SetKey("")
+	if _, err := Read(path); err == nil || !strings.Contains(err.Error(), "--encrypt-key") {
+		t.Errorf("Read() without key error = %v, want a hint to --encrypt-key", err)
+	}
+}
+
+func TestReadRejectsInvalidPlans(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"unsupported version", `{"version": 9, "operation": "transfer", "target_org": "x", "repositories": [{"repository": "a/b"}]}`, "unsupported version"},
+		{"unknown operation", `{"version": 1, "operation": "delete", "target_org": "x", "repositories": [{"repository": "a/b"}]}`, "unknown operation"},
+		{"no repositories", `{"version": 1, "operation": "transfer", "target_org": "x"}`, "no target organization or repositories"},
+		{"staging for an archive", `{"version": 1, "operation": "archive", "target_org": "x", "staging_org": "q", "repositories": [{"repository": "a/b", "archived_name": "b-1"}]}`, "invalid staging organization"},
+		{"staging is the target", `{"version": 1, "operation": "transfer", "target_org": "x", "staging_org": "X", "repositories": [{"repository": "a/b"}]}`, "invalid staging organization"},
+		{"archive without name", `{"version": 1, "operation": "archive", "target_org": "x", "repositories": [{"repository": "a/b"}]}`, "no archived name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "plan.json")
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := Read(path)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Read() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestStagedPath(t *testing.T) {
+	p := &Plan{
+		TargetOrg:  "final",
+		StagingOrg: "quarantine",
+		Repositories: []Repository{
+			{Repository: "acme/app"},
+			{Repository: "acme/api", Staging: &Hop{Validation: &types.MigrationValidation{Summary: types.ValidationSummary{Blockers: 2}}}},
+		},
+	}
+
+	if got := p.StagedPath("acme/app"); got != "quarantine/app" {
+		t.Errorf("StagedPath() = %q, want quarantine/app", got)
+	}
+	planned, ok := p.FindStaged("Quarantine/API")
+	if !ok || planned.Repository != "acme/api" {
+		t.Fatalf("FindStaged() = %+v, %v; want acme/api", planned, ok)
+	}
+	if !planned.Blocked() {
+		t.Errorf("Blocked() = false, want true for staging hop blockers")
+	}
+	if _, ok := p.FindStaged("acme/app"); ok {
+		t.Errorf("FindStaged() matched a source path")
+	}
+}
+
+func TestScanAge(t *testing.T) {
+	now := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+	validation := func(scannedAt time.Time) *types.MigrationValidation {
+		return &types.MigrationValidation{CapabilitiesScannedAt: scannedAt}
+	}
+
+	tests := []struct {
+		name    string
+		plan    *Plan
+		wantAge time.Duration
+		wantOK  bool
+	}{
+		{"recorded scan time", &Plan{ScannedAt: now.Add(-time.Hour), Repositories: []Repository{{Validation: validation(now)}}}, time.Hour, true},
+		{"oldest validation scan of an older plan", &Plan{Repositories: []Repository{
+			{Validation: validation(now.Add(-10 * time.Minute))},
+			{Validation: validation(now.Add(-5 * time.Minute)), Staging: &Hop{Validation: validation(now.Add(-20 * time.Minute))}},
+		}}, 20 * time.Minute, true},
+		{"unknown", &Plan{Repositories: []Repository{{Validation: validation(time.Time{})}}}, 0, false},
+	}
+	for _, tt := range tests {
+		if age, ok := tt.plan.ScanAge(now); age != tt.wantAge || ok != tt.wantOK {
+			t.Errorf("%s: ScanAge() = %s, %v; want %s, %v", tt.name, age, ok, tt.wantAge, tt.wantOK)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+// Package policy loads the migration policy file (--policy-file): organization rules the tool
+// enforces on every transfer and archive, such as legal holds
+package policy
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Policy is the content of a policy file
+type Policy struct {
+	LegalHold LegalHold `yaml:"legal_hold"`
+}
+
+// LegalHold lists the repository markers that put a repository on hold. A repository on hold
+// is never transferred or archived, even with --enforce.
+type LegalHold struct {
+	Properties map[string]string `yaml:"properties"` // Custom property name → value
+	Topics     []string          `yaml:"topics"`
+}
+
+// Default is the policy used without a policy file: the legal-hold custom property set to
+// true, or the legal-hold topic
+func Default() *Policy {
+	return &Policy{LegalHold: LegalHold{
+		Properties: map[string]string{"legal-hold": "true"},
+		Topics:     []string{"legal-hold"},
+	}}
+}
+
+// Load reads a policy file; without a path the default policy is returned. A file replaces
+// the default, so an empty legal_hold section disables the check.
+func Load(path string) (*Policy, error) {
+	if path == "" {
+		return Default(), nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy file %s: %v", path, err)
+	}
+
+	var policy Policy
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&policy); err != nil {
+		return nil, fmt.Errorf("failed to parse policy file %s: %v", path, err)
+	}
+	return &policy, nil
+}
+
+// Enabled reports whether any legal hold marker is configured
+func (h LegalHold) Enabled() bool {
+	return len(h.Properties) > 0 || len(h.Topics) > 0
+}
+
+// Reasons returns the markers of a repository that put it on hold, or nil when it is not on
+// hold. properties holds the repository's custom property values (multi-select properties
+// have several); names and values are compared case-insensitively.
+func (h LegalHold) Reasons(properties map[string][]string, topics []string) []string {
+	var reasons []string
+
+	names := make([]string, 0, len(h.Properties))
+	for name := range h.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		want := h.Properties[name]
+		for actualName, values := range properties {
+			if !strings.EqualFold(actualName, name) {
+				continue
+			}
+			for _, value := range values {
+				if strings.EqualFold(value, want) {
+					reasons = append(reasons, fmt.Sprintf("custom property %s=%s", actualName, value))
+				}
+			}
+		}
+	}
+
+	for _, topic := range h.Topics {
+		for _, actual := range topics {
+			if strings.EqualFold(actual, topic) {
+				reasons = append(reasons, fmt.Sprintf("topic %s", actual))
+			}
+		}
+	}
+	return reasons
+}
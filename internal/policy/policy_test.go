@@ -0,0 +1,75 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLegalHoldReasons(t *testing.T) {
+	hold := Default().LegalHold
+
+	tests := []struct {
+		name       string
+		properties map[string][]string
+		topics     []string
+		want       []string
+	}{
+		{
+			name:       "no markers",
+			properties: map[string][]string{"team": {"payments"}},
+			topics:     []string{"go"},
+		},
+		{
+			name:       "property value other than true",
+			properties: map[string][]string{"legal-hold": {"false"}},
+		},
+		{
+			name:       "property and topic, case-insensitive",
+			properties: map[string][]string{"Legal-Hold": {"TRUE"}},
+			topics:     []string{"legal-hold"},
+			want:       []string{"custom property Legal-Hold=TRUE", "topic legal-hold"},
+		},
+		{
+			name:       "multi-select property value",
+			properties: map[string][]string{"legal-hold": {"pending", "true"}},
+			want:       []string{"custom property legal-hold=true"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hold.Reasons(tt.properties, tt.topics); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Reasons() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	content := "legal_hold:\n  properties:\n    retention: litigation\n  topics: [do-not-archive]\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	want := &Policy{LegalHold: LegalHold{
+		Properties: map[string]string{"retention": "litigation"},
+		Topics:     []string{"do-not-archive"},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Load() = %+v, want %+v", got, want)
+	}
+
+	if err := os.WriteFile(path, []byte("legal_holds: {}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Error("Load() accepted an unknown field")
+	}
+}
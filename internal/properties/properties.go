@@ -0,0 +1,130 @@
+// Package properties compares the custom property definitions of two organizations so the
+// target can be given the same schema before repositories (and their values) move
+package properties
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Change statuses
+const (
+	StatusCreate   = "create"   // Missing in the target
+	StatusUpdate   = "update"   // Present in the target with different settings
+	StatusSame     = "same"     // Identical in both organizations
+	StatusConflict = "conflict" // Different value type; not changed since target values would be invalidated
+)
+
+// Definition is an org custom property definition
+type Definition struct {
+	PropertyName     string          `json:"property_name"`
+	ValueType        string          `json:"value_type"`
+	Required         bool            `json:"required,omitempty"`
+	DefaultValue     json.RawMessage `json:"default_value,omitempty"`
+	Description      string          `json:"description,omitempty"`
+	AllowedValues    []string        `json:"allowed_values,omitempty"`
+	ValuesEditableBy string          `json:"values_editable_by,omitempty"`
+}
+
+// Change is the planned change of one property definition in the target
+type Change struct {
+	Name        string     `json:"name"`
+	Status      string     `json:"status"`
+	Definition  Definition `json:"definition"`
+	Differences []string   `json:"differences,omitempty"`
+}
+
+// Diff plans the changes that give the target the source's property definitions. Allowed values
+// are merged rather than replaced, so values already used in the target stay valid.
+func Diff(source, target []Definition) []Change {
+	existing := make(map[string]Definition, len(target))
+	for _, definition := range target {
+		existing[strings.ToLower(definition.PropertyName)] = definition
+	}
+
+	var changes []Change
+	for _, definition := range source {
+		current, ok := existing[strings.ToLower(definition.PropertyName)]
+		if !ok {
+			changes = append(changes, Change{Name: definition.PropertyName, Status: StatusCreate, Definition: definition})
+			continue
+		}
+		if !strings.EqualFold(current.ValueType, definition.ValueType) {
+			changes = append(changes, Change{
+				Name:        definition.PropertyName,
+				Status:      StatusConflict,
+				Definition:  current,
+				Differences: []string{fmt.Sprintf("value_type: %s in target, %s in source", current.ValueType, definition.ValueType)},
+			})
+			continue
+		}
+
+		merged := definition
+		merged.PropertyName = current.PropertyName
+		merged.AllowedValues = mergeValues(current.AllowedValues, definition.AllowedValues)
+		differences := differences(current, merged)
+		status := StatusUpdate
+		if len(differences) == 0 {
+			status = StatusSame
+		}
+		changes = append(changes, Change{Name: current.PropertyName, Status: status, Definition: merged, Differences: differences})
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		return strings.ToLower(changes[i].Name) < strings.ToLower(changes[j].Name)
+	})
+	return changes
+}
+
+// differences lists the settings that differ between the current and desired definition
+func differences(current, desired Definition) []string {
+	var diffs []string
+	if current.Required != desired.Required {
+		diffs = append(diffs, fmt.Sprintf("required: %t → %t", current.Required, desired.Required))
+	}
+	if string(current.DefaultValue) != string(desired.DefaultValue) {
+		diffs = append(diffs, fmt.Sprintf("default_value: %s → %s", valueOrNone(current.DefaultValue), valueOrNone(desired.DefaultValue)))
+	}
+	if current.Description != desired.Description {
+		diffs = append(diffs, "description")
+	}
+	if added := missing(current.AllowedValues, desired.AllowedValues); len(added) > 0 {
+		diffs = append(diffs, fmt.Sprintf("allowed_values: add %s", strings.Join(added, ", ")))
+	}
+	if desired.ValuesEditableBy != "" && current.ValuesEditableBy != desired.ValuesEditableBy {
+		diffs = append(diffs, fmt.Sprintf("values_editable_by: %s → %s", current.ValuesEditableBy, desired.ValuesEditableBy))
+	}
+	return diffs
+}
+
+// mergeValues appends the source's allowed values missing from the target's list
+func mergeValues(target, source []string) []string {
+	if len(target) == 0 {
+		return source
+	}
+	return append(append([]string{}, target...), missing(target, source)...)
+}
+
+// missing returns the values of b that are not in a
+func missing(a, b []string) []string {
+	present := make(map[string]bool, len(a))
+	for _, value := range a {
+		present[value] = true
+	}
+	var result []string
+	for _, value := range b {
+		if !present[value] {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+func valueOrNone(value json.RawMessage) string {
+	if len(value) == 0 || string(value) == "null" {
+		return "none"
+	}
+	return string(value)
+}
@@ -0,0 +1,39 @@
+package properties
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	source := []Definition{
+		{PropertyName: "team", ValueType: "single_select", AllowedValues: []string{"platform", "data"}, Required: true, DefaultValue: json.RawMessage(`"platform"`)},
+		{PropertyName: "repo-origin", ValueType: "string"},
+		{PropertyName: "tier", ValueType: "single_select", AllowedValues: []string{"1", "2"}},
+		{PropertyName: "owner", ValueType: "string", Description: "Owning team"},
+	}
+	target := []Definition{
+		{PropertyName: "Team", ValueType: "single_select", AllowedValues: []string{"web", "platform"}},
+		{PropertyName: "tier", ValueType: "string"},
+		{PropertyName: "owner", ValueType: "string", Description: "Owning team"},
+	}
+
+	want := []Change{
+		{Name: "owner", Status: StatusSame, Definition: source[3]},
+		{Name: "repo-origin", Status: StatusCreate, Definition: source[1]},
+		{
+			Name:   "Team",
+			Status: StatusUpdate,
+			Definition: Definition{PropertyName: "Team", ValueType: "single_select", AllowedValues: []string{"web", "platform", "data"},
+				Required: true, DefaultValue: json.RawMessage(`"platform"`)},
+			Differences: []string{"required: false → true", `default_value: none → "platform"`, "allowed_values: add data"},
+		},
+		{Name: "tier", Status: StatusConflict, Definition: target[1], Differences: []string{"value_type: string in target, single_select in source"}},
+	}
+
+	got := Diff(source, target)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff() =\n%+v\nwant\n%+v", got, want)
+	}
+}
@@ -0,0 +1,183 @@
+// Package redirects builds the map of URLs a repository move breaks outside github.com's own
+// redirect: release pages and assets, the GitHub Pages site and raw content. Reverse proxies
+// and link shorteners load the map to keep old links working.
+package redirects
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+
+	"github.com/jefeish/gh-repo-transfer/internal/encryption"
+	"github.com/jefeish/gh-repo-transfer/internal/ghclient"
+)
+
+// Kinds of redirects
+const (
+	KindRelease = "release"
+	KindAsset   = "asset"
+	KindPages   = "pages"
+	KindRaw     = "raw"
+)
+
+// Match modes: a prefix redirect keeps the rest of the path, an exact one maps a single URL
+const (
+	MatchPrefix = "prefix"
+	MatchExact  = "exact"
+)
+
+// Redirect maps an old URL to the URL of the moved repository
+type Redirect struct {
+	Repository string `json:"repository"` // Old owner/repo
+	Kind       string `json:"kind"`
+	Match      string `json:"match"`
+	OldURL     string `json:"old_url"`
+	NewURL     string `json:"new_url"`
+}
+
+// Release is a release with the download URLs of its assets
+type Release struct {
+	TagName string  `json:"tag_name"`
+	Assets  []Asset `json:"assets"`
+}
+
+// Asset is a release asset
+type Asset struct {
+	Name               string `json:"name"`
+	BrowserDownloadURL string `json:"browser_download_url"`
+}
+
+// Site is the GitHub Pages site of a repository
+type Site struct {
+	HTMLURL string // e.g. https://acme.github.io/docs/
+	CNAME   string // Custom domain; it moves with the repository, so nothing redirects
+}
+
+// Build returns the redirects for moving oldPath to newPath ("owner/repo"). releases and site
+// may be empty; site is nil when the repository has no Pages site.
+func Build(oldPath, newPath string, releases []Release, site *Site) []Redirect {
+	var redirects []Redirect
+	add := func(kind, match, oldURL, newURL string) {
+		redirects = append(redirects, Redirect{Repository: oldPath, Kind: kind, Match: match, OldURL: oldURL, NewURL: newURL})
+	}
+
+	if len(releases) > 0 {
+		add(KindRelease, MatchPrefix, ghclient.WebURL(oldPath+"/releases/"), ghclient.WebURL(newPath+"/releases/"))
+	}
+	oldDownloads := ghclient.WebURL(oldPath + "/releases/download/")
+	for _, release := range releases {
+		for _, asset := range release.Assets {
+			if !strings.HasPrefix(asset.BrowserDownloadURL, oldDownloads) {
+				continue
+			}
+			add(KindAsset, MatchExact, asset.BrowserDownloadURL,
+				ghclient.WebURL(newPath+"/releases/download/"+strings.TrimPrefix(asset.BrowserDownloadURL, oldDownloads)))
+		}
+	}
+
+	if site != nil && site.CNAME == "" && site.HTMLURL != "" {
+		add(KindPages, MatchPrefix, withTrailingSlash(site.HTMLURL), PagesURL(newPath))
+	}
+
+	add(KindRaw, MatchPrefix, ghclient.RawURL(oldPath), ghclient.RawURL(newPath))
+	return redirects
+}
+
+// PagesURL is the default Pages URL of a repository: <owner>.github.io/<repo>/, or the root of
+// the owner's domain for the repository named <owner>.github.io
+func PagesURL(path string) string {
+	parts := strings.SplitN(path, "/", 2)
+	owner := strings.ToLower(parts[0])
+	if len(parts) == 2 && strings.EqualFold(parts[1], owner+".github.io") {
+		return fmt.Sprintf("https://%s.github.io/", owner)
+	}
+	return fmt.Sprintf("https://%s.github.io/%s/", owner, parts[len(parts)-1])
+}
+
+func withTrailingSlash(rawURL string) string {
+	if !strings.HasSuffix(rawURL, "/") {
+		return rawURL + "/"
+	}
+	return rawURL
+}
+
+// Collect reads the releases and Pages site of owner/repo and builds its redirects to newPath
+func Collect(client api.RESTClient, owner, repo, newPath string) ([]Redirect, error) {
+	var releases []Release
+	if err := ghclient.GetAll(&client, fmt.Sprintf("repos/%s/%s/releases", owner, repo), &releases); err != nil {
+		return nil, fmt.Errorf("failed to list releases: %v", err)
+	}
+
+	var pages struct {
+		HTMLURL string  `json:"html_url"`
+		CNAME   *string `json:"cname"`
+	}
+	var site *Site
+	if err := client.Get(fmt.Sprintf("repos/%s/%s/pages", owner, repo), &pages); err == nil {
+		site = &Site{HTMLURL: pages.HTMLURL}
+		if pages.CNAME != nil {
+			site.CNAME = *pages.CNAME
+		}
+	} else if !strings.Contains(err.Error(), "404") {
+		return nil, fmt.Errorf("failed to read the Pages site: %v", err)
+	}
+
+	return Build(fmt.Sprintf("%s/%s", owner, repo), newPath, releases, site), nil
+}
+
+// WriteFile writes the redirects as CSV, or as JSON when path ends in .json, encrypted when a
+// key is configured
+func WriteFile(path string, redirects []Redirect) error {
+	var buffer bytes.Buffer
+	var err error
+	sort.SliceStable(redirects, func(i, j int) bool { return redirects[i].Repository < redirects[j].Repository })
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = WriteJSON(&buffer, redirects)
+	} else {
+		err = WriteCSV(&buffer, redirects)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write redirect map: %v", err)
+	}
+	data, err := encryption.Encrypt(buffer.Bytes())
+	if err != nil {
+		return fmt.Errorf("failed to encrypt redirect map: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to create redirect map: %v", err)
+	}
+	return nil
+}
+
+// WriteCSV writes the redirects with a header row
+func WriteCSV(w io.Writer, redirects []Redirect) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"repository", "kind", "match", "old_url", "new_url"}); err != nil {
+		return err
+	}
+	for _, redirect := range redirects {
+		if err := writer.Write([]string{redirect.Repository, redirect.Kind, redirect.Match, redirect.OldURL, redirect.NewURL}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteJSON writes the redirects as an indented JSON array
+func WriteJSON(w io.Writer, redirects []Redirect) error {
+	if redirects == nil {
+		redirects = []Redirect{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(redirects)
+}
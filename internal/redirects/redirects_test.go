@@ -0,0 +1,64 @@
+package redirects
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBuild(t *testing.T) {
+	releases := []Release{{
+		TagName: "v1.0",
+		Assets: []Asset{
+			{Name: "tool.tar.gz", BrowserDownloadURL: "https://github.com/acme/tool/releases/download/v1.0/tool.tar.gz"},
+			{Name: "mirror.zip", BrowserDownloadURL: "https://cdn.example.com/mirror.zip"},
+		},
+	}}
+	got := Build("acme/tool", "new-org/tool", releases, &Site{HTMLURL: "https://acme.github.io/tool"})
+	want := []Redirect{
+		{Repository: "acme/tool", Kind: KindRelease, Match: MatchPrefix, OldURL: "https://github.com/acme/tool/releases/", NewURL: "https://github.com/new-org/tool/releases/"},
+		{Repository: "acme/tool", Kind: KindAsset, Match: MatchExact, OldURL: "https://github.com/acme/tool/releases/download/v1.0/tool.tar.gz", NewURL: "https://github.com/new-org/tool/releases/download/v1.0/tool.tar.gz"},
+		{Repository: "acme/tool", Kind: KindPages, Match: MatchPrefix, OldURL: "https://acme.github.io/tool/", NewURL: "https://new-org.github.io/tool/"},
+		{Repository: "acme/tool", Kind: KindRaw, Match: MatchPrefix, OldURL: "https://raw.githubusercontent.com/acme/tool/", NewURL: "https://raw.githubusercontent.com/new-org/tool/"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Build() =\n%+v\nwant\n%+v", got, want)
+	}
+
+	// Without releases and with a custom domain only raw content moves
+	got = Build("acme/docs", "new-org/docs", nil, &Site{HTMLURL: "https://docs.acme.com/", CNAME: "docs.acme.com"})
+	if len(got) != 1 || got[0].Kind != KindRaw {
+		t.Errorf("Build() with a custom domain = %+v, want only the raw redirect", got)
+	}
+}
+
+func TestPagesURL(t *testing.T) {
+	tests := map[string]string{
+		"New-Org/docs":              "https://new-org.github.io/docs/",
+		"new-org/new-org.github.io": "https://new-org.github.io/",
+		"new-org/acme.github.io":    "https://new-org.github.io/acme.github.io/",
+	}
+	for path, want := range tests {
+		if got := PagesURL(path); got != want {
+			t.Errorf("PagesURL(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	redirects := []Redirect{{Repository: "acme/tool", Kind: KindRaw, Match: MatchPrefix, OldURL: "https://raw.githubusercontent.com/acme/tool/", NewURL: "https://raw.githubusercontent.com/new-org/tool/"}}
+	if err := WriteCSV(&buf, redirects); err != nil {
+		t.Fatalf("WriteCSV() error = %v", err)
+	}
+	want := "repository,kind,match,old_url,new_url\nacme/tool,raw,prefix,https://raw.githubusercontent.com/acme/tool/,https://raw.githubusercontent.com/new-org/tool/\n"
+	if buf.String() != want {
+		t.Errorf("WriteCSV() = %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	if err := WriteJSON(&buf, nil); err != nil || strings.TrimSpace(buf.String()) != "[]" {
+		t.Errorf("WriteJSON(nil) = %q, %v; want []", buf.String(), err)
+	}
+}
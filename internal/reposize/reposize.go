@@ -0,0 +1,126 @@
+// Package reposize measures repositories before they are moved. Transfers of repositories over
+// a few GB, or with very large objects, take long to complete and occasionally fail.
+package reposize
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+const (
+	// LargeRepositoryKB is the size above which a repository is reported as large (2 GB)
+	LargeRepositoryKB = 2 * 1024 * 1024
+	// LargeObjectBytes is the blob size above which an object is reported, GitHub's own
+	// warning limit for pushed files (50 MB)
+	LargeObjectBytes = 50 * 1024 * 1024
+
+	// settleBase is how long a moved repository is waited for at least
+	settleBase = 30 * time.Second
+	// settlePerGB is the additional wait per started GB of repository size
+	settlePerGB = 60 * time.Second
+	// settleMax caps the wait for the largest repositories
+	settleMax = 15 * time.Minute
+)
+
+// Stats is the size of a repository and its largest object on the default branch
+type Stats struct {
+	SizeKB        int    `json:"size_kb"`                  // Repository size as reported by GitHub
+	LargestObject string `json:"largest_object,omitempty"` // Path of the largest blob on the default branch
+	LargestBytes  int64  `json:"largest_bytes,omitempty"`
+	Truncated     bool   `json:"truncated,omitempty"` // The tree was too big to list completely
+}
+
+// Measure reads the size of a repository and the largest blob of its default branch. Empty
+// repositories have no tree and are reported with their size alone.
+func Measure(client api.RESTClient, owner, repo string) (*Stats, error) {
+	var repository struct {
+		Size          int    `json:"size"`
+		DefaultBranch string `json:"default_branch"`
+	}
+	if err := client.Get(fmt.Sprintf("repos/%s/%s", owner, repo), &repository); err != nil {
+		return nil, fmt.Errorf("failed to read %s/%s: %v", owner, repo, err)
+	}
+	stats := &Stats{SizeKB: repository.Size}
+	if repository.DefaultBranch == "" || repository.Size == 0 {
+		return stats, nil
+	}
+
+	var tree struct {
+		Tree      []TreeEntry `json:"tree"`
+		Truncated bool        `json:"truncated"`
+	}
+	path := fmt.Sprintf("repos/%s/%s/git/trees/%s?recursive=1", owner, repo, url.PathEscape(repository.DefaultBranch))
+	if err := client.Get(path, &tree); err != nil {
+		if strings.Contains(err.Error(), "409") {
+			return stats, nil
+		}
+		return stats, fmt.Errorf("failed to list the tree of %s/%s: %v", owner, repo, err)
+	}
+	stats.LargestObject, stats.LargestBytes = Largest(tree.Tree)
+	stats.Truncated = tree.Truncated
+	return stats, nil
+}
+
+// TreeEntry is an entry of a recursive git tree listing
+type TreeEntry struct {
+	Path string `json:"path"`
+	Type string `json:"type"`
+	Size int64  `json:"size"`
+}
+
+// Largest returns the path and size of the largest blob of a tree
+func Largest(entries []TreeEntry) (string, int64) {
+	var path string
+	var size int64
+	for _, entry := range entries {
+		if entry.Type == "blob" && entry.Size > size {
+			path, size = entry.Path, entry.Size
+		}
+	}
+	return path, size
+}
+
+// Warnings describes what makes the repository slow or risky to move, if anything
+func (s *Stats) Warnings() []string {
+	if s == nil {
+		return nil
+	}
+	var warnings []string
+	if s.SizeKB > LargeRepositoryKB {
+		warnings = append(warnings, fmt.Sprintf("repository is %s; the transfer may take long to complete and occasionally fails", FormatKB(s.SizeKB)))
+	}
+	if s.LargestBytes > LargeObjectBytes {
+		warnings = append(warnings, fmt.Sprintf("largest object %s is %s", s.LargestObject, FormatKB(int(s.LargestBytes/1024))))
+	}
+	return warnings
+}
+
+// SettleTimeout is how long a moved repository is waited for before the steps after the
+// transfer run: 30 seconds, plus a minute per started GB, at most 15 minutes. Repositories
+// of unknown size get the base wait.
+func (s *Stats) SettleTimeout() time.Duration {
+	if s == nil {
+		return settleBase
+	}
+	gigabytes := (s.SizeKB + 1024*1024 - 1) / (1024 * 1024)
+	timeout := settleBase + time.Duration(gigabytes)*settlePerGB
+	if timeout > settleMax {
+		return settleMax
+	}
+	return timeout
+}
+
+// FormatKB formats a size in KB, e.g. "512 KB", "12.5 MB" or "3.2 GB"
+func FormatKB(kb int) string {
+	switch {
+	case kb >= 1024*1024:
+		return fmt.Sprintf("%.1f GB", float64(kb)/(1024*1024))
+	case kb >= 1024:
+		return fmt.Sprintf("%.1f MB", float64(kb)/1024)
+	}
+	return fmt.Sprintf("%d KB", kb)
+}
@@ -0,0 +1,59 @@
+package reposize
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWarnings(t *testing.T) {
+	tests := []struct {
+		name  string
+		stats *Stats
+		want  []string
+	}{
+		{"unknown", nil, nil},
+		{"small", &Stats{SizeKB: 2048, LargestObject: "logo.png", LargestBytes: 40 * 1024}, nil},
+		{"large repository", &Stats{SizeKB: 3 * 1024 * 1024}, []string{
+			"repository is 3.0 GB; the transfer may take long to complete and occasionally fails",
+		}},
+		{"large object", &Stats{SizeKB: 200 * 1024, LargestObject: "assets/video.mp4", LargestBytes: 80 * 1024 * 1024}, []string{
+			"largest object assets/video.mp4 is 80.0 MB",
+		}},
+	}
+	for _, tt := range tests {
+		if got := tt.stats.Warnings(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Warnings() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSettleTimeout(t *testing.T) {
+	tests := []struct {
+		stats *Stats
+		want  time.Duration
+	}{
+		{nil, 30 * time.Second},
+		{&Stats{SizeKB: 0}, 30 * time.Second},
+		{&Stats{SizeKB: 500 * 1024}, 90 * time.Second},
+		{&Stats{SizeKB: 5 * 1024 * 1024}, 330 * time.Second},
+		{&Stats{SizeKB: 100 * 1024 * 1024}, 15 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := tt.stats.SettleTimeout(); got != tt.want {
+			t.Errorf("SettleTimeout(%+v) = %s, want %s", tt.stats, got, tt.want)
+		}
+	}
+}
+
+func TestLargest(t *testing.T) {
+	entries := []TreeEntry{
+		{Path: "src", Type: "tree"},
+		{Path: "src/main.go", Type: "blob", Size: 2048},
+		{Path: "docs/diagram.psd", Type: "blob", Size: 9000000},
+		{Path: "vendor/lib", Type: "commit"},
+	}
+	if path, size := Largest(entries); path != "docs/diagram.psd" || size != 9000000 {
+		t.Errorf("Largest() = %q, %d", path, size)
+	}
+}
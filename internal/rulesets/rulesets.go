@@ -0,0 +1,189 @@
+// Package rulesets serializes organization rulesets so they can be recreated in another
+// organization, remapping the organization-specific IDs of bypass actors
+package rulesets
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Bypass actor types
+const (
+	ActorTeam              = "Team"
+	ActorIntegration       = "Integration"
+	ActorRepositoryRole    = "RepositoryRole"
+	ActorOrganizationAdmin = "OrganizationAdmin"
+	ActorDeployKey         = "DeployKey"
+)
+
+// builtinRoles are the repository role IDs GitHub uses in every organization
+var builtinRoles = map[int64]string{1: "read", 2: "triage", 3: "write", 4: "maintain", 5: "admin"}
+
+// Export is the file written by 'rulesets export'
+type Export struct {
+	Organization string    `json:"organization"`
+	ExportedAt   time.Time `json:"exported_at"`
+	Rulesets     []Ruleset `json:"rulesets"`
+}
+
+// Ruleset is an org ruleset without its organization-specific identity (ID, source, links)
+type Ruleset struct {
+	Name         string                     `json:"name"`
+	Target       string                     `json:"target,omitempty"`
+	Enforcement  string                     `json:"enforcement"`
+	Conditions   map[string]json.RawMessage `json:"conditions,omitempty"`
+	Rules        []json.RawMessage          `json:"rules,omitempty"`
+	BypassActors []BypassActor              `json:"bypass_actors,omitempty"`
+}
+
+// BypassActor is a bypass actor; ActorName is added on export so the actor can be found again
+// in the target organization (team slug, app slug or custom role name)
+type BypassActor struct {
+	ActorID    *int64 `json:"actor_id"`
+	ActorType  string `json:"actor_type"`
+	BypassMode string `json:"bypass_mode"`
+	ActorName  string `json:"actor_name,omitempty"`
+}
+
+// ActorMap renames actors between organizations (--actor-map); unmapped actors keep their name
+type ActorMap struct {
+	Teams map[string]string `yaml:"teams"`
+	Apps  map[string]string `yaml:"apps"`
+	Roles map[string]string `yaml:"roles"`
+}
+
+// TargetActors holds the IDs of the actors available in the target organization, by name
+type TargetActors struct {
+	Teams map[string]int64 // Team slug → ID
+	Apps  map[string]int64 // App slug → app ID
+	Roles map[string]int64 // Custom repository role name → ID
+}
+
+// NameActors fills ActorName from the source organization's teams, apps and roles (by ID)
+func NameActors(actors []BypassActor, teams, apps, roles map[int64]string) []BypassActor {
+	named := make([]BypassActor, len(actors))
+	for i, actor := range actors {
+		named[i] = actor
+		if actor.ActorID == nil {
+			continue
+		}
+		switch actor.ActorType {
+		case ActorTeam:
+			named[i].ActorName = teams[*actor.ActorID]
+		case ActorIntegration:
+			named[i].ActorName = apps[*actor.ActorID]
+		case ActorRepositoryRole:
+			if name, ok := builtinRoles[*actor.ActorID]; ok {
+				named[i].ActorName = name
+			} else {
+				named[i].ActorName = roles[*actor.ActorID]
+			}
+		}
+	}
+	return named
+}
+
+// RemapActors replaces the source IDs of bypass actors with the IDs of the same (or mapped)
+// actors in the target. Actors that cannot be found are dropped and reported as warnings,
+// since a ruleset with a wrong bypass actor would grant bypass to someone else.
+func RemapActors(actors []BypassActor, mapping ActorMap, target TargetActors) ([]BypassActor, []string) {
+	var remapped []BypassActor
+	var warnings []string
+	for _, actor := range actors {
+		switch actor.ActorType {
+		case ActorOrganizationAdmin, ActorDeployKey:
+			remapped = append(remapped, actor)
+			continue
+		case ActorRepositoryRole:
+			if actor.ActorID != nil {
+				if _, ok := builtinRoles[*actor.ActorID]; ok {
+					remapped = append(remapped, actor)
+					continue
+				}
+			}
+		}
+
+		var name string
+		var ids map[string]int64
+		switch actor.ActorType {
+		case ActorTeam:
+			name, ids = mapName(mapping.Teams, actor.ActorName), target.Teams
+		case ActorIntegration:
+			name, ids = mapName(mapping.Apps, actor.ActorName), target.Apps
+		case ActorRepositoryRole:
+			name, ids = mapName(mapping.Roles, actor.ActorName), target.Roles
+		default:
+			warnings = append(warnings, fmt.Sprintf("bypass actor type %s is not supported; dropped", actor.ActorType))
+			continue
+		}
+
+		id, ok := ids[strings.ToLower(name)]
+		if name == "" || !ok {
+			warnings = append(warnings, fmt.Sprintf("%s bypass actor '%s' not found in the target; dropped", actor.ActorType, describe(actor, name)))
+			continue
+		}
+		actor.ActorID = &id
+		actor.ActorName = name
+		remapped = append(remapped, actor)
+	}
+	return remapped, warnings
+}
+
+// PortableConditions removes conditions that reference repositories by ID, which do not exist in
+// the target. It reports false when nothing is left to select repositories with.
+func PortableConditions(conditions map[string]json.RawMessage) (map[string]json.RawMessage, []string, bool) {
+	portable := make(map[string]json.RawMessage, len(conditions))
+	var warnings []string
+	for name, condition := range conditions {
+		if name == "repository_id" {
+			warnings = append(warnings, "repository_id condition references source repository IDs; dropped")
+			continue
+		}
+		portable[name] = condition
+	}
+	_, byName := portable["repository_name"]
+	_, byProperty := portable["repository_property"]
+	return portable, warnings, len(conditions) == 0 || byName || byProperty
+}
+
+// OrganizationSpecificRules lists rules whose parameters reference IDs of the source
+// organization (e.g. workflow repositories or status check apps) and need manual review
+func OrganizationSpecificRules(rules []json.RawMessage) []string {
+	var flagged []string
+	for _, raw := range rules {
+		var rule struct {
+			Type string `json:"type"`
+		}
+		if json.Unmarshal(raw, &rule) != nil {
+			continue
+		}
+		text := string(raw)
+		if strings.Contains(text, `"repository_id"`) || strings.Contains(text, `"integration_id"`) {
+			flagged = append(flagged, rule.Type)
+		}
+	}
+	sort.Strings(flagged)
+	return flagged
+}
+
+// mapName applies the actor map to a name
+func mapName(mapping map[string]string, name string) string {
+	if mapped, ok := mapping[name]; ok {
+		return mapped
+	}
+	return name
+}
+
+// describe names an actor for warnings, falling back to its source ID
+func describe(actor BypassActor, name string) string {
+	if name != "" {
+		return name
+	}
+	if actor.ActorID != nil {
+		return fmt.Sprintf("#%d", *actor.ActorID)
+	}
+	return "unknown"
+}
@@ -0,0 +1,91 @@
+package rulesets
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func id(v int64) *int64 { return &v }
+
+func TestRemapActors(t *testing.T) {
+	target := TargetActors{
+		Teams: map[string]int64{"platform": 100, "release-eng": 101},
+		Apps:  map[string]int64{"deploy-bot": 200},
+		Roles: map[string]int64{"security-manager": 300},
+	}
+
+	tests := []struct {
+		name         string
+		actors       []BypassActor
+		mapping      ActorMap
+		want         []BypassActor
+		wantWarnings int
+	}{
+		{
+			name:   "team found by slug",
+			actors: []BypassActor{{ActorID: id(1), ActorType: ActorTeam, BypassMode: "always", ActorName: "platform"}},
+			want:   []BypassActor{{ActorID: id(100), ActorType: ActorTeam, BypassMode: "always", ActorName: "platform"}},
+		},
+		{
+			name:    "team renamed by the actor map",
+			actors:  []BypassActor{{ActorID: id(1), ActorType: ActorTeam, BypassMode: "pull_request", ActorName: "releases"}},
+			mapping: ActorMap{Teams: map[string]string{"releases": "release-eng"}},
+			want:    []BypassActor{{ActorID: id(101), ActorType: ActorTeam, BypassMode: "pull_request", ActorName: "release-eng"}},
+		},
+		{
+			name:   "built-in role and org admin are kept",
+			actors: []BypassActor{{ActorID: id(5), ActorType: ActorRepositoryRole, BypassMode: "always"}, {ActorID: id(1), ActorType: ActorOrganizationAdmin, BypassMode: "always"}},
+			want:   []BypassActor{{ActorID: id(5), ActorType: ActorRepositoryRole, BypassMode: "always"}, {ActorID: id(1), ActorType: ActorOrganizationAdmin, BypassMode: "always"}},
+		},
+		{
+			name:   "custom role and app remapped",
+			actors: []BypassActor{{ActorID: id(42), ActorType: ActorRepositoryRole, BypassMode: "always", ActorName: "security-manager"}, {ActorID: id(7), ActorType: ActorIntegration, BypassMode: "always", ActorName: "deploy-bot"}},
+			want:   []BypassActor{{ActorID: id(300), ActorType: ActorRepositoryRole, BypassMode: "always", ActorName: "security-manager"}, {ActorID: id(200), ActorType: ActorIntegration, BypassMode: "always", ActorName: "deploy-bot"}},
+		},
+		{
+			name:         "missing actors are dropped",
+			actors:       []BypassActor{{ActorID: id(1), ActorType: ActorTeam, BypassMode: "always", ActorName: "ghosts"}, {ActorID: id(9), ActorType: ActorIntegration, BypassMode: "always"}},
+			wantWarnings: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, warnings := RemapActors(tt.actors, tt.mapping, target)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RemapActors() = %+v, want %+v", got, tt.want)
+			}
+			if len(warnings) != tt.wantWarnings {
+				t.Errorf("RemapActors() warnings = %v, want %d", warnings, tt.wantWarnings)
+			}
+		})
+	}
+}
+
+func TestPortableConditions(t *testing.T) {
+	refName := json.RawMessage(`{"include":["~DEFAULT_BRANCH"],"exclude":[]}`)
+	repoName := json.RawMessage(`{"include":["~ALL"],"exclude":[]}`)
+	repoID := json.RawMessage(`{"repository_ids":[1,2]}`)
+
+	tests := []struct {
+		name         string
+		conditions   map[string]json.RawMessage
+		wantKeys     int
+		wantWarnings int
+		wantOK       bool
+	}{
+		{"by name", map[string]json.RawMessage{"ref_name": refName, "repository_name": repoName}, 2, 0, true},
+		{"by id only", map[string]json.RawMessage{"ref_name": refName, "repository_id": repoID}, 1, 1, false},
+		{"no conditions", map[string]json.RawMessage{}, 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, warnings, ok := PortableConditions(tt.conditions)
+			if len(got) != tt.wantKeys || len(warnings) != tt.wantWarnings || ok != tt.wantOK {
+				t.Errorf("PortableConditions() = %d keys, %v, %v; want %d keys, %d warnings, %v", len(got), warnings, ok, tt.wantKeys, tt.wantWarnings, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+// Package secretscan summarizes the secret scanning results of a repository so the security
+// team knows what is in the code before it is frozen in an archive organization
+package secretscan
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+
+	"github.com/jefeish/gh-repo-transfer/internal/ghclient"
+)
+
+// customPatternPrefix starts the secret type of alerts raised by custom patterns
+const customPatternPrefix = "custom_pattern"
+
+// Alert is the part of a secret scanning alert the summary needs
+type Alert struct {
+	State                 string `json:"state"`
+	SecretType            string `json:"secret_type"`
+	SecretTypeDisplayName string `json:"secret_type_display_name"`
+	Resolution            string `json:"resolution"`
+}
+
+// Summary is the secret scanning state of a repository at the time it was archived
+type Summary struct {
+	Enabled             bool           `json:"enabled"`
+	PushProtection      bool           `json:"push_protection"`
+	OpenAlerts          int            `json:"open_alerts"`
+	ResolvedAlerts      int            `json:"resolved_alerts"`
+	CustomPatternAlerts int            `json:"custom_pattern_alerts"` // Open alerts raised by custom patterns
+	OpenByType          map[string]int `json:"open_by_type,omitempty"`
+	ScannedAt           time.Time      `json:"scanned_at"`
+}
+
+// Summarize reads the secret scanning settings and alerts of owner/repo. GitHub scans
+// continuously while secret scanning is enabled, so the existing alerts are the scan result;
+// when it is disabled the summary only records that the contents were not scanned.
+func Summarize(client api.RESTClient, owner, repo string) (*Summary, error) {
+	var repository struct {
+		SecurityAndAnalysis struct {
+			SecretScanning struct {
+				Status string `json:"status"`
+			} `json:"secret_scanning"`
+			PushProtection struct {
+				Status string `json:"status"`
+			} `json:"secret_scanning_push_protection"`
+		} `json:"security_and_analysis"`
+	}
+	if err := client.Get(fmt.Sprintf("repos/%s/%s", owner, repo), &repository); err != nil {
+		return nil, fmt.Errorf("failed to get repository %s/%s: %v", owner, repo, err)
+	}
+
+	summary := &Summary{
+		Enabled:        repository.SecurityAndAnalysis.SecretScanning.Status == "enabled",
+		PushProtection: repository.SecurityAndAnalysis.PushProtection.Status == "enabled",
+		ScannedAt:      time.Now().UTC(),
+	}
+	if !summary.Enabled {
+		return summary, nil
+	}
+
+	var alerts []Alert
+	if err := ghclient.GetAll(&client, fmt.Sprintf("repos/%s/%s/secret-scanning/alerts", owner, repo), &alerts); err != nil {
+		return nil, fmt.Errorf("failed to list secret scanning alerts of %s/%s: %v", owner, repo, err)
+	}
+	summary.Tally(alerts)
+	return summary, nil
+}
+
+// Tally counts alerts by state and the open ones by secret type
+func (s *Summary) Tally(alerts []Alert) {
+	for _, alert := range alerts {
+		if alert.State != "open" {
+			s.ResolvedAlerts++
+			continue
+		}
+		s.OpenAlerts++
+		if strings.HasPrefix(alert.SecretType, customPatternPrefix) {
+			s.CustomPatternAlerts++
+		}
+		name := alert.SecretTypeDisplayName
+		if name == "" {
+			name = alert.SecretType
+		}
+		if s.OpenByType == nil {
+			s.OpenByType = make(map[string]int)
+		}
+		s.OpenByType[name]++
+	}
+}
+
+// String returns a one-line summary for reports
+func (s *Summary) String() string {
+	if !s.Enabled {
+		return "secret scanning is disabled, contents were not scanned"
+	}
+	line := fmt.Sprintf("%d open alert(s)", s.OpenAlerts)
+	if s.CustomPatternAlerts > 0 {
+		line += fmt.Sprintf(" (%d custom pattern)", s.CustomPatternAlerts)
+	}
+	line += fmt.Sprintf(", %d resolved", s.ResolvedAlerts)
+
+	types := make([]string, 0, len(s.OpenByType))
+	for name := range s.OpenByType {
+		types = append(types, name)
+	}
+	sort.Strings(types)
+	for i, name := range types {
+		if i == 0 {
+			line += ": "
+		} else {
+			line += ", "
+		}
+		line += fmt.Sprintf("%s ×%d", name, s.OpenByType[name])
+	}
+	return line
+}
@@ -0,0 +1,43 @@
+package secretscan
+
+import "testing"
+
+func TestSummaryString(t *testing.T) {
+	tests := []struct {
+		name    string
+		enabled bool
+		alerts  []Alert
+		want    string
+	}{
+		{
+			name: "disabled",
+			want: "secret scanning is disabled, contents were not scanned",
+		},
+		{
+			name:    "no alerts",
+			enabled: true,
+			want:    "0 open alert(s), 0 resolved",
+		},
+		{
+			name:    "open alerts grouped by type",
+			enabled: true,
+			alerts: []Alert{
+				{State: "open", SecretType: "github_personal_access_token", SecretTypeDisplayName: "GitHub Personal Access Token"},
+				{State: "open", SecretType: "custom_pattern_internal_key", SecretTypeDisplayName: "Internal signing key"},
+				{State: "open", SecretType: "github_personal_access_token", SecretTypeDisplayName: "GitHub Personal Access Token"},
+				{State: "resolved", SecretType: "aws_access_key_id", Resolution: "revoked"},
+			},
+			want: "3 open alert(s) (1 custom pattern), 1 resolved: GitHub Personal Access Token ×2, Internal signing key ×1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary := &Summary{Enabled: tt.enabled}
+			summary.Tally(tt.alerts)
+			if got := summary.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+// Package sinks copies the reports and state files of a run to durable storage (see
+// --output-target): a local directory, an S3 bucket or a Google Cloud Storage bucket. Every run
+// writes below its own prefix so repeated CI runs do not overwrite each other.
+package sinks
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Sink stores files of a run under names relative to its run prefix
+type Sink interface {
+	// Put stores data under name, a slash-separated path relative to the run prefix
+	Put(name string, data []byte) error
+	// Location describes where the files of the run end up, e.g. s3://bucket/reports/123-1/
+	Location() string
+}
+
+// runCommand runs an upload command with data on stdin; replaced in tests
+var runCommand = func(name string, args []string, data []byte) error {
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = bytes.NewReader(data)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s %s failed: %v: %s", name, strings.Join(args, " "), err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// Open returns the sink of an --output-target such as file:///var/reports, s3://bucket/prefix/ or
+// gs://bucket/prefix/ (gcs:// works too); files are written below run inside the prefix
+func Open(target, run string) (Sink, error) {
+	scheme, rest, ok := strings.Cut(target, "://")
+	if !ok {
+		return nil, fmt.Errorf("invalid output target '%s' (use file://DIR, s3://BUCKET/PREFIX or gs://BUCKET/PREFIX)", target)
+	}
+	switch strings.ToLower(scheme) {
+	case "file":
+		if rest == "" {
+			return nil, fmt.Errorf("output target '%s' has no directory", target)
+		}
+		return &fileSink{dir: filepath.Join(filepath.FromSlash(rest), run)}, nil
+	case "s3":
+		bucket, prefix, err := splitBucket(target, rest)
+		if err != nil {
+			return nil, err
+		}
+		return &commandSink{url: "s3://" + bucket + "/" + joinPrefix(prefix, run), command: "aws", args: []string{"s3", "cp", "-"}}, nil
+	case "gs", "gcs":
+		bucket, prefix, err := splitBucket(target, rest)
+		if err != nil {
+			return nil, err
+		}
+		return &commandSink{url: "gs://" + bucket + "/" + joinPrefix(prefix, run), command: "gcloud", args: []string{"storage", "cp", "-"}}, nil
+	}
+	return nil, fmt.Errorf("unsupported output target scheme '%s' (use file, s3 or gs)", scheme)
+}
+
+// RunPrefix names the directory of one run: the GitHub Actions run ID and attempt when running
+// in a workflow, e.g. 4711-2, otherwise the UTC start time, e.g. 20261018T093000Z
+func RunPrefix(now time.Time) string {
+	if id := os.Getenv("GITHUB_RUN_ID"); id != "" {
+		if attempt := os.Getenv("GITHUB_RUN_ATTEMPT"); attempt != "" {
+			return id + "-" + attempt
+		}
+		return id
+	}
+	return now.UTC().Format("20060102T150405Z")
+}
+
+// PutFile stores the file at filePath under name; a missing file is skipped
+func PutFile(sink Sink, name, filePath string) (int, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	if err := sink.Put(name, data); err != nil {
+		return 0, err
+	}
+	return 1, nil
+}
+
+// PutDir stores the files below dir under prefix, keeping their relative paths; a missing
+// directory is skipped. It returns the number of files stored.
+func PutDir(sink Sink, prefix, dir string) (int, error) {
+	stored := 0
+	err := filepath.WalkDir(dir, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && filePath == dir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		relative, err := filepath.Rel(dir, filePath)
+		if err != nil {
+			return err
+		}
+		count, err := PutFile(sink, path.Join(prefix, filepath.ToSlash(relative)), filePath)
+		stored += count
+		return err
+	})
+	return stored, err
+}
+
+// splitBucket splits BUCKET/PREFIX of a bucket target
+func splitBucket(target, rest string) (string, string, error) {
+	bucket, prefix, _ := strings.Cut(rest, "/")
+	if bucket == "" {
+		return "", "", fmt.Errorf("output target '%s' has no bucket", target)
+	}
+	return bucket, prefix, nil
+}
+
+// joinPrefix appends the run to a bucket prefix, ending in a slash
+func joinPrefix(prefix, run string) string {
+	prefix = strings.Trim(prefix, "/")
+	if prefix == "" {
+		return run + "/"
+	}
+	return prefix + "/" + run + "/"
+}
+
+// fileSink writes to a local directory, e.g. a mounted volume or an artifact directory
+type fileSink struct {
+	dir string
+}
+
+func (s *fileSink) Put(name string, data []byte) error {
+	target := filepath.Join(s.dir, filepath.FromSlash(path.Clean("/"+name)))
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(target, data, 0o644)
+}
+
+func (s *fileSink) Location() string {
+	return s.dir + string(filepath.Separator)
+}
+
+// commandSink uploads through a cloud CLI reading the object from stdin, so credentials come
+// from the CLI's usual configuration (e.g. an OIDC role of the CI run)
+type commandSink struct {
+	url     string
+	command string
+	args    []string
+}
+
+func (s *commandSink) Put(name string, data []byte) error {
+	args := append(append([]string{}, s.args...), s.url+strings.TrimPrefix(path.Clean("/"+name), "/"))
+	return runCommand(s.command, args, data)
+}
+
+func (s *commandSink) Location() string {
+	return s.url
+}
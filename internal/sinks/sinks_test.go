@@ -0,0 +1,134 @@
+package sinks
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOpen(t *testing.T) {
+	tests := []struct {
+		target       string
+		wantLocation string
+		wantErr      bool
+	}{
+		{"s3://reports/migrations/", "s3://reports/migrations/42-1/", false},
+		{"s3://reports", "s3://reports/42-1/", false},
+		{"gs://reports/wave-3", "gs://reports/wave-3/42-1/", false},
+		{"gcs://reports/wave-3/", "gs://reports/wave-3/42-1/", false},
+		{"file:///var/reports", filepath.Join("/var/reports", "42-1") + string(filepath.Separator), false},
+		{"s3:///prefix", "", true},
+		{"file://", "", true},
+		{"azure://container", "", true},
+		{"/var/reports", "", true},
+	}
+	for _, tt := range tests {
+		sink, err := Open(tt.target, "42-1")
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Open(%q) error = %v, wantErr %v", tt.target, err, tt.wantErr)
+			continue
+		}
+		if err == nil && sink.Location() != tt.wantLocation {
+			t.Errorf("Open(%q).Location() = %q, want %q", tt.target, sink.Location(), tt.wantLocation)
+		}
+	}
+}
+
+func TestRunPrefix(t *testing.T) {
+	now := time.Date(2026, 10, 18, 9, 30, 0, 0, time.FixedZone("CEST", 2*60*60))
+	tests := []struct {
+		runID   string
+		attempt string
+		want    string
+	}{
+		{"4711", "2", "4711-2"},
+		{"4711", "", "4711"},
+		{"", "", "20261018T073000Z"},
+	}
+	for _, tt := range tests {
+		t.Setenv("GITHUB_RUN_ID", tt.runID)
+		t.Setenv("GITHUB_RUN_ATTEMPT", tt.attempt)
+		if got := RunPrefix(now); got != tt.want {
+			t.Errorf("RunPrefix() with run %q attempt %q = %q, want %q", tt.runID, tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestCommandSinkPut(t *testing.T) {
+	var calls [][]string
+	original := runCommand
+	runCommand = func(name string, args []string, data []byte) error {
+		calls = append(calls, append([]string{name, string(data)}, args...))
+		return nil
+	}
+	defer func() { runCommand = original }()
+
+	tests := []struct {
+		target string
+		name   string
+		want   []string
+	}{
+		{"s3://reports/ci", "report.json", []string{"aws", "{}", "s3", "cp", "-", "s3://reports/ci/7-1/report.json"}},
+		{"gs://reports", "journal/../../state.json", []string{"gcloud", "{}", "storage", "cp", "-", "gs://reports/7-1/state.json"}},
+	}
+	for _, tt := range tests {
+		calls = nil
+		sink, err := Open(tt.target, "7-1")
+		if err != nil {
+			t.Fatalf("Open(%q) error = %v", tt.target, err)
+		}
+		if err := sink.Put(tt.name, []byte("{}")); err != nil {
+			t.Fatalf("Put(%q) error = %v", tt.name, err)
+		}
+		if len(calls) != 1 || !reflect.DeepEqual(calls[0], tt.want) {
+			t.Errorf("Put(%q) ran %v, want %v", tt.name, calls, tt.want)
+		}
+	}
+}
+
+func TestPutDir(t *testing.T) {
+	source := t.TempDir()
+	for name, content := range map[string]string{
+		"index.json":           "{}",
+		"acme/web.json":        `{"repo":"web"}`,
+		"acme/nested/api.json": `{"repo":"api"}`,
+	} {
+		target := filepath.Join(source, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(target, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	destination := t.TempDir()
+	sink, err := Open("file://"+filepath.ToSlash(destination), "run")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		dir       string
+		wantCount int
+		wantFile  string
+	}{
+		{source, 3, "files/acme/nested/api.json"},
+		{filepath.Join(source, "missing"), 0, ""},
+	}
+	for _, tt := range tests {
+		count, err := PutDir(sink, "files", tt.dir)
+		if err != nil || count != tt.wantCount {
+			t.Errorf("PutDir(%q) = %d, %v, want %d", tt.dir, count, err, tt.wantCount)
+		}
+		if tt.wantFile == "" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(destination, "run", filepath.FromSlash(tt.wantFile)))
+		if err != nil || !strings.Contains(string(data), "api") {
+			t.Errorf("%s = %q, %v", tt.wantFile, data, err)
+		}
+	}
+}
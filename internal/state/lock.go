@@ -0,0 +1,122 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"time"
+)
+
+// LockInfo identifies the run holding the lock of a state file
+type LockInfo struct {
+	Holder string    `json:"holder"` // user@host
+	PID    int       `json:"pid"`
+	Since  time.Time `json:"since"`
+}
+
+// LockedError reports a state file locked by another run
+type LockedError struct {
+	Path string
+	Info LockInfo
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("state file %s is locked by %s (pid %d) since %s; wait for that run to finish, or delete %s if it is no longer running",
+		e.Path, e.Info.Holder, e.Info.PID, e.Info.Since.Local().Format(time.RFC3339), LockPath(e.Path))
+}
+
+// LockPath is the lock file next to a state file. It is created exclusively, which works on
+// shared volumes where advisory flock() calls are not reliable.
+func LockPath(path string) string {
+	return path + ".lock"
+}
+
+// LoadLocked locks the state file at path for this run and loads it. Another run holding the
+// lock yields a *LockedError. Unlock releases the lock; an empty path yields nil.
+func LoadLocked(path string) (*State, error) {
+	if path == "" {
+		return nil, nil
+	}
+	if err := acquireLock(path, currentLockInfo(time.Now())); err != nil {
+		return nil, err
+	}
+	s, err := Load(path)
+	if err != nil {
+		os.Remove(LockPath(path))
+		return nil, err
+	}
+	s.locked = true
+	return s, nil
+}
+
+// Unlock releases the lock taken by LoadLocked
+func (s *State) Unlock() error {
+	if s == nil {
+		return nil
+	}
+	s.mutex.Lock()
+	locked := s.locked
+	s.locked = false
+	s.mutex.Unlock()
+	if !locked {
+		return nil
+	}
+	if err := os.Remove(LockPath(s.path)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to release the lock of state file %s: %v", s.path, err)
+	}
+	return nil
+}
+
+// Locked reports whether this run still holds the lock taken by LoadLocked
+func (s *State) Locked() bool {
+	if s == nil {
+		return false
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.locked
+}
+
+// acquireLock creates the lock file of path holding info, or reports who holds it
+func acquireLock(path string, info LockInfo) error {
+	file, err := os.OpenFile(LockPath(path), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if errors.Is(err, os.ErrExist) {
+		var holder LockInfo
+		data, readErr := os.ReadFile(LockPath(path))
+		if readErr != nil || json.Unmarshal(data, &holder) != nil {
+			return fmt.Errorf("state file %s is locked by another run; delete %s if it is no longer running", path, LockPath(path))
+		}
+		return &LockedError{Path: path, Info: holder}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock state file %s: %v", path, err)
+	}
+
+	data, err := json.Marshal(info)
+	if err == nil {
+		_, err = file.Write(data)
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(LockPath(path))
+		return fmt.Errorf("failed to lock state file %s: %v", path, err)
+	}
+	return nil
+}
+
+// currentLockInfo identifies this run as user@host and process ID
+func currentLockInfo(now time.Time) LockInfo {
+	name := os.Getenv("USER")
+	if current, err := user.Current(); err == nil {
+		name = current.Username
+	}
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown host"
+	}
+	return LockInfo{Holder: fmt.Sprintf("%s@%s", name, host), PID: os.Getpid(), Since: now.UTC()}
+}
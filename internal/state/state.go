@@ -0,0 +1,325 @@
+package state
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jefeish/gh-repo-transfer/internal/encryption"
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+// currentVersion is bumped whenever the layout of the state file changes incompatibly
+const currentVersion = 1
+
+// State is the persisted run state shared between invocations (see --state-file)
+type State struct {
+	Version         int                                     `json:"version"`
+	Revision        int                                     `json:"revision"`                   // Incremented by every save, see Save
+	Validations     map[string]CachedValidation             `json:"validations,omitempty"`      // Keyed by "owner/repo->target-org"
+	Plans           map[string]PlannedValidation            `json:"plans,omitempty"`            // Reviewed baselines, keyed like Validations
+	Capabilities    map[string]*types.TargetOrgCapabilities `json:"capabilities,omitempty"`     // Last scan per target org
+	PendingArchives map[string]PendingArchive               `json:"pending_archives,omitempty"` // Keyed by archived "owner/repo"
+
+	path           string
+	loadedRevision int  // Revision of the file when it was loaded or last saved
+	locked         bool // Locked by LoadLocked
+	mutex          sync.Mutex
+}
+
+// CachedValidation is a validation result together with the inputs it was computed from
+type CachedValidation struct {
+	DependenciesHash string                     `json:"dependencies_hash"`
+	CapabilitiesHash string                     `json:"capabilities_hash"`
+	AssignTeams      bool                       `json:"assign_teams"`
+	ValidatedAt      time.Time                  `json:"validated_at"`
+	Validation       *types.MigrationValidation `json:"validation"`
+}
+
+// PlannedValidation is the validation a deps or plan run reviewed for a repository and target
+// org. Transfers and archives diff their validation against it to detect drift, but never
+// update it, so a drift that blocked a run blocks it again until the plan is made again.
+type PlannedValidation struct {
+	PlannedAt  time.Time                  `json:"planned_at"`
+	Validation *types.MigrationValidation `json:"validation"`
+}
+
+// PendingArchive is a repository transferred to the archive org whose archived (read-only) flag
+// is set by 'finalize' once its soak period has passed (see --archive-after)
+type PendingArchive struct {
+	Repository    string    `json:"repository"`    // Archived "owner/repo" in the target org
+	OriginalPath  string    `json:"original_path"` // Source "owner/repo"
+	TransferredAt time.Time `json:"transferred_at"`
+	ArchiveAfter  time.Time `json:"archive_after"`
+}
+
+// Due reports whether the soak period of the pending archive has passed at now
+func (p PendingArchive) Due(now time.Time) bool {
+	return !now.Before(p.ArchiveAfter)
+}
+
+// Load reads the state file at path. A missing file yields an empty state; an empty path
+// yields nil, which disables all state-backed features.
+func Load(path string) (*State, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	s := &State{
+		Version:         currentVersion,
+		Validations:     make(map[string]CachedValidation),
+		Plans:           make(map[string]PlannedValidation),
+		Capabilities:    make(map[string]*types.TargetOrgCapabilities),
+		PendingArchives: make(map[string]PendingArchive),
+		path:            path,
+	}
+
+	data, err := encryption.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file %s: %v", path, err)
+	}
+
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("failed to parse state file %s: %v", path, err)
+	}
+	if s.Version != currentVersion {
+		return nil, fmt.Errorf("state file %s has unsupported version %d (expected %d)", path, s.Version, currentVersion)
+	}
+	s.loadedRevision = s.Revision
+	if s.Validations == nil {
+		s.Validations = make(map[string]CachedValidation)
+	}
+	if s.Plans == nil {
+		// State files written before plans were kept apart used the cached validations as the
+		// baseline
+		s.Plans = make(map[string]PlannedValidation, len(s.Validations))
+		for key, cached := range s.Validations {
+			s.Plans[key] = PlannedValidation{PlannedAt: cached.ValidatedAt, Validation: cached.Validation}
+		}
+	}
+	if s.Capabilities == nil {
+		s.Capabilities = make(map[string]*types.TargetOrgCapabilities)
+	}
+	if s.PendingArchives == nil {
+		s.PendingArchives = make(map[string]PendingArchive)
+	}
+
+	return s, nil
+}
+
+// Save writes the state back to disk. The file is replaced atomically so an interrupted
+// run never leaves a truncated state file behind. Writes are versioned: when another run saved
+// the file since it was loaded, Save fails instead of overwriting that run's results.
+func (s *State) Save() error {
+	if s == nil {
+		return nil
+	}
+	current, err := fileRevision(s.path)
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	if current != s.loadedRevision {
+		s.mutex.Unlock()
+		return fmt.Errorf("state file %s was saved by another run since it was loaded (revision %d, now %d); not overwriting it, re-run to pick up its results", s.path, s.loadedRevision, current)
+	}
+	s.Revision = current + 1
+	data, err := json.MarshalIndent(s, "", "  ")
+	s.mutex.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to marshal state: %v", err)
+	}
+	if data, err = encryption.Encrypt(data); err != nil {
+		return fmt.Errorf("failed to encrypt state: %v", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary state file: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write state file: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write state file: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to replace state file %s: %v", s.path, err)
+	}
+	s.mutex.Lock()
+	s.loadedRevision = s.Revision
+	s.mutex.Unlock()
+	return nil
+}
+
+// fileRevision reads the revision of the state file on disk, 0 when it does not exist
+func fileRevision(path string) (int, error) {
+	data, err := encryption.ReadFile(path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read state file %s: %v", path, err)
+	}
+	var stored struct {
+		Revision int `json:"revision"`
+	}
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return 0, fmt.Errorf("failed to parse state file %s: %v", path, err)
+	}
+	return stored.Revision, nil
+}
+
+// CachedValidationFor returns the cached validation for a repository and target org, if any
+func (s *State) CachedValidationFor(repository, targetOrg string) (CachedValidation, bool) {
+	if s == nil {
+		return CachedValidation{}, false
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	cached, ok := s.Validations[validationKey(repository, targetOrg)]
+	return cached, ok
+}
+
+// StoreValidation records a validation result for a repository and target org
+func (s *State) StoreValidation(repository, targetOrg string, cached CachedValidation) {
+	if s == nil {
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.Validations[validationKey(repository, targetOrg)] = cached
+}
+
+// PlanFor returns the reviewed plan baseline for a repository and target org, if any
+func (s *State) PlanFor(repository, targetOrg string) (PlannedValidation, bool) {
+	if s == nil {
+		return PlannedValidation{}, false
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	planned, ok := s.Plans[validationKey(repository, targetOrg)]
+	return planned, ok
+}
+
+// StorePlan records the reviewed plan baseline for a repository and target org
+func (s *State) StorePlan(repository, targetOrg string, planned PlannedValidation) {
+	if s == nil {
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.Plans[validationKey(repository, targetOrg)] = planned
+}
+
+// CachedCapabilities returns the last recorded capability scan of a target org, if any
+func (s *State) CachedCapabilities(targetOrg string) (*types.TargetOrgCapabilities, bool) {
+	if s == nil {
+		return nil, false
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	capabilities, ok := s.Capabilities[strings.ToLower(targetOrg)]
+	return capabilities, ok
+}
+
+// StoreCapabilities records a capability scan so later runs can reuse it while it is fresh
+func (s *State) StoreCapabilities(capabilities *types.TargetOrgCapabilities) {
+	if s == nil || capabilities == nil {
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.Capabilities[strings.ToLower(capabilities.Organization)] = capabilities
+}
+
+// StorePendingArchive records a repository whose archived flag is set later by 'finalize'
+func (s *State) StorePendingArchive(pending PendingArchive) {
+	if s == nil {
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.PendingArchives[strings.ToLower(pending.Repository)] = pending
+}
+
+// RemovePendingArchive forgets a pending archive once it has been finalized
+func (s *State) RemovePendingArchive(repository string) {
+	if s == nil {
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.PendingArchives, strings.ToLower(repository))
+}
+
+// PendingArchiveList returns the pending archives ordered by when they become due
+func (s *State) PendingArchiveList() []PendingArchive {
+	if s == nil {
+		return nil
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	pending := make([]PendingArchive, 0, len(s.PendingArchives))
+	for _, archive := range s.PendingArchives {
+		pending = append(pending, archive)
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		if !pending[i].ArchiveAfter.Equal(pending[j].ArchiveAfter) {
+			return pending[i].ArchiveAfter.Before(pending[j].ArchiveAfter)
+		}
+		return pending[i].Repository < pending[j].Repository
+	})
+	return pending
+}
+
+// Hash returns a stable SHA-256 hex digest of the JSON encoding of v
+func Hash(v interface{}) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash state input: %v", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func validationKey(repository, targetOrg string) string {
+	return repository + "->" + targetOrg
+}
+
+// Repositories returns every repository with a plan baseline, i.e. the repositories planned
+// for migration, sorted and without duplicates
+func (s *State) Repositories() []string {
+	if s == nil {
+		return nil
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	seen := make(map[string]bool)
+	var repositories []string
+	for key := range s.Plans {
+		repository := strings.SplitN(key, "->", 2)[0]
+		if !seen[repository] {
+			seen[repository] = true
+			repositories = append(repositories, repository)
+		}
+	}
+	sort.Strings(repositories)
+	return repositories
+}
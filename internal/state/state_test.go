@@ -0,0 +1,89 @@
+package state
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadLocked(t *testing.T) {
+	since := time.Date(2026, 10, 18, 9, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		lockFile   string // Content of an existing lock file, "" for none
+		wantHolder string
+		wantErr    string
+	}{
+		{"unlocked", "", "", ""},
+		{"locked by another run", `{"holder":"alice@build-01","pid":4711,"since":"2026-10-18T09:30:00Z"}`, "alice@build-01", "locked by alice@build-01 (pid 4711)"},
+		{"unreadable lock", "garbage", "", "locked by another run"},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), "state.json")
+		if tt.lockFile != "" {
+			if err := os.WriteFile(LockPath(path), []byte(tt.lockFile), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		s, err := LoadLocked(path)
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("%s: LoadLocked() error = %v", tt.name, err)
+				continue
+			}
+			if _, err := LoadLocked(path); err == nil {
+				t.Errorf("%s: second LoadLocked() did not fail", tt.name)
+			}
+			if err := s.Unlock(); err != nil {
+				t.Errorf("%s: Unlock() error = %v", tt.name, err)
+			}
+			if _, err := os.Stat(LockPath(path)); !os.IsNotExist(err) {
+				t.Errorf("%s: lock file left behind after Unlock()", tt.name)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: LoadLocked() error = %v, want %q", tt.name, err, tt.wantErr)
+		}
+		var locked *LockedError
+		if errors.As(err, &locked) != (tt.wantHolder != "") || (locked != nil && (locked.Info.Holder != tt.wantHolder || !locked.Info.Since.Equal(since))) {
+			t.Errorf("%s: LoadLocked() error = %#v, want holder %q", tt.name, err, tt.wantHolder)
+		}
+	}
+}
+
+func TestSaveDetectsConcurrentWrites(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	first, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		state        *State
+		wantErr      bool
+		wantRevision int
+	}{
+		{"first save", first, false, 1},
+		{"save of a run that loaded the old revision", second, true, 1},
+		{"second save of the same run", first, false, 2},
+	}
+	for _, tt := range tests {
+		err := tt.state.Save()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: Save() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+		if revision, err := fileRevision(path); err != nil || revision != tt.wantRevision {
+			t.Errorf("%s: revision on disk = %d, %v, want %d", tt.name, revision, err, tt.wantRevision)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+// Package steps runs multi-step operations such as transfer and archive as declarative
+// sequences, so every operation gets the same dry-run listing, resumability and rollback.
+package steps
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Status is the outcome of a single step
+type Status string
+
+const (
+	StatusDone       Status = "done"
+	StatusSkipped    Status = "skipped"     // Disabled, or completed by an earlier run
+	StatusWarning    Status = "warning"     // An optional step failed; the operation continued
+	StatusFailed     Status = "failed"      // A critical step failed; the operation stopped
+	StatusRolledBack Status = "rolled_back" // Completed, then undone after a later critical failure
+	StatusNotRun     Status = "not_run"     // Not reached because an earlier step failed
+)
+
+// Step is one unit of work in an operation
+type Step struct {
+	Name        string
+	Description string
+	// Skip disables the step, e.g. when the flag enabling it is not set
+	Skip bool
+	// Critical steps stop the operation and roll back completed steps when they fail;
+	// failures of other steps are reported as warnings and the operation continues
+	Critical bool
+	// Restores marks steps that restore data an earlier step captured from the source and kept
+	// only in memory; a resumed operation fails them instead of running them without the data
+	Restores bool
+	Execute  func() error
+	// Verify, when set, checks the result of Execute; a failed verification counts as a failed step
+	Verify func() error
+	// Rollback, when set, undoes the step after a later critical step failed
+	Rollback func() error
+}
+
+// Result records what happened to a step
+type Result struct {
+	Step   string `json:"step"`
+	Status Status `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Options control how an operation runs
+type Options struct {
+	// Completed names steps finished by an earlier run; they are skipped so an operation can resume.
+	// A non-nil map marks the operation as resumed.
+	Completed map[string]bool
+	// OnWarning is called when an optional step fails
+	OnWarning func(step string, err error)
+	// OnComplete is called after each step that completed, e.g. to persist progress
+	OnComplete func(step string)
+}
+
+// Run executes the steps in order. When a critical step fails, the steps completed so far are
+// rolled back in reverse order and the error is returned; rollback failures are appended to it.
+func Run(steps []Step, options Options) ([]Result, error) {
+	results := make([]Result, len(steps))
+	for i, step := range steps {
+		results[i] = Result{Step: step.Name, Status: StatusNotRun}
+	}
+
+	for i, step := range steps {
+		if step.Skip || options.Completed[step.Name] {
+			results[i].Status = StatusSkipped
+			continue
+		}
+
+		var err error
+		if step.Restores && options.Completed != nil {
+			err = fmt.Errorf("the source data captured before the transfer was not kept, so %s has nothing to restore", step.Name)
+		} else {
+			err = runStep(step)
+		}
+		if err == nil {
+			results[i].Status = StatusDone
+			if options.OnComplete != nil {
+				options.OnComplete(step.Name)
+			}
+			continue
+		}
+
+		results[i].Error = err.Error()
+		if !step.Critical {
+			results[i].Status = StatusWarning
+			if options.OnWarning != nil {
+				options.OnWarning(step.Name, err)
+			}
+			continue
+		}
+
+		results[i].Status = StatusFailed
+		failure := fmt.Errorf("%s: %v", step.Name, err)
+		if rollbackErrs := rollback(steps[:i], results[:i]); len(rollbackErrs) > 0 {
+			failure = fmt.Errorf("%v (rollback failed: %s)", failure, strings.Join(rollbackErrs, "; "))
+		}
+		return results, failure
+	}
+
+	return results, nil
+}
+
+// runStep executes a step and its verification
+func runStep(step Step) error {
+	if step.Execute != nil {
+		if err := step.Execute(); err != nil {
+			return err
+		}
+	}
+	if step.Verify != nil {
+		if err := step.Verify(); err != nil {
+			return fmt.Errorf("verification failed: %v", err)
+		}
+	}
+	return nil
+}
+
+// rollback undoes completed steps in reverse order and returns the failures
+func rollback(steps []Step, results []Result) []string {
+	var failures []string
+	for i := len(steps) - 1; i >= 0; i-- {
+		if results[i].Status != StatusDone || steps[i].Rollback == nil {
+			continue
+		}
+		if err := steps[i].Rollback(); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", steps[i].Name, err))
+			continue
+		}
+		results[i].Status = StatusRolledBack
+	}
+	return failures
+}
+
+// Undo rolls back the steps an earlier run completed, in reverse order, e.g. to revert an
+// operation that was left half done. Completed steps without a rollback keep StatusDone; a
+// failed rollback marks its step failed and the remaining steps are still rolled back.
+func Undo(steps []Step, completed map[string]bool) ([]Result, error) {
+	results := make([]Result, len(steps))
+	for i, step := range steps {
+		results[i] = Result{Step: step.Name, Status: StatusNotRun}
+	}
+
+	var failures []string
+	for i := len(steps) - 1; i >= 0; i-- {
+		if !completed[steps[i].Name] {
+			continue
+		}
+		results[i].Status = StatusDone
+		if steps[i].Rollback == nil {
+			continue
+		}
+		if err := steps[i].Rollback(); err != nil {
+			results[i].Status = StatusFailed
+			results[i].Error = err.Error()
+			failures = append(failures, fmt.Sprintf("%s: %v", steps[i].Name, err))
+			continue
+		}
+		results[i].Status = StatusRolledBack
+	}
+	if len(failures) > 0 {
+		return results, fmt.Errorf("rollback failed: %s", strings.Join(failures, "; "))
+	}
+	return results, nil
+}
+
+// Plan lists the steps that would run, for dry-run output
+func Plan(steps []Step) []Step {
+	var planned []Step
+	for _, step := range steps {
+		if !step.Skip {
+			planned = append(planned, step)
+		}
+	}
+	return planned
+}
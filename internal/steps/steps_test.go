@@ -0,0 +1,189 @@
+package steps
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	fail := func() error { return errors.New("boom") }
+
+	tests := []struct {
+		name       string
+		steps      func(log *[]string) []Step
+		completed  map[string]bool
+		wantErr    bool
+		wantLog    []string
+		wantStatus []Status
+	}{
+		{
+			name: "all steps succeed",
+			steps: func(log *[]string) []Step {
+				return []Step{
+					{Name: "a", Execute: record(log, "a")},
+					{Name: "b", Execute: record(log, "b"), Verify: record(log, "verify b")},
+				}
+			},
+			wantLog:    []string{"a", "b", "verify b"},
+			wantStatus: []Status{StatusDone, StatusDone},
+		},
+		{
+			name: "optional failure continues",
+			steps: func(log *[]string) []Step {
+				return []Step{
+					{Name: "a", Execute: fail},
+					{Name: "b", Execute: record(log, "b")},
+				}
+			},
+			wantLog:    []string{"b"},
+			wantStatus: []Status{StatusWarning, StatusDone},
+		},
+		{
+			name: "critical failure rolls back in reverse order",
+			steps: func(log *[]string) []Step {
+				return []Step{
+					{Name: "a", Execute: record(log, "a"), Rollback: record(log, "undo a")},
+					{Name: "b", Skip: true, Execute: record(log, "b"), Rollback: record(log, "undo b")},
+					{Name: "c", Execute: record(log, "c"), Rollback: record(log, "undo c")},
+					{Name: "d", Critical: true, Execute: fail},
+					{Name: "e", Execute: record(log, "e")},
+				}
+			},
+			wantErr:    true,
+			wantLog:    []string{"a", "c", "undo c", "undo a"},
+			wantStatus: []Status{StatusRolledBack, StatusSkipped, StatusRolledBack, StatusFailed, StatusNotRun},
+		},
+		{
+			name: "failed verification of a critical step",
+			steps: func(log *[]string) []Step {
+				return []Step{
+					{Name: "a", Critical: true, Execute: record(log, "a"), Verify: fail},
+				}
+			},
+			wantErr:    true,
+			wantLog:    []string{"a"},
+			wantStatus: []Status{StatusFailed},
+		},
+		{
+			name: "completed steps are skipped on resume",
+			steps: func(log *[]string) []Step {
+				return []Step{
+					{Name: "a", Execute: record(log, "a")},
+					{Name: "b", Execute: record(log, "b")},
+				}
+			},
+			completed:  map[string]bool{"a": true},
+			wantLog:    []string{"b"},
+			wantStatus: []Status{StatusSkipped, StatusDone},
+		},
+		{
+			name: "restoring steps fail on resume",
+			steps: func(log *[]string) []Step {
+				return []Step{
+					{Name: "a", Restores: true, Execute: record(log, "a")},
+					{Name: "b", Restores: true, Skip: true, Execute: record(log, "b")},
+					{Name: "c", Restores: true, Critical: true, Execute: record(log, "c")},
+				}
+			},
+			completed:  map[string]bool{},
+			wantErr:    true,
+			wantStatus: []Status{StatusWarning, StatusSkipped, StatusFailed},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var log []string
+			results, err := Run(tt.steps(&log), Options{Completed: tt.completed})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(log, tt.wantLog) {
+				t.Errorf("executed %v, want %v", log, tt.wantLog)
+			}
+			var statuses []Status
+			for _, result := range results {
+				statuses = append(statuses, result.Status)
+			}
+			if !reflect.DeepEqual(statuses, tt.wantStatus) {
+				t.Errorf("statuses %v, want %v", statuses, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestUndo(t *testing.T) {
+	fail := func() error { return errors.New("boom") }
+
+	tests := []struct {
+		name       string
+		steps      func(log *[]string) []Step
+		completed  map[string]bool
+		wantErr    bool
+		wantLog    []string
+		wantStatus []Status
+	}{
+		{
+			name: "completed steps are undone in reverse order",
+			steps: func(log *[]string) []Step {
+				return []Step{
+					{Name: "a", Rollback: record(log, "undo a")},
+					{Name: "b"},
+					{Name: "c", Skip: true, Rollback: record(log, "undo c")},
+					{Name: "d", Rollback: record(log, "undo d")},
+				}
+			},
+			completed:  map[string]bool{"a": true, "b": true, "c": true},
+			wantLog:    []string{"undo c", "undo a"},
+			wantStatus: []Status{StatusRolledBack, StatusDone, StatusRolledBack, StatusNotRun},
+		},
+		{
+			name: "failed rollback continues with the earlier steps",
+			steps: func(log *[]string) []Step {
+				return []Step{
+					{Name: "a", Rollback: record(log, "undo a")},
+					{Name: "b", Rollback: fail},
+				}
+			},
+			completed:  map[string]bool{"a": true, "b": true},
+			wantErr:    true,
+			wantLog:    []string{"undo a"},
+			wantStatus: []Status{StatusRolledBack, StatusFailed},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var log []string
+			results, err := Undo(tt.steps(&log), tt.completed)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Undo() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(log, tt.wantLog) {
+				t.Errorf("executed %v, want %v", log, tt.wantLog)
+			}
+			var statuses []Status
+			for _, result := range results {
+				statuses = append(statuses, result.Status)
+			}
+			if !reflect.DeepEqual(statuses, tt.wantStatus) {
+				t.Errorf("statuses %v, want %v", statuses, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestPlan(t *testing.T) {
+	planned := Plan([]Step{{Name: "a"}, {Name: "b", Skip: true}, {Name: "c"}})
+	if len(planned) != 2 || planned[0].Name != "a" || planned[1].Name != "c" {
+		t.Errorf("Plan() = %v", planned)
+	}
+}
+
+func record(log *[]string, entry string) func() error {
+	return func() error {
+		*log = append(*log, entry)
+		return nil
+	}
+}
@@ -0,0 +1,90 @@
+package teams
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// MatchMode selects how a source team name is matched against the teams of the target org
+type MatchMode string
+
+const (
+	MatchExact      MatchMode = "exact"      // Names must be identical, including case
+	MatchSlug       MatchMode = "slug"       // The GitHub slug of the source name equals the target slug
+	MatchNormalized MatchMode = "normalized" // Slugs compared ignoring '-' and '_' separators
+)
+
+// mode is the match mode used by Matches (see --team-matcher)
+var mode = MatchSlug
+
+// ParseMatchMode validates a --team-matcher value
+func ParseMatchMode(value string) (MatchMode, error) {
+	switch m := MatchMode(value); m {
+	case MatchExact, MatchSlug, MatchNormalized:
+		return m, nil
+	}
+	return "", fmt.Errorf("invalid team matcher '%s' (use exact, slug or normalized)", value)
+}
+
+// SetMatchMode sets the match mode used by Matches
+func SetMatchMode(m MatchMode) {
+	mode = m
+}
+
+// Mode returns the match mode in use
+func Mode() MatchMode {
+	return mode
+}
+
+// Slug returns the slug GitHub derives from a team name: accents are transliterated, the name
+// is lowercased, every run of characters other than letters, digits, '-' and '_' becomes a
+// single '-', and leading/trailing '-' are removed. "Platform.Core / EU" becomes
+// "platform-core-eu" and "Équipe Données" becomes "equipe-donnees".
+func Slug(name string) string {
+	var slug strings.Builder
+	separator := false
+	for _, r := range norm.NFKD.String(name) {
+		if unicode.Is(unicode.Mn, r) {
+			continue // Combining accent split off by the decomposition
+		}
+		r = unicode.ToLower(r)
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_' || r == '-' {
+			if r != '-' {
+				if separator && slug.Len() > 0 {
+					slug.WriteByte('-')
+				}
+				separator = false
+				slug.WriteRune(r)
+				continue
+			}
+		}
+		separator = true
+	}
+	return slug.String()
+}
+
+// normalize reduces a name to the letters and digits of its slug
+func normalize(name string) string {
+	return strings.NewReplacer("-", "", "_", "").Replace(Slug(name))
+}
+
+// Matches reports whether the source team name refers to the target team with the given name
+// and slug. An empty target slug is derived from the target name.
+func Matches(sourceName, targetName, targetSlug string) bool {
+	if targetSlug == "" {
+		targetSlug = Slug(targetName)
+	}
+
+	switch mode {
+	case MatchExact:
+		return sourceName == targetName
+	case MatchNormalized:
+		source := normalize(sourceName)
+		return source != "" && (source == normalize(targetName) || source == normalize(targetSlug))
+	default:
+		return Slug(sourceName) == targetSlug
+	}
+}
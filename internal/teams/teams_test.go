@@ -0,0 +1,53 @@
+package teams
+
+import "testing"
+
+func TestSlug(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Platform Team", "platform-team"},
+		{"Platform.Core / EU", "platform-core-eu"},
+		{"  leading and trailing  ", "leading-and-trailing"},
+		{"release--managers", "release-managers"},
+		{"data_science", "data_science"},
+		{"Équipe Données", "equipe-donnees"},
+		{"C++ Devs", "c-devs"},
+		{"frontend/web", "frontend-web"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Slug(tt.name); got != tt.want {
+				t.Errorf("Slug(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatches(t *testing.T) {
+	tests := []struct {
+		mode       MatchMode
+		source     string
+		targetName string
+		targetSlug string
+		want       bool
+	}{
+		{MatchExact, "Platform Team", "Platform Team", "platform-team", true},
+		{MatchExact, "platform team", "Platform Team", "platform-team", false},
+		{MatchSlug, "Platform.Team", "Platform Team", "platform-team", true},
+		{MatchSlug, "platform_team", "Platform Team", "platform-team", false},
+		{MatchSlug, "Platform Team", "Platform Team", "", true},
+		{MatchNormalized, "platform_team", "Platform Team", "platform-team", true},
+		{MatchNormalized, "platform", "Platform Team", "platform-team", false},
+	}
+
+	defer SetMatchMode(MatchSlug)
+	for _, tt := range tests {
+		SetMatchMode(tt.mode)
+		if got := Matches(tt.source, tt.targetName, tt.targetSlug); got != tt.want {
+			t.Errorf("%s: Matches(%q, %q, %q) = %v, want %v", tt.mode, tt.source, tt.targetName, tt.targetSlug, got, tt.want)
+		}
+	}
+}
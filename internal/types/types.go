@@ -1,5 +1,7 @@
 package types
 
+import "time"
+
 // ValidationStatus represents the migration readiness status
 type ValidationStatus string
 
@@ -10,19 +12,39 @@ const (
 	ValidationWarning     ValidationStatus = "warning"      // Issue but not blocking
 	ValidationReview      ValidationStatus = "review"       // Requires manual review
 	ValidationUnknown     ValidationStatus = "unknown"      // Could not determine
+	ValidationSuppressed  ValidationStatus = "suppressed"   // Accepted through the ignore file
 )
 
 // ValidationResult represents the validation of a single dependency
 type ValidationResult struct {
+	ID             string           `json:"id,omitempty"`   // Finding ID, e.g. "ci-3f9a1c2e"
+	Kind           string           `json:"kind,omitempty"` // Finding kind, e.g. "ci.create_secret"; see the explain command
 	Item           string           `json:"item"`
 	Status         ValidationStatus `json:"status"`
 	Message        string           `json:"message,omitempty"`
 	Recommendation string           `json:"recommendation,omitempty"`
+	Suppression    *Suppression     `json:"suppression,omitempty"` // Set when Status is suppressed
+	Override       *Override        `json:"override,omitempty"`    // Set when a severity override changed Status
+}
+
+// Override records the severity override that changed the status of a finding
+type Override struct {
+	Status ValidationStatus `json:"status"` // Status the finding had before
+	Rule   string           `json:"rule"`   // Finding ID, kind, category and/or item pattern the override matched
+	Reason string           `json:"reason,omitempty"`
+}
+
+// Suppression records the ignore file entry that suppressed a finding
+type Suppression struct {
+	Status  ValidationStatus `json:"status"`            // Status the finding had before
+	Expires string           `json:"expires,omitempty"` // Last day the suppression applies (YYYY-MM-DD)
+	Reason  string           `json:"reason,omitempty"`
 }
 
 // MigrationValidation contains validation results for all dependency categories
 type MigrationValidation struct {
 	TargetOrganization string                       `json:"target_organization"`
+	CapabilitiesScannedAt time.Time                 `json:"capabilities_scanned_at"`
 	OverallReadiness   ValidationStatus             `json:"overall_readiness"`
 	Summary            ValidationSummary            `json:"summary"`
 	CodeDependencies   []ValidationResult           `json:"code_dependencies,omitempty"`
@@ -31,6 +53,13 @@ type MigrationValidation struct {
 	SecurityCompliance []ValidationResult           `json:"security_compliance,omitempty"`
 	AppsIntegrations   []ValidationResult           `json:"apps_integrations,omitempty"`
 	Governance         []ValidationResult           `json:"governance,omitempty"`
+	Effort             *EffortEstimate              `json:"estimated_effort,omitempty"`
+}
+
+// EffortEstimate is the estimated remediation effort for the items that are not ready
+type EffortEstimate struct {
+	TotalMinutes int            `json:"total_minutes"`
+	ByType       map[string]int `json:"by_type,omitempty"` // Minutes per effort item type
 }
 
 // ValidationSummary provides counts by validation status
@@ -41,6 +70,7 @@ type ValidationSummary struct {
 	Warnings    int `json:"warnings"`
 	Review      int `json:"review"`
 	Unknown     int `json:"unknown"`
+	Suppressed  int `json:"suppressed,omitempty"` // Not part of Total
 	Total       int `json:"total"`
 }
 
@@ -49,12 +79,28 @@ type TargetOrgCapabilities struct {
 	Organization        string              `json:"organization"`
 	Apps                []string            `json:"apps"`
 	Teams               []string            `json:"teams"`
+	TeamSlugs           map[string]string   `json:"team_slugs,omitempty"` // Slug per team name
 	RepositoryPolicies  []OrgPolicy         `json:"repository_policies"`   // Actual repo-level policies
 	MemberPrivileges    OrgMemberPrivileges `json:"member_privileges"`     // Org-wide member settings
 	Rulesets            []string            `json:"rulesets"`
 	Secrets             []string            `json:"secrets"`
 	Variables           []string            `json:"variables"`
 	Runners             []string            `json:"runners"`
+	SecretVisibility    map[string]string   `json:"secret_visibility,omitempty"` // Visibility (all, private, selected) per secret name
+	VariableValues      map[string]string   `json:"variable_values,omitempty"`   // Value per variable name
+	WebhookDestinations []string            `json:"webhook_destinations,omitempty"` // Destinations of the org webhooks
+	IdPGroups           []string            `json:"idp_groups,omitempty"`           // IdP groups teams can be connected to
+	IdPGroupSource      string              `json:"idp_group_source,omitempty"`     // How teams are connected to IdP groups: team_sync or external_groups
+	Enterprise          string              `json:"enterprise,omitempty"`           // Enterprise of the target org (--target-enterprise)
+	EnterprisePolicies  []OrgPolicy         `json:"enterprise_policies,omitempty"`  // Policies the enterprise enforces on the target org
+	SignedCommitRulesets []string           `json:"signed_commit_rulesets,omitempty"` // Active org rulesets requiring signed commits
+	PushRulesets        []PushRuleset       `json:"push_rulesets,omitempty"`        // Active org push rulesets
+	CommunityHealthFiles []string           `json:"community_health_files,omitempty"` // Default community health files and templates of the org's .github repository
+	RequiredWorkflowRulesets []string       `json:"required_workflow_rulesets,omitempty"` // Active org rulesets requiring workflows
+	RunnerGroups        []string            `json:"runner_groups,omitempty"`          // Actions runner groups
+	NetworkConfigurations []string          `json:"network_configurations,omitempty"` // Hosted compute network configurations (private networking)
+	NetworkRunnerGroups map[string]string   `json:"network_runner_groups,omitempty"`  // Network configuration per runner group bound to one
+	ScannedAt           time.Time           `json:"scanned_at"`
 }
 
 // OrganizationalDependencies represents all categories of dependencies
@@ -68,6 +114,67 @@ type OrganizationalDependencies struct {
 	AppsIntegrations         AppsIntegrations         `json:"github_apps_integrations_dependencies" yaml:"github_apps_integrations_dependencies"`
 	OrgGovernance           OrgGovernance            `json:"organizational_governance_dependencies" yaml:"organizational_governance_dependencies"`
 	Validation              *MigrationValidation     `json:"migration_validation,omitempty" yaml:"migration_validation,omitempty"`
+	ReferenceComparison     *ReferenceComparison     `json:"reference_comparison,omitempty" yaml:"reference_comparison,omitempty"`
+	Cluster                 string                   `json:"cluster,omitempty" yaml:"cluster,omitempty"` // Dependency similarity cluster (--cluster)
+	BillingImpact           *BillingImpact           `json:"billing_impact,omitempty" yaml:"billing_impact,omitempty"` // Informational (--billing-impact)
+	Depth                   *AnalysisDepth           `json:"analysis_depth,omitempty" yaml:"analysis_depth,omitempty"` // Fast pass decision (--deep auto/never)
+	Path                    string                   `json:"path,omitempty" yaml:"path,omitempty"` // Subdirectory the file contents were analyzed in (--path)
+	WhatIf                  *WhatIf                  `json:"what_if,omitempty" yaml:"what_if,omitempty"` // Consequences of the planned path (--target-name)
+}
+
+// AnalysisDepth records the fast pass of --deep auto or never: the preliminary score of the
+// repository and whether file contents, code search and ruleset details were analyzed
+type AnalysisDepth struct {
+	Mode      string   `json:"mode" yaml:"mode"`
+	Score     int      `json:"score" yaml:"score"`
+	Threshold int      `json:"threshold" yaml:"threshold"`
+	Deep      bool     `json:"deep" yaml:"deep"`
+	Signals   []string `json:"signals,omitempty" yaml:"signals,omitempty"` // What the score is made of, e.g. "2 workflow(s) (+6)"
+}
+
+// BillingImpact estimates the seats and Advanced Security committers a transfer adds to the
+// target organization. It is informational and does not affect readiness.
+type BillingImpact struct {
+	AdditionalSeats          int      `json:"additional_seats" yaml:"additional_seats"`
+	NewSeatUsers             []string `json:"new_seat_users,omitempty" yaml:"new_seat_users,omitempty"`
+	AdditionalGHASCommitters int      `json:"additional_ghas_committers" yaml:"additional_ghas_committers"`
+	NewCommitters            []string `json:"new_ghas_committers,omitempty" yaml:"new_ghas_committers,omitempty"`
+	Notes                    []string `json:"notes,omitempty" yaml:"notes,omitempty"` // Parts that could not be read
+}
+
+// WhatIf lists what changes with the planned path of a repository in the target organization
+// (--target-name), before anything exists there. It is informational and does not affect readiness.
+type WhatIf struct {
+	TargetRepository string            `json:"target_repository" yaml:"target_repository"`
+	NameTaken        bool              `json:"name_taken,omitempty" yaml:"name_taken,omitempty"` // The target already has a repository with the name
+	Consequences     []NameConsequence `json:"consequences,omitempty" yaml:"consequences,omitempty"`
+	Notes            []string          `json:"notes,omitempty" yaml:"notes,omitempty"` // Parts that could not be read
+}
+
+// NameConsequence is a name derived from the repository's path that changes with the move
+type NameConsequence struct {
+	Kind    string `json:"kind" yaml:"kind"` // go_module, package_scope or container_image
+	Path    string `json:"path" yaml:"path"` // File the name is declared in
+	Current string `json:"current" yaml:"current"`
+	Planned string `json:"planned" yaml:"planned"`
+	Action  string `json:"action" yaml:"action"`
+}
+
+// RepositoryCluster is a group of repositories with similar dependency fingerprints that can
+// be migrated as one wave
+type RepositoryCluster struct {
+	Name               string   `json:"name" yaml:"name"`
+	Repositories       []string `json:"repositories" yaml:"repositories"`
+	SharedDependencies []string `json:"shared_dependencies,omitempty" yaml:"shared_dependencies,omitempty"` // "type:item" keys every member has
+}
+
+// ReferenceComparison lists the dependencies of a repository that its reference repository
+// (an already-migrated repository of the same kind) does not have
+type ReferenceComparison struct {
+	Reference  string              `json:"reference" yaml:"reference"`
+	NovelTypes []string            `json:"novel_types,omitempty" yaml:"novel_types,omitempty"` // Dependency types the reference has none of
+	NovelItems map[string][]string `json:"novel_items,omitempty" yaml:"novel_items,omitempty"` // New items per dependency type
+	Shared     int                 `json:"shared" yaml:"shared"`                               // Dependencies the reference has as well
 }
 
 // CodeDependencies represents organization-specific code dependencies
@@ -77,6 +184,7 @@ type CodeDependencies struct {
 	OrgPackageRegistries              []string `json:"organization_package_registries"`
 	HardcodedOrgReferences           []string `json:"hardcoded_organization_references"`
 	OrgSpecificContainerRegistries    []string `json:"organization_specific_container_registries"`
+	DocumentationURLReferences        []string `json:"documentation_url_references"`
 }
 
 // ActionsCIDependencies represents GitHub Actions and CI/CD dependencies
@@ -88,6 +196,17 @@ type ActionsCIDependencies struct {
 	OrgSpecificActions               []string `json:"organization_specific_actions"`
 	RequiredWorkflows                []string `json:"required_workflows"`
 	CrossRepoWorkflowTriggers        []string `json:"cross_repo_workflow_triggers"`
+	RepositorySecrets                []string `json:"repository_secrets,omitempty"`   // Secrets defined on the repository itself
+	RepositoryVariables              []string `json:"repository_variables,omitempty"` // Variables defined on the repository itself
+	OrganizationVariableValues       map[string]string `json:"organization_variable_values,omitempty"` // Source org values of referenced variables
+	PinnedActionConsumers            []string `json:"pinned_action_consumers,omitempty"` // Workflows in the org using this repository's actions pinned to a commit SHA
+	DispatchConsumers                []string `json:"dispatch_consumers,omitempty"`      // Workflows in the org sending repository_dispatch events to this repository
+	EnvironmentReviewers             []string `json:"environment_reviewers,omitempty"`  // Required deployment reviewers, "team:<slug> (environment: <name>)"
+	EnvironmentSecrets               []string `json:"environment_secrets,omitempty"`    // "NAME (environment: <name>)"
+	EnvironmentVariables             []string `json:"environment_variables,omitempty"`  // "NAME (environment: <name>)"
+	NetworkBoundRunnerGroups         []string `json:"network_bound_runner_groups,omitempty"` // "Runner group: <name> (network configuration: <name>)"
+	StaticIPRunners                  []string `json:"static_ip_runners,omitempty"`           // "Hosted runner: <name> (static IPs: <ips>)"
+	ArtifactAttestations             []string `json:"artifact_attestations,omitempty"`       // "Artifact attestation: actions/attest-build-provenance (in <workflow>, job <job>)"
 }
 
 // AccessPermissions represents access control and permissions
@@ -97,6 +216,8 @@ type AccessPermissions struct {
 	OrganizationRoles               []string `json:"organization_roles"`
 	OrganizationMembership          []string `json:"organization_membership"`
 	CodeownersRequirements          []string `json:"codeowners_requirements"`
+	CodeownersFile                  string   `json:"codeowners_file,omitempty"` // Location of the CODEOWNERS file the requirements come from
+	DeployKeys                      []string `json:"deploy_keys,omitempty"` // Deploy keys, which have to be added again after a transfer
 }
 
 // SecurityCompliance represents security and compliance dependencies
@@ -108,6 +229,8 @@ type SecurityCompliance struct {
 type AppsIntegrations struct {
 	InstalledGitHubApps             []string `json:"installed_github_apps"`
 	PersonalAccessTokens            []string `json:"personal_access_tokens"`
+	Webhooks                        []string `json:"webhooks,omitempty"` // Repository webhooks, which are not carried over by a transfer
+	Pages                           []string `json:"pages,omitempty"`    // GitHub Pages site, whose URL changes with the owner unless it has a custom domain
 }
 
 // OrgAppsIntegrations represents organization-level apps and integrations
@@ -142,6 +265,31 @@ type OrgGovernance struct {
 	IssueTemplates                  []string    `json:"issue_templates"`
 	PullRequestTemplates            []string    `json:"pull_request_templates"`
 	RequiredStatusChecks            []string    `json:"required_status_checks"`
+	EventSinks                      []string    `json:"event_sinks,omitempty"` // Org webhooks and audit log streams receiving repository events
+	EnterprisePolicies              []OrgPolicy `json:"enterprise_policies,omitempty"` // Policies of the source enterprise (--enterprise)
+	CommitSigning                   *CommitSigningPolicy `json:"commit_signing,omitempty"`
+	PushRulesets                    []PushRuleset        `json:"push_rulesets,omitempty"` // Active push rulesets applying to the repository
+	CommunityHealthFiles            []string             `json:"community_health_files,omitempty"` // Community health files and templates, the repository's own and the organization defaults it inherits
+}
+
+// PushRuleset restricts the files that can be pushed: file size, path length, paths and
+// extensions. Source is organization or repository; only repository rulesets move with it.
+type PushRuleset struct {
+	Name                 string   `json:"name"`
+	Source               string   `json:"source"`
+	MaxFileSizeMB        int      `json:"max_file_size_mb,omitempty"`
+	MaxFilePathLength    int      `json:"max_file_path_length,omitempty"`
+	RestrictedPaths      []string `json:"restricted_file_paths,omitempty"`
+	RestrictedExtensions []string `json:"restricted_file_extensions,omitempty"`
+	SelectedRepositories bool     `json:"selected_repositories,omitempty"` // Target rulesets applying to some repositories only
+}
+
+// CommitSigningPolicy records what requires signed commits on the repository's default branch
+// and whether commits made on the web must be signed off
+type CommitSigningPolicy struct {
+	SignaturesRequiredBy []string `json:"signatures_required_by,omitempty"` // e.g. "branch protection", "organization ruleset 42"
+	OrganizationSignoff  bool     `json:"organization_signoff_required"`
+	RepositorySignoff    bool     `json:"repository_signoff_required"`
 }
 
 // Legacy types for governance inspection (to be refactored)
@@ -0,0 +1,24 @@
+package validation
+
+import (
+	"fmt"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+// validateArtifactAttestations reviews the artifact attestations the workflows create. An
+// attestation is signed for the repository and the workflow that built the artifact, and
+// consumers verify it against them, so verification pinned to the source name fails for
+// artifacts built after the move while older ones keep naming the source repository.
+func validateArtifactAttestations(ci types.ActionsCIDependencies, capabilities *types.TargetOrgCapabilities) []types.ValidationResult {
+	var results []types.ValidationResult
+	for _, attestation := range ci.ArtifactAttestations {
+		results = append(results, types.ValidationResult{
+			Item:           attestation,
+			Status:         types.ValidationReview,
+			Message:        "Artifact attestations are signed for the repository's current name; consumers verifying against it fail for artifacts built after the move",
+			Recommendation: fmt.Sprintf("Tell consumers to verify new artifacts with gh attestation verify --owner %s (or --repo and --signer-workflow under %s) and update admission policies that pin the source repository; artifacts built before the move keep verifying against the old name", capabilities.Organization, capabilities.Organization),
+		})
+	}
+	return results
+}
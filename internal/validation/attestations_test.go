@@ -0,0 +1,27 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+func TestValidateArtifactAttestations(t *testing.T) {
+	ci := types.ActionsCIDependencies{
+		ArtifactAttestations: []string{"Artifact attestation: actions/attest-build-provenance (in release.yml, job build)"},
+	}
+	results := validateArtifactAttestations(ci, &types.TargetOrgCapabilities{Organization: "acme-new"})
+	if len(results) != 1 {
+		t.Fatalf("%d results, want 1", len(results))
+	}
+	if results[0].Status != types.ValidationReview {
+		t.Errorf("status = %s, want %s", results[0].Status, types.ValidationReview)
+	}
+	if kind := FindingKind("ci", results[0]); kind != "ci.attestation_verify" {
+		t.Errorf("kind = %s, want ci.attestation_verify", kind)
+	}
+	if !strings.Contains(results[0].Recommendation, "--owner acme-new") {
+		t.Errorf("recommendation %q does not name the target organization", results[0].Recommendation)
+	}
+}
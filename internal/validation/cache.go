@@ -0,0 +1,114 @@
+package validation
+
+import (
+	"time"
+
+	"github.com/jefeish/gh-repo-transfer/internal/state"
+	"github.com/jefeish/gh-repo-transfer/internal/teams"
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+// ValidateAgainstTargetCached reuses the validation stored in the state file when neither the
+// repository's dependencies nor the target capabilities changed since it was computed.
+// Passing revalidate forces a fresh validation. The second return value reports a cache hit.
+// The cache is no plan: drift is detected against the baseline RecordPlan stores.
+func ValidateAgainstTargetCached(deps *types.OrganizationalDependencies, capabilities *types.TargetOrgCapabilities, assignTeams bool, st *state.State, revalidate bool) (*types.MigrationValidation, bool) {
+	if st == nil {
+		return ValidateAgainstTarget(deps, capabilities, assignTeams), false
+	}
+
+	depsHash, depsErr := dependenciesHash(deps)
+	capsHash, capsErr := capabilitiesHash(capabilities)
+	if depsErr != nil || capsErr != nil {
+		return ValidateAgainstTarget(deps, capabilities, assignTeams), false
+	}
+
+	cached, ok := st.CachedValidationFor(deps.Repository, capabilities.Organization)
+	if !revalidate {
+		if ok && cached.Validation != nil && cached.DependenciesHash == depsHash &&
+			cached.CapabilitiesHash == capsHash && cached.AssignTeams == assignTeams {
+			validation := cloneValidation(cached.Validation)
+			validation.CapabilitiesScannedAt = capabilities.ScannedAt
+			// The ignore file, severity overrides and effort weights may differ between runs, so
+			// the finding IDs, statuses, summary and estimate are always recomputed
+			finishValidation(validation, deps.Repository)
+			return validation, true
+		}
+	}
+
+	validation := ValidateAgainstTarget(deps, capabilities, assignTeams)
+	st.StoreValidation(deps.Repository, capabilities.Organization, state.CachedValidation{
+		DependenciesHash: depsHash,
+		CapabilitiesHash: capsHash,
+		AssignTeams:      assignTeams,
+		ValidatedAt:      time.Now().UTC(),
+		Validation:       cloneValidation(validation),
+	})
+	return validation, false
+}
+
+// RecordPlan stores a validation as the reviewed plan baseline of a repository and target org.
+// Only runs that make the plan (deps, plan) record it; runs that check drift against it must
+// not, or a blocked run would clear its own drift.
+func RecordPlan(st *state.State, repository, targetOrg string, validation *types.MigrationValidation) {
+	st.StorePlan(repository, targetOrg, state.PlannedValidation{
+		PlannedAt:  time.Now().UTC(),
+		Validation: cloneValidation(validation),
+	})
+}
+
+// PlannedValidation returns the reviewed plan baseline of a repository and target org, nil
+// when none was recorded
+func PlannedValidation(st *state.State, repository, targetOrg string) *types.MigrationValidation {
+	planned, ok := st.PlanFor(repository, targetOrg)
+	if !ok {
+		return nil
+	}
+	return planned.Validation
+}
+
+// dependenciesHash hashes everything validation reads from a dependency report, including
+// the organization policies that are excluded from the JSON report and the validation mode
+func dependenciesHash(deps *types.OrganizationalDependencies) (string, error) {
+	report := *deps
+	report.Validation = nil
+	report.ReferenceComparison = nil
+	report.Cluster = ""
+	// The collision check, team matcher and excluded checks change the outcome, so they are
+	// part of the inputs
+	return state.Hash(struct {
+		Report               types.OrganizationalDependencies `json:"report"`
+		OrganizationPolicies []types.OrgPolicy                `json:"organization_policies"`
+		CollisionAwareness   bool                             `json:"collision_awareness,omitempty"`
+		TeamMatcher          teams.MatchMode                  `json:"team_matcher"`
+		ExcludedChecks       map[string]bool                  `json:"excluded_checks,omitempty"`
+	}{report, deps.OrgGovernance.OrganizationPolicies, collisionAwareness, teams.Mode(), excludedChecks})
+}
+
+// capabilitiesHash hashes the target capabilities, ignoring when they were scanned
+func capabilitiesHash(capabilities *types.TargetOrgCapabilities) (string, error) {
+	scan := *capabilities
+	scan.ScannedAt = time.Time{}
+	return state.Hash(scan)
+}
+
+// cloneValidation deep-copies a validation so later report rewrites (e.g. --anonymize)
+// never leak into the state file
+func cloneValidation(validation *types.MigrationValidation) *types.MigrationValidation {
+	clone := *validation
+	for _, results := range []*[]types.ValidationResult{
+		&clone.CodeDependencies, &clone.CIDependencies, &clone.AccessPermissions,
+		&clone.SecurityCompliance, &clone.AppsIntegrations, &clone.Governance,
+	} {
+		*results = append([]types.ValidationResult(nil), *results...)
+	}
+	if validation.Effort != nil {
+		effort := *validation.Effort
+		effort.ByType = make(map[string]int, len(validation.Effort.ByType))
+		for itemType, minutes := range validation.Effort.ByType {
+			effort.ByType[itemType] = minutes
+		}
+		clone.Effort = &effort
+	}
+	return &clone
+}
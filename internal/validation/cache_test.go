@@ -0,0 +1,55 @@
+package validation
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/jefeish/gh-repo-transfer/internal/state"
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+func TestDriftAgainstPlanKeepsBlocking(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	deps := &types.OrganizationalDependencies{
+		Repository:        "acme/web",
+		AccessPermissions: types.AccessPermissions{Teams: []string{"Platform (push)"}},
+	}
+	planned := &types.TargetOrgCapabilities{Organization: "acme-new", Teams: []string{"Platform"}}
+	// The team was deleted in the target after the plan was reviewed
+	current := &types.TargetOrgCapabilities{Organization: "acme-new"}
+
+	// deps makes the plan
+	st, err := state.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	validation, _ := ValidateAgainstTargetCached(deps, planned, false, st, false)
+	RecordPlan(st, deps.Repository, planned.Organization, validation)
+	if err := st.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The same transfer runs twice; the first run saving the state must not clear the drift
+	for run := 1; run <= 2; run++ {
+		st, err := state.Load(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		validation, cached := ValidateAgainstTargetCached(deps, current, false, st, false)
+		if cached != (run == 2) {
+			t.Errorf("run %d: cache hit = %v", run, cached)
+		}
+		regressions := 0
+		for _, drift := range DiffValidations(PlannedValidation(st, deps.Repository, current.Organization), validation) {
+			if drift.IsRegression() {
+				regressions++
+			}
+		}
+		if regressions == 0 {
+			t.Errorf("run %d: no regression against the plan, the transfer would not be blocked", run)
+		}
+		if err := st.Save(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
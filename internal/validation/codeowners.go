@@ -0,0 +1,86 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+// validateCodeowners checks the owners named in CODEOWNERS. CODEOWNERS names teams with their
+// organization, so a reference to a team of the source organization stops resolving after the
+// move even when the target has a team with the same slug.
+func validateCodeowners(requirements []string, capabilities *types.TargetOrgCapabilities, sourceOrg string) []types.ValidationResult {
+	var results []types.ValidationResult
+	for _, requirement := range requirements {
+		switch {
+		case strings.HasPrefix(requirement, "Team: @"):
+			// "Team: @org/team-slug"
+			org, slug, ok := strings.Cut(strings.TrimPrefix(requirement, "Team: @"), "/")
+			if !ok {
+				continue
+			}
+			if !strings.EqualFold(org, sourceOrg) && !strings.EqualFold(org, capabilities.Organization) {
+				results = append(results, types.ValidationResult{
+					Item:           requirement,
+					Status:         types.ValidationWarning,
+					Message:        "CODEOWNERS team belongs to neither the source nor the target organization and is ignored by GitHub",
+					Recommendation: fmt.Sprintf("Replace it with a team of %s", capabilities.Organization),
+				})
+				continue
+			}
+
+			// CODEOWNERS references teams by slug, so the slug is compared as is
+			if !isTeamSlugAvailable(slug, capabilities) {
+				recommendation := fmt.Sprintf("Create team '%s' in target organization or update CODEOWNERS", slug)
+				if strings.EqualFold(org, sourceOrg) {
+					recommendation = fmt.Sprintf("Create team '%s' in target organization and update CODEOWNERS to @%s/%s", slug, capabilities.Organization, slug)
+				}
+				results = append(results, types.ValidationResult{
+					Item:           requirement,
+					Status:         types.ValidationBlocker,
+					Message:        "CODEOWNERS team does not exist in target organization",
+					Recommendation: recommendation,
+				})
+				continue
+			}
+			if strings.EqualFold(org, sourceOrg) && !strings.EqualFold(org, capabilities.Organization) {
+				results = append(results, types.ValidationResult{
+					Item:           requirement,
+					Status:         types.ValidationWarning,
+					Message:        "CODEOWNERS team exists in target organization but is named through the source organization",
+					Recommendation: fmt.Sprintf("Update CODEOWNERS to @%s/%s after the transfer, e.g. with transfer --rewrite-codeowners", capabilities.Organization, slug),
+				})
+				continue
+			}
+			results = append(results, types.ValidationResult{
+				Item:    requirement,
+				Status:  types.ValidationReady,
+				Message: "CODEOWNERS team exists in target organization",
+			})
+		case strings.HasPrefix(requirement, "User: @"):
+			// CODEOWNERS users are warnings instead of requiring manual review
+			results = append(results, types.ValidationResult{
+				Item:           requirement,
+				Status:         types.ValidationWarning,
+				Message:        "CODEOWNERS user requires manual setup in target organization",
+				Recommendation: "Invite user to target organization or update CODEOWNERS",
+			})
+		case strings.HasPrefix(requirement, "Email: "):
+			results = append(results, types.ValidationResult{
+				Item:           requirement,
+				Status:         types.ValidationWarning,
+				Message:        "CODEOWNERS email owner requires manual setup in target organization",
+				Recommendation: "Make sure the address belongs to a user with write access to the moved repository, or name the user or a team instead",
+			})
+		case strings.HasPrefix(requirement, "Unknown owner: "):
+			results = append(results, types.ValidationResult{
+				Item:           requirement,
+				Status:         types.ValidationWarning,
+				Message:        "CODEOWNERS owner does not exist in the source organization and is ignored by GitHub",
+				Recommendation: "Remove the owner from CODEOWNERS or replace it with a team of the target organization",
+			})
+		}
+	}
+	return results
+}
@@ -0,0 +1,42 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+func TestValidateCodeowners(t *testing.T) {
+	capabilities := &types.TargetOrgCapabilities{
+		Organization: "acme-new",
+		Teams:        []string{"Platform"},
+		TeamSlugs:    map[string]string{"Platform": "platform"},
+	}
+
+	tests := []struct {
+		requirement string
+		status      types.ValidationStatus
+		kind        string
+	}{
+		{"Team: @acme/platform", types.ValidationWarning, "access.codeowners_update"},
+		{"Team: @acme-new/platform", types.ValidationReady, "access.create_team"},
+		{"Team: @acme/frontend", types.ValidationBlocker, "access.create_team"},
+		{"Team: @elsewhere/platform", types.ValidationWarning, "access.codeowners_update"},
+		{"User: @octocat", types.ValidationWarning, "access.invite_user"},
+		{"Email: jane@acme.com", types.ValidationWarning, "access.invite_user"},
+		{"Unknown owner: @ghost (user not found)", types.ValidationWarning, "access.codeowners_update"},
+	}
+	for _, tt := range tests {
+		results := validateCodeowners([]string{tt.requirement}, capabilities, "acme")
+		if len(results) != 1 {
+			t.Errorf("%s: %d results, want 1", tt.requirement, len(results))
+			continue
+		}
+		if results[0].Status != tt.status {
+			t.Errorf("%s: status = %s, want %s", tt.requirement, results[0].Status, tt.status)
+		}
+		if kind := FindingKind("access", results[0]); kind != tt.kind {
+			t.Errorf("%s: kind = %s, want %s", tt.requirement, kind, tt.kind)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+// collisionAwareness makes same-named org secrets/variables in the target Review items instead
+// of Ready, since CI would silently pick up the target org's value after the move
+var collisionAwareness bool
+
+// SetCollisionAwareness enables or disables the collision check for secrets and variables
+func SetCollisionAwareness(enabled bool) {
+	collisionAwareness = enabled
+}
+
+// secretCollision classifies an org secret that exists under the same name in the target org.
+// Secret values cannot be read, so unless a repository secret overrides it the match needs review.
+func secretCollision(name string, ci types.ActionsCIDependencies, capabilities *types.TargetOrgCapabilities) (types.ValidationStatus, string, string) {
+	if containsName(ci.RepositorySecrets, name) {
+		return types.ValidationReady, "Repository secret of the same name overrides the target org secret", ""
+	}
+
+	message := fmt.Sprintf("Target organization already has a secret named '%s'; CI will use its value after the move", name)
+	recommendation := fmt.Sprintf("Confirm the target org secret '%s' holds the value this repository expects, or add a repository secret to override it", name)
+	switch capabilities.SecretVisibility[strings.ToUpper(name)] {
+	case "selected":
+		message += " (visible to selected repositories only)"
+		recommendation += "; grant this repository access to the secret"
+	case "private":
+		message += " (visible to private repositories only)"
+	}
+	return types.ValidationReview, message, recommendation
+}
+
+// variableCollision classifies an org variable that exists under the same name in the target
+// org by comparing its value with the one in the source org
+func variableCollision(name string, ci types.ActionsCIDependencies, capabilities *types.TargetOrgCapabilities) (types.ValidationStatus, string, string) {
+	if containsName(ci.RepositoryVariables, name) {
+		return types.ValidationReady, "Repository variable of the same name overrides the target org variable", ""
+	}
+
+	key := strings.ToUpper(name)
+	sourceValue, sourceKnown := ci.OrganizationVariableValues[key]
+	targetValue, targetKnown := capabilities.VariableValues[key]
+	switch {
+	case sourceKnown && targetKnown && sourceValue == targetValue:
+		return types.ValidationReady, "Variable exists in target organization with the same value", ""
+	case sourceKnown && targetKnown:
+		return types.ValidationReview,
+			fmt.Sprintf("Variable '%s' exists in target organization with a different value ('%s' in source, '%s' in target)", name, sourceValue, targetValue),
+			fmt.Sprintf("Align the value of '%s' or add a repository variable to override it", name)
+	default:
+		return types.ValidationReview,
+			fmt.Sprintf("Target organization already has a variable named '%s'; its value could not be compared", name),
+			fmt.Sprintf("Confirm the target org variable '%s' holds the value this repository expects", name)
+	}
+}
+
+func containsName(names []string, name string) bool {
+	for _, candidate := range names {
+		if strings.EqualFold(candidate, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,52 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+func TestVariableCollision(t *testing.T) {
+	capabilities := &types.TargetOrgCapabilities{
+		VariableValues: map[string]string{"REGION": "eu-west-1", "TIER": "prod"},
+	}
+
+	tests := []struct {
+		name   string
+		ci     types.ActionsCIDependencies
+		status types.ValidationStatus
+	}{
+		{"same value", types.ActionsCIDependencies{OrganizationVariableValues: map[string]string{"REGION": "eu-west-1"}}, types.ValidationReady},
+		{"different value", types.ActionsCIDependencies{OrganizationVariableValues: map[string]string{"REGION": "us-east-1"}}, types.ValidationReview},
+		{"unknown source value", types.ActionsCIDependencies{}, types.ValidationReview},
+		{"overridden by repository variable", types.ActionsCIDependencies{RepositoryVariables: []string{"region"}}, types.ValidationReady},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if status, _, _ := variableCollision("REGION", tt.ci, capabilities); status != tt.status {
+				t.Errorf("got %s, want %s", status, tt.status)
+			}
+		})
+	}
+}
+
+func TestSecretCollision(t *testing.T) {
+	capabilities := &types.TargetOrgCapabilities{SecretVisibility: map[string]string{"DEPLOY_TOKEN": "selected"}}
+
+	status, message, _ := secretCollision("DEPLOY_TOKEN", types.ActionsCIDependencies{}, capabilities)
+	if status != types.ValidationReview || message == "" {
+		t.Errorf("same-named secret should need review, got %s", status)
+	}
+
+	status, _, _ = secretCollision("DEPLOY_TOKEN", types.ActionsCIDependencies{RepositorySecrets: []string{"DEPLOY_TOKEN"}}, capabilities)
+	if status != types.ValidationReady {
+		t.Errorf("repository secret should override the org secret, got %s", status)
+	}
+}
+
+func TestExtractSecretName(t *testing.T) {
+	if name := extractSecretName("NPM_TOKEN (in release.yml)"); name != "NPM_TOKEN" {
+		t.Errorf("got %q", name)
+	}
+}
@@ -0,0 +1,87 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jefeish/gh-repo-transfer/internal/dependencies"
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+// validateCommitSigning compares the signed commit and web commit sign-off requirements of the
+// source with the target organization, in both directions: a requirement the move drops
+// weakens the repository, a requirement the target adds rejects commits that passed before.
+func validateCommitSigning(signing *types.CommitSigningPolicy, capabilities *types.TargetOrgCapabilities) []types.ValidationResult {
+	if signing == nil {
+		return nil
+	}
+	var results []types.ValidationResult
+	targetRulesets := strings.Join(capabilities.SignedCommitRulesets, ", ")
+
+	// Branch protection and repository rulesets move with the repository; organization and
+	// enterprise rulesets stay behind
+	var kept, dropped []string
+	for _, source := range signing.SignaturesRequiredBy {
+		if dependencies.MovesWithRepository(source) {
+			kept = append(kept, source)
+		} else {
+			dropped = append(dropped, source)
+		}
+	}
+	switch {
+	case len(kept) > 0:
+		results = append(results, types.ValidationResult{
+			Item:    fmt.Sprintf("Signed commits required (%s)", strings.Join(signing.SignaturesRequiredBy, ", ")),
+			Status:  types.ValidationReady,
+			Message: "Signed commit requirement is defined on the repository and moves with it",
+		})
+	case len(dropped) > 0 && len(capabilities.SignedCommitRulesets) > 0:
+		results = append(results, types.ValidationResult{
+			Item:           fmt.Sprintf("Signed commits required (%s)", strings.Join(dropped, ", ")),
+			Status:         types.ValidationReview,
+			Message:        fmt.Sprintf("Signed commits are required by the source organization; target rulesets requiring them: %s", targetRulesets),
+			Recommendation: "Check that the target ruleset covers the repository and its default branch",
+		})
+	case len(dropped) > 0:
+		results = append(results, types.ValidationResult{
+			Item:           fmt.Sprintf("Signed commits required (%s)", strings.Join(dropped, ", ")),
+			Status:         types.ValidationSetupNeeded,
+			Message:        fmt.Sprintf("Signed commits are required by the source organization, but no ruleset of %s requires them", capabilities.Organization),
+			Recommendation: "Add a ruleset requiring signed commits in the target organization, or a repository ruleset before the move",
+		})
+	case len(capabilities.SignedCommitRulesets) > 0:
+		results = append(results, types.ValidationResult{
+			Item:           fmt.Sprintf("Signed commits required by target (%s)", targetRulesets),
+			Status:         types.ValidationReview,
+			Message:        "Target organization requires signed commits, the source does not",
+			Recommendation: "Make sure contributors and automation sign their commits, or pushes to covered branches are rejected",
+		})
+	}
+
+	sourceSignoff := signing.OrganizationSignoff || signing.RepositorySignoff
+	targetSignoff := capabilities.MemberPrivileges.WebCommitSignoffRequired
+	switch {
+	case signing.OrganizationSignoff && !targetSignoff:
+		results = append(results, types.ValidationResult{
+			Item:           "Web commit sign-off required",
+			Status:         types.ValidationSetupNeeded,
+			Message:        fmt.Sprintf("Web commit sign-off is required by the source organization but not by %s", capabilities.Organization),
+			Recommendation: "Require sign-off on web-based commits in the target organization or in the repository settings",
+		})
+	case sourceSignoff:
+		results = append(results, types.ValidationResult{
+			Item:    "Web commit sign-off required",
+			Status:  types.ValidationReady,
+			Message: "Web commit sign-off stays required after the move",
+		})
+	case targetSignoff:
+		results = append(results, types.ValidationResult{
+			Item:           "Web commit sign-off required by target",
+			Status:         types.ValidationReview,
+			Message:        "Target organization requires web commit sign-off, the source does not",
+			Recommendation: "Let contributors know that commits made on the web must be signed off",
+		})
+	}
+
+	return results
+}
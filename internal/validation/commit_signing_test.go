@@ -0,0 +1,45 @@
+package validation
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+func TestValidateCommitSigning(t *testing.T) {
+	tests := []struct {
+		name          string
+		signing       *types.CommitSigningPolicy
+		targetRuleset []string
+		targetSignoff bool
+		want          []types.ValidationStatus
+	}{
+		{"nothing required", &types.CommitSigningPolicy{}, nil, false, nil},
+		{"branch protection moves", &types.CommitSigningPolicy{SignaturesRequiredBy: []string{"branch protection", "organization ruleset 4"}}, nil, false, []types.ValidationStatus{types.ValidationReady}},
+		{"org ruleset dropped", &types.CommitSigningPolicy{SignaturesRequiredBy: []string{"organization ruleset 4"}}, nil, false, []types.ValidationStatus{types.ValidationSetupNeeded}},
+		{"org ruleset with target ruleset", &types.CommitSigningPolicy{SignaturesRequiredBy: []string{"organization ruleset 4"}}, []string{"signed"}, false, []types.ValidationStatus{types.ValidationReview}},
+		{"target adds signatures", &types.CommitSigningPolicy{}, []string{"signed"}, false, []types.ValidationStatus{types.ValidationReview}},
+		{"signoff dropped", &types.CommitSigningPolicy{OrganizationSignoff: true, RepositorySignoff: true}, nil, false, []types.ValidationStatus{types.ValidationSetupNeeded}},
+		{"signoff on both", &types.CommitSigningPolicy{OrganizationSignoff: true}, nil, true, []types.ValidationStatus{types.ValidationReady}},
+		{"repository signoff moves", &types.CommitSigningPolicy{RepositorySignoff: true}, nil, false, []types.ValidationStatus{types.ValidationReady}},
+		{"target adds signoff", &types.CommitSigningPolicy{}, nil, true, []types.ValidationStatus{types.ValidationReview}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			capabilities := &types.TargetOrgCapabilities{
+				Organization:         "target",
+				SignedCommitRulesets: tt.targetRuleset,
+				MemberPrivileges:     types.OrgMemberPrivileges{WebCommitSignoffRequired: tt.targetSignoff},
+			}
+			var got []types.ValidationStatus
+			for _, result := range validateCommitSigning(tt.signing, capabilities) {
+				got = append(got, result.Status)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("validateCommitSigning() statuses = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
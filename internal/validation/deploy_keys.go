@@ -0,0 +1,26 @@
+package validation
+
+import (
+	"strings"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+// validateDeployKeys reports deploy keys, which have to be added to the moved repository
+// again. A key with write access is called out, since whatever pushes with it stops working.
+func validateDeployKeys(keys []string) []types.ValidationResult {
+	var results []types.ValidationResult
+	for _, key := range keys {
+		recommendation := "Add the key to the moved repository again and point its service at the new repository path"
+		if strings.HasSuffix(key, "(read/write)") {
+			recommendation = "Add the key with write access to the moved repository again, before anything that pushes with it runs, and point its service at the new repository path"
+		}
+		results = append(results, types.ValidationResult{
+			Item:           key,
+			Status:         types.ValidationSetupNeeded,
+			Message:        "Deploy key is not carried over by the transfer",
+			Recommendation: recommendation,
+		})
+	}
+	return results
+}
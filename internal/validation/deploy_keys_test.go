@@ -0,0 +1,31 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+func TestValidateDeployKeys(t *testing.T) {
+	tests := []struct {
+		key       string
+		wantWrite bool
+	}{
+		{"Deploy key: release-bot (read/write)", true},
+		{"Deploy key: mirror (read-only)", false},
+	}
+
+	for _, tt := range tests {
+		results := validateDeployKeys([]string{tt.key})
+		if len(results) != 1 || results[0].Status != types.ValidationSetupNeeded {
+			t.Fatalf("validateDeployKeys(%q) = %+v, want one setup_needed result", tt.key, results)
+		}
+		if got := strings.Contains(results[0].Recommendation, "write access"); got != tt.wantWrite {
+			t.Errorf("validateDeployKeys(%q) recommendation = %q, mentions write access %v, want %v", tt.key, results[0].Recommendation, got, tt.wantWrite)
+		}
+		if kind := classifyEffortItem("access", results[0]); kind != "readd_deploy_key" {
+			t.Errorf("classifyEffortItem(%q) = %s, want readd_deploy_key", tt.key, kind)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package validation
+
+import (
+	"sort"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+// ValidationDrift is a single item whose validation status changed between two validations
+type ValidationDrift struct {
+	ID       string                 `json:"id,omitempty"` // Finding ID
+	Category string                 `json:"category"`
+	Item     string                 `json:"item"`
+	Before   types.ValidationStatus `json:"before,omitempty"` // Empty when the item is new
+	After    types.ValidationStatus `json:"after,omitempty"`  // Empty when the item disappeared
+	Message  string                 `json:"message,omitempty"`
+}
+
+// statusSeverity orders statuses from harmless to blocking
+var statusSeverity = map[types.ValidationStatus]int{
+	types.ValidationReady:       0,
+	types.ValidationUnknown:     1,
+	types.ValidationWarning:     2,
+	types.ValidationReview:      3,
+	types.ValidationSetupNeeded: 4,
+	types.ValidationBlocker:     5,
+}
+
+// IsRegression reports whether the item got worse: it appeared with a non-ready status or its
+// status became more severe. Suppressed findings never regress.
+func (d ValidationDrift) IsRegression() bool {
+	if d.After == "" || d.After == types.ValidationSuppressed {
+		return false
+	}
+	if d.Before == "" {
+		return d.After != types.ValidationReady
+	}
+	return statusSeverity[d.After] > statusSeverity[d.Before]
+}
+
+// DiffValidations lists every item whose status differs between a stored (planned) validation
+// and a fresh one, sorted by category and item
+func DiffValidations(before, after *types.MigrationValidation) []ValidationDrift {
+	if before == nil || after == nil {
+		return nil
+	}
+
+	beforeItems := validationStatuses(before)
+	afterItems := validationStatuses(after)
+
+	var drifts []ValidationDrift
+	for key, result := range afterItems {
+		previous, existed := beforeItems[key]
+		if existed && previous.Status == result.Status {
+			continue
+		}
+		drift := ValidationDrift{ID: result.ID, Category: key.category, Item: key.item, After: result.Status, Message: result.Message}
+		if existed {
+			drift.Before = previous.Status
+		}
+		drifts = append(drifts, drift)
+	}
+	for key, result := range beforeItems {
+		if _, exists := afterItems[key]; !exists {
+			drifts = append(drifts, ValidationDrift{ID: result.ID, Category: key.category, Item: key.item, Before: result.Status})
+		}
+	}
+
+	sort.Slice(drifts, func(i, j int) bool {
+		if drifts[i].Category != drifts[j].Category {
+			return drifts[i].Category < drifts[j].Category
+		}
+		return drifts[i].Item < drifts[j].Item
+	})
+	return drifts
+}
+
+type validationKey struct {
+	category string
+	item     string
+}
+
+func validationStatuses(validation *types.MigrationValidation) map[validationKey]types.ValidationResult {
+	statuses := make(map[validationKey]types.ValidationResult)
+	categories := map[string][]types.ValidationResult{
+		"apps_integrations":   validation.AppsIntegrations,
+		"access_permissions":  validation.AccessPermissions,
+		"ci_dependencies":     validation.CIDependencies,
+		"governance":          validation.Governance,
+		"code_dependencies":   validation.CodeDependencies,
+		"security_compliance": validation.SecurityCompliance,
+	}
+	for category, results := range categories {
+		for _, result := range results {
+			statuses[validationKey{category, result.Item}] = result
+		}
+	}
+	return statuses
+}
@@ -0,0 +1,178 @@
+package validation
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+// DefaultEffortWeights are the minutes of remediation work assumed per validation item type
+var DefaultEffortWeights = map[string]int{
+	"create_team":        5,
+	"invite_user":        5,
+	"idp_team":           30,
+	"install_app":        30,
+	"custom_app":         120,
+	"create_secret":      10,
+	"create_variable":    5,
+	"configure_runner":   60,
+	"workflow_policy":    30,
+	"org_policy":         30,
+	"copy_template":      10,
+	"event_sink":         15,
+	"pin_update":         10,
+	"recreate_webhook":   10,
+	"readd_deploy_key":   10,
+	"codeowners_update":  5,
+	"attestation_verify": 15,
+	"pages_setup":        30,
+	"code_rewrite":       120,
+	"doc_url_rewrite":    5,
+	"security_setup":     60,
+	"manual_review":      15,
+}
+
+// effortWeights holds the weights used for the current run (defaults plus --effort-weights overrides)
+var effortWeights = copyWeights(DefaultEffortWeights)
+
+// LoadEffortWeights overrides the default weights with the values from a YAML file mapping
+// item types to minutes, e.g. "create_team: 10". Types that are not listed keep their default.
+func LoadEffortWeights(path string) error {
+	if path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read effort weights %s: %v", path, err)
+	}
+
+	var overrides map[string]int
+	if err := yaml.NewDecoder(bytes.NewReader(data)).Decode(&overrides); err != nil {
+		return fmt.Errorf("failed to parse effort weights %s: %v", path, err)
+	}
+
+	weights := copyWeights(DefaultEffortWeights)
+	for itemType, minutes := range overrides {
+		if _, known := DefaultEffortWeights[itemType]; !known {
+			return fmt.Errorf("effort weights %s: unknown item type '%s' (known: %s)", path, itemType, strings.Join(effortItemTypes(), ", "))
+		}
+		if minutes < 0 {
+			return fmt.Errorf("effort weights %s: '%s' must not be negative", path, itemType)
+		}
+		weights[itemType] = minutes
+	}
+
+	effortWeights = weights
+	return nil
+}
+
+// EstimateEffort sums the weights of every validation item that still needs work
+func EstimateEffort(validation *types.MigrationValidation) *types.EffortEstimate {
+	estimate := &types.EffortEstimate{ByType: make(map[string]int)}
+
+	for _, category := range validationCategories(validation) {
+		for _, result := range category.results {
+			if result.Status == types.ValidationReady || result.Status == types.ValidationSuppressed {
+				continue
+			}
+			itemType := classifyEffortItem(category.name, result)
+			minutes := effortWeights[itemType]
+			estimate.ByType[itemType] += minutes
+			estimate.TotalMinutes += minutes
+		}
+	}
+
+	return estimate
+}
+
+// classifyEffortItem maps a validation result to an effort item type
+func classifyEffortItem(category string, result types.ValidationResult) string {
+	message := strings.ToLower(result.Message)
+
+	switch category {
+	case "apps":
+		if strings.Contains(message, "pages") {
+			return "pages_setup"
+		}
+		if strings.Contains(message, "webhook") {
+			return "recreate_webhook"
+		}
+		if result.Status == types.ValidationBlocker {
+			return "custom_app"
+		}
+		return "install_app"
+	case "access":
+		if strings.Contains(message, "deploy key") {
+			return "readd_deploy_key"
+		}
+		if strings.HasPrefix(message, "codeowners") && (strings.Contains(message, "source organization") || strings.Contains(message, "ignored by github")) {
+			return "codeowners_update"
+		}
+		if strings.Contains(message, "idp") {
+			return "idp_team"
+		}
+		if strings.Contains(message, "team") {
+			return "create_team"
+		}
+		return "invite_user"
+	case "ci":
+		switch {
+		case strings.Contains(message, "attestation"):
+			return "attestation_verify"
+		case strings.Contains(message, "pinned"):
+			return "pin_update"
+		case strings.Contains(message, "reviewer team"):
+			return "create_team"
+		case strings.Contains(message, "reviewer user"):
+			return "invite_user"
+		case strings.Contains(message, "secret"):
+			return "create_secret"
+		case strings.Contains(message, "variable"):
+			return "create_variable"
+		case strings.Contains(message, "runner"):
+			return "configure_runner"
+		case strings.Contains(message, "workflow"):
+			return "workflow_policy"
+		}
+	case "governance":
+		if strings.Contains(message, "template") {
+			return "copy_template"
+		}
+		if strings.Contains(message, "events") {
+			return "event_sink"
+		}
+		return "org_policy"
+	case "code":
+		if strings.Contains(message, "documentation url") {
+			return "doc_url_rewrite"
+		}
+		return "code_rewrite"
+	case "security":
+		return "security_setup"
+	}
+	return "manual_review"
+}
+
+func copyWeights(weights map[string]int) map[string]int {
+	copied := make(map[string]int, len(weights))
+	for itemType, minutes := range weights {
+		copied[itemType] = minutes
+	}
+	return copied
+}
+
+func effortItemTypes() []string {
+	itemTypes := make([]string, 0, len(DefaultEffortWeights))
+	for itemType := range DefaultEffortWeights {
+		itemTypes = append(itemTypes, itemType)
+	}
+	sort.Strings(itemTypes)
+	return itemTypes
+}
@@ -0,0 +1,91 @@
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/jefeish/gh-repo-transfer/internal/enterprise"
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+// targetEnterprise is the enterprise whose policies are scanned with the target org (--target-enterprise)
+var targetEnterprise string
+
+// SetTargetEnterprise sets the enterprise of the target organization; empty disables the scan
+func SetTargetEnterprise(slug string) {
+	targetEnterprise = slug
+}
+
+// validateEnterprisePolicies compares the enterprise policies governing the source repository
+// with those the target enterprise enforces. Policies the target enterprise leaves to its
+// organizations are only reported when they differ from an enforced source policy.
+func validateEnterprisePolicies(source []types.OrgPolicy, capabilities *types.TargetOrgCapabilities) []types.ValidationResult {
+	var results []types.ValidationResult
+
+	sourcePolicies := make(map[string]types.OrgPolicy, len(source))
+	for _, policy := range source {
+		sourcePolicies[policy.Name] = policy
+	}
+
+	for _, target := range capabilities.EnterprisePolicies {
+		item := fmt.Sprintf("%s: %s", target.Name, target.Status)
+		sourcePolicy, known := sourcePolicies[target.Name]
+
+		if known && sourcePolicy.Status == target.Status && reflect.DeepEqual(sourcePolicy.Restrictions, target.Restrictions) {
+			results = append(results, types.ValidationResult{
+				Item:    item,
+				Status:  types.ValidationReady,
+				Message: "Target enterprise enforces the same policy",
+			})
+			continue
+		}
+		if target.Status == enterprise.NoPolicy && (!known || sourcePolicy.Status == enterprise.NoPolicy) {
+			continue
+		}
+
+		sourceStatus := "unknown"
+		if known {
+			sourceStatus = sourcePolicy.Status
+		}
+		status, message, recommendation := classifyEnterprisePolicy(target, sourceStatus, capabilities.Enterprise)
+		results = append(results, types.ValidationResult{
+			Item:           item,
+			Status:         status,
+			Message:        message,
+			Recommendation: recommendation,
+		})
+	}
+
+	return results
+}
+
+// classifyEnterprisePolicy rates a target enterprise policy that differs from the source
+func classifyEnterprisePolicy(target types.OrgPolicy, sourceStatus, targetEnterprise string) (types.ValidationStatus, string, string) {
+	message := fmt.Sprintf("Target enterprise %s sets %s (source: %s)", targetEnterprise, target.Status, sourceStatus)
+	if len(target.Restrictions) > 0 {
+		message += "; " + strings.Join(target.Restrictions, ", ")
+	}
+	recommendation := fmt.Sprintf("Confirm the repository works under this policy or ask the %s enterprise owners for an exception", targetEnterprise)
+
+	switch target.Name {
+	case enterprise.PolicyActions:
+		switch target.Status {
+		case "disabled":
+			return types.ValidationBlocker, message + "; workflows will not run",
+				fmt.Sprintf("Ask the %s enterprise owners to enable Actions for the target organization", targetEnterprise)
+		case "local_only":
+			if sourceStatus != "local_only" {
+				return types.ValidationBlocker, message + "; workflows using public actions will fail",
+					"Replace public actions with copies inside the target enterprise or request an allowlist"
+			}
+		case "selected":
+			return types.ValidationWarning, message,
+				"Check every action used by the workflows against the allowed patterns"
+		}
+	case enterprise.PolicyAdvancedSecurity, enterprise.PolicySecretScanning, enterprise.PolicyPushProtection:
+		return types.ValidationWarning, message + "; defaults apply to new repositories only",
+			"Enable the security features on the repository after the move"
+	}
+	return types.ValidationWarning, message, recommendation
+}
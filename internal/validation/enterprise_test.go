@@ -0,0 +1,66 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/jefeish/gh-repo-transfer/internal/enterprise"
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+func TestValidateEnterprisePolicies(t *testing.T) {
+	tests := []struct {
+		name   string
+		source []types.OrgPolicy
+		target types.OrgPolicy
+		want   []types.ValidationStatus
+	}{
+		{
+			name:   "same policy",
+			source: []types.OrgPolicy{{Name: enterprise.PolicyActions, Status: "all"}},
+			target: types.OrgPolicy{Name: enterprise.PolicyActions, Status: "all"},
+			want:   []types.ValidationStatus{types.ValidationReady},
+		},
+		{
+			name:   "actions restricted to the enterprise",
+			source: []types.OrgPolicy{{Name: enterprise.PolicyActions, Status: "all"}},
+			target: types.OrgPolicy{Name: enterprise.PolicyActions, Status: "local_only"},
+			want:   []types.ValidationStatus{types.ValidationBlocker},
+		},
+		{
+			name:   "actions disabled, source enterprise unknown",
+			target: types.OrgPolicy{Name: enterprise.PolicyActions, Status: "disabled"},
+			want:   []types.ValidationStatus{types.ValidationBlocker},
+		},
+		{
+			name:   "different repository policy",
+			source: []types.OrgPolicy{{Name: enterprise.PolicyRepositoryCreation, Status: "all"}},
+			target: types.OrgPolicy{Name: enterprise.PolicyRepositoryCreation, Status: "private"},
+			want:   []types.ValidationStatus{types.ValidationWarning},
+		},
+		{
+			name:   "no policy in either enterprise",
+			source: []types.OrgPolicy{{Name: enterprise.PolicyPrivateForking, Status: enterprise.NoPolicy}},
+			target: types.OrgPolicy{Name: enterprise.PolicyPrivateForking, Status: enterprise.NoPolicy},
+			want:   []types.ValidationStatus{types.ValidationReady},
+		},
+		{
+			name:   "no policy in target, unknown source",
+			target: types.OrgPolicy{Name: enterprise.PolicyPrivateForking, Status: enterprise.NoPolicy},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			capabilities := &types.TargetOrgCapabilities{Enterprise: "target-ent", EnterprisePolicies: []types.OrgPolicy{tt.target}}
+			results := validateEnterprisePolicies(tt.source, capabilities)
+			if len(results) != len(tt.want) {
+				t.Fatalf("validateEnterprisePolicies() = %+v, want statuses %v", results, tt.want)
+			}
+			for i, result := range results {
+				if result.Status != tt.want[i] {
+					t.Errorf("result %d status = %s, want %s (%s)", i, result.Status, tt.want[i], result.Message)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jefeish/gh-repo-transfer/internal/dependencies"
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+// validateEnvironmentItems checks what --migrate-environments recreates: required reviewers
+// must exist in the target for the protection rule to keep them, secret values cannot be
+// copied, and variables are recreated with their values
+func validateEnvironmentItems(ci types.ActionsCIDependencies, capabilities *types.TargetOrgCapabilities) []types.ValidationResult {
+	var results []types.ValidationResult
+	for _, item := range ci.EnvironmentReviewers {
+		reviewer, environment := dependencies.SplitEnvironmentItem(item)
+		if team, ok := strings.CutPrefix(reviewer, "team:"); ok {
+			if isTeamAvailable(team, capabilities) {
+				results = append(results, types.ValidationResult{
+					Item:    item,
+					Status:  types.ValidationReady,
+					Message: "Reviewer team exists in target organization",
+				})
+				continue
+			}
+			results = append(results, types.ValidationResult{
+				Item:           item,
+				Status:         types.ValidationSetupNeeded,
+				Message:        fmt.Sprintf("Reviewer team does not exist in target organization; environment '%s' would lose this reviewer", environment),
+				Recommendation: fmt.Sprintf("Create team '%s' in target organization before migrating environments", team),
+			})
+			continue
+		}
+		user := strings.TrimPrefix(reviewer, "user:")
+		results = append(results, types.ValidationResult{
+			Item:           item,
+			Status:         types.ValidationReview,
+			Message:        fmt.Sprintf("Reviewer user must keep access to the repository to approve deployments to '%s'", environment),
+			Recommendation: fmt.Sprintf("Make sure '%s' is a member or collaborator in %s", user, capabilities.Organization),
+		})
+	}
+
+	for _, item := range ci.EnvironmentSecrets {
+		name, environment := dependencies.SplitEnvironmentItem(item)
+		results = append(results, types.ValidationResult{
+			Item:           item,
+			Status:         types.ValidationReview,
+			Message:        "Environment secret values cannot be read or copied",
+			Recommendation: fmt.Sprintf("Set '%s' again on environment '%s' after the move (--migrate-environments reports it, --prompt-secrets asks for its value)", name, environment),
+		})
+	}
+	for _, item := range ci.EnvironmentVariables {
+		results = append(results, types.ValidationResult{
+			Item:    item,
+			Status:  types.ValidationReady,
+			Message: "Environment variable is recreated with its value by --migrate-environments",
+		})
+	}
+	return results
+}
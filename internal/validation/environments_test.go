@@ -0,0 +1,43 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+func TestValidateEnvironmentItems(t *testing.T) {
+	ci := types.ActionsCIDependencies{
+		EnvironmentReviewers: []string{"team:platform (environment: production)", "team:release-managers (environment: production)", "user:octocat (environment: staging)"},
+		EnvironmentSecrets:   []string{"DEPLOY_KEY (environment: production)"},
+		EnvironmentVariables: []string{"REGION (environment: production)"},
+	}
+	capabilities := &types.TargetOrgCapabilities{Organization: "new-org", Teams: []string{"Platform"}}
+
+	tests := []struct {
+		item   string
+		status types.ValidationStatus
+		effort string
+	}{
+		{"team:platform (environment: production)", types.ValidationReady, ""},
+		{"team:release-managers (environment: production)", types.ValidationSetupNeeded, "create_team"},
+		{"user:octocat (environment: staging)", types.ValidationReview, "invite_user"},
+		{"DEPLOY_KEY (environment: production)", types.ValidationReview, "create_secret"},
+		{"REGION (environment: production)", types.ValidationReady, ""},
+	}
+
+	results := validateEnvironmentItems(ci, capabilities)
+	if len(results) != len(tests) {
+		t.Fatalf("got %d results, want %d: %+v", len(results), len(tests), results)
+	}
+	for i, tt := range tests {
+		if results[i].Item != tt.item || results[i].Status != tt.status {
+			t.Errorf("result %d = %s (%s), want %s (%s)", i, results[i].Item, results[i].Status, tt.item, tt.status)
+		}
+		if tt.effort != "" {
+			if got := classifyEffortItem("ci", results[i]); got != tt.effort {
+				t.Errorf("classifyEffortItem(%s) = %s, want %s", tt.item, got, tt.effort)
+			}
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+// validateEventSinks warns about org webhooks and audit log streams that stop receiving the
+// repository's events after the move, unless the target org delivers to the same destination
+func validateEventSinks(sinks []string, capabilities *types.TargetOrgCapabilities) []types.ValidationResult {
+	var results []types.ValidationResult
+	for _, sink := range sinks {
+		destination := eventSinkDestination(sink)
+
+		if strings.HasPrefix(sink, "Audit log stream") {
+			results = append(results, types.ValidationResult{
+				Item:           sink,
+				Status:         types.ValidationReview,
+				Message:        "Enterprise audit log stream only receives events of the enterprise's organizations",
+				Recommendation: fmt.Sprintf("Confirm %s belongs to the same enterprise, or configure audit log streaming to %s for its enterprise", capabilities.Organization, destination),
+			})
+			continue
+		}
+
+		if containsName(capabilities.WebhookDestinations, destination) {
+			results = append(results, types.ValidationResult{
+				Item:    sink,
+				Status:  types.ValidationReady,
+				Message: "Target organization has a webhook delivering to the same destination",
+			})
+			continue
+		}
+		results = append(results, types.ValidationResult{
+			Item:           sink,
+			Status:         types.ValidationWarning,
+			Message:        fmt.Sprintf("Repository events stop flowing to %s after the move", destination),
+			Recommendation: fmt.Sprintf("Add an org webhook for the same events in %s, or update the receiver to accept the new organization", capabilities.Organization),
+		})
+	}
+	return results
+}
+
+// eventSinkDestination extracts the destination from an event sink item,
+// e.g. "Org webhook → https://ci.example.com/hook (events: push)"
+func eventSinkDestination(sink string) string {
+	destination := sink
+	if idx := strings.Index(destination, "→ "); idx != -1 {
+		destination = destination[idx+len("→ "):]
+	}
+	if idx := strings.Index(destination, " ("); idx != -1 && strings.HasPrefix(sink, "Org webhook") {
+		destination = destination[:idx]
+	}
+	return destination
+}
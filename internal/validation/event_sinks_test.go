@@ -0,0 +1,30 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+func TestValidateEventSinks(t *testing.T) {
+	capabilities := &types.TargetOrgCapabilities{
+		Organization:        "target",
+		WebhookDestinations: []string{"https://ci.example.com/hook"},
+	}
+
+	tests := []struct {
+		sink string
+		want types.ValidationStatus
+	}{
+		{"Org webhook → https://ci.example.com/hook (events: push)", types.ValidationReady},
+		{"Org webhook → https://chat.example.com/in (events: *)", types.ValidationWarning},
+		{"Audit log stream → Splunk (splunk.example.com)", types.ValidationReview},
+	}
+
+	for _, tt := range tests {
+		results := validateEventSinks([]string{tt.sink}, capabilities)
+		if len(results) != 1 || results[0].Status != tt.want {
+			t.Errorf("validateEventSinks(%q) = %+v, want status %s", tt.sink, results, tt.want)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+// Exit codes of deps --target-org, so CI gates can use it as a pre-migration check. Errors of
+// the tool itself exit with 1.
+const (
+	ExitReady       = 0 // Every repository is ready (or only has warnings)
+	ExitSetupNeeded = 2 // Something has to be created or reviewed in the target first
+	ExitBlockers    = 3 // A repository has validation blockers
+	ExitWarnings    = 4 // A repository has warnings (--fail-on warnings only)
+)
+
+// FailOn is the outcome from which deps --target-org exits with a non-zero code
+type FailOn string
+
+const (
+	FailOnDefault  FailOn = ""         // Blockers and setup needed
+	FailOnBlockers FailOn = "blockers" // Only blockers
+	FailOnWarnings FailOn = "warnings" // Blockers, setup needed and warnings
+)
+
+// ParseFailOn parses a --fail-on value
+func ParseFailOn(value string) (FailOn, error) {
+	switch failOn := FailOn(strings.ToLower(strings.TrimSpace(value))); failOn {
+	case FailOnDefault, FailOnBlockers, FailOnWarnings:
+		return failOn, nil
+	}
+	return "", fmt.Errorf("invalid --fail-on %q: use blockers or warnings", value)
+}
+
+// ExitCode returns the exit code for the validations of a run: the code of the least ready
+// repository. Repositories without a validation do not count.
+func ExitCode(validations []*types.MigrationValidation, failOn FailOn) int {
+	code := ExitReady
+	for _, validation := range validations {
+		if validation == nil {
+			continue
+		}
+		if repositoryCode := readinessExitCode(validation.OverallReadiness, failOn); exitSeverity(repositoryCode) > exitSeverity(code) {
+			code = repositoryCode
+		}
+	}
+	return code
+}
+
+// readinessExitCode returns the exit code of one repository's overall readiness. Findings whose
+// status could not be determined need a look like setup items do.
+func readinessExitCode(readiness types.ValidationStatus, failOn FailOn) int {
+	switch readiness {
+	case types.ValidationBlocker:
+		return ExitBlockers
+	case types.ValidationSetupNeeded, types.ValidationReview, types.ValidationUnknown:
+		if failOn != FailOnBlockers {
+			return ExitSetupNeeded
+		}
+	case types.ValidationWarning:
+		if failOn == FailOnWarnings {
+			return ExitWarnings
+		}
+	}
+	return ExitReady
+}
+
+// exitSeverity orders exit codes from ready to blocked
+func exitSeverity(code int) int {
+	switch code {
+	case ExitWarnings:
+		return 1
+	case ExitSetupNeeded:
+		return 2
+	case ExitBlockers:
+		return 3
+	}
+	return 0
+}
@@ -0,0 +1,43 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+func TestExitCode(t *testing.T) {
+	validationOf := func(readiness types.ValidationStatus) *types.MigrationValidation {
+		return &types.MigrationValidation{OverallReadiness: readiness}
+	}
+	ready := validationOf(types.ValidationReady)
+	warning := validationOf(types.ValidationWarning)
+	setup := validationOf(types.ValidationSetupNeeded)
+	blocker := validationOf(types.ValidationBlocker)
+
+	tests := []struct {
+		name        string
+		validations []*types.MigrationValidation
+		failOn      FailOn
+		want        int
+	}{
+		{"no validation", []*types.MigrationValidation{nil}, FailOnDefault, ExitReady},
+		{"ready", []*types.MigrationValidation{ready}, FailOnDefault, ExitReady},
+		{"warnings pass by default", []*types.MigrationValidation{ready, warning}, FailOnDefault, ExitReady},
+		{"setup needed", []*types.MigrationValidation{warning, setup, ready}, FailOnDefault, ExitSetupNeeded},
+		{"blockers win", []*types.MigrationValidation{setup, blocker, warning}, FailOnDefault, ExitBlockers},
+		{"fail on blockers ignores setup", []*types.MigrationValidation{setup, warning}, FailOnBlockers, ExitReady},
+		{"fail on blockers", []*types.MigrationValidation{setup, blocker}, FailOnBlockers, ExitBlockers},
+		{"fail on warnings", []*types.MigrationValidation{ready, warning}, FailOnWarnings, ExitWarnings},
+		{"setup before warnings", []*types.MigrationValidation{warning, setup}, FailOnWarnings, ExitSetupNeeded},
+	}
+	for _, tt := range tests {
+		if got := ExitCode(tt.validations, tt.failOn); got != tt.want {
+			t.Errorf("%s: ExitCode() = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+
+	if _, err := ParseFailOn("errors"); err == nil {
+		t.Errorf("ParseFailOn() accepted an unknown value")
+	}
+}
@@ -0,0 +1,91 @@
+package validation
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+// FindingKind returns the kind of a validation result in a category ("apps", "access", "ci",
+// "governance", "code" or "security"), e.g. "ci.create_secret". Results needing the same kind
+// of remediation share a kind, which the explain command looks up in its knowledge base.
+func FindingKind(category string, result types.ValidationResult) string {
+	return category + "." + classifyEffortItem(category, result)
+}
+
+// FindingID returns the stable ID of a validation result: its category and a hash of the
+// category and item, e.g. "ci-3f9a1c2e". Unlike the kind, neither depends on the status or the
+// message, so the same dependency keeps its ID across runs and repositories.
+func FindingID(category string, result types.ValidationResult) string {
+	return category + "-" + findingHash(category, result.Item)
+}
+
+func findingHash(category, item string) string {
+	sum := sha256.Sum256([]byte(category + "\x00" + strings.ToLower(strings.TrimSpace(item))))
+	return hex.EncodeToString(sum[:4])
+}
+
+// SplitFindingID splits a finding ID into its kind and hash; IDs without a hash, such as kinds,
+// are returned unchanged with an empty hash
+func SplitFindingID(id string) (kind, hash string) {
+	index := strings.LastIndex(id, "-")
+	if index < 0 || len(id)-index-1 != 8 {
+		return id, ""
+	}
+	if _, err := hex.DecodeString(id[index+1:]); err != nil {
+		return id, ""
+	}
+	return id[:index], id[index+1:]
+}
+
+// validationCategories returns the results of a validation per finding category
+func validationCategories(validation *types.MigrationValidation) []struct {
+	name    string
+	results []types.ValidationResult
+} {
+	return []struct {
+		name    string
+		results []types.ValidationResult
+	}{
+		{"apps", validation.AppsIntegrations},
+		{"access", validation.AccessPermissions},
+		{"ci", validation.CIDependencies},
+		{"governance", validation.Governance},
+		{"code", validation.CodeDependencies},
+		{"security", validation.SecurityCompliance},
+	}
+}
+
+// matchesFinding reports whether rule, a finding ID, its hash, a kind or a category, covers a
+// result of a category. IDs of earlier versions, "<kind>-<hash>", still match by their hash.
+func matchesFinding(rule, category string, result types.ValidationResult) bool {
+	rule = strings.ToLower(strings.TrimSpace(rule))
+	_, hash := SplitFindingID(result.ID)
+	if rule == strings.ToLower(result.ID) || rule == strings.ToLower(result.Kind) || rule == category || rule == hash {
+		return true
+	}
+	_, ruleHash := SplitFindingID(rule)
+	return ruleHash != "" && ruleHash == hash
+}
+
+// AssignFindingIDs sets the ID and kind of every validation result
+func AssignFindingIDs(validation *types.MigrationValidation) {
+	for _, category := range validationCategories(validation) {
+		for i := range category.results {
+			result := &category.results[i]
+			result.ID = FindingID(category.name, *result)
+			// The kind depends on the status, so a suppressed or overridden finding is classified by
+			// its original one
+			classified := *result
+			if result.Suppression != nil {
+				classified.Status = result.Suppression.Status
+			}
+			if result.Override != nil {
+				classified.Status = result.Override.Status
+			}
+			result.Kind = FindingKind(category.name, classified)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+func TestAssignFindingIDs(t *testing.T) {
+	validation := &types.MigrationValidation{
+		AppsIntegrations:  []types.ValidationResult{{Item: "Legacy App", Status: types.ValidationBlocker}},
+		AccessPermissions: []types.ValidationResult{{Item: "platform", Status: types.ValidationSetupNeeded, Message: "Team not found in target"}},
+		CIDependencies: []types.ValidationResult{
+			{Item: "NPM_TOKEN", Status: types.ValidationSetupNeeded, Message: "Secret not found in target organization"},
+			{Item: "gpu", Status: types.ValidationReady, Message: "Runner label available"},
+		},
+		Governance:         []types.ValidationResult{{Item: "Issue templates", Status: types.ValidationReview, Message: "Org template repository"}},
+		SecurityCompliance: []types.ValidationResult{{Item: "Secret scanning", Status: types.ValidationReview}},
+	}
+	AssignFindingIDs(validation)
+
+	tests := []struct {
+		result       types.ValidationResult
+		wantCategory string
+		wantKind     string
+	}{
+		{validation.AppsIntegrations[0], "apps", "apps.custom_app"},
+		{validation.AccessPermissions[0], "access", "access.create_team"},
+		{validation.CIDependencies[0], "ci", "ci.create_secret"},
+		{validation.CIDependencies[1], "ci", "ci.configure_runner"},
+		{validation.Governance[0], "governance", "governance.copy_template"},
+		{validation.SecurityCompliance[0], "security", "security.security_setup"},
+	}
+	for _, tt := range tests {
+		category, hash := SplitFindingID(tt.result.ID)
+		if category != tt.wantCategory || len(hash) != 8 {
+			t.Errorf("ID of %s = %q, want category %q with a hash", tt.result.Item, tt.result.ID, tt.wantCategory)
+		}
+		if tt.result.Kind != tt.wantKind {
+			t.Errorf("Kind of %s = %q, want %q", tt.result.Item, tt.result.Kind, tt.wantKind)
+		}
+	}
+}
+
+func TestFindingIDStable(t *testing.T) {
+	setup := types.ValidationResult{Item: "NPM_TOKEN", Status: types.ValidationSetupNeeded, Message: "Secret not found in target organization"}
+	ready := types.ValidationResult{Item: "npm_token ", Status: types.ValidationReady, Message: "Secret available in target organization"}
+	if a, b := FindingID("ci", setup), FindingID("ci", ready); a != b {
+		t.Errorf("FindingID() differs between runs: %s, %s", a, b)
+	}
+	if a, b := FindingID("ci", setup), FindingID("access", setup); strings.HasSuffix(a, b[len(b)-8:]) {
+		t.Errorf("FindingID() has the same hash in different categories: %s, %s", a, b)
+	}
+	// The kind of an app depends on its status; its ID must not
+	custom := types.ValidationResult{Item: "Legacy App", Status: types.ValidationBlocker}
+	installed := types.ValidationResult{Item: "Legacy App", Status: types.ValidationSetupNeeded}
+	if FindingKind("apps", custom) == FindingKind("apps", installed) {
+		t.Fatalf("FindingKind() is the same for both statuses")
+	}
+	if a, b := FindingID("apps", custom), FindingID("apps", installed); a != b {
+		t.Errorf("FindingID() changes with the kind: %s, %s", a, b)
+	}
+}
+
+func TestMatchesFinding(t *testing.T) {
+	result := types.ValidationResult{Item: "NPM_TOKEN", Status: types.ValidationSetupNeeded, Message: "Secret not found in target organization"}
+	result.ID = FindingID("ci", result)
+	result.Kind = FindingKind("ci", result)
+	_, hash := SplitFindingID(result.ID)
+
+	for _, rule := range []string{result.ID, hash, "CI.Create_Secret", "ci", "ci.create_variable-" + hash} {
+		if !matchesFinding(rule, "ci", result) {
+			t.Errorf("matchesFinding(%s) = false, want true", rule)
+		}
+	}
+	for _, rule := range []string{"ci.create_variable", "access", "ci-00000000"} {
+		if matchesFinding(rule, "ci", result) {
+			t.Errorf("matchesFinding(%s) = true, want false", rule)
+		}
+	}
+}
+
+func TestSplitFindingID(t *testing.T) {
+	tests := []struct {
+		id, wantKind, wantHash string
+	}{
+		{"ci.create_secret-3f9a1c2e", "ci.create_secret", "3f9a1c2e"},
+		{"ci.create_secret", "ci.create_secret", ""},
+		{"custom.license-check", "custom.license-check", ""},
+		{"ci.create_secret-zzzzzzzz", "ci.create_secret-zzzzzzzz", ""},
+	}
+	for _, tt := range tests {
+		if kind, hash := SplitFindingID(tt.id); kind != tt.wantKind || hash != tt.wantHash {
+			t.Errorf("SplitFindingID(%s) = %s, %s, want %s, %s", tt.id, kind, hash, tt.wantKind, tt.wantHash)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package validation
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+
+	"github.com/jefeish/gh-repo-transfer/internal/ghclient"
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+// scanHostedNetworking reads the runner groups of the target organization and its hosted
+// compute network configurations, and which groups are bound to one
+func scanHostedNetworking(client api.RESTClient, targetOrg string, capabilities *types.TargetOrgCapabilities, verbose bool) error {
+	var groups []struct {
+		Name                   string `json:"name"`
+		NetworkConfigurationID string `json:"network_configuration_id"`
+	}
+	if err := ghclient.GetAllField(&client, fmt.Sprintf("orgs/%s/actions/runner-groups", targetOrg), "runner_groups", &groups); err != nil {
+		return fmt.Errorf("failed to get runner groups: %v", err)
+	}
+
+	var configurations []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+	configurationsErr := ghclient.GetAllField(&client, fmt.Sprintf("orgs/%s/settings/network-configurations", targetOrg), "network_configurations", &configurations)
+	if configurationsErr != nil && verbose {
+		fmt.Fprintf(os.Stderr, "Warning: failed to get network configurations: %v\n", configurationsErr)
+	}
+	names := make(map[string]string)
+	for _, configuration := range configurations {
+		capabilities.NetworkConfigurations = append(capabilities.NetworkConfigurations, configuration.Name)
+		names[configuration.ID] = configuration.Name
+	}
+
+	capabilities.NetworkRunnerGroups = make(map[string]string)
+	for _, group := range groups {
+		capabilities.RunnerGroups = append(capabilities.RunnerGroups, group.Name)
+		if group.NetworkConfigurationID == "" {
+			continue
+		}
+		name := names[group.NetworkConfigurationID]
+		if name == "" {
+			name = group.NetworkConfigurationID
+		}
+		capabilities.NetworkRunnerGroups[group.Name] = name
+		// Without access to the configurations, those bound to a group are known by their IDs
+		if configurationsErr != nil && !containsFold(capabilities.NetworkConfigurations, name) {
+			capabilities.NetworkConfigurations = append(capabilities.NetworkConfigurations, name)
+		}
+	}
+
+	if verbose {
+		fmt.Fprintf(os.Stderr, "Found %d runner groups and %d network configurations in target org\n", len(capabilities.RunnerGroups), len(capabilities.NetworkConfigurations))
+	}
+	return nil
+}
+
+// validateHostedNetworking checks the runner groups bound to a network configuration of the
+// source organization against those of the target. Network configurations belong to an
+// organization, so jobs in such a group lose their private network after the move unless the
+// target has a group of the same name bound to a network of its own.
+func validateHostedNetworking(ci types.ActionsCIDependencies, capabilities *types.TargetOrgCapabilities) []types.ValidationResult {
+	var results []types.ValidationResult
+	available := strings.Join(capabilities.NetworkConfigurations, ", ")
+
+	for _, entry := range ci.NetworkBoundRunnerGroups {
+		// "Runner group: <name> (network configuration: <name>)"
+		group, _, _ := strings.Cut(strings.TrimPrefix(entry, "Runner group: "), " (network configuration: ")
+
+		result := types.ValidationResult{Item: entry}
+		network, bound := lookupFold(capabilities.NetworkRunnerGroups, group)
+		switch {
+		case bound:
+			result.Status = types.ValidationReady
+			result.Message = fmt.Sprintf("Runner group is bound to network configuration %s in target organization", network)
+		case len(capabilities.NetworkConfigurations) == 0:
+			result.Status = types.ValidationBlocker
+			result.Message = "Target organization has no hosted compute network configuration for the network-bound runner group"
+			result.Recommendation = fmt.Sprintf("Set up private networking for %s (a network configuration for its Azure virtual network), then bind runner group '%s' to it", capabilities.Organization, group)
+		case containsFold(capabilities.RunnerGroups, group):
+			result.Status = types.ValidationSetupNeeded
+			result.Message = "Runner group exists in target organization but is not bound to a network configuration"
+			result.Recommendation = fmt.Sprintf("Bind runner group '%s' to one of the network configurations of %s (%s)", group, capabilities.Organization, available)
+		default:
+			result.Status = types.ValidationSetupNeeded
+			result.Message = "Network-bound runner group does not exist in target organization"
+			result.Recommendation = fmt.Sprintf("Create runner group '%s' with one of the network configurations of %s (%s) and add its hosted runners", group, capabilities.Organization, available)
+		}
+		results = append(results, result)
+	}
+
+	for _, runner := range ci.StaticIPRunners {
+		results = append(results, types.ValidationResult{
+			Item:           runner,
+			Status:         types.ValidationReview,
+			Message:        "Hosted runner has static public IPs; a runner created in the target organization gets different ones",
+			Recommendation: "Create the hosted runner with static IPs in the target organization and add its IPs to the allow lists of the services the workflows reach",
+		})
+	}
+	return results
+}
+
+// lookupFold returns the value of a key compared case-insensitively
+func lookupFold(values map[string]string, key string) (string, bool) {
+	for candidate, value := range values {
+		if strings.EqualFold(candidate, key) {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// containsFold reports whether a list contains a value compared case-insensitively
+func containsFold(values []string, value string) bool {
+	for _, candidate := range values {
+		if strings.EqualFold(candidate, value) {
+			return true
+		}
+	}
+	return false
+}
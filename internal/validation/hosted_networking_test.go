@@ -0,0 +1,51 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+func TestValidateHostedNetworking(t *testing.T) {
+	capabilities := &types.TargetOrgCapabilities{
+		Organization:          "acme-new",
+		RunnerGroups:          []string{"Default", "Private", "Staging"},
+		NetworkConfigurations: []string{"vnet-eastus"},
+		NetworkRunnerGroups:   map[string]string{"Private": "vnet-eastus"},
+	}
+
+	tests := []struct {
+		name         string
+		entry        string
+		capabilities *types.TargetOrgCapabilities
+		status       types.ValidationStatus
+	}{
+		{"bound in target", "Runner group: private (network configuration: vnet-westus)", capabilities, types.ValidationReady},
+		{"group not bound", "Runner group: Staging (network configuration: vnet-westus)", capabilities, types.ValidationSetupNeeded},
+		{"group missing", "Runner group: Build (network configuration: vnet-westus)", capabilities, types.ValidationSetupNeeded},
+		{"no network configurations", "Runner group: Private (network configuration: vnet-westus)", &types.TargetOrgCapabilities{Organization: "acme-new"}, types.ValidationBlocker},
+	}
+	for _, tt := range tests {
+		ci := types.ActionsCIDependencies{NetworkBoundRunnerGroups: []string{tt.entry}}
+		results := validateHostedNetworking(ci, tt.capabilities)
+		if len(results) != 1 {
+			t.Errorf("%s: %d results, want 1", tt.name, len(results))
+			continue
+		}
+		if results[0].Status != tt.status {
+			t.Errorf("%s: status = %s, want %s", tt.name, results[0].Status, tt.status)
+		}
+		if kind := FindingKind("ci", results[0]); kind != "ci.configure_runner" {
+			t.Errorf("%s: kind = %s, want ci.configure_runner", tt.name, kind)
+		}
+	}
+
+	ci := types.ActionsCIDependencies{StaticIPRunners: []string{"Hosted runner: deploy (static IPs: 20.1.2.0/28)"}}
+	results := validateHostedNetworking(ci, capabilities)
+	if len(results) != 1 || results[0].Status != types.ValidationReview {
+		t.Fatalf("static IP runner: got %+v, want one review item", results)
+	}
+	if kind := FindingKind("ci", results[0]); kind != "ci.configure_runner" {
+		t.Errorf("static IP runner: kind = %s, want ci.configure_runner", kind)
+	}
+}
@@ -0,0 +1,78 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+// idpGroupsOf returns the IdP groups recorded on a team item, e.g.
+// "platform (write) [IdP-synced: eng-platform]"; false when the team is not IdP-synced
+func idpGroupsOf(team string) ([]string, bool) {
+	idx := strings.Index(team, "[IdP-synced")
+	if idx == -1 {
+		return nil, false
+	}
+	list := strings.TrimSuffix(strings.TrimPrefix(team[idx:], "[IdP-synced"), "]")
+	list = strings.TrimSpace(strings.TrimPrefix(list, ":"))
+	if list == "" {
+		return nil, true
+	}
+	return strings.Split(list, "; "), true
+}
+
+// validateIdPTeam classifies a team whose membership is synced from an IdP. Such a team cannot
+// be recreated with --create and its members cannot be copied: the target team has to be
+// connected to the same IdP groups, which must first be available to the target organization.
+func validateIdPTeam(team, teamName string, groups []string, capabilities *types.TargetOrgCapabilities) types.ValidationResult {
+	groupList := strings.Join(groups, ", ")
+	if groupList == "" {
+		groupList = "its IdP group"
+	}
+
+	if isTeamAvailable(teamName, capabilities) {
+		return types.ValidationResult{
+			Item:    team,
+			Status:  types.ValidationReady,
+			Message: fmt.Sprintf("Team exists in target organization; its membership should follow %s", groupList),
+		}
+	}
+
+	var unavailable []string
+	for _, group := range groups {
+		if !containsName(capabilities.IdPGroups, group) {
+			unavailable = append(unavailable, group)
+		}
+	}
+
+	mechanism := "team synchronization"
+	if capabilities.IdPGroupSource == "external_groups" {
+		mechanism = "an external group"
+	}
+
+	if capabilities.IdPGroupSource == "" || len(groups) == 0 || len(unavailable) > 0 {
+		missing := strings.Join(unavailable, ", ")
+		if missing == "" {
+			missing = groupList
+		}
+		message := fmt.Sprintf("IdP-synced team cannot be recreated with --create; IdP group(s) %s not available in target organization", missing)
+		if capabilities.IdPGroupSource == "" {
+			message = "IdP-synced team cannot be recreated with --create; target organization has no IdP team synchronization"
+		}
+		return types.ValidationResult{
+			Item:    team,
+			Status:  types.ValidationBlocker,
+			Message: message,
+			Recommendation: fmt.Sprintf("In the IdP, provision %s to the target organization (team synchronization or the Enterprise Managed Users app), "+
+				"then create team '%s' and connect it to the group(s); do not copy members by hand", groupList, teamName),
+		}
+	}
+
+	return types.ValidationResult{
+		Item:           team,
+		Status:         types.ValidationSetupNeeded,
+		Message:        fmt.Sprintf("IdP-synced team must be created and connected to IdP group(s) %s", groupList),
+		Recommendation: fmt.Sprintf("Create team '%s' in target organization and connect it to %s through %s; membership then follows the IdP", teamName, groupList, mechanism),
+	}
+}
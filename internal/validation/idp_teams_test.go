@@ -0,0 +1,58 @@
+package validation
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+func TestIdPGroupsOf(t *testing.T) {
+	tests := []struct {
+		team       string
+		wantGroups []string
+		wantSynced bool
+	}{
+		{"platform (write)", nil, false},
+		{"platform (write) [IdP-synced]", nil, true},
+		{"platform (write) [IdP-synced: eng-platform; eng-all]", []string{"eng-platform", "eng-all"}, true},
+	}
+
+	for _, tt := range tests {
+		groups, synced := idpGroupsOf(tt.team)
+		if !reflect.DeepEqual(groups, tt.wantGroups) || synced != tt.wantSynced {
+			t.Errorf("idpGroupsOf(%q) = %v, %v; want %v, %v", tt.team, groups, synced, tt.wantGroups, tt.wantSynced)
+		}
+	}
+}
+
+func TestValidateIdPTeam(t *testing.T) {
+	withSync := &types.TargetOrgCapabilities{
+		Teams:          []string{"existing"},
+		IdPGroups:      []string{"eng-platform"},
+		IdPGroupSource: "team_sync",
+	}
+	withoutSync := &types.TargetOrgCapabilities{}
+
+	tests := []struct {
+		name         string
+		teamName     string
+		groups       []string
+		capabilities *types.TargetOrgCapabilities
+		want         types.ValidationStatus
+	}{
+		{"team exists in target", "existing", []string{"eng-platform"}, withSync, types.ValidationReady},
+		{"group available in target", "platform", []string{"eng-platform"}, withSync, types.ValidationSetupNeeded},
+		{"group missing in target", "platform", []string{"eng-data"}, withSync, types.ValidationBlocker},
+		{"target without team sync", "platform", []string{"eng-platform"}, withoutSync, types.ValidationBlocker},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := validateIdPTeam(tt.teamName+" (write)", tt.teamName, tt.groups, tt.capabilities)
+			if result.Status != tt.want {
+				t.Errorf("validateIdPTeam() status = %s, want %s (%s)", result.Status, tt.want, result.Message)
+			}
+		})
+	}
+}
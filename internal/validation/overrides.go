@@ -0,0 +1,221 @@
+package validation
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+// SeverityRule remaps the status of the findings it matches: of a finding ID, kind
+// ("ci.configure_runner") or category ("ci"), of items matching a glob ("gpu-*"), or both
+type SeverityRule struct {
+	Finding string `yaml:"finding,omitempty"` // Finding ID, kind or category ("" for any)
+	Item    string `yaml:"item,omitempty"`    // Glob matching the item, case-insensitive ("" for any)
+	Status  string `yaml:"status"`
+	Reason  string `yaml:"reason,omitempty"`
+}
+
+// String names the rule in the override of a finding, e.g. "ci item gpu-*"
+func (r SeverityRule) String() string {
+	switch {
+	case r.Item == "":
+		return strings.ToLower(r.Finding)
+	case r.Finding == "":
+		return "item " + r.Item
+	}
+	return fmt.Sprintf("%s item %s", strings.ToLower(r.Finding), r.Item)
+}
+
+// matches reports whether the rule covers a finding of a category
+func (r SeverityRule) matches(category string, result types.ValidationResult) bool {
+	if r.Finding != "" && !matchesFinding(r.Finding, category, result) {
+		return false
+	}
+	if r.Item != "" {
+		matched, err := path.Match(strings.ToLower(r.Item), strings.ToLower(result.Item))
+		return err == nil && matched
+	}
+	return true
+}
+
+// severityRules are the severity rules of the current run; the first matching rule applies
+var severityRules []SeverityRule
+
+// SetSeverityRules sets the rules remapping the status of findings, e.g. from --severity-overrides
+// and the config file. Only statuses a check can assign are accepted.
+func SetSeverityRules(rules []SeverityRule) error {
+	severityRules = nil
+	for i, rule := range rules {
+		if strings.TrimSpace(rule.Finding) == "" && strings.TrimSpace(rule.Item) == "" {
+			return fmt.Errorf("severity rule %d matches every finding: set finding or item", i+1)
+		}
+		if _, err := path.Match(strings.ToLower(rule.Item), ""); err != nil {
+			return fmt.Errorf("severity rule %d: invalid item pattern %q: %v", i+1, rule.Item, err)
+		}
+		status, err := ParseOverrideStatus(rule.Status)
+		if err != nil {
+			return fmt.Errorf("severity rule %s: %v", rule, err)
+		}
+		rule.Status = string(status)
+		severityRules = append(severityRules, rule)
+	}
+	return nil
+}
+
+// SetSeverityOverrides sets the status findings get per kind ("ci.configure_runner"), category
+// ("ci") or finding ID (see SeverityRulesFromMap)
+func SetSeverityOverrides(overrides map[string]string) error {
+	return SetSeverityRules(SeverityRulesFromMap(overrides))
+}
+
+// SeverityRulesFromMap turns a map of finding ID, kind or category to status into rules, most
+// specific first: finding IDs, then kinds, then categories
+func SeverityRulesFromMap(overrides map[string]string) []SeverityRule {
+	var rules []SeverityRule
+	for finding, status := range overrides {
+		rules = append(rules, SeverityRule{Finding: finding, Status: status})
+	}
+	specificity := func(finding string) int {
+		if _, hash := SplitFindingID(finding); hash != "" {
+			return 0
+		}
+		if strings.Contains(finding, ".") {
+			return 1
+		}
+		return 2
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		if a, b := specificity(rules[i].Finding), specificity(rules[j].Finding); a != b {
+			return a < b
+		}
+		return rules[i].Finding < rules[j].Finding
+	})
+	return rules
+}
+
+// LoadSeverityRules reads a --severity-overrides file, a list of rules under "rules":
+//
+//	rules:
+//	  - finding: access.create_team
+//	    status: warning
+//	    reason: teams are created after the move
+//	  - finding: ci
+//	    item: "gpu-*"
+//	    status: blocker
+func LoadSeverityRules(filePath string) ([]SeverityRule, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read severity overrides %s: %v", filePath, err)
+	}
+	var file struct {
+		Rules []SeverityRule `yaml:"rules"`
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse severity overrides %s: %v", filePath, err)
+	}
+	return file.Rules, nil
+}
+
+// ParseOverrideStatus returns the status named by a severity override
+func ParseOverrideStatus(status string) (types.ValidationStatus, error) {
+	switch parsed := types.ValidationStatus(strings.ToLower(strings.TrimSpace(status))); parsed {
+	case types.ValidationBlocker, types.ValidationWarning, types.ValidationSetupNeeded,
+		types.ValidationReview, types.ValidationReady:
+		return parsed, nil
+	}
+	return "", fmt.Errorf("invalid status %q: use blocker, warning, setup_needed, review or ready", status)
+}
+
+// excludedChecks holds the finding kinds, categories and IDs (in lower case) whose findings are
+// left out of validation
+var excludedChecks map[string]bool
+
+// SetExcludedChecks leaves the findings of the given kinds, categories or finding IDs out of
+// validation. They change the validation itself, so they are part of the cached inputs.
+func SetExcludedChecks(checks []string) {
+	excludedChecks = make(map[string]bool, len(checks))
+	for _, check := range checks {
+		excludedChecks[strings.ToLower(strings.TrimSpace(check))] = true
+	}
+}
+
+// restoreStatuses gives every finding back the status validation assigned, undoing the
+// suppressions and severity overrides of an earlier run, e.g. in a cached validation
+func restoreStatuses(validation *types.MigrationValidation) {
+	for _, category := range validationCategories(validation) {
+		for i := range category.results {
+			result := &category.results[i]
+			if result.Suppression != nil {
+				result.Status = result.Suppression.Status
+				result.Suppression = nil
+			}
+			if result.Override != nil {
+				result.Status = result.Override.Status
+				result.Override = nil
+			}
+		}
+	}
+}
+
+// excludeChecks removes the findings of excluded kinds, categories and IDs
+func excludeChecks(validation *types.MigrationValidation) {
+	if len(excludedChecks) == 0 {
+		return
+	}
+	for _, category := range []struct {
+		name    string
+		results *[]types.ValidationResult
+	}{
+		{"apps", &validation.AppsIntegrations},
+		{"access", &validation.AccessPermissions},
+		{"ci", &validation.CIDependencies},
+		{"governance", &validation.Governance},
+		{"code", &validation.CodeDependencies},
+		{"security", &validation.SecurityCompliance},
+	} {
+		var kept []types.ValidationResult
+		for _, result := range *category.results {
+			excluded := false
+			for check := range excludedChecks {
+				excluded = excluded || matchesFinding(check, category.name, result)
+			}
+			if !excluded {
+				kept = append(kept, result)
+			}
+		}
+		*category.results = kept
+	}
+}
+
+// applySeverityOverrides sets the status of the findings matching a severity rule, keeping the
+// status validation assigned in Override. The first matching rule applies. Ready findings are
+// never overridden: a kind such as "access.create_team" also covers the teams that exist.
+func applySeverityOverrides(validation *types.MigrationValidation) {
+	if len(severityRules) == 0 {
+		return
+	}
+	for _, category := range validationCategories(validation) {
+		for i := range category.results {
+			result := &category.results[i]
+			if result.Status == types.ValidationReady {
+				continue
+			}
+			for _, rule := range severityRules {
+				if !rule.matches(category.name, *result) {
+					continue
+				}
+				if status := types.ValidationStatus(rule.Status); status != result.Status {
+					result.Override = &types.Override{Status: result.Status, Rule: rule.String(), Reason: rule.Reason}
+					result.Status = status
+				}
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package validation
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+func TestApplySeverityOverrides(t *testing.T) {
+	newValidation := func() *types.MigrationValidation {
+		return &types.MigrationValidation{
+			AppsIntegrations: []types.ValidationResult{{Item: "Legacy App", Status: types.ValidationBlocker}},
+			CIDependencies: []types.ValidationResult{
+				{Item: "NPM_TOKEN", Status: types.ValidationSetupNeeded, Message: "Secret not found in target organization"},
+				{Item: "REGION", Status: types.ValidationReady, Message: "Variable available"},
+			},
+		}
+	}
+	appID := FindingID("apps", newValidation().AppsIntegrations[0])
+
+	tests := []struct {
+		name       string
+		overrides  map[string]string
+		wantApp    types.ValidationStatus
+		wantSecret types.ValidationStatus
+	}{
+		{"no overrides", nil, types.ValidationBlocker, types.ValidationSetupNeeded},
+		{"kind", map[string]string{"apps.custom_app": "warning"}, types.ValidationWarning, types.ValidationSetupNeeded},
+		{"category keeps ready findings", map[string]string{"CI": "blocker"}, types.ValidationBlocker, types.ValidationBlocker},
+		{"ID before kind before category", map[string]string{appID: "review", "apps.custom_app": "warning", "apps": "ready"},
+			types.ValidationReview, types.ValidationSetupNeeded},
+	}
+	defer func() { severityRules = nil }()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := SetSeverityOverrides(tt.overrides); err != nil {
+				t.Fatalf("SetSeverityOverrides() error = %v", err)
+			}
+			validation := newValidation()
+			finishValidation(validation, "acme/web")
+			if got := validation.AppsIntegrations[0].Status; got != tt.wantApp {
+				t.Errorf("app status = %s, want %s", got, tt.wantApp)
+			}
+			if got := validation.CIDependencies[0].Status; got != tt.wantSecret {
+				t.Errorf("secret status = %s, want %s", got, tt.wantSecret)
+			}
+			if got := validation.CIDependencies[1].Status; got != types.ValidationReady {
+				t.Errorf("ready variable status = %s, want ready", got)
+			}
+			if validation.AppsIntegrations[0].ID != appID {
+				t.Errorf("app ID = %s, want %s", validation.AppsIntegrations[0].ID, appID)
+			}
+
+			// Finishing again without overrides restores the statuses validation assigned
+			severityRules = nil
+			finishValidation(validation, "acme/web")
+			if validation.AppsIntegrations[0].Status != types.ValidationBlocker || validation.AppsIntegrations[0].Override != nil {
+				t.Errorf("app after removing the overrides = %+v", validation.AppsIntegrations[0])
+			}
+		})
+	}
+
+	if err := SetSeverityOverrides(map[string]string{"ci": "suppressed"}); err == nil {
+		t.Errorf("SetSeverityOverrides() accepted the status suppressed")
+	}
+}
+
+func TestSeverityRules(t *testing.T) {
+	newValidation := func() *types.MigrationValidation {
+		return &types.MigrationValidation{
+			AccessPermissions: []types.ValidationResult{
+				{Item: "platform (push)", Status: types.ValidationBlocker, Message: "Team does not exist in target organization"},
+				{Item: "security (admin)", Status: types.ValidationBlocker, Message: "Team does not exist in target organization"},
+			},
+			CIDependencies: []types.ValidationResult{
+				{Item: "gpu-a100", Status: types.ValidationSetupNeeded, Message: "Self-hosted runner not found"},
+				{Item: "linux-large", Status: types.ValidationSetupNeeded, Message: "Self-hosted runner not found"},
+			},
+		}
+	}
+	rules := []SeverityRule{
+		{Finding: "access", Item: "security*", Status: "blocker", Reason: "security owns the repository"},
+		{Finding: "access.create_team", Status: "Warning"},
+		{Item: "GPU-*", Status: "blocker"},
+	}
+	defer func() { severityRules = nil }()
+
+	if err := SetSeverityRules(rules); err != nil {
+		t.Fatalf("SetSeverityRules() error = %v", err)
+	}
+	validation := newValidation()
+	finishValidation(validation, "acme/web")
+
+	want := []types.ValidationStatus{types.ValidationWarning, types.ValidationBlocker, types.ValidationBlocker, types.ValidationSetupNeeded}
+	got := []types.ValidationStatus{
+		validation.AccessPermissions[0].Status, validation.AccessPermissions[1].Status,
+		validation.CIDependencies[0].Status, validation.CIDependencies[1].Status,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("statuses = %v, want %v", got, want)
+	}
+	if override := validation.AccessPermissions[0].Override; override == nil || override.Rule != "access.create_team" || override.Status != types.ValidationBlocker {
+		t.Errorf("override of the platform team = %+v", override)
+	}
+	// The security team matched a rule keeping its status, so it has no override
+	if validation.AccessPermissions[1].Override != nil {
+		t.Errorf("override of the security team = %+v", validation.AccessPermissions[1].Override)
+	}
+	if validation.CIDependencies[0].Override.Rule != "item GPU-*" {
+		t.Errorf("override rule of the GPU runner = %q", validation.CIDependencies[0].Override.Rule)
+	}
+
+	for _, invalid := range [][]SeverityRule{
+		{{Status: "blocker"}},
+		{{Item: "[gpu", Status: "blocker"}},
+		{{Finding: "ci", Status: "fatal"}},
+	} {
+		if err := SetSeverityRules(invalid); err == nil {
+			t.Errorf("SetSeverityRules(%+v) accepted an invalid rule", invalid)
+		}
+	}
+}
+
+func TestSeverityRulesFromMap(t *testing.T) {
+	got := SeverityRulesFromMap(map[string]string{
+		"ci":                           "blocker",
+		"ci.configure_runner":          "warning",
+		"ci.configure_runner-3f9a1c2e": "review",
+		"access":                       "warning",
+	})
+	var order []string
+	for _, rule := range got {
+		order = append(order, rule.Finding)
+	}
+	want := []string{"ci.configure_runner-3f9a1c2e", "ci.configure_runner", "access", "ci"}
+	if !reflect.DeepEqual(order, want) {
+		t.Errorf("SeverityRulesFromMap() order = %v, want %v", order, want)
+	}
+}
+
+func TestLoadSeverityRules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "overrides.yaml")
+	content := "rules:\n  - finding: access.create_team\n    status: warning\n    reason: created after the move\n  - item: \"gpu-*\"\n    status: blocker\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadSeverityRules(path)
+	if err != nil {
+		t.Fatalf("LoadSeverityRules() error = %v", err)
+	}
+	want := []SeverityRule{
+		{Finding: "access.create_team", Status: "warning", Reason: "created after the move"},
+		{Item: "gpu-*", Status: "blocker"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("LoadSeverityRules() = %+v, want %+v", got, want)
+	}
+}
+
+func TestExcludeChecks(t *testing.T) {
+	newValidation := func() *types.MigrationValidation {
+		return &types.MigrationValidation{
+			AppsIntegrations: []types.ValidationResult{{Item: "Legacy App", Status: types.ValidationBlocker}},
+			CIDependencies: []types.ValidationResult{
+				{Item: "NPM_TOKEN", Status: types.ValidationSetupNeeded, Message: "Secret not found in target organization"},
+				{Item: "REGION", Status: types.ValidationReady, Message: "Variable available"},
+			},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		checks        []string
+		wantApps      int
+		wantCI        int
+		wantReadiness types.ValidationStatus
+	}{
+		{"nothing excluded", nil, 1, 2, types.ValidationBlocker},
+		{"category", []string{"apps"}, 0, 2, types.ValidationSetupNeeded},
+		{"kind", []string{"apps.custom_app", "ci.create_secret"}, 0, 1, types.ValidationReady},
+	}
+	defer func() { excludedChecks = nil }()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetExcludedChecks(tt.checks)
+			validation := newValidation()
+			finishValidation(validation, "acme/web")
+			if len(validation.AppsIntegrations) != tt.wantApps || len(validation.CIDependencies) != tt.wantCI {
+				t.Errorf("kept %d apps and %d CI findings, want %d and %d",
+					len(validation.AppsIntegrations), len(validation.CIDependencies), tt.wantApps, tt.wantCI)
+			}
+			if validation.OverallReadiness != tt.wantReadiness {
+				t.Errorf("readiness = %s, want %s", validation.OverallReadiness, tt.wantReadiness)
+			}
+		})
+	}
+}
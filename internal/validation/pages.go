@@ -0,0 +1,41 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+// validatePages reports the repository's GitHub Pages site. With a custom domain the site keeps
+// its URL once DNS points at the target organization; otherwise it moves to the target's
+// github.io domain without a redirect, and an organization site stops being one.
+func validatePages(sites []string, capabilities *types.TargetOrgCapabilities) []types.ValidationResult {
+	var results []types.ValidationResult
+	targetDomain := strings.ToLower(capabilities.Organization) + ".github.io"
+	for _, site := range sites {
+		details := site
+		if idx := strings.Index(site, " ("); idx != -1 {
+			details = site[idx:]
+		}
+		result := types.ValidationResult{Item: site}
+		switch {
+		case strings.Contains(details, "custom domain: "):
+			domain := strings.SplitN(strings.SplitN(details, "custom domain: ", 2)[1], ",", 2)[0]
+			domain = strings.TrimSuffix(domain, ")")
+			result.Status = types.ValidationSetupNeeded
+			result.Message = fmt.Sprintf("Pages custom domain %s has to point at the target organization", domain)
+			result.Recommendation = fmt.Sprintf("Point the DNS record of %s at %s, verify the domain in %s if the source organization verified it, and check the site is served after the move (--migrate-pages restores its configuration)", domain, targetDomain, capabilities.Organization)
+		case strings.Contains(details, "organization site"):
+			result.Status = types.ValidationBlocker
+			result.Message = "Pages organization site stops being served from the source organization's github.io domain"
+			result.Recommendation = fmt.Sprintf("Rename the repository to %s after the move if the target has no organization site yet, or give the site a custom domain before the move", targetDomain)
+		default:
+			result.Status = types.ValidationSetupNeeded
+			result.Message = fmt.Sprintf("Pages site moves to %s and its old URL is not redirected", targetDomain)
+			result.Recommendation = "Update links to the site, or add a custom domain to keep its URL, and check the site is served after the move (--migrate-pages restores its configuration)"
+		}
+		results = append(results, result)
+	}
+	return results
+}
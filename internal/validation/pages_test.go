@@ -0,0 +1,33 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+func TestValidatePages(t *testing.T) {
+	capabilities := &types.TargetOrgCapabilities{Organization: "Target"}
+
+	tests := []struct {
+		site        string
+		want        types.ValidationStatus
+		wantMessage string
+	}{
+		{"GitHub Pages: https://docs.acme.com/ (source: branch main /docs, custom domain: docs.acme.com, HTTPS enforced)", types.ValidationSetupNeeded, "Pages custom domain docs.acme.com has to point at the target organization"},
+		{"GitHub Pages: https://acme.github.io/ (source: workflow, organization site, HTTPS enforced)", types.ValidationBlocker, "Pages organization site stops being served from the source organization's github.io domain"},
+		{"GitHub Pages: https://www.acme.com/ (source: workflow, custom domain: www.acme.com, organization site, HTTPS not enforced)", types.ValidationSetupNeeded, "Pages custom domain www.acme.com has to point at the target organization"},
+		{"GitHub Pages: https://acme.github.io/web/ (source: branch gh-pages /, HTTPS enforced)", types.ValidationSetupNeeded, "Pages site moves to target.github.io and its old URL is not redirected"},
+	}
+
+	for _, tt := range tests {
+		results := validatePages([]string{tt.site}, capabilities)
+		if len(results) != 1 || results[0].Status != tt.want || results[0].Message != tt.wantMessage {
+			t.Errorf("validatePages(%q) = %+v, want status %s, message %q", tt.site, results, tt.want, tt.wantMessage)
+			continue
+		}
+		if kind := classifyEffortItem("apps", results[0]); kind != "pages_setup" {
+			t.Errorf("classifyEffortItem(%q) = %s, want pages_setup", tt.site, kind)
+		}
+	}
+}
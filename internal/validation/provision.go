@@ -0,0 +1,97 @@
+package validation
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+// Kinds of target organization items --provision creates
+const (
+	ProvisionTeam     = "team"
+	ProvisionSecret   = "secret"
+	ProvisionVariable = "variable"
+)
+
+// ProvisionItem is a team, organization secret or organization variable missing in the target
+type ProvisionItem struct {
+	Kind string
+	Name string
+}
+
+// ProvisionableItems lists the missing teams (including CODEOWNERS teams), organization secrets
+// and organization variables of a validation that --provision can create, deduplicated and
+// sorted by kind and name. Suppressed findings are left out.
+func ProvisionableItems(validation *types.MigrationValidation) []ProvisionItem {
+	if validation == nil {
+		return nil
+	}
+	seen := make(map[ProvisionItem]bool)
+	var items []ProvisionItem
+	add := func(kind, name string) {
+		item := ProvisionItem{Kind: kind, Name: name}
+		key := ProvisionItem{Kind: kind, Name: strings.ToLower(name)}
+		if name != "" && !seen[key] {
+			seen[key] = true
+			items = append(items, item)
+		}
+	}
+
+	for _, result := range validation.AccessPermissions {
+		if result.Status != types.ValidationBlocker && result.Status != types.ValidationSetupNeeded {
+			continue
+		}
+		switch result.Message {
+		case "Team does not exist in target organization":
+			add(ProvisionTeam, extractTeamName(result.Item))
+		case "CODEOWNERS team does not exist in target organization":
+			// "Team: @org/slug" names the team by its slug
+			if _, slug, ok := strings.Cut(strings.TrimPrefix(result.Item, "Team: @"), "/"); ok {
+				add(ProvisionTeam, slug)
+			}
+		}
+	}
+	for _, result := range validation.CIDependencies {
+		if result.Status != types.ValidationSetupNeeded {
+			continue
+		}
+		switch result.Message {
+		case "Secret needs to be created in target organization":
+			add(ProvisionSecret, extractSecretName(result.Item))
+		case "Variable needs to be created in target organization":
+			add(ProvisionVariable, extractVariableName(result.Item))
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].Kind != items[j].Kind {
+			return items[i].Kind < items[j].Kind
+		}
+		return strings.ToLower(items[i].Name) < strings.ToLower(items[j].Name)
+	})
+	return items
+}
+
+// WithProvisioned returns a copy of the capabilities that includes the provisioned items, so a
+// repository can be validated again without rescanning the target organization
+func WithProvisioned(capabilities *types.TargetOrgCapabilities, items []ProvisionItem) *types.TargetOrgCapabilities {
+	if capabilities == nil || len(items) == 0 {
+		return capabilities
+	}
+	provisioned := *capabilities
+	provisioned.Teams = append([]string(nil), capabilities.Teams...)
+	provisioned.Secrets = append([]string(nil), capabilities.Secrets...)
+	provisioned.Variables = append([]string(nil), capabilities.Variables...)
+	for _, item := range items {
+		switch item.Kind {
+		case ProvisionTeam:
+			provisioned.Teams = append(provisioned.Teams, item.Name)
+		case ProvisionSecret:
+			provisioned.Secrets = append(provisioned.Secrets, item.Name)
+		case ProvisionVariable:
+			provisioned.Variables = append(provisioned.Variables, item.Name)
+		}
+	}
+	return &provisioned
+}
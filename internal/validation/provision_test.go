@@ -0,0 +1,56 @@
+package validation
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+func TestProvisionableItems(t *testing.T) {
+	deps := &types.OrganizationalDependencies{
+		Repository: "acme/web",
+		AccessPermissions: types.AccessPermissions{
+			Teams:                  []string{"Platform (push)", "Owners (admin)"},
+			CodeownersRequirements: []string{"Team: @acme/platform", "Team: @acme/docs", "User: @alice"},
+		},
+		ActionsCIDependencies: types.ActionsCIDependencies{
+			OrganizationSecrets:   []string{"NPM_TOKEN (in release.yml)", "NPM_TOKEN (in ci.yml)", "SONAR_TOKEN"},
+			OrganizationVariables: []string{"REGION (in deploy.yml)", "STAGE"},
+		},
+	}
+	capabilities := &types.TargetOrgCapabilities{
+		Teams:     []string{"Owners"},
+		Secrets:   []string{"SONAR_TOKEN"},
+		Variables: []string{"stage"},
+	}
+
+	tests := []struct {
+		name         string
+		capabilities *types.TargetOrgCapabilities
+		want         []ProvisionItem
+	}{
+		{"missing items", capabilities, []ProvisionItem{
+			{ProvisionSecret, "NPM_TOKEN"},
+			{ProvisionTeam, "docs"},
+			{ProvisionTeam, "Platform"},
+			{ProvisionVariable, "REGION"},
+		}},
+		{"everything present", WithProvisioned(capabilities, []ProvisionItem{
+			{ProvisionSecret, "NPM_TOKEN"},
+			{ProvisionTeam, "docs"},
+			{ProvisionTeam, "Platform"},
+			{ProvisionVariable, "REGION"},
+		}), nil},
+	}
+	for _, tt := range tests {
+		validation := ValidateAgainstTarget(deps, tt.capabilities, false)
+		if got := ProvisionableItems(validation); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: ProvisionableItems() = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+
+	if len(capabilities.Teams) != 1 || len(capabilities.Secrets) != 1 || len(capabilities.Variables) != 1 {
+		t.Errorf("WithProvisioned() modified the scanned capabilities: %+v", capabilities)
+	}
+}
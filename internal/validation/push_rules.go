@@ -0,0 +1,139 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+// pushRestrictions is the combined effect of push rulesets: the smallest limits and every
+// blocked path and extension
+type pushRestrictions struct {
+	maxFileSizeMB     int
+	maxFilePathLength int
+	paths             []string
+	extensions        []string
+}
+
+func combinePushRulesets(rulesets []types.PushRuleset) pushRestrictions {
+	var combined pushRestrictions
+	for _, ruleset := range rulesets {
+		combined.maxFileSizeMB = smallestLimit(combined.maxFileSizeMB, ruleset.MaxFileSizeMB)
+		combined.maxFilePathLength = smallestLimit(combined.maxFilePathLength, ruleset.MaxFilePathLength)
+		combined.paths = append(combined.paths, ruleset.RestrictedPaths...)
+		combined.extensions = append(combined.extensions, ruleset.RestrictedExtensions...)
+	}
+	return combined
+}
+
+// smallestLimit returns the stricter of two limits, where 0 means no limit
+func smallestLimit(a, b int) int {
+	if a == 0 || (b != 0 && b < a) {
+		return b
+	}
+	return a
+}
+
+// notEnforcedBy describes the restrictions of r that other does not enforce at least as strictly
+func (r pushRestrictions) notEnforcedBy(other pushRestrictions) []string {
+	var missing []string
+	if r.maxFileSizeMB != 0 && (other.maxFileSizeMB == 0 || other.maxFileSizeMB > r.maxFileSizeMB) {
+		missing = append(missing, fmt.Sprintf("max file size %d MB", r.maxFileSizeMB))
+	}
+	if r.maxFilePathLength != 0 && (other.maxFilePathLength == 0 || other.maxFilePathLength > r.maxFilePathLength) {
+		missing = append(missing, fmt.Sprintf("max file path length %d", r.maxFilePathLength))
+	}
+	for _, path := range r.paths {
+		if !containsName(other.paths, path) && !containsName(missing, "blocked path "+path) {
+			missing = append(missing, "blocked path "+path)
+		}
+	}
+	for _, extension := range r.extensions {
+		if !containsName(other.extensions, extension) && !containsName(missing, "blocked extension "+extension) {
+			missing = append(missing, "blocked extension "+extension)
+		}
+	}
+	return missing
+}
+
+// validatePushRules compares the push rulesets applying to the repository with those of the
+// target organization, so that file size and path restrictions are neither lost nor newly
+// imposed without warning. Repository push rulesets move with the repository.
+func validatePushRules(rulesets []types.PushRuleset, capabilities *types.TargetOrgCapabilities) []types.ValidationResult {
+	var results []types.ValidationResult
+
+	var repositoryRulesets, organizationRulesets []types.PushRuleset
+	for _, ruleset := range rulesets {
+		if ruleset.Source == "repository" {
+			repositoryRulesets = append(repositoryRulesets, ruleset)
+			results = append(results, types.ValidationResult{
+				Item:    fmt.Sprintf("Push ruleset: %s", ruleset.Name),
+				Status:  types.ValidationReady,
+				Message: "Push ruleset is defined on the repository and moves with it",
+			})
+		} else {
+			organizationRulesets = append(organizationRulesets, ruleset)
+		}
+	}
+
+	var targetAll, targetSelected []types.PushRuleset
+	for _, ruleset := range capabilities.PushRulesets {
+		if ruleset.SelectedRepositories {
+			targetSelected = append(targetSelected, ruleset)
+		} else {
+			targetAll = append(targetAll, ruleset)
+		}
+	}
+	certain := combinePushRulesets(append(append([]types.PushRuleset{}, repositoryRulesets...), targetAll...))
+	possible := combinePushRulesets(append(append(append([]types.PushRuleset{}, repositoryRulesets...), targetAll...), targetSelected...))
+
+	// Organization push rulesets stay behind; the target must enforce the same restrictions
+	for _, ruleset := range organizationRulesets {
+		restrictions := combinePushRulesets([]types.PushRuleset{ruleset})
+		item := fmt.Sprintf("Push ruleset: %s (%s)", ruleset.Name, strings.Join(restrictions.notEnforcedBy(pushRestrictions{}), ", "))
+		switch lost := restrictions.notEnforcedBy(certain); {
+		case len(lost) == 0:
+			results = append(results, types.ValidationResult{
+				Item:    item,
+				Status:  types.ValidationReady,
+				Message: fmt.Sprintf("Push restrictions of the source organization are also enforced in %s", capabilities.Organization),
+			})
+		case len(restrictions.notEnforcedBy(possible)) == 0:
+			results = append(results, types.ValidationResult{
+				Item:           item,
+				Status:         types.ValidationReview,
+				Message:        fmt.Sprintf("Push restrictions are enforced in %s only by rulesets for selected repositories", capabilities.Organization),
+				Recommendation: "Check that the target push rulesets cover the repository",
+			})
+		default:
+			results = append(results, types.ValidationResult{
+				Item:           item,
+				Status:         types.ValidationSetupNeeded,
+				Message:        fmt.Sprintf("Push ruleset of the source organization does not move; not enforced in %s: %s", capabilities.Organization, strings.Join(lost, ", ")),
+				Recommendation: "Add a push ruleset with these restrictions in the target organization, or on the repository before the move",
+			})
+		}
+	}
+
+	// Restrictions the target adds would reject pushes that pass today
+	imposed := combinePushRulesets(capabilities.PushRulesets).notEnforcedBy(combinePushRulesets(rulesets))
+	if len(imposed) > 0 {
+		var names []string
+		for _, ruleset := range capabilities.PushRulesets {
+			name := ruleset.Name
+			if ruleset.SelectedRepositories {
+				name += " (selected repositories)"
+			}
+			names = append(names, name)
+		}
+		results = append(results, types.ValidationResult{
+			Item:           fmt.Sprintf("Target push rulesets: %s", strings.Join(names, ", ")),
+			Status:         types.ValidationReview,
+			Message:        fmt.Sprintf("Target push rulesets add restrictions the source does not enforce: %s", strings.Join(imposed, ", ")),
+			Recommendation: "Check that contributors and automation do not push such files, or their pushes will be rejected after the move",
+		})
+	}
+
+	return results
+}
@@ -0,0 +1,58 @@
+package validation
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+func TestValidatePushRules(t *testing.T) {
+	orgRuleset := types.PushRuleset{Name: "large files", Source: "organization", MaxFileSizeMB: 50, RestrictedExtensions: []string{".exe"}}
+	repoRuleset := types.PushRuleset{Name: "no secrets", Source: "repository", RestrictedPaths: []string{"secrets/**"}}
+
+	tests := []struct {
+		name     string
+		rulesets []types.PushRuleset
+		target   []types.PushRuleset
+		want     []types.ValidationStatus
+	}{
+		{"nothing", nil, nil, nil},
+		{"repository ruleset moves", []types.PushRuleset{repoRuleset}, nil, []types.ValidationStatus{types.ValidationReady}},
+		{"org ruleset lost", []types.PushRuleset{orgRuleset}, nil, []types.ValidationStatus{types.ValidationSetupNeeded}},
+		{"org ruleset partly enforced", []types.PushRuleset{orgRuleset},
+			[]types.PushRuleset{{Name: "size", MaxFileSizeMB: 100, RestrictedExtensions: []string{".EXE"}}},
+			[]types.ValidationStatus{types.ValidationSetupNeeded}},
+		{"org ruleset enforced more strictly", []types.PushRuleset{orgRuleset},
+			[]types.PushRuleset{{Name: "size", MaxFileSizeMB: 50, RestrictedExtensions: []string{".EXE"}}},
+			[]types.ValidationStatus{types.ValidationReady}},
+		{"org ruleset enforced for selected repositories", []types.PushRuleset{orgRuleset},
+			[]types.PushRuleset{{Name: "size", MaxFileSizeMB: 50, RestrictedExtensions: []string{".exe"}, SelectedRepositories: true}},
+			[]types.ValidationStatus{types.ValidationReview}},
+		{"target adds restrictions", []types.PushRuleset{repoRuleset},
+			[]types.PushRuleset{{Name: "size", MaxFileSizeMB: 10}},
+			[]types.ValidationStatus{types.ValidationReady, types.ValidationReview}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			capabilities := &types.TargetOrgCapabilities{Organization: "target", PushRulesets: tt.target}
+			var got []types.ValidationStatus
+			for _, result := range validatePushRules(tt.rulesets, capabilities) {
+				got = append(got, result.Status)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("validatePushRules() statuses = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPushRestrictionsNotEnforcedBy(t *testing.T) {
+	source := pushRestrictions{maxFileSizeMB: 50, maxFilePathLength: 200, paths: []string{"secrets/**"}, extensions: []string{".exe", ".dll"}}
+	target := pushRestrictions{maxFileSizeMB: 100, maxFilePathLength: 150, extensions: []string{".EXE"}}
+	want := []string{"max file size 50 MB", "blocked path secrets/**", "blocked extension .dll"}
+	if got := source.notEnforcedBy(target); !reflect.DeepEqual(got, want) {
+		t.Errorf("notEnforcedBy() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,60 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+// validateRepositoryDefaults reports, for information, what the target organization adds to or
+// changes on the repository when it arrives: the default community health files and templates
+// of its .github repository, which replace those inherited from the source organization and fill
+// in those the repository lacks, and the workflows its rulesets require. The repository's own
+// files are kept. None of it affects readiness.
+func validateRepositoryDefaults(healthFiles []string, capabilities *types.TargetOrgCapabilities) []types.ValidationResult {
+	var results []types.ValidationResult
+	for _, kind := range capabilities.CommunityHealthFiles {
+		message := fmt.Sprintf("The target org default %s is added to the repository on arrival", kind)
+		switch communityHealthSource(healthFiles, kind) {
+		case "repository":
+			continue // The repository's own file takes precedence
+		case "organization":
+			message = fmt.Sprintf("The target org default %s replaces the source org default on arrival", kind)
+		}
+		results = append(results, types.ValidationResult{
+			Item:           fmt.Sprintf("Target default: %s (from %s/.github)", kind, capabilities.Organization),
+			Status:         types.ValidationReady,
+			Message:        message,
+			Recommendation: fmt.Sprintf("Add a %s of the repository's own to keep a different one", kind),
+		})
+	}
+
+	for _, ruleset := range capabilities.RequiredWorkflowRulesets {
+		message := "Workflows required by this target org ruleset run on the repository's pull requests after the move"
+		if strings.HasSuffix(ruleset, "(selected repositories)") {
+			message = "Workflows required by this target org ruleset run on the pull requests of the repositories it selects, which may include this one"
+		}
+		results = append(results, types.ValidationResult{
+			Item:           fmt.Sprintf("Target ruleset: %s", ruleset),
+			Status:         types.ValidationReady,
+			Message:        message,
+			Recommendation: "Check the required workflows pass for the repository before the move, or pull requests will be blocked",
+		})
+	}
+	return results
+}
+
+// communityHealthSource returns where the repository's community health file of a kind comes
+// from: "repository", "organization" (a source org default) or "" when it has none
+func communityHealthSource(healthFiles []string, kind string) string {
+	for _, file := range healthFiles {
+		switch {
+		case file == kind+" (repository)":
+			return "repository"
+		case strings.HasPrefix(file, kind+" (default of "):
+			return "organization"
+		}
+	}
+	return ""
+}
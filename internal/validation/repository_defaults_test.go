@@ -0,0 +1,35 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+func TestValidateRepositoryDefaults(t *testing.T) {
+	capabilities := &types.TargetOrgCapabilities{
+		Organization:             "target",
+		CommunityHealthFiles:     []string{"CONTRIBUTING", "ISSUE_TEMPLATE", "SECURITY"},
+		RequiredWorkflowRulesets: []string{"CI (workflows: .github/workflows/lint.yml) (selected repositories)"},
+	}
+	healthFiles := []string{"CONTRIBUTING (repository)", "SECURITY (default of acme/.github)"}
+
+	want := []struct {
+		item    string
+		message string
+	}{
+		{"Target default: ISSUE_TEMPLATE (from target/.github)", "The target org default ISSUE_TEMPLATE is added to the repository on arrival"},
+		{"Target default: SECURITY (from target/.github)", "The target org default SECURITY replaces the source org default on arrival"},
+		{"Target ruleset: CI (workflows: .github/workflows/lint.yml) (selected repositories)", "Workflows required by this target org ruleset run on the pull requests of the repositories it selects, which may include this one"},
+	}
+
+	results := validateRepositoryDefaults(healthFiles, capabilities)
+	if len(results) != len(want) {
+		t.Fatalf("validateRepositoryDefaults() = %+v, want %d results", results, len(want))
+	}
+	for i, result := range results {
+		if result.Item != want[i].item || result.Message != want[i].message || result.Status != types.ValidationReady {
+			t.Errorf("result %d = %+v, want item %q, message %q, status ready", i, result, want[i].item, want[i].message)
+		}
+	}
+}
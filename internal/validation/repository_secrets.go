@@ -0,0 +1,30 @@
+package validation
+
+import (
+	"fmt"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+// validateRepositorySecretsAndVariables reports the secrets and variables defined on the
+// repository itself. Variable values can be read and are recreated after the move when the
+// destination lacks them; secret values cannot, so a missing secret has to be set again.
+func validateRepositorySecretsAndVariables(ci types.ActionsCIDependencies) []types.ValidationResult {
+	var results []types.ValidationResult
+	for _, secret := range ci.RepositorySecrets {
+		results = append(results, types.ValidationResult{
+			Item:           fmt.Sprintf("%s (repository secret)", secret),
+			Status:         types.ValidationReview,
+			Message:        "Repository secret values cannot be read or copied; the move is checked for missing secrets",
+			Recommendation: fmt.Sprintf("Keep the value of '%s' at hand: a secret missing after the move is reported, and --prompt-secrets asks for its value", secret),
+		})
+	}
+	for _, variable := range ci.RepositoryVariables {
+		results = append(results, types.ValidationResult{
+			Item:    fmt.Sprintf("%s (repository variable)", variable),
+			Status:  types.ValidationReady,
+			Message: "Repository variable is recreated with its value if the move drops it",
+		})
+	}
+	return results
+}
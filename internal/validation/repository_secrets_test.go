@@ -0,0 +1,35 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+func TestValidateRepositorySecretsAndVariables(t *testing.T) {
+	ci := types.ActionsCIDependencies{
+		RepositorySecrets:   []string{"DEPLOY_TOKEN"},
+		RepositoryVariables: []string{"REGION"},
+	}
+	results := validateRepositorySecretsAndVariables(ci)
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2: %+v", len(results), results)
+	}
+
+	tests := []struct {
+		item   string
+		status types.ValidationStatus
+		effort string
+	}{
+		{"DEPLOY_TOKEN (repository secret)", types.ValidationReview, "create_secret"},
+		{"REGION (repository variable)", types.ValidationReady, "create_variable"},
+	}
+	for i, tt := range tests {
+		if results[i].Item != tt.item || results[i].Status != tt.status {
+			t.Errorf("result %d = %s (%s), want %s (%s)", i, results[i].Item, results[i].Status, tt.item, tt.status)
+		}
+		if got := classifyEffortItem("ci", results[i]); got != tt.effort {
+			t.Errorf("classifyEffortItem(%s) = %s, want %s", tt.item, got, tt.effort)
+		}
+	}
+}
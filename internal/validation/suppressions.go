@@ -0,0 +1,140 @@
+package validation
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+// DefaultIgnoreFile is read when --ignore-file is not given and the file exists
+const DefaultIgnoreFile = ".repo-transfer-ignore"
+
+// Suppression is one entry of the ignore file
+type Suppression struct {
+	Finding    string    // Finding ID, its 8-character hash, or a kind or category suppressing every finding of it
+	Repository string    // Only suppress the finding in this owner/repo ("" for every repository)
+	Expires    time.Time // Last day the entry applies (zero: no expiry)
+	Reason     string
+	Line       int
+}
+
+// matches reports whether the entry suppresses a finding of a category in a repository
+func (s Suppression) matches(category string, result types.ValidationResult, repository string) bool {
+	if s.Repository != "" && !strings.EqualFold(s.Repository, repository) {
+		return false
+	}
+	return matchesFinding(s.Finding, category, result)
+}
+
+// suppressions holds the ignore file entries of the current run that have not expired
+var suppressions []Suppression
+
+// LoadSuppressions reads the ignore file at path, or DefaultIgnoreFile when path is empty and it
+// exists. Each line suppresses a finding:
+//
+//	<finding-id> [expires=YYYY-MM-DD] [repo=owner/repo] [# reason]
+//
+// Expired entries are reported and not applied, so an accepted risk comes back for review.
+func LoadSuppressions(path string) error {
+	suppressions = nil
+	if path == "" {
+		if _, err := os.Stat(DefaultIgnoreFile); err != nil {
+			return nil
+		}
+		path = DefaultIgnoreFile
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to read ignore file %s: %v", path, err)
+	}
+	defer file.Close()
+
+	entries, err := ParseSuppressions(bufio.NewScanner(file), path)
+	if err != nil {
+		return err
+	}
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	for _, entry := range entries {
+		if !entry.Expires.IsZero() && entry.Expires.Before(today) {
+			fmt.Fprintf(os.Stderr, "⚠️  Warning: %s:%d: suppression of %s expired on %s and is no longer applied\n",
+				path, entry.Line, entry.Finding, entry.Expires.Format("2006-01-02"))
+			continue
+		}
+		suppressions = append(suppressions, entry)
+	}
+	return nil
+}
+
+// ParseSuppressions parses the lines of an ignore file; blank lines and lines starting with #
+// are skipped
+func ParseSuppressions(scanner *bufio.Scanner, path string) ([]Suppression, error) {
+	var entries []Suppression
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		entry := Suppression{Line: line}
+		if index := strings.Index(text, "#"); index >= 0 {
+			entry.Reason = strings.TrimSpace(text[index+1:])
+			text = text[:index]
+		}
+		fields := strings.Fields(text)
+		entry.Finding = fields[0]
+		for _, field := range fields[1:] {
+			key, value, ok := strings.Cut(field, "=")
+			switch {
+			case ok && key == "expires":
+				expires, err := time.Parse("2006-01-02", value)
+				if err != nil {
+					return nil, fmt.Errorf("%s:%d: invalid expiry date '%s' (use YYYY-MM-DD)", path, line, value)
+				}
+				entry.Expires = expires
+			case ok && key == "repo":
+				if len(strings.Split(value, "/")) != 2 {
+					return nil, fmt.Errorf("%s:%d: repository '%s' must be in format 'owner/repo'", path, line, value)
+				}
+				entry.Repository = value
+			default:
+				return nil, fmt.Errorf("%s:%d: unknown field '%s' (expected expires=YYYY-MM-DD or repo=owner/repo)", path, line, field)
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// applySuppressions sets the status of the findings matching an ignore file entry to suppressed,
+// keeping their status in Suppression. Findings suppressed earlier, e.g. in a cached validation,
+// get their status back first, so the current ignore file alone decides.
+func applySuppressions(validation *types.MigrationValidation, repository string) {
+	for _, category := range validationCategories(validation) {
+		for i := range category.results {
+			result := &category.results[i]
+			if result.Suppression != nil {
+				result.Status = result.Suppression.Status
+				result.Suppression = nil
+			}
+			if result.Status == types.ValidationReady {
+				continue
+			}
+			for _, entry := range suppressions {
+				if !entry.matches(category.name, *result, repository) {
+					continue
+				}
+				result.Suppression = &types.Suppression{Status: result.Status, Reason: entry.Reason}
+				if !entry.Expires.IsZero() {
+					result.Suppression.Expires = entry.Expires.Format("2006-01-02")
+				}
+				result.Status = types.ValidationSuppressed
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package validation
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+func TestParseSuppressions(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []Suppression
+		wantErr string
+	}{
+		{"comments and blank lines", "# accepted risks\n\n", nil, ""},
+		{"full entry", "ci.create_secret-3f9a1c2e expires=2026-12-31 repo=acme/web # vault provisions it\n",
+			[]Suppression{{Finding: "ci.create_secret-3f9a1c2e", Repository: "acme/web", Expires: time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC), Reason: "vault provisions it", Line: 1}}, ""},
+		{"kind", "\nci.pin_update\n", []Suppression{{Finding: "ci.pin_update", Line: 2}}, ""},
+		{"bad date", "3f9a1c2e expires=31.12.2026\n", nil, "invalid expiry date"},
+		{"bad repository", "3f9a1c2e repo=web\n", nil, "must be in format"},
+		{"unknown field", "3f9a1c2e until=2026-12-31\n", nil, "unknown field"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSuppressions(bufio.NewScanner(strings.NewReader(tt.content)), "ignore")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseSuppressions() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseSuppressions() error = %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ParseSuppressions() = %+v, want %+v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("ParseSuppressions()[%d] = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestLoadSuppressionsSkipsExpired(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ignore")
+	content := "ci.pin_update expires=2000-01-01\ncode.doc_url_rewrite expires=2999-01-01\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { suppressions = nil }()
+
+	if err := LoadSuppressions(path); err != nil {
+		t.Fatalf("LoadSuppressions() error = %v", err)
+	}
+	if len(suppressions) != 1 || suppressions[0].Finding != "code.doc_url_rewrite" {
+		t.Errorf("LoadSuppressions() kept %+v, want only the unexpired entry", suppressions)
+	}
+}
+
+func TestApplySuppressions(t *testing.T) {
+	newValidation := func() *types.MigrationValidation {
+		return &types.MigrationValidation{
+			AppsIntegrations: []types.ValidationResult{{Item: "Legacy App", Status: types.ValidationBlocker}},
+			CIDependencies: []types.ValidationResult{
+				{Item: "NPM_TOKEN", Status: types.ValidationSetupNeeded, Message: "Secret not found in target organization"},
+				{Item: "REGION", Status: types.ValidationReady, Message: "Variable available"},
+			},
+		}
+	}
+	appID := FindingID("apps", newValidation().AppsIntegrations[0])
+	_, appHash := SplitFindingID(appID)
+
+	tests := []struct {
+		name          string
+		entries       []Suppression
+		repository    string
+		wantSummary   types.ValidationSummary
+		wantReadiness types.ValidationStatus
+	}{
+		{"nothing suppressed", nil, "acme/web",
+			types.ValidationSummary{Ready: 1, SetupNeeded: 1, Blockers: 1, Total: 3}, types.ValidationBlocker},
+		{"blocker suppressed by ID", []Suppression{{Finding: appID, Reason: "replaced after the move"}}, "acme/web",
+			types.ValidationSummary{Ready: 1, SetupNeeded: 1, Suppressed: 1, Total: 2}, types.ValidationSetupNeeded},
+		{"suppressed by hash and kind", []Suppression{{Finding: appHash}, {Finding: "CI.Create_Secret"}}, "acme/web",
+			types.ValidationSummary{Ready: 1, Suppressed: 2, Total: 1}, types.ValidationReady},
+		{"other repository", []Suppression{{Finding: appID, Repository: "acme/api"}}, "acme/web",
+			types.ValidationSummary{Ready: 1, SetupNeeded: 1, Blockers: 1, Total: 3}, types.ValidationBlocker},
+		{"ready findings stay ready", []Suppression{{Finding: "ci.create_variable"}}, "acme/web",
+			types.ValidationSummary{Ready: 1, SetupNeeded: 1, Blockers: 1, Total: 3}, types.ValidationBlocker},
+	}
+	defer func() { suppressions = nil }()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suppressions = tt.entries
+			validation := newValidation()
+			finishValidation(validation, tt.repository)
+			if validation.Summary != tt.wantSummary {
+				t.Errorf("summary = %+v, want %+v", validation.Summary, tt.wantSummary)
+			}
+			if validation.OverallReadiness != tt.wantReadiness {
+				t.Errorf("readiness = %s, want %s", validation.OverallReadiness, tt.wantReadiness)
+			}
+
+			// Applying again without the ignore file restores the original statuses and IDs
+			ids := validation.AppsIntegrations[0].ID
+			suppressions = nil
+			finishValidation(validation, tt.repository)
+			if validation.AppsIntegrations[0].Status != types.ValidationBlocker || validation.AppsIntegrations[0].Suppression != nil {
+				t.Errorf("status after removing the suppression = %s", validation.AppsIntegrations[0].Status)
+			}
+			if validation.AppsIntegrations[0].ID != ids {
+				t.Errorf("ID changed from %s to %s", ids, validation.AppsIntegrations[0].ID)
+			}
+		})
+	}
+}
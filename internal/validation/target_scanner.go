@@ -4,8 +4,12 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/jefeish/gh-repo-transfer/internal/dependencies"
+	"github.com/jefeish/gh-repo-transfer/internal/enterprise"
+	"github.com/jefeish/gh-repo-transfer/internal/ghclient"
 	"github.com/jefeish/gh-repo-transfer/internal/types"
 )
 
@@ -17,6 +21,7 @@ func ScanTargetOrganization(client api.RESTClient, targetOrg string, verbose boo
 
 	capabilities := &types.TargetOrgCapabilities{
 		Organization: targetOrg,
+		ScannedAt:    time.Now().UTC(),
 	}
 
 	// Scan available GitHub Apps
@@ -47,6 +52,27 @@ func ScanTargetOrganization(client api.RESTClient, targetOrg string, verbose boo
 		}
 	}
 
+	// Scan rulesets requiring signed commits
+	if err := scanSignedCommitRulesets(client, targetOrg, capabilities, verbose); err != nil {
+		if verbose {
+			fmt.Fprintf(os.Stderr, "Warning: failed to scan signed commit rulesets: %v\n", err)
+		}
+	}
+
+	// Scan push rulesets restricting files
+	if err := scanPushRulesets(client, targetOrg, capabilities, verbose); err != nil {
+		if verbose {
+			fmt.Fprintf(os.Stderr, "Warning: failed to scan push rulesets: %v\n", err)
+		}
+	}
+
+	// Scan the defaults repositories get on arrival: community health files and required workflows
+	if err := scanRepositoryDefaults(client, targetOrg, capabilities, verbose); err != nil {
+		if verbose {
+			fmt.Fprintf(os.Stderr, "Warning: failed to scan repository defaults: %v\n", err)
+		}
+	}
+
 	// Scan organization secrets
 	if err := scanAvailableSecrets(client, targetOrg, capabilities, verbose); err != nil {
 		if verbose {
@@ -61,6 +87,33 @@ func ScanTargetOrganization(client api.RESTClient, targetOrg string, verbose boo
 		}
 	}
 
+	// Scan IdP groups available for team synchronization
+	if err := scanIdPGroups(client, targetOrg, capabilities, verbose); err != nil {
+		if verbose {
+			fmt.Fprintf(os.Stderr, "Warning: failed to scan IdP groups: %v\n", err)
+		}
+	}
+
+	// Scan the policies of the target enterprise
+	if targetEnterprise != "" {
+		capabilities.Enterprise = targetEnterprise
+		policies, err := enterprise.Policies(client, targetEnterprise)
+		if err != nil {
+			if verbose {
+				fmt.Fprintf(os.Stderr, "Warning: failed to scan enterprise policies: %v\n", err)
+			}
+		} else {
+			capabilities.EnterprisePolicies = policies
+		}
+	}
+
+	// Scan org webhooks
+	if err := scanWebhookDestinations(client, targetOrg, capabilities, verbose); err != nil {
+		if verbose {
+			fmt.Fprintf(os.Stderr, "Warning: failed to scan webhooks: %v\n", err)
+		}
+	}
+
 	// Scan self-hosted runners
 	if err := scanAvailableRunners(client, targetOrg, capabilities, verbose); err != nil {
 		if verbose {
@@ -68,6 +121,13 @@ func ScanTargetOrganization(client api.RESTClient, targetOrg string, verbose boo
 		}
 	}
 
+	// Scan runner groups and hosted compute network configurations
+	if err := scanHostedNetworking(client, targetOrg, capabilities, verbose); err != nil {
+		if verbose {
+			fmt.Fprintf(os.Stderr, "Warning: failed to scan runner groups: %v\n", err)
+		}
+	}
+
 	return capabilities, nil
 }
 
@@ -78,7 +138,7 @@ func scanAvailableApps(client api.RESTClient, targetOrg string, capabilities *ty
 		AppSlug string `json:"app_slug"`
 	}
 
-	err := client.Get(fmt.Sprintf("orgs/%s/installations", targetOrg), &installations)
+	err := ghclient.GetAllField(&client, fmt.Sprintf("orgs/%s/installations", targetOrg), "installations", &installations)
 	if err != nil {
 		return fmt.Errorf("failed to get app installations: %v", err)
 	}
@@ -100,18 +160,17 @@ func scanAvailableApps(client api.RESTClient, targetOrg string, capabilities *ty
 
 // scanAvailableTeams checks what teams are available in the target organization
 func scanAvailableTeams(client api.RESTClient, targetOrg string, capabilities *types.TargetOrgCapabilities, verbose bool) error {
+	capabilities.TeamSlugs = make(map[string]string)
 	var teams []struct {
 		Name string `json:"name"`
 		Slug string `json:"slug"`
 	}
-
-	err := client.Get(fmt.Sprintf("orgs/%s/teams", targetOrg), &teams)
-	if err != nil {
+	if err := ghclient.GetAll(&client, fmt.Sprintf("orgs/%s/teams", targetOrg), &teams); err != nil {
 		return fmt.Errorf("failed to get teams: %v", err)
 	}
-
 	for _, team := range teams {
 		capabilities.Teams = append(capabilities.Teams, team.Name)
+		capabilities.TeamSlugs[team.Name] = team.Slug
 	}
 
 	if verbose {
@@ -209,7 +268,7 @@ func scanRepositoryPolicies(client api.RESTClient, targetOrg string, capabilitie
 		} `json:"rules"`
 	}
 	
-	err = client.Get(fmt.Sprintf("orgs/%s/rulesets", targetOrg), &rulesets)
+	err = ghclient.GetAll(&client, fmt.Sprintf("orgs/%s/rulesets", targetOrg), &rulesets)
 	if err == nil {
 		for _, ruleset := range rulesets {
 			// Only include rulesets that are explicitly marked as policies (not just branch protection)
@@ -388,21 +447,176 @@ func scanMemberPrivileges(client api.RESTClient, targetOrg string, capabilities
 	return nil
 }
 
+// scanSignedCommitRulesets finds the active branch rulesets of the target organization that
+// require signed commits. Rulesets limited to some repositories are marked as such.
+func scanSignedCommitRulesets(client api.RESTClient, targetOrg string, capabilities *types.TargetOrgCapabilities, verbose bool) error {
+	var rulesets []struct {
+		ID          int    `json:"id"`
+		Name        string `json:"name"`
+		Target      string `json:"target"`
+		Enforcement string `json:"enforcement"`
+	}
+	if err := ghclient.GetAll(&client, fmt.Sprintf("orgs/%s/rulesets", targetOrg), &rulesets); err != nil {
+		return err
+	}
+
+	for _, ruleset := range rulesets {
+		if ruleset.Target != "branch" || ruleset.Enforcement != "active" {
+			continue
+		}
+		var detailed struct {
+			Rules []struct {
+				Type string `json:"type"`
+			} `json:"rules"`
+			Conditions struct {
+				RepositoryName *struct {
+					Include []string `json:"include"`
+					Exclude []string `json:"exclude"`
+				} `json:"repository_name"`
+			} `json:"conditions"`
+		}
+		if err := client.Get(fmt.Sprintf("orgs/%s/rulesets/%d", targetOrg, ruleset.ID), &detailed); err != nil {
+			continue
+		}
+		for _, rule := range detailed.Rules {
+			if rule.Type != "required_signatures" {
+				continue
+			}
+			name := ruleset.Name
+			if repositories := detailed.Conditions.RepositoryName; repositories == nil || !containsName(repositories.Include, "~ALL") || len(repositories.Exclude) > 0 {
+				name += " (selected repositories)"
+			}
+			capabilities.SignedCommitRulesets = append(capabilities.SignedCommitRulesets, name)
+			break
+		}
+	}
+
+	if verbose {
+		fmt.Fprintf(os.Stderr, "Found %d rulesets requiring signed commits in target org\n", len(capabilities.SignedCommitRulesets))
+	}
+	return nil
+}
+
+// scanPushRulesets reads the active push rulesets of the target organization
+func scanPushRulesets(client api.RESTClient, targetOrg string, capabilities *types.TargetOrgCapabilities, verbose bool) error {
+	var rulesets []struct {
+		ID          int    `json:"id"`
+		Name        string `json:"name"`
+		Target      string `json:"target"`
+		Enforcement string `json:"enforcement"`
+	}
+	if err := ghclient.GetAll(&client, fmt.Sprintf("orgs/%s/rulesets", targetOrg), &rulesets); err != nil {
+		return err
+	}
+
+	for _, ruleset := range rulesets {
+		if ruleset.Target != "push" || ruleset.Enforcement != "active" {
+			continue
+		}
+		var detailed struct {
+			Rules      []dependencies.RulesetRule `json:"rules"`
+			Conditions struct {
+				RepositoryName *struct {
+					Include []string `json:"include"`
+					Exclude []string `json:"exclude"`
+				} `json:"repository_name"`
+			} `json:"conditions"`
+		}
+		if err := client.Get(fmt.Sprintf("orgs/%s/rulesets/%d", targetOrg, ruleset.ID), &detailed); err != nil {
+			continue
+		}
+		pushRuleset := dependencies.ParsePushRuleset(ruleset.Name, "organization", detailed.Rules)
+		repositories := detailed.Conditions.RepositoryName
+		pushRuleset.SelectedRepositories = repositories == nil || !containsName(repositories.Include, "~ALL") || len(repositories.Exclude) > 0
+		capabilities.PushRulesets = append(capabilities.PushRulesets, pushRuleset)
+	}
+
+	if verbose {
+		fmt.Fprintf(os.Stderr, "Found %d push rulesets in target org\n", len(capabilities.PushRulesets))
+	}
+	return nil
+}
+
+// scanRepositoryDefaults reads what the target organization applies to the repositories it
+// receives: the default community health files and templates of its .github repository, and
+// its active rulesets requiring workflows to pass
+func scanRepositoryDefaults(client api.RESTClient, targetOrg string, capabilities *types.TargetOrgCapabilities, verbose bool) error {
+	capabilities.CommunityHealthFiles = dependencies.OrganizationHealthDefaults(client, targetOrg)
+
+	var rulesets []struct {
+		ID          int    `json:"id"`
+		Name        string `json:"name"`
+		Target      string `json:"target"`
+		Enforcement string `json:"enforcement"`
+	}
+	if err := ghclient.GetAll(&client, fmt.Sprintf("orgs/%s/rulesets", targetOrg), &rulesets); err != nil {
+		return err
+	}
+
+	for _, ruleset := range rulesets {
+		if ruleset.Target != "branch" || ruleset.Enforcement != "active" {
+			continue
+		}
+		var detailed struct {
+			Rules []struct {
+				Type       string `json:"type"`
+				Parameters struct {
+					Workflows []struct {
+						Path string `json:"path"`
+					} `json:"workflows"`
+				} `json:"parameters"`
+			} `json:"rules"`
+			Conditions struct {
+				RepositoryName *struct {
+					Include []string `json:"include"`
+					Exclude []string `json:"exclude"`
+				} `json:"repository_name"`
+			} `json:"conditions"`
+		}
+		if err := client.Get(fmt.Sprintf("orgs/%s/rulesets/%d", targetOrg, ruleset.ID), &detailed); err != nil {
+			continue
+		}
+		for _, rule := range detailed.Rules {
+			if rule.Type != "workflows" {
+				continue
+			}
+			var paths []string
+			for _, workflow := range rule.Parameters.Workflows {
+				paths = append(paths, workflow.Path)
+			}
+			name := fmt.Sprintf("%s (workflows: %s)", ruleset.Name, strings.Join(paths, ", "))
+			if repositories := detailed.Conditions.RepositoryName; repositories == nil || !containsName(repositories.Include, "~ALL") || len(repositories.Exclude) > 0 {
+				name += " (selected repositories)"
+			}
+			capabilities.RequiredWorkflowRulesets = append(capabilities.RequiredWorkflowRulesets, name)
+			break
+		}
+	}
+
+	if verbose {
+		fmt.Fprintf(os.Stderr, "Found %d default community health files and %d rulesets requiring workflows in target org\n", len(capabilities.CommunityHealthFiles), len(capabilities.RequiredWorkflowRulesets))
+	}
+	return nil
+}
+
 // scanAvailableSecrets checks organization secrets in the target organization
 func scanAvailableSecrets(client api.RESTClient, targetOrg string, capabilities *types.TargetOrgCapabilities, verbose bool) error {
 	var secrets struct {
 		Secrets []struct {
-			Name string `json:"name"`
+			Name       string `json:"name"`
+			Visibility string `json:"visibility"`
 		} `json:"secrets"`
 	}
 
-	err := client.Get(fmt.Sprintf("orgs/%s/actions/secrets", targetOrg), &secrets)
+	err := ghclient.GetAllField(&client, fmt.Sprintf("orgs/%s/actions/secrets", targetOrg), "secrets", &secrets.Secrets)
 	if err != nil {
 		return fmt.Errorf("failed to get secrets: %v", err)
 	}
 
+	capabilities.SecretVisibility = make(map[string]string)
 	for _, secret := range secrets.Secrets {
 		capabilities.Secrets = append(capabilities.Secrets, secret.Name)
+		capabilities.SecretVisibility[strings.ToUpper(secret.Name)] = secret.Visibility
 	}
 
 	if verbose {
@@ -416,17 +630,20 @@ func scanAvailableSecrets(client api.RESTClient, targetOrg string, capabilities
 func scanAvailableVariables(client api.RESTClient, targetOrg string, capabilities *types.TargetOrgCapabilities, verbose bool) error {
 	var variables struct {
 		Variables []struct {
-			Name string `json:"name"`
+			Name  string `json:"name"`
+			Value string `json:"value"`
 		} `json:"variables"`
 	}
 
-	err := client.Get(fmt.Sprintf("orgs/%s/actions/variables", targetOrg), &variables)
+	err := ghclient.GetAllField(&client, fmt.Sprintf("orgs/%s/actions/variables", targetOrg), "variables", &variables.Variables)
 	if err != nil {
 		return fmt.Errorf("failed to get variables: %v", err)
 	}
 
+	capabilities.VariableValues = make(map[string]string)
 	for _, variable := range variables.Variables {
 		capabilities.Variables = append(capabilities.Variables, variable.Name)
+		capabilities.VariableValues[strings.ToUpper(variable.Name)] = variable.Value
 	}
 
 	if verbose {
@@ -436,6 +653,63 @@ func scanAvailableVariables(client api.RESTClient, targetOrg string, capabilitie
 	return nil
 }
 
+// scanIdPGroups records the IdP groups teams of the target organization can be connected to:
+// SCIM groups with Enterprise Managed Users, otherwise team synchronization groups
+func scanIdPGroups(client api.RESTClient, targetOrg string, capabilities *types.TargetOrgCapabilities, verbose bool) error {
+	var groups struct {
+		Groups []struct {
+			GroupName string `json:"group_name"`
+		} `json:"groups"`
+	}
+
+	source := dependencies.IdPSourceExternalGroups
+	err := ghclient.GetAllField(&client, fmt.Sprintf("orgs/%s/external-groups", targetOrg), "groups", &groups.Groups)
+	if err != nil || len(groups.Groups) == 0 {
+		source = dependencies.IdPSourceTeamSync
+		if err := ghclient.GetAllField(&client, fmt.Sprintf("orgs/%s/team-sync/groups", targetOrg), "groups", &groups.Groups); err != nil {
+			return fmt.Errorf("team synchronization is not available: %v", err)
+		}
+	}
+
+	capabilities.IdPGroupSource = source
+	for _, group := range groups.Groups {
+		capabilities.IdPGroups = append(capabilities.IdPGroups, group.GroupName)
+	}
+
+	if verbose {
+		fmt.Fprintf(os.Stderr, "Found %d IdP groups (%s) in target org\n", len(capabilities.IdPGroups), source)
+	}
+
+	return nil
+}
+
+// scanWebhookDestinations records where the target organization's webhooks deliver to
+func scanWebhookDestinations(client api.RESTClient, targetOrg string, capabilities *types.TargetOrgCapabilities, verbose bool) error {
+	var hooks []struct {
+		Active bool `json:"active"`
+		Config struct {
+			URL string `json:"url"`
+		} `json:"config"`
+	}
+
+	err := ghclient.GetAll(&client, fmt.Sprintf("orgs/%s/hooks", targetOrg), &hooks)
+	if err != nil {
+		return fmt.Errorf("failed to get webhooks: %v", err)
+	}
+
+	for _, hook := range hooks {
+		if hook.Active {
+			capabilities.WebhookDestinations = append(capabilities.WebhookDestinations, dependencies.WebhookDestination(hook.Config.URL))
+		}
+	}
+
+	if verbose {
+		fmt.Fprintf(os.Stderr, "Found %d active webhooks in target org\n", len(capabilities.WebhookDestinations))
+	}
+
+	return nil
+}
+
 // scanAvailableRunners checks self-hosted runners in the target organization
 func scanAvailableRunners(client api.RESTClient, targetOrg string, capabilities *types.TargetOrgCapabilities, verbose bool) error {
 	var runners struct {
@@ -445,7 +719,7 @@ func scanAvailableRunners(client api.RESTClient, targetOrg string, capabilities
 		} `json:"runners"`
 	}
 
-	err := client.Get(fmt.Sprintf("orgs/%s/actions/runners", targetOrg), &runners)
+	err := ghclient.GetAllField(&client, fmt.Sprintf("orgs/%s/actions/runners", targetOrg), "runners", &runners.Runners)
 	if err != nil {
 		return fmt.Errorf("failed to get runners: %v", err)
 	}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/jefeish/gh-repo-transfer/internal/teams"
 	"github.com/jefeish/gh-repo-transfer/internal/types"
 )
 
@@ -11,22 +12,38 @@ import (
 func ValidateAgainstTarget(deps *types.OrganizationalDependencies, capabilities *types.TargetOrgCapabilities, assignTeams bool) *types.MigrationValidation {
 	validation := &types.MigrationValidation{
 		TargetOrganization: capabilities.Organization,
+		CapabilitiesScannedAt: capabilities.ScannedAt,
 		Summary:           types.ValidationSummary{},
 	}
 
 	// Validate each dependency category
 	validation.AppsIntegrations = validateAppsIntegrations(deps.AppsIntegrations, capabilities)
-	validation.AccessPermissions = validateAccessPermissions(deps.AccessPermissions, capabilities, assignTeams)
+	validation.AccessPermissions = validateAccessPermissions(deps.AccessPermissions, capabilities, assignTeams, sourceOwner(deps.Repository))
 	validation.CIDependencies = validateCIDependencies(deps.ActionsCIDependencies, capabilities)
 	validation.Governance = validateGovernance(deps.OrgGovernance, capabilities)
-	validation.CodeDependencies = validateCodeDependencies(deps.CodeDependencies, capabilities)
+	if deps.Depth == nil || deps.Depth.Deep {
+		// What the target org applies on arrival; the repository's community health files are
+		// only read by the deep analysis
+		validation.Governance = append(validation.Governance, validateRepositoryDefaults(deps.OrgGovernance.CommunityHealthFiles, capabilities)...)
+	}
+	validation.CodeDependencies = validateCodeDependencies(deps.CodeDependencies, capabilities, sourceOwner(deps.Repository))
 	validation.SecurityCompliance = validateSecurityCompliance(deps.SecurityCompliance, capabilities)
 
-	// Calculate summary and overall readiness
+	finishValidation(validation, deps.Repository)
+	return validation
+}
+
+// finishValidation assigns the finding IDs, applies the excluded checks, severity overrides and
+// ignore file and calculates the summary, overall readiness and effort
+func finishValidation(validation *types.MigrationValidation, repository string) {
+	restoreStatuses(validation)
+	AssignFindingIDs(validation)
+	excludeChecks(validation)
+	applySeverityOverrides(validation)
+	applySuppressions(validation, repository)
 	validation.Summary = calculateSummary(validation)
 	validation.OverallReadiness = determineOverallReadiness(validation.Summary)
-
-	return validation
+	validation.Effort = EstimateEffort(validation)
 }
 
 // validateAppsIntegrations checks if required apps are available in target org
@@ -63,22 +80,29 @@ func validateAppsIntegrations(apps types.AppsIntegrations, capabilities *types.T
 		})
 	}
 
+	results = append(results, validateRepositoryWebhooks(apps.Webhooks, capabilities)...)
+	results = append(results, validatePages(apps.Pages, capabilities)...)
+
 	return results
 }
 
 // validateAccessPermissions checks teams and collaborator access in target org
-func validateAccessPermissions(access types.AccessPermissions, capabilities *types.TargetOrgCapabilities, assignTeams bool) []types.ValidationResult {
+func validateAccessPermissions(access types.AccessPermissions, capabilities *types.TargetOrgCapabilities, assignTeams bool, sourceOrg string) []types.ValidationResult {
 	var results []types.ValidationResult
 
 	// Validate teams - missing teams are now always blockers
 	for _, team := range access.Teams {
 		teamName := extractTeamName(team)
+		if groups, synced := idpGroupsOf(team); synced {
+			results = append(results, validateIdPTeam(team, teamName, groups, capabilities))
+			continue
+		}
 		
 		status := types.ValidationBlocker
 		message := "Team does not exist in target organization"
 		recommendation := fmt.Sprintf("Create team '%s' in target organization", teamName)
 
-		if isTeamAvailable(teamName, capabilities.Teams) {
+		if isTeamAvailable(teamName, capabilities) {
 			status = types.ValidationReady
 			message = "Team exists in target organization"
 			recommendation = ""
@@ -103,41 +127,9 @@ func validateAccessPermissions(access types.AccessPermissions, capabilities *typ
 	}
 
 	// Validate CODEOWNERS requirements
-	for _, requirement := range access.CodeownersRequirements {
-		if strings.HasPrefix(requirement, "Team: @") {
-			// Extract team name from format "Team: @org/team-name"
-			teamRef := strings.TrimPrefix(requirement, "Team: @")
-			parts := strings.Split(teamRef, "/")
-			if len(parts) == 2 {
-				teamName := parts[1]
-				
-				status := types.ValidationBlocker
-				message := "CODEOWNERS team does not exist in target organization"
-				recommendation := fmt.Sprintf("Create team '%s' in target organization or update CODEOWNERS", teamName)
-
-				if isTeamAvailable(teamName, capabilities.Teams) {
-					status = types.ValidationReady
-					message = "CODEOWNERS team exists in target organization"
-					recommendation = ""
-				}
+	results = append(results, validateCodeowners(access.CodeownersRequirements, capabilities, sourceOrg)...)
 
-				results = append(results, types.ValidationResult{
-					Item:           requirement,
-					Status:         status,
-					Message:        message,
-					Recommendation: recommendation,
-				})
-			}
-		} else if strings.HasPrefix(requirement, "User: @") {
-			// CODEOWNERS users are warnings instead of requiring manual review
-			results = append(results, types.ValidationResult{
-				Item:           requirement,
-				Status:         types.ValidationWarning,
-				Message:        "CODEOWNERS user requires manual setup in target organization",
-				Recommendation: "Invite user to target organization or update CODEOWNERS",
-			})
-		}
-	}
+	results = append(results, validateDeployKeys(access.DeployKeys)...)
 
 	return results
 }
@@ -158,6 +150,9 @@ func validateCIDependencies(ci types.ActionsCIDependencies, capabilities *types.
 			status = types.ValidationReady
 			message = "Secret exists in target organization"
 			recommendation = ""
+			if collisionAwareness {
+				status, message, recommendation = secretCollision(secretName, ci, capabilities)
+			}
 		}
 
 		results = append(results, types.ValidationResult{
@@ -180,6 +175,9 @@ func validateCIDependencies(ci types.ActionsCIDependencies, capabilities *types.
 			status = types.ValidationReady
 			message = "Variable exists in target organization"
 			recommendation = ""
+			if collisionAwareness {
+				status, message, recommendation = variableCollision(variableName, ci, capabilities)
+			}
 		}
 
 		results = append(results, types.ValidationResult{
@@ -212,6 +210,12 @@ func validateCIDependencies(ci types.ActionsCIDependencies, capabilities *types.
 		})
 	}
 
+	// Runner groups on a private network and hosted runners with static IPs
+	results = append(results, validateHostedNetworking(ci, capabilities)...)
+
+	// Artifact attestations name the repository consumers verify against
+	results = append(results, validateArtifactAttestations(ci, capabilities)...)
+
 	// Required workflows need manual review
 	for _, workflow := range ci.RequiredWorkflows {
 		results = append(results, types.ValidationResult{
@@ -222,6 +226,30 @@ func validateCIDependencies(ci types.ActionsCIDependencies, capabilities *types.
 		})
 	}
 
+	// Repository-level secrets and variables belong to the repository, not to either org
+	results = append(results, validateRepositorySecretsAndVariables(ci)...)
+	results = append(results, validateEnvironmentItems(ci, capabilities)...)
+
+	// SHA-pinned consumers keep working through the redirect, until the old name is reused
+	for _, consumer := range ci.PinnedActionConsumers {
+		results = append(results, types.ValidationResult{
+			Item:           consumer,
+			Status:         types.ValidationReview,
+			Message:        "Pinned to a commit SHA; resolves through the redirect only until someone reuses the old name",
+			Recommendation: fmt.Sprintf("Update the reference to the repository's path in %s, and create a tombstone at the old path (--create-tombstone) so the name cannot be re-registered", capabilities.Organization),
+		})
+	}
+
+	// Dispatches are API calls to the old path, authorized for the source organization
+	for _, consumer := range ci.DispatchConsumers {
+		results = append(results, types.ValidationResult{
+			Item:           consumer,
+			Status:         types.ValidationReview,
+			Message:        "Dispatches deployments to this repository; the event stops arriving once the path or the sender's access changes",
+			Recommendation: fmt.Sprintf("Point the dispatch at the repository's path in %s and give its token or app access there", capabilities.Organization),
+		})
+	}
+
 	return results
 }
 
@@ -261,11 +289,23 @@ func validateGovernance(governance types.OrgGovernance, capabilities *types.Targ
 		})
 	}
 
+	// Org webhooks and audit log streams receiving the repository's events
+	results = append(results, validateEventSinks(governance.EventSinks, capabilities)...)
+
+	// Enterprise policies overriding organization settings
+	results = append(results, validateEnterprisePolicies(governance.EnterprisePolicies, capabilities)...)
+
+	// Signed commit and web commit sign-off requirements, in both directions
+	results = append(results, validateCommitSigning(governance.CommitSigning, capabilities)...)
+
+	// Push rulesets restricting file sizes, paths and extensions, in both directions
+	results = append(results, validatePushRules(governance.PushRulesets, capabilities)...)
+
 	return results
 }
 
 // validateCodeDependencies checks code-related dependencies
-func validateCodeDependencies(code types.CodeDependencies, capabilities *types.TargetOrgCapabilities) []types.ValidationResult {
+func validateCodeDependencies(code types.CodeDependencies, capabilities *types.TargetOrgCapabilities, owner string) []types.ValidationResult {
 	var results []types.ValidationResult
 
 	// Git submodules need verification regardless of being external
@@ -287,9 +327,49 @@ func validateCodeDependencies(code types.CodeDependencies, capabilities *types.T
 		}
 	}
 
+	// Release, tag and badge URLs in docs keep pointing at the old organization
+	for _, reference := range code.DocumentationURLReferences {
+		recommendation := "Update the URL to point at the target organization"
+		if rewritten := rewriteDocumentationURL(reference, owner, capabilities.Organization); rewritten != "" {
+			recommendation = fmt.Sprintf("Rewrite to %s", rewritten)
+		}
+		results = append(results, types.ValidationResult{
+			Item:           reference,
+			Status:         types.ValidationReview,
+			Message:        "Documentation URL references the source organization",
+			Recommendation: recommendation,
+		})
+	}
+
 	return results
 }
 
+// rewriteDocumentationURL extracts the URL from a "file:line url (kind)" reference and
+// replaces the source owner path segment with the target organization
+func rewriteDocumentationURL(reference, sourceOwner, targetOrg string) string {
+	fields := strings.Fields(reference)
+	if len(fields) < 2 || sourceOwner == "" || targetOrg == "" {
+		return ""
+	}
+
+	url := fields[1]
+	lower := strings.ToLower(url)
+	segment := "/" + strings.ToLower(sourceOwner) + "/"
+	idx := strings.Index(lower, segment)
+	if idx == -1 {
+		return ""
+	}
+	return url[:idx] + "/" + targetOrg + "/" + url[idx+len(segment):]
+}
+
+// sourceOwner extracts the owner from an "owner/repo" string
+func sourceOwner(repository string) string {
+	if idx := strings.Index(repository, "/"); idx != -1 {
+		return repository[:idx]
+	}
+	return ""
+}
+
 // validateSecurityCompliance checks security dependencies
 func validateSecurityCompliance(security types.SecurityCompliance, capabilities *types.TargetOrgCapabilities) []types.ValidationResult {
 	var results []types.ValidationResult
@@ -326,15 +406,18 @@ func extractTeamName(teamString string) string {
 }
 
 func extractSecretName(secretString string) string {
-	return secretString // Assuming secrets are already just names
+	// Strip the " (in workflow.yml)" suffix added by the workflow analysis
+	return strings.SplitN(secretString, " (in ", 2)[0]
 }
 
 func extractVariableName(variableString string) string {
-	return variableString // Assuming variables are already just names
+	return strings.SplitN(variableString, " (in ", 2)[0]
 }
 
 func extractRunnerName(runnerString string) string {
-	return runnerString // Assuming runners are already just names
+	// "Self-hosted runner: linux, gpu (in ci.yml, job build)" names the runner's labels
+	name := strings.SplitN(runnerString, " (in ", 2)[0]
+	return strings.TrimPrefix(name, "Self-hosted runner: ")
 }
 
 func isAppAvailable(appName string, availableApps []string) bool {
@@ -356,9 +439,23 @@ func isCommonApp(appName string) bool {
 	return false
 }
 
-func isTeamAvailable(teamName string, availableTeams []string) bool {
-	for _, available := range availableTeams {
-		if strings.EqualFold(teamName, available) {
+// isTeamAvailable matches a source team name against the target teams using --team-matcher
+func isTeamAvailable(teamName string, capabilities *types.TargetOrgCapabilities) bool {
+	for _, available := range capabilities.Teams {
+		if teams.Matches(teamName, available, capabilities.TeamSlugs[available]) {
+			return true
+		}
+	}
+	return false
+}
+
+func isTeamSlugAvailable(teamSlug string, capabilities *types.TargetOrgCapabilities) bool {
+	for _, available := range capabilities.Teams {
+		slug := capabilities.TeamSlugs[available]
+		if slug == "" {
+			slug = teams.Slug(available)
+		}
+		if strings.EqualFold(teamSlug, slug) {
 			return true
 		}
 	}
@@ -468,10 +565,7 @@ func validateMemberPrivilegePolicy(policy types.OrgPolicy, targetPrivileges type
 			if !targetPrivileges.TwoFactorRequired {
 				missingRestrictions = append(missingRestrictions, "Two-factor authentication needs to be required")
 			}
-		case strings.Contains(restrictionLower, "web commit signoff required"):
-			if !targetPrivileges.WebCommitSignoffRequired {
-				missingRestrictions = append(missingRestrictions, "Web commit signoff needs to be required")
-			}
+		// Web commit signoff is compared explicitly by validateCommitSigning
 		}
 	}
 	
@@ -533,6 +627,10 @@ func calculateSummary(validation *types.MigrationValidation) types.ValidationSum
 	allResults = append(allResults, validation.SecurityCompliance...)
 	
 	for _, result := range allResults {
+		if result.Status == types.ValidationSuppressed {
+			summary.Suppressed++
+			continue
+		}
 		summary.Total++
 		switch result.Status {
 		case types.ValidationReady:
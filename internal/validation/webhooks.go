@@ -0,0 +1,46 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+// validateRepositoryWebhooks reports repository webhooks, which have to be recreated on the
+// moved repository. A target org webhook delivering to the same destination would receive the
+// events as well, so recreating the repository webhook could duplicate deliveries.
+func validateRepositoryWebhooks(webhooks []string, capabilities *types.TargetOrgCapabilities) []types.ValidationResult {
+	var results []types.ValidationResult
+	for _, webhook := range webhooks {
+		if strings.HasSuffix(webhook, "[inactive]") {
+			results = append(results, types.ValidationResult{
+				Item:    webhook,
+				Status:  types.ValidationReady,
+				Message: "Inactive webhook, nothing is delivered today",
+			})
+			continue
+		}
+
+		destination := eventSinkDestination(webhook)
+		if idx := strings.Index(destination, " ("); idx != -1 {
+			destination = destination[:idx]
+		}
+		if containsName(capabilities.WebhookDestinations, destination) {
+			results = append(results, types.ValidationResult{
+				Item:           webhook,
+				Status:         types.ValidationReview,
+				Message:        "Target org webhook already delivers to the same destination",
+				Recommendation: "Check whether the org webhook covers these events before recreating the repository webhook, to avoid duplicate deliveries",
+			})
+			continue
+		}
+		results = append(results, types.ValidationResult{
+			Item:           webhook,
+			Status:         types.ValidationSetupNeeded,
+			Message:        "Repository webhook is not carried over by the transfer",
+			Recommendation: fmt.Sprintf("Recreate the webhook after the move (--migrate-webhooks) and set its secret again; %s has no webhook delivering there", capabilities.Organization),
+		})
+	}
+	return results
+}
@@ -0,0 +1,33 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/jefeish/gh-repo-transfer/internal/types"
+)
+
+func TestValidateRepositoryWebhooks(t *testing.T) {
+	capabilities := &types.TargetOrgCapabilities{
+		Organization:        "target",
+		WebhookDestinations: []string{"https://ci.example.com/hook"},
+	}
+
+	tests := []struct {
+		webhook string
+		want    types.ValidationStatus
+	}{
+		{"Repository webhook → https://deploy.example.com/in (events: push)", types.ValidationSetupNeeded},
+		{"Repository webhook → https://ci.example.com/hook (events: pull_request, push)", types.ValidationReview},
+		{"Repository webhook → https://old.example.com/in (events: push) [inactive]", types.ValidationReady},
+	}
+
+	for _, tt := range tests {
+		results := validateRepositoryWebhooks([]string{tt.webhook}, capabilities)
+		if len(results) != 1 || results[0].Status != tt.want {
+			t.Errorf("validateRepositoryWebhooks(%q) = %+v, want status %s", tt.webhook, results, tt.want)
+		}
+		if tt.want != types.ValidationReady && classifyEffortItem("apps", results[0]) != "recreate_webhook" {
+			t.Errorf("classifyEffortItem(%q) = %s, want recreate_webhook", tt.webhook, classifyEffortItem("apps", results[0]))
+		}
+	}
+}
@@ -1,5 +1,15 @@
 package utils
 
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
 // ShouldIncludeSection determines if a section should be included based on the sections filter
 func ShouldIncludeSection(sections []string, section string) bool {
 	if len(sections) == 0 {
@@ -37,4 +47,88 @@ func PermissionToIcon(permission string) string {
 	default:
 		return "❓ " + permission
 	}
-}
\ No newline at end of file
+}
+
+// FormatMinutes renders a number of minutes as a short duration, e.g. "2h 15m"
+func FormatMinutes(minutes int) string {
+	if minutes < 60 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	if minutes%60 == 0 {
+		return fmt.Sprintf("%dh", minutes/60)
+	}
+	return fmt.Sprintf("%dh %dm", minutes/60, minutes%60)
+}
+
+// ParseDuration parses a duration like time.ParseDuration and also accepts whole days and
+// weeks, e.g. "7d" or "2w", for soak and cooling-off periods
+func ParseDuration(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if number := strings.TrimSuffix(value, suffix); number != value {
+			count, err := strconv.Atoi(number)
+			if err != nil || count < 0 {
+				return 0, fmt.Errorf("invalid duration %q", value)
+			}
+			return time.Duration(count) * unit, nil
+		}
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q: use e.g. 7d, 2w or 36h", value)
+	}
+	return duration, nil
+}
+
+// RulesetCoversName reports whether a ruleset's repository_name condition (include/exclude
+// fnmatch patterns, with "~ALL" matching everything) applies to a repository name
+func RulesetCoversName(include, exclude []string, name string) bool {
+	matches := func(patterns []string) bool {
+		for _, pattern := range patterns {
+			if pattern == "~ALL" {
+				return true
+			}
+			if ok, err := path.Match(strings.ToLower(pattern), strings.ToLower(name)); err == nil && ok {
+				return true
+			}
+		}
+		return false
+	}
+	return matches(include) && !matches(exclude)
+}
+
+// ParseRepositoryList reads a repository list with one owner/repo per line, e.g. from a
+// --repos-file. Blank lines and comments starting with # are skipped, also at the end of a line.
+func ParseRepositoryList(r io.Reader) ([]string, error) {
+	var repositories []string
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if i := strings.Index(text, "#"); i >= 0 {
+			text = text[:i]
+		}
+		text = strings.TrimSpace(text)
+		if text == "" {
+			continue
+		}
+		// HOST/owner/repo names the GitHub host as well, as gh accepts it
+		parts := strings.Split(text, "/")
+		if len(parts) < 2 || len(parts) > 3 || containsEmpty(parts) || strings.ContainsAny(text, " \t") {
+			return nil, fmt.Errorf("line %d: '%s' must be in format 'owner/repo' or 'HOST/owner/repo'", line, text)
+		}
+		repositories = append(repositories, text)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return repositories, nil
+}
+
+func containsEmpty(values []string) bool {
+	for _, value := range values {
+		if value == "" {
+			return true
+		}
+	}
+	return false
+}
@@ -1,7 +1,10 @@
 package utils
 
 import (
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestShouldIncludeSection(t *testing.T) {
@@ -105,4 +108,102 @@ func TestPermissionToIcon(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestFormatMinutes(t *testing.T) {
+	tests := []struct {
+		minutes int
+		want    string
+	}{
+		{0, "0m"},
+		{45, "45m"},
+		{60, "1h"},
+		{135, "2h 15m"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatMinutes(tt.minutes); got != tt.want {
+			t.Errorf("FormatMinutes(%d) = %q, want %q", tt.minutes, got, tt.want)
+		}
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"7d", 7 * 24 * time.Hour, false},
+		{"2w", 14 * 24 * time.Hour, false},
+		{"36h", 36 * time.Hour, false},
+		{"0d", 0, false},
+		{"1.5d", 0, true},
+		{"-1d", 0, true},
+		{"soon", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseDuration(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseDuration(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseDuration(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestRulesetCoversName(t *testing.T) {
+	tests := []struct {
+		name    string
+		include []string
+		exclude []string
+		repo    string
+		want    bool
+	}{
+		{"all repositories", []string{"~ALL"}, nil, "service-a", true},
+		{"explicit name", []string{"service-a"}, nil, "service-a", true},
+		{"explicit name, renamed", []string{"service-a"}, nil, "archived-service-a-1a2b", false},
+		{"pattern", []string{"service-*"}, nil, "Service-B", true},
+		{"excluded", []string{"~ALL"}, []string{"archived-*"}, "archived-service-a-1a2b", false},
+		{"no include", nil, nil, "service-a", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RulesetCoversName(tt.include, tt.exclude, tt.repo); got != tt.want {
+				t.Errorf("RulesetCoversName(%v, %v, %q) = %v, want %v", tt.include, tt.exclude, tt.repo, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRepositoryList(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+		wantErr bool
+	}{
+		{"one per line", "acme/web\nacme/api\n", []string{"acme/web", "acme/api"}, false},
+		{"comments and blank lines", "# wave 3\n\nacme/web   # owned by platform\n\tacme/api\r\n", []string{"acme/web", "acme/api"}, false},
+		{"empty", "# nothing yet\n", nil, false},
+		{"missing owner", "acme/web\nweb\n", nil, true},
+		{"two on one line", "acme/web acme/api\n", nil, true},
+		{"with host", "ghes.example.com/acme/web\n", []string{"ghes.example.com/acme/web"}, false},
+		{"empty repository", "acme/\n", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRepositoryList(strings.NewReader(tt.content))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRepositoryList() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseRepositoryList() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}